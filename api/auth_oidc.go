@@ -44,6 +44,18 @@ func (op OidcProvider) GetUrn() string {
 // AUTHENTICATOR OIDC API IMPLEMENTATION
 
 func (api WorkerAPI) AddOidcProvider(requestInfo RequestInfo, name string, path string, issuerURL string, oidcClients []string) (*OidcProvider, error) {
+	var oidcProvider *OidcProvider
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		oidcProvider, err = txAPI.addOidcProvider(requestInfo, name, path, issuerURL, oidcClients)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return oidcProvider, nil
+}
+
+func (api WorkerAPI) addOidcProvider(requestInfo RequestInfo, name string, path string, issuerURL string, oidcClients []string) (*OidcProvider, error) {
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -104,15 +116,11 @@ func (api WorkerAPI) AddOidcProvider(requestInfo RequestInfo, name string, path
 
 			// Check if there is an unexpected error in DB
 			if err != nil {
-				//Transform to DB error
-				dbError := err.(*database.Error)
-				return nil, &Error{
-					Code:    UNKNOWN_API_ERROR,
-					Message: dbError.Message,
-				}
+				return nil, storeError(err)
 			}
 
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("OIDC provider created %+v", createdOidcProvider))
+			api.emitEvent(requestInfo, OIDC_PROVIDER_EVENT_CREATED, createdOidcProvider, nil)
 			return createdOidcProvider, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -325,16 +333,12 @@ func (api WorkerAPI) UpdateOidcProvider(requestInfo RequestInfo, oidcProviderNam
 
 	// Check unexpected DB error
 	if err != nil {
-		//Transform to DB error
-		dbError := err.(*database.Error)
-		return nil, &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
+		return nil, storeError(err)
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("OIDC Provider updated from %+v to %+v",
 		oldOidcProvider, updatedOidcProvider))
+	api.emitEvent(requestInfo, OIDC_PROVIDER_EVENT_UPDATED, updatedOidcProvider, nil)
 	return updatedOidcProvider, nil
 }
 
@@ -371,6 +375,7 @@ func (api WorkerAPI) RemoveOidcProvider(requestInfo RequestInfo, name string) er
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("OIDC Provider deleted %v", oidcProvider))
+	api.emitEvent(requestInfo, OIDC_PROVIDER_EVENT_DELETED, oidcProvider, nil)
 	return nil
 }
 
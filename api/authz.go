@@ -14,6 +14,8 @@ type RequestInfo struct {
 	Identifier string
 	Admin      bool
 	RequestID  string
+	// Revision required by the request to update or remove an entity, 0 if any revision is accepted
+	Revision int
 }
 
 type EffectRestriction struct {
@@ -26,6 +28,9 @@ type Restrictions struct {
 	AllowedFullUrns    []string `json:"allowedFullUrns,omitempty"`
 	DeniedUrnPrefixes  []string `json:"deniedUrnPrefixes,omitempty"`
 	DeniedFullUrns     []string `json:"deniedFullUrns,omitempty"`
+	// Each element applies to resources that don't match any of its urns, from notResources statements
+	AllowedNotUrns [][]string `json:"allowedNotUrns,omitempty"`
+	DeniedNotUrns  [][]string `json:"deniedNotUrns,omitempty"`
 }
 
 type ExternalResource struct {
@@ -36,6 +41,20 @@ func (e ExternalResource) GetUrn() string {
 	return e.Urn
 }
 
+// Subject whose access is reviewed: a user, and the names of the groups of an organization it's asserted to
+// belong to by the reviewer in addition to the ones it's a member of
+type Subject struct {
+	ExternalID string
+	Org        string
+	Groups     []string
+}
+
+// AccessReview is the decision taken about the access of a subject
+type AccessReview struct {
+	Allowed bool
+	Reason  string
+}
+
 // AUTHZ API IMPLEMENTATION
 
 // GetAuthorizedUsers returns authorized users for specified resource+action
@@ -123,6 +142,23 @@ func (api WorkerAPI) GetAuthorizedOidcProviders(requestInfo RequestInfo, resourc
 	return oidcProvidersFiltered, nil
 }
 
+// GetAuthorizedOrganizations returns authorized organizations for specified user combined with resource+action
+func (api WorkerAPI) GetAuthorizedOrganizations(requestInfo RequestInfo, resourceUrn string, action string, orgs []Organization) ([]Organization, error) {
+	resourcesToAuthorize := []Resource{}
+	for _, org := range orgs {
+		resourcesToAuthorize = append(resourcesToAuthorize, org)
+	}
+	resources, err := api.getAuthorizedResources(requestInfo, resourceUrn, action, resourcesToAuthorize)
+	if err != nil {
+		return nil, err
+	}
+	orgsFiltered := []Organization{}
+	for _, res := range resources {
+		orgsFiltered = append(orgsFiltered, res.(Organization))
+	}
+	return orgsFiltered, nil
+}
+
 // GetAuthorizedExternalResources returns the resources where the specified user has the action granted
 func (api WorkerAPI) GetAuthorizedExternalResources(requestInfo RequestInfo, action string, resources []string) ([]string, error) {
 	// Validate parameters
@@ -142,23 +178,12 @@ func (api WorkerAPI) GetAuthorizedExternalResources(requestInfo RequestInfo, act
 	}
 	externalResources := []Resource{}
 	for _, res := range resources {
-		if !isFullUrn(res) {
-			return nil, &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: fmt.Sprintf("Invalid parameter resource %v. Urn prefixes are not allowed here", res),
-			}
-		}
-		if err := AreValidResources([]string{res}, RESOURCE_EXTERNAL); err != nil {
-			// Transform to API error
-			apiError := err.(*Error)
-			return nil, &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: apiError.Message,
-			}
+		if err := validateExternalResource(res); err != nil {
+			return nil, err
 		}
 		externalResources = append(externalResources, ExternalResource{Urn: res})
 	}
-	if strings.Contains(action, "*") {
+	if strings.ContainsAny(action, "*?") {
 		return nil, &Error{
 			Code:    INVALID_PARAMETER_ERROR,
 			Message: fmt.Sprintf("Invalid parameter action %v. Action parameter can't be a prefix", action),
@@ -185,8 +210,124 @@ func (api WorkerAPI) GetAuthorizedExternalResources(requestInfo RequestInfo, act
 	return response, nil
 }
 
+// ReviewSubjectAccess decides if a subject is allowed to do an action over an external resource
+func (api WorkerAPI) ReviewSubjectAccess(requestInfo RequestInfo, subject Subject, action string, resource string) (*AccessReview, error) {
+	// Only admins can review the access of other users
+	if !requestInfo.Admin {
+		return nil, &Error{
+			Code:    UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to review the access of other users", requestInfo.Identifier),
+		}
+	}
+
+	// Validate parameters
+	if len(subject.Groups) > 0 && !IsValidOrg(subject.Org) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: org %v", subject.Org),
+		}
+	}
+	if err := AreValidActions([]string{action}); err != nil {
+		// Transform to API error
+		apiError := err.(*Error)
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: apiError.Message,
+		}
+	}
+	if strings.ContainsAny(action, "*?") {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter action %v. Action parameter can't be a prefix", action),
+		}
+	}
+	if err := validateExternalResource(resource); err != nil {
+		return nil, err
+	}
+
+	// Subjects like Kubernetes service accounts can't be Foulkon users
+	if !IsValidUserExternalID(subject.ExternalID) {
+		return &AccessReview{
+			Allowed: false,
+			Reason:  fmt.Sprintf("User with externalId %v not found", subject.ExternalID),
+		}, nil
+	}
+	user, err := api.UserRepo.GetUserByExternalID(subject.ExternalID)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		if dbError.Code == database.USER_NOT_FOUND {
+			return &AccessReview{
+				Allowed: false,
+				Reason:  fmt.Sprintf("User with externalId %v not found", subject.ExternalID),
+			}, nil
+		}
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Groups asserted by the reviewer that don't exist in the organization are ignored
+	groups := []Group{}
+	for _, name := range subject.Groups {
+		if !IsValidName(name) {
+			continue
+		}
+		group, err := api.GroupRepo.GetGroupByName(subject.Org, name)
+		if err != nil {
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			if dbError.Code == database.GROUP_NOT_FOUND {
+				continue
+			}
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+		groups = append(groups, *group)
+	}
+
+	restrictions, err := api.getUserRestrictions(user, groups, action, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filterResources([]Resource{ExternalResource{Urn: resource}}, restrictions)) < 1 {
+		return &AccessReview{
+			Allowed: false,
+			Reason:  fmt.Sprintf("User with externalId %v is not allowed to do action %v over resource %v", subject.ExternalID, action, resource),
+		}, nil
+	}
+
+	return &AccessReview{
+		Allowed: true,
+		Reason:  fmt.Sprintf("User with externalId %v is allowed to do action %v over resource %v", subject.ExternalID, action, resource),
+	}, nil
+}
+
 // PRIVATE HELPER METHODS
 
+// Check that a resource is a valid full urn of an external resource
+func validateExternalResource(resource string) error {
+	if !isFullUrn(resource) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter resource %v. Urn prefixes are not allowed here", resource),
+		}
+	}
+	if err := AreValidResources([]string{resource}, RESOURCE_EXTERNAL); err != nil {
+		// Transform to API error
+		apiError := err.(*Error)
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: apiError.Message,
+		}
+	}
+	return nil
+}
+
 // getAuthorizedResources retrieves filtered resources where the authenticated user has permissions
 func (api WorkerAPI) getAuthorizedResources(requestInfo RequestInfo, resourceUrn string, action string, resources []Resource) ([]Resource, error) {
 	// If user is an admin return all resources without restriction
@@ -203,7 +344,7 @@ func (api WorkerAPI) getAuthorizedResources(requestInfo RequestInfo, resourceUrn
 	Log.Debugf("Restrictions: %v", *restrictions)
 
 	// Check if there are some restrictions for this urn resource
-	if len(restrictions.AllowedFullUrns) < 1 && len(restrictions.AllowedUrnPrefixes) < 1 {
+	if len(restrictions.AllowedFullUrns) < 1 && len(restrictions.AllowedUrnPrefixes) < 1 && len(restrictions.AllowedNotUrns) < 1 {
 		return nil, &Error{
 			Code:    UNAUTHORIZED_RESOURCES_ERROR,
 			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v", requestInfo.Identifier, resourceUrn),
@@ -239,19 +380,41 @@ func (api WorkerAPI) getRestrictions(externalID string, action string, resource
 		}
 	}
 
+	return api.getUserRestrictions(user, nil, action, resource)
+}
+
+// Get restrictions for this action and full resource or prefix resource, attached to a user, to the groups it
+// belongs to and to the extra groups received, which are added to the user membership with their parents
+func (api WorkerAPI) getUserRestrictions(user *User, extraGroups []Group, action string, resource string) (*Restrictions, error) {
 	groups, err := api.getGroupsByUser(user.ID)
 	if err != nil {
 		return nil, err
 	}
+	if len(extraGroups) > 0 {
+		extraGroups, err = api.getParentGroupsClosure(extraGroups)
+		if err != nil {
+			return nil, err
+		}
+		groups = mergeGroups(groups, extraGroups)
+	}
 
 	policies, err := api.getPoliciesByGroups(groups)
 	if err != nil {
 		return nil, err
 	}
 
+	userPolicies, err := api.getPoliciesByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	policies = append(policies, userPolicies...)
+
 	// Retrieve valid statements
 	statements := getStatementsByRequestedAction(policies, action)
 
+	// Replace policy variables with values from this user and request
+	statements = resolvePolicyVariables(statements, user, resource)
+
 	// Retrieve restrictions
 	var authResources *Restrictions
 	authResources = getRestrictions(statements, resource, isFullUrn(resource))
@@ -276,9 +439,73 @@ func (api WorkerAPI) getGroupsByUser(userID string) ([]Group, error) {
 		groups = append(groups, *g.GetGroup())
 	}
 
+	// Add groups inherited through nested groups
+	return api.getParentGroupsClosure(groups)
+}
+
+// Retrieve a slice of groups together with all the groups they belong to, directly or through other groups
+func (api WorkerAPI) getParentGroupsClosure(groups []Group) ([]Group, error) {
+	visited := map[string]bool{}
+	for _, g := range groups {
+		visited[g.ID] = true
+	}
+
+	for i := 0; i < len(groups); i++ {
+		parents, err := api.GroupRepo.GetParentGroups(groups[i].ID)
+		if err != nil {
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+		for _, p := range parents {
+			if !visited[p.ID] {
+				visited[p.ID] = true
+				groups = append(groups, p)
+			}
+		}
+	}
+
 	return groups, nil
 }
 
+// Append groups not already contained in a slice of groups
+func mergeGroups(groups []Group, others []Group) []Group {
+	contained := map[string]bool{}
+	for _, g := range groups {
+		contained[g.ID] = true
+	}
+	for _, g := range others {
+		if !contained[g.ID] {
+			contained[g.ID] = true
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// Retrieve policies attached directly to a user
+func (api WorkerAPI) getPoliciesByUser(userID string) ([]Policy, error) {
+	policiesAttached, _, err := api.UserRepo.GetAttachedUserPolicies(userID, &Filter{})
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	policies := []Policy{}
+	for _, policy := range policiesAttached {
+		policies = append(policies, *policy.GetPolicy())
+	}
+
+	return policies, nil
+}
+
 // Retrieve policies attached to a slice of groups
 func (api WorkerAPI) getPoliciesByGroups(groups []Group) ([]Policy, error) {
 	if groups == nil || len(groups) < 1 {
@@ -319,7 +546,12 @@ func getStatementsByRequestedAction(policies []Policy, requestedAction string) [
 	statements := []Statement{}
 	for _, policy := range policies {
 		for _, statement := range *policy.Statements {
-			if isActionContained(requestedAction, statement.Actions) {
+			if len(statement.NotActions) > 0 {
+				// Statement applies to every action except the ones defined
+				if !isActionContained(requestedAction, statement.NotActions) {
+					statements = append(statements, statement)
+				}
+			} else if isActionContained(requestedAction, statement.Actions) {
 				statements = append(statements, statement)
 			}
 		}
@@ -328,18 +560,64 @@ func getStatementsByRequestedAction(policies []Policy, requestedAction string) [
 	return statements
 }
 
+// Replace policy variables in statement resources. When the requested resource doesn't belong to a specific
+// organization, the request organization can't be resolved. Resources of allow statements that use it are
+// discarded, and resources of deny statements or excluded resources of allow statements match any organization,
+// so the permissions granted are never wider than the ones of any organization.
+func resolvePolicyVariables(statements []Statement, user *User, resource string) []Statement {
+	org := getOrgFromUrn(resource)
+	replacer := strings.NewReplacer(
+		POLICY_VARIABLE_USER_EXTERNAL_ID, user.ExternalID,
+		POLICY_VARIABLE_USER_PATH, user.Path,
+		POLICY_VARIABLE_REQUEST_ORG, org,
+	)
+	anyOrgReplacer := strings.NewReplacer(
+		POLICY_VARIABLE_USER_EXTERNAL_ID, user.ExternalID,
+		POLICY_VARIABLE_USER_PATH, user.Path,
+		POLICY_VARIABLE_REQUEST_ORG, "*",
+	)
+
+	// Resources that restrict the permissions match any organization, the other ones are discarded
+	resolveFunc := func(resources []string, restrictive bool) []string {
+		resolvedResources := []string{}
+		for _, res := range resources {
+			switch {
+			case org != "" || !strings.Contains(res, POLICY_VARIABLE_REQUEST_ORG):
+				resolvedResources = append(resolvedResources, replacer.Replace(res))
+			case restrictive:
+				resolvedResources = append(resolvedResources, anyOrgReplacer.Replace(res))
+			}
+		}
+		return resolvedResources
+	}
+
+	resolvedStatements := []Statement{}
+	for _, statement := range statements {
+		if len(statement.NotResources) > 0 {
+			statement.NotResources = resolveFunc(statement.NotResources, statement.Effect == "allow")
+		} else {
+			statement.Resources = resolveFunc(statement.Resources, statement.Effect == "deny")
+		}
+		resolvedStatements = append(resolvedStatements, statement)
+	}
+
+	return resolvedStatements
+}
+
+// Retrieve organization from a resource urn, empty if urn has no valid organization
+func getOrgFromUrn(urn string) string {
+	blocks := strings.Split(urn, ":")
+	if len(blocks) < 5 || !IsValidOrg(blocks[3]) {
+		return ""
+	}
+	return blocks[3]
+}
+
 // Returns true if an action is contained inside a slice of statements
 func isActionContained(actionRequested string, statementActions []string) bool {
 	match := false
 	for _, statementAction := range statementActions {
-		// Prefixes
-		if strings.ContainsAny(statementAction, "*") {
-			value := strings.Trim(statementAction, "*")
-			if len(value) < 1 || strings.HasPrefix(actionRequested, value) {
-				match = true
-				break
-			}
-		} else if strings.Compare(actionRequested, statementAction) == 0 {
+		if isContainedOrEqual(actionRequested, statementAction) {
 			match = true
 			break
 		}
@@ -348,17 +626,69 @@ func isActionContained(actionRequested string, statementActions []string) bool {
 	return match
 }
 
-// Returns true if a resource is contained in a prefix
-func isContainedOrEqual(resource string, resourcePrefix string) bool {
-	prefix := strings.Trim(resourcePrefix, "*")
-	if len(prefix) < 1 {
-		return true
+// Returns true if a resource matches a glob pattern, where '*' matches any sequence of characters and '?'
+// a single one. Wildcards in resource are taken as literals, so it also returns true when resource is a
+// pattern contained in the other one
+func isContainedOrEqual(resource string, pattern string) bool {
+	r, p := 0, 0
+	starP, starR := -1, 0
+	for r < len(resource) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			starP, starR = p, r
+			p++
+		case p < len(pattern) && (pattern[p] == resource[r] || (pattern[p] == '?' && resource[r] != '*')):
+			p++
+			r++
+		case starP >= 0:
+			// Backtrack, last '*' in pattern consumes one more character
+			starR++
+			r, p = starR, starP+1
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// Returns true if there is any resource matched by both glob patterns
+func isOverlapped(pattern1 string, pattern2 string) bool {
+	// visited[i][j] marks already checked positions in each pattern
+	visited := make([][]bool, len(pattern1)+1)
+	for i := range visited {
+		visited[i] = make([]bool, len(pattern2)+1)
+	}
+
+	var overlap func(i, j int) bool
+	overlap = func(i, j int) bool {
+		if visited[i][j] {
+			return false
+		}
+		visited[i][j] = true
+
+		switch {
+		case i == len(pattern1) && j == len(pattern2):
+			return true
+		case i < len(pattern1) && pattern1[i] == '*':
+			return overlap(i+1, j) || (j < len(pattern2) && overlap(i, j+1))
+		case j < len(pattern2) && pattern2[j] == '*':
+			return overlap(i, j+1) || (i < len(pattern1) && overlap(i+1, j))
+		case i < len(pattern1) && j < len(pattern2):
+			if pattern1[i] == pattern2[j] || pattern1[i] == '?' || pattern2[j] == '?' {
+				return overlap(i+1, j+1)
+			}
+		}
+		return false
 	}
-	return strings.HasPrefix(resource, prefix)
+
+	return overlap(0, 0)
 }
 
 func isFullUrn(resource string) bool {
-	return !strings.ContainsAny(resource, "*")
+	return !strings.ContainsAny(resource, "*?")
 }
 
 // Insert restriction with filtering and cleaning
@@ -498,6 +828,10 @@ func getRestrictions(statements []Statement, resource string, resourceIsFullUrn
 	}
 	if statements != nil || len(statements) > 0 {
 		for _, statement := range statements {
+			if len(statement.NotResources) > 0 {
+				restrictions.insertNotResourcesRestriction(statement.Effect == "allow", resource, statement.NotResources)
+				continue
+			}
 			for _, statementResource := range statement.Resources {
 				// Append resource to allowed or denied resources, if the resource URN is not a prefix (full URN), and is contained inside the passed resource.
 				// Else, it means that resource is a prefix, so we have to check if the passed resource contains it or vice versa.
@@ -505,7 +839,7 @@ func getRestrictions(statements []Statement, resource string, resourceIsFullUrn
 				statementIsAllow := statement.Effect == "allow"
 
 				if !resourceIsFullUrn {
-					if isContainedOrEqual(statementResource, resource) || isContainedOrEqual(resource, statementResource) {
+					if isOverlapped(statementResource, resource) {
 						restrictions.insertRestriction(statementIsAllow, statementIsFullUrn, statementResource)
 					}
 				} else {
@@ -521,6 +855,19 @@ func getRestrictions(statements []Statement, resource string, resourceIsFullUrn
 	return restrictions
 }
 
+// Insert restriction for resources not matched by notResources, unless the requested resource is always matched
+func (r *Restrictions) insertNotResourcesRestriction(allow bool, resource string, notResources []string) {
+	if isContainedInAny(resource, notResources) {
+		return
+	}
+
+	if allow {
+		r.AllowedNotUrns = append(r.AllowedNotUrns, notResources)
+	} else {
+		r.DeniedNotUrns = append(r.DeniedNotUrns, notResources)
+	}
+}
+
 // Remove resources that are not allowed by the restrictions
 func filterResources(resources []Resource, restrictions *Restrictions) []Resource {
 	filteredResource := []Resource{}
@@ -554,6 +901,14 @@ func isAllowedResource(resource Resource, restrictions Restrictions) bool {
 			}
 		}
 	}
+	if len(restrictions.DeniedNotUrns) > 0 && !denied {
+		for _, restriction := range restrictions.DeniedNotUrns {
+			if !isContainedInAny(resource.GetUrn(), restriction) {
+				denied = true
+				break
+			}
+		}
+	}
 
 	// Check allow restrictions
 	if len(restrictions.AllowedUrnPrefixes) > 0 && !denied {
@@ -572,6 +927,24 @@ func isAllowedResource(resource Resource, restrictions Restrictions) bool {
 			}
 		}
 	}
+	if len(restrictions.AllowedNotUrns) > 0 && !denied && !allowed {
+		for _, restriction := range restrictions.AllowedNotUrns {
+			if !isContainedInAny(resource.GetUrn(), restriction) {
+				allowed = true
+				break
+			}
+		}
+	}
 
 	return allowed && !denied
 }
+
+// Returns true if a resource is matched by any of the urns
+func isContainedInAny(resource string, urns []string) bool {
+	for _, urn := range urns {
+		if isContainedOrEqual(resource, urn) {
+			return true
+		}
+	}
+	return false
+}
@@ -345,6 +345,99 @@ func TestWorkerAPI_GetAuthorizedOidcProviders(t *testing.T) {
 	}
 }
 
+func TestWorkerAPI_GetAuthorizedOrganizations(t *testing.T) {
+	testcases := map[string]struct {
+		// Authenticated user
+		requestInfo RequestInfo
+		// Resource urn that user wants to access
+		resourceUrn string
+		// Action to do
+		action string
+		// Resources received from db that system has to authorize
+		orgsToAuthorize []Organization
+		// Resources authorized by method
+		orgsAuthorized []Organization
+		// Error to compare when we expect an error
+		wantError error
+		// GetUserByExternalID Method Out Arguments
+		getUserByExternalIDResult *User
+		getUserByExternalIDError  error
+	}{
+		"OKtestCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			resourceUrn: CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			action:      ORGANIZATION_ACTION_GET_ORGANIZATION,
+			orgsToAuthorize: []Organization{
+				{
+					ID:  "654321",
+					Urn: CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+				},
+			},
+			orgsAuthorized: []Organization{
+				{
+					ID:  "654321",
+					Urn: CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+				},
+			},
+		},
+		"ErrortestCaseUserWithoutPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "USER-AUTHENTICATED",
+				Admin:      false,
+			},
+			resourceUrn: CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			action:      ORGANIZATION_ACTION_GET_ORGANIZATION,
+			orgsToAuthorize: []Organization{
+				{
+					ID:  "654321",
+					Urn: CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+				},
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId USER-AUTHENTICATED is not allowed to access to resource urn:iws:iam::org/path/org1",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "USER-AUTHENTICATED",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "USER-AUTHENTICATED"),
+			},
+		},
+		"ErrortestCaseDatabaseError": {
+			requestInfo: RequestInfo{
+				Identifier: "USER-AUTHENTICATED",
+				Admin:      false,
+			},
+			resourceUrn: CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			action:      ORGANIZATION_ACTION_GET_ORGANIZATION,
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			getUserByExternalIDError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = test.getUserByExternalIDResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = test.getUserByExternalIDError
+
+		authorizedOrgs, err := testAPI.GetAuthorizedOrganizations(test.requestInfo, test.resourceUrn, test.action, test.orgsToAuthorize)
+		checkMethodResponse(t, n, test.wantError, err, test.orgsAuthorized, authorizedOrgs)
+	}
+}
+
 func TestGetAuthorizedExternalResources(t *testing.T) {
 	testcases := map[string]struct {
 		// Authenticated user
@@ -700,6 +793,302 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 	}
 }
 
+func TestReviewSubjectAccess(t *testing.T) {
+	k8sPolicy := &Policy{
+		ID:  "POLICY-K8S-ID",
+		Urn: CreateUrn("example", RESOURCE_POLICY, "/path/", "k8s"),
+		Statements: &[]Statement{
+			{
+				Effect: "allow",
+				Actions: []string{
+					"k8s:get",
+				},
+				Resources: []string{
+					"urn:k8s:cluster::default/*",
+				},
+			},
+			{
+				Effect: "deny",
+				Actions: []string{
+					"k8s:get",
+				},
+				Resources: []string{
+					"urn:k8s:cluster::default/-/secrets/*",
+				},
+			},
+		},
+	}
+	testcases := map[string]struct {
+		// Authenticated user
+		requestInfo RequestInfo
+		// Subject reviewed
+		subject  Subject
+		action   string
+		resource string
+		// Expected result
+		expectedReview *AccessReview
+		// Error to compare when we expect an error
+		wantError error
+		// GetUserByExternalID Method Out Arguments
+		getUserByExternalIDResult *User
+		getUserByExternalIDError  error
+		// GetGroupByName Method Out Arguments
+		getGroupByNameResult *Group
+		getGroupByNameError  error
+		// GetAttachedPolicies Method Out Arguments
+		getAttachedPoliciesResult []TestPolicyGroupRelation
+	}{
+		"OktestCaseAllowedByAssertedGroup": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers", "system:authenticated"},
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			expectedReview: &AccessReview{
+				Allowed: true,
+				Reason:  "User with externalId jane is allowed to do action k8s:get over resource urn:k8s:cluster::default/-/pods/-/nginx",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "USER-ID",
+				ExternalID: "jane",
+			},
+			getGroupByNameResult: &Group{
+				ID:  "GROUP-ID",
+				Urn: CreateUrn("example", RESOURCE_GROUP, "/path/", "developers"),
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: k8sPolicy,
+				},
+			},
+		},
+		"OktestCaseDeniedByStatement": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers"},
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/secrets/-/token",
+			expectedReview: &AccessReview{
+				Allowed: false,
+				Reason:  "User with externalId jane is not allowed to do action k8s:get over resource urn:k8s:cluster::default/-/secrets/-/token",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "USER-ID",
+				ExternalID: "jane",
+			},
+			getGroupByNameResult: &Group{
+				ID:  "GROUP-ID",
+				Urn: CreateUrn("example", RESOURCE_GROUP, "/path/", "developers"),
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: k8sPolicy,
+				},
+			},
+		},
+		"OktestCaseAssertedGroupNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers"},
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			expectedReview: &AccessReview{
+				Allowed: false,
+				Reason:  "User with externalId jane is not allowed to do action k8s:get over resource urn:k8s:cluster::default/-/pods/-/nginx",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "USER-ID",
+				ExternalID: "jane",
+			},
+			getGroupByNameError: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: k8sPolicy,
+				},
+			},
+		},
+		"OktestCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "john",
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			expectedReview: &AccessReview{
+				Allowed: false,
+				Reason:  "User with externalId john not found",
+			},
+			getUserByExternalIDError: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+		},
+		"OktestCaseInvalidUser": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "system:serviceaccount:default:builder",
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			expectedReview: &AccessReview{
+				Allowed: false,
+				Reason:  "User with externalId system:serviceaccount:default:builder not found",
+			},
+		},
+		"ErrortestCaseNotAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "jane",
+				Admin:      false,
+			},
+			subject: Subject{
+				ExternalID: "john",
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId jane is not allowed to review the access of other users",
+			},
+		},
+		"ErrortestCaseInvalidOrg": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "jane",
+				Groups:     []string{"developers"},
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: org ",
+			},
+		},
+		"ErrortestCaseActionPrefix": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "jane",
+			},
+			action:   "k8s:*",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter action k8s:*. Action parameter can't be a prefix",
+			},
+		},
+		"ErrortestCaseInvalidResource": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "jane",
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default//pods",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter urn, value: urn:k8s:cluster::default//pods",
+			},
+		},
+		"ErrortestCaseGetUserDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "jane",
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			getUserByExternalIDError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrortestCaseGetGroupDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "kube-apiserver",
+				Admin:      true,
+			},
+			subject: Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers"},
+			},
+			action:   "k8s:get",
+			resource: "urn:k8s:cluster::default/-/pods/-/nginx",
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "USER-ID",
+				ExternalID: "jane",
+			},
+			getGroupByNameError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = test.getUserByExternalIDResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = test.getUserByExternalIDError
+
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = test.getGroupByNameResult
+		testRepo.ArgsOut[GetGroupByNameMethod][1] = test.getGroupByNameError
+
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = test.getAttachedPoliciesResult
+
+		review, err := testAPI.ReviewSubjectAccess(test.requestInfo, test.subject, test.action, test.resource)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedReview, review)
+		if test.getUserByExternalIDResult != nil {
+			// Check received subject in method GetUserByExternalID
+			assert.Equal(t, test.subject.ExternalID, testRepo.ArgsIn[GetUserByExternalIDMethod][0], "Error in test case %v", n)
+		}
+	}
+}
+
 // Test for aux methods of Foulkon
 
 func TestGetAuthorizedResources(t *testing.T) {
@@ -955,21 +1344,104 @@ func TestGetRestrictions(t *testing.T) {
 		// GetAttachedPolicies Method Out Arguments
 		getAttachedPoliciesResult []TestPolicyGroupRelation
 		getAttachedPoliciesError  error
+		// GetAttachedUserPolicies Method Out Arguments
+		getAttachedUserPoliciesResult []TestPolicyUserRelation
+		getAttachedUserPoliciesError  error
 	}{
-		"ErrortestCaseGetUserAuthenticatedNotFound": {
-			authUserID:  "NotFound",
-			resourceUrn: "urn:resource",
-			action:      USER_ACTION_GET_USER,
-			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "Authenticated user with externalId NotFound not found. Unable to retrieve permissions.",
+		"OktestCaseUserPolicies": {
+			authUserID:  "123456",
+			resourceUrn: CreateUrn("example", RESOURCE_GROUP, "/path/", "group1"),
+			action:      GROUP_ACTION_GET_GROUP,
+			expectedRestrictions: &Restrictions{
+				AllowedUrnPrefixes: []string{GetUrnPrefix("example", RESOURCE_GROUP, "/path/")},
+				AllowedFullUrns:    []string{},
+				DeniedUrnPrefixes:  []string{},
+				DeniedFullUrns:     []string{},
 			},
-			getUserByExternalIDError: &database.Error{
-				Code: database.USER_NOT_FOUND,
+			getUserByExternalIDResult: &User{
+				ID: "123456",
+			},
+			getAttachedUserPoliciesResult: []TestPolicyUserRelation{
+				{
+					Policy: &Policy{
+						ID: "PolicyID",
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									GROUP_ACTION_GET_GROUP,
+								},
+								Resources: []string{
+									GetUrnPrefix("example", RESOURCE_GROUP, "/path/"),
+								},
+							},
+						},
+					},
+				},
 			},
 		},
-		"ErrortestCaseGetUserAuthenticatedInternalError": {
-			authUserID:  "InternalError",
+		"OktestCasePolicyVariables": {
+			authUserID:  "123456",
+			resourceUrn: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+			action:      USER_ACTION_GET_USER,
+			expectedRestrictions: &Restrictions{
+				AllowedUrnPrefixes: []string{},
+				AllowedFullUrns:    []string{CreateUrn("", RESOURCE_USER, "/path/", "user1")},
+				DeniedUrnPrefixes:  []string{},
+				DeniedFullUrns:     []string{},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "123456",
+				ExternalID: "user1",
+				Path:       "/path/",
+			},
+			getAttachedUserPoliciesResult: []TestPolicyUserRelation{
+				{
+					Policy: &Policy{
+						ID: "PolicyID",
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+								},
+								Resources: []string{
+									"urn:iws:iam::user${user.path}${user.externalId}",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrortestCaseGetUserPoliciesError": {
+			authUserID:  "InternalError",
+			resourceUrn: "urn:resource",
+			action:      USER_ACTION_GET_USER,
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDResult: &User{
+				ID: "UserID",
+			},
+			getAttachedUserPoliciesError: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrortestCaseGetUserAuthenticatedNotFound": {
+			authUserID:  "NotFound",
+			resourceUrn: "urn:resource",
+			action:      USER_ACTION_GET_USER,
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Authenticated user with externalId NotFound not found. Unable to retrieve permissions.",
+			},
+			getUserByExternalIDError: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+		},
+		"ErrortestCaseGetUserAuthenticatedInternalError": {
+			authUserID:  "InternalError",
 			resourceUrn: "urn:resource",
 			action:      USER_ACTION_GET_USER,
 			wantError: &Error{
@@ -1178,6 +1650,9 @@ func TestGetRestrictions(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = test.getAttachedPoliciesResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][2] = test.getAttachedPoliciesError
 
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][0] = test.getAttachedUserPoliciesResult
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][2] = test.getAttachedUserPoliciesError
+
 		restrictions, err := testAPI.getRestrictions(test.authUserID, test.action, test.resourceUrn)
 		checkMethodResponse(t, n, test.wantError, err, test.expectedRestrictions, restrictions)
 		if test.wantError == nil {
@@ -1276,166 +1751,598 @@ func TestGetPoliciesByGroups(t *testing.T) {
 			expectedPolicies:          []Policy{},
 			getAttachedPoliciesResult: nil,
 		},
-		"OktestCase": {
-			groups: []Group{
+		"OktestCase": {
+			groups: []Group{
+				{
+					ID: "GroupID1",
+				},
+				{
+					ID: "GroupID2",
+				},
+			},
+			expectedPolicies: []Policy{
+				{
+					ID: "PolicyID",
+				},
+				{
+					ID: "PolicyID",
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+
+						ID: "PolicyID",
+					},
+				},
+			},
+		},
+		"ErrortestCase": {
+			groups: []Group{
+				{
+					ID: "GroupID1",
+				},
+				{
+					ID: "GroupID2",
+				},
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getAttachedPoliciesError: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = test.getAttachedPoliciesResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][2] = test.getAttachedPoliciesError
+
+		policies, err := testAPI.getPoliciesByGroups(test.groups)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedPolicies, policies)
+		if test.wantError == nil && len(test.groups) > 0 {
+			assert.Equal(t, testRepo.ArgsIn[GetAttachedPoliciesMethod][0], test.groups[len(test.groups)-1].ID, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestGetParentGroupsClosure(t *testing.T) {
+	testcases := map[string]struct {
+		groups         []Group
+		expectedGroups []Group
+		// Error to compare when we expect an error
+		wantError error
+		// GetParentGroups Method results
+		parents            map[string][]Group
+		getParentGroupsErr error
+	}{
+		"OktestCaseNoParents": {
+			groups:         []Group{{ID: "G1"}},
+			expectedGroups: []Group{{ID: "G1"}},
+		},
+		"OktestCaseNested": {
+			groups:         []Group{{ID: "G1"}, {ID: "G2"}},
+			expectedGroups: []Group{{ID: "G1"}, {ID: "G2"}, {ID: "P1"}, {ID: "P2"}},
+			parents: map[string][]Group{
+				"G1": {{ID: "P1"}},
+				"G2": {{ID: "P1"}, {ID: "G1"}},
+				"P1": {{ID: "P2"}},
+				"P2": {{ID: "G2"}},
+			},
+		},
+		"ErrortestCase": {
+			groups: []Group{{ID: "G1"}},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getParentGroupsErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		parents := test.parents
+		parentsErr := test.getParentGroupsErr
+		testRepo.SpecialFuncs[GetParentGroupsMethod] = func(groupID string) ([]Group, error) {
+			if parentsErr != nil {
+				return nil, parentsErr
+			}
+			return parents[groupID], nil
+		}
+
+		groups, err := testAPI.getParentGroupsClosure(test.groups)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedGroups, groups)
+	}
+}
+
+func TestGetPoliciesByUser(t *testing.T) {
+	testcases := map[string]struct {
+		userID           string
+		expectedPolicies []Policy
+		// Error to compare when we expect an error
+		wantError error
+		// GetAttachedUserPolicies Method Out Arguments
+		getAttachedUserPoliciesResult []TestPolicyUserRelation
+		getAttachedUserPoliciesError  error
+	}{
+		"OktestCaseNoPolicies": {
+			userID:           "UserID",
+			expectedPolicies: []Policy{},
+		},
+		"OktestCase": {
+			userID: "UserID",
+			expectedPolicies: []Policy{
+				{
+					ID: "PolicyID1",
+				},
+				{
+					ID: "PolicyID2",
+				},
+			},
+			getAttachedUserPoliciesResult: []TestPolicyUserRelation{
+				{
+					Policy: &Policy{
+						ID: "PolicyID1",
+					},
+				},
+				{
+					Policy: &Policy{
+						ID: "PolicyID2",
+					},
+				},
+			},
+		},
+		"ErrortestCase": {
+			userID: "UserID",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getAttachedUserPoliciesError: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][0] = test.getAttachedUserPoliciesResult
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][2] = test.getAttachedUserPoliciesError
+
+		policies, err := testAPI.getPoliciesByUser(test.userID)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedPolicies, policies)
+		assert.Equal(t, test.userID, testRepo.ArgsIn[GetAttachedUserPoliciesMethod][0], "Error in test case %v", n)
+	}
+}
+
+func TestGetStatementsByRequestedAction(t *testing.T) {
+	testcases := map[string]struct {
+		// Policies to retrieve its statements according to an action
+		policies []Policy
+		action   string
+		// Expected data
+		expectedStatements []Statement
+	}{
+		"OktestCaseEmptyPolicies": {
+			policies: []Policy{},
+			action:   "action",
+		},
+		"OktestCaseNilPolicies": {
+			action: "action",
+		},
+		"OktestCaseFilteredStatements": {
+			policies: []Policy{
+				{
+					ID: "PolicyID1Contained",
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								"act*", "noaction",
+							},
+							Resources: []string{
+								CreateUrn("example", RESOURCE_GROUP, "/path1/", "groupAllow"),
+								CreateUrn("example", RESOURCE_GROUP, "/path2/", "groupAllow"),
+								GetUrnPrefix("example", RESOURCE_GROUP, "/path1/"),
+								GetUrnPrefix("example", RESOURCE_GROUP, "/path2/"),
+							},
+						},
+						{
+							Effect: "deny",
+							Actions: []string{
+								"noaction", "action",
+							},
+							Resources: []string{
+								CreateUrn("example", RESOURCE_POLICY, "/pathdeny/", "policydeny"),
+							},
+						},
+						{
+							Effect: "allow",
+							Actions: []string{
+								"noaction",
+							},
+							Resources: []string{
+								CreateUrn("example", RESOURCE_POLICY, "/nocontained/", "policy"),
+							},
+						},
+					},
+				},
+				{
+					ID: "PolicyID2NoContained",
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								"noact*",
+							},
+							Resources: []string{
+								CreateUrn("example", RESOURCE_GROUP, "/path1/", "groupAllow"),
+								CreateUrn("example", RESOURCE_GROUP, "/path2/", "groupAllow"),
+								GetUrnPrefix("example", RESOURCE_GROUP, "/path1/"),
+								GetUrnPrefix("example", RESOURCE_GROUP, "/path2/"),
+							},
+						},
+					},
+				},
+			},
+			action: "action",
+			expectedStatements: []Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						"act*", "noaction",
+					},
+					Resources: []string{
+						CreateUrn("example", RESOURCE_GROUP, "/path1/", "groupAllow"),
+						CreateUrn("example", RESOURCE_GROUP, "/path2/", "groupAllow"),
+						GetUrnPrefix("example", RESOURCE_GROUP, "/path1/"),
+						GetUrnPrefix("example", RESOURCE_GROUP, "/path2/"),
+					},
+				},
+				{
+					Effect: "deny",
+					Actions: []string{
+						"noaction", "action",
+					},
+					Resources: []string{
+						CreateUrn("example", RESOURCE_POLICY, "/pathdeny/", "policydeny"),
+					},
+				},
+			},
+		},
+		"OktestCaseNotActions": {
+			policies: []Policy{
+				{
+					ID: "PolicyID",
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							NotActions: []string{
+								"iam:Delete*",
+							},
+							Resources: []string{
+								GetUrnPrefix("", RESOURCE_USER, "/"),
+							},
+						},
+						{
+							Effect: "deny",
+							NotActions: []string{
+								USER_ACTION_GET_USER,
+							},
+							Resources: []string{
+								GetUrnPrefix("", RESOURCE_USER, "/admin/"),
+							},
+						},
+					},
+				},
+			},
+			action: USER_ACTION_GET_USER,
+			expectedStatements: []Statement{
+				{
+					Effect: "allow",
+					NotActions: []string{
+						"iam:Delete*",
+					},
+					Resources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/"),
+					},
+				},
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		statements := getStatementsByRequestedAction(test.policies, test.action)
+		checkMethodResponse(t, n, nil, nil, test.expectedStatements, statements)
+	}
+}
+
+func TestGetAuthorizedPoliciesWithRequestOrgVariable(t *testing.T) {
+	policy := Policy{
+		ID:  "654321",
+		Org: "org1",
+		Urn: CreateUrn("org1", RESOURCE_POLICY, "/path/", "policy1"),
+	}
+	testcases := map[string]struct {
+		// Resource urn that user wants to access
+		resourceUrn string
+		// Resources authorized by method
+		policiesAuthorized []Policy
+		// Error to compare when we expect an error
+		wantError error
+	}{
+		"OKtestCaseDeniedInOrg": {
+			resourceUrn:        GetUrnPrefix("org1", RESOURCE_POLICY, "/"),
+			policiesAuthorized: []Policy{},
+		},
+		// Deny statement applies to every organization
+		"ErrortestCaseDeniedWithoutOrg": {
+			resourceUrn: GetUrnPrefix("", RESOURCE_POLICY, "/"),
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId USER-AUTHENTICATED is not allowed to access to resource urn:iws:iam::policy/*",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = &User{
+			ID:         "543210",
+			ExternalID: "USER-AUTHENTICATED",
+			Path:       "/path/",
+			Urn:        CreateUrn("", RESOURCE_USER, "/path/", "USER-AUTHENTICATED"),
+		}
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = []TestUserGroupRelation{
+			{
+				Group: &Group{
+					ID:  "GROUP-USER-ID",
+					Org: "org1",
+				},
+			},
+		}
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = []TestPolicyGroupRelation{
+			{
+				Policy: &Policy{
+					ID:  "POLICY-USER-ID",
+					Org: "org1",
+					Statements: &[]Statement{
+						{
+							Effect:    "allow",
+							Actions:   []string{POLICY_ACTION_LIST_POLICIES},
+							Resources: []string{"urn:iws:iam:*:policy/*"},
+						},
+						{
+							Effect:    "deny",
+							Actions:   []string{POLICY_ACTION_LIST_POLICIES},
+							Resources: []string{"urn:iws:iam:${request.org}:policy/*"},
+						},
+					},
+				},
+			},
+		}
+
+		authorizedPolicies, err := testAPI.GetAuthorizedPolicies(RequestInfo{Identifier: "USER-AUTHENTICATED"},
+			test.resourceUrn, POLICY_ACTION_LIST_POLICIES, []Policy{policy})
+		checkMethodResponse(t, n, test.wantError, err, test.policiesAuthorized, authorizedPolicies)
+	}
+}
+
+func TestResolvePolicyVariables(t *testing.T) {
+	testcases := map[string]struct {
+		statements []Statement
+		user       *User
+		resource   string
+		// Expected result
+		expectedResponse []Statement
+	}{
+		"OktestCaseUserVariables": {
+			statements: []Statement{
+				{
+					Effect:  "allow",
+					Actions: []string{USER_ACTION_GET_USER},
+					Resources: []string{
+						"urn:iws:iam::user${user.path}${user.externalId}",
+						"urn:iws:iam::user/fixed/*",
+					},
+				},
+			},
+			user: &User{
+				ExternalID: "user1",
+				Path:       "/path/",
+			},
+			resource: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+			expectedResponse: []Statement{
+				{
+					Effect:  "allow",
+					Actions: []string{USER_ACTION_GET_USER},
+					Resources: []string{
+						"urn:iws:iam::user/path/user1",
+						"urn:iws:iam::user/fixed/*",
+					},
+				},
+			},
+		},
+		"OktestCaseRequestOrg": {
+			statements: []Statement{
+				{
+					Effect:    "allow",
+					Actions:   []string{GROUP_ACTION_GET_GROUP},
+					Resources: []string{"urn:iws:iam:${request.org}:group${user.path}*"},
+				},
+			},
+			user: &User{
+				ExternalID: "user1",
+				Path:       "/path/",
+			},
+			resource: CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+			expectedResponse: []Statement{
+				{
+					Effect:    "allow",
+					Actions:   []string{GROUP_ACTION_GET_GROUP},
+					Resources: []string{"urn:iws:iam:org1:group/path/*"},
+				},
+			},
+		},
+		"OktestCaseNotResources": {
+			statements: []Statement{
+				{
+					Effect:       "allow",
+					Actions:      []string{USER_ACTION_GET_USER},
+					NotResources: []string{"urn:iws:iam::user${user.path}${user.externalId}"},
+				},
+			},
+			user: &User{
+				ExternalID: "user1",
+				Path:       "/path/",
+			},
+			resource: CreateUrn("", RESOURCE_USER, "/path/", "user2"),
+			expectedResponse: []Statement{
+				{
+					Effect:       "allow",
+					Actions:      []string{USER_ACTION_GET_USER},
+					NotResources: []string{"urn:iws:iam::user/path/user1"},
+				},
+			},
+		},
+		"OktestCaseRequestWithoutOrg": {
+			statements: []Statement{
+				{
+					Effect:    "allow",
+					Actions:   []string{GROUP_ACTION_LIST_GROUPS},
+					Resources: []string{"urn:iws:iam:${request.org}:group/*"},
+				},
+			},
+			user: &User{
+				ExternalID: "user1",
+				Path:       "/path/",
+			},
+			resource: GetUrnPrefix("", RESOURCE_GROUP, "/"),
+			expectedResponse: []Statement{
+				{
+					Effect:    "allow",
+					Actions:   []string{GROUP_ACTION_LIST_GROUPS},
+					Resources: []string{},
+				},
+			},
+		},
+		"OktestCaseAllowNotResourcesWithoutOrg": {
+			statements: []Statement{
 				{
-					ID: "GroupID1",
+					Effect:       "allow",
+					Actions:      []string{USER_ACTION_GET_USER},
+					NotResources: []string{"urn:iws:iam:${request.org}:secret", "urn:iws:iam::other"},
 				},
 				{
-					ID: "GroupID2",
+					Effect:    "allow",
+					Actions:   []string{USER_ACTION_GET_USER},
+					Resources: []string{"urn:iws:iam::user/path/*"},
 				},
 			},
-			expectedPolicies: []Policy{
+			user: &User{
+				ExternalID: "user1",
+				Path:       "/path/",
+			},
+			resource: "urn:iws:iam::secret",
+			expectedResponse: []Statement{
 				{
-					ID: "PolicyID",
+					Effect:       "allow",
+					Actions:      []string{USER_ACTION_GET_USER},
+					NotResources: []string{"urn:iws:iam:*:secret", "urn:iws:iam::other"},
 				},
 				{
-					ID: "PolicyID",
+					Effect:    "allow",
+					Actions:   []string{USER_ACTION_GET_USER},
+					Resources: []string{"urn:iws:iam::user/path/*"},
 				},
 			},
-			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+		},
+		"OktestCaseDenyWithoutOrg": {
+			statements: []Statement{
 				{
-					Policy: &Policy{
-
-						ID: "PolicyID",
-					},
+					Effect:    "deny",
+					Actions:   []string{POLICY_ACTION_LIST_POLICIES},
+					Resources: []string{"urn:iws:iam:${request.org}:policy/*"},
 				},
 			},
-		},
-		"ErrortestCase": {
-			groups: []Group{
+			user: &User{
+				ExternalID: "user1",
+				Path:       "/path/",
+			},
+			resource: GetUrnPrefix("", RESOURCE_POLICY, "/"),
+			expectedResponse: []Statement{
 				{
-					ID: "GroupID1",
+					Effect:    "deny",
+					Actions:   []string{POLICY_ACTION_LIST_POLICIES},
+					Resources: []string{"urn:iws:iam:*:policy/*"},
 				},
+			},
+		},
+		"OktestCaseDenyNotResourcesWithoutOrg": {
+			statements: []Statement{
 				{
-					ID: "GroupID2",
+					Effect:       "deny",
+					Actions:      []string{USER_ACTION_GET_USER},
+					NotResources: []string{"urn:iws:iam:${request.org}:secret", "urn:iws:iam::other"},
 				},
 			},
-			wantError: &Error{
-				Code: UNKNOWN_API_ERROR,
+			user: &User{
+				ExternalID: "user1",
+				Path:       "/path/",
 			},
-			getAttachedPoliciesError: &database.Error{
-				Code: database.INTERNAL_ERROR,
+			resource: "urn:iws:iam::secret",
+			expectedResponse: []Statement{
+				{
+					Effect:       "deny",
+					Actions:      []string{USER_ACTION_GET_USER},
+					NotResources: []string{"urn:iws:iam::other"},
+				},
 			},
 		},
 	}
 
 	for n, test := range testcases {
-
-		testRepo := makeTestRepo()
-		testAPI := makeTestAPI(testRepo)
-
-		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = test.getAttachedPoliciesResult
-		testRepo.ArgsOut[GetAttachedPoliciesMethod][2] = test.getAttachedPoliciesError
-
-		policies, err := testAPI.getPoliciesByGroups(test.groups)
-		checkMethodResponse(t, n, test.wantError, err, test.expectedPolicies, policies)
-		if test.wantError == nil && len(test.groups) > 0 {
-			assert.Equal(t, testRepo.ArgsIn[GetAttachedPoliciesMethod][0], test.groups[len(test.groups)-1].ID, "Error in test case %v", n)
-		}
+		statements := resolvePolicyVariables(test.statements, test.user, test.resource)
+		checkMethodResponse(t, n, nil, nil, test.expectedResponse, statements)
 	}
 }
 
-func TestGetStatementsByRequestedAction(t *testing.T) {
+func TestGetOrgFromUrn(t *testing.T) {
 	testcases := map[string]struct {
-		// Policies to retrieve its statements according to an action
-		policies []Policy
-		action   string
-		// Expected data
-		expectedStatements []Statement
+		urn              string
+		expectedResponse string
 	}{
-		"OktestCaseEmptyPolicies": {
-			policies: []Policy{},
-			action:   "action",
+		"OktestCaseOrg": {
+			urn:              CreateUrn("org1", RESOURCE_POLICY, "/path/", "policy1"),
+			expectedResponse: "org1",
 		},
-		"OktestCaseNilPolicies": {
-			action: "action",
+		"OktestCaseUserWithoutOrg": {
+			urn:              CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+			expectedResponse: "",
 		},
-		"OktestCaseFilteredStatements": {
-			policies: []Policy{
-				{
-					ID: "PolicyID1Contained",
-					Statements: &[]Statement{
-						{
-							Effect: "allow",
-							Actions: []string{
-								"act*", "noaction",
-							},
-							Resources: []string{
-								CreateUrn("example", RESOURCE_GROUP, "/path1/", "groupAllow"),
-								CreateUrn("example", RESOURCE_GROUP, "/path2/", "groupAllow"),
-								GetUrnPrefix("example", RESOURCE_GROUP, "/path1/"),
-								GetUrnPrefix("example", RESOURCE_GROUP, "/path2/"),
-							},
-						},
-						{
-							Effect: "deny",
-							Actions: []string{
-								"noaction", "action",
-							},
-							Resources: []string{
-								CreateUrn("example", RESOURCE_POLICY, "/pathdeny/", "policydeny"),
-							},
-						},
-						{
-							Effect: "allow",
-							Actions: []string{
-								"noaction",
-							},
-							Resources: []string{
-								CreateUrn("example", RESOURCE_POLICY, "/nocontained/", "policy"),
-							},
-						},
-					},
-				},
-				{
-					ID: "PolicyID2NoContained",
-					Statements: &[]Statement{
-						{
-							Effect: "allow",
-							Actions: []string{
-								"noact*",
-							},
-							Resources: []string{
-								CreateUrn("example", RESOURCE_GROUP, "/path1/", "groupAllow"),
-								CreateUrn("example", RESOURCE_GROUP, "/path2/", "groupAllow"),
-								GetUrnPrefix("example", RESOURCE_GROUP, "/path1/"),
-								GetUrnPrefix("example", RESOURCE_GROUP, "/path2/"),
-							},
-						},
-					},
-				},
-			},
-			action: "action",
-			expectedStatements: []Statement{
-				{
-					Effect: "allow",
-					Actions: []string{
-						"act*", "noaction",
-					},
-					Resources: []string{
-						CreateUrn("example", RESOURCE_GROUP, "/path1/", "groupAllow"),
-						CreateUrn("example", RESOURCE_GROUP, "/path2/", "groupAllow"),
-						GetUrnPrefix("example", RESOURCE_GROUP, "/path1/"),
-						GetUrnPrefix("example", RESOURCE_GROUP, "/path2/"),
-					},
-				},
-				{
-					Effect: "deny",
-					Actions: []string{
-						"noaction", "action",
-					},
-					Resources: []string{
-						CreateUrn("example", RESOURCE_POLICY, "/pathdeny/", "policydeny"),
-					},
-				},
-			},
+		"OktestCasePrefixOrg": {
+			urn:              "urn:iws:iam:*",
+			expectedResponse: "",
 		},
 	}
 
 	for n, test := range testcases {
-		statements := getStatementsByRequestedAction(test.policies, test.action)
-		checkMethodResponse(t, n, nil, nil, test.expectedStatements, statements)
+		org := getOrgFromUrn(test.urn)
+		checkMethodResponse(t, n, nil, nil, test.expectedResponse, org)
 	}
 }
 
@@ -1481,6 +2388,21 @@ func TestIsActionContained(t *testing.T) {
 			},
 			expectedResponse: false,
 		},
+		"OktestCaseActionContainedWithGlob": {
+			actionRequested: "iam:ListAttachedGroupPolicies",
+			statementActions: []string{
+				"iam:*Group*",
+			},
+			expectedResponse: true,
+		},
+		"OktestCaseNoActionContainedWithLeadingWildcard": {
+			actionRequested: "iam:GetUser",
+			statementActions: []string{
+				"*iam",
+				"iam:Get?",
+			},
+			expectedResponse: false,
+		},
 		"OktestCaseNoActionContainedWithoutPrefix": {
 			actionRequested: "action",
 			statementActions: []string{
@@ -1519,6 +2441,36 @@ func TestIsResourceContained(t *testing.T) {
 			resourcePrefix:   "nores*",
 			expectedResponse: false,
 		},
+		"OktestCaseNoContainedWithoutWildcard": {
+			resource:         "resource1",
+			resourcePrefix:   "resource",
+			expectedResponse: false,
+		},
+		"OktestCaseContainedWithGlob": {
+			resource:         "urn:ews:shop:instance1:order/eu/order1",
+			resourcePrefix:   "urn:ews:shop:*:order/eu/*",
+			expectedResponse: true,
+		},
+		"OktestCaseNoContainedWithGlob": {
+			resource:         "urn:ews:shop:instance1:order/us/order1",
+			resourcePrefix:   "urn:ews:shop:*:order/eu/*",
+			expectedResponse: false,
+		},
+		"OktestCaseContainedWithSingleCharWildcard": {
+			resource:         "urn:ews:shop:instance1:order/eu/order1",
+			resourcePrefix:   "urn:ews:shop:instance?:order/eu/order?",
+			expectedResponse: true,
+		},
+		"OktestCasePatternContainedInPattern": {
+			resource:         "urn:ews:shop:instance1:order/*",
+			resourcePrefix:   "urn:ews:shop:*",
+			expectedResponse: true,
+		},
+		"OktestCaseWildcardNotContainedInSingleChar": {
+			resource:         "urn:ews:shop:instance*",
+			resourcePrefix:   "urn:ews:shop:instance?",
+			expectedResponse: false,
+		},
 	}
 
 	for n, test := range testcases {
@@ -1527,6 +2479,50 @@ func TestIsResourceContained(t *testing.T) {
 	}
 }
 
+func TestIsOverlapped(t *testing.T) {
+	testcases := map[string]struct {
+		pattern1         string
+		pattern2         string
+		expectedResponse bool
+	}{
+		"OktestCasePrefixes": {
+			pattern1:         "urn:ews:shop:*",
+			pattern2:         "urn:ews:shop:instance1:order/*",
+			expectedResponse: true,
+		},
+		"OktestCaseFullUrnInPrefix": {
+			pattern1:         "urn:ews:shop:instance1:order/order1",
+			pattern2:         "urn:ews:shop:instance1:*",
+			expectedResponse: true,
+		},
+		"OktestCasePartialOverlap": {
+			pattern1:         "urn:ews:shop:*:order/secret",
+			pattern2:         "urn:ews:shop:instance1:order/*",
+			expectedResponse: true,
+		},
+		"OktestCaseSingleCharWildcards": {
+			pattern1:         "urn:ews:shop:instance?:order/*",
+			pattern2:         "urn:ews:shop:*1:order/eu",
+			expectedResponse: true,
+		},
+		"OktestCaseNoOverlap": {
+			pattern1:         "urn:ews:shop:*:order/eu/*",
+			pattern2:         "urn:ews:store:*",
+			expectedResponse: false,
+		},
+		"OktestCaseNoOverlapFullUrns": {
+			pattern1:         "urn:ews:shop:instance1:order/order1",
+			pattern2:         "urn:ews:shop:instance1:order/order2",
+			expectedResponse: false,
+		},
+	}
+
+	for n, test := range testcases {
+		isOverlapped := isOverlapped(test.pattern1, test.pattern2)
+		checkMethodResponse(t, n, nil, nil, test.expectedResponse, isOverlapped)
+	}
+}
+
 func TestIsFullUrn(t *testing.T) {
 	testcases := map[string]struct {
 		resource         string
@@ -1540,6 +2536,10 @@ func TestIsFullUrn(t *testing.T) {
 			resource:         "resource*",
 			expectedResponse: false,
 		},
+		"OktestCaseIsNotFullUrnWithSingleCharWildcard": {
+			resource:         "resource?",
+			expectedResponse: false,
+		},
 	}
 
 	for n, test := range testcases {
@@ -2054,6 +3054,74 @@ func TestGetRestrictionsWhenResourceRequestedIsPrefix(t *testing.T) {
 				DeniedFullUrns:     []string{},
 			},
 		},
+		"OktestCaseStatementNotResources": {
+			statements: []Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						USER_ACTION_LIST_USERS,
+					},
+					NotResources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/admin/"),
+					},
+				},
+				{
+					Effect: "deny",
+					Actions: []string{
+						USER_ACTION_LIST_USERS,
+					},
+					NotResources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/"),
+					},
+				},
+			},
+			resource: GetUrnPrefix("", RESOURCE_USER, "/path/"),
+			expectedRestrictions: &Restrictions{
+				AllowedUrnPrefixes: []string{},
+				AllowedFullUrns:    []string{},
+				DeniedUrnPrefixes:  []string{},
+				DeniedFullUrns:     []string{},
+				AllowedNotUrns: [][]string{
+					{
+						GetUrnPrefix("", RESOURCE_USER, "/admin/"),
+					},
+				},
+			},
+		},
+		"OktestCaseStatementResourceGlob": {
+			statements: []Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						GROUP_ACTION_LIST_GROUPS,
+					},
+					Resources: []string{
+						"urn:iws:iam:org1:group/*",
+						"urn:iws:iam:org2:group/*",
+					},
+				},
+				{
+					Effect: "deny",
+					Actions: []string{
+						GROUP_ACTION_LIST_GROUPS,
+					},
+					Resources: []string{
+						"urn:iws:iam:*:group/secret/*",
+					},
+				},
+			},
+			resource: GetUrnPrefix("org1", RESOURCE_GROUP, "/"),
+			expectedRestrictions: &Restrictions{
+				AllowedUrnPrefixes: []string{
+					"urn:iws:iam:org1:group/*",
+				},
+				AllowedFullUrns: []string{},
+				DeniedUrnPrefixes: []string{
+					"urn:iws:iam:*:group/secret/*",
+				},
+				DeniedFullUrns: []string{},
+			},
+		},
 		"OktestCaseStatementResourcePrefix": {
 			statements: []Statement{
 				{
@@ -2311,6 +3379,48 @@ func TestIsAllowedResource(t *testing.T) {
 			},
 			expectedData: false,
 		},
+		"OktestCaseAllowedByNotUrns": {
+			resource: User{
+				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user"),
+			},
+			restrictions: Restrictions{
+				AllowedNotUrns: [][]string{
+					{
+						GetUrnPrefix("", RESOURCE_USER, "/admin/"),
+					},
+				},
+			},
+			expectedData: true,
+		},
+		"OktestCaseNotAllowedByNotUrns": {
+			resource: User{
+				Urn: CreateUrn("", RESOURCE_USER, "/admin/", "user"),
+			},
+			restrictions: Restrictions{
+				AllowedNotUrns: [][]string{
+					{
+						GetUrnPrefix("", RESOURCE_USER, "/admin/"),
+					},
+				},
+			},
+			expectedData: false,
+		},
+		"OktestCaseDeniedByNotUrns": {
+			resource: User{
+				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user"),
+			},
+			restrictions: Restrictions{
+				AllowedUrnPrefixes: []string{
+					GetUrnPrefix("", RESOURCE_USER, "/"),
+				},
+				DeniedNotUrns: [][]string{
+					{
+						GetUrnPrefix("", RESOURCE_USER, "/public/"),
+					},
+				},
+			},
+			expectedData: false,
+		},
 		"OktestCaseDeniedByUrnPrefix": {
 			resource: User{
 				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user"),
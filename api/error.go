@@ -7,6 +7,7 @@ const (
 	UNKNOWN_API_ERROR            = "UnknownApiError"
 	INVALID_PARAMETER_ERROR      = "InvalidParameterError"
 	UNAUTHORIZED_RESOURCES_ERROR = "UnauthorizedResourcesError"
+	REQUEST_BODY_TOO_LARGE       = "RequestBodyTooLarge"
 
 	// Authentication API error code
 	AUTHENTICATION_API_ERROR = "AuthenticationApiError"
@@ -23,23 +24,44 @@ const (
 	USER_IS_ALREADY_A_MEMBER_OF_GROUP = "UserIsAlreadyAMemberOfGroup"
 	USER_IS_NOT_A_MEMBER_OF_GROUP     = "UserIsNotAMemberOfGroup"
 
+	// GroupSubgroups error codes
+	GROUP_IS_ALREADY_A_SUBGROUP = "GroupIsAlreadyASubgroup"
+	GROUP_IS_NOT_A_SUBGROUP     = "GroupIsNotASubgroup"
+	GROUP_HIERARCHY_CYCLE       = "GroupHierarchyCycle"
+
 	// GroupPolicies error codes
 	POLICY_IS_ALREADY_ATTACHED_TO_GROUP = "PolicyIsAlreadyAttachedToGroup"
 	POLICY_IS_NOT_ATTACHED_TO_GROUP     = "PolicyIsNotAttachedToGroup"
 
+	// UserPolicies error codes
+	POLICY_IS_ALREADY_ATTACHED_TO_USER = "PolicyIsAlreadyAttachedToUser"
+	POLICY_IS_NOT_ATTACHED_TO_USER     = "PolicyIsNotAttachedToUser"
+
 	// Policy API error codes
 	POLICY_ALREADY_EXIST             = "PolicyAlreadyExist"
 	POLICY_BY_ORG_AND_NAME_NOT_FOUND = "PolicyWithOrgAndNameNotFound"
+	POLICY_VERSION_NOT_FOUND         = "PolicyVersionNotFound"
 
 	// Proxy resources API error codes
 	PROXY_RESOURCE_ALREADY_EXIST             = "ProxyResourceAlreadyExist"
 	PROXY_RESOURCE_BY_ORG_AND_NAME_NOT_FOUND = "ProxyResourceWithOrgAndNameNotFound"
 	PROXY_RESOURCES_ROUTES_CONFLICT          = "ProxyResourcesRoutesConflict"
 
+	// Organization API error codes
+	ORGANIZATION_ALREADY_EXIST     = "OrganizationAlreadyExist"
+	ORGANIZATION_BY_NAME_NOT_FOUND = "OrganizationWithNameNotFound"
+
 	// Auth OIDC Provider API error codes
 	AUTH_OIDC_PROVIDER_ALREADY_EXIST     = "AuthOidcProviderAlreadyExist"
 	AUTH_OIDC_PROVIDER_BY_NAME_NOT_FOUND = "AuthOidcProviderWithNameNotFound"
 
+	// Webhook API error codes
+	WEBHOOK_ALREADY_EXIST     = "WebhookAlreadyExist"
+	WEBHOOK_BY_NAME_NOT_FOUND = "WebhookWithNameNotFound"
+
+	// Optimistic concurrency error code
+	REVISION_MISMATCH = "RevisionMismatch"
+
 	// Regex error
 	REGEX_NO_MATCH = "RegexNoMatch"
 )
@@ -47,6 +69,14 @@ const (
 type Error struct {
 	Code    string `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
+	// Every problem found when there are several, like the schema violations of a request body
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail is a problem found in a value of a JSON document, located by its JSON Pointer (RFC 6901)
+type ErrorDetail struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
 }
 
 func (e Error) Error() string {
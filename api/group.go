@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Tecsisa/foulkon/database"
@@ -19,6 +20,7 @@ type Group struct {
 	Urn      string    `json:"urn,omitempty"`
 	CreateAt time.Time `json:"createAt,omitempty"`
 	UpdateAt time.Time `json:"updateAt,omitempty"`
+	Revision int       `json:"revision,omitempty"`
 }
 
 func (g Group) String() string {
@@ -41,14 +43,61 @@ type GroupMembers struct {
 	CreateAt time.Time `json:"joined,omitempty"`
 }
 
+type GroupSubgroups struct {
+	Group    string    `json:"group,omitempty"`
+	CreateAt time.Time `json:"joined,omitempty"`
+}
+
 type GroupPolicies struct {
 	Policy   string    `json:"policy,omitempty"`
 	CreateAt time.Time `json:"attached,omitempty"`
 }
 
+// Operations of group bulk requests
+const (
+	GROUP_BULK_OPERATION_ADD    = "add"
+	GROUP_BULK_OPERATION_REMOVE = "remove"
+	GROUP_BULK_OPERATION_ATTACH = "attach"
+	GROUP_BULK_OPERATION_DETACH = "detach"
+)
+
+// GroupBulkResult with the result of every item of a bulk request. Changes are only applied if all items succeed
+type GroupBulkResult struct {
+	Applied bool            `json:"applied"`
+	Items   []GroupBulkItem `json:"items"`
+}
+
+// GroupBulkItem with the operation over a user or policy and its error if it failed
+type GroupBulkItem struct {
+	Operation string `json:"operation"`
+	Name      string `json:"name"`
+	Error     *Error `json:"error,omitempty"`
+}
+
+// groupBulkOperation applies an operation to every named item
+type groupBulkOperation struct {
+	operation string
+	// Kind of the items, like users or policies. Names can only be repeated in items of different kinds
+	kind  string
+	names []string
+	apply func(txAPI WorkerAPI, name string) error
+}
+
 // GROUP API IMPLEMENTATION
 
 func (api WorkerAPI) AddGroup(requestInfo RequestInfo, org string, name string, path string) (*Group, error) {
+	var group *Group
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		group, err = txAPI.addGroup(requestInfo, org, name, path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (api WorkerAPI) addGroup(requestInfo RequestInfo, org string, name string, path string) (*Group, error) {
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -84,6 +133,11 @@ func (api WorkerAPI) AddGroup(requestInfo RequestInfo, org string, name string,
 		}
 	}
 
+	// Check if organization exists
+	if err := api.checkOrganizationExists(org); err != nil {
+		return nil, err
+	}
+
 	// Check if group already exists
 	_, err = api.GroupRepo.GetGroupByName(org, name)
 
@@ -99,14 +153,10 @@ func (api WorkerAPI) AddGroup(requestInfo RequestInfo, org string, name string,
 
 			// Check if there is an unexpected error in DB
 			if err != nil {
-				//Transform to DB error
-				dbError := err.(*database.Error)
-				return nil, &Error{
-					Code:    UNKNOWN_API_ERROR,
-					Message: dbError.Message,
-				}
+				return nil, storeError(err)
 			}
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group created %+v", createdGroup))
+			api.emitEvent(requestInfo, GROUP_EVENT_CREATED, createdGroup, nil)
 			return createdGroup, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -178,13 +228,17 @@ func (api WorkerAPI) GetGroupByName(requestInfo RequestInfo, org string, name st
 	}
 }
 
-func (api WorkerAPI) ListGroups(requestInfo RequestInfo, filter *Filter) ([]GroupIdentity, int, error) {
+func (api WorkerAPI) ListGroups(requestInfo RequestInfo, filter *Filter) ([]GroupIdentity, int, string, error) {
 	// Validate fields
 	var total int
 	orderByValidColumns := api.GroupRepo.OrderByValidColumns(GROUP_ACTION_LIST_GROUPS)
 	err := validateFilter(filter, orderByValidColumns)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
+	}
+	err = validateNextToken(filter)
+	if err != nil {
+		return nil, total, "", err
 	}
 
 	// Call repo to retrieve the groups
@@ -194,12 +248,18 @@ func (api WorkerAPI) ListGroups(requestInfo RequestInfo, filter *Filter) ([]Grou
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return nil, total, &Error{
+		return nil, total, "", &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
 
+	var nextToken string
+	if len(groups) > 0 {
+		last := groups[len(groups)-1]
+		nextToken = nextPageToken(filter, len(groups), last.CreateAt, last.ID)
+	}
+
 	// Check restrictions to list
 	var urnPrefix string
 	if len(filter.Org) == 0 {
@@ -209,7 +269,7 @@ func (api WorkerAPI) ListGroups(requestInfo RequestInfo, filter *Filter) ([]Grou
 	}
 	filteredGroups, err := api.GetAuthorizedGroups(requestInfo, urnPrefix, GROUP_ACTION_LIST_GROUPS, groups)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
 	}
 
 	// Transform to identifiers
@@ -221,7 +281,7 @@ func (api WorkerAPI) ListGroups(requestInfo RequestInfo, filter *Filter) ([]Grou
 		})
 	}
 
-	return groupIDs, total, nil
+	return groupIDs, total, nextToken, nil
 }
 
 func (api WorkerAPI) UpdateGroup(requestInfo RequestInfo, org string, name string, newName string, newPath string) (*Group, error) {
@@ -258,6 +318,11 @@ func (api WorkerAPI) UpdateGroup(requestInfo RequestInfo, org string, name strin
 		}
 	}
 
+	// Check revision required by request
+	if err := checkRevision(requestInfo, oldGroup.Revision); err != nil {
+		return nil, err
+	}
+
 	// Check if a group with "newName" already exists
 	newGroup, err := api.GetGroupByName(requestInfo, org, newName)
 
@@ -301,21 +366,18 @@ func (api WorkerAPI) UpdateGroup(requestInfo RequestInfo, org string, name strin
 		Urn:      auxGroup.Urn,
 		CreateAt: oldGroup.CreateAt,
 		UpdateAt: time.Now().UTC(),
+		Revision: oldGroup.Revision,
 	}
 
 	updatedGroup, err := api.GroupRepo.UpdateGroup(group)
 
 	// Check unexpected DB error
 	if err != nil {
-		//Transform to DB error
-		dbError := err.(*database.Error)
-		return nil, &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
+		return nil, revisionError(err)
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group updated from %+v to %+v", oldGroup, updatedGroup))
+	api.emitEvent(requestInfo, GROUP_EVENT_UPDATED, updatedGroup, nil)
 	return updatedGroup, nil
 
 }
@@ -340,23 +402,132 @@ func (api WorkerAPI) RemoveGroup(requestInfo RequestInfo, org string, name strin
 		}
 	}
 
-	err = api.GroupRepo.RemoveGroup(group.ID)
+	// Check revision required by request
+	if err := checkRevision(requestInfo, group.Revision); err != nil {
+		return err
+	}
+
+	err = api.GroupRepo.RemoveGroup(group.ID, group.Revision)
+
+	// Error handling
+	if err != nil {
+		return revisionError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group deleted %v", group))
+	api.emitEvent(requestInfo, GROUP_EVENT_DELETED, group, nil)
+	return nil
+}
+
+func (api WorkerAPI) RestoreGroup(requestInfo RequestInfo, org string, name string) (*Group, error) {
+	var group *Group
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		group, err = txAPI.restoreGroup(requestInfo, org, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (api WorkerAPI) restoreGroup(requestInfo RequestInfo, org string, name string) (*Group, error) {
+	// Validate fields
+	if !IsValidName(name) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+	if !IsValidOrg(org) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: org %v", org),
+		}
+	}
+
+	// Check if another group with the same name was created after deletion
+	_, err := api.GroupRepo.GetGroupByName(org, name)
+	if err == nil {
+		return nil, &Error{
+			Code:    GROUP_ALREADY_EXIST,
+			Message: fmt.Sprintf("Unable to restore group, group with org %v and name %v already exists", org, name),
+		}
+	}
+	if dbError := err.(*database.Error); dbError.Code != database.GROUP_NOT_FOUND {
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Call repo to retrieve the deleted group
+	group, err := api.GroupRepo.GetDeletedGroupByName(org, name)
 
 	// Error handling
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return &Error{
+		// Deleted group doesn't exist in DB
+		if dbError.Code == database.GROUP_NOT_FOUND {
+			return nil, &Error{
+				Code:    GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return nil, &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
 
-	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group deleted %v", group))
-	return nil
+	// Check restrictions
+	groupsFiltered, err := api.GetAuthorizedGroups(requestInfo, group.Urn, GROUP_ACTION_RESTORE_GROUP, []Group{*group})
+	if err != nil {
+		return nil, err
+	}
+	if len(groupsFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, group.Urn),
+		}
+	}
+
+	// Relations of deleted groups are kept, check that they don't form a cycle through the group
+	if err := api.lockGroupHierarchy(org); err != nil {
+		return nil, err
+	}
+	ancestors, err := api.getAncestorGroupIDs(group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if ancestors[group.ID] {
+		return nil, &Error{
+			Code:    GROUP_HIERARCHY_CYCLE,
+			Message: fmt.Sprintf("Group with org %v and name %v can't be restored, it would create a cycle", org, name),
+		}
+	}
+
+	err = api.GroupRepo.RestoreGroup(group.ID)
+
+	// Error handling
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group restored %v", group))
+	api.emitEvent(requestInfo, GROUP_EVENT_RESTORED, group, nil)
+	return group, nil
 }
 
 func (api WorkerAPI) AddMember(requestInfo RequestInfo, externalId string, name string, org string) error {
+	return api.inTransaction(func(txAPI WorkerAPI) error {
+		return txAPI.addMember(requestInfo, externalId, name, org)
+	})
+}
+
+func (api WorkerAPI) addMember(requestInfo RequestInfo, externalId string, name string, org string) error {
 	// Call repo to retrieve the group
 	groupDB, err := api.GetGroupByName(requestInfo, org, name)
 	if err != nil {
@@ -406,18 +577,20 @@ func (api WorkerAPI) AddMember(requestInfo RequestInfo, externalId string, name
 
 	// Check if there is an unexpected error in DB
 	if err != nil {
-		//Transform to DB error
-		dbError := err.(*database.Error)
-		return &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
+		return storeError(err)
 	}
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Member %+v added to group %+v", userDB, groupDB))
+	api.emitEvent(requestInfo, GROUP_EVENT_MEMBER_ADDED, groupDB, userDB)
 	return nil
 }
 
 func (api WorkerAPI) RemoveMember(requestInfo RequestInfo, externalId string, name string, org string) error {
+	return api.inTransaction(func(txAPI WorkerAPI) error {
+		return txAPI.removeMember(requestInfo, externalId, name, org)
+	})
+}
+
+func (api WorkerAPI) removeMember(requestInfo RequestInfo, externalId string, name string, org string) error {
 	// Call repo to retrieve the group
 	groupDB, err := api.GetGroupByName(requestInfo, org, name)
 	if err != nil {
@@ -476,31 +649,42 @@ func (api WorkerAPI) RemoveMember(requestInfo RequestInfo, externalId string, na
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Member %+v removed from group %+v", userDB, groupDB))
+	api.emitEvent(requestInfo, GROUP_EVENT_MEMBER_REMOVED, groupDB, userDB)
 	return nil
 }
 
-func (api WorkerAPI) ListMembers(requestInfo RequestInfo, filter *Filter) ([]GroupMembers, int, error) {
+func (api WorkerAPI) ListMembers(requestInfo RequestInfo, filter *Filter) ([]GroupMembers, int, string, error) {
 	// Validate fields
 	var total int
 	orderByValidColumns := api.UserRepo.OrderByValidColumns(GROUP_ACTION_LIST_MEMBERS)
 	err := validateFilter(filter, orderByValidColumns)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
+	}
+	if filter.Transitive && len(filter.NextToken) > 0 {
+		return nil, total, "", &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: NextToken can't be used with Transitive",
+		}
+	}
+	err = validateNextToken(filter)
+	if err != nil {
+		return nil, total, "", err
 	}
 
 	// Call repo to retrieve the group
 	group, err := api.GetGroupByName(requestInfo, filter.Org, filter.GroupName)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
 	}
 
 	// Check restrictions
 	groupsFiltered, err := api.GetAuthorizedGroups(requestInfo, group.Urn, GROUP_ACTION_LIST_MEMBERS, []Group{*group})
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
 	}
 	if len(groupsFiltered) < 1 {
-		return nil, total, &Error{
+		return nil, total, "", &Error{
 			Code: UNAUTHORIZED_RESOURCES_ERROR,
 			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
 				requestInfo.Identifier, group.Urn),
@@ -508,18 +692,30 @@ func (api WorkerAPI) ListMembers(requestInfo RequestInfo, filter *Filter) ([]Gro
 	}
 
 	// Get Members
-	users, total, err := api.GroupRepo.GetGroupMembers(group.ID, filter)
+	var users []UserGroupRelation
+	if filter.Transitive {
+		users, total, err = api.getTransitiveGroupMembers(group.ID, filter)
+	} else {
+		users, total, err = api.GroupRepo.GetGroupMembers(group.ID, filter)
+	}
 
 	// Error handling
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return nil, total, &Error{
+		return nil, total, "", &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
 
+	// Members of nested groups are merged, so they can't be listed by keyset
+	var nextToken string
+	if len(users) > 0 && !filter.Transitive {
+		last := users[len(users)-1]
+		nextToken = nextPageToken(filter, len(users), last.GetDate(), last.GetUser().ID)
+	}
+
 	members := []GroupMembers{}
 	if users != nil {
 		members = make([]GroupMembers, len(users), cap(users))
@@ -531,10 +727,218 @@ func (api WorkerAPI) ListMembers(requestInfo RequestInfo, filter *Filter) ([]Gro
 		}
 	}
 
-	return members, total, nil
+	return members, total, nextToken, nil
+}
+
+func (api WorkerAPI) AddSubgroup(requestInfo RequestInfo, org string, name string, subgroupName string) error {
+	return api.inTransaction(func(txAPI WorkerAPI) error {
+		return txAPI.addSubgroup(requestInfo, org, name, subgroupName)
+	})
+}
+
+func (api WorkerAPI) addSubgroup(requestInfo RequestInfo, org string, name string, subgroupName string) error {
+	// Serialize changes of the hierarchy, so concurrent requests can't create a cycle together
+	if err := api.lockGroupHierarchy(org); err != nil {
+		return err
+	}
+
+	// Call repo to retrieve the group
+	group, err := api.GetGroupByName(requestInfo, org, name)
+	if err != nil {
+		return err
+	}
+
+	// Check restrictions
+	groupsFiltered, err := api.GetAuthorizedGroups(requestInfo, group.Urn, GROUP_ACTION_ADD_SUBGROUP, []Group{*group})
+	if err != nil {
+		return err
+	}
+	if len(groupsFiltered) < 1 {
+		return &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, group.Urn),
+		}
+	}
+
+	// Call repo to retrieve the subgroup, it must belong to the same org
+	subgroup, err := api.GetGroupByName(requestInfo, org, subgroupName)
+	if err != nil {
+		return err
+	}
+
+	// Check existing relationship
+	isSubgroup, err := api.GroupRepo.IsSubgroupOfGroup(subgroup.ID, group.ID)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	if isSubgroup {
+		return &Error{
+			Code:    GROUP_IS_ALREADY_A_SUBGROUP,
+			Message: fmt.Sprintf("Group: %v is already a member of Group: %v", subgroup.Name, group.Name),
+		}
+	}
+
+	// Check that the subgroup isn't the group itself or one of its ancestors, deleted ones included
+	ancestors, err := api.getAncestorGroupIDs(group.ID)
+	if err != nil {
+		return err
+	}
+	if subgroup.ID == group.ID || ancestors[subgroup.ID] {
+		return &Error{
+			Code: GROUP_HIERARCHY_CYCLE,
+			Message: fmt.Sprintf("Group with org %v and name %v can't be a member of group with org %v and name %v, it would create a cycle",
+				subgroup.Org, subgroup.Name, group.Org, group.Name),
+		}
+	}
+
+	// Add subgroup
+	err = api.GroupRepo.AddSubgroup(group.ID, subgroup.ID)
+
+	// Check if there is an unexpected error in DB
+	if err != nil {
+		return storeError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group %+v added to group %+v", subgroup, group))
+	api.emitEvent(requestInfo, GROUP_EVENT_SUBGROUP_ADDED, group, subgroup)
+	return nil
+}
+
+func (api WorkerAPI) RemoveSubgroup(requestInfo RequestInfo, org string, name string, subgroupName string) error {
+	return api.inTransaction(func(txAPI WorkerAPI) error {
+		return txAPI.removeSubgroup(requestInfo, org, name, subgroupName)
+	})
+}
+
+func (api WorkerAPI) removeSubgroup(requestInfo RequestInfo, org string, name string, subgroupName string) error {
+	// Call repo to retrieve the group
+	group, err := api.GetGroupByName(requestInfo, org, name)
+	if err != nil {
+		return err
+	}
+
+	// Check restrictions
+	groupsFiltered, err := api.GetAuthorizedGroups(requestInfo, group.Urn, GROUP_ACTION_REMOVE_SUBGROUP, []Group{*group})
+	if err != nil {
+		return err
+	}
+	if len(groupsFiltered) < 1 {
+		return &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, group.Urn),
+		}
+	}
+
+	// Call repo to retrieve the subgroup
+	subgroup, err := api.GetGroupByName(requestInfo, org, subgroupName)
+	if err != nil {
+		return err
+	}
+
+	// Check existing relationship
+	isSubgroup, err := api.GroupRepo.IsSubgroupOfGroup(subgroup.ID, group.ID)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	if !isSubgroup {
+		return &Error{
+			Code: GROUP_IS_NOT_A_SUBGROUP,
+			Message: fmt.Sprintf("Group with org %v and name %v is not a member of group with org %v and name %v",
+				subgroup.Org, subgroup.Name, group.Org, group.Name),
+		}
+	}
+
+	// Remove subgroup
+	err = api.GroupRepo.RemoveSubgroup(group.ID, subgroup.ID)
+
+	// Check if there is an unexpected error in DB
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group %+v removed from group %+v", subgroup, group))
+	api.emitEvent(requestInfo, GROUP_EVENT_SUBGROUP_REMOVED, group, subgroup)
+	return nil
+}
+
+func (api WorkerAPI) ListSubgroups(requestInfo RequestInfo, filter *Filter) ([]GroupSubgroups, int, error) {
+	// Validate fields
+	var total int
+	orderByValidColumns := api.GroupRepo.OrderByValidColumns(GROUP_ACTION_LIST_SUBGROUPS)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the group
+	group, err := api.GetGroupByName(requestInfo, filter.Org, filter.GroupName)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Check restrictions
+	groupsFiltered, err := api.GetAuthorizedGroups(requestInfo, group.Urn, GROUP_ACTION_LIST_SUBGROUPS, []Group{*group})
+	if err != nil {
+		return nil, total, err
+	}
+	if len(groupsFiltered) < 1 {
+		return nil, total, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, group.Urn),
+		}
+	}
+
+	// Get subgroups
+	relations, total, err := api.GroupRepo.GetSubgroups(group.ID, filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	subgroups := []GroupSubgroups{}
+	for _, r := range relations {
+		subgroups = append(subgroups, GroupSubgroups{
+			Group:    r.GetSubgroup().Name,
+			CreateAt: r.GetDate(),
+		})
+	}
+
+	return subgroups, total, nil
 }
 
 func (api WorkerAPI) AttachPolicyToGroup(requestInfo RequestInfo, org string, name string, policyName string) error {
+	return api.inTransaction(func(txAPI WorkerAPI) error {
+		return txAPI.attachPolicyToGroup(requestInfo, org, name, policyName)
+	})
+}
+
+func (api WorkerAPI) attachPolicyToGroup(requestInfo RequestInfo, org string, name string, policyName string) error {
 
 	// Check if group exists
 	group, err := api.GetGroupByName(requestInfo, org, name)
@@ -583,18 +987,21 @@ func (api WorkerAPI) AttachPolicyToGroup(requestInfo RequestInfo, org string, na
 	err = api.GroupRepo.AttachPolicy(group.ID, policy.ID)
 
 	if err != nil {
-		dbError := err.(*database.Error)
-		return &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
+		return storeError(err)
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy %+v attached to group %+v", policy, group))
+	api.emitEvent(requestInfo, GROUP_EVENT_POLICY_ATTACHED, group, policy)
 	return nil
 }
 
 func (api WorkerAPI) DetachPolicyToGroup(requestInfo RequestInfo, org string, name string, policyName string) error {
+	return api.inTransaction(func(txAPI WorkerAPI) error {
+		return txAPI.detachPolicyToGroup(requestInfo, org, name, policyName)
+	})
+}
+
+func (api WorkerAPI) detachPolicyToGroup(requestInfo RequestInfo, org string, name string, policyName string) error {
 
 	// Check if group exists
 	group, err := api.GetGroupByName(requestInfo, org, name)
@@ -652,9 +1059,52 @@ func (api WorkerAPI) DetachPolicyToGroup(requestInfo RequestInfo, org string, na
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy %+v detached from group %+v", policy, group))
+	api.emitEvent(requestInfo, GROUP_EVENT_POLICY_DETACHED, group, policy)
 	return nil
 }
 
+func (api WorkerAPI) UpdateMembers(requestInfo RequestInfo, org string, name string, add []string, remove []string) (*GroupBulkResult, error) {
+	return api.applyGroupBulkOperations(requestInfo, org, name, []groupBulkOperation{
+		{
+			operation: GROUP_BULK_OPERATION_ADD,
+			kind:      RESOURCE_USER,
+			names:     add,
+			apply: func(txAPI WorkerAPI, externalId string) error {
+				return txAPI.AddMember(requestInfo, externalId, name, org)
+			},
+		},
+		{
+			operation: GROUP_BULK_OPERATION_REMOVE,
+			kind:      RESOURCE_USER,
+			names:     remove,
+			apply: func(txAPI WorkerAPI, externalId string) error {
+				return txAPI.RemoveMember(requestInfo, externalId, name, org)
+			},
+		},
+	})
+}
+
+func (api WorkerAPI) UpdateAttachedGroupPolicies(requestInfo RequestInfo, org string, name string, attach []string, detach []string) (*GroupBulkResult, error) {
+	return api.applyGroupBulkOperations(requestInfo, org, name, []groupBulkOperation{
+		{
+			operation: GROUP_BULK_OPERATION_ATTACH,
+			kind:      RESOURCE_POLICY,
+			names:     attach,
+			apply: func(txAPI WorkerAPI, policyName string) error {
+				return txAPI.AttachPolicyToGroup(requestInfo, org, name, policyName)
+			},
+		},
+		{
+			operation: GROUP_BULK_OPERATION_DETACH,
+			kind:      RESOURCE_POLICY,
+			names:     detach,
+			apply: func(txAPI WorkerAPI, policyName string) error {
+				return txAPI.DetachPolicyToGroup(requestInfo, org, name, policyName)
+			},
+		},
+	})
+}
+
 func (api WorkerAPI) ListAttachedGroupPolicies(requestInfo RequestInfo, filter *Filter) ([]GroupPolicies, int, error) {
 	// Validate fields
 	var total int
@@ -712,6 +1162,179 @@ func (api WorkerAPI) ListAttachedGroupPolicies(requestInfo RequestInfo, filter *
 
 // PRIVATE HELPER METHODS
 
+// lockGroupHierarchy locks the group hierarchy of an organization until the transaction ends
+func (api WorkerAPI) lockGroupHierarchy(org string) error {
+	if err := api.GroupRepo.LockGroupHierarchy(org); err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	return nil
+}
+
+// getAncestorGroupIDs retrieves the IDs of the groups which a group is a member of, directly or through other
+// groups, including deleted groups. The group is only contained if it's a member of itself through a cycle.
+func (api WorkerAPI) getAncestorGroupIDs(groupID string) (map[string]bool, error) {
+	ancestors := map[string]bool{}
+	pending := []string{groupID}
+	for len(pending) > 0 {
+		parentIDs, err := api.GroupRepo.GetParentGroupIDs(pending[0])
+		if err != nil {
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+		pending = pending[1:]
+		for _, id := range parentIDs {
+			if !ancestors[id] {
+				ancestors[id] = true
+				pending = append(pending, id)
+			}
+		}
+	}
+	return ancestors, nil
+}
+
+// membersByDate sorts group members by join date
+type membersByDate []UserGroupRelation
+
+func (m membersByDate) Len() int           { return len(m) }
+func (m membersByDate) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m membersByDate) Less(i, j int) bool { return m[i].GetDate().Before(m[j].GetDate()) }
+
+// Retrieve members of a group and all its nested groups, paginated and sorted by join date.
+// A user that belongs to several groups is returned once, with the oldest join date.
+func (api WorkerAPI) getTransitiveGroupMembers(groupID string, filter *Filter) ([]UserGroupRelation, int, error) {
+	groupIDs := []string{groupID}
+	visited := map[string]bool{groupID: true}
+	for i := 0; i < len(groupIDs); i++ {
+		subgroups, _, err := api.GroupRepo.GetSubgroups(groupIDs[i], &Filter{})
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, s := range subgroups {
+			if !visited[s.GetSubgroup().ID] {
+				visited[s.GetSubgroup().ID] = true
+				groupIDs = append(groupIDs, s.GetSubgroup().ID)
+			}
+		}
+	}
+
+	members := []UserGroupRelation{}
+	index := map[string]int{}
+	for _, id := range groupIDs {
+		users, _, err := api.GroupRepo.GetGroupMembers(id, &Filter{})
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, u := range users {
+			if i, ok := index[u.GetUser().ID]; ok {
+				if u.GetDate().Before(members[i].GetDate()) {
+					members[i] = u
+				}
+				continue
+			}
+			index[u.GetUser().ID] = len(members)
+			members = append(members, u)
+		}
+	}
+
+	if filter.OrderBy == "create_at desc" {
+		sort.Stable(sort.Reverse(membersByDate(members)))
+	} else {
+		sort.Stable(membersByDate(members))
+	}
+
+	total := len(members)
+	if filter.Offset >= total {
+		return []UserGroupRelation{}, total, nil
+	}
+	end := filter.Offset + filter.Limit
+	if end > total {
+		end = total
+	}
+
+	return members[filter.Offset:end], total, nil
+}
+
+// applyGroupBulkOperations applies the operations over the group in a single transaction, through the API methods
+// of every item so they are validated and authorized as if they had been requested one by one. All items are
+// processed to return their results, but changes are rolled back and the first item error is returned if any fails.
+func (api WorkerAPI) applyGroupBulkOperations(requestInfo RequestInfo, org string, name string,
+	operations []groupBulkOperation) (*GroupBulkResult, error) {
+	// Validate fields
+	items := map[string]bool{}
+	for _, o := range operations {
+		for _, item := range o.names {
+			key := o.kind + "/" + item
+			if items[key] {
+				return nil, &Error{
+					Code:    INVALID_PARAMETER_ERROR,
+					Message: fmt.Sprintf("Invalid parameter: item %v is repeated", item),
+				}
+			}
+			items[key] = true
+		}
+	}
+	if len(items) < 1 || len(items) > MAX_BULK_ITEMS {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: number of items %v, it must be between 1 and %v", len(items), MAX_BULK_ITEMS),
+		}
+	}
+
+	// Call repo to retrieve the group, so request fails as a whole if it doesn't exist
+	group, err := api.GetGroupByName(requestInfo, org, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GroupBulkResult{
+		Items: []GroupBulkItem{},
+	}
+	var itemErr *Error
+	err = api.inTransaction(func(txAPI WorkerAPI) error {
+		for _, o := range operations {
+			for _, item := range o.names {
+				bulkItem := GroupBulkItem{
+					Operation: o.operation,
+					Name:      item,
+				}
+				if err := o.apply(txAPI, item); err != nil {
+					bulkItem.Error = transactionError(err).(*Error)
+					if itemErr == nil {
+						itemErr = bulkItem.Error
+					}
+				}
+				result.Items = append(result.Items, bulkItem)
+			}
+		}
+		if itemErr != nil {
+			return itemErr
+		}
+		return nil
+	})
+
+	// Error handling
+	if err != nil {
+		if itemErr != nil {
+			return result, itemErr
+		}
+		return nil, err
+	}
+
+	result.Applied = true
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Bulk request with %v items applied to group %+v",
+		len(result.Items), group))
+	return result, nil
+}
+
 func createGroup(org string, name string, path string) Group {
 	urn := CreateUrn(org, RESOURCE_GROUP, path, name)
 	group := Group{
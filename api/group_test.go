@@ -1,7 +1,9 @@
 package api
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Tecsisa/foulkon/database"
 	"github.com/stretchr/testify/assert"
@@ -23,9 +25,10 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 		getAttachedPoliciesResult []TestPolicyGroupRelation
 		getGroupByName            *Group
 		// Manager Errors
-		getGroupByNameMethodErr      error
-		getUserByExternalIDMethodErr error
-		addGroupMethodErr            error
+		getGroupByNameMethodErr        error
+		getUserByExternalIDMethodErr   error
+		addGroupMethodErr              error
+		getOrganizationByNameMethodErr error
 	}{
 		"OKCaseAdmin": {
 			requestInfo: RequestInfo{
@@ -299,6 +302,23 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 				Code: database.INTERNAL_ERROR,
 			},
 		},
+		"ErrorCaseOrganizationNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "group1",
+			org:  "org1",
+			path: "/example/",
+			getOrganizationByNameMethodErr: &database.Error{
+				Code:    database.ORGANIZATION_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+			wantError: &Error{
+				Code:    ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+		},
 	}
 
 	for x, testcase := range testcases {
@@ -313,6 +333,7 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 		testRepo.ArgsOut[AddGroupMethod][0] = testcase.expectedGroup
 		testRepo.ArgsOut[AddGroupMethod][1] = testcase.addGroupMethodErr
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = testcase.getOrganizationByNameMethodErr
 
 		group, err := testAPI.AddGroup(testcase.requestInfo, testcase.org, testcase.name, testcase.path)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedGroup, group)
@@ -972,7 +993,7 @@ func TestAuthAPI_ListGroups(t *testing.T) {
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 
-		groups, total, err := testAPI.ListGroups(testcase.requestInfo, testcase.filter)
+		groups, total, _, err := testAPI.ListGroups(testcase.requestInfo, testcase.filter)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedGroups, groups)
 		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
 	}
@@ -1952,64 +1973,90 @@ func TestAuthAPI_RemoveGroup(t *testing.T) {
 	}
 }
 
-func TestAuthAPI_AddMember(t *testing.T) {
+func TestAuthAPI_RestoreGroup(t *testing.T) {
 	testcases := map[string]struct {
-		// API Method args
+		//API method args
 		requestInfo RequestInfo
-		userID      string
+		name        string
 		org         string
-		groupName   string
 		// Expected result
-		wantError error
+		expectedGroup *Group
+		wantError     error
 		// Manager Results
-		getGroupsByUserIDResult   []TestUserGroupRelation
-		getAttachedPoliciesResult []TestPolicyGroupRelation
-		getUserByExternalIDResult *User
-		getGroupByNameResult      *Group
-		isMemberOfGroupResult     bool
-		// Manager Errors
-		getUserByExternalIDMethodErr error
-		getGroupByNameMethodErr      error
-		addMemberMethodErr           error
-		isMemberOfGroupMethodErr     error
+		getUserByExternalIDResult         *User
+		getGroupsByUserIDResult           []TestUserGroupRelation
+		getAttachedPoliciesResult         []TestPolicyGroupRelation
+		getGroupByNameMethodResult        *Group
+		getDeletedGroupByNameMethodResult *Group
+		getParentGroupIDsResult           map[string][]string
+		// API Errors
+		getGroupByNameMethodErr        error
+		getDeletedGroupByNameMethodErr error
+		restoreGroupMethodErr          error
 	}{
-		"OkCaseAdmin": {
+		"OKCaseAdminUser": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      true,
 			},
-			userID:    "12345",
-			org:       "org1",
-			groupName: "group1",
-			getUserByExternalIDResult: &User{
-				ID:         "543210",
-				ExternalID: "12345",
-				Path:       "/test/asd/",
+			name: "group1",
+			org:  "org1",
+			expectedGroup: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "group1"),
 			},
-			getGroupByNameResult: &Group{
+			getGroupByNameMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+			getDeletedGroupByNameMethodResult: &Group{
 				ID:   "543210",
 				Name: "group1",
 				Org:  "org1",
-				Path: "/test/asd/",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "group1"),
 			},
-			isMemberOfGroupResult: false,
 		},
-		"OKCase": {
+		"OkCase": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      false,
 			},
-			userID:    "12345",
-			org:       "org1",
-			groupName: "group1",
+			name: "group1",
+			org:  "org1",
+			expectedGroup: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "group1"),
+			},
+			getGroupByNameMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+			getDeletedGroupByNameMethodResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "group1"),
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "123456",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
 					Group: &Group{
 						ID:   "GROUP-USER-ID",
 						Name: "groupUser",
+						Path: "/example/",
 						Org:  "org1",
-						Path: "/path/",
-						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "groupUser"),
+						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "groupUser"),
 					},
 				},
 			},
@@ -2019,129 +2066,127 @@ func TestAuthAPI_AddMember(t *testing.T) {
 						ID:   "POLICY-USER-ID",
 						Name: "policyUser",
 						Org:  "org1",
-						Path: "/path/",
-						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
+						Path: "/example/",
+						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policyUser"),
 						Statements: &[]Statement{
 							{
 								Effect: "allow",
 								Actions: []string{
-									"iam:*",
+									GROUP_ACTION_RESTORE_GROUP,
 								},
 								Resources: []string{
 									GetUrnPrefix("org1", RESOURCE_GROUP, ""),
-									GetUrnPrefix("", RESOURCE_USER, ""),
 								},
 							},
 						},
 					},
 				},
 			},
-			getUserByExternalIDResult: &User{
-				ID:         "543210",
-				ExternalID: "1234",
-				Path:       "/path/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+		},
+		"ErrorCaseInvalidName": {
+			name: "invalid*",
+			org:  "org1",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name invalid*",
 			},
-			getGroupByNameResult: &Group{
-				ID:   "GROUP-USER-ID",
-				Name: "group1",
-				Org:  "org1",
-				Path: "/path/",
-				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+		},
+		"ErrorCaseInvalidOrg": {
+			name: "n1",
+			org:  "**^!$%&",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: org **^!$%&",
 			},
-			isMemberOfGroupResult: false,
 		},
-		"ErrorCaseInvalidExternalID": {
+		"ErrorCaseGroupAlreadyExist": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      true,
 			},
-			userID:    "d*%$",
-			groupName: "group",
-			org:       "org1",
+			name: "group1",
+			org:  "org1",
 			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: externalId d*%$",
+				Code:    GROUP_ALREADY_EXIST,
+				Message: "Unable to restore group, group with org org1 and name group1 already exists",
 			},
-			getGroupByNameResult: &Group{
-				ID:   "GROUP-USER-ID",
+			getGroupByNameMethodResult: &Group{
+				ID:   "654321",
 				Name: "group1",
 				Org:  "org1",
-				Path: "/path/1/",
-				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/1/", "group1"),
-			},
-		},
-		"ErrorCaseInvalidOrg": {
-			userID:    "12345",
-			groupName: "group1",
-			org:       "!^**$%&",
-			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: org !^**$%&",
+				Path: "/example/",
 			},
 		},
-		"ErrorCaseInvalidGroupName": {
-			userID:    "12345",
-			org:       "org1",
-			groupName: "d*%$",
-			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: name d*%$",
+		"ErrorCaseGetGroupDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
 			},
-		},
-		"ErrorCaseGroupNotFound": {
-			userID:    "12345",
-			org:       "org1",
-			groupName: "group1",
+			name: "group1",
+			org:  "org1",
 			wantError: &Error{
-				Code: GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
 			},
 			getGroupByNameMethodErr: &database.Error{
-				Code: database.GROUP_NOT_FOUND,
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
 			},
 		},
-		"ErrorCaseUnauthorizedUser": {
+		"ErrorCaseDeletedGroupNotFound": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
-				Admin:      false,
+				Admin:      true,
 			},
-			userID:    "12345",
-			org:       "org1",
-			groupName: "group1",
+			name: "group1",
+			org:  "org1",
 			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "Authenticated user with externalId 123456 not found. Unable to retrieve permissions.",
+				Code:    GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Deleted group with organization org1 and name group1 not found",
 			},
-			getGroupByNameResult: &Group{
-				ID:   "GROUP-USER-ID",
-				Name: "groupUser",
-				Org:  "org1",
-				Path: "/path/1/",
-				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "groupUser"),
+			getGroupByNameMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
 			},
-			getUserByExternalIDMethodErr: &database.Error{
-				Code: database.USER_NOT_FOUND,
+			getDeletedGroupByNameMethodErr: &database.Error{
+				Code:    database.GROUP_NOT_FOUND,
+				Message: "Deleted group with organization org1 and name group1 not found",
 			},
 		},
-		"ErrorCaseUnauthorizedResource": {
+		"ErrorCaseNoPermissions": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      false,
 			},
-			userID:    "12345",
-			org:       "org1",
-			groupName: "group1",
+			name: "group1",
+			org:  "org1",
 			wantError: &Error{
 				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/path/1/group1",
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/example/group1",
+			},
+			getGroupByNameMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+			getDeletedGroupByNameMethodResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "group1"),
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "123456",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
 					Group: &Group{
 						ID:   "GROUP-USER-ID",
 						Name: "groupUser",
+						Path: "/example/",
 						Org:  "org1",
-						Path: "/path/1/",
+						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "groupUser"),
 					},
 				},
 			},
@@ -2151,13 +2196,13 @@ func TestAuthAPI_AddMember(t *testing.T) {
 						ID:   "POLICY-USER-ID",
 						Name: "policyUser",
 						Org:  "org1",
-						Path: "/path/",
-						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
+						Path: "/example/",
+						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policyUser"),
 						Statements: &[]Statement{
 							{
 								Effect: "allow",
 								Actions: []string{
-									GROUP_ACTION_GET_GROUP,
+									GROUP_ACTION_DELETE_GROUP,
 								},
 								Resources: []string{
 									GetUrnPrefix("org1", RESOURCE_GROUP, ""),
@@ -2167,33 +2212,134 @@ func TestAuthAPI_AddMember(t *testing.T) {
 					},
 				},
 			},
-			getUserByExternalIDResult: &User{
-				ID:         "543210",
-				ExternalID: "1234",
-				Path:       "/path/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+		},
+		"ErrorCaseCycle": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
 			},
-			getGroupByNameResult: &Group{
-				ID:   "GROUP-USER-ID",
+			name: "group1",
+			org:  "org1",
+			wantError: &Error{
+				Code:    GROUP_HIERARCHY_CYCLE,
+				Message: "Group with org org1 and name group1 can't be restored, it would create a cycle",
+			},
+			getGroupByNameMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+			getDeletedGroupByNameMethodResult: &Group{
+				ID:   "543210",
 				Name: "group1",
 				Org:  "org1",
-				Path: "/path/1/",
-				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/1/", "group1"),
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "group1"),
+			},
+			// Relations kept while the group was deleted form a cycle through it
+			getParentGroupIDsResult: map[string][]string{
+				"543210": {"PARENT"},
+				"PARENT": {"543210"},
 			},
 		},
-		"ErrorCaseDenyAddMember": {
+		"ErrorCaseRestoreGroupDBErr": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
-				Admin:      false,
+				Admin:      true,
 			},
-			userID:    "12345",
-			org:       "org1",
-			groupName: "group1",
+			name: "group1",
+			org:  "org1",
 			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/path/group1",
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
 			},
-			getGroupsByUserIDResult: []TestUserGroupRelation{
+			getGroupByNameMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+			getDeletedGroupByNameMethodResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "group1"),
+			},
+			restoreGroupMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = testcase.getGroupByNameMethodResult
+		testRepo.ArgsOut[GetGroupByNameMethod][1] = testcase.getGroupByNameMethodErr
+		testRepo.ArgsOut[GetDeletedGroupByNameMethod][0] = testcase.getDeletedGroupByNameMethodResult
+		testRepo.ArgsOut[GetDeletedGroupByNameMethod][1] = testcase.getDeletedGroupByNameMethodErr
+		testRepo.ArgsOut[RestoreGroupMethod][0] = testcase.restoreGroupMethodErr
+		parents := testcase.getParentGroupIDsResult
+		testRepo.SpecialFuncs[GetParentGroupIDsMethod] = func(groupID string) ([]string, error) {
+			return parents[groupID], nil
+		}
+		group, err := testAPI.RestoreGroup(testcase.requestInfo, testcase.org, testcase.name)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedGroup, group)
+	}
+}
+
+func TestAuthAPI_AddMember(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		userID      string
+		org         string
+		groupName   string
+		// Expected result
+		wantError error
+		// Manager Results
+		getGroupsByUserIDResult   []TestUserGroupRelation
+		getAttachedPoliciesResult []TestPolicyGroupRelation
+		getUserByExternalIDResult *User
+		getGroupByNameResult      *Group
+		isMemberOfGroupResult     bool
+		// Manager Errors
+		getUserByExternalIDMethodErr error
+		getGroupByNameMethodErr      error
+		addMemberMethodErr           error
+		isMemberOfGroupMethodErr     error
+	}{
+		"OkCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "12345",
+				Path:       "/test/asd/",
+			},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			isMemberOfGroupResult: false,
+		},
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
 					Group: &Group{
 						ID:   "GROUP-USER-ID",
@@ -2213,15 +2359,6 @@ func TestAuthAPI_AddMember(t *testing.T) {
 						Path: "/path/",
 						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
 						Statements: &[]Statement{
-							{
-								Effect: "deny",
-								Actions: []string{
-									GROUP_ACTION_ADD_MEMBER,
-								},
-								Resources: []string{
-									GetUrnPrefix("org1", RESOURCE_GROUP, "/path/"),
-								},
-							},
 							{
 								Effect: "allow",
 								Actions: []string{
@@ -2249,146 +2386,346 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				Path: "/path/",
 				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
 			},
+			isMemberOfGroupResult: false,
 		},
-		"ErrorCaseNoPermissions": {
+		"ErrorCaseInvalidExternalID": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
-				Admin:      false,
+				Admin:      true,
 			},
-			userID:    "12345",
+			userID:    "d*%$",
+			groupName: "group",
 			org:       "org1",
-			groupName: "group1",
 			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/path/group1",
-			},
-			getGroupsByUserIDResult: []TestUserGroupRelation{
-				{
-					Group: &Group{
-						ID:   "GROUP-USER-ID",
-						Name: "groupUser",
-						Org:  "org1",
-						Path: "/path/",
-						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "groupUser"),
-					},
-				},
-			},
-			getAttachedPoliciesResult: []TestPolicyGroupRelation{
-				{
-					Policy: &Policy{
-						ID:         "POLICY-USER-ID",
-						Name:       "policyUser",
-						Org:        "org1",
-						Path:       "/path/",
-						Urn:        CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
-						Statements: &[]Statement{},
-					},
-				},
-			},
-			getUserByExternalIDResult: &User{
-				ID:         "543210",
-				ExternalID: "1234",
-				Path:       "/path/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalId d*%$",
 			},
 			getGroupByNameResult: &Group{
 				ID:   "GROUP-USER-ID",
 				Name: "group1",
 				Org:  "org1",
-				Path: "/path/",
-				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+				Path: "/path/1/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/1/", "group1"),
 			},
 		},
-		"ErrorCaseUserNotFound": {
-			requestInfo: RequestInfo{
-				Identifier: "123456",
-				Admin:      true,
+		"ErrorCaseInvalidOrg": {
+			userID:    "12345",
+			groupName: "group1",
+			org:       "!^**$%&",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: org !^**$%&",
 			},
+		},
+		"ErrorCaseInvalidGroupName": {
 			userID:    "12345",
 			org:       "org1",
-			groupName: "group1",
+			groupName: "d*%$",
 			wantError: &Error{
-				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name d*%$",
 			},
-			getGroupByNameResult: &Group{
-				ID:   "543210",
-				Name: "group1",
-				Org:  "org1",
-				Path: "/test/asd/",
+		},
+		"ErrorCaseGroupNotFound": {
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			wantError: &Error{
+				Code: GROUP_BY_ORG_AND_NAME_NOT_FOUND,
 			},
-			getUserByExternalIDMethodErr: &database.Error{
-				Code: database.USER_NOT_FOUND,
+			getGroupByNameMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
 			},
 		},
-		"ErrorCaseIsAlreadyMember": {
+		"ErrorCaseUnauthorizedUser": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
-				Admin:      true,
+				Admin:      false,
 			},
 			userID:    "12345",
 			org:       "org1",
 			groupName: "group1",
 			wantError: &Error{
-				Code:    USER_IS_ALREADY_A_MEMBER_OF_GROUP,
-				Message: "User: 12345 is already a member of Group: group1",
-			},
-			getUserByExternalIDResult: &User{
-				ID:         "543210",
-				ExternalID: "12345",
-				Path:       "/test/asd/",
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Authenticated user with externalId 123456 not found. Unable to retrieve permissions.",
 			},
 			getGroupByNameResult: &Group{
-				ID:   "543210",
-				Name: "group1",
+				ID:   "GROUP-USER-ID",
+				Name: "groupUser",
 				Org:  "org1",
-				Path: "/test/asd/",
+				Path: "/path/1/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "groupUser"),
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
 			},
-			isMemberOfGroupResult: true,
 		},
-		"ErrorCaseIsMemberDBErr": {
+		"ErrorCaseUnauthorizedResource": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
-				Admin:      true,
+				Admin:      false,
 			},
 			userID:    "12345",
 			org:       "org1",
 			groupName: "group1",
 			wantError: &Error{
-				Code: UNKNOWN_API_ERROR,
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/path/1/group1",
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Org:  "org1",
+						Path: "/path/1/",
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "org1",
+						Path: "/path/",
+						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									GROUP_ACTION_GET_GROUP,
+								},
+								Resources: []string{
+									GetUrnPrefix("org1", RESOURCE_GROUP, ""),
+								},
+							},
+						},
+					},
+				},
 			},
 			getUserByExternalIDResult: &User{
 				ID:         "543210",
-				ExternalID: "12345",
-				Path:       "/test/asd/",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
 			},
 			getGroupByNameResult: &Group{
-				ID:   "543210",
+				ID:   "GROUP-USER-ID",
 				Name: "group1",
 				Org:  "org1",
-				Path: "/test/asd/",
-			},
-			isMemberOfGroupMethodErr: &database.Error{
-				Code: database.INTERNAL_ERROR,
+				Path: "/path/1/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/1/", "group1"),
 			},
 		},
-		"ErrorCaseAddMemberDBErr": {
+		"ErrorCaseDenyAddMember": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
-				Admin:      true,
+				Admin:      false,
 			},
 			userID:    "12345",
 			org:       "org1",
 			groupName: "group1",
 			wantError: &Error{
-				Code: UNKNOWN_API_ERROR,
-			},
-			getUserByExternalIDResult: &User{
-				ID:         "543210",
-				ExternalID: "12345",
-				Path:       "/test/asd/",
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/path/group1",
 			},
-			getGroupByNameResult: &Group{
-				ID:   "543210",
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Org:  "org1",
+						Path: "/path/",
+						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "org1",
+						Path: "/path/",
+						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "deny",
+								Actions: []string{
+									GROUP_ACTION_ADD_MEMBER,
+								},
+								Resources: []string{
+									GetUrnPrefix("org1", RESOURCE_GROUP, "/path/"),
+								},
+							},
+							{
+								Effect: "allow",
+								Actions: []string{
+									"iam:*",
+								},
+								Resources: []string{
+									GetUrnPrefix("org1", RESOURCE_GROUP, ""),
+									GetUrnPrefix("", RESOURCE_USER, ""),
+								},
+							},
+						},
+					},
+				},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getGroupByNameResult: &Group{
+				ID:   "GROUP-USER-ID",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/path/group1",
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Org:  "org1",
+						Path: "/path/",
+						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:         "POLICY-USER-ID",
+						Name:       "policyUser",
+						Org:        "org1",
+						Path:       "/path/",
+						Urn:        CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{},
+					},
+				},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getGroupByNameResult: &Group{
+				ID:   "GROUP-USER-ID",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			wantError: &Error{
+				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+		},
+		"ErrorCaseIsAlreadyMember": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			wantError: &Error{
+				Code:    USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+				Message: "User: 12345 is already a member of Group: group1",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "12345",
+				Path:       "/test/asd/",
+			},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			isMemberOfGroupResult: true,
+		},
+		"ErrorCaseIsMemberDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "12345",
+				Path:       "/test/asd/",
+			},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			isMemberOfGroupMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseAddMemberDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "12345",
+				Path:       "/test/asd/",
+			},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
 				Name: "group1",
 				Org:  "org1",
 				Path: "/test/asd/",
@@ -2398,6 +2735,35 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				Code: database.INTERNAL_ERROR,
 			},
 		},
+		"ErrorCaseAddMemberDBAlreadyExist": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userID:    "12345",
+			org:       "org1",
+			groupName: "group1",
+			wantError: &Error{
+				Code:    USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+				Message: "User with id 543210 is already a member of group with id 543210",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "12345",
+				Path:       "/test/asd/",
+			},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			isMemberOfGroupResult: false,
+			addMemberMethodErr: &database.Error{
+				Code:    database.GROUP_USER_RELATION_ALREADY_EXIST,
+				Message: "User with id 543210 is already a member of group with id 543210",
+			},
+		},
 	}
 
 	for x, testcase := range testcases {
@@ -3394,7 +3760,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 
-		members, total, err := testAPI.ListMembers(testcase.requestInfo, testcase.filter)
+		members, total, _, err := testAPI.ListMembers(testcase.requestInfo, testcase.filter)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedMembers, members)
 		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
 	}
@@ -4917,3 +5283,959 @@ func TestAuthAPI_ListAttachedGroupPolicies(t *testing.T) {
 		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
 	}
 }
+
+func TestAuthAPI_AddSubgroup(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo  RequestInfo
+		org          string
+		groupName    string
+		subgroupName string
+		// Expected result
+		wantError error
+		// Manager Results
+		groups                  map[string]*Group
+		isSubgroupOfGroupResult bool
+		getParentGroupIDsResult map[string][]string
+		// Manager Errors
+		lockGroupHierarchyErr      error
+		isSubgroupOfGroupMethodErr error
+		addSubgroupMethodErr       error
+	}{
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+			getParentGroupIDsResult: map[string][]string{
+				"PARENT": {"GRANDPARENT"},
+			},
+		},
+		"ErrorCaseSubgroupNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code: GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+			},
+		},
+		"ErrorCaseNoAuth": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/path/parent",
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+			},
+		},
+		"ErrorCaseAlreadySubgroup": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code:    GROUP_IS_ALREADY_A_SUBGROUP,
+				Message: "Group: child is already a member of Group: parent",
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+			isSubgroupOfGroupResult: true,
+		},
+		"ErrorCaseSelfCycle": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "parent",
+			wantError: &Error{
+				Code:    GROUP_HIERARCHY_CYCLE,
+				Message: "Group with org org1 and name parent can't be a member of group with org org1 and name parent, it would create a cycle",
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+			},
+		},
+		"ErrorCaseAncestorCycle": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "child",
+			subgroupName: "parent",
+			wantError: &Error{
+				Code:    GROUP_HIERARCHY_CYCLE,
+				Message: "Group with org org1 and name parent can't be a member of group with org org1 and name child, it would create a cycle",
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+			getParentGroupIDsResult: map[string][]string{
+				"CHILD":  {"MIDDLE"},
+				"MIDDLE": {"PARENT"},
+			},
+		},
+		"ErrorCaseCycleThroughDeletedGroup": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "child",
+			subgroupName: "parent",
+			wantError: &Error{
+				Code:    GROUP_HIERARCHY_CYCLE,
+				Message: "Group with org org1 and name parent can't be a member of group with org org1 and name child, it would create a cycle",
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+			// Deleted group DELETED is a member of PARENT, it isn't returned as active parent
+			getParentGroupIDsResult: map[string][]string{
+				"CHILD":   {"DELETED"},
+				"DELETED": {"PARENT"},
+			},
+		},
+		"ErrorCaseLockGroupHierarchyDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			lockGroupHierarchyErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseIsSubgroupDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+			isSubgroupOfGroupMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseAddSubgroupDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+			addSubgroupMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		groups := testcase.groups
+		testRepo.SpecialFuncs[GetGroupByNameMethod] = func(org string, name string) (*Group, error) {
+			if g, ok := groups[name]; ok {
+				return g, nil
+			}
+			return nil, &database.Error{Code: database.GROUP_NOT_FOUND}
+		}
+		parents := testcase.getParentGroupIDsResult
+		testRepo.SpecialFuncs[GetParentGroupIDsMethod] = func(groupID string) ([]string, error) {
+			return parents[groupID], nil
+		}
+		testRepo.SpecialFuncs[GetParentGroupsMethod] = func(groupID string) ([]Group, error) {
+			return nil, nil
+		}
+		testRepo.ArgsOut[LockGroupHierarchyMethod][0] = testcase.lockGroupHierarchyErr
+		testRepo.ArgsOut[IsSubgroupOfGroupMethod][0] = testcase.isSubgroupOfGroupResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = &User{
+			ID:         "USER-ID",
+			ExternalID: testcase.requestInfo.Identifier,
+		}
+		testRepo.ArgsOut[IsSubgroupOfGroupMethod][1] = testcase.isSubgroupOfGroupMethodErr
+		testRepo.ArgsOut[AddSubgroupMethod][0] = testcase.addSubgroupMethodErr
+
+		err := testAPI.AddSubgroup(testcase.requestInfo, testcase.org, testcase.groupName, testcase.subgroupName)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+		assert.Equal(t, testcase.org, testRepo.ArgsIn[LockGroupHierarchyMethod][0], "Error in test case %v", x)
+		if testcase.wantError == nil {
+			assert.Equal(t, groups[testcase.groupName].ID, testRepo.ArgsIn[AddSubgroupMethod][0], "Error in test case %v", x)
+			assert.Equal(t, groups[testcase.subgroupName].ID, testRepo.ArgsIn[AddSubgroupMethod][1], "Error in test case %v", x)
+		}
+	}
+}
+
+func TestAuthAPI_RemoveSubgroup(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo  RequestInfo
+		org          string
+		groupName    string
+		subgroupName string
+		// Expected result
+		wantError error
+		// Manager Results
+		groups                  map[string]*Group
+		isSubgroupOfGroupResult bool
+		// Manager Errors
+		removeSubgroupMethodErr error
+	}{
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+			isSubgroupOfGroupResult: true,
+		},
+		"ErrorCaseNotSubgroup": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code:    GROUP_IS_NOT_A_SUBGROUP,
+				Message: "Group with org org1 and name child is not a member of group with org org1 and name parent",
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+		},
+		"ErrorCaseGroupNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code: GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+			},
+		},
+		"ErrorCaseRemoveSubgroupDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "org1",
+			groupName:    "parent",
+			subgroupName: "child",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			groups: map[string]*Group{
+				"parent": {ID: "PARENT", Name: "parent", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent")},
+				"child": {ID: "CHILD", Name: "child", Org: "org1", Path: "/path/",
+					Urn: CreateUrn("org1", RESOURCE_GROUP, "/path/", "child")},
+			},
+			isSubgroupOfGroupResult: true,
+			removeSubgroupMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		groups := testcase.groups
+		testRepo.SpecialFuncs[GetGroupByNameMethod] = func(org string, name string) (*Group, error) {
+			if g, ok := groups[name]; ok {
+				return g, nil
+			}
+			return nil, &database.Error{Code: database.GROUP_NOT_FOUND}
+		}
+		testRepo.ArgsOut[IsSubgroupOfGroupMethod][0] = testcase.isSubgroupOfGroupResult
+		testRepo.ArgsOut[RemoveSubgroupMethod][0] = testcase.removeSubgroupMethodErr
+
+		err := testAPI.RemoveSubgroup(testcase.requestInfo, testcase.org, testcase.groupName, testcase.subgroupName)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+		if testcase.wantError == nil {
+			assert.Equal(t, groups[testcase.groupName].ID, testRepo.ArgsIn[RemoveSubgroupMethod][0], "Error in test case %v", x)
+			assert.Equal(t, groups[testcase.subgroupName].ID, testRepo.ArgsIn[RemoveSubgroupMethod][1], "Error in test case %v", x)
+		}
+	}
+}
+
+func TestAuthAPI_ListSubgroups(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedSubgroups []GroupSubgroups
+		totalResult       int
+		wantError         error
+		// Manager Results
+		getGroupByNameResult *Group
+		getSubgroupsResult   []TestGroupSubgroupRelation
+		// Manager Errors
+		getSubgroupsMethodErr error
+	}{
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Org:       "org1",
+				GroupName: "parent",
+			},
+			expectedSubgroups: []GroupSubgroups{
+				{
+					Group:    "child",
+					CreateAt: now,
+				},
+			},
+			totalResult: 1,
+			getGroupByNameResult: &Group{
+				ID:   "PARENT",
+				Name: "parent",
+				Org:  "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent"),
+			},
+			getSubgroupsResult: []TestGroupSubgroupRelation{
+				{
+					Subgroup: &Group{
+						ID:   "CHILD",
+						Name: "child",
+						Org:  "org1",
+					},
+					CreateAt: now,
+				},
+			},
+		},
+		"ErrorCaseInvalidOrderBy": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Org:       "org1",
+				GroupName: "parent",
+				OrderBy:   "name-desc",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: OrderBy column name",
+			},
+		},
+		"ErrorCaseGetSubgroupsDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Org:       "org1",
+				GroupName: "parent",
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getGroupByNameResult: &Group{
+				ID:   "PARENT",
+				Name: "parent",
+				Org:  "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "parent"),
+			},
+			getSubgroupsMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[OrderByValidColumnsMethod][0] = []string{"create_at"}
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = testcase.getGroupByNameResult
+		testRepo.ArgsOut[GetSubgroupsMethod][0] = testcase.getSubgroupsResult
+		testRepo.ArgsOut[GetSubgroupsMethod][1] = testcase.totalResult
+		testRepo.ArgsOut[GetSubgroupsMethod][2] = testcase.getSubgroupsMethodErr
+
+		subgroups, total, err := testAPI.ListSubgroups(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedSubgroups, subgroups)
+		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+	}
+}
+
+func TestGetTransitiveGroupMembers(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		groupID string
+		filter  *Filter
+		// Expected result
+		expectedUsers []string
+		totalResult   int
+		wantError     error
+		// Manager Results
+		subgroups map[string][]string
+		members   map[string][]TestUserGroupRelation
+		// Manager Errors
+		getGroupMembersMethodErr error
+	}{
+		"OkCase": {
+			groupID: "PARENT",
+			filter: &Filter{
+				Limit: 20,
+			},
+			expectedUsers: []string{"user1", "user2", "user3"},
+			totalResult:   3,
+			subgroups: map[string][]string{
+				"PARENT": {"CHILD"},
+				"CHILD":  {"GRANDCHILD"},
+			},
+			members: map[string][]TestUserGroupRelation{
+				"PARENT": {
+					{User: &User{ID: "U2", ExternalID: "user2"}, CreateAt: now.Add(2)},
+				},
+				"CHILD": {
+					{User: &User{ID: "U3", ExternalID: "user3"}, CreateAt: now.Add(3)},
+					{User: &User{ID: "U2", ExternalID: "user2"}, CreateAt: now.Add(4)},
+				},
+				"GRANDCHILD": {
+					{User: &User{ID: "U1", ExternalID: "user1"}, CreateAt: now.Add(1)},
+				},
+			},
+		},
+		"OkCaseDescPaginated": {
+			groupID: "PARENT",
+			filter: &Filter{
+				Offset:  1,
+				Limit:   1,
+				OrderBy: "create_at desc",
+			},
+			expectedUsers: []string{"user1"},
+			totalResult:   2,
+			subgroups: map[string][]string{
+				"PARENT": {"CHILD"},
+				"CHILD":  {"PARENT"},
+			},
+			members: map[string][]TestUserGroupRelation{
+				"PARENT": {
+					{User: &User{ID: "U1", ExternalID: "user1"}, CreateAt: now.Add(1)},
+				},
+				"CHILD": {
+					{User: &User{ID: "U2", ExternalID: "user2"}, CreateAt: now.Add(2)},
+				},
+			},
+		},
+		"OkCaseOffsetOutOfRange": {
+			groupID: "PARENT",
+			filter: &Filter{
+				Offset: 5,
+				Limit:  20,
+			},
+			expectedUsers: []string{},
+			totalResult:   1,
+			members: map[string][]TestUserGroupRelation{
+				"PARENT": {
+					{User: &User{ID: "U1", ExternalID: "user1"}, CreateAt: now},
+				},
+			},
+		},
+		"ErrorCaseGetGroupMembersDBErr": {
+			groupID: "PARENT",
+			filter: &Filter{
+				Limit: 20,
+			},
+			wantError: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			getGroupMembersMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		subgroups := testcase.subgroups
+		testRepo.SpecialFuncs[GetSubgroupsMethod] = func(groupID string, filter *Filter) ([]GroupSubgroupRelation, int, error) {
+			relations := []GroupSubgroupRelation{}
+			for _, id := range subgroups[groupID] {
+				relations = append(relations, TestGroupSubgroupRelation{Subgroup: &Group{ID: id}})
+			}
+			return relations, len(relations), nil
+		}
+		members := testcase.members
+		membersErr := testcase.getGroupMembersMethodErr
+		testRepo.SpecialFuncs[GetGroupMembersMethod] = func(groupID string, filter *Filter) ([]UserGroupRelation, int, error) {
+			if membersErr != nil {
+				return nil, 0, membersErr
+			}
+			relations := []UserGroupRelation{}
+			for _, m := range members[groupID] {
+				relations = append(relations, m)
+			}
+			return relations, len(relations), nil
+		}
+
+		users, total, err := testAPI.getTransitiveGroupMembers(testcase.groupID, testcase.filter)
+		if testcase.wantError != nil {
+			assert.Equal(t, testcase.wantError, err, "Error in test case %v", x)
+			continue
+		}
+		assert.Nil(t, err, "Error in test case %v", x)
+		externalIDs := []string{}
+		for _, u := range users {
+			externalIDs = append(externalIDs, u.GetUser().ExternalID)
+		}
+		assert.Equal(t, testcase.expectedUsers, externalIDs, "Error in test case %v", x)
+		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+	}
+}
+
+func TestAuthAPI_UpdateMembers(t *testing.T) {
+	group := &Group{
+		ID:   "GROUP-ID",
+		Name: "group1",
+		Org:  "org1",
+		Path: "/path/",
+		Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+	}
+	tooManyUsers := []string{}
+	for i := 0; i <= MAX_BULK_ITEMS; i++ {
+		tooManyUsers = append(tooManyUsers, fmt.Sprintf("user%v", i))
+	}
+	testcases := map[string]struct {
+		// API method args
+		add    []string
+		remove []string
+		// Expected result
+		expectedResponse *GroupBulkResult
+		wantError        error
+		// Manager Results
+		isMemberOfGroupResult bool
+		// API Errors
+		getGroupByNameMethodErr error
+		withTransactionErr      error
+	}{
+		"OkCaseAdd": {
+			add: []string{"user1", "user2"},
+			expectedResponse: &GroupBulkResult{
+				Applied: true,
+				Items: []GroupBulkItem{
+					{
+						Operation: GROUP_BULK_OPERATION_ADD,
+						Name:      "user1",
+					},
+					{
+						Operation: GROUP_BULK_OPERATION_ADD,
+						Name:      "user2",
+					},
+				},
+			},
+		},
+		"OkCaseRemove": {
+			remove:                []string{"user1"},
+			isMemberOfGroupResult: true,
+			expectedResponse: &GroupBulkResult{
+				Applied: true,
+				Items: []GroupBulkItem{
+					{
+						Operation: GROUP_BULK_OPERATION_REMOVE,
+						Name:      "user1",
+					},
+				},
+			},
+		},
+		"ErrorCaseItemFails": {
+			add: []string{"user1", "missing", "user2"},
+			expectedResponse: &GroupBulkResult{
+				Applied: false,
+				Items: []GroupBulkItem{
+					{
+						Operation: GROUP_BULK_OPERATION_ADD,
+						Name:      "user1",
+					},
+					{
+						Operation: GROUP_BULK_OPERATION_ADD,
+						Name:      "missing",
+						Error: &Error{
+							Code:    USER_BY_EXTERNAL_ID_NOT_FOUND,
+							Message: "User with externalId missing not found",
+						},
+					},
+					{
+						Operation: GROUP_BULK_OPERATION_ADD,
+						Name:      "user2",
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User with externalId missing not found",
+			},
+		},
+		"ErrorCaseNotMember": {
+			remove: []string{"user1"},
+			expectedResponse: &GroupBulkResult{
+				Applied: false,
+				Items: []GroupBulkItem{
+					{
+						Operation: GROUP_BULK_OPERATION_REMOVE,
+						Name:      "user1",
+						Error: &Error{
+							Code:    USER_IS_NOT_A_MEMBER_OF_GROUP,
+							Message: "User with externalId user1 is not a member of group with org org1 and name group1",
+						},
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    USER_IS_NOT_A_MEMBER_OF_GROUP,
+				Message: "User with externalId user1 is not a member of group with org org1 and name group1",
+			},
+		},
+		"ErrorCaseRepeatedItem": {
+			add:    []string{"user1"},
+			remove: []string{"user1"},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: item user1 is repeated",
+			},
+		},
+		"ErrorCaseNoItems": {
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: number of items 0, it must be between 1 and 100",
+			},
+		},
+		"ErrorCaseTooManyItems": {
+			add: tooManyUsers,
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: number of items 101, it must be between 1 and 100",
+			},
+		},
+		"ErrorCaseGroupNotFound": {
+			add: []string{"user1"},
+			wantError: &Error{
+				Code:    GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group not found",
+			},
+			getGroupByNameMethodErr: &database.Error{
+				Code:    database.GROUP_NOT_FOUND,
+				Message: "Group not found",
+			},
+		},
+		"ErrorCaseTransactionErr": {
+			add: []string{"user1"},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			withTransactionErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = group
+		testRepo.ArgsOut[GetGroupByNameMethod][1] = testcase.getGroupByNameMethodErr
+		testRepo.ArgsOut[IsMemberOfGroupMethod][0] = testcase.isMemberOfGroupResult
+		testRepo.ArgsOut[WithTransactionMethod][0] = testcase.withTransactionErr
+		testRepo.SpecialFuncs[GetUserByExternalIDMethod] = func(id string) (*User, error) {
+			if id == "missing" {
+				return nil, &database.Error{
+					Code:    database.USER_NOT_FOUND,
+					Message: fmt.Sprintf("User with externalId %v not found", id),
+				}
+			}
+			return &User{
+				ID:         id + "-ID",
+				ExternalID: id,
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", id),
+			}, nil
+		}
+		requestInfo := RequestInfo{
+			Identifier: "123456",
+			Admin:      true,
+		}
+		result, err := testAPI.UpdateMembers(requestInfo, "org1", "group1", testcase.add, testcase.remove)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResponse, result)
+		// Result of every item is returned although changes weren't applied
+		assert.Equal(t, testcase.expectedResponse, result, "Error in test case %v", x)
+	}
+}
+
+func TestAuthAPI_UpdateAttachedGroupPolicies(t *testing.T) {
+	group := &Group{
+		ID:   "GROUP-ID",
+		Name: "group1",
+		Org:  "org1",
+		Path: "/path/",
+		Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+	}
+	testcases := map[string]struct {
+		// API method args
+		requestInfo RequestInfo
+		attach      []string
+		detach      []string
+		// Expected result
+		expectedResponse *GroupBulkResult
+		wantError        error
+		// Manager Results
+		isAttachedToGroupResult bool
+	}{
+		"OkCaseAttach": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			attach: []string{"policy1", "policy2"},
+			expectedResponse: &GroupBulkResult{
+				Applied: true,
+				Items: []GroupBulkItem{
+					{
+						Operation: GROUP_BULK_OPERATION_ATTACH,
+						Name:      "policy1",
+					},
+					{
+						Operation: GROUP_BULK_OPERATION_ATTACH,
+						Name:      "policy2",
+					},
+				},
+			},
+		},
+		"OkCaseDetach": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			detach:                  []string{"policy1"},
+			isAttachedToGroupResult: true,
+			expectedResponse: &GroupBulkResult{
+				Applied: true,
+				Items: []GroupBulkItem{
+					{
+						Operation: GROUP_BULK_OPERATION_DETACH,
+						Name:      "policy1",
+					},
+				},
+			},
+		},
+		"ErrorCaseAlreadyAttached": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			attach:                  []string{"policy1"},
+			isAttachedToGroupResult: true,
+			expectedResponse: &GroupBulkResult{
+				Applied: false,
+				Items: []GroupBulkItem{
+					{
+						Operation: GROUP_BULK_OPERATION_ATTACH,
+						Name:      "policy1",
+						Error: &Error{
+							Code:    POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+							Message: "Policy: policy1 is already attached to Group: group1",
+						},
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+				Message: "Policy: policy1 is already attached to Group: group1",
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			attach: []string{"policy1"},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/path/group1",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = &User{
+			ID:         "123456",
+			ExternalID: "123456",
+			Path:       "/path/",
+			Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+		}
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = group
+		testRepo.ArgsOut[IsAttachedToGroupMethod][0] = testcase.isAttachedToGroupResult
+		testRepo.SpecialFuncs[GetPolicyByNameMethod] = func(org string, name string) (*Policy, error) {
+			return &Policy{
+				ID:   name + "-ID",
+				Name: name,
+				Org:  org,
+				Path: "/path/",
+				Urn:  CreateUrn(org, RESOURCE_POLICY, "/path/", name),
+			}, nil
+		}
+		result, err := testAPI.UpdateAttachedGroupPolicies(testcase.requestInfo, "org1", "group1", testcase.attach, testcase.detach)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResponse, result)
+		assert.Equal(t, testcase.expectedResponse, result, "Error in test case %v", x)
+	}
+}
+
+func TestApplyGroupBulkOperations(t *testing.T) {
+	testcases := map[string]struct {
+		add    []string
+		remove []string
+		attach []string
+		// Expected result
+		expectedResponse *GroupBulkResult
+		wantError        error
+	}{
+		"OkCaseSameNameInDifferentKinds": {
+			add:    []string{"foo"},
+			attach: []string{"foo"},
+			expectedResponse: &GroupBulkResult{
+				Applied: true,
+				Items: []GroupBulkItem{
+					{Operation: GROUP_BULK_OPERATION_ADD, Name: "foo"},
+					{Operation: GROUP_BULK_OPERATION_ATTACH, Name: "foo"},
+				},
+			},
+		},
+		"ErrorCaseRepeatedItemOfSameKind": {
+			add:    []string{"foo"},
+			remove: []string{"foo"},
+			attach: []string{"bar"},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: item foo is repeated",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = &Group{
+			ID:   "GROUP-ID",
+			Name: "group1",
+			Org:  "org1",
+			Path: "/path/",
+			Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+		}
+		applied := []string{}
+		apply := func(kind string) func(txAPI WorkerAPI, name string) error {
+			return func(txAPI WorkerAPI, name string) error {
+				applied = append(applied, kind+"/"+name)
+				return nil
+			}
+		}
+		requestInfo := RequestInfo{
+			Identifier: "123456",
+			Admin:      true,
+		}
+		result, err := testAPI.applyGroupBulkOperations(requestInfo, "org1", "group1", []groupBulkOperation{
+			{operation: GROUP_BULK_OPERATION_ADD, kind: RESOURCE_USER, names: testcase.add, apply: apply(RESOURCE_USER)},
+			{operation: GROUP_BULK_OPERATION_REMOVE, kind: RESOURCE_USER, names: testcase.remove, apply: apply(RESOURCE_USER)},
+			{operation: GROUP_BULK_OPERATION_ATTACH, kind: RESOURCE_POLICY, names: testcase.attach, apply: apply(RESOURCE_POLICY)},
+		})
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResponse, result)
+		if testcase.wantError == nil {
+			assert.Equal(t, []string{"user/foo", "policy/foo"}, applied, "Error in test case %v", x)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"net/http"
 	"time"
 )
 
@@ -26,13 +27,44 @@ type PolicyGroupRelation interface {
 	GetDate() time.Time
 }
 
+// GroupSubgroupRelation interface for Group-Group relationships
+type GroupSubgroupRelation interface {
+	GetGroup() *Group
+	GetSubgroup() *Group
+	GetDate() time.Time
+}
+
+// PolicyUserRelation interface for Policy-User relationships
+type PolicyUserRelation interface {
+	GetUser() *User
+	GetPolicy() *Policy
+	GetDate() time.Time
+}
+
 // WorkerAPI that implements API interfaces using repositories
 type WorkerAPI struct {
-	UserRepo     UserRepo
-	GroupRepo    GroupRepo
-	PolicyRepo   PolicyRepo
-	ProxyRepo    ProxyRepo
-	AuthOidcRepo AuthOidcRepo
+	UserRepo         UserRepo
+	GroupRepo        GroupRepo
+	PolicyRepo       PolicyRepo
+	ProxyRepo        ProxyRepo
+	AuthOidcRepo     AuthOidcRepo
+	OrganizationRepo OrganizationRepo
+	TransactionRepo  TransactionRepo
+	WebhookRepo      WebhookRepo
+
+	// Events of the changes done inside a transaction, published once it's committed
+	events *[]Event
+}
+
+// Repos groups the repositories used by the worker
+type Repos struct {
+	UserRepo         UserRepo
+	GroupRepo        GroupRepo
+	PolicyRepo       PolicyRepo
+	ProxyRepo        ProxyRepo
+	AuthOidcRepo     AuthOidcRepo
+	OrganizationRepo OrganizationRepo
+	TransactionRepo  TransactionRepo
 }
 
 // ProxyAPI that implements API interfaces using repositories
@@ -49,13 +81,41 @@ type Filter struct {
 	GroupName         string
 	ProxyResourceName string
 	AuthProviderName  string
+	WebhookName       string
+	// Search by name (external identifier for users), matching its beginning or any part of it
+	NamePrefix   string
+	NameContains string
+	// Creation and update date ranges, zero values are not applied
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	// Groups with this user as member or with this policy attached
+	Member         string
+	AttachedPolicy string
+	// Policies with a statement that references this action or resource
+	Action   string
+	Resource string
+	// Retrieve deleted entities instead of current ones
+	Deleted bool
+	// Include members of nested groups
+	Transitive bool
 	// Pagination
 	Offset int
 	Limit  int
+	// Keyset pagination, NextToken is decoded into Cursor
+	NextToken string
+	Cursor    *Cursor
 	// Sorting
 	OrderBy string
 }
 
+// Cursor with the position of the last item of a page, sorted by creation date and identifier
+type Cursor struct {
+	CreateAt time.Time
+	ID       string
+}
+
 // API INTERFACES WITH AUTHORIZATION
 
 // UserAPI interface
@@ -68,21 +128,38 @@ type UserAPI interface {
 	// user doesn't exist or unexpected error happen.
 	GetUserByExternalID(requestInfo RequestInfo, externalId string) (*User, error)
 
-	// Retrieve user identifiers from database filtered by pathPrefix (optional parameter). Throw error
-	// if pathPrefix is invalid or unexpected error happen.
-	ListUsers(requestInfo RequestInfo, filter *Filter) ([]string, int, error)
+	// Retrieve user identifiers from database filtered by pathPrefix (optional parameter), with a token
+	// to retrieve the next page. Throw error if pathPrefix is invalid or unexpected error happen.
+	ListUsers(requestInfo RequestInfo, filter *Filter) ([]string, int, string, error)
 
 	// Update user stored in database with new pathPrefix. Throw error if the input parameters
 	// are invalid, user doesn't exist or unexpected error happen.
 	UpdateUser(requestInfo RequestInfo, externalId string, newPath string) (*User, error)
 
-	// Remove user stored in database with its group relationships.
+	// Remove user stored in database. Its group relationships are kept and it can be restored until it's purged.
 	// Throw error if externalId parameter is invalid, user doesn't exist or unexpected error happen.
 	RemoveUser(requestInfo RequestInfo, externalId string) error
 
+	// Restore the last deleted user with the externalId and its relationships. Throw error if externalId
+	// parameter is invalid, deleted user doesn't exist, another user with the externalId exists or
+	// unexpected error happen.
+	RestoreUser(requestInfo RequestInfo, externalId string) (*User, error)
+
 	// Retrieve groups that belongs to the user. Throw error if externalId parameter is invalid, user
 	// doesn't exist or unexpected error happen.
 	ListGroupsByUser(requestInfo RequestInfo, filter *Filter) ([]UserGroups, int, error)
+
+	// Attach policy to user. Throw error if the input parameters are invalid, user doesn't exist,
+	// policy doesn't exist, policy is already attached to user or unexpected error happen.
+	AttachPolicyToUser(requestInfo RequestInfo, externalId string, org string, policyName string) error
+
+	// Detach policy from user. Throw error if the input parameters are invalid, user doesn't exist,
+	// policy doesn't exist, policy isn't attached to user or unexpected error happen.
+	DetachPolicyFromUser(requestInfo RequestInfo, externalId string, org string, policyName string) error
+
+	// Retrieve policies that are attached directly to the user. Throw error if the input parameters
+	// are invalid, user doesn't exist or unexpected error happen.
+	ListAttachedUserPolicies(requestInfo RequestInfo, filter *Filter) ([]UserPolicies, int, error)
 }
 
 // GroupAPI interface
@@ -96,18 +173,23 @@ type GroupAPI interface {
 	GetGroupByName(requestInfo RequestInfo, org string, name string) (*Group, error)
 
 	// Retrieve group identifiers from database filtered by org and pathPrefix parameters. These input parameters are optional.
-	// Throw error if the input parameters are invalid or unexpected error happen.
-	ListGroups(requestInfo RequestInfo, filter *Filter) ([]GroupIdentity, int, error)
+	// A token to retrieve the next page is returned too. Throw error if the input parameters are invalid or unexpected error happen.
+	ListGroups(requestInfo RequestInfo, filter *Filter) ([]GroupIdentity, int, string, error)
 
 	// Update group stored in database with new name and pathPrefix.
 	// Throw error if the input parameters are invalid, group to update doesn't exist,
 	// target group already exist or unexpected error happen.
 	UpdateGroup(requestInfo RequestInfo, org string, groupName string, newName string, newPath string) (*Group, error)
 
-	// Remove group stored in database with its user and policy relationships.
-	// Throw error if the input parameters are invalid, the group doesn't exist or unexpected error happen.
+	// Remove group stored in database. Its user and policy relationships are kept and it can be restored
+	// until it's purged. Throw error if the input parameters are invalid, the group doesn't exist or
+	// unexpected error happen.
 	RemoveGroup(requestInfo RequestInfo, org string, name string) error
 
+	// Restore the last deleted group with the name and its relationships. Throw error if the input parameters
+	// are invalid, deleted group doesn't exist, another group with the name exists or unexpected error happen.
+	RestoreGroup(requestInfo RequestInfo, org string, name string) (*Group, error)
+
 	// Add new member to group. Throw error if the input parameters are invalid, user doesn't exist,
 	// group doesn't exist, user is already a member of the group or unexpected error happen.
 	AddMember(requestInfo RequestInfo, externalId string, groupName string, org string) error
@@ -116,9 +198,23 @@ type GroupAPI interface {
 	// group doesn't exist, user isn't a member of the group or unexpected error happen.
 	RemoveMember(requestInfo RequestInfo, externalId string, groupName string, org string) error
 
-	// List user identifiers that belong to the group. Throw error if the input parameters are invalid,
+	// List user identifiers that belong to the group, with a token to retrieve the next page. If filter is transitive,
+	// members of nested groups are included too and no token is returned. Throw error if the input parameters are invalid,
+	// group doesn't exist or unexpected error happen.
+	ListMembers(requestInfo RequestInfo, filter *Filter) ([]GroupMembers, int, string, error)
+
+	// Add group as member of another group of the same org. Throw error if the input parameters are invalid,
+	// any group doesn't exist, subgroup is already a member of the group, the relation creates a cycle
+	// or unexpected error happen.
+	AddSubgroup(requestInfo RequestInfo, org string, groupName string, subgroupName string) error
+
+	// Remove group from another group. Throw error if the input parameters are invalid, any group doesn't exist,
+	// subgroup isn't a member of the group or unexpected error happen.
+	RemoveSubgroup(requestInfo RequestInfo, org string, groupName string, subgroupName string) error
+
+	// List groups that are direct members of the group. Throw error if the input parameters are invalid,
 	// group doesn't exist or unexpected error happen.
-	ListMembers(requestInfo RequestInfo, filter *Filter) ([]GroupMembers, int, error)
+	ListSubgroups(requestInfo RequestInfo, filter *Filter) ([]GroupSubgroups, int, error)
 
 	// Attach policy to group. Throw error if the input parameters are invalid, policy doesn't exist,
 	// group doesn't exist, policy is already attached to the group or unexpected error happen.
@@ -131,6 +227,16 @@ type GroupAPI interface {
 	// Retrieve policies that are attached to the group. Throw error if the input parameters are invalid,
 	// group doesn't exist or unexpected error happen.
 	ListAttachedGroupPolicies(requestInfo RequestInfo, filter *Filter) ([]GroupPolicies, int, error)
+
+	// Add and remove members of group in a single transaction, with the result of every item. Changes are only
+	// applied if all items succeed. Throw error if the input parameters are invalid, group doesn't exist,
+	// any item fails as in AddMember and RemoveMember, or unexpected error happen.
+	UpdateMembers(requestInfo RequestInfo, org string, groupName string, add []string, remove []string) (*GroupBulkResult, error)
+
+	// Attach and detach policies of group in a single transaction, with the result of every item. Changes are only
+	// applied if all items succeed. Throw error if the input parameters are invalid, group doesn't exist,
+	// any item fails as in AttachPolicyToGroup and DetachPolicyToGroup, or unexpected error happen.
+	UpdateAttachedGroupPolicies(requestInfo RequestInfo, org string, groupName string, attach []string, detach []string) (*GroupBulkResult, error)
 }
 
 // PolicyAPI interface
@@ -144,22 +250,44 @@ type PolicyAPI interface {
 	GetPolicyByName(requestInfo RequestInfo, org string, name string) (*Policy, error)
 
 	// Retrieve policy identifiers from database filtered by org and pathPrefix parameters. These input parameters are optional.
-	// Throw error if the input parameters are invalid or unexpected error happen.
-	ListPolicies(requestInfo RequestInfo, filter *Filter) ([]PolicyIdentity, int, error)
+	// A token to retrieve the next page is returned too. Throw error if the input parameters are invalid or unexpected error happen.
+	ListPolicies(requestInfo RequestInfo, filter *Filter) ([]PolicyIdentity, int, string, error)
 
 	// Update policy stored in database with new name, new pathPrefix and new statements.
-	// It overrides older statements. Throw error if the input parameters are invalid,
-	// policy to update doesn't exist, target policy already exist or unexpected error happen.
+	// New statements are stored in a new policy version that becomes the default one.
+	// Throw error if the input parameters are invalid, policy to update doesn't exist,
+	// target policy already exist or unexpected error happen.
 	UpdatePolicy(requestInfo RequestInfo, org string, name string, newName string, newPath string,
 		newStatements []Statement) (*Policy, error)
 
-	// Remove policy stored in database with its groups relationships.
-	// Throw error if the input parameters are invalid, the policy doesn't exist or unexpected error happen.
+	// Remove policy stored in database. Its versions and relationships are kept and it can be restored
+	// until it's purged. Throw error if the input parameters are invalid, the policy doesn't exist or
+	// unexpected error happen.
 	RemovePolicy(requestInfo RequestInfo, org string, name string) error
 
-	// Retrieve groups that are attached to the policy. Throw error if the input parameters are invalid,
+	// Restore the last deleted policy with the name and its relationships. Throw error if the input parameters
+	// are invalid, deleted policy doesn't exist, another policy with the name exists or unexpected error happen.
+	RestorePolicy(requestInfo RequestInfo, org string, name string) (*Policy, error)
+
+	// Retrieve groups that are attached to the policy, with a token to retrieve the next page. Throw error
+	// if the input parameters are invalid, policy doesn't exist or unexpected error happen.
+	ListAttachedGroups(requestInfo RequestInfo, filter *Filter) ([]PolicyGroups, int, string, error)
+
+	// Retrieve versions of the policy without their statements. Throw error if the input parameters are invalid,
 	// policy doesn't exist or unexpected error happen.
-	ListAttachedGroups(requestInfo RequestInfo, filter *Filter) ([]PolicyGroups, int, error)
+	ListPolicyVersions(requestInfo RequestInfo, filter *Filter) ([]PolicyVersion, int, error)
+
+	// Retrieve a policy version with its statements. Throw error if the input parameters are invalid,
+	// policy or version don't exist or unexpected error happen.
+	GetPolicyVersion(requestInfo RequestInfo, org string, name string, version int) (*PolicyVersion, error)
+
+	// Retrieve statements added and removed between two policy versions. Throw error if the input parameters
+	// are invalid, policy or versions don't exist or unexpected error happen.
+	DiffPolicyVersions(requestInfo RequestInfo, org string, name string, fromVersion int, toVersion int) (*PolicyVersionDiff, error)
+
+	// Set the version used to evaluate the policy, which allows to roll back updates. Throw error if the
+	// input parameters are invalid, policy or version don't exist or unexpected error happen.
+	SetDefaultPolicyVersion(requestInfo RequestInfo, org string, name string, version int) (*Policy, error)
 }
 
 // AuthzAPI interface
@@ -183,6 +311,10 @@ type AuthzAPI interface {
 	// Retrieve list of authorized external resources filtered according to the input parameters. Throw error
 	// if requestInfo doesn't exist, requestInfo doesn't have access to any resources or unexpected error happen.
 	GetAuthorizedExternalResources(requestInfo RequestInfo, action string, resources []string) ([]string, error)
+
+	// Decide if a subject, that could be other than requestInfo, is allowed to do an action over an external
+	// resource. Throw error if requestInfo isn't an admin, input parameters are invalid or unexpected error happen.
+	ReviewSubjectAccess(requestInfo RequestInfo, subject Subject, action string, resource string) (*AccessReview, error)
 }
 
 // InternalProxyAPI interface to manage proxy resources
@@ -191,6 +323,23 @@ type InternalProxyAPI interface {
 	GetProxyResources() ([]ProxyResource, error)
 }
 
+// ProxyResourcesWatcher notifies changes of proxy resources as soon as they're stored
+type ProxyResourcesWatcher interface {
+	// Channel that receives a value every time proxy resources could have changed, also after
+	// reconnections when changes could have been missed. It's closed when the watcher stops.
+	Changes() <-chan struct{}
+
+	// Stop watching changes
+	Close() error
+}
+
+// InternalPurgeAPI interface to remove deleted entities
+type InternalPurgeAPI interface {
+	// Remove users, groups and policies deleted before deleteBefore with all their relationships.
+	// It returns the number of entities removed. Throw error if unexpected error happen.
+	PurgeDeletedEntities(deleteBefore time.Time) (int, error)
+}
+
 // WorkerProxyResourcesAPI interface to manage proxy resources
 type ProxyResourcesAPI interface {
 	// Store proxy resource in database. Throw error when the input parameters are invalid,
@@ -201,8 +350,8 @@ type ProxyResourcesAPI interface {
 	// Proxy resource doesn't exist or unexpected error happen.
 	GetProxyResourceByName(requestInfo RequestInfo, org string, name string) (*ProxyResource, error)
 
-	// Retrieve list of proxy resources.
-	ListProxyResources(requestInfo RequestInfo, filter *Filter) ([]ProxyResourceIdentity, int, error)
+	// Retrieve list of proxy resources, with a token to retrieve the next page.
+	ListProxyResources(requestInfo RequestInfo, filter *Filter) ([]ProxyResourceIdentity, int, string, error)
 
 	// Update proxy resource stored in database with new name, new path and new resource.
 	// It overrides the older resource. Throw error if the input parameters are invalid,
@@ -215,6 +364,29 @@ type ProxyResourcesAPI interface {
 	RemoveProxyResource(requestInfo RequestInfo, org string, name string) error
 }
 
+// OrganizationAPI interface
+type OrganizationAPI interface {
+	// Store organization in database. Throw error when parameters are invalid,
+	// the organization already exists or unexpected error happen.
+	AddOrganization(requestInfo RequestInfo, name string, path string) (*Organization, error)
+
+	// Retrieve organization from database. Throw error when parameter is invalid,
+	// the organization doesn't exist or unexpected error happen.
+	GetOrganizationByName(requestInfo RequestInfo, name string) (*Organization, error)
+
+	// Retrieve organization names from database filtered by pathPrefix (optional parameter). Throw error
+	// if pathPrefix is invalid or unexpected error happen.
+	ListOrganizations(requestInfo RequestInfo, filter *Filter) ([]string, int, error)
+
+	// Update organization stored in database with new pathPrefix. Throw error if the input parameters
+	// are invalid, the organization doesn't exist or unexpected error happen.
+	UpdateOrganization(requestInfo RequestInfo, name string, newPath string) (*Organization, error)
+
+	// Remove organization stored in database with its groups, policies and proxy resources.
+	// Throw error if name parameter is invalid, organization doesn't exist or unexpected error happen.
+	RemoveOrganization(requestInfo RequestInfo, name string) error
+}
+
 // AuthOidcAPI interface
 type AuthOidcAPI interface {
 	// Store a new OIDC provider in database. Throw error when parameters are invalid,
@@ -239,6 +411,66 @@ type AuthOidcAPI interface {
 	RemoveOidcProvider(requestInfo RequestInfo, name string) error
 }
 
+// StateAPI interface to move the complete IAM configuration between environments
+type StateAPI interface {
+	// Retrieve a document with all organizations, users, groups, policies, proxy resources and OIDC providers
+	// with their relationships. Throw error if requestInfo isn't an admin or unexpected error happen.
+	ExportState(requestInfo RequestInfo) (*State, error)
+
+	// Apply a state document in a single transaction using merge or replace mode. With validateOnly, changes are
+	// validated and returned but not stored. Throw error if requestInfo isn't an admin, document is invalid, any
+	// change fails or unexpected error happen.
+	ImportState(requestInfo RequestInfo, state *State, mode string, validateOnly bool) (*ImportResult, error)
+
+	// Compare the groups, policies and proxy resources of the organizations in the state document with the
+	// current ones, read with requestInfo permissions, and return the changes needed to reach the document.
+	// Changes are only stored if apply is true. Unmanaged entities, those that aren't in the document, are
+	// deleted unless ignoreUnmanaged is true. Throw error if document is invalid, requestInfo isn't allowed
+	// to do any change, any change fails or unexpected error happen.
+	ReconcileState(requestInfo RequestInfo, state *State, ignoreUnmanaged bool, apply bool) (*ReconcileResult, error)
+}
+
+// WebhookAPI interface to manage the webhooks notified of IAM changes
+type WebhookAPI interface {
+	// Store webhook in database. Throw error when requestInfo isn't an admin, parameters are invalid,
+	// the webhook already exists or unexpected error happen.
+	AddWebhook(requestInfo RequestInfo, name string, url string, secret string, events []string) (*Webhook, error)
+
+	// Retrieve webhook from database. Throw error when requestInfo isn't an admin, parameter is invalid,
+	// the webhook doesn't exist or unexpected error happen.
+	GetWebhookByName(requestInfo RequestInfo, name string) (*Webhook, error)
+
+	// Retrieve webhook names from database. Throw error if requestInfo isn't an admin, filter is invalid
+	// or unexpected error happen.
+	ListWebhooks(requestInfo RequestInfo, filter *Filter) ([]string, int, error)
+
+	// Update webhook stored in database with new parameters. Throw error if requestInfo isn't an admin,
+	// the input parameters are invalid, the webhook doesn't exist, target webhook already exists or
+	// unexpected error happen.
+	UpdateWebhook(requestInfo RequestInfo, name string, newName string, newURL string, newSecret string,
+		newEvents []string) (*Webhook, error)
+
+	// Remove webhook stored in database with its deliveries. Throw error if requestInfo isn't an admin,
+	// name parameter is invalid, the webhook doesn't exist or unexpected error happen.
+	RemoveWebhook(requestInfo RequestInfo, name string) error
+
+	// Retrieve the deliveries of events to the webhook, newest first. Throw error if requestInfo isn't an admin,
+	// filter is invalid, the webhook doesn't exist or unexpected error happen.
+	ListWebhookDeliveries(requestInfo RequestInfo, filter *Filter) ([]WebhookDelivery, int, error)
+}
+
+// InternalWebhookAPI interface to deliver events to webhooks
+type InternalWebhookAPI interface {
+	// Send pending deliveries whose next attempt is due using client. Failed deliveries are retried after
+	// backoff, doubled on every attempt, until maxAttempts. It returns the number of deliveries sent.
+	// Throw error if unexpected error happen.
+	DeliverWebhookEvents(client *http.Client, maxAttempts int, backoff time.Duration) (int, error)
+
+	// Remove delivered and failed deliveries last updated before updatedBefore. It returns the number
+	// of deliveries removed. Throw error if unexpected error happen.
+	PurgeWebhookDeliveries(updatedBefore time.Time) (int, error)
+}
+
 // REPOSITORY INTERFACES
 
 // UserRepo contains all database operations
@@ -253,18 +485,43 @@ type UserRepo interface {
 	// if there are problems with database.
 	GetUsersFiltered(filter *Filter) ([]User, int, error)
 
-	// Update user stored in database with new fields. Throw error if the database restrictions
-	// are not satisfied or unexpected error happen.
+	// Update user stored in database with new fields, user revision is the one being replaced. Throw error
+	// if the database restrictions are not satisfied, the revision isn't the current one or unexpected error happen.
 	UpdateUser(user User) (*User, error)
 
-	// Remove user stored in database with its group relationships.
-	// Throw error if there are problems during transactions.
-	RemoveUser(id string) error
+	// Mark user as deleted. Its group and policy relationships are kept until it's purged.
+	// Throw error if the revision isn't the current one or there are problems with database.
+	RemoveUser(id string, revision int) error
+
+	// Retrieve the last deleted user with the externalId if it exists. Otherwise it throws an error.
+	GetDeletedUserByExternalID(id string) (*User, error)
+
+	// Restore deleted user with its relationships. Throw error if there are problems with database.
+	RestoreUser(id string) error
+
+	// Remove users deleted before deleteBefore with all their relationships. It returns the number
+	// of users removed. Throw error if there are problems during transactions.
+	PurgeUsers(deleteBefore time.Time) (int, error)
 
 	// Retrieve groups that belong to the user. Throw error
 	// if there are problems with database.
 	GetGroupsByUserID(id string, filter *Filter) ([]UserGroupRelation, int, error)
 
+	// Attach policy to user. It doesn't check restrictions about existence of user or policy. It throws
+	// errors if there are problems with database.
+	AttachPolicyToUser(userID string, policyID string) error
+
+	// Detach policy from user. It doesn't check restrictions about existence of user or policy. It throws
+	// errors if there are problems with database.
+	DetachPolicyFromUser(userID string, policyID string) error
+
+	// Check if policy is attached to user. It returns true if at least one relation exists. It throws
+	// errors if there are problems with database.
+	IsAttachedToUser(userID string, policyID string) (bool, error)
+
+	// Retrieve policies that are attached to the user. Throw error if there are problems with database.
+	GetAttachedUserPolicies(userID string, filter *Filter) ([]PolicyUserRelation, int, error)
+
 	// OrderByValidColumns returns valid columns that you can use in OrderBy
 	OrderByValidColumns(action string) []string
 }
@@ -281,13 +538,23 @@ type GroupRepo interface {
 	// if there are problems with database.
 	GetGroupsFiltered(filter *Filter) ([]Group, int, error)
 
-	// Update group stored in database with new fields.
-	// Throw error if there are problems with database.
+	// Update group stored in database with new fields, group revision is the one being replaced.
+	// Throw error if the revision isn't the current one or there are problems with database.
 	UpdateGroup(group Group) (*Group, error)
 
-	// Remove group stored in database with its user and policy relationships.
-	// Throw error if there are problems during transactions.
-	RemoveGroup(groupID string) error
+	// Mark group as deleted. Its user, policy and nested group relationships are kept until it's purged.
+	// Throw error if the revision isn't the current one or there are problems with database.
+	RemoveGroup(groupID string, revision int) error
+
+	// Retrieve the last deleted group with the name if it exists. Otherwise it throws an error.
+	GetDeletedGroupByName(org string, name string) (*Group, error)
+
+	// Restore deleted group with its relationships. Throw error if there are problems with database.
+	RestoreGroup(groupID string) error
+
+	// Remove groups deleted before deleteBefore with all their relationships. It returns the number
+	// of groups removed. Throw error if there are problems during transactions.
+	PurgeGroups(deleteBefore time.Time) (int, error)
 
 	// Add new member to group. It doesn't check restrictions about existence of group or user. It throws
 	// errors if there are problems with database.
@@ -304,6 +571,32 @@ type GroupRepo interface {
 	// Retrieve users that belong to the group. Throw error if there are problems with database.
 	GetGroupMembers(groupID string, filter *Filter) ([]UserGroupRelation, int, error)
 
+	// Add group as member of another group. It doesn't check restrictions about existence of groups or cycles.
+	// It throws errors if there are problems with database.
+	AddSubgroup(groupID string, subgroupID string) error
+
+	// Remove group from another group. It doesn't check restrictions about existence of groups. It throws
+	// errors if there are problems with database.
+	RemoveSubgroup(groupID string, subgroupID string) error
+
+	// Check if group is a direct member of another group. It returns true if at least one relation exists.
+	// It throws errors if there are problems with database.
+	IsSubgroupOfGroup(subgroupID string, groupID string) (bool, error)
+
+	// Retrieve groups that are direct members of the group. Throw error if there are problems with database.
+	GetSubgroups(groupID string, filter *Filter) ([]GroupSubgroupRelation, int, error)
+
+	// Retrieve groups which the group is a direct member of. Throw error if there are problems with database.
+	GetParentGroups(groupID string) ([]Group, error)
+
+	// Retrieve IDs of groups which the group is a direct member of, including deleted groups. Throw error if
+	// there are problems with database.
+	GetParentGroupIDs(groupID string) ([]string, error)
+
+	// Lock the group hierarchy of an organization until the current transaction ends, so checks of cycles
+	// aren't run concurrently. Throw error if there are problems with database.
+	LockGroupHierarchy(org string) error
+
 	// Attach policy to group. It doesn't check restrictions about existence of group or policy. It throws
 	// errors if there are problems with database.
 	AttachPolicy(groupID string, policyID string) error
@@ -325,8 +618,8 @@ type GroupRepo interface {
 
 // PolicyRepo contains all database operations
 type PolicyRepo interface {
-	// Store policy in database if there aren't errors.
-	AddPolicy(policy Policy) (*Policy, error)
+	// Store policy in database with its first version if there aren't errors.
+	AddPolicy(policy Policy, author string) (*Policy, error)
 
 	// Retrieve policy from database if it exists. Otherwise it throws an error.
 	GetPolicyByName(org string, name string) (*Policy, error)
@@ -335,17 +628,37 @@ type PolicyRepo interface {
 	// if there are problems with database.
 	GetPoliciesFiltered(filter *Filter) ([]Policy, int, error)
 
-	// Update policy stored in database with new fields. Also it overrides statements if it has.
-	// Throw error if there are problems with database.
-	UpdatePolicy(policy Policy) (*Policy, error)
+	// Update policy stored in database with new fields, policy revision is the one being replaced. Statements are
+	// stored in a new version that becomes the default one. Throw error if the revision isn't the current one
+	// or there are problems with database.
+	UpdatePolicy(policy Policy, author string) (*Policy, error)
 
-	// Remove policy stored in database with its groups relationships.
-	// Throw error if there are problems during transactions.
-	RemovePolicy(id string) error
+	// Mark policy as deleted. Its versions and relationships are kept until it's purged.
+	// Throw error if the revision isn't the current one or there are problems with database.
+	RemovePolicy(id string, revision int) error
+
+	// Retrieve the last deleted policy with the name if it exists. Otherwise it throws an error.
+	GetDeletedPolicyByName(org string, name string) (*Policy, error)
+
+	// Restore deleted policy with its relationships. Throw error if there are problems with database.
+	RestorePolicy(id string) error
+
+	// Remove policies deleted before deleteBefore with their versions and relationships. It returns
+	// the number of policies removed. Throw error if there are problems during transactions.
+	PurgePolicies(deleteBefore time.Time) (int, error)
 
 	// Retrieve groups that are attached to the policy. Throw error if there are problems with database.
 	GetAttachedGroups(policyID string, filter *Filter) ([]PolicyGroupRelation, int, error)
 
+	// Retrieve versions of the policy without statements. Throw error if there are problems with database.
+	GetPolicyVersions(policyID string, filter *Filter) ([]PolicyVersion, int, error)
+
+	// Retrieve policy version with its statements if it exists. Otherwise it throws an error.
+	GetPolicyVersion(policyID string, version int) (*PolicyVersion, error)
+
+	// Set the default version of the policy. Throw error if there are problems with database.
+	SetDefaultPolicyVersion(policyID string, version int, revision int, updateAt time.Time) (*Policy, error)
+
 	// OrderByValidColumns returns valid columns that you can use in OrderBy
 	OrderByValidColumns(action string) []string
 }
@@ -361,13 +674,37 @@ type ProxyRepo interface {
 	// Store proxy resource in database if there aren't errors.
 	AddProxyResource(proxyResource ProxyResource) (*ProxyResource, error)
 
-	// Update proxy resource stored in database with new fields. Also it overrides statements if it has.
-	// Throw error if there are problems with database.
+	// Update proxy resource stored in database with new fields, proxy resource revision is the one being replaced.
+	// Throw error if the revision isn't the current one or there are problems with database.
 	UpdateProxyResource(proxyResource ProxyResource) (*ProxyResource, error)
 
 	// Remove proxy resource stored in database.
-	// Throw error if there are problems during transaction.
-	RemoveProxyResource(proxyResourceID string) error
+	// Throw error if the revision isn't the current one or there are problems during transaction.
+	RemoveProxyResource(proxyResourceID string, revision int) error
+
+	// OrderByValidColumns returns valid columns that you can use in OrderBy
+	OrderByValidColumns(action string) []string
+}
+
+// OrganizationRepo contains all database operations
+type OrganizationRepo interface {
+	// Store organization in database if there aren't errors.
+	AddOrganization(org Organization) (*Organization, error)
+
+	// Retrieve organization from database if it exists. Otherwise it throws an error.
+	GetOrganizationByName(name string) (*Organization, error)
+
+	// Retrieve organizations from database filtered by pathPrefix optional parameter. Throw error
+	// if there are problems with database.
+	GetOrganizationsFiltered(filter *Filter) ([]Organization, int, error)
+
+	// Update organization stored in database with new fields.
+	// Throw error if there are problems with database.
+	UpdateOrganization(org Organization) (*Organization, error)
+
+	// Remove organization stored in database with its groups, policies and proxy resources
+	// and all their relationships. Throw error if there are problems during transactions.
+	RemoveOrganization(id string) error
 
 	// OrderByValidColumns returns valid columns that you can use in OrderBy
 	OrderByValidColumns(action string) []string
@@ -396,3 +733,50 @@ type AuthOidcRepo interface {
 	// OrderByValidColumns returns valid columns that you can use in OrderBy
 	OrderByValidColumns(action string) []string
 }
+
+// WebhookRepo contains all database operations
+type WebhookRepo interface {
+	// Store webhook in database if there aren't errors.
+	AddWebhook(webhook Webhook) (*Webhook, error)
+
+	// Retrieve webhook from database if it exists. Otherwise it throws an error.
+	GetWebhookByName(name string) (*Webhook, error)
+
+	// Retrieve webhooks from database. Throw error if there are problems with database.
+	GetWebhooksFiltered(filter *Filter) ([]Webhook, int, error)
+
+	// Update webhook stored in database with new fields. Throw error if there are problems with database.
+	UpdateWebhook(webhook Webhook) (*Webhook, error)
+
+	// Remove webhook stored in database with its deliveries. Throw error if there are problems during transactions.
+	RemoveWebhook(id string) error
+
+	// Store deliveries of events to webhooks. Throw error if there are problems with database.
+	AddWebhookDeliveries(deliveries []WebhookDelivery) error
+
+	// Retrieve deliveries of the webhook. Throw error if there are problems with database.
+	GetWebhookDeliveries(webhookID string, filter *Filter) ([]WebhookDelivery, int, error)
+
+	// Retrieve up to limit pending deliveries whose next attempt is before now, oldest first, and postpone
+	// their next attempt until now plus lease. Deliveries claimed concurrently are skipped.
+	// Throw error if there are problems with database.
+	ClaimWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error)
+
+	// Update the delivery with the result of an attempt, if it's still pending and claimed until claimedUntil.
+	// Throw error if it has been claimed again or there are problems with database.
+	UpdateWebhookDelivery(delivery WebhookDelivery, claimedUntil time.Time) error
+
+	// Remove delivered and failed deliveries last updated before updatedBefore, returning the number removed.
+	// Throw error if there are problems with database.
+	PurgeWebhookDeliveries(updatedBefore time.Time) (int, error)
+
+	// OrderByValidColumns returns valid columns that you can use in OrderBy
+	OrderByValidColumns(action string) []string
+}
+
+// TransactionRepo runs several repository operations as a unit
+type TransactionRepo interface {
+	// Run function with repositories bound to a single transaction. Changes are committed if
+	// function doesn't return error, otherwise they are rolled back and the error is returned.
+	WithTransaction(f func(repos Repos) error) error
+}
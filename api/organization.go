@@ -0,0 +1,338 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/satori/go.uuid"
+)
+
+// TYPE DEFINITIONS
+
+// Organization domain
+type Organization struct {
+	ID       string    `json:"id,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Urn      string    `json:"urn,omitempty"`
+	CreateAt time.Time `json:"createAt,omitempty"`
+	UpdateAt time.Time `json:"updateAt,omitempty"`
+}
+
+func (o Organization) String() string {
+	return fmt.Sprintf("[id: %v, name: %v, path: %v, urn: %v, createAt: %v, updateAt: %v]",
+		o.ID, o.Name, o.Path, o.Urn, o.CreateAt.Format("2006-01-02 15:04:05 MST"), o.UpdateAt.Format("2006-01-02 15:04:05 MST"))
+}
+
+func (o Organization) GetUrn() string {
+	return o.Urn
+}
+
+// ORGANIZATION API IMPLEMENTATION
+
+func (api WorkerAPI) AddOrganization(requestInfo RequestInfo, name string, path string) (*Organization, error) {
+	var organization *Organization
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		organization, err = txAPI.addOrganization(requestInfo, name, path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+func (api WorkerAPI) addOrganization(requestInfo RequestInfo, name string, path string) (*Organization, error) {
+	// Validate fields
+	if !IsValidOrg(name) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+	if !IsValidPath(path) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: path %v", path),
+		}
+	}
+
+	org := createOrganization(name, path)
+
+	// Check restrictions
+	orgsFiltered, err := api.GetAuthorizedOrganizations(requestInfo, org.Urn, ORGANIZATION_ACTION_CREATE_ORGANIZATION, []Organization{org})
+	if err != nil {
+		return nil, err
+	}
+	if len(orgsFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, org.Urn),
+		}
+	}
+
+	// Check if organization already exists
+	_, err = api.OrganizationRepo.GetOrganizationByName(name)
+
+	// Check if organization could be retrieved
+	if err != nil {
+		// Transform to DB error
+		dbError := err.(*database.Error)
+		switch dbError.Code {
+		// Organization doesn't exist in DB
+		case database.ORGANIZATION_NOT_FOUND:
+			// Create organization
+			createdOrg, err := api.OrganizationRepo.AddOrganization(org)
+
+			// Check if there is an unexpected error in DB
+			if err != nil {
+				return nil, storeError(err)
+			}
+
+			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Organization created %+v", createdOrg))
+			api.emitEvent(requestInfo, ORGANIZATION_EVENT_CREATED, createdOrg, nil)
+			return createdOrg, nil
+		default: // Unexpected error
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+	} else { // Fail if organization exists
+		return nil, &Error{
+			Code:    ORGANIZATION_ALREADY_EXIST,
+			Message: fmt.Sprintf("Unable to create organization, organization with name %v already exist", name),
+		}
+	}
+}
+
+func (api WorkerAPI) GetOrganizationByName(requestInfo RequestInfo, name string) (*Organization, error) {
+	// Validate fields
+	if !IsValidOrg(name) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+
+	// Call repo to retrieve the organization
+	org, err := api.OrganizationRepo.GetOrganizationByName(name)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		// Organization doesn't exist in DB
+		if dbError.Code == database.ORGANIZATION_NOT_FOUND {
+			return nil, &Error{
+				Code:    ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions
+	orgsFiltered, err := api.GetAuthorizedOrganizations(requestInfo, org.Urn, ORGANIZATION_ACTION_GET_ORGANIZATION, []Organization{*org})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orgsFiltered) > 0 {
+		orgFiltered := orgsFiltered[0]
+		return &orgFiltered, nil
+	}
+	return nil, &Error{
+		Code: UNAUTHORIZED_RESOURCES_ERROR,
+		Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+			requestInfo.Identifier, org.Urn),
+	}
+}
+
+func (api WorkerAPI) ListOrganizations(requestInfo RequestInfo, filter *Filter) ([]string, int, error) {
+	// Validate fields
+	var total int
+	orderByValidColumns := api.OrganizationRepo.OrderByValidColumns(ORGANIZATION_ACTION_LIST_ORGANIZATIONS)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the organizations
+	orgs, total, err := api.OrganizationRepo.GetOrganizationsFiltered(filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions to list
+	urnPrefix := GetUrnPrefix("", RESOURCE_ORGANIZATION, filter.PathPrefix)
+	orgsFiltered, err := api.GetAuthorizedOrganizations(requestInfo, urnPrefix, ORGANIZATION_ACTION_LIST_ORGANIZATIONS, orgs)
+	if err != nil {
+		return nil, total, err
+	}
+
+	orgNames := []string{}
+	for _, o := range orgsFiltered {
+		orgNames = append(orgNames, o.Name)
+	}
+
+	return orgNames, total, nil
+}
+
+func (api WorkerAPI) UpdateOrganization(requestInfo RequestInfo, name string, newPath string) (*Organization, error) {
+	// Validate fields
+	if !IsValidPath(newPath) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: new path %v", newPath),
+		}
+	}
+
+	// Call repo to retrieve the old organization
+	oldOrg, err := api.GetOrganizationByName(requestInfo, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check restrictions
+	orgsFiltered, err := api.GetAuthorizedOrganizations(requestInfo, oldOrg.Urn, ORGANIZATION_ACTION_UPDATE_ORGANIZATION, []Organization{*oldOrg})
+	if err != nil {
+		return nil, err
+	}
+	if len(orgsFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, oldOrg.Urn),
+		}
+	}
+
+	auxOrg := Organization{
+		Urn: CreateUrn("", RESOURCE_ORGANIZATION, newPath, name),
+	}
+
+	// Check restrictions
+	orgsFiltered, err = api.GetAuthorizedOrganizations(requestInfo, auxOrg.Urn, ORGANIZATION_ACTION_UPDATE_ORGANIZATION, []Organization{auxOrg})
+	if err != nil {
+		return nil, err
+	}
+	if len(orgsFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, auxOrg.Urn),
+		}
+	}
+
+	org := Organization{
+		ID:       oldOrg.ID,
+		Name:     oldOrg.Name,
+		Path:     newPath,
+		Urn:      auxOrg.Urn,
+		CreateAt: oldOrg.CreateAt,
+		UpdateAt: time.Now().UTC(),
+	}
+
+	// Update organization
+	updatedOrg, err := api.OrganizationRepo.UpdateOrganization(org)
+
+	// Check unexpected DB error
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Organization updated from %+v to %+v", oldOrg, updatedOrg))
+	api.emitEvent(requestInfo, ORGANIZATION_EVENT_UPDATED, updatedOrg, nil)
+	return updatedOrg, nil
+}
+
+func (api WorkerAPI) RemoveOrganization(requestInfo RequestInfo, name string) error {
+	// Call repo to retrieve the organization
+	org, err := api.GetOrganizationByName(requestInfo, name)
+	if err != nil {
+		return err
+	}
+
+	// Check restrictions
+	orgsFiltered, err := api.GetAuthorizedOrganizations(requestInfo, org.Urn, ORGANIZATION_ACTION_DELETE_ORGANIZATION, []Organization{*org})
+	if err != nil {
+		return err
+	}
+	if len(orgsFiltered) < 1 {
+		return &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, org.Urn),
+		}
+	}
+
+	err = api.OrganizationRepo.RemoveOrganization(org.ID)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Organization deleted %v", org))
+	api.emitEvent(requestInfo, ORGANIZATION_EVENT_DELETED, org, nil)
+	return nil
+}
+
+// PRIVATE HELPER METHODS
+
+func createOrganization(name string, path string) Organization {
+	urn := CreateUrn("", RESOURCE_ORGANIZATION, path, name)
+	org := Organization{
+		ID:       uuid.NewV4().String(),
+		Name:     name,
+		Path:     path,
+		CreateAt: time.Now().UTC(),
+		UpdateAt: time.Now().UTC(),
+		Urn:      urn,
+	}
+
+	return org
+}
+
+// checkOrganizationExists fails if there is no organization with the given name. Resources
+// can only be created inside an existing organization.
+func (api WorkerAPI) checkOrganizationExists(name string) error {
+	_, err := api.OrganizationRepo.GetOrganizationByName(name)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		if dbError.Code == database.ORGANIZATION_NOT_FOUND {
+			return &Error{
+				Code:    ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,775 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerAPI_AddOrganization(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		name        string
+		path        string
+		// Expected results
+		expectedOrganization *Organization
+		wantError            error
+		// Manager Results
+		getUserByExternalIDResult      *User
+		getGroupsByUserIDResult        []TestUserGroupRelation
+		getAttachedPoliciesResult      []TestPolicyGroupRelation
+		getOrganizationByNameResult    *Organization
+		addOrganizationMethodResult    *Organization
+		getOrganizationByNameMethodErr error
+		addOrganizationMethodErr       error
+	}{
+		"OKCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			path: "/path/",
+			getOrganizationByNameMethodErr: &database.Error{
+				Code: database.ORGANIZATION_NOT_FOUND,
+			},
+			addOrganizationMethodResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			expectedOrganization: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+		},
+		"ErrorCaseOrganizationAlreadyExists": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			path: "/path/",
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			wantError: &Error{
+				Code:    ORGANIZATION_ALREADY_EXIST,
+				Message: "Unable to create organization, organization with name org1 already exist",
+			},
+		},
+		"ErrorCaseBadName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "**!^#~",
+			path: "/path/",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name **!^#~",
+			},
+		},
+		"ErrorCaseBadPath": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			path: "/**!^#~path/",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: path /**!^#~path/",
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			name: "org1",
+			path: "/path/",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::org/path/org1",
+			},
+		},
+		"ErrorCaseDenyResource": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			name: "org1",
+			path: "/path/",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									ORGANIZATION_ACTION_CREATE_ORGANIZATION,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_ORGANIZATION, "/"),
+								},
+							},
+							{
+								Effect: "deny",
+								Actions: []string{
+									ORGANIZATION_ACTION_CREATE_ORGANIZATION,
+								},
+								Resources: []string{
+									CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::org/path/org1",
+			},
+		},
+		"ErrorCaseAddOrganizationDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			path: "/path/",
+			getOrganizationByNameMethodErr: &database.Error{
+				Code: database.ORGANIZATION_NOT_FOUND,
+			},
+			addOrganizationMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+		"ErrorCaseGetOrganizationDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			path: "/path/",
+			getOrganizationByNameMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetOrganizationByNameMethod][0] = testcase.getOrganizationByNameResult
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = testcase.getOrganizationByNameMethodErr
+		testRepo.ArgsOut[AddOrganizationMethod][0] = testcase.addOrganizationMethodResult
+		testRepo.ArgsOut[AddOrganizationMethod][1] = testcase.addOrganizationMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+
+		org, err := testAPI.AddOrganization(testcase.requestInfo, testcase.name, testcase.path)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedOrganization, org)
+	}
+}
+
+func TestWorkerAPI_GetOrganizationByName(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		name        string
+		// Expected results
+		wantError error
+		// Manager Results
+		getUserByExternalIDResult      *User
+		getOrganizationByNameResult    *Organization
+		getOrganizationByNameMethodErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+		},
+		"ErrorCaseBadName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "**!^#~",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name **!^#~",
+			},
+		},
+		"ErrorCaseOrganizationNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			getOrganizationByNameMethodErr: &database.Error{
+				Code:    database.ORGANIZATION_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+			wantError: &Error{
+				Code:    ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+		},
+		"ErrorCaseGetOrganizationDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			getOrganizationByNameMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			name: "org1",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::org/path/org1",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetOrganizationByNameMethod][0] = testcase.getOrganizationByNameResult
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = testcase.getOrganizationByNameMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+
+		org, err := testAPI.GetOrganizationByName(testcase.requestInfo, testcase.name)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.getOrganizationByNameResult, org)
+	}
+}
+
+func TestWorkerAPI_ListOrganizations(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedOrganizations []string
+		totalResult           int
+		wantError             error
+		// Manager Results
+		getUserByExternalIDResult         *User
+		getGroupsByUserIDResult           []TestUserGroupRelation
+		getAttachedPoliciesResult         []TestPolicyGroupRelation
+		getOrganizationsFilteredResult    []Organization
+		getOrganizationsFilteredMethodErr error
+	}{
+		"OkCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &testFilter,
+			expectedOrganizations: []string{
+				"org1",
+				"org2",
+			},
+			totalResult: 2,
+			getOrganizationsFilteredResult: []Organization{
+				{
+					ID:   "ORG-ID-1",
+					Name: "org1",
+					Path: "/path/",
+					Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+				},
+				{
+					ID:   "ORG-ID-2",
+					Name: "org2",
+					Path: "/path2/",
+					Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path2/", "org2"),
+				},
+			},
+		},
+		"OkCaseUser": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			filter: &testFilter,
+			expectedOrganizations: []string{
+				"org1",
+			},
+			totalResult: 2,
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									ORGANIZATION_ACTION_LIST_ORGANIZATIONS,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_ORGANIZATION, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+			getOrganizationsFilteredResult: []Organization{
+				{
+					ID:   "ORG-ID-1",
+					Name: "org1",
+					Path: "/path/",
+					Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+				},
+				{
+					ID:   "ORG-ID-2",
+					Name: "org2",
+					Path: "/path2/",
+					Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path2/", "org2"),
+				},
+			},
+		},
+		"ErrorCaseInvalidOrderBy": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				OrderBy: "invalid",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: OrderBy invalid",
+			},
+		},
+		"ErrorCaseGetOrganizationsDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &testFilter,
+			getOrganizationsFilteredMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetOrganizationsFilteredMethod][0] = testcase.getOrganizationsFilteredResult
+		testRepo.ArgsOut[GetOrganizationsFilteredMethod][1] = testcase.totalResult
+		testRepo.ArgsOut[GetOrganizationsFilteredMethod][2] = testcase.getOrganizationsFilteredMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+
+		orgs, total, err := testAPI.ListOrganizations(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedOrganizations, orgs)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWorkerAPI_UpdateOrganization(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		name        string
+		newPath     string
+		// Expected results
+		expectedOrganization *Organization
+		wantError            error
+		// Manager Results
+		getUserByExternalIDResult      *User
+		getGroupsByUserIDResult        []TestUserGroupRelation
+		getAttachedPoliciesResult      []TestPolicyGroupRelation
+		getOrganizationByNameResult    *Organization
+		getOrganizationByNameMethodErr error
+		updateOrganizationMethodErr    error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:    "org1",
+			newPath: "/newpath/",
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			expectedOrganization: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/newpath/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/newpath/", "org1"),
+			},
+		},
+		"ErrorCaseBadPath": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:    "org1",
+			newPath: "/**!^#~path/",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: new path /**!^#~path/",
+			},
+		},
+		"ErrorCaseOrganizationNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:    "org1",
+			newPath: "/newpath/",
+			getOrganizationByNameMethodErr: &database.Error{
+				Code:    database.ORGANIZATION_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+			wantError: &Error{
+				Code:    ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+		},
+		"ErrorCaseDenyNewPath": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			name:    "org1",
+			newPath: "/newpath/",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									ORGANIZATION_ACTION_GET_ORGANIZATION,
+									ORGANIZATION_ACTION_UPDATE_ORGANIZATION,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_ORGANIZATION, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::org/newpath/org1",
+			},
+		},
+		"ErrorCaseUpdateOrganizationDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:    "org1",
+			newPath: "/newpath/",
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			updateOrganizationMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetOrganizationByNameMethod][0] = testcase.getOrganizationByNameResult
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = testcase.getOrganizationByNameMethodErr
+		testRepo.ArgsOut[UpdateOrganizationMethod][0] = testcase.expectedOrganization
+		testRepo.ArgsOut[UpdateOrganizationMethod][1] = testcase.updateOrganizationMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+
+		org, err := testAPI.UpdateOrganization(testcase.requestInfo, testcase.name, testcase.newPath)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedOrganization, org)
+		if testcase.wantError == nil {
+			updated := testRepo.ArgsIn[UpdateOrganizationMethod][0].(Organization)
+			assert.Equal(t, testcase.expectedOrganization.Urn, updated.Urn, "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWorkerAPI_RemoveOrganization(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		name        string
+		// Expected results
+		wantError error
+		// Manager Results
+		getUserByExternalIDResult      *User
+		getGroupsByUserIDResult        []TestUserGroupRelation
+		getAttachedPoliciesResult      []TestPolicyGroupRelation
+		getOrganizationByNameResult    *Organization
+		getOrganizationByNameMethodErr error
+		removeOrganizationMethodErr    error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+		},
+		"ErrorCaseOrganizationNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			getOrganizationByNameMethodErr: &database.Error{
+				Code:    database.ORGANIZATION_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+			wantError: &Error{
+				Code:    ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+		},
+		"ErrorCaseNoDeletePermission": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			name: "org1",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									ORGANIZATION_ACTION_GET_ORGANIZATION,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_ORGANIZATION, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::org/path/org1",
+			},
+		},
+		"ErrorCaseRemoveOrganizationDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "org1",
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			removeOrganizationMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetOrganizationByNameMethod][0] = testcase.getOrganizationByNameResult
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = testcase.getOrganizationByNameMethodErr
+		testRepo.ArgsOut[RemoveOrganizationMethod][0] = testcase.removeOrganizationMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+
+		err := testAPI.RemoveOrganization(testcase.requestInfo, testcase.name)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.getOrganizationByNameResult.ID, testRepo.ArgsIn[RemoveOrganizationMethod][0], "Error in test case %v", x)
+		}
+	}
+}
@@ -19,12 +19,14 @@ type Policy struct {
 	Urn        string       `json:"urn,omitempty"`
 	CreateAt   time.Time    `json:"createAt,omitempty"`
 	UpdateAt   time.Time    `json:"updateAt,omitempty"`
+	Version    int          `json:"version,omitempty"`
 	Statements *[]Statement `json:"statements,omitempty"`
+	Revision   int          `json:"revision,omitempty"`
 }
 
 func (p Policy) String() string {
-	return fmt.Sprintf("[id: %v, name: %v, path: %v, org: %v, urn: %v, createAt: %v, statements: %v]",
-		p.ID, p.Name, p.Path, p.Org, p.Urn, p.CreateAt.Format("2006-01-02 15:04:05 MST"), p.Statements)
+	return fmt.Sprintf("[id: %v, name: %v, path: %v, org: %v, urn: %v, createAt: %v, version: %v, statements: %v]",
+		p.ID, p.Name, p.Path, p.Org, p.Urn, p.CreateAt.Format("2006-01-02 15:04:05 MST"), p.Version, p.Statements)
 }
 
 func (p Policy) GetUrn() string {
@@ -38,9 +40,34 @@ type PolicyIdentity struct {
 }
 
 type Statement struct {
-	Effect    string   `json:"effect,omitempty"`
-	Actions   []string `json:"actions,omitempty"`
-	Resources []string `json:"resources,omitempty"`
+	Effect       string   `json:"effect,omitempty" yaml:"effect,omitempty"`
+	Actions      []string `json:"actions,omitempty" yaml:"actions,omitempty"`
+	NotActions   []string `json:"notActions,omitempty" yaml:"notActions,omitempty"`
+	Resources    []string `json:"resources,omitempty" yaml:"resources,omitempty"`
+	NotResources []string `json:"notResources,omitempty" yaml:"notResources,omitempty"`
+}
+
+// Policy version domain. Versions are immutable, every policy update creates a new one
+type PolicyVersion struct {
+	ID         string       `json:"id,omitempty"`
+	Version    int          `json:"version,omitempty"`
+	Author     string       `json:"author,omitempty"`
+	CreateAt   time.Time    `json:"createAt,omitempty"`
+	Default    bool         `json:"default"`
+	Statements *[]Statement `json:"statements,omitempty"`
+}
+
+func (pv PolicyVersion) String() string {
+	return fmt.Sprintf("[id: %v, version: %v, author: %v, createAt: %v, default: %v, statements: %v]",
+		pv.ID, pv.Version, pv.Author, pv.CreateAt.Format("2006-01-02 15:04:05 MST"), pv.Default, pv.Statements)
+}
+
+// Statements added and removed from one policy version to another
+type PolicyVersionDiff struct {
+	FromVersion       int         `json:"fromVersion,omitempty"`
+	ToVersion         int         `json:"toVersion,omitempty"`
+	AddedStatements   []Statement `json:"addedStatements"`
+	RemovedStatements []Statement `json:"removedStatements"`
 }
 
 type PolicyGroups struct {
@@ -55,6 +82,18 @@ func (s Statement) String() string {
 // POLICY API IMPLEMENTATION
 
 func (api WorkerAPI) AddPolicy(requestInfo RequestInfo, name string, path string, org string, statements []Statement) (*Policy, error) {
+	var policy *Policy
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		policy, err = txAPI.addPolicy(requestInfo, name, path, org, statements)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (api WorkerAPI) addPolicy(requestInfo RequestInfo, name string, path string, org string, statements []Statement) (*Policy, error) {
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -100,6 +139,11 @@ func (api WorkerAPI) AddPolicy(requestInfo RequestInfo, name string, path string
 		}
 	}
 
+	// Check if organization exists
+	if err := api.checkOrganizationExists(org); err != nil {
+		return nil, err
+	}
+
 	// Check if policy already exists
 	_, err = api.PolicyRepo.GetPolicyByName(org, name)
 
@@ -111,19 +155,15 @@ func (api WorkerAPI) AddPolicy(requestInfo RequestInfo, name string, path string
 		// Policy doesn't exist in DB
 		case database.POLICY_NOT_FOUND:
 			// Create policy
-			createdPolicy, err := api.PolicyRepo.AddPolicy(policy)
+			createdPolicy, err := api.PolicyRepo.AddPolicy(policy, requestInfo.Identifier)
 
 			// Check if there is an unexpected error in DB
 			if err != nil {
-				//Transform to DB error
-				dbError := err.(*database.Error)
-				return nil, &Error{
-					Code:    UNKNOWN_API_ERROR,
-					Message: dbError.Message,
-				}
+				return nil, storeError(err)
 			}
 
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy created %+v", createdPolicy))
+			api.emitEvent(requestInfo, POLICY_EVENT_CREATED, createdPolicy, nil)
 			return createdPolicy, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -192,13 +232,17 @@ func (api WorkerAPI) GetPolicyByName(requestInfo RequestInfo, org string, policy
 	}
 }
 
-func (api WorkerAPI) ListPolicies(requestInfo RequestInfo, filter *Filter) ([]PolicyIdentity, int, error) {
+func (api WorkerAPI) ListPolicies(requestInfo RequestInfo, filter *Filter) ([]PolicyIdentity, int, string, error) {
 	// Validate fields
 	var total int
 	orderByValidColumns := api.PolicyRepo.OrderByValidColumns(POLICY_ACTION_LIST_POLICIES)
 	err := validateFilter(filter, orderByValidColumns)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
+	}
+	err = validateNextToken(filter)
+	if err != nil {
+		return nil, total, "", err
 	}
 
 	// Call repo to retrieve the policies
@@ -208,12 +252,18 @@ func (api WorkerAPI) ListPolicies(requestInfo RequestInfo, filter *Filter) ([]Po
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return nil, total, &Error{
+		return nil, total, "", &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
 
+	var nextToken string
+	if len(policies) > 0 {
+		last := policies[len(policies)-1]
+		nextToken = nextPageToken(filter, len(policies), last.CreateAt, last.ID)
+	}
+
 	// Check restrictions to list
 	var urnPrefix string
 	if len(filter.Org) == 0 {
@@ -223,7 +273,7 @@ func (api WorkerAPI) ListPolicies(requestInfo RequestInfo, filter *Filter) ([]Po
 	}
 	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, urnPrefix, POLICY_ACTION_LIST_POLICIES, policies)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
 	}
 
 	policyIDs := []PolicyIdentity{}
@@ -234,7 +284,7 @@ func (api WorkerAPI) ListPolicies(requestInfo RequestInfo, filter *Filter) ([]Po
 		})
 	}
 
-	return policyIDs, total, nil
+	return policyIDs, total, nextToken, nil
 }
 
 func (api WorkerAPI) UpdatePolicy(requestInfo RequestInfo, org string, policyName string, newName string, newPath string,
@@ -282,6 +332,11 @@ func (api WorkerAPI) UpdatePolicy(requestInfo RequestInfo, org string, policyNam
 		}
 	}
 
+	// Check revision required by request
+	if err := checkRevision(requestInfo, oldPolicy.Revision); err != nil {
+		return nil, err
+	}
+
 	// Check if policy with "newName" exists
 	targetPolicy, err := api.GetPolicyByName(requestInfo, org, newName)
 
@@ -324,23 +379,21 @@ func (api WorkerAPI) UpdatePolicy(requestInfo RequestInfo, org string, policyNam
 		Urn:        auxPolicy.Urn,
 		CreateAt:   oldPolicy.CreateAt,
 		UpdateAt:   time.Now().UTC(),
+		Version:    oldPolicy.Version,
 		Statements: &newStatements,
+		Revision:   oldPolicy.Revision,
 	}
 
-	// Update policy
-	updatedPolicy, err := api.PolicyRepo.UpdatePolicy(policy)
+	// Update policy, creating a new version that becomes the default one
+	updatedPolicy, err := api.PolicyRepo.UpdatePolicy(policy, requestInfo.Identifier)
 
 	// Check unexpected DB error
 	if err != nil {
-		//Transform to DB error
-		dbError := err.(*database.Error)
-		return nil, &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
+		return nil, revisionError(err)
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy updated from %+v to %+v", oldPolicy, updatedPolicy))
+	api.emitEvent(requestInfo, POLICY_EVENT_UPDATED, updatedPolicy, nil)
 	return updatedPolicy, nil
 }
 
@@ -365,42 +418,133 @@ func (api WorkerAPI) RemovePolicy(requestInfo RequestInfo, org string, name stri
 		}
 	}
 
-	err = api.PolicyRepo.RemovePolicy(policy.ID)
+	// Check revision required by request
+	if err := checkRevision(requestInfo, policy.Revision); err != nil {
+		return err
+	}
+
+	err = api.PolicyRepo.RemovePolicy(policy.ID, policy.Revision)
+	if err != nil {
+		return revisionError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy deleted %+v", policy))
+	api.emitEvent(requestInfo, POLICY_EVENT_DELETED, policy, nil)
+	return nil
+}
+
+func (api WorkerAPI) RestorePolicy(requestInfo RequestInfo, org string, name string) (*Policy, error) {
+	var policy *Policy
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		policy, err = txAPI.restorePolicy(requestInfo, org, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (api WorkerAPI) restorePolicy(requestInfo RequestInfo, org string, name string) (*Policy, error) {
+	// Validate fields
+	if !IsValidName(name) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+	// Validate org
+	if !IsValidOrg(org) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: org %v", org),
+		}
+	}
+
+	// Check if another policy with the same name was created after deletion
+	_, err := api.PolicyRepo.GetPolicyByName(org, name)
+	if err == nil {
+		return nil, &Error{
+			Code:    POLICY_ALREADY_EXIST,
+			Message: fmt.Sprintf("Unable to restore policy, policy with org %v and name %v already exist", org, name),
+		}
+	}
+	if dbError := err.(*database.Error); dbError.Code != database.POLICY_NOT_FOUND {
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Call repo to retrieve the deleted policy
+	policy, err := api.PolicyRepo.GetDeletedPolicyByName(org, name)
+
+	// Error handling
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return &Error{
+		// Deleted policy doesn't exist in DB
+		if dbError.Code == database.POLICY_NOT_FOUND {
+			return nil, &Error{
+				Code:    POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return nil, &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
 
-	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy deleted %+v", policy))
-	return nil
+	// Check restrictions
+	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, policy.Urn, POLICY_ACTION_RESTORE_POLICY, []Policy{*policy})
+	if err != nil {
+		return nil, err
+	}
+	if len(policiesFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, policy.Urn),
+		}
+	}
+
+	err = api.PolicyRepo.RestorePolicy(policy.ID)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy restored %+v", policy))
+	api.emitEvent(requestInfo, POLICY_EVENT_RESTORED, policy, nil)
+	return policy, nil
 }
 
-func (api WorkerAPI) ListAttachedGroups(requestInfo RequestInfo, filter *Filter) ([]PolicyGroups, int, error) {
+func (api WorkerAPI) ListAttachedGroups(requestInfo RequestInfo, filter *Filter) ([]PolicyGroups, int, string, error) {
 	// Validate fields
 	var total int
 	orderByValidColumns := api.UserRepo.OrderByValidColumns(POLICY_ACTION_LIST_ATTACHED_GROUPS)
 	err := validateFilter(filter, orderByValidColumns)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
+	}
+	err = validateNextToken(filter)
+	if err != nil {
+		return nil, total, "", err
 	}
 
 	// Call repo to retrieve the policy
 	policy, err := api.GetPolicyByName(requestInfo, filter.Org, filter.PolicyName)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
 	}
 
 	// Check restrictions
 	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, policy.Urn, POLICY_ACTION_LIST_ATTACHED_GROUPS, []Policy{*policy})
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
 	}
 	if len(policiesFiltered) < 1 {
-		return nil, total, &Error{
+		return nil, total, "", &Error{
 			Code: UNAUTHORIZED_RESOURCES_ERROR,
 			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
 				requestInfo.Identifier, policy.Urn),
@@ -414,12 +558,18 @@ func (api WorkerAPI) ListAttachedGroups(requestInfo RequestInfo, filter *Filter)
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return nil, total, &Error{
+		return nil, total, "", &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
 
+	var nextToken string
+	if len(attachedGroups) > 0 {
+		last := attachedGroups[len(attachedGroups)-1]
+		nextToken = nextPageToken(filter, len(attachedGroups), last.GetDate(), last.GetGroup().ID)
+	}
+
 	groups := []PolicyGroups{}
 	if attachedGroups != nil {
 		groups = make([]PolicyGroups, len(attachedGroups), cap(attachedGroups))
@@ -431,11 +581,228 @@ func (api WorkerAPI) ListAttachedGroups(requestInfo RequestInfo, filter *Filter)
 		}
 	}
 
-	return groups, total, nil
+	return groups, total, nextToken, nil
+}
+
+func (api WorkerAPI) ListPolicyVersions(requestInfo RequestInfo, filter *Filter) ([]PolicyVersion, int, error) {
+	// Validate fields
+	var total int
+	orderByValidColumns := api.PolicyRepo.OrderByValidColumns(POLICY_ACTION_LIST_POLICY_VERSIONS)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the policy
+	policy, err := api.GetPolicyByName(requestInfo, filter.Org, filter.PolicyName)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Check restrictions
+	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, policy.Urn, POLICY_ACTION_LIST_POLICY_VERSIONS, []Policy{*policy})
+	if err != nil {
+		return nil, total, err
+	}
+	if len(policiesFiltered) < 1 {
+		return nil, total, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, policy.Urn),
+		}
+	}
+
+	// Call repo to retrieve the policy versions
+	versions, total, err := api.PolicyRepo.GetPolicyVersions(policy.ID, filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	policyVersions := []PolicyVersion{}
+	for _, v := range versions {
+		v.Default = v.Version == policy.Version
+		policyVersions = append(policyVersions, v)
+	}
+
+	return policyVersions, total, nil
+}
+
+func (api WorkerAPI) GetPolicyVersion(requestInfo RequestInfo, org string, policyName string, version int) (*PolicyVersion, error) {
+	// Call repo to retrieve the policy
+	policy, err := api.GetPolicyByName(requestInfo, org, policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check restrictions
+	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, policy.Urn, POLICY_ACTION_GET_POLICY_VERSION, []Policy{*policy})
+	if err != nil {
+		return nil, err
+	}
+	if len(policiesFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, policy.Urn),
+		}
+	}
+
+	return api.getPolicyVersion(policy, version)
+}
+
+func (api WorkerAPI) DiffPolicyVersions(requestInfo RequestInfo, org string, policyName string, fromVersion int,
+	toVersion int) (*PolicyVersionDiff, error) {
+	// Call repo to retrieve the policy
+	policy, err := api.GetPolicyByName(requestInfo, org, policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check restrictions
+	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, policy.Urn, POLICY_ACTION_GET_POLICY_VERSION, []Policy{*policy})
+	if err != nil {
+		return nil, err
+	}
+	if len(policiesFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, policy.Urn),
+		}
+	}
+
+	from, err := api.getPolicyVersion(policy, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.getPolicyVersion(policy, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PolicyVersionDiff{
+		FromVersion:       from.Version,
+		ToVersion:         to.Version,
+		AddedStatements:   statementsNotContained(*to.Statements, *from.Statements),
+		RemovedStatements: statementsNotContained(*from.Statements, *to.Statements),
+	}, nil
+}
+
+func (api WorkerAPI) SetDefaultPolicyVersion(requestInfo RequestInfo, org string, policyName string, version int) (*Policy, error) {
+	// Call repo to retrieve the policy
+	policy, err := api.GetPolicyByName(requestInfo, org, policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check restrictions
+	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, policy.Urn, POLICY_ACTION_SET_DEFAULT_POLICY_VERSION, []Policy{*policy})
+	if err != nil {
+		return nil, err
+	}
+	if len(policiesFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, policy.Urn),
+		}
+	}
+
+	// Check that version exists
+	if _, err := api.getPolicyVersion(policy, version); err != nil {
+		return nil, err
+	}
+
+	if err := checkRevision(requestInfo, policy.Revision); err != nil {
+		return nil, err
+	}
+
+	updatedPolicy, err := api.PolicyRepo.SetDefaultPolicyVersion(policy.ID, version, policy.Revision, time.Now().UTC())
+	if err != nil {
+		return nil, revisionError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier,
+		fmt.Sprintf("Policy %v default version changed from %v to %v", policy.Urn, policy.Version, version))
+	api.emitEvent(requestInfo, POLICY_EVENT_DEFAULT_VERSION_SET, updatedPolicy, nil)
+	return updatedPolicy, nil
 }
 
 // PRIVATE HELPER METHODS
 
+func (api WorkerAPI) getPolicyVersion(policy *Policy, version int) (*PolicyVersion, error) {
+	if version < 1 {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: version %v", version),
+		}
+	}
+
+	policyVersion, err := api.PolicyRepo.GetPolicyVersion(policy.ID, version)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		if dbError.Code == database.POLICY_VERSION_NOT_FOUND {
+			return nil, &Error{
+				Code:    POLICY_VERSION_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	policyVersion.Default = policyVersion.Version == policy.Version
+
+	return policyVersion, nil
+}
+
+// statementsNotContained returns statements in source that don't appear in target
+func statementsNotContained(source []Statement, target []Statement) []Statement {
+	statements := []Statement{}
+	for _, s := range source {
+		found := false
+		for _, t := range target {
+			if isEqualStatement(s, t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			statements = append(statements, s)
+		}
+	}
+	return statements
+}
+
+func isEqualStatement(s1 Statement, s2 Statement) bool {
+	return s1.Effect == s2.Effect && isEqualStringArray(s1.Actions, s2.Actions) &&
+		isEqualStringArray(s1.NotActions, s2.NotActions) && isEqualStringArray(s1.Resources, s2.Resources) &&
+		isEqualStringArray(s1.NotResources, s2.NotResources)
+}
+
+func isEqualStringArray(a1 []string, a2 []string) bool {
+	if len(a1) != len(a2) {
+		return false
+	}
+	for i := range a1 {
+		if a1[i] != a2[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func createPolicy(name string, path string, org string, statements *[]Statement) Policy {
 	urn := CreateUrn(org, RESOURCE_POLICY, path, name)
 	policy := Policy{
@@ -446,6 +813,7 @@ func createPolicy(name string, path string, org string, statements *[]Statement)
 		UpdateAt:   time.Now().UTC(),
 		Org:        org,
 		Urn:        urn,
+		Version:    1,
 		Statements: statements,
 	}
 
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Tecsisa/foulkon/database"
 	"github.com/stretchr/testify/assert"
@@ -23,8 +25,9 @@ func TestAuthAPI_AddPolicy(t *testing.T) {
 		getPolicyByNameMethodResult *Policy
 		wantError                   error
 
-		getPolicyByNameMethodErr error
-		addPolicyMethodErr       error
+		getPolicyByNameMethodErr       error
+		addPolicyMethodErr             error
+		getOrganizationByNameMethodErr error
 	}{
 		"OKCase": {
 			requestInfo: RequestInfo{
@@ -427,6 +430,34 @@ func TestAuthAPI_AddPolicy(t *testing.T) {
 				Code: UNKNOWN_API_ERROR,
 			},
 		},
+		"ErrorCaseOrganizationNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:        "123",
+			policyName: "test",
+			path:       "/path/",
+			statements: []Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						USER_ACTION_GET_USER,
+					},
+					Resources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/path/"),
+					},
+				},
+			},
+			getOrganizationByNameMethodErr: &database.Error{
+				Code:    database.ORGANIZATION_NOT_FOUND,
+				Message: "Organization with name 123 not found",
+			},
+			wantError: &Error{
+				Code:    ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization with name 123 not found",
+			},
+		},
 	}
 
 	testRepo := makeTestRepo()
@@ -440,6 +471,7 @@ func TestAuthAPI_AddPolicy(t *testing.T) {
 		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = testcase.getOrganizationByNameMethodErr
 		policy, err := testAPI.AddPolicy(testcase.requestInfo, testcase.policyName, testcase.path, testcase.org, testcase.statements)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.addPolicyMethodResult, policy)
 	}
@@ -997,7 +1029,7 @@ func TestAuthAPI_ListPolicies(t *testing.T) {
 		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDErr
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
-		policies, total, err := testAPI.ListPolicies(testcase.requestInfo, testcase.filter)
+		policies, total, _, err := testAPI.ListPolicies(testcase.requestInfo, testcase.filter)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedPolicies, policies)
 		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
 	}
@@ -2636,6 +2668,290 @@ func TestAuthAPI_RemovePolicy(t *testing.T) {
 	}
 }
 
+func TestAuthAPI_RestorePolicy(t *testing.T) {
+	testcases := map[string]struct {
+		//API method args
+		requestInfo RequestInfo
+		name        string
+		org         string
+		// Expected result
+		expectedPolicy *Policy
+		wantError      error
+		// Manager Results
+		getUserByExternalIDResult          *User
+		getGroupsByUserIDResult            []TestUserGroupRelation
+		getAttachedPoliciesResult          []TestPolicyGroupRelation
+		getPolicyByNameMethodResult        *Policy
+		getDeletedPolicyByNameMethodResult *Policy
+		// API Errors
+		getPolicyByNameMethodErr        error
+		getDeletedPolicyByNameMethodErr error
+		restorePolicyMethodErr          error
+	}{
+		"OKCaseAdminUser": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "policy1",
+			org:  "org1",
+			expectedPolicy: &Policy{
+				ID:   "543210",
+				Name: "policy1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policy1"),
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code: database.POLICY_NOT_FOUND,
+			},
+			getDeletedPolicyByNameMethodResult: &Policy{
+				ID:   "543210",
+				Name: "policy1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policy1"),
+			},
+		},
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			name: "policy1",
+			org:  "org1",
+			expectedPolicy: &Policy{
+				ID:   "543210",
+				Name: "policy1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policy1"),
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code: database.POLICY_NOT_FOUND,
+			},
+			getDeletedPolicyByNameMethodResult: &Policy{
+				ID:   "543210",
+				Name: "policy1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policy1"),
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "123456",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/example/",
+						Org:  "org1",
+						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "org1",
+						Path: "/example/",
+						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									POLICY_ACTION_RESTORE_POLICY,
+								},
+								Resources: []string{
+									GetUrnPrefix("org1", RESOURCE_POLICY, ""),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseInvalidName": {
+			name: "invalid*",
+			org:  "org1",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name invalid*",
+			},
+		},
+		"ErrorCaseInvalidOrg": {
+			name: "n1",
+			org:  "**^!$%&",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: org **^!$%&",
+			},
+		},
+		"ErrorCasePolicyAlreadyExist": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "policy1",
+			org:  "org1",
+			wantError: &Error{
+				Code:    POLICY_ALREADY_EXIST,
+				Message: "Unable to restore policy, policy with org org1 and name policy1 already exist",
+			},
+			getPolicyByNameMethodResult: &Policy{
+				ID:   "654321",
+				Name: "policy1",
+				Org:  "org1",
+				Path: "/example/",
+			},
+		},
+		"ErrorCaseGetPolicyDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "policy1",
+			org:  "org1",
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseDeletedPolicyNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "policy1",
+			org:  "org1",
+			wantError: &Error{
+				Code:    POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Deleted policy with organization org1 and name policy1 not found",
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code: database.POLICY_NOT_FOUND,
+			},
+			getDeletedPolicyByNameMethodErr: &database.Error{
+				Code:    database.POLICY_NOT_FOUND,
+				Message: "Deleted policy with organization org1 and name policy1 not found",
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			name: "policy1",
+			org:  "org1",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:policy/example/policy1",
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code: database.POLICY_NOT_FOUND,
+			},
+			getDeletedPolicyByNameMethodResult: &Policy{
+				ID:   "543210",
+				Name: "policy1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policy1"),
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "123456",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/example/",
+						Org:  "org1",
+						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/example/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "org1",
+						Path: "/example/",
+						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									POLICY_ACTION_DELETE_POLICY,
+								},
+								Resources: []string{
+									GetUrnPrefix("org1", RESOURCE_POLICY, ""),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseRestorePolicyDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "policy1",
+			org:  "org1",
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code: database.POLICY_NOT_FOUND,
+			},
+			getDeletedPolicyByNameMethodResult: &Policy{
+				ID:   "543210",
+				Name: "policy1",
+				Org:  "org1",
+				Path: "/example/",
+				Urn:  CreateUrn("org1", RESOURCE_POLICY, "/example/", "policy1"),
+			},
+			restorePolicyMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		testRepo.ArgsOut[GetPolicyByNameMethod][0] = testcase.getPolicyByNameMethodResult
+		testRepo.ArgsOut[GetPolicyByNameMethod][1] = testcase.getPolicyByNameMethodErr
+		testRepo.ArgsOut[GetDeletedPolicyByNameMethod][0] = testcase.getDeletedPolicyByNameMethodResult
+		testRepo.ArgsOut[GetDeletedPolicyByNameMethod][1] = testcase.getDeletedPolicyByNameMethodErr
+		testRepo.ArgsOut[RestorePolicyMethod][0] = testcase.restorePolicyMethodErr
+		policy, err := testAPI.RestorePolicy(testcase.requestInfo, testcase.org, testcase.name)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedPolicy, policy)
+	}
+}
+
 func TestAuthAPI_ListAttachedGroups(t *testing.T) {
 	testcases := map[string]struct {
 		// API Method args
@@ -2959,8 +3275,506 @@ func TestAuthAPI_ListAttachedGroups(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedGroupsMethod][0] = testcase.getAttachedGroupsResult
 		testRepo.ArgsOut[GetAttachedGroupsMethod][1] = testcase.totalResult
 		testRepo.ArgsOut[GetAttachedGroupsMethod][2] = testcase.getAttachedGroupsErr
-		groups, total, err := testAPI.ListAttachedGroups(testcase.requestInfo, testcase.filter)
+		groups, total, _, err := testAPI.ListAttachedGroups(testcase.requestInfo, testcase.filter)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedGroups, groups)
 		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
 	}
 }
+
+func TestAuthAPI_ListPolicyVersions(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedVersions []PolicyVersion
+		totalResult      int
+		wantError        error
+		// Manager Results
+		getPolicyByNameMethodResult *Policy
+		getPolicyVersionsResult     []PolicyVersion
+		// Manager Errors
+		getPolicyByNameMethodErr error
+		getPolicyVersionsErr     error
+	}{
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Org:        "example",
+				PolicyName: "test",
+			},
+			getPolicyByNameMethodResult: &Policy{
+				ID:      "test1",
+				Name:    "test",
+				Org:     "example",
+				Path:    "/path/",
+				Urn:     CreateUrn("example", RESOURCE_POLICY, "/path/", "test"),
+				Version: 1,
+			},
+			getPolicyVersionsResult: []PolicyVersion{
+				{
+					ID:       "v2",
+					Version:  2,
+					Author:   "user2",
+					CreateAt: now,
+				},
+				{
+					ID:       "v1",
+					Version:  1,
+					Author:   "user1",
+					CreateAt: now,
+				},
+			},
+			totalResult: 2,
+			expectedVersions: []PolicyVersion{
+				{
+					ID:       "v2",
+					Version:  2,
+					Author:   "user2",
+					CreateAt: now,
+				},
+				{
+					ID:       "v1",
+					Version:  1,
+					Author:   "user1",
+					CreateAt: now,
+					Default:  true,
+				},
+			},
+		},
+		"ErrorCaseInvalidName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Org:        "example",
+				PolicyName: "invalid*",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: policy invalid*",
+			},
+		},
+		"ErrorCasePolicyNotExist": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Org:        "example",
+				PolicyName: "test",
+			},
+			wantError: &Error{
+				Code: POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code: database.POLICY_NOT_FOUND,
+			},
+		},
+		"ErrorCaseGetPolicyVersionsFail": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Org:        "example",
+				PolicyName: "test",
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getPolicyByNameMethodResult: &Policy{
+				ID:   "test1",
+				Name: "test",
+				Org:  "example",
+				Path: "/path/",
+				Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "test"),
+			},
+			getPolicyVersionsErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetPolicyByNameMethod][0] = testcase.getPolicyByNameMethodResult
+		testRepo.ArgsOut[GetPolicyByNameMethod][1] = testcase.getPolicyByNameMethodErr
+		testRepo.ArgsOut[GetPolicyVersionsMethod][0] = testcase.getPolicyVersionsResult
+		testRepo.ArgsOut[GetPolicyVersionsMethod][1] = testcase.totalResult
+		testRepo.ArgsOut[GetPolicyVersionsMethod][2] = testcase.getPolicyVersionsErr
+		versions, total, err := testAPI.ListPolicyVersions(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedVersions, versions)
+		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+	}
+}
+
+func TestAuthAPI_GetPolicyVersion(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		org         string
+		policyName  string
+		version     int
+		// Expected result
+		expectedVersion *PolicyVersion
+		wantError       error
+		// Manager Results
+		getUserByExternalIDResult   *User
+		getGroupsByUserIDResult     []TestUserGroupRelation
+		getAttachedPoliciesResult   []TestPolicyGroupRelation
+		getPolicyByNameMethodResult *Policy
+		getPolicyVersionResult      *PolicyVersion
+		// Manager Errors
+		getPolicyVersionErr error
+	}{
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:        "example",
+			policyName: "test",
+			version:    2,
+			getPolicyByNameMethodResult: &Policy{
+				ID:      "test1",
+				Name:    "test",
+				Org:     "example",
+				Path:    "/path/",
+				Urn:     CreateUrn("example", RESOURCE_POLICY, "/path/", "test"),
+				Version: 2,
+			},
+			getPolicyVersionResult: &PolicyVersion{
+				ID:      "v2",
+				Version: 2,
+				Author:  "user2",
+			},
+			expectedVersion: &PolicyVersion{
+				ID:      "v2",
+				Version: 2,
+				Author:  "user2",
+				Default: true,
+			},
+		},
+		"ErrorCaseInvalidVersion": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:        "example",
+			policyName: "test",
+			version:    0,
+			getPolicyByNameMethodResult: &Policy{
+				ID:   "test1",
+				Name: "test",
+				Org:  "example",
+				Path: "/path/",
+				Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "test"),
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: version 0",
+			},
+		},
+		"ErrorCaseVersionNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:        "example",
+			policyName: "test",
+			version:    3,
+			getPolicyByNameMethodResult: &Policy{
+				ID:   "test1",
+				Name: "test",
+				Org:  "example",
+				Path: "/path/",
+				Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "test"),
+			},
+			getPolicyVersionErr: &database.Error{
+				Code:    database.POLICY_VERSION_NOT_FOUND,
+				Message: "Version 3 of policy with id test1 not found",
+			},
+			wantError: &Error{
+				Code:    POLICY_VERSION_NOT_FOUND,
+				Message: "Version 3 of policy with id test1 not found",
+			},
+		},
+		"ErrorCaseNotEnoughPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			org:        "example",
+			policyName: "test",
+			version:    1,
+			getPolicyByNameMethodResult: &Policy{
+				ID:   "test1",
+				Name: "test",
+				Org:  "example",
+				Path: "/path/",
+				Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "test"),
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									POLICY_ACTION_GET_POLICY,
+								},
+								Resources: []string{
+									GetUrnPrefix("example", RESOURCE_POLICY, "/"),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:example:policy/path/test",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		testRepo.ArgsOut[GetPolicyByNameMethod][0] = testcase.getPolicyByNameMethodResult
+		testRepo.ArgsOut[GetPolicyVersionMethod][0] = testcase.getPolicyVersionResult
+		testRepo.ArgsOut[GetPolicyVersionMethod][1] = testcase.getPolicyVersionErr
+		version, err := testAPI.GetPolicyVersion(testcase.requestInfo, testcase.org, testcase.policyName, testcase.version)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedVersion, version)
+	}
+}
+
+func TestAuthAPI_DiffPolicyVersions(t *testing.T) {
+	allowGetUser := Statement{
+		Effect:    "allow",
+		Actions:   []string{USER_ACTION_GET_USER},
+		Resources: []string{GetUrnPrefix("", RESOURCE_USER, "/path/")},
+	}
+	allowListUsers := Statement{
+		Effect:    "allow",
+		Actions:   []string{USER_ACTION_LIST_USERS},
+		Resources: []string{GetUrnPrefix("", RESOURCE_USER, "/path/")},
+	}
+	denyGetUser := Statement{
+		Effect:    "deny",
+		Actions:   []string{USER_ACTION_GET_USER},
+		Resources: []string{GetUrnPrefix("", RESOURCE_USER, "/path/")},
+	}
+	versions := map[int]*PolicyVersion{
+		1: {
+			ID:         "v1",
+			Version:    1,
+			Statements: &[]Statement{allowGetUser, allowListUsers},
+		},
+		2: {
+			ID:         "v2",
+			Version:    2,
+			Statements: &[]Statement{allowListUsers, denyGetUser},
+		},
+	}
+	testcases := map[string]struct {
+		// API Method args
+		fromVersion int
+		toVersion   int
+		// Expected result
+		expectedDiff *PolicyVersionDiff
+		wantError    error
+	}{
+		"OkCase": {
+			fromVersion: 1,
+			toVersion:   2,
+			expectedDiff: &PolicyVersionDiff{
+				FromVersion:       1,
+				ToVersion:         2,
+				AddedStatements:   []Statement{denyGetUser},
+				RemovedStatements: []Statement{allowGetUser},
+			},
+		},
+		"OkCaseSameVersion": {
+			fromVersion: 2,
+			toVersion:   2,
+			expectedDiff: &PolicyVersionDiff{
+				FromVersion:       2,
+				ToVersion:         2,
+				AddedStatements:   []Statement{},
+				RemovedStatements: []Statement{},
+			},
+		},
+		"ErrorCaseVersionNotFound": {
+			fromVersion: 1,
+			toVersion:   3,
+			wantError: &Error{
+				Code:    POLICY_VERSION_NOT_FOUND,
+				Message: "Version 3 not found",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetPolicyByNameMethod][0] = &Policy{
+			ID:      "test1",
+			Name:    "test",
+			Org:     "example",
+			Path:    "/path/",
+			Urn:     CreateUrn("example", RESOURCE_POLICY, "/path/", "test"),
+			Version: 2,
+		}
+		testRepo.SpecialFuncs[GetPolicyVersionMethod] = func(policyID string, version int) (*PolicyVersion, error) {
+			if v, ok := versions[version]; ok {
+				policyVersion := *v
+				return &policyVersion, nil
+			}
+			return nil, &database.Error{
+				Code:    database.POLICY_VERSION_NOT_FOUND,
+				Message: fmt.Sprintf("Version %v not found", version),
+			}
+		}
+		diff, err := testAPI.DiffPolicyVersions(RequestInfo{Identifier: "123456", Admin: true}, "example", "test",
+			testcase.fromVersion, testcase.toVersion)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedDiff, diff)
+	}
+}
+
+func TestAuthAPI_SetDefaultPolicyVersion(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		version  int
+		revision int
+		// Expected result
+		expectedPolicy *Policy
+		wantError      error
+		// Manager Results
+		getPolicyVersionResult        *PolicyVersion
+		setDefaultPolicyVersionResult *Policy
+		// Manager Errors
+		getPolicyVersionErr        error
+		setDefaultPolicyVersionErr error
+	}{
+		"OkCase": {
+			version: 1,
+			getPolicyVersionResult: &PolicyVersion{
+				ID:      "v1",
+				Version: 1,
+			},
+			setDefaultPolicyVersionResult: &Policy{
+				ID:      "test1",
+				Name:    "test",
+				Version: 1,
+			},
+			expectedPolicy: &Policy{
+				ID:      "test1",
+				Name:    "test",
+				Version: 1,
+			},
+		},
+		"ErrorCaseVersionNotFound": {
+			version: 5,
+			getPolicyVersionErr: &database.Error{
+				Code: database.POLICY_VERSION_NOT_FOUND,
+			},
+			wantError: &Error{
+				Code: POLICY_VERSION_NOT_FOUND,
+			},
+		},
+		"ErrorCaseSetDefaultPolicyVersionFail": {
+			version: 1,
+			getPolicyVersionResult: &PolicyVersion{
+				ID:      "v1",
+				Version: 1,
+			},
+			setDefaultPolicyVersionErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+		"ErrorCaseRevisionMismatch": {
+			version:  1,
+			revision: 2,
+			getPolicyVersionResult: &PolicyVersion{
+				ID:      "v1",
+				Version: 1,
+			},
+			wantError: &Error{
+				Code:    REVISION_MISMATCH,
+				Message: "Revision 2 required by request isn't the current revision 3",
+			},
+		},
+		"ErrorCaseSetDefaultPolicyVersionConcurrentlyModified": {
+			version:  1,
+			revision: 3,
+			getPolicyVersionResult: &PolicyVersion{
+				ID:      "v1",
+				Version: 1,
+			},
+			setDefaultPolicyVersionErr: &database.Error{
+				Code:    database.REVISION_MISMATCH,
+				Message: "Policy with id test1 has been modified, revision 3 isn't the current one",
+			},
+			wantError: &Error{
+				Code:    REVISION_MISMATCH,
+				Message: "Policy with id test1 has been modified, revision 3 isn't the current one",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetPolicyByNameMethod][0] = &Policy{
+			ID:       "test1",
+			Name:     "test",
+			Org:      "example",
+			Path:     "/path/",
+			Urn:      CreateUrn("example", RESOURCE_POLICY, "/path/", "test"),
+			Version:  2,
+			Revision: 3,
+		}
+		testRepo.ArgsOut[GetPolicyVersionMethod][0] = testcase.getPolicyVersionResult
+		testRepo.ArgsOut[GetPolicyVersionMethod][1] = testcase.getPolicyVersionErr
+		testRepo.ArgsOut[SetDefaultPolicyVersionMethod][0] = testcase.setDefaultPolicyVersionResult
+		testRepo.ArgsOut[SetDefaultPolicyVersionMethod][1] = testcase.setDefaultPolicyVersionErr
+		policy, err := testAPI.SetDefaultPolicyVersion(RequestInfo{Identifier: "123456", Admin: true, Revision: testcase.revision},
+			"example", "test", testcase.version)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedPolicy, policy)
+		if testcase.setDefaultPolicyVersionResult != nil {
+			assert.Equal(t, 3, testRepo.ArgsIn[SetDefaultPolicyVersionMethod][2], "Error in test case %v", x)
+		}
+	}
+}
@@ -22,6 +22,7 @@ type ProxyResource struct {
 	Resource ResourceEntity `json:"resource,omitempty"`
 	CreateAt time.Time      `json:"createAt,omitempty"`
 	UpdateAt time.Time      `json:"updateAt,omitempty"`
+	Revision int            `json:"revision,omitempty"`
 }
 
 // Proxy resource identifier to retrieve them from DB
@@ -31,11 +32,11 @@ type ProxyResourceIdentity struct {
 }
 
 type ResourceEntity struct {
-	Host   string `json:"host,omitempty"`
-	Path   string `json:"path,omitempty"`
-	Method string `json:"method,omitempty"`
-	Urn    string `json:"urn,omitempty"`
-	Action string `json:"action,omitempty"`
+	Host   string `json:"host,omitempty" yaml:"host,omitempty"`
+	Path   string `json:"path,omitempty" yaml:"path,omitempty"`
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	Urn    string `json:"urn,omitempty" yaml:"urn,omitempty"`
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
 }
 
 func (p ProxyResource) GetUrn() string {
@@ -60,6 +61,18 @@ func (api ProxyAPI) GetProxyResources() ([]ProxyResource, error) {
 }
 
 func (api WorkerAPI) AddProxyResource(requestInfo RequestInfo, name string, org string, path string, resource ResourceEntity) (*ProxyResource, error) {
+	var proxyResource *ProxyResource
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		proxyResource, err = txAPI.addProxyResource(requestInfo, name, org, path, resource)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proxyResource, nil
+}
+
+func (api WorkerAPI) addProxyResource(requestInfo RequestInfo, name string, org string, path string, resource ResourceEntity) (*ProxyResource, error) {
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -100,6 +113,11 @@ func (api WorkerAPI) AddProxyResource(requestInfo RequestInfo, name string, org
 		}
 	}
 
+	// Check if organization exists
+	if err := api.checkOrganizationExists(org); err != nil {
+		return nil, err
+	}
+
 	// Check if proxy resource already exists
 	_, err = api.ProxyRepo.GetProxyResourceByName(org, name)
 
@@ -142,14 +160,10 @@ func (api WorkerAPI) AddProxyResource(requestInfo RequestInfo, name string, org
 
 			// Check unexpected DB error
 			if err != nil {
-				//Transform to DB error
-				dbError := err.(*database.Error)
-				return nil, &Error{
-					Code:    UNKNOWN_API_ERROR,
-					Message: dbError.Message,
-				}
+				return nil, storeError(err)
 			}
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("proxy resource created %+v", created))
+			api.emitEvent(requestInfo, PROXY_RESOURCE_EVENT_CREATED, created, nil)
 			return created, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -258,6 +272,11 @@ func (api WorkerAPI) UpdateProxyResource(requestInfo RequestInfo, org string, na
 		}
 	}
 
+	// Check revision required by request
+	if err := checkRevision(requestInfo, oldProxyResource.Revision); err != nil {
+		return nil, err
+	}
+
 	// Check if a proxy resource with "newName" already exists
 	newProxyResource, err := api.GetProxyResourceByName(requestInfo, org, newName)
 
@@ -302,6 +321,7 @@ func (api WorkerAPI) UpdateProxyResource(requestInfo RequestInfo, org string, na
 		Resource: newResource,
 		CreateAt: oldProxyResource.CreateAt,
 		UpdateAt: time.Now().UTC(),
+		Revision: oldProxyResource.Revision,
 	}
 
 	// Retrieve all routes to check if new proxy resource is consistent
@@ -343,15 +363,11 @@ func (api WorkerAPI) UpdateProxyResource(requestInfo RequestInfo, org string, na
 
 	// Check unexpected DB error
 	if err != nil {
-		//Transform to DB error
-		dbError := err.(*database.Error)
-		return nil, &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
+		return nil, revisionError(err)
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Proxy resource updated from %+v to %+v", oldProxyResource, updatedProxyResource))
+	api.emitEvent(requestInfo, PROXY_RESOURCE_EVENT_UPDATED, updatedProxyResource, nil)
 	return updatedProxyResource, nil
 }
 
@@ -375,29 +391,34 @@ func (api WorkerAPI) RemoveProxyResource(requestInfo RequestInfo, org string, na
 		}
 	}
 
-	err = api.ProxyRepo.RemoveProxyResource(proxyResource.ID)
+	// Check revision required by request
+	if err := checkRevision(requestInfo, proxyResource.Revision); err != nil {
+		return err
+	}
+
+	err = api.ProxyRepo.RemoveProxyResource(proxyResource.ID, proxyResource.Revision)
 
 	// Error handling
 	if err != nil {
-		// Transform to DB error
-		dbError := err.(*database.Error)
-		return &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
+		return revisionError(err)
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Proxy resource deleted %+v", proxyResource))
+	api.emitEvent(requestInfo, PROXY_RESOURCE_EVENT_DELETED, proxyResource, nil)
 	return nil
 }
 
-func (api WorkerAPI) ListProxyResources(requestInfo RequestInfo, filter *Filter) ([]ProxyResourceIdentity, int, error) {
+func (api WorkerAPI) ListProxyResources(requestInfo RequestInfo, filter *Filter) ([]ProxyResourceIdentity, int, string, error) {
 	// Validate fields
 	var total int
 	orderByValidColumns := api.ProxyRepo.OrderByValidColumns(PROXY_ACTION_LIST_RESOURCES)
 	err := validateFilter(filter, orderByValidColumns)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
+	}
+	err = validateNextToken(filter)
+	if err != nil {
+		return nil, total, "", err
 	}
 
 	// Call repo to retrieve the proxy resources
@@ -407,12 +428,18 @@ func (api WorkerAPI) ListProxyResources(requestInfo RequestInfo, filter *Filter)
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return nil, total, &Error{
+		return nil, total, "", &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
 
+	var nextToken string
+	if len(proxyResources) > 0 {
+		last := proxyResources[len(proxyResources)-1]
+		nextToken = nextPageToken(filter, len(proxyResources), last.CreateAt, last.ID)
+	}
+
 	// Check restrictions
 	var urnPrefix string
 	if len(filter.Org) == 0 {
@@ -422,7 +449,7 @@ func (api WorkerAPI) ListProxyResources(requestInfo RequestInfo, filter *Filter)
 	}
 	proxyResourcesFiltered, err := api.GetAuthorizedProxyResources(requestInfo, urnPrefix, PROXY_ACTION_LIST_RESOURCES, proxyResources)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
 	}
 
 	proxyResourcesIDs := []ProxyResourceIdentity{}
@@ -433,7 +460,7 @@ func (api WorkerAPI) ListProxyResources(requestInfo RequestInfo, filter *Filter)
 		})
 	}
 
-	return proxyResourcesIDs, total, nil
+	return proxyResourcesIDs, total, nextToken, nil
 }
 
 // PRIVATE HELPER METHODS
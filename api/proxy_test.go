@@ -2130,7 +2130,7 @@ func TestWorkerAPI_ListProxyResources(t *testing.T) {
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 
-		resources, total, err := testAPI.ListProxyResources(testcase.requestInfo, testcase.filter)
+		resources, total, _, err := testAPI.ListProxyResources(testcase.requestInfo, testcase.filter)
 		checkMethodResponse(t, n, testcase.wantError, err, testcase.expectedProxyResources, resources)
 		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", n)
 	}
@@ -2160,6 +2160,7 @@ func TestWorkerAPI_AddProxyResource(t *testing.T) {
 		getProxyResourcesMethodErr      error
 		getUserByExternalIDMethodErr    error
 		addProxyResourceMethodErr       error
+		getOrganizationByNameMethodErr  error
 	}{
 		"OKCaseAdmin": {
 			requestInfo: RequestInfo{
@@ -2646,6 +2647,30 @@ func TestWorkerAPI_AddProxyResource(t *testing.T) {
 					"resource path: Error in route handler: a handle is already registered for path ''/path'",
 			},
 		},
+		"ErrorCaseOrganizationNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "name",
+			org:  "org",
+			path: "/example/",
+			resource: ResourceEntity{
+				Host:   "http://host.com",
+				Path:   "/path",
+				Method: "GET",
+				Urn:    "urn:ews:example:instance1:resource/get",
+				Action: "example:get",
+			},
+			getOrganizationByNameMethodErr: &database.Error{
+				Code:    database.ORGANIZATION_NOT_FOUND,
+				Message: "Organization with name org not found",
+			},
+			wantError: &Error{
+				Code:    ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization with name org not found",
+			},
+		},
 	}
 
 	for x, testcase := range testcases {
@@ -2663,6 +2688,7 @@ func TestWorkerAPI_AddProxyResource(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 		testRepo.ArgsOut[AddProxyResourceMethod][0] = testcase.expectedProxyResource
 		testRepo.ArgsOut[AddProxyResourceMethod][1] = testcase.addProxyResourceMethodErr
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = testcase.getOrganizationByNameMethodErr
 
 		proxyResource, err := testAPI.AddProxyResource(testcase.requestInfo, testcase.name, testcase.org, testcase.path, testcase.resource)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedProxyResource, proxyResource)
@@ -0,0 +1,31 @@
+package api
+
+import "time"
+
+// PURGE API IMPLEMENTATION
+
+func (api WorkerAPI) PurgeDeletedEntities(deleteBefore time.Time) (int, error) {
+	var users, groups, policies int
+
+	// Purge all kinds of entities in a single transaction
+	err := api.TransactionRepo.WithTransaction(func(repos Repos) error {
+		var err error
+		if users, err = repos.UserRepo.PurgeUsers(deleteBefore); err != nil {
+			return err
+		}
+		if groups, err = repos.GroupRepo.PurgeGroups(deleteBefore); err != nil {
+			return err
+		}
+		if policies, err = repos.PolicyRepo.PurgePolicies(deleteBefore); err != nil {
+			return err
+		}
+		return nil
+	})
+
+	// Error handling
+	if err != nil {
+		return 0, transactionError(err)
+	}
+
+	return users + groups + policies, nil
+}
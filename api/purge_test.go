@@ -0,0 +1,101 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerAPI_PurgeDeletedEntities(t *testing.T) {
+	deleteBefore := time.Date(2016, time.October, 1, 0, 0, 0, 0, time.UTC)
+	testcases := map[string]struct {
+		// Expected result
+		expectedPurged int
+		wantError      error
+		// Manager Results
+		purgeUsersResult    int
+		purgeGroupsResult   int
+		purgePoliciesResult int
+		// API Errors
+		purgeUsersErr      error
+		purgeGroupsErr     error
+		purgePoliciesErr   error
+		withTransactionErr error
+	}{
+		"OKCase": {
+			expectedPurged:      6,
+			purgeUsersResult:    1,
+			purgeGroupsResult:   2,
+			purgePoliciesResult: 3,
+		},
+		"OKCaseNothingToPurge": {
+			expectedPurged: 0,
+		},
+		"ErrorCasePurgeUsersDBErr": {
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			purgeUsersErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCasePurgeGroupsDBErr": {
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			purgeUsersResult: 1,
+			purgeGroupsErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCasePurgePoliciesDBErr": {
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			purgeUsersResult:  1,
+			purgeGroupsResult: 2,
+			purgePoliciesErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseTransactionError": {
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			withTransactionErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[PurgeUsersMethod][0] = test.purgeUsersResult
+		testRepo.ArgsOut[PurgeUsersMethod][1] = test.purgeUsersErr
+		testRepo.ArgsOut[PurgeGroupsMethod][0] = test.purgeGroupsResult
+		testRepo.ArgsOut[PurgeGroupsMethod][1] = test.purgeGroupsErr
+		testRepo.ArgsOut[PurgePoliciesMethod][0] = test.purgePoliciesResult
+		testRepo.ArgsOut[PurgePoliciesMethod][1] = test.purgePoliciesErr
+		testRepo.ArgsOut[WithTransactionMethod][0] = test.withTransactionErr
+
+		purged, err := testAPI.PurgeDeletedEntities(deleteBefore)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedPurged, purged)
+		if test.wantError == nil {
+			assert.Equal(t, deleteBefore, testRepo.ArgsIn[PurgeUsersMethod][0], "Error in test case %v", n)
+			assert.Equal(t, deleteBefore, testRepo.ArgsIn[PurgeGroupsMethod][0], "Error in test case %v", n)
+			assert.Equal(t, deleteBefore, testRepo.ArgsIn[PurgePoliciesMethod][0], "Error in test case %v", n)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"fmt"
+)
+
+// TYPE DEFINITIONS
+
+// ReconcileResult contains the plan computed to reach the desired state of some organizations
+type ReconcileResult struct {
+	Orgs            []string      `json:"orgs" yaml:"orgs"`
+	IgnoreUnmanaged bool          `json:"ignoreUnmanaged" yaml:"ignoreUnmanaged"`
+	Applied         bool          `json:"applied" yaml:"applied"`
+	Changes         []StateChange `json:"changes" yaml:"changes"`
+}
+
+// RECONCILE API IMPLEMENTATION
+
+func (api WorkerAPI) ReconcileState(requestInfo RequestInfo, state *State, ignoreUnmanaged bool, apply bool) (*ReconcileResult, error) {
+	// Validate fields
+	if err := validateReconcileState(state); err != nil {
+		return nil, err
+	}
+
+	result := &ReconcileResult{
+		Orgs:            []string{},
+		IgnoreUnmanaged: ignoreUnmanaged,
+		Applied:         apply,
+	}
+	for _, o := range state.Organizations {
+		result.Orgs = append(result.Orgs, o.Name)
+	}
+
+	// Plan is always applied in a transaction, so it's validated by the API methods even if it's discarded later
+	err := api.inTransaction(func(txAPI WorkerAPI) error {
+		currentState, err := txAPI.getOrganizationsState(requestInfo, result.Orgs)
+		if err != nil {
+			return err
+		}
+
+		result.Changes = planStateChanges(currentState, state, !ignoreUnmanaged, true)
+		for _, change := range result.Changes {
+			if err := change.apply(txAPI, requestInfo); err != nil {
+				return err
+			}
+		}
+
+		if !apply {
+			return errValidateOnly
+		}
+		return nil
+	})
+
+	// Error handling
+	if err != nil && err != errValidateOnly {
+		return nil, err
+	}
+
+	if apply {
+		LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("State of organizations %v reconciled with %v changes",
+			result.Orgs, len(result.Changes)))
+	}
+	return result, nil
+}
+
+// PRIVATE HELPER METHODS
+
+// validateReconcileState checks that the state document only has organizations and entities that belong to them
+func validateReconcileState(state *State) error {
+	if err := validateState(state); err != nil {
+		return err
+	}
+
+	if len(state.Organizations) == 0 {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: organizations, at least one organization is required",
+		}
+	}
+	if len(state.Users) > 0 || len(state.OidcProviders) > 0 {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: users and OIDC providers can't be reconciled",
+		}
+	}
+
+	orgs := map[string]bool{}
+	for _, o := range state.Organizations {
+		orgs[o.Name] = true
+	}
+	checkOrg := func(org string, resourceType string, name string) error {
+		if !orgs[org] {
+			return &Error{
+				Code: INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: resource %v belongs to an organization that isn't in the document",
+					CreateUrn(org, resourceType, "/", name)),
+			}
+		}
+		return nil
+	}
+	for _, g := range state.Groups {
+		if err := checkOrg(g.Org, RESOURCE_GROUP, g.Name); err != nil {
+			return err
+		}
+	}
+	for _, p := range state.Policies {
+		if err := checkOrg(p.Org, RESOURCE_POLICY, p.Name); err != nil {
+			return err
+		}
+	}
+	for _, pr := range state.ProxyResources {
+		if err := checkOrg(pr.Org, RESOURCE_PROXY, pr.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getOrganizationsState reads the groups, policies and proxy resources of the given organizations through
+// the API methods, so only entities that requestInfo can see are returned
+func (api WorkerAPI) getOrganizationsState(requestInfo RequestInfo, orgs []string) (*State, error) {
+	state := &State{
+		Version:        STATE_VERSION,
+		Organizations:  []OrganizationState{},
+		Groups:         []GroupState{},
+		Policies:       []PolicyState{},
+		ProxyResources: []ProxyResourceState{},
+	}
+
+	for _, org := range orgs {
+		o, err := api.GetOrganizationByName(requestInfo, org)
+		if err != nil {
+			if apiError, ok := err.(*Error); ok && apiError.Code == ORGANIZATION_BY_NAME_NOT_FOUND {
+				// Organization will be created
+				continue
+			}
+			return nil, err
+		}
+		state.Organizations = append(state.Organizations, OrganizationState{
+			Name: o.Name,
+			Path: o.Path,
+		})
+
+		groups, err := api.getOrganizationGroupsState(requestInfo, org)
+		if err != nil {
+			return nil, err
+		}
+		state.Groups = append(state.Groups, groups...)
+
+		policies, err := api.getOrganizationPoliciesState(requestInfo, org)
+		if err != nil {
+			return nil, err
+		}
+		state.Policies = append(state.Policies, policies...)
+
+		proxyResources, err := api.getOrganizationProxyResourcesState(requestInfo, org)
+		if err != nil {
+			return nil, err
+		}
+		state.ProxyResources = append(state.ProxyResources, proxyResources...)
+	}
+
+	return state, nil
+}
+
+func (api WorkerAPI) getOrganizationGroupsState(requestInfo RequestInfo, org string) ([]GroupState, error) {
+	groupIDs := []GroupIdentity{}
+	err := listAllPages(func(offset int) (int, error) {
+		groups, total, _, err := api.ListGroups(requestInfo, &Filter{Org: org, Offset: offset, Limit: MAX_LIMIT_SIZE})
+		groupIDs = append(groupIDs, groups...)
+		return total, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groupStates := []GroupState{}
+	for _, id := range groupIDs {
+		group, err := api.GetGroupByName(requestInfo, id.Org, id.Name)
+		if err != nil {
+			return nil, err
+		}
+		groupState := GroupState{
+			Org:  group.Org,
+			Name: group.Name,
+			Path: group.Path,
+		}
+		err = listAllPages(func(offset int) (int, error) {
+			members, total, _, err := api.ListMembers(requestInfo, &Filter{Org: org, GroupName: id.Name, Offset: offset, Limit: MAX_LIMIT_SIZE})
+			for _, m := range members {
+				groupState.Members = append(groupState.Members, m.User)
+			}
+			return total, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = listAllPages(func(offset int) (int, error) {
+			subgroups, total, err := api.ListSubgroups(requestInfo, &Filter{Org: org, GroupName: id.Name, Offset: offset, Limit: MAX_LIMIT_SIZE})
+			for _, s := range subgroups {
+				groupState.Subgroups = append(groupState.Subgroups, s.Group)
+			}
+			return total, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = listAllPages(func(offset int) (int, error) {
+			policies, total, err := api.ListAttachedGroupPolicies(requestInfo, &Filter{Org: org, GroupName: id.Name, Offset: offset, Limit: MAX_LIMIT_SIZE})
+			for _, p := range policies {
+				groupState.Policies = append(groupState.Policies, p.Policy)
+			}
+			return total, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		groupStates = append(groupStates, groupState)
+	}
+
+	return groupStates, nil
+}
+
+func (api WorkerAPI) getOrganizationPoliciesState(requestInfo RequestInfo, org string) ([]PolicyState, error) {
+	policyIDs := []PolicyIdentity{}
+	err := listAllPages(func(offset int) (int, error) {
+		policies, total, _, err := api.ListPolicies(requestInfo, &Filter{Org: org, Offset: offset, Limit: MAX_LIMIT_SIZE})
+		policyIDs = append(policyIDs, policies...)
+		return total, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policyStates := []PolicyState{}
+	for _, id := range policyIDs {
+		policy, err := api.GetPolicyByName(requestInfo, id.Org, id.Name)
+		if err != nil {
+			return nil, err
+		}
+		policyState := PolicyState{
+			Org:        policy.Org,
+			Name:       policy.Name,
+			Path:       policy.Path,
+			Statements: []Statement{},
+		}
+		if policy.Statements != nil {
+			policyState.Statements = *policy.Statements
+		}
+		policyStates = append(policyStates, policyState)
+	}
+
+	return policyStates, nil
+}
+
+func (api WorkerAPI) getOrganizationProxyResourcesState(requestInfo RequestInfo, org string) ([]ProxyResourceState, error) {
+	proxyResourceIDs := []ProxyResourceIdentity{}
+	err := listAllPages(func(offset int) (int, error) {
+		proxyResources, total, _, err := api.ListProxyResources(requestInfo, &Filter{Org: org, Offset: offset, Limit: MAX_LIMIT_SIZE})
+		proxyResourceIDs = append(proxyResourceIDs, proxyResources...)
+		return total, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	proxyResourceStates := []ProxyResourceState{}
+	for _, id := range proxyResourceIDs {
+		proxyResource, err := api.GetProxyResourceByName(requestInfo, id.Org, id.Name)
+		if err != nil {
+			return nil, err
+		}
+		proxyResourceStates = append(proxyResourceStates, ProxyResourceState{
+			Org:      proxyResource.Org,
+			Name:     proxyResource.Name,
+			Path:     proxyResource.Path,
+			Resource: proxyResource.Resource,
+		})
+	}
+
+	return proxyResourceStates, nil
+}
+
+// listAllPages calls list with increasing offsets until the total number of elements has been requested
+func listAllPages(list func(offset int) (int, error)) error {
+	for offset := 0; ; offset += MAX_LIMIT_SIZE {
+		total, err := list(offset)
+		if err != nil {
+			return err
+		}
+		if offset+MAX_LIMIT_SIZE >= total {
+			return nil
+		}
+	}
+}
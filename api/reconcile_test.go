@@ -0,0 +1,342 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerAPI_ReconcileState(t *testing.T) {
+	statements := []Statement{
+		{
+			Effect:    "allow",
+			Actions:   []string{USER_ACTION_GET_USER},
+			Resources: []string{GetUrnPrefix("", RESOURCE_USER, "/path/")},
+		},
+	}
+	state := &State{
+		Version: STATE_VERSION,
+		Organizations: []OrganizationState{
+			{
+				Name: "org1",
+				Path: "/path/",
+			},
+		},
+		Groups: []GroupState{
+			{
+				Org:     "org1",
+				Name:    "group1",
+				Path:    "/path/",
+				Members: []string{"user1"},
+			},
+		},
+	}
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo     RequestInfo
+		state           *State
+		ignoreUnmanaged bool
+		apply           bool
+		// Expected results
+		expectedChanges []string
+		wantError       error
+		// Manager Results
+		getOrganizationByNameResult *Organization
+		getGroupsFilteredResult     []Group
+		getGroupMembersResult       []TestUserGroupRelation
+		getPoliciesFilteredResult   []Policy
+		addOrganizationResult       *Organization
+		// Manager Errors
+		getOrganizationByNameErr error
+		removeMemberErr          error
+		withTransactionErr       error
+	}{
+		"OkCasePlan": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: state,
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			getGroupsFilteredResult: []Group{
+				{
+					ID:   "GROUP-ID",
+					Org:  "org1",
+					Name: "group1",
+					Path: "/path/",
+					Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+				},
+			},
+			getGroupMembersResult: []TestUserGroupRelation{
+				{
+					User: &User{
+						ID:         "USER-ID1",
+						ExternalID: "user1",
+					},
+				},
+				{
+					User: &User{
+						ID:         "USER-ID2",
+						ExternalID: "user2",
+					},
+				},
+			},
+			getPoliciesFilteredResult: []Policy{
+				{
+					ID:         "POLICY-ID",
+					Org:        "org1",
+					Name:       "policy1",
+					Path:       "/path/",
+					Urn:        CreateUrn("org1", RESOURCE_POLICY, "/path/", "policy1"),
+					Statements: &statements,
+				},
+			},
+			expectedChanges: []string{
+				"removeMember urn:iws:iam:org1:group/path/group1 user2",
+				"delete urn:iws:iam:org1:policy/path/policy1",
+			},
+		},
+		"OkCaseApplyIgnoreUnmanaged": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state:           state,
+			ignoreUnmanaged: true,
+			apply:           true,
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			getGroupsFilteredResult: []Group{
+				{
+					ID:   "GROUP-ID",
+					Org:  "org1",
+					Name: "group1",
+					Path: "/path/",
+					Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+				},
+			},
+			getGroupMembersResult: []TestUserGroupRelation{
+				{
+					User: &User{
+						ID:         "USER-ID1",
+						ExternalID: "user1",
+					},
+				},
+				{
+					User: &User{
+						ID:         "USER-ID2",
+						ExternalID: "user2",
+					},
+				},
+			},
+			getPoliciesFilteredResult: []Policy{
+				{
+					ID:         "POLICY-ID",
+					Org:        "org1",
+					Name:       "policy1",
+					Path:       "/path/",
+					Urn:        CreateUrn("org1", RESOURCE_POLICY, "/path/", "policy1"),
+					Statements: &statements,
+				},
+			},
+			expectedChanges: []string{
+				"removeMember urn:iws:iam:org1:group/path/group1 user2",
+			},
+		},
+		"OkCaseNewOrganization": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path/",
+					},
+				},
+			},
+			getOrganizationByNameErr: &database.Error{
+				Code: database.ORGANIZATION_NOT_FOUND,
+			},
+			addOrganizationResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+			},
+			expectedChanges: []string{
+				"create urn:iws:iam::org/path/org1",
+			},
+		},
+		"ErrorCaseNoOrganizations": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: organizations, at least one organization is required",
+			},
+		},
+		"ErrorCaseUsers": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path/",
+					},
+				},
+				Users: []UserState{
+					{
+						ExternalID: "user1",
+						Path:       "/path/",
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: users and OIDC providers can't be reconciled",
+			},
+		},
+		"ErrorCaseResourceOutsideOrganizations": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path/",
+					},
+				},
+				Policies: []PolicyState{
+					{
+						Org:        "org2",
+						Name:       "policy1",
+						Path:       "/path/",
+						Statements: statements,
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: resource urn:iws:iam:org2:policy/policy1 belongs to an organization that isn't in the document",
+			},
+		},
+		"ErrorCaseChangeFails": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state:           state,
+			ignoreUnmanaged: true,
+			apply:           true,
+			getOrganizationByNameResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			getGroupsFilteredResult: []Group{
+				{
+					ID:   "GROUP-ID",
+					Org:  "org1",
+					Name: "group1",
+					Path: "/path/",
+					Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+				},
+			},
+			getGroupMembersResult: []TestUserGroupRelation{
+				{
+					User: &User{
+						ID:         "USER-ID2",
+						ExternalID: "user2",
+					},
+				},
+			},
+			removeMemberErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseTransactionError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: state,
+			withTransactionErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetOrganizationByNameMethod][0] = test.getOrganizationByNameResult
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = test.getOrganizationByNameErr
+		testRepo.ArgsOut[AddOrganizationMethod][0] = test.addOrganizationResult
+		testRepo.ArgsOut[GetGroupsFilteredMethod][0] = test.getGroupsFilteredResult
+		testRepo.ArgsOut[GetGroupsFilteredMethod][1] = len(test.getGroupsFilteredResult)
+		if len(test.getGroupsFilteredResult) > 0 {
+			testRepo.ArgsOut[GetGroupByNameMethod][0] = &test.getGroupsFilteredResult[0]
+		}
+		testRepo.ArgsOut[GetGroupMembersMethod][0] = test.getGroupMembersResult
+		testRepo.ArgsOut[GetGroupMembersMethod][1] = len(test.getGroupMembersResult)
+		testRepo.ArgsOut[GetPoliciesFilteredMethod][0] = test.getPoliciesFilteredResult
+		testRepo.ArgsOut[GetPoliciesFilteredMethod][1] = len(test.getPoliciesFilteredResult)
+		if len(test.getPoliciesFilteredResult) > 0 {
+			testRepo.ArgsOut[GetPolicyByNameMethod][0] = &test.getPoliciesFilteredResult[0]
+		}
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = &User{
+			ID:         "USER-ID2",
+			ExternalID: "user2",
+		}
+		testRepo.ArgsOut[IsMemberOfGroupMethod][0] = true
+		testRepo.ArgsOut[RemoveMemberMethod][0] = test.removeMemberErr
+		testRepo.ArgsOut[WithTransactionMethod][0] = test.withTransactionErr
+
+		result, err := testAPI.ReconcileState(test.requestInfo, test.state, test.ignoreUnmanaged, test.apply)
+		if test.wantError != nil {
+			checkMethodResponse(t, n, test.wantError, err, nil, result)
+			continue
+		}
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, []string{"org1"}, result.Orgs, "Error in test case %v", n)
+		assert.Equal(t, test.ignoreUnmanaged, result.IgnoreUnmanaged, "Error in test case %v", n)
+		assert.Equal(t, test.apply, result.Applied, "Error in test case %v", n)
+		assert.Equal(t, test.expectedChanges, stateChangesToStrings(result.Changes), "Error in test case %v", n)
+	}
+}
@@ -0,0 +1,1056 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/database"
+)
+
+// TYPE DEFINITIONS
+
+const (
+	// Version of the state document format
+	STATE_VERSION = "v1"
+
+	// Import modes. Merge creates and updates entities and relations of the document keeping
+	// the rest, replace also removes everything that isn't in the document.
+	IMPORT_MODE_MERGE   = "merge"
+	IMPORT_MODE_REPLACE = "replace"
+
+	// Operations of state changes
+	STATE_OPERATION_CREATE          = "create"
+	STATE_OPERATION_UPDATE          = "update"
+	STATE_OPERATION_DELETE          = "delete"
+	STATE_OPERATION_ADD_MEMBER      = "addMember"
+	STATE_OPERATION_REMOVE_MEMBER   = "removeMember"
+	STATE_OPERATION_ADD_SUBGROUP    = "addSubgroup"
+	STATE_OPERATION_REMOVE_SUBGROUP = "removeSubgroup"
+	STATE_OPERATION_ATTACH_POLICY   = "attachPolicy"
+	STATE_OPERATION_DETACH_POLICY   = "detachPolicy"
+)
+
+// State document with the complete IAM configuration
+type State struct {
+	Version        string               `json:"version" yaml:"version"`
+	Organizations  []OrganizationState  `json:"organizations,omitempty" yaml:"organizations,omitempty"`
+	Users          []UserState          `json:"users,omitempty" yaml:"users,omitempty"`
+	Groups         []GroupState         `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Policies       []PolicyState        `json:"policies,omitempty" yaml:"policies,omitempty"`
+	ProxyResources []ProxyResourceState `json:"proxyResources,omitempty" yaml:"proxyResources,omitempty"`
+	OidcProviders  []OidcProviderState  `json:"oidcProviders,omitempty" yaml:"oidcProviders,omitempty"`
+}
+
+type OrganizationState struct {
+	Name string `json:"name" yaml:"name"`
+	Path string `json:"path" yaml:"path"`
+}
+
+type UserState struct {
+	ExternalID string            `json:"externalId" yaml:"externalId"`
+	Path       string            `json:"path" yaml:"path"`
+	Policies   []PolicyReference `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+// PolicyReference identifies a policy attached to a user, which can belong to any organization
+type PolicyReference struct {
+	Org  string `json:"org" yaml:"org"`
+	Name string `json:"name" yaml:"name"`
+}
+
+// GroupState with its relations. Subgroups and policies belong to the group organization
+type GroupState struct {
+	Org       string   `json:"org" yaml:"org"`
+	Name      string   `json:"name" yaml:"name"`
+	Path      string   `json:"path" yaml:"path"`
+	Members   []string `json:"members,omitempty" yaml:"members,omitempty"`
+	Subgroups []string `json:"subgroups,omitempty" yaml:"subgroups,omitempty"`
+	Policies  []string `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+type PolicyState struct {
+	Org        string      `json:"org" yaml:"org"`
+	Name       string      `json:"name" yaml:"name"`
+	Path       string      `json:"path" yaml:"path"`
+	Statements []Statement `json:"statements" yaml:"statements"`
+}
+
+type ProxyResourceState struct {
+	Org      string         `json:"org" yaml:"org"`
+	Name     string         `json:"name" yaml:"name"`
+	Path     string         `json:"path" yaml:"path"`
+	Resource ResourceEntity `json:"resource" yaml:"resource"`
+}
+
+type OidcProviderState struct {
+	Name      string   `json:"name" yaml:"name"`
+	Path      string   `json:"path" yaml:"path"`
+	IssuerURL string   `json:"issuerUrl" yaml:"issuerUrl"`
+	Clients   []string `json:"clients,omitempty" yaml:"clients,omitempty"`
+}
+
+// StateChange describes an operation needed to reach the imported state
+type StateChange struct {
+	Operation string `json:"operation" yaml:"operation"`
+	Resource  string `json:"resource" yaml:"resource"`
+	Target    string `json:"target,omitempty" yaml:"target,omitempty"`
+	// Fields changed by an update
+	Details []string `json:"details,omitempty" yaml:"details,omitempty"`
+
+	// Function that performs the change
+	apply func(api WorkerAPI, requestInfo RequestInfo) error
+}
+
+func (sc StateChange) String() string {
+	if sc.Target != "" {
+		return fmt.Sprintf("%v %v %v", sc.Operation, sc.Resource, sc.Target)
+	}
+	return fmt.Sprintf("%v %v", sc.Operation, sc.Resource)
+}
+
+type ImportResult struct {
+	Mode         string        `json:"mode" yaml:"mode"`
+	ValidateOnly bool          `json:"validateOnly" yaml:"validateOnly"`
+	Changes      []StateChange `json:"changes" yaml:"changes"`
+}
+
+// Returned inside the transaction to discard changes of a validation-only import
+var errValidateOnly = errors.New("Validation only import")
+
+// STATE API IMPLEMENTATION
+
+func (api WorkerAPI) ExportState(requestInfo RequestInfo) (*State, error) {
+	// Check restrictions
+	if !requestInfo.Admin {
+		return nil, &Error{
+			Code:    UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to export IAM state", requestInfo.Identifier),
+		}
+	}
+
+	return api.getState()
+}
+
+func (api WorkerAPI) ImportState(requestInfo RequestInfo, state *State, mode string, validateOnly bool) (*ImportResult, error) {
+	// Check restrictions
+	if !requestInfo.Admin {
+		return nil, &Error{
+			Code:    UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to import IAM state", requestInfo.Identifier),
+		}
+	}
+
+	// Validate fields
+	if mode == "" {
+		mode = IMPORT_MODE_MERGE
+	}
+	if mode != IMPORT_MODE_MERGE && mode != IMPORT_MODE_REPLACE {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: mode %v", mode),
+		}
+	}
+	if err := validateState(state); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		Mode:         mode,
+		ValidateOnly: validateOnly,
+	}
+
+	// Apply changes in a single transaction. Every change is done through the API methods
+	// so they are validated as if they had been requested one by one
+	err := api.inTransaction(func(txAPI WorkerAPI) error {
+		currentState, err := txAPI.getState()
+		if err != nil {
+			return err
+		}
+
+		result.Changes = planStateChanges(currentState, state, mode == IMPORT_MODE_REPLACE, mode == IMPORT_MODE_REPLACE)
+		for _, change := range result.Changes {
+			if err := change.apply(txAPI, requestInfo); err != nil {
+				return err
+			}
+		}
+
+		if validateOnly {
+			return errValidateOnly
+		}
+		return nil
+	})
+
+	// Error handling
+	if err != nil && err != errValidateOnly {
+		return nil, err
+	}
+
+	if !validateOnly {
+		LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("IAM state imported in %v mode with %v changes",
+			mode, len(result.Changes)))
+	}
+	return result, nil
+}
+
+// PRIVATE HELPER METHODS
+
+// withRepos returns a copy of the API that uses the given repositories
+func (api WorkerAPI) withRepos(repos Repos) WorkerAPI {
+	return WorkerAPI{
+		UserRepo:         repos.UserRepo,
+		GroupRepo:        repos.GroupRepo,
+		PolicyRepo:       repos.PolicyRepo,
+		ProxyRepo:        repos.ProxyRepo,
+		AuthOidcRepo:     repos.AuthOidcRepo,
+		OrganizationRepo: repos.OrganizationRepo,
+		TransactionRepo:  repos.TransactionRepo,
+		WebhookRepo:      api.WebhookRepo,
+		events:           api.events,
+	}
+}
+
+// inTransaction runs f with a copy of the API bound to a single transaction, so the checks and changes
+// done by f are applied as a unit. Events of the changes are published once the outermost transaction
+// is committed, so they are discarded if it's rolled back.
+func (api WorkerAPI) inTransaction(f func(txAPI WorkerAPI) error) error {
+	outermost := api.events == nil
+	if outermost {
+		api.events = &[]Event{}
+	}
+	err := api.TransactionRepo.WithTransaction(func(repos Repos) error {
+		return f(api.withRepos(repos))
+	})
+	if err != nil {
+		// Validation only requests roll back their changes on purpose
+		if err == errValidateOnly {
+			return err
+		}
+		return transactionError(err)
+	}
+	if outermost {
+		api.publishEvents(*api.events)
+	}
+	return nil
+}
+
+// getState reads the complete IAM state from repositories without checking restrictions
+func (api WorkerAPI) getState() (*State, error) {
+	state := &State{
+		Version:        STATE_VERSION,
+		Organizations:  []OrganizationState{},
+		Users:          []UserState{},
+		Groups:         []GroupState{},
+		Policies:       []PolicyState{},
+		ProxyResources: []ProxyResourceState{},
+		OidcProviders:  []OidcProviderState{},
+	}
+
+	orgs, _, err := api.OrganizationRepo.GetOrganizationsFiltered(&Filter{})
+	if err != nil {
+		return nil, dbErrorToAPIError(err)
+	}
+	for _, o := range orgs {
+		state.Organizations = append(state.Organizations, OrganizationState{
+			Name: o.Name,
+			Path: o.Path,
+		})
+	}
+
+	users, _, err := api.UserRepo.GetUsersFiltered(&Filter{})
+	if err != nil {
+		return nil, dbErrorToAPIError(err)
+	}
+	for _, u := range users {
+		userPolicies, _, err := api.UserRepo.GetAttachedUserPolicies(u.ID, &Filter{})
+		if err != nil {
+			return nil, dbErrorToAPIError(err)
+		}
+		userState := UserState{
+			ExternalID: u.ExternalID,
+			Path:       u.Path,
+		}
+		for _, up := range userPolicies {
+			userState.Policies = append(userState.Policies, PolicyReference{
+				Org:  up.GetPolicy().Org,
+				Name: up.GetPolicy().Name,
+			})
+		}
+		state.Users = append(state.Users, userState)
+	}
+
+	groups, _, err := api.GroupRepo.GetGroupsFiltered(&Filter{})
+	if err != nil {
+		return nil, dbErrorToAPIError(err)
+	}
+	for _, g := range groups {
+		groupState := GroupState{
+			Org:  g.Org,
+			Name: g.Name,
+			Path: g.Path,
+		}
+		members, _, err := api.GroupRepo.GetGroupMembers(g.ID, &Filter{})
+		if err != nil {
+			return nil, dbErrorToAPIError(err)
+		}
+		for _, m := range members {
+			groupState.Members = append(groupState.Members, m.GetUser().ExternalID)
+		}
+		subgroups, _, err := api.GroupRepo.GetSubgroups(g.ID, &Filter{})
+		if err != nil {
+			return nil, dbErrorToAPIError(err)
+		}
+		for _, s := range subgroups {
+			groupState.Subgroups = append(groupState.Subgroups, s.GetSubgroup().Name)
+		}
+		groupPolicies, _, err := api.GroupRepo.GetAttachedPolicies(g.ID, &Filter{})
+		if err != nil {
+			return nil, dbErrorToAPIError(err)
+		}
+		for _, gp := range groupPolicies {
+			groupState.Policies = append(groupState.Policies, gp.GetPolicy().Name)
+		}
+		state.Groups = append(state.Groups, groupState)
+	}
+
+	policies, _, err := api.PolicyRepo.GetPoliciesFiltered(&Filter{})
+	if err != nil {
+		return nil, dbErrorToAPIError(err)
+	}
+	for _, p := range policies {
+		policyState := PolicyState{
+			Org:        p.Org,
+			Name:       p.Name,
+			Path:       p.Path,
+			Statements: []Statement{},
+		}
+		if p.Statements != nil {
+			policyState.Statements = *p.Statements
+		}
+		state.Policies = append(state.Policies, policyState)
+	}
+
+	proxyResources, _, err := api.ProxyRepo.GetProxyResources(&Filter{})
+	if err != nil {
+		return nil, dbErrorToAPIError(err)
+	}
+	for _, pr := range proxyResources {
+		state.ProxyResources = append(state.ProxyResources, ProxyResourceState{
+			Org:      pr.Org,
+			Name:     pr.Name,
+			Path:     pr.Path,
+			Resource: pr.Resource,
+		})
+	}
+
+	oidcProviders, _, err := api.AuthOidcRepo.GetOidcProvidersFiltered(&Filter{})
+	if err != nil {
+		return nil, dbErrorToAPIError(err)
+	}
+	for _, op := range oidcProviders {
+		oidcProviderState := OidcProviderState{
+			Name:      op.Name,
+			Path:      op.Path,
+			IssuerURL: op.IssuerURL,
+		}
+		for _, c := range op.OidcClients {
+			oidcProviderState.Clients = append(oidcProviderState.Clients, c.Name)
+		}
+		state.OidcProviders = append(state.OidcProviders, oidcProviderState)
+	}
+
+	return state, nil
+}
+
+// validateState checks the document version and that there aren't duplicated entities.
+// Entity fields are validated later by the API methods that apply the changes.
+func validateState(state *State) error {
+	if state == nil || state.Version != STATE_VERSION {
+		version := ""
+		if state != nil {
+			version = state.Version
+		}
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: version %v, supported version: %v", version, STATE_VERSION),
+		}
+	}
+
+	keys := map[string]bool{}
+	checkDuplicated := func(urn string) error {
+		if keys[urn] {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: resource %v is duplicated", urn),
+			}
+		}
+		keys[urn] = true
+		return nil
+	}
+	for _, o := range state.Organizations {
+		if err := checkDuplicated(CreateUrn("", RESOURCE_ORGANIZATION, "/", o.Name)); err != nil {
+			return err
+		}
+	}
+	for _, u := range state.Users {
+		if err := checkDuplicated(CreateUrn("", RESOURCE_USER, "/", u.ExternalID)); err != nil {
+			return err
+		}
+	}
+	for _, g := range state.Groups {
+		if err := checkDuplicated(CreateUrn(g.Org, RESOURCE_GROUP, "/", g.Name)); err != nil {
+			return err
+		}
+	}
+	for _, p := range state.Policies {
+		if err := checkDuplicated(CreateUrn(p.Org, RESOURCE_POLICY, "/", p.Name)); err != nil {
+			return err
+		}
+	}
+	for _, pr := range state.ProxyResources {
+		if err := checkDuplicated(CreateUrn(pr.Org, RESOURCE_PROXY, "/", pr.Name)); err != nil {
+			return err
+		}
+	}
+	for _, op := range state.OidcProviders {
+		if err := checkDuplicated(CreateUrn("", RESOURCE_AUTH_OIDC_PROVIDER, "/", op.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planStateChanges compares the current state with the desired one and returns the changes needed to reach it.
+// Relations are removed before entities are deleted and added after entities are created, so a group hierarchy
+// can be reorganized without cycles. Entities that aren't in the desired state are only deleted if removeEntities
+// is true, and relations of desired entities that aren't in the desired state are only removed if removeRelations
+// is true.
+func planStateChanges(current *State, desired *State, removeEntities bool, removeRelations bool) []StateChange {
+	changes := []StateChange{}
+
+	currentOrgs := map[string]OrganizationState{}
+	for _, o := range current.Organizations {
+		currentOrgs[o.Name] = o
+	}
+	desiredOrgs := map[string]bool{}
+	for _, o := range desired.Organizations {
+		desiredOrgs[o.Name] = true
+	}
+	currentUsers := map[string]UserState{}
+	for _, u := range current.Users {
+		currentUsers[u.ExternalID] = u
+	}
+	desiredUsers := map[string]bool{}
+	for _, u := range desired.Users {
+		desiredUsers[u.ExternalID] = true
+	}
+	currentGroups := map[string]GroupState{}
+	for _, g := range current.Groups {
+		currentGroups[g.Org+"/"+g.Name] = g
+	}
+	desiredGroups := map[string]bool{}
+	for _, g := range desired.Groups {
+		desiredGroups[g.Org+"/"+g.Name] = true
+	}
+	currentPolicies := map[string]PolicyState{}
+	for _, p := range current.Policies {
+		currentPolicies[p.Org+"/"+p.Name] = p
+	}
+	desiredPolicies := map[string]bool{}
+	for _, p := range desired.Policies {
+		desiredPolicies[p.Org+"/"+p.Name] = true
+	}
+	currentProxyResources := map[string]ProxyResourceState{}
+	for _, pr := range current.ProxyResources {
+		currentProxyResources[pr.Org+"/"+pr.Name] = pr
+	}
+	desiredProxyResources := map[string]bool{}
+	for _, pr := range desired.ProxyResources {
+		desiredProxyResources[pr.Org+"/"+pr.Name] = true
+	}
+	currentOidcProviders := map[string]OidcProviderState{}
+	for _, op := range current.OidcProviders {
+		currentOidcProviders[op.Name] = op
+	}
+	desiredOidcProviders := map[string]bool{}
+	for _, op := range desired.OidcProviders {
+		desiredOidcProviders[op.Name] = true
+	}
+
+	// Entities that will be deleted, their relations are removed with them
+	deletedUsers := map[string]bool{}
+	deletedGroups := map[string]bool{}
+	deletedPolicies := map[string]bool{}
+	if removeEntities {
+		for _, u := range current.Users {
+			deletedUsers[u.ExternalID] = !desiredUsers[u.ExternalID]
+		}
+		for _, g := range current.Groups {
+			deletedGroups[g.Org+"/"+g.Name] = !desiredGroups[g.Org+"/"+g.Name]
+		}
+		for _, p := range current.Policies {
+			deletedPolicies[p.Org+"/"+p.Name] = !desiredPolicies[p.Org+"/"+p.Name]
+		}
+	}
+
+	if removeRelations {
+		// Remove relations of entities that will be kept
+		for _, g := range desired.Groups {
+			cg, ok := currentGroups[g.Org+"/"+g.Name]
+			if !ok {
+				continue
+			}
+			for _, m := range stringsNotContained(cg.Members, g.Members) {
+				if !deletedUsers[m] {
+					changes = append(changes, removeMemberChange(g, m))
+				}
+			}
+			for _, s := range stringsNotContained(cg.Subgroups, g.Subgroups) {
+				if !deletedGroups[g.Org+"/"+s] {
+					changes = append(changes, removeSubgroupChange(g, s))
+				}
+			}
+			for _, p := range stringsNotContained(cg.Policies, g.Policies) {
+				if !deletedPolicies[g.Org+"/"+p] {
+					changes = append(changes, detachGroupPolicyChange(g, p))
+				}
+			}
+		}
+		for _, u := range desired.Users {
+			cu, ok := currentUsers[u.ExternalID]
+			if !ok {
+				continue
+			}
+			for _, p := range policyReferencesNotContained(cu.Policies, u.Policies) {
+				if !deletedPolicies[p.Org+"/"+p.Name] {
+					changes = append(changes, detachUserPolicyChange(u, p))
+				}
+			}
+		}
+	}
+
+	if removeEntities {
+		// Delete entities
+		for _, g := range current.Groups {
+			if deletedGroups[g.Org+"/"+g.Name] {
+				changes = append(changes, deleteGroupChange(g))
+			}
+		}
+		for _, p := range current.Policies {
+			if deletedPolicies[p.Org+"/"+p.Name] {
+				changes = append(changes, deletePolicyChange(p))
+			}
+		}
+		for _, pr := range current.ProxyResources {
+			if !desiredProxyResources[pr.Org+"/"+pr.Name] {
+				changes = append(changes, deleteProxyResourceChange(pr))
+			}
+		}
+		for _, u := range current.Users {
+			if deletedUsers[u.ExternalID] {
+				changes = append(changes, deleteUserChange(u))
+			}
+		}
+		for _, op := range current.OidcProviders {
+			if !desiredOidcProviders[op.Name] {
+				changes = append(changes, deleteOidcProviderChange(op))
+			}
+		}
+		for _, o := range current.Organizations {
+			if !desiredOrgs[o.Name] {
+				changes = append(changes, deleteOrganizationChange(o))
+			}
+		}
+	}
+
+	// Create or update entities
+	for _, o := range desired.Organizations {
+		co, ok := currentOrgs[o.Name]
+		if !ok {
+			changes = append(changes, createOrganizationChange(o))
+		} else if details := pathDetails(co.Path, o.Path); len(details) > 0 {
+			changes = append(changes, updateOrganizationChange(o, details))
+		}
+	}
+	for _, u := range desired.Users {
+		cu, ok := currentUsers[u.ExternalID]
+		if !ok {
+			changes = append(changes, createUserChange(u))
+		} else if details := pathDetails(cu.Path, u.Path); len(details) > 0 {
+			changes = append(changes, updateUserChange(u, details))
+		}
+	}
+	for _, op := range desired.OidcProviders {
+		cop, ok := currentOidcProviders[op.Name]
+		if !ok {
+			changes = append(changes, createOidcProviderChange(op))
+		} else if details := oidcProviderDetails(cop, op); len(details) > 0 {
+			changes = append(changes, updateOidcProviderChange(op, details))
+		}
+	}
+	for _, p := range desired.Policies {
+		cp, ok := currentPolicies[p.Org+"/"+p.Name]
+		if !ok {
+			changes = append(changes, createPolicyChange(p))
+		} else if details := policyDetails(cp, p); len(details) > 0 {
+			changes = append(changes, updatePolicyChange(p, details))
+		}
+	}
+	for _, pr := range desired.ProxyResources {
+		cpr, ok := currentProxyResources[pr.Org+"/"+pr.Name]
+		if !ok {
+			changes = append(changes, createProxyResourceChange(pr))
+		} else if details := proxyResourceDetails(cpr, pr); len(details) > 0 {
+			changes = append(changes, updateProxyResourceChange(pr, details))
+		}
+	}
+	for _, g := range desired.Groups {
+		cg, ok := currentGroups[g.Org+"/"+g.Name]
+		if !ok {
+			changes = append(changes, createGroupChange(g))
+		} else if details := pathDetails(cg.Path, g.Path); len(details) > 0 {
+			changes = append(changes, updateGroupChange(g, details))
+		}
+	}
+
+	// Add relations
+	for _, g := range desired.Groups {
+		cg := currentGroups[g.Org+"/"+g.Name]
+		for _, m := range stringsNotContained(g.Members, cg.Members) {
+			changes = append(changes, addMemberChange(g, m))
+		}
+		for _, s := range stringsNotContained(g.Subgroups, cg.Subgroups) {
+			changes = append(changes, addSubgroupChange(g, s))
+		}
+		for _, p := range stringsNotContained(g.Policies, cg.Policies) {
+			changes = append(changes, attachGroupPolicyChange(g, p))
+		}
+	}
+	for _, u := range desired.Users {
+		cu := currentUsers[u.ExternalID]
+		for _, p := range policyReferencesNotContained(u.Policies, cu.Policies) {
+			changes = append(changes, attachUserPolicyChange(u, p))
+		}
+	}
+
+	return changes
+}
+
+func createOrganizationChange(o OrganizationState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_CREATE,
+		Resource:  CreateUrn("", RESOURCE_ORGANIZATION, o.Path, o.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.AddOrganization(requestInfo, o.Name, o.Path)
+			return err
+		},
+	}
+}
+
+func updateOrganizationChange(o OrganizationState, details []string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_UPDATE,
+		Resource:  CreateUrn("", RESOURCE_ORGANIZATION, o.Path, o.Name),
+		Details:   details,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.UpdateOrganization(requestInfo, o.Name, o.Path)
+			return err
+		},
+	}
+}
+
+func deleteOrganizationChange(o OrganizationState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_DELETE,
+		Resource:  CreateUrn("", RESOURCE_ORGANIZATION, o.Path, o.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.RemoveOrganization(requestInfo, o.Name)
+		},
+	}
+}
+
+func createUserChange(u UserState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_CREATE,
+		Resource:  CreateUrn("", RESOURCE_USER, u.Path, u.ExternalID),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.AddUser(requestInfo, u.ExternalID, u.Path)
+			return err
+		},
+	}
+}
+
+func updateUserChange(u UserState, details []string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_UPDATE,
+		Resource:  CreateUrn("", RESOURCE_USER, u.Path, u.ExternalID),
+		Details:   details,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.UpdateUser(requestInfo, u.ExternalID, u.Path)
+			return err
+		},
+	}
+}
+
+func deleteUserChange(u UserState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_DELETE,
+		Resource:  CreateUrn("", RESOURCE_USER, u.Path, u.ExternalID),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.RemoveUser(requestInfo, u.ExternalID)
+		},
+	}
+}
+
+func createGroupChange(g GroupState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_CREATE,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.AddGroup(requestInfo, g.Org, g.Name, g.Path)
+			return err
+		},
+	}
+}
+
+func updateGroupChange(g GroupState, details []string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_UPDATE,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		Details:   details,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.UpdateGroup(requestInfo, g.Org, g.Name, g.Name, g.Path)
+			return err
+		},
+	}
+}
+
+func deleteGroupChange(g GroupState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_DELETE,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.RemoveGroup(requestInfo, g.Org, g.Name)
+		},
+	}
+}
+
+func createPolicyChange(p PolicyState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_CREATE,
+		Resource:  CreateUrn(p.Org, RESOURCE_POLICY, p.Path, p.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.AddPolicy(requestInfo, p.Name, p.Path, p.Org, p.Statements)
+			return err
+		},
+	}
+}
+
+func updatePolicyChange(p PolicyState, details []string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_UPDATE,
+		Resource:  CreateUrn(p.Org, RESOURCE_POLICY, p.Path, p.Name),
+		Details:   details,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.UpdatePolicy(requestInfo, p.Org, p.Name, p.Name, p.Path, p.Statements)
+			return err
+		},
+	}
+}
+
+func deletePolicyChange(p PolicyState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_DELETE,
+		Resource:  CreateUrn(p.Org, RESOURCE_POLICY, p.Path, p.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.RemovePolicy(requestInfo, p.Org, p.Name)
+		},
+	}
+}
+
+func createProxyResourceChange(pr ProxyResourceState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_CREATE,
+		Resource:  CreateUrn(pr.Org, RESOURCE_PROXY, pr.Path, pr.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.AddProxyResource(requestInfo, pr.Name, pr.Org, pr.Path, pr.Resource)
+			return err
+		},
+	}
+}
+
+func updateProxyResourceChange(pr ProxyResourceState, details []string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_UPDATE,
+		Resource:  CreateUrn(pr.Org, RESOURCE_PROXY, pr.Path, pr.Name),
+		Details:   details,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.UpdateProxyResource(requestInfo, pr.Org, pr.Name, pr.Name, pr.Path, pr.Resource)
+			return err
+		},
+	}
+}
+
+func deleteProxyResourceChange(pr ProxyResourceState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_DELETE,
+		Resource:  CreateUrn(pr.Org, RESOURCE_PROXY, pr.Path, pr.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.RemoveProxyResource(requestInfo, pr.Org, pr.Name)
+		},
+	}
+}
+
+func createOidcProviderChange(op OidcProviderState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_CREATE,
+		Resource:  CreateUrn("", RESOURCE_AUTH_OIDC_PROVIDER, op.Path, op.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.AddOidcProvider(requestInfo, op.Name, op.Path, op.IssuerURL, op.Clients)
+			return err
+		},
+	}
+}
+
+func updateOidcProviderChange(op OidcProviderState, details []string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_UPDATE,
+		Resource:  CreateUrn("", RESOURCE_AUTH_OIDC_PROVIDER, op.Path, op.Name),
+		Details:   details,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			_, err := api.UpdateOidcProvider(requestInfo, op.Name, op.Name, op.Path, op.IssuerURL, op.Clients)
+			return err
+		},
+	}
+}
+
+func deleteOidcProviderChange(op OidcProviderState) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_DELETE,
+		Resource:  CreateUrn("", RESOURCE_AUTH_OIDC_PROVIDER, op.Path, op.Name),
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.RemoveOidcProvider(requestInfo, op.Name)
+		},
+	}
+}
+
+func addMemberChange(g GroupState, externalID string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_ADD_MEMBER,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		Target:    externalID,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.AddMember(requestInfo, externalID, g.Name, g.Org)
+		},
+	}
+}
+
+func removeMemberChange(g GroupState, externalID string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_REMOVE_MEMBER,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		Target:    externalID,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.RemoveMember(requestInfo, externalID, g.Name, g.Org)
+		},
+	}
+}
+
+func addSubgroupChange(g GroupState, subgroupName string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_ADD_SUBGROUP,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		Target:    subgroupName,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.AddSubgroup(requestInfo, g.Org, g.Name, subgroupName)
+		},
+	}
+}
+
+func removeSubgroupChange(g GroupState, subgroupName string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_REMOVE_SUBGROUP,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		Target:    subgroupName,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.RemoveSubgroup(requestInfo, g.Org, g.Name, subgroupName)
+		},
+	}
+}
+
+func attachGroupPolicyChange(g GroupState, policyName string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_ATTACH_POLICY,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		Target:    policyName,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.AttachPolicyToGroup(requestInfo, g.Org, g.Name, policyName)
+		},
+	}
+}
+
+func detachGroupPolicyChange(g GroupState, policyName string) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_DETACH_POLICY,
+		Resource:  CreateUrn(g.Org, RESOURCE_GROUP, g.Path, g.Name),
+		Target:    policyName,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.DetachPolicyToGroup(requestInfo, g.Org, g.Name, policyName)
+		},
+	}
+}
+
+func attachUserPolicyChange(u UserState, p PolicyReference) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_ATTACH_POLICY,
+		Resource:  CreateUrn("", RESOURCE_USER, u.Path, u.ExternalID),
+		Target:    p.Org + "/" + p.Name,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.AttachPolicyToUser(requestInfo, u.ExternalID, p.Org, p.Name)
+		},
+	}
+}
+
+func detachUserPolicyChange(u UserState, p PolicyReference) StateChange {
+	return StateChange{
+		Operation: STATE_OPERATION_DETACH_POLICY,
+		Resource:  CreateUrn("", RESOURCE_USER, u.Path, u.ExternalID),
+		Target:    p.Org + "/" + p.Name,
+		apply: func(api WorkerAPI, requestInfo RequestInfo) error {
+			return api.DetachPolicyFromUser(requestInfo, u.ExternalID, p.Org, p.Name)
+		},
+	}
+}
+
+// pathDetails describes a path change, if any
+func pathDetails(current string, desired string) []string {
+	details := []string{}
+	if current != desired {
+		details = append(details, fieldDetail("path", current, desired))
+	}
+	return details
+}
+
+func oidcProviderDetails(current OidcProviderState, desired OidcProviderState) []string {
+	details := pathDetails(current.Path, desired.Path)
+	if current.IssuerURL != desired.IssuerURL {
+		details = append(details, fieldDetail("issuerUrl", current.IssuerURL, desired.IssuerURL))
+	}
+	if !isEqualStringSet(current.Clients, desired.Clients) {
+		details = append(details, fieldDetail("clients", strings.Join(current.Clients, ","), strings.Join(desired.Clients, ",")))
+	}
+	return details
+}
+
+func policyDetails(current PolicyState, desired PolicyState) []string {
+	details := pathDetails(current.Path, desired.Path)
+	if !isEqualStatementArray(current.Statements, desired.Statements) {
+		added := len(statementsNotContained(desired.Statements, current.Statements))
+		removed := len(statementsNotContained(current.Statements, desired.Statements))
+		if added == 0 && removed == 0 {
+			details = append(details, "statements: reordered")
+		} else {
+			details = append(details, fmt.Sprintf("statements: %v added, %v removed", added, removed))
+		}
+	}
+	return details
+}
+
+func proxyResourceDetails(current ProxyResourceState, desired ProxyResourceState) []string {
+	details := pathDetails(current.Path, desired.Path)
+	if current.Resource.Host != desired.Resource.Host {
+		details = append(details, fieldDetail("host", current.Resource.Host, desired.Resource.Host))
+	}
+	if current.Resource.Path != desired.Resource.Path {
+		details = append(details, fieldDetail("resource path", current.Resource.Path, desired.Resource.Path))
+	}
+	if current.Resource.Method != desired.Resource.Method {
+		details = append(details, fieldDetail("method", current.Resource.Method, desired.Resource.Method))
+	}
+	if current.Resource.Urn != desired.Resource.Urn {
+		details = append(details, fieldDetail("urn", current.Resource.Urn, desired.Resource.Urn))
+	}
+	if current.Resource.Action != desired.Resource.Action {
+		details = append(details, fieldDetail("action", current.Resource.Action, desired.Resource.Action))
+	}
+	return details
+}
+
+func fieldDetail(field string, current string, desired string) string {
+	return fmt.Sprintf("%v: %v -> %v", field, current, desired)
+}
+
+// stringsNotContained returns strings in source that don't appear in target
+func stringsNotContained(source []string, target []string) []string {
+	targetSet := map[string]bool{}
+	for _, t := range target {
+		targetSet[t] = true
+	}
+	result := []string{}
+	for _, s := range source {
+		if !targetSet[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// policyReferencesNotContained returns policy references in source that don't appear in target
+func policyReferencesNotContained(source []PolicyReference, target []PolicyReference) []PolicyReference {
+	targetSet := map[PolicyReference]bool{}
+	for _, t := range target {
+		targetSet[t] = true
+	}
+	result := []PolicyReference{}
+	for _, s := range source {
+		if !targetSet[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func isEqualStringSet(a1 []string, a2 []string) bool {
+	return len(stringsNotContained(a1, a2)) == 0 && len(stringsNotContained(a2, a1)) == 0
+}
+
+func isEqualStatementArray(s1 []Statement, s2 []Statement) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if !isEqualStatement(s1[i], s2[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Aux method to transform errors returned by a transaction that applies state changes
+func transactionError(err error) error {
+	switch e := err.(type) {
+	case *Error:
+		return e
+	case *database.Error:
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: e.Message,
+		}
+	default:
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: err.Error(),
+		}
+	}
+}
+
+// Aux method to transform repository errors
+func dbErrorToAPIError(err error) error {
+	//Transform to DB error
+	dbError := err.(*database.Error)
+	return &Error{
+		Code:    UNKNOWN_API_ERROR,
+		Message: dbError.Message,
+	}
+}
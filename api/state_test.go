@@ -0,0 +1,789 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerAPI_ExportState(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		// Expected results
+		expectedState *State
+		wantError     error
+		// Manager Results
+		getOrganizationsFilteredResult []Organization
+		getUsersFilteredResult         []User
+		getAttachedUserPoliciesResult  []TestPolicyUserRelation
+		getGroupsFilteredResult        []Group
+		getGroupMembersResult          []TestUserGroupRelation
+		getSubgroupsResult             []TestGroupSubgroupRelation
+		getAttachedPoliciesResult      []TestPolicyGroupRelation
+		getPoliciesFilteredResult      []Policy
+		getProxyResourcesResult        []ProxyResource
+		getOidcProvidersFilteredResult []OidcProvider
+		// Manager Errors
+		getUsersFilteredErr error
+	}{
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			getOrganizationsFilteredResult: []Organization{
+				{
+					ID:   "ORG-ID",
+					Name: "org1",
+					Path: "/path/",
+				},
+			},
+			getUsersFilteredResult: []User{
+				{
+					ID:         "USER-ID",
+					ExternalID: "user1",
+					Path:       "/path/",
+				},
+			},
+			getAttachedUserPoliciesResult: []TestPolicyUserRelation{
+				{
+					Policy: &Policy{
+						Name: "policy1",
+						Org:  "org1",
+					},
+				},
+			},
+			getGroupsFilteredResult: []Group{
+				{
+					ID:   "GROUP-ID",
+					Name: "group1",
+					Org:  "org1",
+					Path: "/path/",
+				},
+			},
+			getGroupMembersResult: []TestUserGroupRelation{
+				{
+					User: &User{
+						ExternalID: "user1",
+					},
+				},
+			},
+			getSubgroupsResult: []TestGroupSubgroupRelation{
+				{
+					Subgroup: &Group{
+						Name: "group2",
+						Org:  "org1",
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						Name: "policy1",
+						Org:  "org1",
+					},
+				},
+			},
+			getPoliciesFilteredResult: []Policy{
+				{
+					ID:   "POLICY-ID",
+					Name: "policy1",
+					Org:  "org1",
+					Path: "/path/",
+					Statements: &[]Statement{
+						{
+							Effect:    "allow",
+							Actions:   []string{USER_ACTION_GET_USER},
+							Resources: []string{GetUrnPrefix("", RESOURCE_USER, "/path/")},
+						},
+					},
+				},
+			},
+			getProxyResourcesResult: []ProxyResource{
+				{
+					ID:   "PROXY-ID",
+					Name: "proxy1",
+					Org:  "org1",
+					Path: "/path/",
+					Resource: ResourceEntity{
+						Host:   "http://host.com",
+						Path:   "/mypath",
+						Method: "GET",
+						Urn:    "urn:ews:example:instance1:resource/mypath",
+						Action: "example:One",
+					},
+				},
+			},
+			getOidcProvidersFilteredResult: []OidcProvider{
+				{
+					ID:        "OIDC-ID",
+					Name:      "oidc1",
+					Path:      "/path/",
+					IssuerURL: "https://accounts.google.com",
+					OidcClients: []OidcClient{
+						{
+							Name: "client1",
+						},
+					},
+				},
+			},
+			expectedState: &State{
+				Version: STATE_VERSION,
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path/",
+					},
+				},
+				Users: []UserState{
+					{
+						ExternalID: "user1",
+						Path:       "/path/",
+						Policies: []PolicyReference{
+							{
+								Org:  "org1",
+								Name: "policy1",
+							},
+						},
+					},
+				},
+				Groups: []GroupState{
+					{
+						Org:       "org1",
+						Name:      "group1",
+						Path:      "/path/",
+						Members:   []string{"user1"},
+						Subgroups: []string{"group2"},
+						Policies:  []string{"policy1"},
+					},
+				},
+				Policies: []PolicyState{
+					{
+						Org:  "org1",
+						Name: "policy1",
+						Path: "/path/",
+						Statements: []Statement{
+							{
+								Effect:    "allow",
+								Actions:   []string{USER_ACTION_GET_USER},
+								Resources: []string{GetUrnPrefix("", RESOURCE_USER, "/path/")},
+							},
+						},
+					},
+				},
+				ProxyResources: []ProxyResourceState{
+					{
+						Org:  "org1",
+						Name: "proxy1",
+						Path: "/path/",
+						Resource: ResourceEntity{
+							Host:   "http://host.com",
+							Path:   "/mypath",
+							Method: "GET",
+							Urn:    "urn:ews:example:instance1:resource/mypath",
+							Action: "example:One",
+						},
+					},
+				},
+				OidcProviders: []OidcProviderState{
+					{
+						Name:      "oidc1",
+						Path:      "/path/",
+						IssuerURL: "https://accounts.google.com",
+						Clients:   []string{"client1"},
+					},
+				},
+			},
+		},
+		"ErrorCaseNotAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to export IAM state",
+			},
+		},
+		"ErrorCaseDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			getUsersFilteredErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetOrganizationsFilteredMethod][0] = test.getOrganizationsFilteredResult
+		testRepo.ArgsOut[GetUsersFilteredMethod][0] = test.getUsersFilteredResult
+		testRepo.ArgsOut[GetUsersFilteredMethod][2] = test.getUsersFilteredErr
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][0] = test.getAttachedUserPoliciesResult
+		testRepo.ArgsOut[GetGroupsFilteredMethod][0] = test.getGroupsFilteredResult
+		testRepo.ArgsOut[GetGroupMembersMethod][0] = test.getGroupMembersResult
+		testRepo.ArgsOut[GetSubgroupsMethod][0] = test.getSubgroupsResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = test.getAttachedPoliciesResult
+		testRepo.ArgsOut[GetPoliciesFilteredMethod][0] = test.getPoliciesFilteredResult
+		testRepo.ArgsOut[GetProxyResourcesMethod][0] = test.getProxyResourcesResult
+		testRepo.ArgsOut[GetOidcProvidersFilteredMethod][0] = test.getOidcProvidersFilteredResult
+
+		state, err := testAPI.ExportState(test.requestInfo)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedState, state)
+	}
+}
+
+func TestWorkerAPI_ImportState(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo  RequestInfo
+		state        *State
+		mode         string
+		validateOnly bool
+		// Expected results
+		expectedMode    string
+		expectedChanges []string
+		wantError       error
+		// Manager Results
+		addOrganizationResult *Organization
+		// Manager Errors
+		getOrganizationByNameErr error
+		addOrganizationErr       error
+		withTransactionErr       error
+	}{
+		"OkCaseMerge": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path/",
+					},
+				},
+			},
+			getOrganizationByNameErr: &database.Error{
+				Code: database.ORGANIZATION_NOT_FOUND,
+			},
+			addOrganizationResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+			},
+			expectedMode: IMPORT_MODE_MERGE,
+			expectedChanges: []string{
+				"create urn:iws:iam::org/path/org1",
+			},
+		},
+		"OkCaseReplaceValidateOnly": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path/",
+					},
+				},
+			},
+			mode:         IMPORT_MODE_REPLACE,
+			validateOnly: true,
+			getOrganizationByNameErr: &database.Error{
+				Code: database.ORGANIZATION_NOT_FOUND,
+			},
+			addOrganizationResult: &Organization{
+				ID:   "ORG-ID",
+				Name: "org1",
+				Path: "/path/",
+			},
+			expectedMode: IMPORT_MODE_REPLACE,
+			expectedChanges: []string{
+				"create urn:iws:iam::org/path/org1",
+			},
+		},
+		"ErrorCaseNotAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to import IAM state",
+			},
+		},
+		"ErrorCaseInvalidMode": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+			},
+			mode: "mode",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: mode mode",
+			},
+		},
+		"ErrorCaseInvalidVersion": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: "v0",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: version v0, supported version: v1",
+			},
+		},
+		"ErrorCaseDuplicatedResource": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+				Users: []UserState{
+					{
+						ExternalID: "user1",
+						Path:       "/path/",
+					},
+					{
+						ExternalID: "user1",
+						Path:       "/path2/",
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: resource urn:iws:iam::user/user1 is duplicated",
+			},
+		},
+		"ErrorCaseChangeFails": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path/",
+					},
+				},
+			},
+			getOrganizationByNameErr: &database.Error{
+				Code: database.ORGANIZATION_NOT_FOUND,
+			},
+			addOrganizationErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseTransactionError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			state: &State{
+				Version: STATE_VERSION,
+			},
+			withTransactionErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetOrganizationByNameMethod][1] = test.getOrganizationByNameErr
+		testRepo.ArgsOut[AddOrganizationMethod][0] = test.addOrganizationResult
+		testRepo.ArgsOut[AddOrganizationMethod][1] = test.addOrganizationErr
+		testRepo.ArgsOut[WithTransactionMethod][0] = test.withTransactionErr
+
+		result, err := testAPI.ImportState(test.requestInfo, test.state, test.mode, test.validateOnly)
+		if test.wantError != nil {
+			checkMethodResponse(t, n, test.wantError, err, nil, result)
+			continue
+		}
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedMode, result.Mode, "Error in test case %v", n)
+		assert.Equal(t, test.validateOnly, result.ValidateOnly, "Error in test case %v", n)
+		assert.Equal(t, test.expectedChanges, stateChangesToStrings(result.Changes), "Error in test case %v", n)
+	}
+}
+
+func Test_planStateChanges(t *testing.T) {
+	statements := []Statement{
+		{
+			Effect:    "allow",
+			Actions:   []string{USER_ACTION_GET_USER},
+			Resources: []string{GetUrnPrefix("", RESOURCE_USER, "/path/")},
+		},
+	}
+	testcases := map[string]struct {
+		current         *State
+		desired         *State
+		removeEntities  bool
+		removeRelations bool
+		expectedChanges []string
+		expectedDetails [][]string
+	}{
+		"OkCaseMergeKeepsUnmanagedEntities": {
+			current: &State{
+				Users: []UserState{
+					{
+						ExternalID: "user2",
+						Path:       "/path/",
+					},
+				},
+				Groups: []GroupState{
+					{
+						Org:     "org1",
+						Name:    "group1",
+						Path:    "/path/",
+						Members: []string{"user2"},
+					},
+				},
+			},
+			desired: &State{
+				Users: []UserState{
+					{
+						ExternalID: "user1",
+						Path:       "/path/",
+					},
+				},
+			},
+			expectedChanges: []string{
+				"create urn:iws:iam::user/path/user1",
+			},
+		},
+		"OkCaseReplaceRemovesUnmanagedEntities": {
+			current: &State{
+				Users: []UserState{
+					{
+						ExternalID: "user2",
+						Path:       "/path/",
+					},
+				},
+				Groups: []GroupState{
+					{
+						Org:     "org1",
+						Name:    "group1",
+						Path:    "/path/",
+						Members: []string{"user2"},
+					},
+				},
+			},
+			desired: &State{
+				Users: []UserState{
+					{
+						ExternalID: "user1",
+						Path:       "/path/",
+					},
+				},
+			},
+			removeEntities:  true,
+			removeRelations: true,
+			expectedChanges: []string{
+				"delete urn:iws:iam:org1:group/path/group1",
+				"delete urn:iws:iam::user/path/user2",
+				"create urn:iws:iam::user/path/user1",
+			},
+		},
+		"OkCaseReplaceRelations": {
+			current: &State{
+				Users: []UserState{
+					{
+						ExternalID: "user1",
+						Path:       "/path/",
+						Policies: []PolicyReference{
+							{
+								Org:  "org1",
+								Name: "policy1",
+							},
+						},
+					},
+					{
+						ExternalID: "user2",
+						Path:       "/path/",
+					},
+				},
+				Groups: []GroupState{
+					{
+						Org:       "org1",
+						Name:      "group1",
+						Path:      "/path/",
+						Members:   []string{"user1"},
+						Subgroups: []string{"group2"},
+					},
+					{
+						Org:  "org1",
+						Name: "group2",
+						Path: "/path/",
+					},
+				},
+				Policies: []PolicyState{
+					{
+						Org:        "org1",
+						Name:       "policy1",
+						Path:       "/path/",
+						Statements: statements,
+					},
+				},
+			},
+			desired: &State{
+				Users: []UserState{
+					{
+						ExternalID: "user1",
+						Path:       "/path/",
+					},
+					{
+						ExternalID: "user2",
+						Path:       "/path/",
+						Policies: []PolicyReference{
+							{
+								Org:  "org1",
+								Name: "policy1",
+							},
+						},
+					},
+				},
+				Groups: []GroupState{
+					{
+						Org:      "org1",
+						Name:     "group1",
+						Path:     "/path2/",
+						Members:  []string{"user2"},
+						Policies: []string{"policy1"},
+					},
+					{
+						Org:       "org1",
+						Name:      "group2",
+						Path:      "/path/",
+						Subgroups: []string{"group1"},
+					},
+				},
+				Policies: []PolicyState{
+					{
+						Org:        "org1",
+						Name:       "policy1",
+						Path:       "/path/",
+						Statements: statements,
+					},
+				},
+			},
+			removeEntities:  true,
+			removeRelations: true,
+			expectedChanges: []string{
+				"removeMember urn:iws:iam:org1:group/path2/group1 user1",
+				"removeSubgroup urn:iws:iam:org1:group/path2/group1 group2",
+				"detachPolicy urn:iws:iam::user/path/user1 org1/policy1",
+				"update urn:iws:iam:org1:group/path2/group1",
+				"addMember urn:iws:iam:org1:group/path2/group1 user2",
+				"attachPolicy urn:iws:iam:org1:group/path2/group1 policy1",
+				"addSubgroup urn:iws:iam:org1:group/path/group2 group1",
+				"attachPolicy urn:iws:iam::user/path/user2 org1/policy1",
+			},
+		},
+		"OkCaseUpdateEntities": {
+			current: &State{
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path/",
+					},
+				},
+				Policies: []PolicyState{
+					{
+						Org:        "org1",
+						Name:       "policy1",
+						Path:       "/path/",
+						Statements: statements,
+					},
+					{
+						Org:        "org1",
+						Name:       "policy2",
+						Path:       "/path/",
+						Statements: statements,
+					},
+				},
+				ProxyResources: []ProxyResourceState{
+					{
+						Org:  "org1",
+						Name: "proxy1",
+						Path: "/path/",
+						Resource: ResourceEntity{
+							Host:   "http://host.com",
+							Path:   "/mypath",
+							Method: "GET",
+						},
+					},
+				},
+				OidcProviders: []OidcProviderState{
+					{
+						Name:      "oidc1",
+						Path:      "/path/",
+						IssuerURL: "https://accounts.google.com",
+						Clients:   []string{"client1", "client2"},
+					},
+				},
+			},
+			desired: &State{
+				Organizations: []OrganizationState{
+					{
+						Name: "org1",
+						Path: "/path2/",
+					},
+				},
+				Policies: []PolicyState{
+					{
+						Org:        "org1",
+						Name:       "policy1",
+						Path:       "/path/",
+						Statements: statements,
+					},
+					{
+						Org:  "org1",
+						Name: "policy2",
+						Path: "/path/",
+						Statements: []Statement{
+							{
+								Effect:    "deny",
+								Actions:   []string{USER_ACTION_GET_USER},
+								Resources: []string{GetUrnPrefix("", RESOURCE_USER, "/path/")},
+							},
+						},
+					},
+				},
+				ProxyResources: []ProxyResourceState{
+					{
+						Org:  "org1",
+						Name: "proxy1",
+						Path: "/path/",
+						Resource: ResourceEntity{
+							Host:   "http://host.com",
+							Path:   "/mypath",
+							Method: "POST",
+						},
+					},
+				},
+				OidcProviders: []OidcProviderState{
+					{
+						Name:      "oidc1",
+						Path:      "/path/",
+						IssuerURL: "https://accounts.google.com",
+						Clients:   []string{"client2", "client1"},
+					},
+				},
+			},
+			expectedChanges: []string{
+				"update urn:iws:iam::org/path2/org1",
+				"update urn:iws:iam:org1:policy/path/policy2",
+				"update urn:iws:iam:org1:proxy/path/proxy1",
+			},
+			expectedDetails: [][]string{
+				{"path: /path/ -> /path2/"},
+				{"statements: 1 added, 1 removed"},
+				{"method: GET -> POST"},
+			},
+		},
+		"OkCaseRemoveRelationsKeepsUnmanagedEntities": {
+			current: &State{
+				Groups: []GroupState{
+					{
+						Org:      "org1",
+						Name:     "group1",
+						Path:     "/path/",
+						Members:  []string{"user1", "user2"},
+						Policies: []string{"policy1"},
+					},
+					{
+						Org:     "org1",
+						Name:    "group2",
+						Path:    "/path/",
+						Members: []string{"user1"},
+					},
+				},
+				Policies: []PolicyState{
+					{
+						Org:        "org1",
+						Name:       "policy1",
+						Path:       "/path/",
+						Statements: statements,
+					},
+				},
+			},
+			desired: &State{
+				Groups: []GroupState{
+					{
+						Org:     "org1",
+						Name:    "group1",
+						Path:    "/path/",
+						Members: []string{"user2"},
+					},
+				},
+			},
+			removeRelations: true,
+			expectedChanges: []string{
+				"removeMember urn:iws:iam:org1:group/path/group1 user1",
+				"detachPolicy urn:iws:iam:org1:group/path/group1 policy1",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		changes := planStateChanges(test.current, test.desired, test.removeEntities, test.removeRelations)
+		assert.Equal(t, test.expectedChanges, stateChangesToStrings(changes), "Error in test case %v", n)
+		if test.expectedDetails != nil {
+			details := [][]string{}
+			for _, c := range changes {
+				details = append(details, c.Details)
+			}
+			assert.Equal(t, test.expectedDetails, details, "Error in test case %v", n)
+		}
+	}
+}
+
+// Aux method to compare state changes, which contain functions
+func stateChangesToStrings(changes []StateChange) []string {
+	result := []string{}
+	for _, c := range changes {
+		result = append(result, c.String())
+	}
+	return result
+}
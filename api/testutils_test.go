@@ -12,42 +12,83 @@ import (
 )
 
 const (
-	GetUserByExternalIDMethod      = "GetUserByExternalID"
-	AddUserMethod                  = "AddUser"
-	UpdateUserMethod               = "UpdateUser"
-	GetUsersFilteredMethod         = "GetUsersFiltered"
-	GetGroupsByUserIDMethod        = "GetGroupsByUserID"
-	RemoveUserMethod               = "RemoveUser"
-	GetGroupByNameMethod           = "GetGroupByName"
-	IsMemberOfGroupMethod          = "IsMemberOfGroup"
-	GetGroupMembersMethod          = "GetGroupMembers"
-	IsAttachedToGroupMethod        = "IsAttachedToGroup"
-	GetAttachedPoliciesMethod      = "GetAttachedPolicies"
-	GetGroupsFilteredMethod        = "GetGroupsFiltered"
-	RemoveGroupMethod              = "RemoveGroup"
-	AddGroupMethod                 = "AddGroup"
-	AddMemberMethod                = "AddMember"
-	RemoveMemberMethod             = "RemoveMember"
-	UpdateGroupMethod              = "UpdateGroup"
-	AttachPolicyMethod             = "AttachPolicy"
-	DetachPolicyMethod             = "DetachPolicy"
-	GetPolicyByNameMethod          = "GetPolicyByName"
-	AddPolicyMethod                = "AddPolicy"
-	UpdatePolicyMethod             = "UpdatePolicy"
-	RemovePolicyMethod             = "RemovePolicy"
-	GetPoliciesFilteredMethod      = "GetPoliciesFiltered"
-	GetAttachedGroupsMethod        = "GetAttachedGroups"
-	OrderByValidColumnsMethod      = "OrderByValidColumns"
-	GetProxyResourcesMethod        = "GetProxyResources"
-	RemoveProxyResourceMethod      = "RemoveProxyResource"
-	AddProxyResourceMethod         = "AddProxyResource"
-	UpdateProxyResourceMethod      = "UpdateProxyResource"
-	GetProxyResourceByNameMethod   = "GetProxyResourceByName"
-	AddOidcProviderMethod          = "AddOidcProvider"
-	GetOidcProviderByNameMethod    = "GetOidcProviderByName"
-	GetOidcProvidersFilteredMethod = "GetOidcProvidersFiltered"
-	UpdateOidcProviderMethod       = "UpdateOidcProvider"
-	RemoveOidcProviderMethod       = "RemoveOidcProviderMethod"
+	GetUserByExternalIDMethod        = "GetUserByExternalID"
+	AddUserMethod                    = "AddUser"
+	UpdateUserMethod                 = "UpdateUser"
+	GetUsersFilteredMethod           = "GetUsersFiltered"
+	GetGroupsByUserIDMethod          = "GetGroupsByUserID"
+	RemoveUserMethod                 = "RemoveUser"
+	GetDeletedUserByExternalIDMethod = "GetDeletedUserByExternalID"
+	RestoreUserMethod                = "RestoreUser"
+	PurgeUsersMethod                 = "PurgeUsers"
+	AttachPolicyToUserMethod         = "AttachPolicyToUser"
+	DetachPolicyFromUserMethod       = "DetachPolicyFromUser"
+	IsAttachedToUserMethod           = "IsAttachedToUser"
+	GetAttachedUserPoliciesMethod    = "GetAttachedUserPolicies"
+	GetGroupByNameMethod             = "GetGroupByName"
+	IsMemberOfGroupMethod            = "IsMemberOfGroup"
+	GetGroupMembersMethod            = "GetGroupMembers"
+	AddSubgroupMethod                = "AddSubgroup"
+	RemoveSubgroupMethod             = "RemoveSubgroup"
+	IsSubgroupOfGroupMethod          = "IsSubgroupOfGroup"
+	GetSubgroupsMethod               = "GetSubgroups"
+	GetParentGroupsMethod            = "GetParentGroups"
+	GetParentGroupIDsMethod          = "GetParentGroupIDs"
+	LockGroupHierarchyMethod         = "LockGroupHierarchy"
+	IsAttachedToGroupMethod          = "IsAttachedToGroup"
+	GetAttachedPoliciesMethod        = "GetAttachedPolicies"
+	GetGroupsFilteredMethod          = "GetGroupsFiltered"
+	RemoveGroupMethod                = "RemoveGroup"
+	GetDeletedGroupByNameMethod      = "GetDeletedGroupByName"
+	RestoreGroupMethod               = "RestoreGroup"
+	PurgeGroupsMethod                = "PurgeGroups"
+	AddGroupMethod                   = "AddGroup"
+	AddMemberMethod                  = "AddMember"
+	RemoveMemberMethod               = "RemoveMember"
+	UpdateGroupMethod                = "UpdateGroup"
+	AttachPolicyMethod               = "AttachPolicy"
+	DetachPolicyMethod               = "DetachPolicy"
+	GetPolicyByNameMethod            = "GetPolicyByName"
+	AddPolicyMethod                  = "AddPolicy"
+	UpdatePolicyMethod               = "UpdatePolicy"
+	RemovePolicyMethod               = "RemovePolicy"
+	GetDeletedPolicyByNameMethod     = "GetDeletedPolicyByName"
+	RestorePolicyMethod              = "RestorePolicy"
+	PurgePoliciesMethod              = "PurgePolicies"
+	GetPoliciesFilteredMethod        = "GetPoliciesFiltered"
+	GetAttachedGroupsMethod          = "GetAttachedGroups"
+	GetPolicyVersionsMethod          = "GetPolicyVersions"
+	GetPolicyVersionMethod           = "GetPolicyVersion"
+	SetDefaultPolicyVersionMethod    = "SetDefaultPolicyVersion"
+	OrderByValidColumnsMethod        = "OrderByValidColumns"
+	GetProxyResourcesMethod          = "GetProxyResources"
+	RemoveProxyResourceMethod        = "RemoveProxyResource"
+	AddProxyResourceMethod           = "AddProxyResource"
+	UpdateProxyResourceMethod        = "UpdateProxyResource"
+	GetProxyResourceByNameMethod     = "GetProxyResourceByName"
+	AddOidcProviderMethod            = "AddOidcProvider"
+	GetOidcProviderByNameMethod      = "GetOidcProviderByName"
+	GetOidcProvidersFilteredMethod   = "GetOidcProvidersFiltered"
+	UpdateOidcProviderMethod         = "UpdateOidcProvider"
+	RemoveOidcProviderMethod         = "RemoveOidcProviderMethod"
+	AddOrganizationMethod            = "AddOrganization"
+	GetOrganizationByNameMethod      = "GetOrganizationByName"
+	GetOrganizationsFilteredMethod   = "GetOrganizationsFiltered"
+	UpdateOrganizationMethod         = "UpdateOrganization"
+	RemoveOrganizationMethod         = "RemoveOrganization"
+	WithTransactionMethod            = "WithTransaction"
+
+	// Webhook repo methods
+	AddWebhookMethod             = "AddWebhook"
+	GetWebhookByNameMethod       = "GetWebhookByName"
+	GetWebhooksFilteredMethod    = "GetWebhooksFiltered"
+	UpdateWebhookMethod          = "UpdateWebhook"
+	RemoveWebhookMethod          = "RemoveWebhook"
+	AddWebhookDeliveriesMethod   = "AddWebhookDeliveries"
+	GetWebhookDeliveriesMethod   = "GetWebhookDeliveries"
+	ClaimWebhookDeliveriesMethod = "ClaimWebhookDeliveries"
+	UpdateWebhookDeliveryMethod  = "UpdateWebhookDelivery"
+	PurgeWebhookDeliveriesMethod = "PurgeWebhookDeliveries"
 )
 
 // TestRepo that implements all repo manager interfaces
@@ -69,6 +110,18 @@ type TestPolicyGroupRelation struct {
 	CreateAt time.Time
 }
 
+type TestGroupSubgroupRelation struct {
+	Group    *Group
+	Subgroup *Group
+	CreateAt time.Time
+}
+
+type TestPolicyUserRelation struct {
+	User     *User
+	Policy   *Policy
+	CreateAt time.Time
+}
+
 var testFilter = Filter{
 	PathPrefix: "",
 	Org:        "",
@@ -91,14 +144,31 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsIn[UpdateUserMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[GetUsersFilteredMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[GetGroupsByUserIDMethod] = make([]interface{}, 2)
-	testRepo.ArgsIn[RemoveUserMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[RemoveUserMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetDeletedUserByExternalIDMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[RestoreUserMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[PurgeUsersMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[AttachPolicyToUserMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[DetachPolicyFromUserMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[IsAttachedToUserMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetAttachedUserPoliciesMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetGroupByNameMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[IsMemberOfGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetGroupMembersMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[AddSubgroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[RemoveSubgroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[IsSubgroupOfGroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetSubgroupsMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetParentGroupsMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetParentGroupIDsMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[LockGroupHierarchyMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[IsAttachedToGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetAttachedPoliciesMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetGroupsFilteredMethod] = make([]interface{}, 1)
-	testRepo.ArgsIn[RemoveGroupMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[RemoveGroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetDeletedGroupByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[RestoreGroupMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[PurgeGroupsMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[AddGroupMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[AddMemberMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[RemoveMemberMethod] = make([]interface{}, 2)
@@ -106,11 +176,17 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsIn[AttachPolicyMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[DetachPolicyMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetPolicyByNameMethod] = make([]interface{}, 2)
-	testRepo.ArgsIn[AddPolicyMethod] = make([]interface{}, 1)
-	testRepo.ArgsIn[UpdatePolicyMethod] = make([]interface{}, 1)
-	testRepo.ArgsIn[RemovePolicyMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[AddPolicyMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[UpdatePolicyMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[RemovePolicyMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetDeletedPolicyByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[RestorePolicyMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[PurgePoliciesMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[GetPoliciesFilteredMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[GetAttachedGroupsMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetPolicyVersionsMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetPolicyVersionMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[SetDefaultPolicyVersionMethod] = make([]interface{}, 4)
 	testRepo.ArgsIn[OrderByValidColumnsMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[GetProxyResourcesMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[RemoveProxyResourceMethod] = make([]interface{}, 2)
@@ -122,6 +198,21 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsIn[GetOidcProvidersFilteredMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[UpdateOidcProviderMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[RemoveOidcProviderMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[AddOrganizationMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetOrganizationByNameMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetOrganizationsFilteredMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[UpdateOrganizationMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[RemoveOrganizationMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[AddWebhookMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetWebhookByNameMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetWebhooksFilteredMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[UpdateWebhookMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[RemoveWebhookMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[AddWebhookDeliveriesMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetWebhookDeliveriesMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[ClaimWebhookDeliveriesMethod] = make([]interface{}, 3)
+	testRepo.ArgsIn[UpdateWebhookDeliveryMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[PurgeWebhookDeliveriesMethod] = make([]interface{}, 1)
 
 	testRepo.ArgsOut[GetUserByExternalIDMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[AddUserMethod] = make([]interface{}, 2)
@@ -129,13 +220,30 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsOut[GetUsersFilteredMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[GetGroupsByUserIDMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[RemoveUserMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[GetDeletedUserByExternalIDMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[RestoreUserMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[PurgeUsersMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[AttachPolicyToUserMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[DetachPolicyFromUserMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[IsAttachedToUserMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetAttachedUserPoliciesMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[GetGroupByNameMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[IsMemberOfGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[GetGroupMembersMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[AddSubgroupMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[RemoveSubgroupMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[IsSubgroupOfGroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetSubgroupsMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[GetParentGroupsMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetParentGroupIDsMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[LockGroupHierarchyMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[IsAttachedToGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[GetAttachedPoliciesMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[GetGroupsFilteredMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[RemoveGroupMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[GetDeletedGroupByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[RestoreGroupMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[PurgeGroupsMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[AddGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[AddMemberMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[RemoveMemberMethod] = make([]interface{}, 1)
@@ -146,8 +254,14 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsOut[AddPolicyMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[UpdatePolicyMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[RemovePolicyMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[GetDeletedPolicyByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[RestorePolicyMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[PurgePoliciesMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[GetPoliciesFilteredMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[GetAttachedGroupsMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[GetPolicyVersionsMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[GetPolicyVersionMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[SetDefaultPolicyVersionMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[OrderByValidColumnsMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[GetProxyResourcesMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[RemoveProxyResourceMethod] = make([]interface{}, 1)
@@ -159,17 +273,36 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsOut[GetOidcProvidersFilteredMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[UpdateOidcProviderMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[RemoveOidcProviderMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[AddOrganizationMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetOrganizationByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetOrganizationsFilteredMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[UpdateOrganizationMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[RemoveOrganizationMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[WithTransactionMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[AddWebhookMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetWebhookByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetWebhooksFilteredMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[UpdateWebhookMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[RemoveWebhookMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[AddWebhookDeliveriesMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[GetWebhookDeliveriesMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[ClaimWebhookDeliveriesMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[UpdateWebhookDeliveryMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[PurgeWebhookDeliveriesMethod] = make([]interface{}, 2)
 
 	return testRepo
 }
 
 func makeTestAPI(testRepo *TestRepo) *WorkerAPI {
 	api := &WorkerAPI{
-		UserRepo:     testRepo,
-		GroupRepo:    testRepo,
-		PolicyRepo:   testRepo,
-		ProxyRepo:    testRepo,
-		AuthOidcRepo: testRepo,
+		UserRepo:         testRepo,
+		GroupRepo:        testRepo,
+		PolicyRepo:       testRepo,
+		ProxyRepo:        testRepo,
+		AuthOidcRepo:     testRepo,
+		OrganizationRepo: testRepo,
+		TransactionRepo:  testRepo,
+		WebhookRepo:      testRepo,
 	}
 	Log = &log.Logger{
 		Out:       bytes.NewBuffer([]byte{}),
@@ -225,6 +358,30 @@ func (t TestPolicyGroupRelation) GetDate() time.Time {
 	return t.CreateAt
 }
 
+func (t TestGroupSubgroupRelation) GetGroup() *Group {
+	return t.Group
+}
+
+func (t TestGroupSubgroupRelation) GetSubgroup() *Group {
+	return t.Subgroup
+}
+
+func (t TestGroupSubgroupRelation) GetDate() time.Time {
+	return t.CreateAt
+}
+
+func (t TestPolicyUserRelation) GetPolicy() *Policy {
+	return t.Policy
+}
+
+func (t TestPolicyUserRelation) GetUser() *User {
+	return t.User
+}
+
+func (t TestPolicyUserRelation) GetDate() time.Time {
+	return t.CreateAt
+}
+
 //////////////////
 // User repo
 //////////////////
@@ -310,8 +467,9 @@ func (t TestRepo) GetGroupsByUserID(id string, filter *Filter) ([]UserGroupRelat
 	return groups, total, err
 }
 
-func (t TestRepo) RemoveUser(id string) error {
+func (t TestRepo) RemoveUser(id string, revision int) error {
 	t.ArgsIn[RemoveUserMethod][0] = id
+	t.ArgsIn[RemoveUserMethod][1] = revision
 	var err error
 	if t.ArgsOut[RemoveUserMethod][0] != nil {
 		err = t.ArgsOut[RemoveUserMethod][0].(error)
@@ -319,6 +477,97 @@ func (t TestRepo) RemoveUser(id string) error {
 	return err
 }
 
+func (t TestRepo) GetDeletedUserByExternalID(id string) (*User, error) {
+	t.ArgsIn[GetDeletedUserByExternalIDMethod][0] = id
+	var user *User
+	if t.ArgsOut[GetDeletedUserByExternalIDMethod][0] != nil {
+		user = t.ArgsOut[GetDeletedUserByExternalIDMethod][0].(*User)
+	}
+	var err error
+	if t.ArgsOut[GetDeletedUserByExternalIDMethod][1] != nil {
+		err = t.ArgsOut[GetDeletedUserByExternalIDMethod][1].(error)
+	}
+	return user, err
+}
+
+func (t TestRepo) RestoreUser(id string) error {
+	t.ArgsIn[RestoreUserMethod][0] = id
+	var err error
+	if t.ArgsOut[RestoreUserMethod][0] != nil {
+		err = t.ArgsOut[RestoreUserMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) PurgeUsers(deleteBefore time.Time) (int, error) {
+	t.ArgsIn[PurgeUsersMethod][0] = deleteBefore
+	var purged int
+	if t.ArgsOut[PurgeUsersMethod][0] != nil {
+		purged = t.ArgsOut[PurgeUsersMethod][0].(int)
+	}
+	var err error
+	if t.ArgsOut[PurgeUsersMethod][1] != nil {
+		err = t.ArgsOut[PurgeUsersMethod][1].(error)
+	}
+	return purged, err
+}
+
+func (t TestRepo) AttachPolicyToUser(userID string, policyID string) error {
+	t.ArgsIn[AttachPolicyToUserMethod][0] = userID
+	t.ArgsIn[AttachPolicyToUserMethod][1] = policyID
+	var err error
+	if t.ArgsOut[AttachPolicyToUserMethod][0] != nil {
+		err = t.ArgsOut[AttachPolicyToUserMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) DetachPolicyFromUser(userID string, policyID string) error {
+	t.ArgsIn[DetachPolicyFromUserMethod][0] = userID
+	t.ArgsIn[DetachPolicyFromUserMethod][1] = policyID
+	var err error
+	if t.ArgsOut[DetachPolicyFromUserMethod][0] != nil {
+		err = t.ArgsOut[DetachPolicyFromUserMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) IsAttachedToUser(userID string, policyID string) (bool, error) {
+	t.ArgsIn[IsAttachedToUserMethod][0] = userID
+	t.ArgsIn[IsAttachedToUserMethod][1] = policyID
+	var isAttached bool
+	if t.ArgsOut[IsAttachedToUserMethod][0] != nil {
+		isAttached = t.ArgsOut[IsAttachedToUserMethod][0].(bool)
+	}
+	var err error
+	if t.ArgsOut[IsAttachedToUserMethod][1] != nil {
+		err = t.ArgsOut[IsAttachedToUserMethod][1].(error)
+	}
+	return isAttached, err
+}
+
+func (t TestRepo) GetAttachedUserPolicies(userID string, filter *Filter) ([]PolicyUserRelation, int, error) {
+	t.ArgsIn[GetAttachedUserPoliciesMethod][0] = userID
+	t.ArgsIn[GetAttachedUserPoliciesMethod][1] = filter
+	var policies []PolicyUserRelation
+	if t.ArgsOut[GetAttachedUserPoliciesMethod][0] != nil {
+		testPolicies := t.ArgsOut[GetAttachedUserPoliciesMethod][0].([]TestPolicyUserRelation)
+		for _, v := range testPolicies {
+			policies = append(policies, v)
+		}
+	}
+	var total int
+	if t.ArgsOut[GetAttachedUserPoliciesMethod][1] != nil {
+		total = t.ArgsOut[GetAttachedUserPoliciesMethod][1].(int)
+	}
+
+	var err error
+	if t.ArgsOut[GetAttachedUserPoliciesMethod][2] != nil {
+		err = t.ArgsOut[GetAttachedUserPoliciesMethod][2].(error)
+	}
+	return policies, total, err
+}
+
 //////////////////
 // Group repo
 //////////////////
@@ -356,6 +605,9 @@ func (t TestRepo) IsMemberOfGroup(userID string, groupID string) (bool, error) {
 
 func (t TestRepo) GetGroupMembers(groupID string, filter *Filter) ([]UserGroupRelation, int, error) {
 	t.ArgsIn[GetGroupMembersMethod][0] = groupID
+	if specialFunc, ok := t.SpecialFuncs[GetGroupMembersMethod].(func(groupID string, filter *Filter) ([]UserGroupRelation, int, error)); ok && specialFunc != nil {
+		return specialFunc(groupID, filter)
+	}
 	var members []UserGroupRelation
 	if t.ArgsOut[GetGroupMembersMethod][0] != nil {
 		testMembers := t.ArgsOut[GetGroupMembersMethod][0].([]TestUserGroupRelation)
@@ -389,6 +641,105 @@ func (t TestRepo) IsAttachedToGroup(groupID string, policyID string) (bool, erro
 	return isAttached, err
 }
 
+func (t TestRepo) AddSubgroup(groupID string, subgroupID string) error {
+	t.ArgsIn[AddSubgroupMethod][0] = groupID
+	t.ArgsIn[AddSubgroupMethod][1] = subgroupID
+	var err error
+	if t.ArgsOut[AddSubgroupMethod][0] != nil {
+		err = t.ArgsOut[AddSubgroupMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) RemoveSubgroup(groupID string, subgroupID string) error {
+	t.ArgsIn[RemoveSubgroupMethod][0] = groupID
+	t.ArgsIn[RemoveSubgroupMethod][1] = subgroupID
+	var err error
+	if t.ArgsOut[RemoveSubgroupMethod][0] != nil {
+		err = t.ArgsOut[RemoveSubgroupMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) IsSubgroupOfGroup(subgroupID string, groupID string) (bool, error) {
+	t.ArgsIn[IsSubgroupOfGroupMethod][0] = subgroupID
+	t.ArgsIn[IsSubgroupOfGroupMethod][1] = groupID
+	var isSubgroup bool
+	if t.ArgsOut[IsSubgroupOfGroupMethod][0] != nil {
+		isSubgroup = t.ArgsOut[IsSubgroupOfGroupMethod][0].(bool)
+	}
+	var err error
+	if t.ArgsOut[IsSubgroupOfGroupMethod][1] != nil {
+		err = t.ArgsOut[IsSubgroupOfGroupMethod][1].(error)
+	}
+	return isSubgroup, err
+}
+
+func (t TestRepo) GetSubgroups(groupID string, filter *Filter) ([]GroupSubgroupRelation, int, error) {
+	t.ArgsIn[GetSubgroupsMethod][0] = groupID
+	t.ArgsIn[GetSubgroupsMethod][1] = filter
+	if specialFunc, ok := t.SpecialFuncs[GetSubgroupsMethod].(func(groupID string, filter *Filter) ([]GroupSubgroupRelation, int, error)); ok && specialFunc != nil {
+		return specialFunc(groupID, filter)
+	}
+	var subgroups []GroupSubgroupRelation
+	if t.ArgsOut[GetSubgroupsMethod][0] != nil {
+		testSubgroups := t.ArgsOut[GetSubgroupsMethod][0].([]TestGroupSubgroupRelation)
+		for _, v := range testSubgroups {
+			subgroups = append(subgroups, v)
+		}
+	}
+	var total int
+	if t.ArgsOut[GetSubgroupsMethod][1] != nil {
+		total = t.ArgsOut[GetSubgroupsMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[GetSubgroupsMethod][2] != nil {
+		err = t.ArgsOut[GetSubgroupsMethod][2].(error)
+	}
+	return subgroups, total, err
+}
+
+func (t TestRepo) GetParentGroups(groupID string) ([]Group, error) {
+	t.ArgsIn[GetParentGroupsMethod][0] = groupID
+	if specialFunc, ok := t.SpecialFuncs[GetParentGroupsMethod].(func(groupID string) ([]Group, error)); ok && specialFunc != nil {
+		return specialFunc(groupID)
+	}
+	var groups []Group
+	if t.ArgsOut[GetParentGroupsMethod][0] != nil {
+		groups = t.ArgsOut[GetParentGroupsMethod][0].([]Group)
+	}
+	var err error
+	if t.ArgsOut[GetParentGroupsMethod][1] != nil {
+		err = t.ArgsOut[GetParentGroupsMethod][1].(error)
+	}
+	return groups, err
+}
+
+func (t TestRepo) GetParentGroupIDs(groupID string) ([]string, error) {
+	t.ArgsIn[GetParentGroupIDsMethod][0] = groupID
+	if specialFunc, ok := t.SpecialFuncs[GetParentGroupIDsMethod].(func(groupID string) ([]string, error)); ok && specialFunc != nil {
+		return specialFunc(groupID)
+	}
+	var groupIDs []string
+	if t.ArgsOut[GetParentGroupIDsMethod][0] != nil {
+		groupIDs = t.ArgsOut[GetParentGroupIDsMethod][0].([]string)
+	}
+	var err error
+	if t.ArgsOut[GetParentGroupIDsMethod][1] != nil {
+		err = t.ArgsOut[GetParentGroupIDsMethod][1].(error)
+	}
+	return groupIDs, err
+}
+
+func (t TestRepo) LockGroupHierarchy(org string) error {
+	t.ArgsIn[LockGroupHierarchyMethod][0] = org
+	var err error
+	if t.ArgsOut[LockGroupHierarchyMethod][0] != nil {
+		err = t.ArgsOut[LockGroupHierarchyMethod][0].(error)
+	}
+	return err
+}
+
 func (t TestRepo) GetAttachedPolicies(groupID string, filter *Filter) ([]PolicyGroupRelation, int, error) {
 	t.ArgsIn[GetAttachedPoliciesMethod][0] = groupID
 	var policies []PolicyGroupRelation
@@ -427,8 +778,9 @@ func (t TestRepo) GetGroupsFiltered(filter *Filter) ([]Group, int, error) {
 	}
 	return groups, total, err
 }
-func (t TestRepo) RemoveGroup(id string) error {
+func (t TestRepo) RemoveGroup(id string, revision int) error {
 	t.ArgsIn[RemoveGroupMethod][0] = id
+	t.ArgsIn[RemoveGroupMethod][1] = revision
 	var err error
 	if t.ArgsOut[RemoveGroupMethod][0] != nil {
 		err = t.ArgsOut[RemoveGroupMethod][0].(error)
@@ -436,6 +788,42 @@ func (t TestRepo) RemoveGroup(id string) error {
 	return err
 }
 
+func (t TestRepo) GetDeletedGroupByName(org string, name string) (*Group, error) {
+	t.ArgsIn[GetDeletedGroupByNameMethod][0] = org
+	t.ArgsIn[GetDeletedGroupByNameMethod][1] = name
+	var group *Group
+	if t.ArgsOut[GetDeletedGroupByNameMethod][0] != nil {
+		group = t.ArgsOut[GetDeletedGroupByNameMethod][0].(*Group)
+	}
+	var err error
+	if t.ArgsOut[GetDeletedGroupByNameMethod][1] != nil {
+		err = t.ArgsOut[GetDeletedGroupByNameMethod][1].(error)
+	}
+	return group, err
+}
+
+func (t TestRepo) RestoreGroup(id string) error {
+	t.ArgsIn[RestoreGroupMethod][0] = id
+	var err error
+	if t.ArgsOut[RestoreGroupMethod][0] != nil {
+		err = t.ArgsOut[RestoreGroupMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) PurgeGroups(deleteBefore time.Time) (int, error) {
+	t.ArgsIn[PurgeGroupsMethod][0] = deleteBefore
+	var purged int
+	if t.ArgsOut[PurgeGroupsMethod][0] != nil {
+		purged = t.ArgsOut[PurgeGroupsMethod][0].(int)
+	}
+	var err error
+	if t.ArgsOut[PurgeGroupsMethod][1] != nil {
+		err = t.ArgsOut[PurgeGroupsMethod][1].(error)
+	}
+	return purged, err
+}
+
 func (t TestRepo) AddGroup(group Group) (*Group, error) {
 	t.ArgsIn[AddGroupMethod][0] = group
 	var created *Group
@@ -523,8 +911,9 @@ func (t TestRepo) GetPolicyByName(org string, name string) (*Policy, error) {
 	return policy, err
 }
 
-func (t TestRepo) AddPolicy(policy Policy) (*Policy, error) {
+func (t TestRepo) AddPolicy(policy Policy, author string) (*Policy, error) {
 	t.ArgsIn[AddPolicyMethod][0] = policy
+	t.ArgsIn[AddPolicyMethod][1] = author
 	var created *Policy
 	if t.ArgsOut[AddPolicyMethod][0] != nil {
 		created = t.ArgsOut[AddPolicyMethod][0].(*Policy)
@@ -536,8 +925,9 @@ func (t TestRepo) AddPolicy(policy Policy) (*Policy, error) {
 	return created, err
 }
 
-func (t TestRepo) UpdatePolicy(policy Policy) (*Policy, error) {
+func (t TestRepo) UpdatePolicy(policy Policy, author string) (*Policy, error) {
 	t.ArgsIn[UpdatePolicyMethod][0] = policy
+	t.ArgsIn[UpdatePolicyMethod][1] = author
 
 	var updated *Policy
 	if t.ArgsOut[UpdatePolicyMethod][0] != nil {
@@ -550,8 +940,9 @@ func (t TestRepo) UpdatePolicy(policy Policy) (*Policy, error) {
 	return updated, err
 }
 
-func (t TestRepo) RemovePolicy(id string) error {
+func (t TestRepo) RemovePolicy(id string, revision int) error {
 	t.ArgsIn[RemovePolicyMethod][0] = id
+	t.ArgsIn[RemovePolicyMethod][1] = revision
 	var err error
 	if t.ArgsOut[RemovePolicyMethod][0] != nil {
 		err = t.ArgsOut[RemovePolicyMethod][0].(error)
@@ -559,6 +950,42 @@ func (t TestRepo) RemovePolicy(id string) error {
 	return err
 }
 
+func (t TestRepo) GetDeletedPolicyByName(org string, name string) (*Policy, error) {
+	t.ArgsIn[GetDeletedPolicyByNameMethod][0] = org
+	t.ArgsIn[GetDeletedPolicyByNameMethod][1] = name
+	var policy *Policy
+	if t.ArgsOut[GetDeletedPolicyByNameMethod][0] != nil {
+		policy = t.ArgsOut[GetDeletedPolicyByNameMethod][0].(*Policy)
+	}
+	var err error
+	if t.ArgsOut[GetDeletedPolicyByNameMethod][1] != nil {
+		err = t.ArgsOut[GetDeletedPolicyByNameMethod][1].(error)
+	}
+	return policy, err
+}
+
+func (t TestRepo) RestorePolicy(id string) error {
+	t.ArgsIn[RestorePolicyMethod][0] = id
+	var err error
+	if t.ArgsOut[RestorePolicyMethod][0] != nil {
+		err = t.ArgsOut[RestorePolicyMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) PurgePolicies(deleteBefore time.Time) (int, error) {
+	t.ArgsIn[PurgePoliciesMethod][0] = deleteBefore
+	var purged int
+	if t.ArgsOut[PurgePoliciesMethod][0] != nil {
+		purged = t.ArgsOut[PurgePoliciesMethod][0].(int)
+	}
+	var err error
+	if t.ArgsOut[PurgePoliciesMethod][1] != nil {
+		err = t.ArgsOut[PurgePoliciesMethod][1].(error)
+	}
+	return purged, err
+}
+
 func (t TestRepo) GetPoliciesFiltered(filter *Filter) ([]Policy, int, error) {
 	t.ArgsIn[GetPoliciesFilteredMethod][0] = filter
 
@@ -598,6 +1025,59 @@ func (t TestRepo) GetAttachedGroups(policyID string, filter *Filter) ([]PolicyGr
 	return groups, total, err
 }
 
+func (t TestRepo) GetPolicyVersions(policyID string, filter *Filter) ([]PolicyVersion, int, error) {
+	t.ArgsIn[GetPolicyVersionsMethod][0] = policyID
+	t.ArgsIn[GetPolicyVersionsMethod][1] = filter
+
+	var versions []PolicyVersion
+	if t.ArgsOut[GetPolicyVersionsMethod][0] != nil {
+		versions = t.ArgsOut[GetPolicyVersionsMethod][0].([]PolicyVersion)
+	}
+	var total int
+	if t.ArgsOut[GetPolicyVersionsMethod][1] != nil {
+		total = t.ArgsOut[GetPolicyVersionsMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[GetPolicyVersionsMethod][2] != nil {
+		err = t.ArgsOut[GetPolicyVersionsMethod][2].(error)
+	}
+	return versions, total, err
+}
+
+func (t TestRepo) GetPolicyVersion(policyID string, version int) (*PolicyVersion, error) {
+	t.ArgsIn[GetPolicyVersionMethod][0] = policyID
+	t.ArgsIn[GetPolicyVersionMethod][1] = version
+	if specialFunc, ok := t.SpecialFuncs[GetPolicyVersionMethod].(func(policyID string, version int) (*PolicyVersion, error)); ok && specialFunc != nil {
+		return specialFunc(policyID, version)
+	}
+	var policyVersion *PolicyVersion
+	if t.ArgsOut[GetPolicyVersionMethod][0] != nil {
+		policyVersion = t.ArgsOut[GetPolicyVersionMethod][0].(*PolicyVersion)
+	}
+	var err error
+	if t.ArgsOut[GetPolicyVersionMethod][1] != nil {
+		err = t.ArgsOut[GetPolicyVersionMethod][1].(error)
+	}
+	return policyVersion, err
+}
+
+func (t TestRepo) SetDefaultPolicyVersion(policyID string, version int, revision int, updateAt time.Time) (*Policy, error) {
+	t.ArgsIn[SetDefaultPolicyVersionMethod][0] = policyID
+	t.ArgsIn[SetDefaultPolicyVersionMethod][1] = version
+	t.ArgsIn[SetDefaultPolicyVersionMethod][2] = revision
+	t.ArgsIn[SetDefaultPolicyVersionMethod][3] = updateAt
+
+	var policy *Policy
+	if t.ArgsOut[SetDefaultPolicyVersionMethod][0] != nil {
+		policy = t.ArgsOut[SetDefaultPolicyVersionMethod][0].(*Policy)
+	}
+	var err error
+	if t.ArgsOut[SetDefaultPolicyVersionMethod][1] != nil {
+		err = t.ArgsOut[SetDefaultPolicyVersionMethod][1].(error)
+	}
+	return policy, err
+}
+
 func (t TestRepo) OrderByValidColumns(action string) []string {
 	t.ArgsIn[OrderByValidColumnsMethod][0] = action
 	var validColumns []string
@@ -673,8 +1153,9 @@ func (t TestRepo) GetProxyResources(filter *Filter) ([]ProxyResource, int, error
 	return resources, total, err
 }
 
-func (t TestRepo) RemoveProxyResource(id string) error {
+func (t TestRepo) RemoveProxyResource(id string, revision int) error {
 	t.ArgsIn[RemoveProxyResourceMethod][0] = id
+	t.ArgsIn[RemoveProxyResourceMethod][1] = revision
 	var err error
 	if t.ArgsOut[RemoveProxyResourceMethod][0] != nil {
 		err = t.ArgsOut[RemoveProxyResourceMethod][0].(error)
@@ -756,6 +1237,80 @@ func (t TestRepo) RemoveOidcProvider(id string) error {
 	return err
 }
 
+///////////////////////////
+// Organization repo
+//////////////////////////
+
+func (t TestRepo) AddOrganization(org Organization) (*Organization, error) {
+	t.ArgsIn[AddOrganizationMethod][0] = org
+	var created *Organization
+	if t.ArgsOut[AddOrganizationMethod][0] != nil {
+		created = t.ArgsOut[AddOrganizationMethod][0].(*Organization)
+	}
+	var err error
+	if t.ArgsOut[AddOrganizationMethod][1] != nil {
+		err = t.ArgsOut[AddOrganizationMethod][1].(error)
+	}
+	return created, err
+}
+
+func (t TestRepo) GetOrganizationByName(name string) (*Organization, error) {
+	t.ArgsIn[GetOrganizationByNameMethod][0] = name
+	if specialFunc, ok := t.SpecialFuncs[GetOrganizationByNameMethod].(func(name string) (*Organization, error)); ok && specialFunc != nil {
+		return specialFunc(name)
+	}
+	var org *Organization
+	if t.ArgsOut[GetOrganizationByNameMethod][0] != nil {
+		org = t.ArgsOut[GetOrganizationByNameMethod][0].(*Organization)
+	}
+	var err error
+	if t.ArgsOut[GetOrganizationByNameMethod][1] != nil {
+		err = t.ArgsOut[GetOrganizationByNameMethod][1].(error)
+	}
+	return org, err
+}
+
+func (t TestRepo) GetOrganizationsFiltered(filter *Filter) ([]Organization, int, error) {
+	t.ArgsIn[GetOrganizationsFilteredMethod][0] = filter
+
+	var orgs []Organization
+	if t.ArgsOut[GetOrganizationsFilteredMethod][0] != nil {
+		orgs = t.ArgsOut[GetOrganizationsFilteredMethod][0].([]Organization)
+	}
+	var total int
+	if t.ArgsOut[GetOrganizationsFilteredMethod][1] != nil {
+		total = t.ArgsOut[GetOrganizationsFilteredMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[GetOrganizationsFilteredMethod][2] != nil {
+		err = t.ArgsOut[GetOrganizationsFilteredMethod][2].(error)
+	}
+	return orgs, total, err
+}
+
+func (t TestRepo) UpdateOrganization(org Organization) (*Organization, error) {
+	t.ArgsIn[UpdateOrganizationMethod][0] = org
+
+	var updated *Organization
+	if t.ArgsOut[UpdateOrganizationMethod][0] != nil {
+		updated = t.ArgsOut[UpdateOrganizationMethod][0].(*Organization)
+	}
+	var err error
+	if t.ArgsOut[UpdateOrganizationMethod][1] != nil {
+		err = t.ArgsOut[UpdateOrganizationMethod][1].(error)
+	}
+	return updated, err
+}
+
+func (t TestRepo) RemoveOrganization(id string) error {
+	t.ArgsIn[RemoveOrganizationMethod][0] = id
+	var err error
+	if t.ArgsOut[RemoveOrganizationMethod][0] != nil {
+		err = t.ArgsOut[RemoveOrganizationMethod][0].(error)
+	}
+	return err
+}
+
 // Private helper methods
 
 func getRandomString(runeValue []rune, n int) string {
@@ -783,3 +1338,163 @@ func checkMethodResponse(t *testing.T, testcase string, expectedError error, rec
 		assert.Equal(t, receivedResponse, expectedResponse, "Error in test case %v", testcase)
 	}
 }
+
+//////////////////////////
+// Webhook repo
+//////////////////////////
+
+func (t TestRepo) AddWebhook(webhook Webhook) (*Webhook, error) {
+	t.ArgsIn[AddWebhookMethod][0] = webhook
+	var created *Webhook
+	if t.ArgsOut[AddWebhookMethod][0] != nil {
+		created = t.ArgsOut[AddWebhookMethod][0].(*Webhook)
+	}
+	var err error
+	if t.ArgsOut[AddWebhookMethod][1] != nil {
+		err = t.ArgsOut[AddWebhookMethod][1].(error)
+	}
+	return created, err
+}
+
+func (t TestRepo) GetWebhookByName(name string) (*Webhook, error) {
+	t.ArgsIn[GetWebhookByNameMethod][0] = name
+	if specialFunc, ok := t.SpecialFuncs[GetWebhookByNameMethod].(func(name string) (*Webhook, error)); ok && specialFunc != nil {
+		return specialFunc(name)
+	}
+	var webhook *Webhook
+	if t.ArgsOut[GetWebhookByNameMethod][0] != nil {
+		webhook = t.ArgsOut[GetWebhookByNameMethod][0].(*Webhook)
+	}
+	var err error
+	if t.ArgsOut[GetWebhookByNameMethod][1] != nil {
+		err = t.ArgsOut[GetWebhookByNameMethod][1].(error)
+	}
+	return webhook, err
+}
+
+func (t TestRepo) GetWebhooksFiltered(filter *Filter) ([]Webhook, int, error) {
+	t.ArgsIn[GetWebhooksFilteredMethod][0] = filter
+	var webhooks []Webhook
+	if t.ArgsOut[GetWebhooksFilteredMethod][0] != nil {
+		webhooks = t.ArgsOut[GetWebhooksFilteredMethod][0].([]Webhook)
+	}
+	var total int
+	if t.ArgsOut[GetWebhooksFilteredMethod][1] != nil {
+		total = t.ArgsOut[GetWebhooksFilteredMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[GetWebhooksFilteredMethod][2] != nil {
+		err = t.ArgsOut[GetWebhooksFilteredMethod][2].(error)
+	}
+	return webhooks, total, err
+}
+
+func (t TestRepo) UpdateWebhook(webhook Webhook) (*Webhook, error) {
+	t.ArgsIn[UpdateWebhookMethod][0] = webhook
+	var updated *Webhook
+	if t.ArgsOut[UpdateWebhookMethod][0] != nil {
+		updated = t.ArgsOut[UpdateWebhookMethod][0].(*Webhook)
+	}
+	var err error
+	if t.ArgsOut[UpdateWebhookMethod][1] != nil {
+		err = t.ArgsOut[UpdateWebhookMethod][1].(error)
+	}
+	return updated, err
+}
+
+func (t TestRepo) RemoveWebhook(id string) error {
+	t.ArgsIn[RemoveWebhookMethod][0] = id
+	var err error
+	if t.ArgsOut[RemoveWebhookMethod][0] != nil {
+		err = t.ArgsOut[RemoveWebhookMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) AddWebhookDeliveries(deliveries []WebhookDelivery) error {
+	t.ArgsIn[AddWebhookDeliveriesMethod][0] = deliveries
+	var err error
+	if t.ArgsOut[AddWebhookDeliveriesMethod][0] != nil {
+		err = t.ArgsOut[AddWebhookDeliveriesMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) GetWebhookDeliveries(webhookID string, filter *Filter) ([]WebhookDelivery, int, error) {
+	t.ArgsIn[GetWebhookDeliveriesMethod][0] = webhookID
+	t.ArgsIn[GetWebhookDeliveriesMethod][1] = filter
+	var deliveries []WebhookDelivery
+	if t.ArgsOut[GetWebhookDeliveriesMethod][0] != nil {
+		deliveries = t.ArgsOut[GetWebhookDeliveriesMethod][0].([]WebhookDelivery)
+	}
+	var total int
+	if t.ArgsOut[GetWebhookDeliveriesMethod][1] != nil {
+		total = t.ArgsOut[GetWebhookDeliveriesMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[GetWebhookDeliveriesMethod][2] != nil {
+		err = t.ArgsOut[GetWebhookDeliveriesMethod][2].(error)
+	}
+	return deliveries, total, err
+}
+
+func (t TestRepo) ClaimWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	t.ArgsIn[ClaimWebhookDeliveriesMethod][0] = now
+	t.ArgsIn[ClaimWebhookDeliveriesMethod][1] = lease
+	t.ArgsIn[ClaimWebhookDeliveriesMethod][2] = limit
+	var deliveries []WebhookDelivery
+	if t.ArgsOut[ClaimWebhookDeliveriesMethod][0] != nil {
+		deliveries = t.ArgsOut[ClaimWebhookDeliveriesMethod][0].([]WebhookDelivery)
+	}
+	var err error
+	if t.ArgsOut[ClaimWebhookDeliveriesMethod][1] != nil {
+		err = t.ArgsOut[ClaimWebhookDeliveriesMethod][1].(error)
+	}
+	return deliveries, err
+}
+
+func (t TestRepo) UpdateWebhookDelivery(delivery WebhookDelivery, claimedUntil time.Time) error {
+	if specialFunc, ok := t.SpecialFuncs[UpdateWebhookDeliveryMethod].(func(delivery WebhookDelivery, claimedUntil time.Time) error); ok && specialFunc != nil {
+		return specialFunc(delivery, claimedUntil)
+	}
+	t.ArgsIn[UpdateWebhookDeliveryMethod][0] = delivery
+	t.ArgsIn[UpdateWebhookDeliveryMethod][1] = claimedUntil
+	var err error
+	if t.ArgsOut[UpdateWebhookDeliveryMethod][0] != nil {
+		err = t.ArgsOut[UpdateWebhookDeliveryMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) PurgeWebhookDeliveries(updatedBefore time.Time) (int, error) {
+	t.ArgsIn[PurgeWebhookDeliveriesMethod][0] = updatedBefore
+	var purged int
+	if t.ArgsOut[PurgeWebhookDeliveriesMethod][0] != nil {
+		purged = t.ArgsOut[PurgeWebhookDeliveriesMethod][0].(int)
+	}
+	var err error
+	if t.ArgsOut[PurgeWebhookDeliveriesMethod][1] != nil {
+		err = t.ArgsOut[PurgeWebhookDeliveriesMethod][1].(error)
+	}
+	return purged, err
+}
+
+//////////////////////////
+// Transaction repo
+//////////////////////////
+
+func (t TestRepo) WithTransaction(f func(repos Repos) error) error {
+	// Error starting the transaction
+	if t.ArgsOut[WithTransactionMethod][0] != nil {
+		return t.ArgsOut[WithTransactionMethod][0].(error)
+	}
+	return f(Repos{
+		UserRepo:         t,
+		GroupRepo:        t,
+		PolicyRepo:       t,
+		ProxyRepo:        t,
+		AuthOidcRepo:     t,
+		OrganizationRepo: t,
+		TransactionRepo:  t,
+	})
+}
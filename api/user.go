@@ -18,6 +18,7 @@ type User struct {
 	Urn        string    `json:"urn,omitempty"`
 	CreateAt   time.Time `json:"createAt,omitempty"`
 	UpdateAt   time.Time `json:"updateAt,omitempty"`
+	Revision   int       `json:"revision,omitempty"`
 }
 
 type UserGroups struct {
@@ -26,6 +27,12 @@ type UserGroups struct {
 	CreateAt time.Time `json:"joined,omitempty"`
 }
 
+type UserPolicies struct {
+	Org      string    `json:"org,omitempty"`
+	Policy   string    `json:"policy,omitempty"`
+	CreateAt time.Time `json:"attached,omitempty"`
+}
+
 func (u User) String() string {
 	return fmt.Sprintf("[id: %v, externalId: %v, path: %v, urn: %v, createAt: %v]",
 		u.ID, u.ExternalID, u.Path, u.Urn, u.CreateAt.Format("2006-01-02 15:04:05 MST"))
@@ -38,6 +45,18 @@ func (u User) GetUrn() string {
 // USER API IMPLEMENTATION
 
 func (api WorkerAPI) AddUser(requestInfo RequestInfo, externalId string, path string) (*User, error) {
+	var user *User
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		user, err = txAPI.addUser(requestInfo, externalId, path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (api WorkerAPI) addUser(requestInfo RequestInfo, externalId string, path string) (*User, error) {
 	// Validate fields
 	if !IsValidUserExternalID(externalId) {
 		return nil, &Error{
@@ -81,14 +100,10 @@ func (api WorkerAPI) AddUser(requestInfo RequestInfo, externalId string, path st
 
 			// Check unexpected DB error
 			if err != nil {
-				//Transform to DB error
-				dbError := err.(*database.Error)
-				return nil, &Error{
-					Code:    UNKNOWN_API_ERROR,
-					Message: dbError.Message,
-				}
+				return nil, storeError(err)
 			}
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User created %+v", createdUser))
+			api.emitEvent(requestInfo, USER_EVENT_CREATED, createdUser, nil)
 			return createdUser, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -148,13 +163,17 @@ func (api WorkerAPI) GetUserByExternalID(requestInfo RequestInfo, externalId str
 	}
 }
 
-func (api WorkerAPI) ListUsers(requestInfo RequestInfo, filter *Filter) ([]string, int, error) {
+func (api WorkerAPI) ListUsers(requestInfo RequestInfo, filter *Filter) ([]string, int, string, error) {
 	// Check parameters
 	var total int
 	orderByValidColumns := api.UserRepo.OrderByValidColumns(USER_ACTION_LIST_USERS)
 	err := validateFilter(filter, orderByValidColumns)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
+	}
+	err = validateNextToken(filter)
+	if err != nil {
+		return nil, total, "", err
 	}
 
 	// Retrieve users with specified path prefix
@@ -164,17 +183,23 @@ func (api WorkerAPI) ListUsers(requestInfo RequestInfo, filter *Filter) ([]strin
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return nil, total, &Error{
+		return nil, total, "", &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
 
+	var nextToken string
+	if len(users) > 0 {
+		last := users[len(users)-1]
+		nextToken = nextPageToken(filter, len(users), last.CreateAt, last.ID)
+	}
+
 	// Check restrictions
 	urnPrefix := GetUrnPrefix("", RESOURCE_USER, filter.PathPrefix)
 	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, urnPrefix, USER_ACTION_LIST_USERS, users)
 	if err != nil {
-		return nil, total, err
+		return nil, total, "", err
 	}
 
 	// Return user IDs
@@ -183,7 +208,7 @@ func (api WorkerAPI) ListUsers(requestInfo RequestInfo, filter *Filter) ([]strin
 		externalIds = append(externalIds, u.ExternalID)
 	}
 
-	return externalIds, total, nil
+	return externalIds, total, nextToken, nil
 }
 
 func (api WorkerAPI) UpdateUser(requestInfo RequestInfo, externalId string, newPath string) (*User, error) {
@@ -213,6 +238,11 @@ func (api WorkerAPI) UpdateUser(requestInfo RequestInfo, externalId string, newP
 		}
 	}
 
+	// Check revision required by request
+	if err := checkRevision(requestInfo, oldUser.Revision); err != nil {
+		return nil, err
+	}
+
 	auxUser := User{
 		Urn: CreateUrn("", RESOURCE_USER, newPath, externalId),
 	}
@@ -237,21 +267,18 @@ func (api WorkerAPI) UpdateUser(requestInfo RequestInfo, externalId string, newP
 		CreateAt:   oldUser.CreateAt,
 		UpdateAt:   time.Now().UTC(),
 		Urn:        auxUser.Urn,
+		Revision:   oldUser.Revision,
 	}
 
 	updatedUser, err := api.UserRepo.UpdateUser(user)
 
 	// Check unexpected DB error
 	if err != nil {
-		//Transform to DB error
-		dbError := err.(*database.Error)
-		return nil, &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
+		return nil, revisionError(err)
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User updated from %+v to %+v", oldUser, updatedUser))
+	api.emitEvent(requestInfo, USER_EVENT_UPDATED, updatedUser, nil)
 	return updatedUser, nil
 
 }
@@ -276,19 +303,99 @@ func (api WorkerAPI) RemoveUser(requestInfo RequestInfo, externalId string) erro
 		}
 	}
 
-	err = api.UserRepo.RemoveUser(user.ID)
+	// Check revision required by request
+	if err := checkRevision(requestInfo, user.Revision); err != nil {
+		return err
+	}
+
+	err = api.UserRepo.RemoveUser(user.ID, user.Revision)
+
+	// Error handling
+	if err != nil {
+		return revisionError(err)
+	}
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User deleted %+v", user))
+	api.emitEvent(requestInfo, USER_EVENT_DELETED, user, nil)
+	return nil
+}
+
+func (api WorkerAPI) RestoreUser(requestInfo RequestInfo, externalId string) (*User, error) {
+	var user *User
+	err := api.inTransaction(func(txAPI WorkerAPI) (err error) {
+		user, err = txAPI.restoreUser(requestInfo, externalId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (api WorkerAPI) restoreUser(requestInfo RequestInfo, externalId string) (*User, error) {
+	if !IsValidUserExternalID(externalId) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: externalId %v", externalId),
+		}
+	}
+
+	// Check if another user with the same externalId was created after deletion
+	_, err := api.UserRepo.GetUserByExternalID(externalId)
+	if err == nil {
+		return nil, &Error{
+			Code:    USER_ALREADY_EXIST,
+			Message: fmt.Sprintf("Unable to restore user, user with externalId %v already exist", externalId),
+		}
+	}
+	if dbError := err.(*database.Error); dbError.Code != database.USER_NOT_FOUND {
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Call repo to retrieve the deleted user
+	user, err := api.UserRepo.GetDeletedUserByExternalID(externalId)
 
 	// Error handling
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return &Error{
+		// Deleted user doesn't exist in DB
+		if dbError.Code == database.USER_NOT_FOUND {
+			return nil, &Error{
+				Code:    USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return nil, &Error{
 			Code:    UNKNOWN_API_ERROR,
 			Message: dbError.Message,
 		}
 	}
-	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User deleted %+v", user))
-	return nil
+
+	// Check restrictions
+	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, user.Urn, USER_ACTION_RESTORE_USER, []User{*user})
+	if err != nil {
+		return nil, err
+	}
+	if len(usersFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, user.Urn),
+		}
+	}
+
+	err = api.UserRepo.RestoreUser(user.ID)
+
+	// Error handling
+	if err != nil {
+		return nil, storeError(err)
+	}
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User restored %+v", user))
+	api.emitEvent(requestInfo, USER_EVENT_RESTORED, user, nil)
+	return user, nil
 }
 
 func (api WorkerAPI) ListGroupsByUser(requestInfo RequestInfo, filter *Filter) ([]UserGroups, int, error) {
@@ -344,6 +451,186 @@ func (api WorkerAPI) ListGroupsByUser(requestInfo RequestInfo, filter *Filter) (
 	return groupIDs, total, nil
 }
 
+func (api WorkerAPI) AttachPolicyToUser(requestInfo RequestInfo, externalId string, org string, policyName string) error {
+	return api.inTransaction(func(txAPI WorkerAPI) error {
+		return txAPI.attachPolicyToUser(requestInfo, externalId, org, policyName)
+	})
+}
+
+func (api WorkerAPI) attachPolicyToUser(requestInfo RequestInfo, externalId string, org string, policyName string) error {
+	// Call repo to retrieve the user
+	user, err := api.GetUserByExternalID(requestInfo, externalId)
+	if err != nil {
+		return err
+	}
+
+	// Check restrictions
+	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, user.Urn, USER_ACTION_ATTACH_USER_POLICY, []User{*user})
+	if err != nil {
+		return err
+	}
+	if len(usersFiltered) < 1 {
+		return &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, user.Urn),
+		}
+	}
+
+	// Check if policy exists
+	policy, err := api.GetPolicyByName(requestInfo, org, policyName)
+	if err != nil {
+		return err
+	}
+
+	// Check existing relationship
+	isAttached, err := api.UserRepo.IsAttachedToUser(user.ID, policy.ID)
+	if err != nil {
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	if isAttached {
+		return &Error{
+			Code:    POLICY_IS_ALREADY_ATTACHED_TO_USER,
+			Message: fmt.Sprintf("Policy: %v is already attached to User: %v", policy.Name, user.ExternalID),
+		}
+	}
+
+	// Attach Policy to User
+	err = api.UserRepo.AttachPolicyToUser(user.ID, policy.ID)
+
+	if err != nil {
+		return storeError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy %+v attached to user %+v", policy, user))
+	api.emitEvent(requestInfo, USER_EVENT_POLICY_ATTACHED, user, policy)
+	return nil
+}
+
+func (api WorkerAPI) DetachPolicyFromUser(requestInfo RequestInfo, externalId string, org string, policyName string) error {
+	return api.inTransaction(func(txAPI WorkerAPI) error {
+		return txAPI.detachPolicyFromUser(requestInfo, externalId, org, policyName)
+	})
+}
+
+func (api WorkerAPI) detachPolicyFromUser(requestInfo RequestInfo, externalId string, org string, policyName string) error {
+	// Call repo to retrieve the user
+	user, err := api.GetUserByExternalID(requestInfo, externalId)
+	if err != nil {
+		return err
+	}
+
+	// Check restrictions
+	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, user.Urn, USER_ACTION_DETACH_USER_POLICY, []User{*user})
+	if err != nil {
+		return err
+	}
+	if len(usersFiltered) < 1 {
+		return &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, user.Urn),
+		}
+	}
+
+	// Check if policy exists
+	policy, err := api.GetPolicyByName(requestInfo, org, policyName)
+	if err != nil {
+		return err
+	}
+
+	// Check existing relationship
+	isAttached, err := api.UserRepo.IsAttachedToUser(user.ID, policy.ID)
+	if err != nil {
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	if !isAttached {
+		return &Error{
+			Code: POLICY_IS_NOT_ATTACHED_TO_USER,
+			Message: fmt.Sprintf("Policy with org %v and name %v is not attached to user with externalId %v",
+				policy.Org, policy.Name, user.ExternalID),
+		}
+	}
+
+	// Detach Policy from User
+	err = api.UserRepo.DetachPolicyFromUser(user.ID, policy.ID)
+
+	if err != nil {
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy %+v detached from user %+v", policy, user))
+	api.emitEvent(requestInfo, USER_EVENT_POLICY_DETACHED, user, policy)
+	return nil
+}
+
+func (api WorkerAPI) ListAttachedUserPolicies(requestInfo RequestInfo, filter *Filter) ([]UserPolicies, int, error) {
+	// Check parameters
+	var total int
+	orderByValidColumns := api.UserRepo.OrderByValidColumns(USER_ACTION_LIST_ATTACHED_USER_POLICIES)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the user
+	user, err := api.GetUserByExternalID(requestInfo, filter.ExternalID)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Check restrictions
+	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, user.Urn, USER_ACTION_LIST_ATTACHED_USER_POLICIES, []User{*user})
+	if err != nil {
+		return nil, total, err
+	}
+	if len(usersFiltered) < 1 {
+		return nil, total, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, user.Urn),
+		}
+	}
+
+	// Call repo to retrieve the PolicyUserRelations
+	attachedPolicies, total, err := api.UserRepo.GetAttachedUserPolicies(user.ID, filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	policies := []UserPolicies{}
+	for _, p := range attachedPolicies {
+		policies = append(policies, UserPolicies{
+			Org:      p.GetPolicy().Org,
+			Policy:   p.GetPolicy().Name,
+			CreateAt: p.GetDate(),
+		})
+	}
+
+	return policies, total, nil
+}
+
 // PRIVATE HELPER METHODS
 
 func createUser(externalId string, path string) User {
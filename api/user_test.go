@@ -2,6 +2,7 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Tecsisa/foulkon/database"
 	"github.com/stretchr/testify/assert"
@@ -24,6 +25,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 		// API Errors
 		addUserMethodErr             error
 		getUserByExternalIDMethodErr error
+		withTransactionErr           error
 	}{
 		"OKCaseAdmin": {
 			requestInfo: RequestInfo{
@@ -267,6 +269,42 @@ func TestAuthAPI_AddUser(t *testing.T) {
 				Code: database.INTERNAL_ERROR,
 			},
 		},
+		"ErrorCaseAddUserDBAlreadyExist": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			path:       "/example/",
+			wantError: &Error{
+				Code:    USER_ALREADY_EXIST,
+				Message: "User with externalId 1234 already exist",
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code:    database.USER_NOT_FOUND,
+				Message: "User not found",
+			},
+			addUserMethodErr: &database.Error{
+				Code:    database.USER_ALREADY_EXIST,
+				Message: "User with externalId 1234 already exist",
+			},
+		},
+		"ErrorCaseTransactionErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			path:       "/example/",
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			withTransactionErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
 		"ErrorCaseGetUserDBErr": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
@@ -314,6 +352,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 		testRepo.ArgsOut[AddUserMethod][0] = testcase.expectedUser
 		testRepo.ArgsOut[AddUserMethod][1] = testcase.addUserMethodErr
+		testRepo.ArgsOut[WithTransactionMethod][0] = testcase.withTransactionErr
 		user, err := testAPI.AddUser(testcase.requestInfo, testcase.externalID, testcase.path)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedUser, user)
 	}
@@ -571,9 +610,10 @@ func TestAuthAPI_ListUsers(t *testing.T) {
 		requestInfo RequestInfo
 		filter      *Filter
 		// Expected result
-		expectedResult []string
-		totalResult    int
-		wantError      error
+		expectedResult    []string
+		totalResult       int
+		expectedNextToken string
+		wantError         error
 		// Manager Results
 		getUsersFilteredMethodResult    []User
 		getGroupsByUserIDMethodResult   []TestUserGroupRelation
@@ -609,6 +649,47 @@ func TestAuthAPI_ListUsers(t *testing.T) {
 				},
 			},
 		},
+		"OKCaseNextPage": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Limit: 2,
+			},
+			expectedResult:    []string{"123", "321"},
+			totalResult:       3,
+			expectedNextToken: nextPageToken(&Filter{Limit: 2}, 2, time.Unix(0, 1000).UTC(), "321"),
+			getUsersFilteredMethodResult: []User{
+				{
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+					CreateAt:   time.Unix(0, 500).UTC(),
+				},
+				{
+					ID:         "321",
+					ExternalID: "321",
+					Path:       "/example/test2/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test2/", "321"),
+					CreateAt:   time.Unix(0, 1000).UTC(),
+				},
+			},
+		},
+		"ErrorCaseInvalidNextToken": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				NextToken: "invalid",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: NextToken invalid",
+			},
+		},
 		"OKCase": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
@@ -950,9 +1031,10 @@ func TestAuthAPI_ListUsers(t *testing.T) {
 		testRepo.ArgsOut[GetUsersFilteredMethod][0] = testcase.getUsersFilteredMethodResult
 		testRepo.ArgsOut[GetUsersFilteredMethod][1] = testcase.totalResult
 		testRepo.ArgsOut[GetUsersFilteredMethod][2] = testcase.GetUsersFilteredMethodErr
-		users, total, err := testAPI.ListUsers(testcase.requestInfo, testcase.filter)
+		users, total, nextToken, err := testAPI.ListUsers(testcase.requestInfo, testcase.filter)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResult, users)
 		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+		assert.Equal(t, testcase.expectedNextToken, nextToken, "Error in test case %v", x)
 	}
 
 }
@@ -1677,6 +1759,45 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 				Code: database.INTERNAL_ERROR,
 			},
 		},
+		"ErrorCaseRevisionMismatch": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+				Revision:   1,
+			},
+			externalID: "123456",
+			wantError: &Error{
+				Code:    REVISION_MISMATCH,
+				Message: "Revision 1 required by request isn't the current revision 2",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/example/",
+				Revision:   2,
+			},
+		},
+		"ErrorCaseRemoveUserDBRevisionMismatch": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "123456",
+			wantError: &Error{
+				Code:    REVISION_MISMATCH,
+				Message: "User with id 543210 has been modified, revision 2 isn't the current one",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/example/",
+				Revision:   2,
+			},
+			removeUserMethodErr: &database.Error{
+				Code:    database.REVISION_MISMATCH,
+				Message: "User with id 543210 has been modified, revision 2 isn't the current one",
+			},
+		},
 	}
 
 	for x, testcase := range testcases {
@@ -1693,68 +1814,59 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 	}
 }
 
-func TestAuthAPI_ListGroupsByUser(t *testing.T) {
+func TestAuthAPI_RestoreUser(t *testing.T) {
+	// Only the user that makes the request exists, restored user was deleted
+	getRequestUser := func(id string) (*User, error) {
+		if id == "123456" {
+			return &User{
+				ID:         "123456",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+			}, nil
+		}
+		return nil, &database.Error{
+			Code: database.USER_NOT_FOUND,
+		}
+	}
 	testcases := map[string]struct {
-		// API Method args
+		// API method args
 		requestInfo RequestInfo
-		filter      *Filter
+		externalID  string
 		// Expected result
-		expectedResponse []UserGroups
-		totalResult      int
-		wantError        error
+		expectedUser *User
+		wantError    error
 		// Manager Results
-		getUserByExternalIDMethodResult *User
-		getGroupsByUserIDMethodResult   []TestUserGroupRelation
-		getAttachedPoliciesMethodResult []TestPolicyGroupRelation
-		// Manager Errors
-		getGroupsByUserIDMethodErr   error
-		getUserByExternalIDMethodErr error
+		getUserByExternalIDMethodSpecialFunc func(string) (*User, error)
+		getUserByExternalIDMethodResult      *User
+		getDeletedUserByExternalIDResult     *User
+		getGroupsByUserIDResult              []TestUserGroupRelation
+		getAttachedPoliciesResult            []TestPolicyGroupRelation
+		// API Errors
+		getUserByExternalIDMethodErr        error
+		getDeletedUserByExternalIDMethodErr error
+		restoreUserMethodErr                error
 	}{
 		"OKCaseAdmin": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      true,
 			},
-			filter: &Filter{
+			externalID: "1234",
+			expectedUser: &User{
+				ID:         "543210",
 				ExternalID: "1234",
-				Offset:     0,
-				Limit:      0,
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
 			},
-			expectedResponse: []UserGroups{
-				{
-					Org:  "org1",
-					Name: "groupUser1",
-				},
-				{
-					Org:  "org2",
-					Name: "groupUser2",
-				},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
 			},
-			totalResult: 2,
-			getUserByExternalIDMethodResult: &User{
+			getDeletedUserByExternalIDResult: &User{
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/example/",
-			},
-			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
-				{
-					Group: &Group{
-						ID:   "GROUP1",
-						Org:  "org1",
-						Name: "groupUser1",
-						Path: "/path/",
-						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser1"),
-					},
-				},
-				{
-					Group: &Group{
-						ID:   "GROUP2",
-						Org:  "org2",
-						Name: "groupUser2",
-						Path: "/path/",
-						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser1"),
-					},
-				},
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
 			},
 		},
 		"OKCase": {
@@ -1762,68 +1874,42 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 				Identifier: "123456",
 				Admin:      false,
 			},
-			filter: &Filter{
-				ExternalID: "1234",
-				Limit:      0,
-			},
-			expectedResponse: []UserGroups{
-				{
-					Org:  "org1",
-					Name: "group1",
-				},
-				{
-					Org:  "org2",
-					Name: "group2",
-				},
-			},
-			totalResult: 2,
-			getUserByExternalIDMethodResult: &User{
+			externalID: "1234",
+			expectedUser: &User{
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
 			},
-			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
-				{
-					Group: &Group{
-						ID:   "GROUP-USER-ID-1",
-						Name: "group1",
-						Path: "/path/1/",
-						Org:  "org1",
-						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/1/", "group1"),
-					},
-				},
+			getUserByExternalIDMethodSpecialFunc: getRequestUser,
+			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
 					Group: &Group{
-						ID:   "GROUP-USER-ID-2",
-						Name: "group2",
-						Path: "/path/2/",
-						Org:  "org2",
-						Urn:  CreateUrn("org2", RESOURCE_GROUP, "/path/2/", "group2"),
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
 					},
 				},
 			},
-			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+			getDeletedUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
 				{
 					Policy: &Policy{
 						ID:   "POLICY-USER-ID",
 						Name: "policyUser",
 						Path: "/path/",
-						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
 						Statements: &[]Statement{
 							{
 								Effect: "allow",
 								Actions: []string{
-									USER_ACTION_GET_USER,
-								},
-								Resources: []string{
-									CreateUrn("", RESOURCE_USER, "/path/", "1234"),
-								},
-							},
-							{
-								Effect: "allow",
-								Actions: []string{
-									USER_ACTION_LIST_GROUPS_FOR_USER,
+									USER_ACTION_RESTORE_USER,
 								},
 								Resources: []string{
 									GetUrnPrefix("", RESOURCE_USER, "/path/"),
@@ -1834,102 +1920,237 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 				},
 			},
 		},
-		"ErrorCaseMaxLimitSize": {
-			requestInfo: RequestInfo{
-				Identifier: "123456",
-				Admin:      true,
-			},
-			filter: &Filter{
-				ExternalID: "1234",
-				Limit:      10000,
-			},
+		"ErrorCaseInvalidExtID": {
+			externalID: "*%~#@|",
 			wantError: &Error{
 				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: limit 10000, max limit allowed: 1000",
+				Message: "Invalid parameter: externalId *%~#@|",
 			},
 		},
-		"ErrorCaseNoAuth": {
+		"ErrorCaseUserAlreadyExist": {
 			requestInfo: RequestInfo{
-				Identifier: "1234",
-				Admin:      false,
-			},
-			filter: &Filter{
-				ExternalID: "1234",
+				Identifier: "123456",
+				Admin:      true,
 			},
+			externalID: "1234",
 			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/example/12345",
+				Code:    USER_ALREADY_EXIST,
+				Message: "Unable to restore user, user with externalId 1234 already exist",
 			},
 			getUserByExternalIDMethodResult: &User{
-				ID:         "543210",
-				ExternalID: "12345",
+				ID:         "654321",
+				ExternalID: "1234",
 				Path:       "/example/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "12345"),
 			},
 		},
-		"ErrorCaseUserNotFound": {
+		"ErrorCaseGetUserDBErr": {
 			requestInfo: RequestInfo{
-				Identifier: "1234",
-				Admin:      false,
-			},
-			filter: &Filter{
-				ExternalID: "1234",
+				Identifier: "123456",
+				Admin:      true,
 			},
+			externalID: "1234",
 			wantError: &Error{
-				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
 			},
 			getUserByExternalIDMethodErr: &database.Error{
-				Code: database.USER_NOT_FOUND,
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
 			},
 		},
-		"ErrorCaseGetUserExtIDDBErr": {
+		"ErrorCaseDeletedUserNotFound": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      true,
 			},
-			filter: &Filter{
+			externalID: "1234",
+			wantError: &Error{
+				Code:    USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "Deleted user with externalId 1234 not found",
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+			getDeletedUserByExternalIDMethodErr: &database.Error{
+				Code:    database.USER_NOT_FOUND,
+				Message: "Deleted user with externalId 1234 not found",
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			externalID: "1234",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			},
+			getUserByExternalIDMethodSpecialFunc: getRequestUser,
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getDeletedUserByExternalIDResult: &User{
+				ID:         "543210",
 				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_DELETE_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseRestoreUserDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
 			},
+			externalID: "1234",
 			wantError: &Error{
-				Code: UNKNOWN_API_ERROR,
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
 			},
 			getUserByExternalIDMethodErr: &database.Error{
-				Code: database.INTERNAL_ERROR,
+				Code: database.USER_NOT_FOUND,
+			},
+			getDeletedUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+			},
+			restoreUserMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
 			},
 		},
-		"ErrorCaseGetGroupsDBErr": {
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.SpecialFuncs[GetUserByExternalIDMethod] = testcase.getUserByExternalIDMethodSpecialFunc
+		testRepo.ArgsOut[GetDeletedUserByExternalIDMethod][0] = testcase.getDeletedUserByExternalIDResult
+		testRepo.ArgsOut[GetDeletedUserByExternalIDMethod][1] = testcase.getDeletedUserByExternalIDMethodErr
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		testRepo.ArgsOut[RestoreUserMethod][0] = testcase.restoreUserMethodErr
+		user, err := testAPI.RestoreUser(testcase.requestInfo, testcase.externalID)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedUser, user)
+	}
+}
+
+func TestAuthAPI_ListGroupsByUser(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedResponse []UserGroups
+		totalResult      int
+		wantError        error
+		// Manager Results
+		getUserByExternalIDMethodResult *User
+		getGroupsByUserIDMethodResult   []TestUserGroupRelation
+		getAttachedPoliciesMethodResult []TestPolicyGroupRelation
+		// Manager Errors
+		getGroupsByUserIDMethodErr   error
+		getUserByExternalIDMethodErr error
+	}{
+		"OKCaseAdmin": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      true,
 			},
 			filter: &Filter{
 				ExternalID: "1234",
+				Offset:     0,
+				Limit:      0,
 			},
-			wantError: &Error{
-				Code: UNKNOWN_API_ERROR,
+			expectedResponse: []UserGroups{
+				{
+					Org:  "org1",
+					Name: "groupUser1",
+				},
+				{
+					Org:  "org2",
+					Name: "groupUser2",
+				},
 			},
+			totalResult: 2,
 			getUserByExternalIDMethodResult: &User{
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/example/",
 			},
-			getGroupsByUserIDMethodErr: &database.Error{
-				Code: database.INTERNAL_ERROR,
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP1",
+						Org:  "org1",
+						Name: "groupUser1",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser1"),
+					},
+				},
+				{
+					Group: &Group{
+						ID:   "GROUP2",
+						Org:  "org2",
+						Name: "groupUser2",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser1"),
+					},
+				},
 			},
 		},
-		"ErrorCaseNoPermissions": {
+		"OKCase": {
 			requestInfo: RequestInfo{
-				Identifier: "1234",
+				Identifier: "123456",
 				Admin:      false,
 			},
 			filter: &Filter{
 				ExternalID: "1234",
 				Limit:      0,
 			},
-			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			expectedResponse: []UserGroups{
+				{
+					Org:  "org1",
+					Name: "group1",
+				},
+				{
+					Org:  "org2",
+					Name: "group2",
+				},
 			},
+			totalResult: 2,
 			getUserByExternalIDMethodResult: &User{
 				ID:         "543210",
 				ExternalID: "1234",
@@ -1939,10 +2160,20 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
 					Group: &Group{
-						ID:   "GROUP-USER-ID",
-						Name: "groupUser",
+						ID:   "GROUP-USER-ID-1",
+						Name: "group1",
 						Path: "/path/1/",
-						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+						Org:  "org1",
+						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/1/", "group1"),
+					},
+				},
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID-2",
+						Name: "group2",
+						Path: "/path/2/",
+						Org:  "org2",
+						Urn:  CreateUrn("org2", RESOURCE_GROUP, "/path/2/", "group2"),
 					},
 				},
 			},
@@ -1952,7 +2183,7 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 						ID:   "POLICY-USER-ID",
 						Name: "policyUser",
 						Path: "/path/",
-						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
 						Statements: &[]Statement{
 							{
 								Effect: "allow",
@@ -1972,21 +2203,26 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 									GetUrnPrefix("", RESOURCE_USER, "/path/"),
 								},
 							},
-							{
-								Effect: "deny",
-								Actions: []string{
-									USER_ACTION_LIST_GROUPS_FOR_USER,
-								},
-								Resources: []string{
-									CreateUrn("", RESOURCE_USER, "/path/", "1234"),
-								},
-							},
 						},
 					},
 				},
 			},
 		},
-		"ErrorCaseUnauthorizedListGroups": {
+		"ErrorCaseMaxLimitSize": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+				Limit:      10000,
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: limit 10000, max limit allowed: 1000",
+			},
+		},
+		"ErrorCaseNoAuth": {
 			requestInfo: RequestInfo{
 				Identifier: "1234",
 				Admin:      false,
@@ -1996,54 +2232,192 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 			},
 			wantError: &Error{
 				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/example/12345",
 			},
 			getUserByExternalIDMethodResult: &User{
 				ID:         "543210",
+				ExternalID: "12345",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "12345"),
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			filter: &Filter{
 				ExternalID: "1234",
-				Path:       "/path/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
 			},
-			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
-				{
-					Group: &Group{
-						ID:   "GROUP-USER-ID",
-						Name: "groupUser",
-						Path: "/path/1/",
-						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
-					},
-				},
+			wantError: &Error{
+				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
 			},
-			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
-				{
-					Policy: &Policy{
-						ID:   "POLICY-USER-ID",
-						Name: "policyUser",
-						Path: "/path/",
-						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
-						Statements: &[]Statement{
-							{
-								Effect: "allow",
-								Actions: []string{
-									USER_ACTION_GET_USER,
-								},
-								Resources: []string{
-									GetUrnPrefix("", RESOURCE_USER, "/path/"),
-								},
-							},
-						},
-					},
-				},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
 			},
 		},
-	}
-
-	for x, testcase := range testcases {
-		testRepo := makeTestRepo()
-		testAPI := makeTestAPI(testRepo)
-
-		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
-		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		"ErrorCaseGetUserExtIDDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseGetGroupsDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+			},
+			getGroupsByUserIDMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+				Limit:      0,
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+								},
+								Resources: []string{
+									CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+								},
+							},
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_LIST_GROUPS_FOR_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+							{
+								Effect: "deny",
+								Actions: []string{
+									USER_ACTION_LIST_GROUPS_FOR_USER,
+								},
+								Resources: []string{
+									CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseUnauthorizedListGroups": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDMethodResult
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][1] = testcase.totalResult
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][2] = testcase.getGroupsByUserIDMethodErr
@@ -2054,3 +2428,587 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 	}
 
 }
+
+func TestAuthAPI_AttachPolicyToUser(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		externalID  string
+		org         string
+		policyName  string
+		// Expected result
+		wantError error
+		// Manager Results
+		getUserByExternalIDResult     *User
+		getPolicyByNameResult         *Policy
+		getAttachedUserPoliciesResult []TestPolicyUserRelation
+		isAttachedToUserResult        bool
+		// Manager Errors
+		getUserByExternalIDMethodErr error
+		getPolicyByNameMethodErr     error
+		isAttachedToUserMethodErr    error
+		attachPolicyToUserMethodErr  error
+	}{
+		"OkCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameResult: &Policy{
+				ID:   "test1",
+				Name: "policy1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policy1"),
+			},
+		},
+		"OkCaseAllowedByUserPolicy": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameResult: &Policy{
+				ID:   "test1",
+				Name: "policy1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policy1"),
+			},
+			getAttachedUserPoliciesResult: []TestPolicyUserRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "123",
+						Path: "/path/",
+						Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+									USER_ACTION_ATTACH_USER_POLICY,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+							{
+								Effect: "allow",
+								Actions: []string{
+									POLICY_ACTION_GET_POLICY,
+								},
+								Resources: []string{
+									GetUrnPrefix("123", RESOURCE_POLICY, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseNoAuth": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getAttachedUserPoliciesResult: []TestPolicyUserRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "123",
+						Path: "/path/",
+						Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+		},
+		"ErrorCasePolicyNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code: POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code: database.POLICY_NOT_FOUND,
+			},
+		},
+		"ErrorCaseIsAttachedToUserDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameResult: &Policy{
+				ID:   "test1",
+				Name: "policy1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policy1"),
+			},
+			isAttachedToUserMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCasePolicyIsAlreadyAttached": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code:    POLICY_IS_ALREADY_ATTACHED_TO_USER,
+				Message: "Policy: policy1 is already attached to User: 1234",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameResult: &Policy{
+				ID:   "test1",
+				Name: "policy1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policy1"),
+			},
+			isAttachedToUserResult: true,
+		},
+		"ErrorCaseAttachPolicyDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameResult: &Policy{
+				ID:   "test1",
+				Name: "policy1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policy1"),
+			},
+			attachPolicyToUserMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.ArgsOut[GetPolicyByNameMethod][0] = testcase.getPolicyByNameResult
+		testRepo.ArgsOut[GetPolicyByNameMethod][1] = testcase.getPolicyByNameMethodErr
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][0] = testcase.getAttachedUserPoliciesResult
+		testRepo.ArgsOut[IsAttachedToUserMethod][0] = testcase.isAttachedToUserResult
+		testRepo.ArgsOut[IsAttachedToUserMethod][1] = testcase.isAttachedToUserMethodErr
+		testRepo.ArgsOut[AttachPolicyToUserMethod][0] = testcase.attachPolicyToUserMethodErr
+
+		err := testAPI.AttachPolicyToUser(testcase.requestInfo, testcase.externalID, testcase.org, testcase.policyName)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.getUserByExternalIDResult.ID, testRepo.ArgsIn[AttachPolicyToUserMethod][0], "Error in test case %v", x)
+			assert.Equal(t, testcase.getPolicyByNameResult.ID, testRepo.ArgsIn[AttachPolicyToUserMethod][1], "Error in test case %v", x)
+		}
+	}
+}
+
+func TestAuthAPI_DetachPolicyFromUser(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		externalID  string
+		org         string
+		policyName  string
+		// Expected result
+		wantError error
+		// Manager Results
+		getUserByExternalIDResult *User
+		getPolicyByNameResult     *Policy
+		isAttachedToUserResult    bool
+		// Manager Errors
+		getUserByExternalIDMethodErr  error
+		getPolicyByNameMethodErr      error
+		isAttachedToUserMethodErr     error
+		detachPolicyFromUserMethodErr error
+	}{
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameResult: &Policy{
+				ID:   "test1",
+				Name: "policy1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policy1"),
+			},
+			isAttachedToUserResult: true,
+		},
+		"ErrorCaseNoAuth": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+		},
+		"ErrorCasePolicyIsNotAttached": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code:    POLICY_IS_NOT_ATTACHED_TO_USER,
+				Message: "Policy with org 123 and name policy1 is not attached to user with externalId 1234",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameResult: &Policy{
+				ID:   "test1",
+				Name: "policy1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policy1"),
+			},
+			isAttachedToUserResult: false,
+		},
+		"ErrorCasePolicyNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code: POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameMethodErr: &database.Error{
+				Code: database.POLICY_NOT_FOUND,
+			},
+		},
+		"ErrorCaseDetachPolicyDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			org:        "123",
+			policyName: "policy1",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getPolicyByNameResult: &Policy{
+				ID:   "test1",
+				Name: "policy1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_POLICY, "/path/", "policy1"),
+			},
+			isAttachedToUserResult: true,
+			detachPolicyFromUserMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.ArgsOut[GetPolicyByNameMethod][0] = testcase.getPolicyByNameResult
+		testRepo.ArgsOut[GetPolicyByNameMethod][1] = testcase.getPolicyByNameMethodErr
+		testRepo.ArgsOut[IsAttachedToUserMethod][0] = testcase.isAttachedToUserResult
+		testRepo.ArgsOut[IsAttachedToUserMethod][1] = testcase.isAttachedToUserMethodErr
+		testRepo.ArgsOut[DetachPolicyFromUserMethod][0] = testcase.detachPolicyFromUserMethodErr
+
+		err := testAPI.DetachPolicyFromUser(testcase.requestInfo, testcase.externalID, testcase.org, testcase.policyName)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.getUserByExternalIDResult.ID, testRepo.ArgsIn[DetachPolicyFromUserMethod][0], "Error in test case %v", x)
+			assert.Equal(t, testcase.getPolicyByNameResult.ID, testRepo.ArgsIn[DetachPolicyFromUserMethod][1], "Error in test case %v", x)
+		}
+	}
+}
+
+func TestAuthAPI_ListAttachedUserPolicies(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedResponse []UserPolicies
+		totalResult      int
+		wantError        error
+		// Manager Results
+		getUserByExternalIDMethodResult     *User
+		getAttachedUserPoliciesMethodResult []TestPolicyUserRelation
+		// Manager Errors
+		getUserByExternalIDMethodErr     error
+		getAttachedUserPoliciesMethodErr error
+	}{
+		"OkCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+			},
+			expectedResponse: []UserPolicies{
+				{
+					Org:    "org1",
+					Policy: "policy1",
+				},
+				{
+					Org:    "org2",
+					Policy: "policy2",
+				},
+			},
+			totalResult: 2,
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getAttachedUserPoliciesMethodResult: []TestPolicyUserRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY1",
+						Org:  "org1",
+						Name: "policy1",
+					},
+				},
+				{
+					Policy: &Policy{
+						ID:   "POLICY2",
+						Org:  "org2",
+						Name: "policy2",
+					},
+				},
+			},
+		},
+		"ErrorCaseInvalidOrderBy": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+				OrderBy:    "name-desc",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: OrderBy column name",
+			},
+		},
+		"ErrorCaseNoAuth": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+			},
+			wantError: &Error{
+				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+		},
+		"ErrorCaseGetAttachedUserPoliciesDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "1234",
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
+			getAttachedUserPoliciesMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[OrderByValidColumnsMethod][0] = []string{"create_at"}
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][0] = testcase.getAttachedUserPoliciesMethodResult
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][1] = testcase.totalResult
+		testRepo.ArgsOut[GetAttachedUserPoliciesMethod][2] = testcase.getAttachedUserPoliciesMethodErr
+		policies, total, err := testAPI.ListAttachedUserPolicies(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResponse, policies)
+		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+	}
+}
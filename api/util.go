@@ -1,9 +1,14 @@
 package api
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
 )
 
 const (
@@ -13,33 +18,45 @@ const (
 	RESOURCE_POLICY             = "policy"
 	RESOURCE_PROXY              = "proxy"
 	RESOURCE_AUTH_OIDC_PROVIDER = "oidc"
+	RESOURCE_ORGANIZATION       = "org"
 
 	// Resource validation
 	RESOURCE_EXTERNAL = "external"
 	RESOURCE_IAM      = "iam"
 
+	// Policy variables allowed in statement resources
+	POLICY_VARIABLE_USER_EXTERNAL_ID = "${user.externalId}"
+	POLICY_VARIABLE_USER_PATH        = "${user.path}"
+	POLICY_VARIABLE_REQUEST_ORG      = "${request.org}"
+
 	// Constraints
 	MAX_EXTERNAL_ID_LENGTH = 128
 	MAX_NAME_LENGTH        = 128
 	MAX_ACTION_LENGTH      = 128
 	MAX_PATH_LENGTH        = 512
 	MAX_RESOURCE_NUMBER    = 50
+	MAX_BULK_ITEMS         = 100
 	MAX_LIMIT_SIZE         = 1000
 	DEFAULT_LIMIT_SIZE     = 20
 
 	// Actions
 
 	// User actions
-	USER_ACTION_CREATE_USER          = "iam:CreateUser"
-	USER_ACTION_DELETE_USER          = "iam:DeleteUser"
-	USER_ACTION_GET_USER             = "iam:GetUser"
-	USER_ACTION_LIST_USERS           = "iam:ListUsers"
-	USER_ACTION_UPDATE_USER          = "iam:UpdateUser"
-	USER_ACTION_LIST_GROUPS_FOR_USER = "iam:ListGroupsForUser"
+	USER_ACTION_CREATE_USER                 = "iam:CreateUser"
+	USER_ACTION_DELETE_USER                 = "iam:DeleteUser"
+	USER_ACTION_RESTORE_USER                = "iam:RestoreUser"
+	USER_ACTION_GET_USER                    = "iam:GetUser"
+	USER_ACTION_LIST_USERS                  = "iam:ListUsers"
+	USER_ACTION_UPDATE_USER                 = "iam:UpdateUser"
+	USER_ACTION_LIST_GROUPS_FOR_USER        = "iam:ListGroupsForUser"
+	USER_ACTION_ATTACH_USER_POLICY          = "iam:AttachUserPolicy"
+	USER_ACTION_DETACH_USER_POLICY          = "iam:DetachUserPolicy"
+	USER_ACTION_LIST_ATTACHED_USER_POLICIES = "iam:ListAttachedUserPolicies"
 
 	// Group actions
 	GROUP_ACTION_CREATE_GROUP                 = "iam:CreateGroup"
 	GROUP_ACTION_DELETE_GROUP                 = "iam:DeleteGroup"
+	GROUP_ACTION_RESTORE_GROUP                = "iam:RestoreGroup"
 	GROUP_ACTION_GET_GROUP                    = "iam:GetGroup"
 	GROUP_ACTION_LIST_GROUPS                  = "iam:ListGroups"
 	GROUP_ACTION_UPDATE_GROUP                 = "iam:UpdateGroup"
@@ -49,14 +66,21 @@ const (
 	GROUP_ACTION_ATTACH_GROUP_POLICY          = "iam:AttachGroupPolicy"
 	GROUP_ACTION_DETACH_GROUP_POLICY          = "iam:DetachGroupPolicy"
 	GROUP_ACTION_LIST_ATTACHED_GROUP_POLICIES = "iam:ListAttachedGroupPolicies"
+	GROUP_ACTION_ADD_SUBGROUP                 = "iam:AddSubgroup"
+	GROUP_ACTION_REMOVE_SUBGROUP              = "iam:RemoveSubgroup"
+	GROUP_ACTION_LIST_SUBGROUPS               = "iam:ListSubgroups"
 
 	// Policy actions
-	POLICY_ACTION_CREATE_POLICY        = "iam:CreatePolicy"
-	POLICY_ACTION_DELETE_POLICY        = "iam:DeletePolicy"
-	POLICY_ACTION_UPDATE_POLICY        = "iam:UpdatePolicy"
-	POLICY_ACTION_GET_POLICY           = "iam:GetPolicy"
-	POLICY_ACTION_LIST_ATTACHED_GROUPS = "iam:ListAttachedGroups"
-	POLICY_ACTION_LIST_POLICIES        = "iam:ListPolicies"
+	POLICY_ACTION_CREATE_POLICY              = "iam:CreatePolicy"
+	POLICY_ACTION_DELETE_POLICY              = "iam:DeletePolicy"
+	POLICY_ACTION_RESTORE_POLICY             = "iam:RestorePolicy"
+	POLICY_ACTION_UPDATE_POLICY              = "iam:UpdatePolicy"
+	POLICY_ACTION_GET_POLICY                 = "iam:GetPolicy"
+	POLICY_ACTION_LIST_ATTACHED_GROUPS       = "iam:ListAttachedGroups"
+	POLICY_ACTION_LIST_POLICIES              = "iam:ListPolicies"
+	POLICY_ACTION_LIST_POLICY_VERSIONS       = "iam:ListPolicyVersions"
+	POLICY_ACTION_GET_POLICY_VERSION         = "iam:GetPolicyVersion"
+	POLICY_ACTION_SET_DEFAULT_POLICY_VERSION = "iam:SetDefaultPolicyVersion"
 
 	// Proxy resource actions
 	PROXY_ACTION_CREATE_RESOURCE    = "iam:CreateProxyResource"
@@ -65,36 +89,58 @@ const (
 	PROXY_ACTION_LIST_RESOURCES     = "iam:ListProxyResources"
 	PROXY_ACTION_GET_PROXY_RESOURCE = "iam:GetProxyResource"
 
+	// Organization actions
+	ORGANIZATION_ACTION_CREATE_ORGANIZATION = "iam:CreateOrganization"
+	ORGANIZATION_ACTION_DELETE_ORGANIZATION = "iam:DeleteOrganization"
+	ORGANIZATION_ACTION_GET_ORGANIZATION    = "iam:GetOrganization"
+	ORGANIZATION_ACTION_LIST_ORGANIZATIONS  = "iam:ListOrganizations"
+	ORGANIZATION_ACTION_UPDATE_ORGANIZATION = "iam:UpdateOrganization"
+
 	// Auth OIDC provider actions
 	AUTH_OIDC_ACTION_CREATE_PROVIDER = "auth:CreateOidcProvider"
 	AUTH_OIDC_ACTION_DELETE_PROVIDER = "auth:DeleteOidcProvider"
 	AUTH_OIDC_ACTION_UPDATE_PROVIDER = "auth:UpdateOidcProvider"
 	AUTH_OIDC_ACTION_LIST_PROVIDERS  = "auth:ListOidcProviders"
 	AUTH_OIDC_ACTION_GET_PROVIDER    = "auth:GetOidcProvider"
+
+	// Webhook actions
+	WEBHOOK_ACTION_LIST_WEBHOOKS   = "admin:ListWebhooks"
+	WEBHOOK_ACTION_LIST_DELIVERIES = "admin:ListWebhookDeliveries"
 )
 
 var (
-	rUserExtID, _          = regexp.Compile(`^[\w+.@=\-_]+$`)
-	rName, _               = regexp.Compile(`^[\w\-_]+$`)
-	rOrder, _              = regexp.Compile(`^\w+\-(asc|desc)$`)
-	rOrg, _                = regexp.Compile(`^[\w\-_]+$`)
-	rPath, _               = regexp.Compile(`^/$|^/[\w+/\-_]+\w+/$`)
-	rPathExclude, _        = regexp.Compile(`[/]{2,}`)
-	rAction, _             = regexp.Compile(`^[\w\-_:]+[\w\-_*]+$`)
-	rActionExclude, _      = regexp.Compile(`[*]{2,}|[:]{2,}`)
-	rWordResource, _       = regexp.Compile(`^[\w+\-_.@]+$`)
-	rWordResourcePrefix, _ = regexp.Compile(`^[\w+\-_.@]+\*$`)
-	rUrn, _                = regexp.Compile(`^\*$|^[\w+\-@.]+\*?$|^[\w+\-@.]+\*?$|^[\w+\-@.]+(/?([\w+\-@.]+/)*([\w+\-@.]|[*])+)?$`)
-	rUrnExclude, _         = regexp.Compile(`[/]{2,}|[:]{2,}|[*]{2,}`)
-	rPathResource, _       = regexp.Compile(`^/$|^(/([\w*_-]+|:[\w_-]+))+$`)
-	rHost, _               = regexp.Compile(`^https?:/{2}[\w+\/\-_.]+(:\d{1,5})?$`)
-	rUrnProxy, _           = regexp.Compile(`^\*$|^[\w+\-@.]+\*?$|^[\w+\-@.]+\*?$|^([\w+\-@.]|\{\w+\})+(/?(([\w+\-@.]|\{\w+\})+/)*([\w+\-@.]|\{\w+\})+)?$`)
+	rUserExtID, _           = regexp.Compile(`^[\w+.@=\-_]+$`)
+	rName, _                = regexp.Compile(`^[\w\-_]+$`)
+	rOrder, _               = regexp.Compile(`^\w+\-(asc|desc)$`)
+	rOrg, _                 = regexp.Compile(`^[\w\-_]+$`)
+	rPath, _                = regexp.Compile(`^/$|^/[\w+/\-_]+\w+/$`)
+	rPathExclude, _         = regexp.Compile(`[/]{2,}`)
+	rAction, _              = regexp.Compile(`^[\w\-_:*?]+[\w\-_*?]+$`)
+	rActionExclude, _       = regexp.Compile(`[*]{2,}|[:]{2,}`)
+	rWordResource, _        = regexp.Compile(`^[\w+\-_.@]+$`)
+	rWordResourcePrefix, _  = regexp.Compile(`^[\w+\-_.@]+\*$`)
+	rWordResourceGlob, _    = regexp.Compile(`^(\*|\*?([\w+\-_.@?]+\*?)+)$`)
+	rWordResourceGlobEnd, _ = regexp.Compile(`^(\*?[\w+\-_.@?]+)*\*$`)
+	rUrn, _                 = regexp.Compile(`^[\w+\-@.*?]+(/[\w+\-@.*?]+)*$`)
+	rUrnExclude, _          = regexp.Compile(`[/]{2,}|[:]{2,}|[*]{2,}`)
+	rPathResource, _        = regexp.Compile(`^/$|^(/([\w*_-]+|:[\w_-]+))+$`)
+	rHost, _                = regexp.Compile(`^https?:/{2}[\w+\/\-_.]+(:\d{1,5})?$`)
+	rUrnProxy, _            = regexp.Compile(`^\*$|^[\w+\-@.]+\*?$|^[\w+\-@.]+\*?$|^([\w+\-@.]|\{\w+\})+(/?(([\w+\-@.]|\{\w+\})+/)*([\w+\-@.]|\{\w+\})+)?$`)
+
+	// Sample values used to validate resources with policy variables
+	policyVariableSamples = strings.NewReplacer(
+		POLICY_VARIABLE_USER_EXTERNAL_ID, "externalId",
+		POLICY_VARIABLE_USER_PATH, "/",
+		POLICY_VARIABLE_REQUEST_ORG, "org",
+	)
 )
 
 func CreateUrn(org string, resource string, path string, name string) string {
 	switch resource {
 	case RESOURCE_USER:
 		return fmt.Sprintf("urn:iws:iam::user%v%v", path, name)
+	case RESOURCE_ORGANIZATION:
+		return fmt.Sprintf("urn:iws:iam::org%v%v", path, name)
 	case RESOURCE_AUTH_OIDC_PROVIDER:
 		return fmt.Sprintf("urn:iws:auth::%v%v%v", resource, path, name)
 	default:
@@ -106,6 +152,8 @@ func GetUrnPrefix(org string, resource string, path string) string {
 	switch resource {
 	case RESOURCE_USER:
 		return fmt.Sprintf("urn:iws:iam::user%v*", path)
+	case RESOURCE_ORGANIZATION:
+		return fmt.Sprintf("urn:iws:iam::org%v*", path)
 	case RESOURCE_AUTH_OIDC_PROVIDER:
 		return fmt.Sprintf("urn:iws:auth::%v%v*", resource, path)
 	default:
@@ -187,7 +235,17 @@ func AreValidActions(actions []string) error {
 func AreValidResources(resources []string, resourceType string) error {
 	for _, resource := range resources {
 		err := errFunc("urn", resource)
-		blocks := strings.Split(resource, ":")
+		urn := resource
+		if resourceType == RESOURCE_IAM {
+			// Policy variables are resolved at evaluation time, so validate them with sample values
+			urn = policyVariableSamples.Replace(resource)
+		}
+		wordResource, wordResourcePrefix := rWordResource, rWordResourcePrefix
+		if resourceType == RESOURCE_IAM {
+			// Wildcards are allowed anywhere in IAM resources
+			wordResource, wordResourcePrefix = rWordResourceGlob, rWordResourceGlobEnd
+		}
+		blocks := strings.Split(urn, ":")
 		for n, block := range blocks {
 			switch n {
 			case 0:
@@ -202,31 +260,31 @@ func AreValidResources(resources []string, resourceType string) error {
 				}
 			case 1:
 				if len(blocks) < 3 { // This is the last block
-					if block != "*" && !rWordResourcePrefix.MatchString(block) {
+					if block != "*" && !wordResourcePrefix.MatchString(block) {
 						return err
 					}
 				} else {
-					if !rWordResource.MatchString(block) {
+					if !wordResource.MatchString(block) {
 						return err
 					}
 				}
 			case 2:
 				if len(blocks) < 4 { // This is the last block
-					if block != "*" && !rWordResourcePrefix.MatchString(block) {
+					if block != "*" && !wordResourcePrefix.MatchString(block) {
 						return err
 					}
 				} else {
-					if !rWordResource.MatchString(block) {
+					if !wordResource.MatchString(block) {
 						return err
 					}
 				}
 			case 3:
 				if len(blocks) < 5 { // This is the last block
-					if block != "*" && !rWordResourcePrefix.MatchString(block) {
+					if block != "*" && !wordResourcePrefix.MatchString(block) {
 						return err
 					}
 				} else {
-					if block != "" && !rWordResource.MatchString(block) {
+					if block != "" && !wordResource.MatchString(block) {
 						return err
 					}
 				}
@@ -259,26 +317,38 @@ func AreValidStatements(statements *[]Statement) error {
 			return err
 		}
 
-		// check actions
-		if len(statement.Actions) < 1 {
+		// check actions, only one of actions or notActions is allowed
+		if len(statement.Actions) > 0 && len(statement.NotActions) > 0 {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Actions and notActions can't be defined in the same statement",
+			}
+		}
+		if len(statement.Actions) < 1 && len(statement.NotActions) < 1 {
 			return &Error{
 				Code:    INVALID_PARAMETER_ERROR,
 				Message: "Empty actions",
 			}
 		}
-		err = AreValidActions(statement.Actions)
+		err = AreValidActions(append(statement.Actions, statement.NotActions...))
 		if err != nil {
 			return err
 		}
 
-		// check resources
-		if len(statement.Resources) < 1 {
+		// check resources, only one of resources or notResources is allowed
+		if len(statement.Resources) > 0 && len(statement.NotResources) > 0 {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Resources and notResources can't be defined in the same statement",
+			}
+		}
+		if len(statement.Resources) < 1 && len(statement.NotResources) < 1 {
 			return &Error{
 				Code:    INVALID_PARAMETER_ERROR,
 				Message: "Empty resources",
 			}
 		}
-		err = AreValidResources(statement.Resources, RESOURCE_IAM)
+		err = AreValidResources(append(statement.Resources, statement.NotResources...), RESOURCE_IAM)
 		if err != nil {
 			return err
 		}
@@ -336,6 +406,62 @@ func validateFilter(filter *Filter, validColumns []string) error {
 		}
 	}
 
+	if len(filter.NamePrefix) > MAX_NAME_LENGTH {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: NamePrefix %v", filter.NamePrefix),
+		}
+	}
+
+	if len(filter.NameContains) > MAX_NAME_LENGTH {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: NameContains %v", filter.NameContains),
+		}
+	}
+
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && filter.CreatedBefore.Before(filter.CreatedAfter) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: CreatedBefore %v is previous to CreatedAfter %v", filter.CreatedBefore.Format(time.RFC3339), filter.CreatedAfter.Format(time.RFC3339)),
+		}
+	}
+
+	if !filter.UpdatedAfter.IsZero() && !filter.UpdatedBefore.IsZero() && filter.UpdatedBefore.Before(filter.UpdatedAfter) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: UpdatedBefore %v is previous to UpdatedAfter %v", filter.UpdatedBefore.Format(time.RFC3339), filter.UpdatedAfter.Format(time.RFC3339)),
+		}
+	}
+
+	if len(filter.Member) > 0 && !IsValidUserExternalID(filter.Member) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: Member %v", filter.Member),
+		}
+	}
+
+	if len(filter.AttachedPolicy) > 0 && !IsValidName(filter.AttachedPolicy) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: AttachedPolicy %v", filter.AttachedPolicy),
+		}
+	}
+
+	if len(filter.Action) > 0 && AreValidActions([]string{filter.Action}) != nil {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: Action %v", filter.Action),
+		}
+	}
+
+	if len(filter.Resource) > 0 && AreValidResources([]string{filter.Resource}, RESOURCE_IAM) != nil {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: Resource %v", filter.Resource),
+		}
+	}
+
 	if filter.Limit == 0 {
 		filter.Limit = DEFAULT_LIMIT_SIZE
 	} else if filter.Limit > MAX_LIMIT_SIZE {
@@ -372,6 +498,120 @@ func validateFilter(filter *Filter, validColumns []string) error {
 	return nil
 }
 
+// Check that the revision required by the request, if any, is the current revision of the entity
+func checkRevision(requestInfo RequestInfo, revision int) error {
+	if requestInfo.Revision != 0 && requestInfo.Revision != revision {
+		return &Error{
+			Code:    REVISION_MISMATCH,
+			Message: fmt.Sprintf("Revision %v required by request isn't the current revision %v", requestInfo.Revision, revision),
+		}
+	}
+	return nil
+}
+
+// Transform an error of an update or deletion conditioned by revision to API error
+func revisionError(err error) error {
+	dbError := err.(*database.Error)
+	switch dbError.Code {
+	case database.REVISION_MISMATCH:
+		return &Error{
+			Code:    REVISION_MISMATCH,
+			Message: dbError.Message,
+		}
+	default:
+		// Updates can also conflict with names stored by concurrent requests
+		return storeError(err)
+	}
+}
+
+// API error codes of entities and relations that already exist in database
+var alreadyExistErrorCodes = map[string]string{
+	database.USER_ALREADY_EXIST:                    USER_ALREADY_EXIST,
+	database.GROUP_ALREADY_EXIST:                   GROUP_ALREADY_EXIST,
+	database.POLICY_ALREADY_EXIST:                  POLICY_ALREADY_EXIST,
+	database.PROXY_RESOURCE_ALREADY_EXIST:          PROXY_RESOURCE_ALREADY_EXIST,
+	database.AUTH_OIDC_PROVIDER_ALREADY_EXIST:      AUTH_OIDC_PROVIDER_ALREADY_EXIST,
+	database.ORGANIZATION_ALREADY_EXIST:            ORGANIZATION_ALREADY_EXIST,
+	database.WEBHOOK_ALREADY_EXIST:                 WEBHOOK_ALREADY_EXIST,
+	database.GROUP_USER_RELATION_ALREADY_EXIST:     USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+	database.GROUP_SUBGROUP_RELATION_ALREADY_EXIST: GROUP_IS_ALREADY_A_SUBGROUP,
+	database.GROUP_POLICY_RELATION_ALREADY_EXIST:   POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+	database.USER_POLICY_RELATION_ALREADY_EXIST:    POLICY_IS_ALREADY_ATTACHED_TO_USER,
+}
+
+// Transform an error of an operation that stores entities or relations to API error. They are checked
+// before being stored, so they can only exist if they were stored by a concurrent request.
+func storeError(err error) error {
+	dbError := err.(*database.Error)
+	if code, ok := alreadyExistErrorCodes[dbError.Code]; ok {
+		return &Error{
+			Code:    code,
+			Message: dbError.Message,
+		}
+	}
+	return &Error{
+		Code:    UNKNOWN_API_ERROR,
+		Message: dbError.Message,
+	}
+}
+
+// Content of the opaque tokens used by keyset pagination
+type pageToken struct {
+	CreateAt int64  `json:"createAt"`
+	ID       string `json:"id"`
+}
+
+// Decode filter NextToken into the cursor used by keyset pagination
+func validateNextToken(filter *Filter) error {
+	if len(filter.NextToken) == 0 {
+		return nil
+	}
+	if len(filter.OrderBy) > 0 {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: NextToken can't be used with OrderBy",
+		}
+	}
+	if filter.Offset > 0 {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: NextToken can't be used with Offset",
+		}
+	}
+
+	token := pageToken{}
+	data, err := base64.RawURLEncoding.DecodeString(filter.NextToken)
+	if err == nil {
+		err = json.Unmarshal(data, &token)
+	}
+	if err != nil || len(token.ID) == 0 {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: NextToken %v", filter.NextToken),
+		}
+	}
+
+	filter.Cursor = &Cursor{
+		CreateAt: time.Unix(0, token.CreateAt).UTC(),
+		ID:       token.ID,
+	}
+	return nil
+}
+
+// Retrieve the token of the page after the last item listed. There is no next page when
+// current one isn't full, and keyset pagination can't be used with custom sorting.
+func nextPageToken(filter *Filter, items int, createAt time.Time, id string) string {
+	if items < filter.Limit || len(filter.OrderBy) > 0 {
+		return ""
+	}
+
+	data, _ := json.Marshal(pageToken{
+		CreateAt: createAt.UnixNano(),
+		ID:       id,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
 // Private Methods
 
 func errFunc(parameter string, value string) error {
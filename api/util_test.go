@@ -2,7 +2,11 @@ package api
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateUrn(t *testing.T) {
@@ -33,6 +37,12 @@ func TestCreateUrn(t *testing.T) {
 			name:        "policy",
 			expectedUrn: "urn:iws:iam:org1:policy/policypath/policy",
 		},
+		"OkCaseOrganizationResource": {
+			resource:    RESOURCE_ORGANIZATION,
+			path:        "/orgpath/",
+			name:        "org1",
+			expectedUrn: "urn:iws:iam::org/orgpath/org1",
+		},
 	}
 
 	for x, testcase := range testcases {
@@ -65,6 +75,11 @@ func TestGetUrnPrefix(t *testing.T) {
 			path:        "/policypath/",
 			expectedUrn: "urn:iws:iam:org1:policy/policypath/*",
 		},
+		"OkCaseOrganizationResourcePrefix": {
+			resource:    RESOURCE_ORGANIZATION,
+			path:        "/orgpath/",
+			expectedUrn: "urn:iws:iam::org/orgpath/*",
+		},
 	}
 
 	for x, testcase := range testcases {
@@ -315,6 +330,12 @@ func TestAreValidActions(t *testing.T) {
 				"iam:*",
 			},
 		},
+		"OKCaseValidActionWithGlob": {
+			actions: []string{
+				"iam:*Group*",
+				"*:Get?ser",
+			},
+		},
 		"ErrorCaseMalformedAction": {
 			actions: []string{
 				"iam:",
@@ -388,6 +409,121 @@ func TestAreValidStatements(t *testing.T) {
 				},
 			},
 		},
+		"OKCaseNotActionsNotResources": {
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					NotActions: []string{
+						USER_ACTION_DELETE_USER,
+					},
+					NotResources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/admin/"),
+					},
+				},
+			},
+		},
+		"ErrorCaseActionsAndNotActions": {
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						USER_ACTION_GET_USER,
+					},
+					NotActions: []string{
+						USER_ACTION_DELETE_USER,
+					},
+					Resources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/path/"),
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Actions and notActions can't be defined in the same statement",
+			},
+		},
+		"ErrorCaseEmptyActions": {
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					Resources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/path/"),
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Empty actions",
+			},
+		},
+		"ErrorCaseInvalidNotAction": {
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					NotActions: []string{
+						"fail***",
+					},
+					Resources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/path/"),
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    REGEX_NO_MATCH,
+				Message: "Invalid parameter action, value: fail***",
+			},
+		},
+		"ErrorCaseResourcesAndNotResources": {
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						USER_ACTION_GET_USER,
+					},
+					Resources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/path/"),
+					},
+					NotResources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/admin/"),
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Resources and notResources can't be defined in the same statement",
+			},
+		},
+		"ErrorCaseEmptyResources": {
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						USER_ACTION_GET_USER,
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Empty resources",
+			},
+		},
+		"ErrorCaseInvalidNotResource": {
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						USER_ACTION_GET_USER,
+					},
+					NotResources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/path/***"),
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    REGEX_NO_MATCH,
+				Message: "Invalid parameter urn, value: urn:iws:iam::user/path/****",
+			},
+		},
 		"ErrorCaseInvalidEffect": {
 			Statements: &[]Statement{
 				{
@@ -591,6 +727,62 @@ func TestAreValidResources(t *testing.T) {
 				Message: "Invalid parameter urn, value: urn:iws:iam:org1:fail**!^_#",
 			},
 		},
+		"OKCaseGlob": {
+			Resources: []string{
+				"urn:ews:shop:*:order/eu/*",
+				"urn:ews:sh?p:instance1:order/*/order?",
+				"urn:*ws:shop*",
+			},
+			resourceType: RESOURCE_IAM,
+		},
+		"ErrorCaseGlobDoubleWildcard": {
+			Resources: []string{
+				"urn:ews:shop:**:order",
+			},
+			resourceType: RESOURCE_IAM,
+			wantError: &Error{
+				Code:    REGEX_NO_MATCH,
+				Message: "Invalid parameter urn, value: urn:ews:shop:**:order",
+			},
+		},
+		"ErrorCaseGlobInExternalResource": {
+			Resources: []string{
+				"urn:ews:shop:*:order/eu/order1",
+			},
+			resourceType: RESOURCE_EXTERNAL,
+			wantError: &Error{
+				Code:    REGEX_NO_MATCH,
+				Message: "Invalid parameter urn, value: urn:ews:shop:*:order/eu/order1",
+			},
+		},
+		"OKCasePolicyVariables": {
+			Resources: []string{
+				"urn:iws:iam::user${user.path}${user.externalId}",
+				"urn:iws:iam::user${user.path}*",
+				"urn:iws:iam:${request.org}:group/*",
+			},
+			resourceType: RESOURCE_IAM,
+		},
+		"ErrorCaseUnknownPolicyVariable": {
+			Resources: []string{
+				"urn:iws:iam::user/${user.name}",
+			},
+			resourceType: RESOURCE_IAM,
+			wantError: &Error{
+				Code:    REGEX_NO_MATCH,
+				Message: "Invalid parameter urn, value: urn:iws:iam::user/${user.name}",
+			},
+		},
+		"ErrorCasePolicyVariablesInExternalResource": {
+			Resources: []string{
+				"urn:ews:product:instance:user${user.path}${user.externalId}",
+			},
+			resourceType: RESOURCE_EXTERNAL,
+			wantError: &Error{
+				Code:    REGEX_NO_MATCH,
+				Message: "Invalid parameter urn, value: urn:ews:product:instance:user${user.path}${user.externalId}",
+			},
+		},
 		"ErrorCaseBadResource": {
 			Resources: []string{
 				"urn:iws:iam:org1:fail:fail:fail",
@@ -658,6 +850,84 @@ func TestValidateFilter(t *testing.T) {
 				Message: "Invalid parameter: pathPrefix fail",
 			},
 		},
+		"OKCaseSearch": {
+			filter: &Filter{
+				NamePrefix:     "grp",
+				NameContains:   "admin",
+				CreatedAfter:   time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore:  time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAfter:   time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC),
+				Member:         "123",
+				AttachedPolicy: "p1",
+				Action:         "iam:*",
+				Resource:       "urn:iws:iam:org:user/path/*",
+			},
+		},
+		"ErrorCaseInvalidNameContains": {
+			filter: &Filter{
+				NameContains: strings.Repeat("a", MAX_NAME_LENGTH+1),
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: NameContains " + strings.Repeat("a", MAX_NAME_LENGTH+1),
+			},
+		},
+		"ErrorCaseInvalidCreatedRange": {
+			filter: &Filter{
+				CreatedAfter:  time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: CreatedBefore 2016-01-01T00:00:00Z is previous to CreatedAfter 2017-01-01T00:00:00Z",
+			},
+		},
+		"ErrorCaseInvalidUpdatedRange": {
+			filter: &Filter{
+				UpdatedAfter:  time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedBefore: time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: UpdatedBefore 2016-01-01T00:00:00Z is previous to UpdatedAfter 2017-01-01T00:00:00Z",
+			},
+		},
+		"ErrorCaseInvalidMember": {
+			filter: &Filter{
+				Member: "!*@~#",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Member !*@~#",
+			},
+		},
+		"ErrorCaseInvalidAttachedPolicy": {
+			filter: &Filter{
+				AttachedPolicy: "!*@~#",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: AttachedPolicy !*@~#",
+			},
+		},
+		"ErrorCaseInvalidAction": {
+			filter: &Filter{
+				Action: "iam:**",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Action iam:**",
+			},
+		},
+		"ErrorCaseInvalidResource": {
+			filter: &Filter{
+				Resource: "invalid",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Resource invalid",
+			},
+		},
 		"ErrorCaseInvalidLimit": {
 			filter: &Filter{
 				ExternalID: "123",
@@ -753,6 +1023,112 @@ func TestValidateFilter(t *testing.T) {
 	}
 }
 
+func TestValidateNextToken(t *testing.T) {
+	createAt := time.Date(2016, time.October, 1, 10, 30, 0, 500, time.UTC)
+	testcases := map[string]struct {
+		// Method args
+		filter *Filter
+		// Expected results
+		expectedCursor *Cursor
+		wantError      error
+	}{
+		"OKCase": {
+			filter: &Filter{
+				NextToken: nextPageToken(&Filter{Limit: 1}, 1, createAt, "123"),
+			},
+			expectedCursor: &Cursor{
+				CreateAt: createAt,
+				ID:       "123",
+			},
+		},
+		"OKCaseNoToken": {
+			filter: &Filter{
+				Offset: 2,
+			},
+		},
+		"ErrorCaseInvalidToken": {
+			filter: &Filter{
+				NextToken: "invalid!",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: NextToken invalid!",
+			},
+		},
+		"ErrorCaseTokenWithoutID": {
+			filter: &Filter{
+				NextToken: "e30",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: NextToken e30",
+			},
+		},
+		"ErrorCaseTokenWithOrderBy": {
+			filter: &Filter{
+				NextToken: nextPageToken(&Filter{Limit: 1}, 1, createAt, "123"),
+				OrderBy:   "name desc",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: NextToken can't be used with OrderBy",
+			},
+		},
+		"ErrorCaseTokenWithOffset": {
+			filter: &Filter{
+				NextToken: nextPageToken(&Filter{Limit: 1}, 1, createAt, "123"),
+				Offset:    2,
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: NextToken can't be used with Offset",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		err := validateNextToken(testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedCursor, testcase.filter.Cursor)
+	}
+}
+
+func TestNextPageToken(t *testing.T) {
+	createAt := time.Date(2016, time.October, 1, 10, 30, 0, 0, time.UTC)
+	testcases := map[string]struct {
+		// Method args
+		filter *Filter
+		items  int
+		// Expected results
+		expectedToken bool
+	}{
+		"OKCaseFullPage": {
+			filter: &Filter{
+				Limit: 2,
+			},
+			items:         2,
+			expectedToken: true,
+		},
+		"OKCaseLastPage": {
+			filter: &Filter{
+				Limit: 2,
+			},
+			items: 1,
+		},
+		"OKCaseCustomOrder": {
+			filter: &Filter{
+				Limit:   2,
+				OrderBy: "name desc",
+			},
+			items: 2,
+		},
+	}
+
+	for x, testcase := range testcases {
+		token := nextPageToken(testcase.filter, testcase.items, createAt, "123")
+		assert.Equal(t, testcase.expectedToken, token != "", "Error in test case %v", x)
+	}
+}
+
 func TestIsValidProxyResource(t *testing.T) {
 	testcases := map[string]struct {
 		// Method args
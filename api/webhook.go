@@ -0,0 +1,646 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// Event types
+	USER_EVENT_CREATED         = "user.created"
+	USER_EVENT_UPDATED         = "user.updated"
+	USER_EVENT_DELETED         = "user.deleted"
+	USER_EVENT_RESTORED        = "user.restored"
+	USER_EVENT_POLICY_ATTACHED = "user.policy_attached"
+	USER_EVENT_POLICY_DETACHED = "user.policy_detached"
+
+	GROUP_EVENT_CREATED          = "group.created"
+	GROUP_EVENT_UPDATED          = "group.updated"
+	GROUP_EVENT_DELETED          = "group.deleted"
+	GROUP_EVENT_RESTORED         = "group.restored"
+	GROUP_EVENT_MEMBER_ADDED     = "group.member_added"
+	GROUP_EVENT_MEMBER_REMOVED   = "group.member_removed"
+	GROUP_EVENT_SUBGROUP_ADDED   = "group.subgroup_added"
+	GROUP_EVENT_SUBGROUP_REMOVED = "group.subgroup_removed"
+	GROUP_EVENT_POLICY_ATTACHED  = "group.policy_attached"
+	GROUP_EVENT_POLICY_DETACHED  = "group.policy_detached"
+
+	POLICY_EVENT_CREATED             = "policy.created"
+	POLICY_EVENT_UPDATED             = "policy.updated"
+	POLICY_EVENT_DELETED             = "policy.deleted"
+	POLICY_EVENT_RESTORED            = "policy.restored"
+	POLICY_EVENT_DEFAULT_VERSION_SET = "policy.default_version_set"
+
+	PROXY_RESOURCE_EVENT_CREATED = "proxy_resource.created"
+	PROXY_RESOURCE_EVENT_UPDATED = "proxy_resource.updated"
+	PROXY_RESOURCE_EVENT_DELETED = "proxy_resource.deleted"
+
+	ORGANIZATION_EVENT_CREATED = "organization.created"
+	ORGANIZATION_EVENT_UPDATED = "organization.updated"
+	ORGANIZATION_EVENT_DELETED = "organization.deleted"
+
+	OIDC_PROVIDER_EVENT_CREATED = "oidc_provider.created"
+	OIDC_PROVIDER_EVENT_UPDATED = "oidc_provider.updated"
+	OIDC_PROVIDER_EVENT_DELETED = "oidc_provider.deleted"
+
+	// Webhooks subscribed to this event type receive all events
+	ALL_EVENTS = "*"
+
+	// Webhook delivery status
+	WEBHOOK_DELIVERY_PENDING   = "pending"
+	WEBHOOK_DELIVERY_DELIVERED = "delivered"
+	WEBHOOK_DELIVERY_FAILED    = "failed"
+
+	// Headers of webhook requests
+	WEBHOOK_EVENT_HEADER     = "X-Foulkon-Event"
+	WEBHOOK_DELIVERY_HEADER  = "X-Foulkon-Delivery"
+	WEBHOOK_TIMESTAMP_HEADER = "X-Foulkon-Timestamp"
+	WEBHOOK_SIGNATURE_HEADER = "X-Foulkon-Signature"
+
+	// Constraints
+	MAX_WEBHOOK_URL_LENGTH    = 2048
+	MAX_WEBHOOK_SECRET_LENGTH = 256
+	MAX_WEBHOOK_DELIVERIES    = 100
+	MAX_WEBHOOK_ERROR_LENGTH  = 1024
+)
+
+// Time claimed deliveries are kept from other workers while they are sent. Deliveries claimed by a
+// worker that stops before updating them are pending again when it expires.
+const WEBHOOK_DELIVERY_LEASE = 5 * time.Minute
+
+// Event types webhooks can be subscribed to
+var eventTypes = map[string]bool{
+	USER_EVENT_CREATED:               true,
+	USER_EVENT_UPDATED:               true,
+	USER_EVENT_DELETED:               true,
+	USER_EVENT_RESTORED:              true,
+	USER_EVENT_POLICY_ATTACHED:       true,
+	USER_EVENT_POLICY_DETACHED:       true,
+	GROUP_EVENT_CREATED:              true,
+	GROUP_EVENT_UPDATED:              true,
+	GROUP_EVENT_DELETED:              true,
+	GROUP_EVENT_RESTORED:             true,
+	GROUP_EVENT_MEMBER_ADDED:         true,
+	GROUP_EVENT_MEMBER_REMOVED:       true,
+	GROUP_EVENT_SUBGROUP_ADDED:       true,
+	GROUP_EVENT_SUBGROUP_REMOVED:     true,
+	GROUP_EVENT_POLICY_ATTACHED:      true,
+	GROUP_EVENT_POLICY_DETACHED:      true,
+	POLICY_EVENT_CREATED:             true,
+	POLICY_EVENT_UPDATED:             true,
+	POLICY_EVENT_DELETED:             true,
+	POLICY_EVENT_RESTORED:            true,
+	POLICY_EVENT_DEFAULT_VERSION_SET: true,
+	PROXY_RESOURCE_EVENT_CREATED:     true,
+	PROXY_RESOURCE_EVENT_UPDATED:     true,
+	PROXY_RESOURCE_EVENT_DELETED:     true,
+	ORGANIZATION_EVENT_CREATED:       true,
+	ORGANIZATION_EVENT_UPDATED:       true,
+	ORGANIZATION_EVENT_DELETED:       true,
+	OIDC_PROVIDER_EVENT_CREATED:      true,
+	OIDC_PROVIDER_EVENT_UPDATED:      true,
+	OIDC_PROVIDER_EVENT_DELETED:      true,
+}
+
+// TYPE DEFINITIONS
+
+// Webhook notified of the IAM changes of its event types
+type Webhook struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+	// Key used to sign deliveries, it's never returned
+	Secret   string    `json:"-"`
+	Events   []string  `json:"events,omitempty"`
+	CreateAt time.Time `json:"createAt,omitempty"`
+	UpdateAt time.Time `json:"updateAt,omitempty"`
+}
+
+func (w Webhook) String() string {
+	return fmt.Sprintf("[id: %v, name: %v, url: %v, events: %v, createAt: %v, updateAt: %v]",
+		w.ID, w.Name, w.URL, w.Events, w.CreateAt.Format("2006-01-02 15:04:05 MST"), w.UpdateAt.Format("2006-01-02 15:04:05 MST"))
+}
+
+// isSubscribed returns true if the webhook has to be notified of events of eventType
+func (w Webhook) isSubscribed(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType || e == ALL_EVENTS {
+			return true
+		}
+	}
+	return false
+}
+
+// Event of a change done by a request, Resource is the entity changed and Related
+// the other entity of the relation changed, if any
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Resource   interface{} `json:"resource"`
+	Related    interface{} `json:"related,omitempty"`
+	Identifier string      `json:"identifier,omitempty"`
+	RequestID  string      `json:"requestId,omitempty"`
+	CreateAt   time.Time   `json:"createAt"`
+}
+
+// WebhookDelivery of an event to a webhook, with the result of its last attempt
+type WebhookDelivery struct {
+	ID            string    `json:"id,omitempty"`
+	WebhookID     string    `json:"-"`
+	EventID       string    `json:"eventId,omitempty"`
+	EventType     string    `json:"eventType,omitempty"`
+	Payload       string    `json:"-"`
+	Status        string    `json:"status,omitempty"`
+	Attempts      int       `json:"attempts"`
+	ResponseCode  int       `json:"responseCode,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	NextAttemptAt time.Time `json:"nextAttemptAt,omitempty"`
+	CreateAt      time.Time `json:"createAt,omitempty"`
+	UpdateAt      time.Time `json:"updateAt,omitempty"`
+}
+
+// WEBHOOK API IMPLEMENTATION
+
+func (api WorkerAPI) AddWebhook(requestInfo RequestInfo, name string, url string, secret string, events []string) (*Webhook, error) {
+	// Check restrictions
+	if err := checkWebhookAdmin(requestInfo); err != nil {
+		return nil, err
+	}
+
+	// Validate fields
+	if err := validateWebhook(name, url, secret, events); err != nil {
+		return nil, err
+	}
+
+	// Check if webhook already exists
+	_, err := api.WebhookRepo.GetWebhookByName(name)
+
+	// Check if webhook could be retrieved
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		switch dbError.Code {
+		// Webhook doesn't exist in DB
+		case database.WEBHOOK_NOT_FOUND:
+			now := time.Now().UTC()
+			webhook := Webhook{
+				ID:       uuid.NewV4().String(),
+				Name:     name,
+				URL:      url,
+				Secret:   secret,
+				Events:   events,
+				CreateAt: now,
+				UpdateAt: now,
+			}
+
+			// Create webhook
+			createdWebhook, err := api.WebhookRepo.AddWebhook(webhook)
+
+			// Check if there is an unexpected error in DB
+			if err != nil {
+				return nil, storeError(err)
+			}
+
+			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Webhook created %+v", createdWebhook))
+			return createdWebhook, nil
+		default: // Unexpected error
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+	} else { // Fail if webhook exists
+		return nil, &Error{
+			Code:    WEBHOOK_ALREADY_EXIST,
+			Message: fmt.Sprintf("Unable to create webhook, webhook with name %v already exist", name),
+		}
+	}
+}
+
+func (api WorkerAPI) GetWebhookByName(requestInfo RequestInfo, name string) (*Webhook, error) {
+	// Check restrictions
+	if err := checkWebhookAdmin(requestInfo); err != nil {
+		return nil, err
+	}
+
+	// Validate fields
+	if !IsValidName(name) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+
+	// Call repo to retrieve the webhook
+	webhook, err := api.WebhookRepo.GetWebhookByName(name)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		// Webhook doesn't exist in DB
+		if dbError.Code == database.WEBHOOK_NOT_FOUND {
+			return nil, &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	return webhook, nil
+}
+
+func (api WorkerAPI) ListWebhooks(requestInfo RequestInfo, filter *Filter) ([]string, int, error) {
+	// Check restrictions
+	var total int
+	if err := checkWebhookAdmin(requestInfo); err != nil {
+		return nil, total, err
+	}
+
+	// Validate fields
+	orderByValidColumns := api.WebhookRepo.OrderByValidColumns(WEBHOOK_ACTION_LIST_WEBHOOKS)
+	if err := validateFilter(filter, orderByValidColumns); err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the webhooks
+	webhooks, total, err := api.WebhookRepo.GetWebhooksFiltered(filter)
+
+	// Error handling
+	if err != nil {
+		return nil, total, dbErrorToAPIError(err)
+	}
+
+	webhookNames := []string{}
+	for _, w := range webhooks {
+		webhookNames = append(webhookNames, w.Name)
+	}
+
+	return webhookNames, total, nil
+}
+
+func (api WorkerAPI) UpdateWebhook(requestInfo RequestInfo, name string, newName string, newURL string, newSecret string,
+	newEvents []string) (*Webhook, error) {
+	// Validate fields
+	if err := validateWebhook(newName, newURL, newSecret, newEvents); err != nil {
+		return nil, err
+	}
+
+	// Call repo to retrieve the old webhook
+	oldWebhook, err := api.GetWebhookByName(requestInfo, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if there is another webhook with the new name
+	if oldWebhook.Name != newName {
+		_, err := api.WebhookRepo.GetWebhookByName(newName)
+		if err == nil {
+			return nil, &Error{
+				Code:    WEBHOOK_ALREADY_EXIST,
+				Message: fmt.Sprintf("Webhook name: %v already exists", newName),
+			}
+		}
+		if dbError := err.(*database.Error); dbError.Code != database.WEBHOOK_NOT_FOUND {
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+	}
+
+	webhook := Webhook{
+		ID:       oldWebhook.ID,
+		Name:     newName,
+		URL:      newURL,
+		Secret:   newSecret,
+		Events:   newEvents,
+		CreateAt: oldWebhook.CreateAt,
+		UpdateAt: time.Now().UTC(),
+	}
+
+	// Update webhook
+	updatedWebhook, err := api.WebhookRepo.UpdateWebhook(webhook)
+
+	// Check unexpected DB error
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Webhook updated from %+v to %+v", oldWebhook, updatedWebhook))
+	return updatedWebhook, nil
+}
+
+func (api WorkerAPI) RemoveWebhook(requestInfo RequestInfo, name string) error {
+	// Call repo to retrieve the webhook
+	webhook, err := api.GetWebhookByName(requestInfo, name)
+	if err != nil {
+		return err
+	}
+
+	err = api.WebhookRepo.RemoveWebhook(webhook.ID)
+
+	// Error handling
+	if err != nil {
+		return dbErrorToAPIError(err)
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Webhook deleted %v", webhook))
+	return nil
+}
+
+func (api WorkerAPI) ListWebhookDeliveries(requestInfo RequestInfo, filter *Filter) ([]WebhookDelivery, int, error) {
+	// Check restrictions
+	var total int
+	if err := checkWebhookAdmin(requestInfo); err != nil {
+		return nil, total, err
+	}
+
+	// Validate fields
+	orderByValidColumns := api.WebhookRepo.OrderByValidColumns(WEBHOOK_ACTION_LIST_DELIVERIES)
+	if err := validateFilter(filter, orderByValidColumns); err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the webhook
+	webhook, err := api.GetWebhookByName(requestInfo, filter.WebhookName)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the deliveries
+	deliveries, total, err := api.WebhookRepo.GetWebhookDeliveries(webhook.ID, filter)
+
+	// Error handling
+	if err != nil {
+		return nil, total, dbErrorToAPIError(err)
+	}
+
+	return deliveries, total, nil
+}
+
+// INTERNAL WEBHOOK API IMPLEMENTATION
+
+func (api WorkerAPI) DeliverWebhookEvents(client *http.Client, maxAttempts int, backoff time.Duration) (int, error) {
+	// Claim deliveries, so workers running concurrently don't send them too
+	claimedAt := time.Now().UTC()
+	deliveries, err := api.WebhookRepo.ClaimWebhookDeliveries(claimedAt, WEBHOOK_DELIVERY_LEASE, MAX_WEBHOOK_DELIVERIES)
+	if err != nil {
+		return 0, dbErrorToAPIError(err)
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	webhooks, _, err := api.WebhookRepo.GetWebhooksFiltered(&Filter{})
+	if err != nil {
+		return 0, dbErrorToAPIError(err)
+	}
+	webhooksByID := make(map[string]Webhook, len(webhooks))
+	for _, w := range webhooks {
+		webhooksByID[w.ID] = w
+	}
+
+	sent := 0
+	for _, delivery := range deliveries {
+		// Deliveries left can be claimed by other workers once the lease expires
+		if time.Since(claimedAt) >= WEBHOOK_DELIVERY_LEASE {
+			break
+		}
+
+		// Deliveries of removed webhooks are removed with them
+		webhook, ok := webhooksByID[delivery.WebhookID]
+		if !ok {
+			continue
+		}
+
+		claimedUntil := delivery.NextAttemptAt
+		responseCode, err := sendWebhookDelivery(client, webhook, delivery)
+		now := time.Now().UTC()
+		delivery.Attempts++
+		delivery.ResponseCode = responseCode
+		delivery.UpdateAt = now
+		if err == nil {
+			delivery.Status = WEBHOOK_DELIVERY_DELIVERED
+			delivery.Error = ""
+		} else {
+			delivery.Error = truncate(err.Error(), MAX_WEBHOOK_ERROR_LENGTH)
+			if delivery.Attempts >= maxAttempts {
+				delivery.Status = WEBHOOK_DELIVERY_FAILED
+			} else {
+				delivery.NextAttemptAt = now.Add(backoff << uint(delivery.Attempts-1))
+			}
+		}
+
+		if err := api.WebhookRepo.UpdateWebhookDelivery(delivery, claimedUntil); err != nil {
+			// Lease expired and another worker claimed the delivery, its result is kept
+			if dbError, ok := err.(*database.Error); ok && dbError.Code == database.WEBHOOK_DELIVERY_NOT_CLAIMED {
+				continue
+			}
+			return sent, dbErrorToAPIError(err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (api WorkerAPI) PurgeWebhookDeliveries(updatedBefore time.Time) (int, error) {
+	purged, err := api.WebhookRepo.PurgeWebhookDeliveries(updatedBefore)
+
+	// Error handling
+	if err != nil {
+		return 0, dbErrorToAPIError(err)
+	}
+
+	return purged, nil
+}
+
+// PRIVATE HELPER METHODS
+
+// emitEvent publishes the event of a change done by requestInfo. Inside a transaction it's kept
+// until the transaction is committed.
+func (api WorkerAPI) emitEvent(requestInfo RequestInfo, eventType string, resource interface{}, related interface{}) {
+	event := Event{
+		ID:         uuid.NewV4().String(),
+		Type:       eventType,
+		Resource:   resource,
+		Related:    related,
+		Identifier: requestInfo.Identifier,
+		RequestID:  requestInfo.RequestID,
+		CreateAt:   time.Now().UTC(),
+	}
+	if api.events != nil {
+		*api.events = append(*api.events, event)
+		return
+	}
+	api.publishEvents([]Event{event})
+}
+
+// publishEvents stores a pending delivery of every event to each webhook subscribed to its type. Changes
+// are already stored when their events are published, so errors are logged instead of returned.
+func (api WorkerAPI) publishEvents(events []Event) {
+	if api.WebhookRepo == nil || len(events) == 0 {
+		return
+	}
+
+	webhooks, _, err := api.WebhookRepo.GetWebhooksFiltered(&Filter{})
+	if err != nil {
+		logEventsError(events[0], err)
+		return
+	}
+
+	deliveries := []WebhookDelivery{}
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			logEventsError(event, err)
+			continue
+		}
+		for _, webhook := range webhooks {
+			if !webhook.isSubscribed(event.Type) {
+				continue
+			}
+			deliveries = append(deliveries, WebhookDelivery{
+				ID:            uuid.NewV4().String(),
+				WebhookID:     webhook.ID,
+				EventID:       event.ID,
+				EventType:     event.Type,
+				Payload:       string(payload),
+				Status:        WEBHOOK_DELIVERY_PENDING,
+				NextAttemptAt: event.CreateAt,
+				CreateAt:      event.CreateAt,
+				UpdateAt:      event.CreateAt,
+			})
+		}
+	}
+
+	if len(deliveries) > 0 {
+		if err := api.WebhookRepo.AddWebhookDeliveries(deliveries); err != nil {
+			logEventsError(events[0], err)
+		}
+	}
+}
+
+func logEventsError(event Event, err error) {
+	message := err.Error()
+	if dbError, ok := err.(*database.Error); ok {
+		message = dbError.Message
+	}
+	LogOperationError(event.RequestID, event.Identifier, &Error{
+		Code:    UNKNOWN_API_ERROR,
+		Message: fmt.Sprintf("Unable to publish events: %v", message),
+	})
+}
+
+// sendWebhookDelivery posts the delivery payload to the webhook, signed with its secret. It returns
+// the response status code, and an error if the request failed or the status code isn't 2xx.
+func sendWebhookDelivery(client *http.Client, webhook Webhook, delivery WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WEBHOOK_EVENT_HEADER, delivery.EventType)
+	req.Header.Set(WEBHOOK_DELIVERY_HEADER, delivery.ID)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(WEBHOOK_TIMESTAMP_HEADER, timestamp)
+	req.Header.Set(WEBHOOK_SIGNATURE_HEADER, SignWebhookPayload(webhook.Secret, timestamp, []byte(delivery.Payload)))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// Drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("Unexpected response status %v", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// SignWebhookPayload returns the value of the signature header of a webhook request, which is the
+// hex encoded HMAC-SHA256 of the timestamp header, a dot and the payload, with the webhook secret as key.
+// Signing the timestamp lets receivers reject old requests replayed with a valid signature.
+func SignWebhookPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Webhooks are only managed by admins
+func checkWebhookAdmin(requestInfo RequestInfo) error {
+	if !requestInfo.Admin {
+		return &Error{
+			Code:    UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to manage webhooks", requestInfo.Identifier),
+		}
+	}
+	return nil
+}
+
+func validateWebhook(name string, webhookURL string, secret string, events []string) error {
+	if !IsValidName(name) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+	if u, err := url.Parse(webhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 ||
+		len(webhookURL) > MAX_WEBHOOK_URL_LENGTH {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: url %v", webhookURL),
+		}
+	}
+	if len(secret) == 0 || len(secret) > MAX_WEBHOOK_SECRET_LENGTH {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: secret, it must have between 1 and %v characters", MAX_WEBHOOK_SECRET_LENGTH),
+		}
+	}
+	if len(events) == 0 {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: events, at least one event type is required",
+		}
+	}
+	seen := map[string]bool{}
+	for _, e := range events {
+		if (!eventTypes[e] && e != ALL_EVENTS) || seen[e] {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: event %v", e),
+			}
+		}
+		seen[e] = true
+	}
+	return nil
+}
+
+func truncate(s string, length int) string {
+	if len(s) > length {
+		return s[:length]
+	}
+	return s
+}
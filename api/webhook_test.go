@@ -0,0 +1,1016 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerAPI_AddWebhook(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		name        string
+		url         string
+		secret      string
+		events      []string
+		// Expected results
+		expectedWebhook *Webhook
+		wantError       error
+		// Manager Results
+		getWebhookByNameResult    *Webhook
+		getWebhookByNameMethodErr error
+		addWebhookMethodResult    *Webhook
+		addWebhookMethodErr       error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			events: []string{USER_EVENT_CREATED, GROUP_EVENT_MEMBER_ADDED},
+			getWebhookByNameMethodErr: &database.Error{
+				Code: database.WEBHOOK_NOT_FOUND,
+			},
+			addWebhookMethodResult: &Webhook{
+				ID:     "HOOK-ID",
+				Name:   "hook1",
+				URL:    "https://example.com/hook",
+				Events: []string{USER_EVENT_CREATED, GROUP_EVENT_MEMBER_ADDED},
+			},
+			expectedWebhook: &Webhook{
+				ID:     "HOOK-ID",
+				Name:   "hook1",
+				URL:    "https://example.com/hook",
+				Events: []string{USER_EVENT_CREATED, GROUP_EVENT_MEMBER_ADDED},
+			},
+		},
+		"ErrorCaseNotAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			events: []string{ALL_EVENTS},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to manage webhooks",
+			},
+		},
+		"ErrorCaseBadName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "**!^#~",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			events: []string{ALL_EVENTS},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name **!^#~",
+			},
+		},
+		"ErrorCaseBadURL": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "ftp://example.com/hook",
+			secret: "secret",
+			events: []string{ALL_EVENTS},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: url ftp://example.com/hook",
+			},
+		},
+		"ErrorCaseEmptySecret": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			events: []string{ALL_EVENTS},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: secret, it must have between 1 and 256 characters",
+			},
+		},
+		"ErrorCaseNoEvents": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: events, at least one event type is required",
+			},
+		},
+		"ErrorCaseUnknownEvent": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			events: []string{"user.renamed"},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: event user.renamed",
+			},
+		},
+		"ErrorCaseDuplicatedEvent": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			events: []string{USER_EVENT_CREATED, USER_EVENT_CREATED},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: event user.created",
+			},
+		},
+		"ErrorCaseWebhookAlreadyExists": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			events: []string{ALL_EVENTS},
+			getWebhookByNameResult: &Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_ALREADY_EXIST,
+				Message: "Unable to create webhook, webhook with name hook1 already exist",
+			},
+		},
+		"ErrorCaseGetWebhookDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			events: []string{ALL_EVENTS},
+			getWebhookByNameMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseAddWebhookDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:   "hook1",
+			url:    "https://example.com/hook",
+			secret: "secret",
+			events: []string{ALL_EVENTS},
+			getWebhookByNameMethodErr: &database.Error{
+				Code: database.WEBHOOK_NOT_FOUND,
+			},
+			addWebhookMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetWebhookByNameMethod][0] = test.getWebhookByNameResult
+		testRepo.ArgsOut[GetWebhookByNameMethod][1] = test.getWebhookByNameMethodErr
+		testRepo.ArgsOut[AddWebhookMethod][0] = test.addWebhookMethodResult
+		testRepo.ArgsOut[AddWebhookMethod][1] = test.addWebhookMethodErr
+
+		webhook, err := testAPI.AddWebhook(test.requestInfo, test.name, test.url, test.secret, test.events)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedWebhook, webhook)
+		if test.wantError == nil {
+			stored := testRepo.ArgsIn[AddWebhookMethod][0].(Webhook)
+			assert.Equal(t, test.secret, stored.Secret, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerAPI_GetWebhookByName(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		name        string
+		// Expected results
+		expectedWebhook *Webhook
+		wantError       error
+		// Manager Results
+		getWebhookByNameResult    *Webhook
+		getWebhookByNameMethodErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "hook1",
+			getWebhookByNameResult: &Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+			},
+			expectedWebhook: &Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+			},
+		},
+		"ErrorCaseNotAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+			},
+			name: "hook1",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to manage webhooks",
+			},
+		},
+		"ErrorCaseBadName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "**!^#~",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name **!^#~",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "hook1",
+			getWebhookByNameMethodErr: &database.Error{
+				Code:    database.WEBHOOK_NOT_FOUND,
+				Message: "Webhook with name hook1 not found",
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook with name hook1 not found",
+			},
+		},
+		"ErrorCaseDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "hook1",
+			getWebhookByNameMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetWebhookByNameMethod][0] = test.getWebhookByNameResult
+		testRepo.ArgsOut[GetWebhookByNameMethod][1] = test.getWebhookByNameMethodErr
+
+		webhook, err := testAPI.GetWebhookByName(test.requestInfo, test.name)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedWebhook, webhook)
+	}
+}
+
+func TestWorkerAPI_ListWebhooks(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedWebhooks []string
+		totalResult      int
+		wantError        error
+		// Manager Results
+		getWebhooksFilteredResult    []Webhook
+		getWebhooksFilteredMethodErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter:           &testFilter,
+			expectedWebhooks: []string{"hook1", "hook2"},
+			totalResult:      2,
+			getWebhooksFilteredResult: []Webhook{
+				{
+					ID:   "HOOK-ID-1",
+					Name: "hook1",
+				},
+				{
+					ID:   "HOOK-ID-2",
+					Name: "hook2",
+				},
+			},
+		},
+		"ErrorCaseNotAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+			},
+			filter: &testFilter,
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to manage webhooks",
+			},
+		},
+		"ErrorCaseInvalidFilter": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Limit: 10000,
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: limit 10000, max limit allowed: 1000",
+			},
+		},
+		"ErrorCaseDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &testFilter,
+			getWebhooksFilteredMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetWebhooksFilteredMethod][0] = test.getWebhooksFilteredResult
+		testRepo.ArgsOut[GetWebhooksFilteredMethod][1] = test.totalResult
+		testRepo.ArgsOut[GetWebhooksFilteredMethod][2] = test.getWebhooksFilteredMethodErr
+
+		webhooks, total, err := testAPI.ListWebhooks(test.requestInfo, test.filter)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedWebhooks, webhooks)
+		if test.wantError == nil {
+			assert.Equal(t, test.totalResult, total, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerAPI_UpdateWebhook(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		name        string
+		newName     string
+		newURL      string
+		newSecret   string
+		newEvents   []string
+		// Expected results
+		expectedWebhook *Webhook
+		wantError       error
+		// Manager Results
+		getWebhookByNameResult map[string]*Webhook
+		getWebhookByNameErr    map[string]error
+		updateWebhookResult    *Webhook
+		updateWebhookErr       error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:      "hook1",
+			newName:   "hook2",
+			newURL:    "http://example.com/new",
+			newSecret: "newsecret",
+			newEvents: []string{POLICY_EVENT_UPDATED},
+			getWebhookByNameResult: map[string]*Webhook{
+				"hook1": {
+					ID:   "HOOK-ID",
+					Name: "hook1",
+				},
+			},
+			getWebhookByNameErr: map[string]error{
+				"hook2": &database.Error{
+					Code: database.WEBHOOK_NOT_FOUND,
+				},
+			},
+			updateWebhookResult: &Webhook{
+				ID:     "HOOK-ID",
+				Name:   "hook2",
+				URL:    "http://example.com/new",
+				Events: []string{POLICY_EVENT_UPDATED},
+			},
+			expectedWebhook: &Webhook{
+				ID:     "HOOK-ID",
+				Name:   "hook2",
+				URL:    "http://example.com/new",
+				Events: []string{POLICY_EVENT_UPDATED},
+			},
+		},
+		"ErrorCaseBadURL": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:      "hook1",
+			newName:   "hook1",
+			newURL:    "example.com",
+			newSecret: "secret",
+			newEvents: []string{ALL_EVENTS},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: url example.com",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:      "hook1",
+			newName:   "hook1",
+			newURL:    "http://example.com",
+			newSecret: "secret",
+			newEvents: []string{ALL_EVENTS},
+			getWebhookByNameErr: map[string]error{
+				"hook1": &database.Error{
+					Code:    database.WEBHOOK_NOT_FOUND,
+					Message: "Webhook with name hook1 not found",
+				},
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook with name hook1 not found",
+			},
+		},
+		"ErrorCaseNewNameAlreadyExists": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:      "hook1",
+			newName:   "hook2",
+			newURL:    "http://example.com",
+			newSecret: "secret",
+			newEvents: []string{ALL_EVENTS},
+			getWebhookByNameResult: map[string]*Webhook{
+				"hook1": {
+					ID:   "HOOK-ID",
+					Name: "hook1",
+				},
+				"hook2": {
+					ID:   "HOOK-ID-2",
+					Name: "hook2",
+				},
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_ALREADY_EXIST,
+				Message: "Webhook name: hook2 already exists",
+			},
+		},
+		"ErrorCaseUpdateDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name:      "hook1",
+			newName:   "hook1",
+			newURL:    "http://example.com",
+			newSecret: "secret",
+			newEvents: []string{ALL_EVENTS},
+			getWebhookByNameResult: map[string]*Webhook{
+				"hook1": {
+					ID:   "HOOK-ID",
+					Name: "hook1",
+				},
+			},
+			updateWebhookErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.SpecialFuncs[GetWebhookByNameMethod] = func(name string) (*Webhook, error) {
+			if webhook, ok := test.getWebhookByNameResult[name]; ok {
+				return webhook, nil
+			}
+			return nil, test.getWebhookByNameErr[name]
+		}
+		testRepo.ArgsOut[UpdateWebhookMethod][0] = test.updateWebhookResult
+		testRepo.ArgsOut[UpdateWebhookMethod][1] = test.updateWebhookErr
+
+		webhook, err := testAPI.UpdateWebhook(test.requestInfo, test.name, test.newName, test.newURL, test.newSecret, test.newEvents)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedWebhook, webhook)
+	}
+}
+
+func TestWorkerAPI_RemoveWebhook(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		name        string
+		// Expected result
+		wantError error
+		// Manager Results
+		getWebhookByNameResult    *Webhook
+		getWebhookByNameMethodErr error
+		removeWebhookMethodErr    error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "hook1",
+			getWebhookByNameResult: &Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+			},
+		},
+		"ErrorCaseNotAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+			},
+			name: "hook1",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to manage webhooks",
+			},
+		},
+		"ErrorCaseRemoveDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			name: "hook1",
+			getWebhookByNameResult: &Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+			},
+			removeWebhookMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetWebhookByNameMethod][0] = test.getWebhookByNameResult
+		testRepo.ArgsOut[GetWebhookByNameMethod][1] = test.getWebhookByNameMethodErr
+		testRepo.ArgsOut[RemoveWebhookMethod][0] = test.removeWebhookMethodErr
+
+		err := testAPI.RemoveWebhook(test.requestInfo, test.name)
+		checkMethodResponse(t, n, test.wantError, err, nil, nil)
+		if test.wantError == nil {
+			assert.Equal(t, "HOOK-ID", testRepo.ArgsIn[RemoveWebhookMethod][0], "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerAPI_ListWebhookDeliveries(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedDeliveries []WebhookDelivery
+		totalResult        int
+		wantError          error
+		// Manager Results
+		getWebhookByNameResult    *Webhook
+		getWebhookByNameMethodErr error
+		getDeliveriesResult       []WebhookDelivery
+		getDeliveriesMethodErr    error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				WebhookName: "hook1",
+				Limit:       20,
+			},
+			getWebhookByNameResult: &Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+			},
+			getDeliveriesResult: []WebhookDelivery{
+				{
+					ID:        "DELIVERY-ID",
+					WebhookID: "HOOK-ID",
+					EventType: USER_EVENT_CREATED,
+					Status:    WEBHOOK_DELIVERY_DELIVERED,
+					Attempts:  1,
+					CreateAt:  now,
+				},
+			},
+			expectedDeliveries: []WebhookDelivery{
+				{
+					ID:        "DELIVERY-ID",
+					WebhookID: "HOOK-ID",
+					EventType: USER_EVENT_CREATED,
+					Status:    WEBHOOK_DELIVERY_DELIVERED,
+					Attempts:  1,
+					CreateAt:  now,
+				},
+			},
+			totalResult: 1,
+		},
+		"ErrorCaseNotAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+			},
+			filter: &Filter{
+				WebhookName: "hook1",
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to manage webhooks",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				WebhookName: "hook1",
+			},
+			getWebhookByNameMethodErr: &database.Error{
+				Code:    database.WEBHOOK_NOT_FOUND,
+				Message: "Webhook with name hook1 not found",
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook with name hook1 not found",
+			},
+		},
+		"ErrorCaseDBError": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				WebhookName: "hook1",
+			},
+			getWebhookByNameResult: &Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+			},
+			getDeliveriesMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetWebhookByNameMethod][0] = test.getWebhookByNameResult
+		testRepo.ArgsOut[GetWebhookByNameMethod][1] = test.getWebhookByNameMethodErr
+		testRepo.ArgsOut[GetWebhookDeliveriesMethod][0] = test.getDeliveriesResult
+		testRepo.ArgsOut[GetWebhookDeliveriesMethod][1] = test.totalResult
+		testRepo.ArgsOut[GetWebhookDeliveriesMethod][2] = test.getDeliveriesMethodErr
+
+		deliveries, total, err := testAPI.ListWebhookDeliveries(test.requestInfo, test.filter)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedDeliveries, deliveries)
+		if test.wantError == nil {
+			assert.Equal(t, test.totalResult, total, "Error in test case %v", n)
+			assert.Equal(t, "HOOK-ID", testRepo.ArgsIn[GetWebhookDeliveriesMethod][0], "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerAPI_DeliverWebhookEvents(t *testing.T) {
+	payload := `{"id":"EVENT-ID","type":"user.created"}`
+	var received *http.Request
+	var receivedBody []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	testcases := map[string]struct {
+		// Delivery to send
+		attempts     int
+		responseCode int
+		// Expected results
+		expectedSent     int
+		expectedStatus   string
+		expectedAttempts int
+		expectedError    string
+		// Expected backoff of the next attempt
+		expectedBackoff time.Duration
+		// API Errors
+		updateWebhookDeliveryErr error
+	}{
+		"OKCaseDelivered": {
+			responseCode:     http.StatusOK,
+			expectedSent:     1,
+			expectedStatus:   WEBHOOK_DELIVERY_DELIVERED,
+			expectedAttempts: 1,
+		},
+		"OKCaseRetry": {
+			attempts:         1,
+			responseCode:     http.StatusInternalServerError,
+			expectedSent:     1,
+			expectedStatus:   WEBHOOK_DELIVERY_PENDING,
+			expectedAttempts: 2,
+			expectedError:    "Unexpected response status 500 Internal Server Error",
+			expectedBackoff:  2 * time.Minute,
+		},
+		"OKCaseFailed": {
+			attempts:         2,
+			responseCode:     http.StatusNotFound,
+			expectedSent:     1,
+			expectedStatus:   WEBHOOK_DELIVERY_FAILED,
+			expectedAttempts: 3,
+			expectedError:    "Unexpected response status 404 Not Found",
+		},
+		"OKCaseClaimedAgain": {
+			responseCode:     http.StatusOK,
+			expectedStatus:   WEBHOOK_DELIVERY_DELIVERED,
+			expectedAttempts: 1,
+			updateWebhookDeliveryErr: &database.Error{
+				Code:    database.WEBHOOK_DELIVERY_NOT_CLAIMED,
+				Message: "Error",
+			},
+		},
+	}
+
+	claimedUntil := time.Now().UTC().Add(WEBHOOK_DELIVERY_LEASE)
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+		status = test.responseCode
+
+		testRepo.ArgsOut[ClaimWebhookDeliveriesMethod][0] = []WebhookDelivery{
+			{
+				ID:            "DELIVERY-ID",
+				WebhookID:     "HOOK-ID",
+				EventID:       "EVENT-ID",
+				EventType:     USER_EVENT_CREATED,
+				Payload:       payload,
+				Status:        WEBHOOK_DELIVERY_PENDING,
+				Attempts:      test.attempts,
+				NextAttemptAt: claimedUntil,
+			},
+			{
+				ID:        "DELIVERY-REMOVED-WEBHOOK",
+				WebhookID: "REMOVED-HOOK-ID",
+				Status:    WEBHOOK_DELIVERY_PENDING,
+			},
+		}
+		testRepo.ArgsOut[GetWebhooksFilteredMethod][0] = []Webhook{
+			{
+				ID:     "HOOK-ID",
+				Name:   "hook1",
+				URL:    server.URL,
+				Secret: "secret",
+				Events: []string{ALL_EVENTS},
+			},
+		}
+		testRepo.ArgsOut[UpdateWebhookDeliveryMethod][0] = test.updateWebhookDeliveryErr
+
+		before := time.Now().UTC()
+		sent, err := testAPI.DeliverWebhookEvents(http.DefaultClient, 3, time.Minute)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedSent, sent, "Error in test case %v", n)
+		assert.Equal(t, WEBHOOK_DELIVERY_LEASE, testRepo.ArgsIn[ClaimWebhookDeliveriesMethod][1], "Error in test case %v", n)
+		assert.Equal(t, MAX_WEBHOOK_DELIVERIES, testRepo.ArgsIn[ClaimWebhookDeliveriesMethod][2], "Error in test case %v", n)
+
+		// Check request
+		assert.Equal(t, payload, string(receivedBody), "Error in test case %v", n)
+		assert.Equal(t, USER_EVENT_CREATED, received.Header.Get(WEBHOOK_EVENT_HEADER), "Error in test case %v", n)
+		assert.Equal(t, "DELIVERY-ID", received.Header.Get(WEBHOOK_DELIVERY_HEADER), "Error in test case %v", n)
+		timestamp, err := strconv.ParseInt(received.Header.Get(WEBHOOK_TIMESTAMP_HEADER), 10, 64)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.False(t, time.Unix(timestamp, 0).Before(before.Truncate(time.Second)), "Error in test case %v", n)
+		assert.Equal(t, SignWebhookPayload("secret", received.Header.Get(WEBHOOK_TIMESTAMP_HEADER), []byte(payload)),
+			received.Header.Get(WEBHOOK_SIGNATURE_HEADER), "Error in test case %v", n)
+
+		// Check stored result
+		delivery := testRepo.ArgsIn[UpdateWebhookDeliveryMethod][0].(WebhookDelivery)
+		assert.Equal(t, "DELIVERY-ID", delivery.ID, "Error in test case %v", n)
+		assert.Equal(t, claimedUntil, testRepo.ArgsIn[UpdateWebhookDeliveryMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.expectedStatus, delivery.Status, "Error in test case %v", n)
+		assert.Equal(t, test.expectedAttempts, delivery.Attempts, "Error in test case %v", n)
+		assert.Equal(t, test.responseCode, delivery.ResponseCode, "Error in test case %v", n)
+		assert.Equal(t, test.expectedError, delivery.Error, "Error in test case %v", n)
+		if test.expectedBackoff > 0 {
+			assert.False(t, delivery.NextAttemptAt.Before(before.Add(test.expectedBackoff)), "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerAPI_DeliverWebhookEventsDBError(t *testing.T) {
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+	testRepo.ArgsOut[ClaimWebhookDeliveriesMethod][1] = &database.Error{
+		Code:    database.INTERNAL_ERROR,
+		Message: "Error",
+	}
+
+	sent, err := testAPI.DeliverWebhookEvents(http.DefaultClient, 3, time.Minute)
+	assert.Equal(t, 0, sent)
+	assert.Equal(t, &Error{Code: UNKNOWN_API_ERROR, Message: "Error"}, err)
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// HMAC-SHA256 of "1475280000.{}" with key "secret"
+	expected := "sha256=9e4c81d2dc3e64d93e64eb6030a667a4854b414f0d8aa35362196e170239d4ed"
+
+	assert.Equal(t, expected, SignWebhookPayload("secret", "1475280000", []byte("{}")))
+	assert.NotEqual(t, expected, SignWebhookPayload("secret", "1475280001", []byte("{}")))
+}
+
+func TestWorkerAPI_PurgeWebhookDeliveries(t *testing.T) {
+	updatedBefore := time.Date(2016, time.October, 1, 0, 0, 0, 0, time.UTC)
+	testcases := map[string]struct {
+		// Expected result
+		expectedPurged int
+		wantError      error
+		// Manager Results
+		purgeResult int
+		// API Errors
+		purgeErr error
+	}{
+		"OKCase": {
+			expectedPurged: 2,
+			purgeResult:    2,
+		},
+		"ErrorCaseDBErr": {
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			purgeErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[PurgeWebhookDeliveriesMethod][0] = test.purgeResult
+		testRepo.ArgsOut[PurgeWebhookDeliveriesMethod][1] = test.purgeErr
+
+		purged, err := testAPI.PurgeWebhookDeliveries(updatedBefore)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedPurged, purged)
+		assert.Equal(t, updatedBefore, testRepo.ArgsIn[PurgeWebhookDeliveriesMethod][0], "Error in test case %v", n)
+	}
+}
+
+func TestWorkerAPI_PublishEvents(t *testing.T) {
+	testcases := map[string]struct {
+		// Result of the transaction
+		transactionErr error
+		// Expected deliveries
+		expectedEventTypes []string
+	}{
+		"OKCaseCommitted": {
+			expectedEventTypes: []string{USER_EVENT_CREATED, GROUP_EVENT_MEMBER_ADDED},
+		},
+		"OKCaseRolledBack": {
+			transactionErr: errors.New("Error"),
+		},
+	}
+
+	for n, test := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+		requestInfo := RequestInfo{
+			Identifier: "123456",
+			RequestID:  "REQUEST-ID",
+		}
+		testRepo.ArgsOut[GetWebhooksFilteredMethod][0] = []Webhook{
+			{
+				ID:     "HOOK-ID-1",
+				Events: []string{ALL_EVENTS},
+			},
+			{
+				ID:     "HOOK-ID-2",
+				Events: []string{GROUP_EVENT_MEMBER_ADDED},
+			},
+			{
+				ID:     "HOOK-ID-3",
+				Events: []string{POLICY_EVENT_CREATED},
+			},
+		}
+
+		testAPI.inTransaction(func(txAPI WorkerAPI) error {
+			txAPI.emitEvent(requestInfo, USER_EVENT_CREATED, &User{ExternalID: "user1"}, nil)
+			// Nested transactions publish their events with the outermost one
+			txAPI.inTransaction(func(nestedAPI WorkerAPI) error {
+				nestedAPI.emitEvent(requestInfo, GROUP_EVENT_MEMBER_ADDED, &Group{Name: "group1"}, &User{ExternalID: "user1"})
+				return nil
+			})
+			assert.Nil(t, testRepo.ArgsIn[AddWebhookDeliveriesMethod][0], "Error in test case %v", n)
+			return test.transactionErr
+		})
+
+		if test.expectedEventTypes == nil {
+			assert.Nil(t, testRepo.ArgsIn[AddWebhookDeliveriesMethod][0], "Error in test case %v", n)
+			continue
+		}
+
+		deliveries := testRepo.ArgsIn[AddWebhookDeliveriesMethod][0].([]WebhookDelivery)
+		webhooksByEvent := map[string][]string{}
+		for _, d := range deliveries {
+			assert.Equal(t, WEBHOOK_DELIVERY_PENDING, d.Status, "Error in test case %v", n)
+			event := Event{}
+			assert.Nil(t, json.Unmarshal([]byte(d.Payload), &event), "Error in test case %v", n)
+			assert.Equal(t, d.EventType, event.Type, "Error in test case %v", n)
+			assert.Equal(t, "REQUEST-ID", event.RequestID, "Error in test case %v", n)
+			webhooksByEvent[d.EventType] = append(webhooksByEvent[d.EventType], d.WebhookID)
+		}
+		assert.Equal(t, map[string][]string{
+			USER_EVENT_CREATED:       {"HOOK-ID-1"},
+			GROUP_EVENT_MEMBER_ADDED: {"HOOK-ID-1", "HOOK-ID-2"},
+		}, webhooksByEvent, "Error in test case %v", n)
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+// AUTH OIDC PROVIDER API IMPLEMENTATION
+
+func (c *Client) AddOidcProvider(requestInfo api.RequestInfo, name string, path string, issuerURL string, oidcClients []string) (*api.OidcProvider, error) {
+	request := &types.CreateOidcProviderRequest{
+		Name:        name,
+		Path:        path,
+		IssuerURL:   issuerURL,
+		OidcClients: oidcClients,
+	}
+	oidcProvider := &api.OidcProvider{}
+	if err := c.do(requestInfo, http.MethodPost, types.OIDC_AUTH_ROOT_URL, nil, request, oidcProvider); err != nil {
+		return nil, err
+	}
+	return oidcProvider, nil
+}
+
+func (c *Client) GetOidcProviderByName(requestInfo api.RequestInfo, name string) (*api.OidcProvider, error) {
+	oidcProvider := &api.OidcProvider{}
+	if err := c.do(requestInfo, http.MethodGet, oidcProviderRoute(name), nil, nil, oidcProvider); err != nil {
+		return nil, err
+	}
+	return oidcProvider, nil
+}
+
+func (c *Client) ListOidcProviders(requestInfo api.RequestInfo, filter *api.Filter) ([]string, int, error) {
+	response := &types.ListOidcProvidersResponse{}
+	if err := c.do(requestInfo, http.MethodGet, types.OIDC_AUTH_ROOT_URL, filterQuery(getFilter(filter)), nil, response); err != nil {
+		return nil, 0, err
+	}
+	return response.Providers, response.Total, nil
+}
+
+func (c *Client) UpdateOidcProvider(requestInfo api.RequestInfo, oidcProviderName string, newName string, newPath string, newIssuerUrl string,
+	newClients []string) (*api.OidcProvider, error) {
+	request := &types.UpdateOidcProviderRequest{
+		Name:        newName,
+		Path:        newPath,
+		IssuerURL:   newIssuerUrl,
+		OidcClients: newClients,
+	}
+	oidcProvider := &api.OidcProvider{}
+	if err := c.do(requestInfo, http.MethodPut, oidcProviderRoute(oidcProviderName), nil, request, oidcProvider); err != nil {
+		return nil, err
+	}
+	return oidcProvider, nil
+}
+
+func (c *Client) RemoveOidcProvider(requestInfo api.RequestInfo, name string) error {
+	return c.do(requestInfo, http.MethodDelete, oidcProviderRoute(name), nil, nil, nil)
+}
+
+// PRIVATE HELPER METHODS
+
+func oidcProviderRoute(name string) string {
+	return route(types.OIDC_AUTH_ID_URL, types.AUTH_PROVIDER_NAME, name)
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+func TestClient_AuthOidcAPI(t *testing.T) {
+	now := time.Date(2016, time.July, 1, 10, 0, 0, 0, time.UTC)
+	oidcProvider := &api.OidcProvider{
+		ID:          "OidcProviderID",
+		Name:        "google",
+		Path:        "/path/",
+		Urn:         api.CreateUrn("", api.RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "google"),
+		CreateAt:    now,
+		UpdateAt:    now,
+		IssuerURL:   "https://accounts.google.com",
+		OidcClients: []api.OidcClient{{Name: "client1"}},
+	}
+
+	runClientTestCases(t, map[string]clientTestCase{
+		"OkCaseAddOidcProvider": {
+			call: func(c *Client) (interface{}, error) {
+				return c.AddOidcProvider(api.RequestInfo{}, "google", "/path/", "https://accounts.google.com", []string{"client1"})
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/admin/auth/oidc/providers",
+				body:     `{"name": "google", "path": "/path/", "issuerUrl": "https://accounts.google.com", "clients": ["client1"]}`,
+				status:   http.StatusCreated,
+				response: oidcProvider,
+			},
+			expectedResult: oidcProvider,
+		},
+		"OkCaseGetOidcProviderByName": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetOidcProviderByName(api.RequestInfo{}, "google")
+			},
+			exchange: testExchange{
+				method:   http.MethodGet,
+				url:      "/api/v1/admin/auth/oidc/providers/google",
+				status:   http.StatusOK,
+				response: oidcProvider,
+			},
+			expectedResult: oidcProvider,
+		},
+		"OkCaseListOidcProviders": {
+			call: func(c *Client) (interface{}, error) {
+				oidcProviders, total, err := c.ListOidcProviders(api.RequestInfo{}, nil)
+				return listResult{oidcProviders, total, ""}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/admin/auth/oidc/providers",
+				status: http.StatusOK,
+				response: &types.ListOidcProvidersResponse{
+					Providers: []string{"google"},
+					Total:     1,
+				},
+			},
+			expectedResult: listResult{[]string{"google"}, 1, ""},
+		},
+		"OkCaseUpdateOidcProvider": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateOidcProvider(api.RequestInfo{}, "google0", "google", "/path/", "https://accounts.google.com", []string{"client1"})
+			},
+			exchange: testExchange{
+				method:   http.MethodPut,
+				url:      "/api/v1/admin/auth/oidc/providers/google0",
+				body:     `{"name": "google", "path": "/path/", "issuerUrl": "https://accounts.google.com", "clients": ["client1"]}`,
+				status:   http.StatusOK,
+				response: oidcProvider,
+			},
+			expectedResult: oidcProvider,
+		},
+		"OkCaseRemoveOidcProvider": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.RemoveOidcProvider(api.RequestInfo{}, "google")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/admin/auth/oidc/providers/google",
+				status: http.StatusNoContent,
+			},
+		},
+	})
+}
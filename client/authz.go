@@ -0,0 +1,25 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+// AUTHZ API IMPLEMENTATION
+
+// GetAuthorizedExternalResources returns the resources the authenticated user is allowed to do action over.
+// It's the only method of api.AuthzAPI exposed by the worker, the other ones are used internally to filter
+// entities and Kubernetes access reviews are answered from the Kubernetes resource attributes.
+func (c *Client) GetAuthorizedExternalResources(requestInfo api.RequestInfo, action string, resources []string) ([]string, error) {
+	request := &types.AuthorizeResourcesRequest{
+		Action:    action,
+		Resources: resources,
+	}
+	response := &types.AuthorizeResourcesResponse{}
+	if err := c.do(requestInfo, http.MethodPost, types.RESOURCE_URL, nil, request, response); err != nil {
+		return nil, err
+	}
+	return response.ResourcesAllowed, nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+func TestClient_GetAuthorizedExternalResources(t *testing.T) {
+	runClientTestCases(t, map[string]clientTestCase{
+		"OkCaseResourcesAllowed": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetAuthorizedExternalResources(api.RequestInfo{}, "example:get", []string{
+					"urn:ews:example:instance1:resource/one",
+					"urn:ews:example:instance1:resource/two",
+				})
+			},
+			exchange: testExchange{
+				method: http.MethodPost,
+				url:    "/api/v1/resource",
+				body: `{"action": "example:get", "resources": [
+					"urn:ews:example:instance1:resource/one", "urn:ews:example:instance1:resource/two"
+				]}`,
+				status: http.StatusOK,
+				response: &types.AuthorizeResourcesResponse{
+					ResourcesAllowed: []string{"urn:ews:example:instance1:resource/one"},
+				},
+			},
+			expectedResult: []string{"urn:ews:example:instance1:resource/one"},
+		},
+		"ErrorCaseUnauthorizedError": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetAuthorizedExternalResources(api.RequestInfo{}, "example:get", []string{
+					"urn:ews:example:instance1:resource/one",
+				})
+			},
+			exchange: testExchange{
+				method: http.MethodPost,
+				url:    "/api/v1/resource",
+				body:   `{"action": "example:get", "resources": ["urn:ews:example:instance1:resource/one"]}`,
+				status: http.StatusForbidden,
+				response: &api.Error{
+					Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+					Message: "No resources allowed",
+				},
+			},
+			expectedResult: ([]string)(nil),
+			wantError: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "No resources allowed",
+			},
+		},
+	})
+}
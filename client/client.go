@@ -0,0 +1,342 @@
+// Package client implements a Go client for the Foulkon worker API. Its methods mirror the API interfaces,
+// so the client can be used wherever an api.UserAPI, api.GroupAPI, api.PolicyAPI, api.ProxyResourcesAPI
+// or api.AuthOidcAPI is expected.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+const (
+	// Default backoff before the first retry, doubled on each retry
+	DEFAULT_RETRY_BACKOFF = 100 * time.Millisecond
+)
+
+// Check that the client implements the API interfaces exposed by the worker
+var (
+	_ api.UserAPI           = &Client{}
+	_ api.GroupAPI          = &Client{}
+	_ api.PolicyAPI         = &Client{}
+	_ api.ProxyResourcesAPI = &Client{}
+	_ api.AuthOidcAPI       = &Client{}
+)
+
+// Authenticator adds the credentials of the caller to the requests sent to the worker
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate calls f(req)
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BasicAuth authenticates requests with a user and password, like the worker admin user
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate sets the basic authentication header
+func (a *BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// BearerToken authenticates requests with a token of the authentication connector, like an OIDC ID token
+type BearerToken struct {
+	Token string
+}
+
+// Authenticate sets the bearer authorization header
+func (a *BearerToken) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// Config of the client
+type Config struct {
+	// Worker URL, like http://localhost:8080
+	URL string
+	// HTTP client used to send requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+	// Credentials added to requests, none if nil
+	Authenticator Authenticator
+	// Times an idempotent request is retried after a connection error or an unavailable worker
+	MaxRetries int
+	// Wait before the first retry, doubled on each retry. DEFAULT_RETRY_BACKOFF if 0
+	RetryBackoff time.Duration
+}
+
+// Client of the worker API. Identity of the caller comes from its Authenticator, so the
+// Identifier, Admin and RequestID fields of api.RequestInfo are ignored. Revision is sent
+// as an If-Match header to update or remove an entity only if it wasn't modified.
+type Client struct {
+	url           string
+	httpClient    *http.Client
+	authenticator Authenticator
+	maxRetries    int
+	retryBackoff  time.Duration
+}
+
+// NewClient returns a client of the worker with the URL given in config
+func NewClient(config *Config) (*Client, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("Invalid worker URL %v", config.URL)
+	}
+	if config.MaxRetries < 0 {
+		return nil, fmt.Errorf("Invalid max retries %v", config.MaxRetries)
+	}
+
+	c := &Client{
+		url:           strings.TrimSuffix(config.URL, "/"),
+		httpClient:    config.HTTPClient,
+		authenticator: config.Authenticator,
+		maxRetries:    config.MaxRetries,
+		retryBackoff:  config.RetryBackoff,
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.retryBackoff == 0 {
+		c.retryBackoff = DEFAULT_RETRY_BACKOFF
+	}
+	return c, nil
+}
+
+// PRIVATE HELPER METHODS
+
+// do sends a request to the worker, decoding the JSON response into result if it isn't nil.
+// Error responses are returned as *api.Error.
+func (c *Client) do(requestInfo api.RequestInfo, method string, path string, query url.Values, body interface{}, result interface{}) error {
+	res, err := c.sendJSON(requestInfo, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return responseError(res)
+	}
+	if result == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// doGroupBulk sends a bulk request over the items of a group. Failed requests answer with the result of every
+// item, so it's returned along with the error of the first failed item, like the API does.
+func (c *Client) doGroupBulk(requestInfo api.RequestInfo, path string, body interface{}) (*api.GroupBulkResult, error) {
+	res, err := c.sendJSON(requestInfo, http.MethodPost, path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.GroupBulkResult{}
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		if err := json.Unmarshal(b, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if err := json.Unmarshal(b, result); err == nil {
+		for _, item := range result.Items {
+			if item.Error != nil {
+				return result, item.Error
+			}
+		}
+	}
+	// Errors of the request as a whole, like an unknown group, have no item results
+	res.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return nil, responseError(res)
+}
+
+// sendJSON sends a request to the worker with the JSON encoded body, if it isn't nil
+func (c *Client) sendJSON(requestInfo api.RequestInfo, method string, path string, query url.Values, body interface{}) (*http.Response, error) {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	u := c.url + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return c.send(requestInfo, method, u, b)
+}
+
+// send sends a request, retrying idempotent ones after a connection error or an unavailable worker
+func (c *Client) send(requestInfo api.RequestInfo, method string, u string, body []byte) (*http.Response, error) {
+	backoff := c.retryBackoff
+	for retry := 0; ; retry++ {
+		req, err := c.newRequest(requestInfo, method, u, body)
+		if err != nil {
+			return nil, err
+		}
+		res, err := c.httpClient.Do(req)
+		if retry >= c.maxRetries || !isIdempotent(method) || !isRetryable(res, err) {
+			return res, err
+		}
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (c *Client) newRequest(requestInfo api.RequestInfo, method string, u string, body []byte) (*http.Request, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if requestInfo.Revision > 0 {
+		req.Header.Set(types.IF_MATCH_HEADER, fmt.Sprintf("\"%v\"", requestInfo.Revision))
+	}
+	if c.authenticator != nil {
+		if err := c.authenticator.Authenticate(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// isIdempotent returns true for methods that can be sent again without changing the result
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryable returns true if the worker couldn't be reached or it was temporarily unavailable
+func isRetryable(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// responseError returns the API error of an error response. Responses without an API error,
+// like authentication failures or unexpected errors, are mapped to an error code by status code.
+func responseError(res *http.Response) error {
+	apiError := &api.Error{}
+	if err := json.NewDecoder(res.Body).Decode(apiError); err == nil && apiError.Code != "" {
+		return apiError
+	}
+
+	code := api.UNKNOWN_API_ERROR
+	switch res.StatusCode {
+	case http.StatusBadRequest:
+		code = api.INVALID_PARAMETER_ERROR
+	case http.StatusUnauthorized:
+		code = api.AUTHENTICATION_API_ERROR
+	case http.StatusForbidden:
+		code = api.UNAUTHORIZED_RESOURCES_ERROR
+	case http.StatusPreconditionFailed:
+		code = api.REVISION_MISMATCH
+	}
+	return &api.Error{
+		Code:    code,
+		Message: fmt.Sprintf("%v %v", res.StatusCode, http.StatusText(res.StatusCode)),
+	}
+}
+
+// route replaces path params in pattern with their values. Params are pairs of name and value.
+func route(pattern string, params ...string) string {
+	for i := 0; i+1 < len(params); i += 2 {
+		pattern = strings.Replace(pattern, ":"+params[i], escapePathParam(params[i+1]), 1)
+	}
+	return pattern
+}
+
+func escapePathParam(value string) string {
+	return strings.Replace(url.QueryEscape(value), "+", "%20", -1)
+}
+
+// filterQuery returns the query params of the search, date range and pagination fields of filter.
+// Fields sent as path params are left out.
+func filterQuery(filter *api.Filter) url.Values {
+	query := url.Values{}
+	setParam := func(name string, value string) {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	setDateParam := func(name string, value time.Time) {
+		if !value.IsZero() {
+			query.Set(name, value.UTC().Format(time.RFC3339))
+		}
+	}
+	setParam("PathPrefix", filter.PathPrefix)
+	setParam("NamePrefix", filter.NamePrefix)
+	setParam("NameContains", filter.NameContains)
+	setDateParam("CreatedAfter", filter.CreatedAfter)
+	setDateParam("CreatedBefore", filter.CreatedBefore)
+	setDateParam("UpdatedAfter", filter.UpdatedAfter)
+	setDateParam("UpdatedBefore", filter.UpdatedBefore)
+	setParam("Member", filter.Member)
+	setParam("AttachedPolicy", filter.AttachedPolicy)
+	setParam("Action", filter.Action)
+	setParam("Resource", filter.Resource)
+	setParam("NextToken", filter.NextToken)
+	setParam("OrderBy", filter.OrderBy)
+	if filter.Deleted {
+		query.Set("Deleted", "true")
+	}
+	if filter.Transitive {
+		query.Set("Transitive", "true")
+	}
+	if filter.Offset > 0 {
+		query.Set("Offset", strconv.Itoa(filter.Offset))
+	}
+	if filter.Limit > 0 {
+		query.Set("Limit", strconv.Itoa(filter.Limit))
+	}
+	return query
+}
+
+// getFilter returns the filter given, or an empty one if it's nil
+func getFilter(filter *api.Filter) *api.Filter {
+	if filter == nil {
+		return &api.Filter{}
+	}
+	return filter
+}
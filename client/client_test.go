@@ -0,0 +1,437 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testExchange is a request expected by the test server and its response
+type testExchange struct {
+	method string
+	// Path and query of the request
+	url string
+	// Expected JSON body, empty if no body is expected
+	body string
+	// Response
+	status   int
+	response interface{}
+}
+
+// newTestClient returns a client of a server that expects the exchanges given in order
+func newTestClient(t *testing.T, n string, exchanges ...testExchange) (*Client, *httptest.Server) {
+	i := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.True(t, i < len(exchanges), "Unexpected request %v %v in test case %v", r.Method, r.URL, n) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		exchange := exchanges[i]
+		i++
+		assert.Equal(t, exchange.method, r.Method, "Error in test case %v", n)
+		assert.Equal(t, exchange.url, r.URL.RequestURI(), "Error in test case %v", n)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err, "Error in test case %v", n)
+		if exchange.body != "" {
+			assert.JSONEq(t, exchange.body, string(body), "Error in test case %v", n)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"), "Error in test case %v", n)
+		} else {
+			assert.Empty(t, body, "Error in test case %v", n)
+		}
+
+		if exchange.response != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(exchange.status)
+			json.NewEncoder(w).Encode(exchange.response)
+			return
+		}
+		w.WriteHeader(exchange.status)
+	}))
+
+	c, err := NewClient(&Config{URL: server.URL})
+	assert.Nil(t, err, "Error in test case %v", n)
+	return c, server
+}
+
+// clientTestCase is a call to a client method, the exchange it sends and its expected result
+type clientTestCase struct {
+	call     func(c *Client) (interface{}, error)
+	exchange testExchange
+	// Expected result
+	expectedResult interface{}
+	wantError      error
+}
+
+// listResult holds the results of a list method
+type listResult struct {
+	items     interface{}
+	total     int
+	nextToken string
+}
+
+func runClientTestCases(t *testing.T, testcases map[string]clientTestCase) {
+	for n, test := range testcases {
+		c, server := newTestClient(t, n, test.exchange)
+		result, err := test.call(c)
+		assert.Equal(t, test.wantError, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResult, result, "Error in test case %v", n)
+		server.Close()
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	testcases := map[string]struct {
+		config *Config
+		// Expected result
+		expectedClient *Client
+		wantError      error
+	}{
+		"OkCaseDefaults": {
+			config: &Config{
+				URL: "http://localhost:8080/",
+			},
+			expectedClient: &Client{
+				url:          "http://localhost:8080",
+				httpClient:   http.DefaultClient,
+				retryBackoff: DEFAULT_RETRY_BACKOFF,
+			},
+		},
+		"OkCaseAllValues": {
+			config: &Config{
+				URL:           "https://foulkon.example.com",
+				HTTPClient:    &http.Client{Timeout: time.Second},
+				Authenticator: &BearerToken{Token: "token"},
+				MaxRetries:    3,
+				RetryBackoff:  time.Second,
+			},
+			expectedClient: &Client{
+				url:           "https://foulkon.example.com",
+				httpClient:    &http.Client{Timeout: time.Second},
+				authenticator: &BearerToken{Token: "token"},
+				maxRetries:    3,
+				retryBackoff:  time.Second,
+			},
+		},
+		"ErrorCaseInvalidURL": {
+			config: &Config{
+				URL: "localhost:8080",
+			},
+			wantError: errors.New("Invalid worker URL localhost:8080"),
+		},
+		"ErrorCaseInvalidMaxRetries": {
+			config: &Config{
+				URL:        "http://localhost:8080",
+				MaxRetries: -1,
+			},
+			wantError: errors.New("Invalid max retries -1"),
+		},
+	}
+
+	for n, test := range testcases {
+		c, err := NewClient(test.config)
+		assert.Equal(t, test.wantError, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedClient, c, "Error in test case %v", n)
+	}
+}
+
+func TestClient_Authenticator(t *testing.T) {
+	testcases := map[string]struct {
+		authenticator Authenticator
+		// Expected result
+		expectedAuthorization string
+		wantError             error
+	}{
+		"OkCaseNoAuthenticator": {},
+		"OkCaseBasicAuth": {
+			authenticator:         &BasicAuth{Username: "admin", Password: "admin"},
+			expectedAuthorization: "Basic YWRtaW46YWRtaW4=",
+		},
+		"OkCaseBearerToken": {
+			authenticator:         &BearerToken{Token: "token"},
+			expectedAuthorization: "Bearer token",
+		},
+		"OkCaseAuthenticatorFunc": {
+			authenticator: AuthenticatorFunc(func(req *http.Request) error {
+				req.Header.Set("Authorization", "Custom credentials")
+				return nil
+			}),
+			expectedAuthorization: "Custom credentials",
+		},
+		"ErrorCaseAuthenticatorFunc": {
+			authenticator: AuthenticatorFunc(func(req *http.Request) error {
+				return errors.New("No credentials")
+			}),
+			wantError: errors.New("No credentials"),
+		},
+	}
+
+	for n, test := range testcases {
+		var authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		c, err := NewClient(&Config{URL: server.URL, Authenticator: test.authenticator})
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		err = c.RemoveUser(api.RequestInfo{}, "user1")
+		assert.Equal(t, test.wantError, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedAuthorization, authorization, "Error in test case %v", n)
+		server.Close()
+	}
+}
+
+func TestClient_ResponseError(t *testing.T) {
+	testcases := map[string]struct {
+		status   int
+		response interface{}
+		// Expected result
+		wantError error
+	}{
+		"ErrorCaseAPIError": {
+			status: http.StatusNotFound,
+			response: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User with externalId user1 not found",
+			},
+			wantError: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User with externalId user1 not found",
+			},
+		},
+		"ErrorCaseAPIErrorDetails": {
+			status: http.StatusBadRequest,
+			response: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: request body doesn't match its schema, 1 errors found",
+				Details: []api.ErrorDetail{
+					{Pointer: "/path", Message: "Property is required"},
+				},
+			},
+			wantError: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: request body doesn't match its schema, 1 errors found",
+				Details: []api.ErrorDetail{
+					{Pointer: "/path", Message: "Property is required"},
+				},
+			},
+		},
+		"ErrorCaseAuthenticationError": {
+			status: http.StatusUnauthorized,
+			wantError: &api.Error{
+				Code:    api.AUTHENTICATION_API_ERROR,
+				Message: "401 Unauthorized",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			status: http.StatusForbidden,
+			wantError: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "403 Forbidden",
+			},
+		},
+		"ErrorCaseRevisionMismatch": {
+			status: http.StatusPreconditionFailed,
+			wantError: &api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "412 Precondition Failed",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			status: http.StatusInternalServerError,
+			wantError: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "500 Internal Server Error",
+			},
+		},
+		"ErrorCaseUnexpectedBody": {
+			status:   http.StatusNotFound,
+			response: "404 page not found",
+			wantError: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "404 Not Found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		c, server := newTestClient(t, n, testExchange{
+			method:   http.MethodGet,
+			url:      types.USER_ROOT_URL + "/user1",
+			status:   test.status,
+			response: test.response,
+		})
+		user, err := c.GetUserByExternalID(api.RequestInfo{}, "user1")
+		assert.Nil(t, user, "Error in test case %v", n)
+		assert.Equal(t, test.wantError, err, "Error in test case %v", n)
+		server.Close()
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	testcases := map[string]struct {
+		method     string
+		maxRetries int
+		statuses   []int
+		// Expected result
+		expectedRequests int
+		wantError        error
+	}{
+		"OkCaseRetriedUntilSuccess": {
+			method:           http.MethodPut,
+			maxRetries:       2,
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectedRequests: 3,
+		},
+		"OkCaseNoRetryNeeded": {
+			method:           http.MethodGet,
+			maxRetries:       2,
+			statuses:         []int{http.StatusOK},
+			expectedRequests: 1,
+		},
+		"ErrorCaseMaxRetriesReached": {
+			method:           http.MethodGet,
+			maxRetries:       1,
+			statuses:         []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout},
+			expectedRequests: 2,
+			wantError: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "504 Gateway Timeout",
+			},
+		},
+		"ErrorCaseNotRetryableStatus": {
+			method:           http.MethodGet,
+			maxRetries:       2,
+			statuses:         []int{http.StatusInternalServerError},
+			expectedRequests: 1,
+			wantError: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "500 Internal Server Error",
+			},
+		},
+		"ErrorCaseNotIdempotentMethod": {
+			method:           http.MethodPost,
+			maxRetries:       2,
+			statuses:         []int{http.StatusServiceUnavailable},
+			expectedRequests: 1,
+			wantError: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "503 Service Unavailable",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Every retry sends the same body
+			body, err := ioutil.ReadAll(r.Body)
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.JSONEq(t, `{"path": "/path/"}`, string(body), "Error in test case %v", n)
+			w.WriteHeader(test.statuses[requests])
+			requests++
+		}))
+		c, err := NewClient(&Config{URL: server.URL, MaxRetries: test.maxRetries, RetryBackoff: time.Millisecond})
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		err = c.do(api.RequestInfo{}, test.method, types.USER_ROOT_URL+"/user1", nil,
+			&types.UpdateUserRequest{Path: "/path/"}, nil)
+		assert.Equal(t, test.wantError, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedRequests, requests, "Error in test case %v", n)
+		server.Close()
+	}
+}
+
+func TestClient_RetriesConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	requests := 0
+	transport := &http.Transport{}
+	c, err := NewClient(&Config{
+		URL: server.URL,
+		HTTPClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return transport.RoundTrip(req)
+		})},
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+	assert.Nil(t, err, "Error creating client")
+
+	_, err = c.GetUserByExternalID(api.RequestInfo{}, "user1")
+	assert.NotNil(t, err, "Error expected")
+	assert.Equal(t, 3, requests, "Error in requests sent")
+}
+
+func TestClient_Revision(t *testing.T) {
+	testcases := map[string]struct {
+		revision int
+		// Expected result
+		expectedIfMatch string
+	}{
+		"OkCaseAnyRevision": {},
+		"OkCaseRevision": {
+			revision:        3,
+			expectedIfMatch: `"3"`,
+		},
+	}
+
+	for n, test := range testcases {
+		var ifMatch string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ifMatch = r.Header.Get(types.IF_MATCH_HEADER)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		c, err := NewClient(&Config{URL: server.URL})
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		err = c.RemoveGroup(api.RequestInfo{Revision: test.revision}, "org1", "group1")
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedIfMatch, ifMatch, "Error in test case %v", n)
+		server.Close()
+	}
+}
+
+func TestFilterQuery(t *testing.T) {
+	date := time.Date(2016, time.July, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	query := filterQuery(&api.Filter{
+		PathPrefix:     "/path/",
+		Org:            "org1",
+		ExternalID:     "user1",
+		NamePrefix:     "na",
+		NameContains:   "am",
+		CreatedAfter:   date,
+		UpdatedBefore:  date,
+		Member:         "user2",
+		AttachedPolicy: "policy1",
+		Action:         "iam:*",
+		Resource:       "urn:*",
+		Deleted:        true,
+		Transitive:     true,
+		Offset:         10,
+		Limit:          5,
+		NextToken:      "token",
+		OrderBy:        "name desc",
+	})
+	assert.Equal(t, "Action=iam%3A%2A&AttachedPolicy=policy1&CreatedAfter=2016-07-01T08%3A00%3A00Z&Deleted=true&Limit=5&"+
+		"Member=user2&NameContains=am&NamePrefix=na&NextToken=token&Offset=10&OrderBy=name+desc&PathPrefix=%2Fpath%2F&"+
+		"Resource=urn%3A%2A&Transitive=true&UpdatedBefore=2016-07-01T08%3A00%3A00Z", query.Encode())
+
+	assert.Empty(t, filterQuery(&api.Filter{}), "Error in empty filter")
+}
+
+// roundTripperFunc adapts a function to an http.RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+const (
+	// Groups of all organizations, filtered by Org query param
+	GROUP_ALL_URL = types.API_VERSION_1 + "/groups"
+)
+
+// GROUP API IMPLEMENTATION
+
+func (c *Client) AddGroup(requestInfo api.RequestInfo, org string, name string, path string) (*api.Group, error) {
+	request := &types.CreateGroupRequest{
+		Name: name,
+		Path: path,
+	}
+	group := &api.Group{}
+	if err := c.do(requestInfo, http.MethodPost, route(types.GROUP_ORG_ROOT_URL, types.ORG_NAME, org), nil, request, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (c *Client) GetGroupByName(requestInfo api.RequestInfo, org string, name string) (*api.Group, error) {
+	group := &api.Group{}
+	if err := c.do(requestInfo, http.MethodGet, groupRoute(types.GROUP_ID_URL, org, name), nil, nil, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (c *Client) ListGroups(requestInfo api.RequestInfo, filter *api.Filter) ([]api.GroupIdentity, int, string, error) {
+	filter = getFilter(filter)
+	query := filterQuery(filter)
+	if filter.Org != "" {
+		query.Set("Org", filter.Org)
+	}
+	response := &types.ListAllGroupsResponse{}
+	if err := c.do(requestInfo, http.MethodGet, GROUP_ALL_URL, query, nil, response); err != nil {
+		return nil, 0, "", err
+	}
+	return response.Groups, response.Total, response.NextToken, nil
+}
+
+func (c *Client) UpdateGroup(requestInfo api.RequestInfo, org string, groupName string, newName string, newPath string) (*api.Group, error) {
+	request := &types.UpdateGroupRequest{
+		Name: newName,
+		Path: newPath,
+	}
+	group := &api.Group{}
+	if err := c.do(requestInfo, http.MethodPut, groupRoute(types.GROUP_ID_URL, org, groupName), nil, request, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (c *Client) RemoveGroup(requestInfo api.RequestInfo, org string, name string) error {
+	return c.do(requestInfo, http.MethodDelete, groupRoute(types.GROUP_ID_URL, org, name), nil, nil, nil)
+}
+
+func (c *Client) RestoreGroup(requestInfo api.RequestInfo, org string, name string) (*api.Group, error) {
+	group := &api.Group{}
+	if err := c.do(requestInfo, http.MethodPost, groupRoute(types.GROUP_ID_RESTORE_URL, org, name), nil, nil, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (c *Client) AddMember(requestInfo api.RequestInfo, externalId string, groupName string, org string) error {
+	return c.do(requestInfo, http.MethodPost, groupRoute(types.GROUP_ID_USERS_ID_URL, org, groupName,
+		types.USER_ID, externalId), nil, nil, nil)
+}
+
+func (c *Client) RemoveMember(requestInfo api.RequestInfo, externalId string, groupName string, org string) error {
+	return c.do(requestInfo, http.MethodDelete, groupRoute(types.GROUP_ID_USERS_ID_URL, org, groupName,
+		types.USER_ID, externalId), nil, nil, nil)
+}
+
+func (c *Client) ListMembers(requestInfo api.RequestInfo, filter *api.Filter) ([]api.GroupMembers, int, string, error) {
+	filter = getFilter(filter)
+	response := &types.ListMembersResponse{}
+	if err := c.do(requestInfo, http.MethodGet, groupRoute(types.GROUP_ID_USERS_URL, filter.Org, filter.GroupName),
+		filterQuery(filter), nil, response); err != nil {
+		return nil, 0, "", err
+	}
+	return response.Members, response.Total, response.NextToken, nil
+}
+
+func (c *Client) AddSubgroup(requestInfo api.RequestInfo, org string, groupName string, subgroupName string) error {
+	return c.do(requestInfo, http.MethodPost, groupRoute(types.GROUP_ID_GROUPS_ID_URL, org, groupName,
+		types.SUBGROUP_NAME, subgroupName), nil, nil, nil)
+}
+
+func (c *Client) RemoveSubgroup(requestInfo api.RequestInfo, org string, groupName string, subgroupName string) error {
+	return c.do(requestInfo, http.MethodDelete, groupRoute(types.GROUP_ID_GROUPS_ID_URL, org, groupName,
+		types.SUBGROUP_NAME, subgroupName), nil, nil, nil)
+}
+
+func (c *Client) ListSubgroups(requestInfo api.RequestInfo, filter *api.Filter) ([]api.GroupSubgroups, int, error) {
+	filter = getFilter(filter)
+	response := &types.ListSubgroupsResponse{}
+	if err := c.do(requestInfo, http.MethodGet, groupRoute(types.GROUP_ID_GROUPS_URL, filter.Org, filter.GroupName),
+		filterQuery(filter), nil, response); err != nil {
+		return nil, 0, err
+	}
+	return response.Subgroups, response.Total, nil
+}
+
+func (c *Client) AttachPolicyToGroup(requestInfo api.RequestInfo, org string, groupName string, policyName string) error {
+	return c.do(requestInfo, http.MethodPost, groupRoute(types.GROUP_ID_POLICIES_ID_URL, org, groupName,
+		types.POLICY_NAME, policyName), nil, nil, nil)
+}
+
+func (c *Client) DetachPolicyToGroup(requestInfo api.RequestInfo, org string, groupName string, policyName string) error {
+	return c.do(requestInfo, http.MethodDelete, groupRoute(types.GROUP_ID_POLICIES_ID_URL, org, groupName,
+		types.POLICY_NAME, policyName), nil, nil, nil)
+}
+
+func (c *Client) ListAttachedGroupPolicies(requestInfo api.RequestInfo, filter *api.Filter) ([]api.GroupPolicies, int, error) {
+	filter = getFilter(filter)
+	response := &types.ListAttachedGroupPoliciesResponse{}
+	if err := c.do(requestInfo, http.MethodGet, groupRoute(types.GROUP_ID_POLICIES_URL, filter.Org, filter.GroupName),
+		filterQuery(filter), nil, response); err != nil {
+		return nil, 0, err
+	}
+	return response.AttachedPolicies, response.Total, nil
+}
+
+func (c *Client) UpdateMembers(requestInfo api.RequestInfo, org string, groupName string, add []string, remove []string) (*api.GroupBulkResult, error) {
+	request := &types.UpdateMembersRequest{
+		Add:    add,
+		Remove: remove,
+	}
+	return c.doGroupBulk(requestInfo, groupRoute(types.GROUP_ID_USERS_URL, org, groupName), request)
+}
+
+func (c *Client) UpdateAttachedGroupPolicies(requestInfo api.RequestInfo, org string, groupName string, attach []string, detach []string) (*api.GroupBulkResult, error) {
+	request := &types.UpdateAttachedGroupPoliciesRequest{
+		Attach: attach,
+		Detach: detach,
+	}
+	return c.doGroupBulk(requestInfo, groupRoute(types.GROUP_ID_POLICIES_URL, org, groupName), request)
+}
+
+// PRIVATE HELPER METHODS
+
+// groupRoute replaces the organization and group path params of pattern, along with the other params given
+func groupRoute(pattern string, org string, groupName string, params ...string) string {
+	return route(pattern, append([]string{types.ORG_NAME, org, types.GROUP_NAME, groupName}, params...)...)
+}
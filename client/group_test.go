@@ -0,0 +1,338 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+func TestClient_GroupAPI(t *testing.T) {
+	now := time.Date(2016, time.July, 1, 10, 0, 0, 0, time.UTC)
+	group := &api.Group{
+		ID:       "GroupID",
+		Name:     "group1",
+		Path:     "/path/",
+		Org:      "org1",
+		Urn:      api.CreateUrn("org1", api.RESOURCE_GROUP, "/path/", "group1"),
+		CreateAt: now,
+		UpdateAt: now,
+		Revision: 1,
+	}
+	appliedBulkResult := &api.GroupBulkResult{
+		Applied: true,
+		Items: []api.GroupBulkItem{
+			{Operation: api.GROUP_BULK_OPERATION_ADD, Name: "user1"},
+			{Operation: api.GROUP_BULK_OPERATION_REMOVE, Name: "user2"},
+		},
+	}
+	bulkResult := &api.GroupBulkResult{
+		Applied: false,
+		Items: []api.GroupBulkItem{
+			{Operation: api.GROUP_BULK_OPERATION_ADD, Name: "user1"},
+			{
+				Operation: api.GROUP_BULK_OPERATION_REMOVE,
+				Name:      "user2",
+				Error: &api.Error{
+					Code:    api.USER_IS_NOT_A_MEMBER_OF_GROUP,
+					Message: "User with externalId user2 is not a member of group with org org1 and name group1",
+				},
+			},
+		},
+	}
+	policiesBulkResult := &api.GroupBulkResult{
+		Applied: true,
+		Items: []api.GroupBulkItem{
+			{Operation: api.GROUP_BULK_OPERATION_ATTACH, Name: "policy1"},
+		},
+	}
+	failedPoliciesBulkResult := &api.GroupBulkResult{
+		Applied: false,
+		Items: []api.GroupBulkItem{
+			{
+				Operation: api.GROUP_BULK_OPERATION_DETACH,
+				Name:      "policy2",
+				Error: &api.Error{
+					Code:    api.POLICY_IS_NOT_ATTACHED_TO_GROUP,
+					Message: "Policy with org org1 and name policy2 is not attached to group with org org1 and name group1",
+				},
+			},
+		},
+	}
+
+	runClientTestCases(t, map[string]clientTestCase{
+		"OkCaseAddGroup": {
+			call: func(c *Client) (interface{}, error) {
+				return c.AddGroup(api.RequestInfo{}, "org1", "group1", "/path/")
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/organizations/org1/groups",
+				body:     `{"name": "group1", "path": "/path/"}`,
+				status:   http.StatusCreated,
+				response: group,
+			},
+			expectedResult: group,
+		},
+		"OkCaseGetGroupByName": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetGroupByName(api.RequestInfo{}, "org1", "group1")
+			},
+			exchange: testExchange{
+				method:   http.MethodGet,
+				url:      "/api/v1/organizations/org1/groups/group1",
+				status:   http.StatusOK,
+				response: group,
+			},
+			expectedResult: group,
+		},
+		"OkCaseListGroups": {
+			call: func(c *Client) (interface{}, error) {
+				groups, total, nextToken, err := c.ListGroups(api.RequestInfo{}, &api.Filter{
+					Org:    "org1",
+					Member: "user1",
+				})
+				return listResult{groups, total, nextToken}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/groups?Member=user1&Org=org1",
+				status: http.StatusOK,
+				response: &types.ListAllGroupsResponse{
+					Groups: []api.GroupIdentity{{Org: "org1", Name: "group1"}},
+					Total:  1,
+				},
+			},
+			expectedResult: listResult{[]api.GroupIdentity{{Org: "org1", Name: "group1"}}, 1, ""},
+		},
+		"OkCaseUpdateGroup": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateGroup(api.RequestInfo{}, "org1", "group0", "group1", "/path/")
+			},
+			exchange: testExchange{
+				method:   http.MethodPut,
+				url:      "/api/v1/organizations/org1/groups/group0",
+				body:     `{"name": "group1", "path": "/path/"}`,
+				status:   http.StatusOK,
+				response: group,
+			},
+			expectedResult: group,
+		},
+		"OkCaseRemoveGroup": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.RemoveGroup(api.RequestInfo{}, "org1", "group1")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/organizations/org1/groups/group1",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseRestoreGroup": {
+			call: func(c *Client) (interface{}, error) {
+				return c.RestoreGroup(api.RequestInfo{}, "org1", "group1")
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/organizations/org1/groups/group1/restore",
+				status:   http.StatusOK,
+				response: group,
+			},
+			expectedResult: group,
+		},
+		"OkCaseAddMember": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.AddMember(api.RequestInfo{}, "user1", "group1", "org1")
+			},
+			exchange: testExchange{
+				method: http.MethodPost,
+				url:    "/api/v1/organizations/org1/groups/group1/users/user1",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseRemoveMember": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.RemoveMember(api.RequestInfo{}, "user1", "group1", "org1")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/organizations/org1/groups/group1/users/user1",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseListMembers": {
+			call: func(c *Client) (interface{}, error) {
+				members, total, nextToken, err := c.ListMembers(api.RequestInfo{}, &api.Filter{
+					Org:        "org1",
+					GroupName:  "group1",
+					Transitive: true,
+				})
+				return listResult{members, total, nextToken}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/organizations/org1/groups/group1/users?Transitive=true",
+				status: http.StatusOK,
+				response: &types.ListMembersResponse{
+					Members: []api.GroupMembers{{User: "user1", CreateAt: now}},
+					Total:   1,
+				},
+			},
+			expectedResult: listResult{[]api.GroupMembers{{User: "user1", CreateAt: now}}, 1, ""},
+		},
+		"OkCaseUpdateMembers": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateMembers(api.RequestInfo{}, "org1", "group1", []string{"user1"}, []string{"user2"})
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/organizations/org1/groups/group1/users",
+				body:     `{"add": ["user1"], "remove": ["user2"]}`,
+				status:   http.StatusOK,
+				response: appliedBulkResult,
+			},
+			expectedResult: appliedBulkResult,
+		},
+		"ErrorCaseUpdateMembersItemFailed": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateMembers(api.RequestInfo{}, "org1", "group1", []string{"user1"}, []string{"user2"})
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/organizations/org1/groups/group1/users",
+				body:     `{"add": ["user1"], "remove": ["user2"]}`,
+				status:   http.StatusNotFound,
+				response: bulkResult,
+			},
+			expectedResult: bulkResult,
+			wantError:      bulkResult.Items[1].Error,
+		},
+		"ErrorCaseUpdateMembersGroupNotFound": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateMembers(api.RequestInfo{}, "org1", "group1", []string{"user1"}, nil)
+			},
+			exchange: testExchange{
+				method: http.MethodPost,
+				url:    "/api/v1/organizations/org1/groups/group1/users",
+				body:   `{"add": ["user1"]}`,
+				status: http.StatusNotFound,
+				response: &api.Error{
+					Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+					Message: "Group with org org1 and name group1 not found",
+				},
+			},
+			expectedResult: (*api.GroupBulkResult)(nil),
+			wantError: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group with org org1 and name group1 not found",
+			},
+		},
+		"OkCaseAddSubgroup": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.AddSubgroup(api.RequestInfo{}, "org1", "group1", "group2")
+			},
+			exchange: testExchange{
+				method: http.MethodPost,
+				url:    "/api/v1/organizations/org1/groups/group1/groups/group2",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseRemoveSubgroup": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.RemoveSubgroup(api.RequestInfo{}, "org1", "group1", "group2")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/organizations/org1/groups/group1/groups/group2",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseListSubgroups": {
+			call: func(c *Client) (interface{}, error) {
+				subgroups, total, err := c.ListSubgroups(api.RequestInfo{}, &api.Filter{
+					Org:       "org1",
+					GroupName: "group1",
+				})
+				return listResult{subgroups, total, ""}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/organizations/org1/groups/group1/groups",
+				status: http.StatusOK,
+				response: &types.ListSubgroupsResponse{
+					Subgroups: []api.GroupSubgroups{{Group: "group2", CreateAt: now}},
+					Total:     1,
+				},
+			},
+			expectedResult: listResult{[]api.GroupSubgroups{{Group: "group2", CreateAt: now}}, 1, ""},
+		},
+		"OkCaseAttachPolicyToGroup": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.AttachPolicyToGroup(api.RequestInfo{}, "org1", "group1", "policy1")
+			},
+			exchange: testExchange{
+				method: http.MethodPost,
+				url:    "/api/v1/organizations/org1/groups/group1/policies/policy1",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseDetachPolicyToGroup": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.DetachPolicyToGroup(api.RequestInfo{}, "org1", "group1", "policy1")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/organizations/org1/groups/group1/policies/policy1",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseListAttachedGroupPolicies": {
+			call: func(c *Client) (interface{}, error) {
+				policies, total, err := c.ListAttachedGroupPolicies(api.RequestInfo{}, &api.Filter{
+					Org:       "org1",
+					GroupName: "group1",
+				})
+				return listResult{policies, total, ""}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/organizations/org1/groups/group1/policies",
+				status: http.StatusOK,
+				response: &types.ListAttachedGroupPoliciesResponse{
+					AttachedPolicies: []api.GroupPolicies{{Policy: "policy1", CreateAt: now}},
+					Total:            1,
+				},
+			},
+			expectedResult: listResult{[]api.GroupPolicies{{Policy: "policy1", CreateAt: now}}, 1, ""},
+		},
+		"OkCaseUpdateAttachedGroupPolicies": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateAttachedGroupPolicies(api.RequestInfo{}, "org1", "group1", []string{"policy1"}, nil)
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/organizations/org1/groups/group1/policies",
+				body:     `{"attach": ["policy1"]}`,
+				status:   http.StatusOK,
+				response: policiesBulkResult,
+			},
+			expectedResult: policiesBulkResult,
+		},
+		"ErrorCaseUpdateAttachedGroupPoliciesItemFailed": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateAttachedGroupPolicies(api.RequestInfo{}, "org1", "group1", nil, []string{"policy2"})
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/organizations/org1/groups/group1/policies",
+				body:     `{"detach": ["policy2"]}`,
+				status:   http.StatusNotFound,
+				response: failedPoliciesBulkResult,
+			},
+			expectedResult: failedPoliciesBulkResult,
+			wantError:      failedPoliciesBulkResult.Items[0].Error,
+		},
+	})
+}
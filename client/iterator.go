@@ -0,0 +1,305 @@
+package client
+
+import (
+	"github.com/Tecsisa/foulkon/api"
+)
+
+// TYPE DEFINITIONS
+
+// pager requests the pages of a list following its pagination. Lists with keyset pagination are
+// followed by their next token, and the other ones by offset until their total is reached.
+type pager struct {
+	filter api.Filter
+	done   bool
+	err    error
+}
+
+// StringIterator iterates over a list of names, like user external identifiers
+type StringIterator struct {
+	pager
+	fetch func(filter *api.Filter) ([]string, int, string, error)
+	page  []string
+	index int
+}
+
+// GroupIdentityIterator iterates over a list of groups
+type GroupIdentityIterator struct {
+	pager
+	fetch func(filter *api.Filter) ([]api.GroupIdentity, int, string, error)
+	page  []api.GroupIdentity
+	index int
+}
+
+// PolicyIdentityIterator iterates over a list of policies
+type PolicyIdentityIterator struct {
+	pager
+	fetch func(filter *api.Filter) ([]api.PolicyIdentity, int, string, error)
+	page  []api.PolicyIdentity
+	index int
+}
+
+// ProxyResourceIdentityIterator iterates over a list of proxy resources
+type ProxyResourceIdentityIterator struct {
+	pager
+	fetch func(filter *api.Filter) ([]api.ProxyResourceIdentity, int, string, error)
+	page  []api.ProxyResourceIdentity
+	index int
+}
+
+// GroupMembersIterator iterates over the members of a group
+type GroupMembersIterator struct {
+	pager
+	fetch func(filter *api.Filter) ([]api.GroupMembers, int, string, error)
+	page  []api.GroupMembers
+	index int
+}
+
+// PolicyGroupsIterator iterates over the groups a policy is attached to
+type PolicyGroupsIterator struct {
+	pager
+	fetch func(filter *api.Filter) ([]api.PolicyGroups, int, string, error)
+	page  []api.PolicyGroups
+	index int
+}
+
+// ITERATORS
+
+// IterateUsers iterates over the users of every page of ListUsers, starting from filter offset or next token
+func (c *Client) IterateUsers(requestInfo api.RequestInfo, filter *api.Filter) *StringIterator {
+	return &StringIterator{
+		pager: newPager(filter),
+		fetch: func(filter *api.Filter) ([]string, int, string, error) {
+			return c.ListUsers(requestInfo, filter)
+		},
+	}
+}
+
+// IterateOidcProviders iterates over the OIDC providers of every page of ListOidcProviders
+func (c *Client) IterateOidcProviders(requestInfo api.RequestInfo, filter *api.Filter) *StringIterator {
+	return &StringIterator{
+		pager: newPager(filter),
+		fetch: func(filter *api.Filter) ([]string, int, string, error) {
+			oidcProviders, total, err := c.ListOidcProviders(requestInfo, filter)
+			return oidcProviders, total, "", err
+		},
+	}
+}
+
+// IterateGroups iterates over the groups of every page of ListGroups
+func (c *Client) IterateGroups(requestInfo api.RequestInfo, filter *api.Filter) *GroupIdentityIterator {
+	return &GroupIdentityIterator{
+		pager: newPager(filter),
+		fetch: func(filter *api.Filter) ([]api.GroupIdentity, int, string, error) {
+			return c.ListGroups(requestInfo, filter)
+		},
+	}
+}
+
+// IteratePolicies iterates over the policies of every page of ListPolicies
+func (c *Client) IteratePolicies(requestInfo api.RequestInfo, filter *api.Filter) *PolicyIdentityIterator {
+	return &PolicyIdentityIterator{
+		pager: newPager(filter),
+		fetch: func(filter *api.Filter) ([]api.PolicyIdentity, int, string, error) {
+			return c.ListPolicies(requestInfo, filter)
+		},
+	}
+}
+
+// IterateProxyResources iterates over the proxy resources of every page of ListProxyResources
+func (c *Client) IterateProxyResources(requestInfo api.RequestInfo, filter *api.Filter) *ProxyResourceIdentityIterator {
+	return &ProxyResourceIdentityIterator{
+		pager: newPager(filter),
+		fetch: func(filter *api.Filter) ([]api.ProxyResourceIdentity, int, string, error) {
+			return c.ListProxyResources(requestInfo, filter)
+		},
+	}
+}
+
+// IterateMembers iterates over the members of every page of ListMembers
+func (c *Client) IterateMembers(requestInfo api.RequestInfo, filter *api.Filter) *GroupMembersIterator {
+	return &GroupMembersIterator{
+		pager: newPager(filter),
+		fetch: func(filter *api.Filter) ([]api.GroupMembers, int, string, error) {
+			return c.ListMembers(requestInfo, filter)
+		},
+	}
+}
+
+// IterateAttachedGroups iterates over the groups of every page of ListAttachedGroups
+func (c *Client) IterateAttachedGroups(requestInfo api.RequestInfo, filter *api.Filter) *PolicyGroupsIterator {
+	return &PolicyGroupsIterator{
+		pager: newPager(filter),
+		fetch: func(filter *api.Filter) ([]api.PolicyGroups, int, string, error) {
+			return c.ListAttachedGroups(requestInfo, filter)
+		},
+	}
+}
+
+// Next advances to the next element, requesting the next page when needed. It returns false
+// when there are no more elements or a request failed, check Err to tell them apart.
+func (it *StringIterator) Next() bool {
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.nextPage(func(filter *api.Filter) (total int, nextToken string, err error) {
+			it.page, total, nextToken, err = it.fetch(filter)
+			return
+		}) {
+			return false
+		}
+		it.index = 0
+	}
+	return true
+}
+
+// Value returns the current element
+func (it *StringIterator) Value() string {
+	return it.page[it.index]
+}
+
+// Next advances to the next element, requesting the next page when needed. It returns false
+// when there are no more elements or a request failed, check Err to tell them apart.
+func (it *GroupIdentityIterator) Next() bool {
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.nextPage(func(filter *api.Filter) (total int, nextToken string, err error) {
+			it.page, total, nextToken, err = it.fetch(filter)
+			return
+		}) {
+			return false
+		}
+		it.index = 0
+	}
+	return true
+}
+
+// Value returns the current element
+func (it *GroupIdentityIterator) Value() api.GroupIdentity {
+	return it.page[it.index]
+}
+
+// Next advances to the next element, requesting the next page when needed. It returns false
+// when there are no more elements or a request failed, check Err to tell them apart.
+func (it *PolicyIdentityIterator) Next() bool {
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.nextPage(func(filter *api.Filter) (total int, nextToken string, err error) {
+			it.page, total, nextToken, err = it.fetch(filter)
+			return
+		}) {
+			return false
+		}
+		it.index = 0
+	}
+	return true
+}
+
+// Value returns the current element
+func (it *PolicyIdentityIterator) Value() api.PolicyIdentity {
+	return it.page[it.index]
+}
+
+// Next advances to the next element, requesting the next page when needed. It returns false
+// when there are no more elements or a request failed, check Err to tell them apart.
+func (it *ProxyResourceIdentityIterator) Next() bool {
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.nextPage(func(filter *api.Filter) (total int, nextToken string, err error) {
+			it.page, total, nextToken, err = it.fetch(filter)
+			return
+		}) {
+			return false
+		}
+		it.index = 0
+	}
+	return true
+}
+
+// Value returns the current element
+func (it *ProxyResourceIdentityIterator) Value() api.ProxyResourceIdentity {
+	return it.page[it.index]
+}
+
+// Next advances to the next element, requesting the next page when needed. It returns false
+// when there are no more elements or a request failed, check Err to tell them apart.
+func (it *GroupMembersIterator) Next() bool {
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.nextPage(func(filter *api.Filter) (total int, nextToken string, err error) {
+			it.page, total, nextToken, err = it.fetch(filter)
+			return
+		}) {
+			return false
+		}
+		it.index = 0
+	}
+	return true
+}
+
+// Value returns the current element
+func (it *GroupMembersIterator) Value() api.GroupMembers {
+	return it.page[it.index]
+}
+
+// Next advances to the next element, requesting the next page when needed. It returns false
+// when there are no more elements or a request failed, check Err to tell them apart.
+func (it *PolicyGroupsIterator) Next() bool {
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.nextPage(func(filter *api.Filter) (total int, nextToken string, err error) {
+			it.page, total, nextToken, err = it.fetch(filter)
+			return
+		}) {
+			return false
+		}
+		it.index = 0
+	}
+	return true
+}
+
+// Value returns the current element
+func (it *PolicyGroupsIterator) Value() api.PolicyGroups {
+	return it.page[it.index]
+}
+
+// Err returns the error of the last page requested, if any
+func (p *pager) Err() error {
+	return p.err
+}
+
+// PRIVATE HELPER METHODS
+
+func newPager(filter *api.Filter) pager {
+	return pager{filter: *getFilter(filter)}
+}
+
+// nextPage requests the page of the current filter with fetch and moves the filter to the page after it.
+// It returns false if the list already ended or the page couldn't be retrieved. Pages are advanced by
+// the limit requested, not by the elements received, because the worker leaves out of a page the
+// elements the user isn't allowed to see.
+func (p *pager) nextPage(fetch func(filter *api.Filter) (int, string, error)) bool {
+	if p.done {
+		return false
+	}
+	filter := p.filter
+	total, nextToken, err := fetch(&filter)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+	switch {
+	case nextToken != "":
+		p.filter.NextToken = nextToken
+		p.filter.Offset = 0
+	case p.filter.NextToken != "":
+		p.done = true
+	default:
+		limit := p.filter.Limit
+		if limit == 0 {
+			limit = api.DEFAULT_LIMIT_SIZE
+		}
+		p.filter.Offset += limit
+		p.done = p.filter.Offset >= total
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_IterateUsers(t *testing.T) {
+	testcases := map[string]struct {
+		filter    *api.Filter
+		exchanges []testExchange
+		// Expected result
+		expectedUsers []string
+		wantError     error
+	}{
+		"OkCaseOffsetPagination": {
+			filter: &api.Filter{Limit: 2},
+			exchanges: []testExchange{
+				{
+					method: http.MethodGet,
+					url:    "/api/v1/users?Limit=2",
+					status: http.StatusOK,
+					response: &types.GetUserExternalIDsResponse{
+						ExternalIDs: []string{"user1", "user2"},
+						Total:       5,
+					},
+				},
+				{
+					// Users not allowed are left out of the page
+					method: http.MethodGet,
+					url:    "/api/v1/users?Limit=2&Offset=2",
+					status: http.StatusOK,
+					response: &types.GetUserExternalIDsResponse{
+						Total: 5,
+					},
+				},
+				{
+					method: http.MethodGet,
+					url:    "/api/v1/users?Limit=2&Offset=4",
+					status: http.StatusOK,
+					response: &types.GetUserExternalIDsResponse{
+						ExternalIDs: []string{"user5"},
+						Total:       5,
+					},
+				},
+			},
+			expectedUsers: []string{"user1", "user2", "user5"},
+		},
+		"OkCaseDefaultLimit": {
+			filter: &api.Filter{Offset: 15},
+			exchanges: []testExchange{
+				{
+					method: http.MethodGet,
+					url:    "/api/v1/users?Offset=15",
+					status: http.StatusOK,
+					response: &types.GetUserExternalIDsResponse{
+						ExternalIDs: []string{"user16"},
+						Total:       16,
+					},
+				},
+			},
+			expectedUsers: []string{"user16"},
+		},
+		"OkCaseTokenPagination": {
+			filter: &api.Filter{Offset: 2, OrderBy: "createAt"},
+			exchanges: []testExchange{
+				{
+					method: http.MethodGet,
+					url:    "/api/v1/users?Offset=2&OrderBy=createAt",
+					status: http.StatusOK,
+					response: &types.GetUserExternalIDsResponse{
+						ExternalIDs: []string{"user3"},
+						Total:       5,
+						NextToken:   "token1",
+					},
+				},
+				{
+					method: http.MethodGet,
+					url:    "/api/v1/users?NextToken=token1&OrderBy=createAt",
+					status: http.StatusOK,
+					response: &types.GetUserExternalIDsResponse{
+						ExternalIDs: []string{"user4", "user5"},
+						Total:       5,
+					},
+				},
+			},
+			expectedUsers: []string{"user3", "user4", "user5"},
+		},
+		"OkCaseNoUsers": {
+			exchanges: []testExchange{
+				{
+					method:   http.MethodGet,
+					url:      "/api/v1/users",
+					status:   http.StatusOK,
+					response: &types.GetUserExternalIDsResponse{},
+				},
+			},
+			expectedUsers: []string{},
+		},
+		"ErrorCaseSecondPage": {
+			filter: &api.Filter{Limit: 1},
+			exchanges: []testExchange{
+				{
+					method: http.MethodGet,
+					url:    "/api/v1/users?Limit=1",
+					status: http.StatusOK,
+					response: &types.GetUserExternalIDsResponse{
+						ExternalIDs: []string{"user1"},
+						Total:       2,
+					},
+				},
+				{
+					method: http.MethodGet,
+					url:    "/api/v1/users?Limit=1&Offset=1",
+					status: http.StatusInternalServerError,
+				},
+			},
+			expectedUsers: []string{"user1"},
+			wantError: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "500 Internal Server Error",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		c, server := newTestClient(t, n, test.exchanges...)
+
+		users := []string{}
+		it := c.IterateUsers(api.RequestInfo{}, test.filter)
+		for it.Next() {
+			users = append(users, it.Value())
+		}
+		assert.Equal(t, test.wantError, it.Err(), "Error in test case %v", n)
+		assert.Equal(t, test.expectedUsers, users, "Error in test case %v", n)
+		// Iterator stays finished
+		assert.False(t, it.Next(), "Error in test case %v", n)
+		server.Close()
+	}
+}
+
+func TestClient_IterateGroups(t *testing.T) {
+	c, server := newTestClient(t, "IterateGroups",
+		testExchange{
+			method: http.MethodGet,
+			url:    "/api/v1/groups?Limit=1&Org=org1",
+			status: http.StatusOK,
+			response: &types.ListAllGroupsResponse{
+				Groups: []api.GroupIdentity{{Org: "org1", Name: "group1"}},
+				Total:  2,
+			},
+		},
+		testExchange{
+			method: http.MethodGet,
+			url:    "/api/v1/groups?Limit=1&Offset=1&Org=org1",
+			status: http.StatusOK,
+			response: &types.ListAllGroupsResponse{
+				Groups: []api.GroupIdentity{{Org: "org1", Name: "group2"}},
+				Total:  2,
+			},
+		},
+	)
+	defer server.Close()
+
+	groups := []api.GroupIdentity{}
+	it := c.IterateGroups(api.RequestInfo{}, &api.Filter{Org: "org1", Limit: 1})
+	for it.Next() {
+		groups = append(groups, it.Value())
+	}
+	assert.Nil(t, it.Err(), "Error iterating groups")
+	assert.Equal(t, []api.GroupIdentity{{Org: "org1", Name: "group1"}, {Org: "org1", Name: "group2"}}, groups, "Error iterating groups")
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+const (
+	// Policies of all organizations, filtered by Org query param
+	POLICY_ALL_URL = types.API_VERSION_1 + "/policies"
+)
+
+// POLICY API IMPLEMENTATION
+
+func (c *Client) AddPolicy(requestInfo api.RequestInfo, name string, path string, org string, statements []api.Statement) (*api.Policy, error) {
+	request := &types.CreatePolicyRequest{
+		Name:       name,
+		Path:       path,
+		Statements: statements,
+	}
+	policy := &api.Policy{}
+	if err := c.do(requestInfo, http.MethodPost, route(types.POLICY_ROOT_URL, types.ORG_NAME, org), nil, request, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (c *Client) GetPolicyByName(requestInfo api.RequestInfo, org string, name string) (*api.Policy, error) {
+	policy := &api.Policy{}
+	if err := c.do(requestInfo, http.MethodGet, policyRoute(types.POLICY_ID_URL, org, name), nil, nil, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (c *Client) ListPolicies(requestInfo api.RequestInfo, filter *api.Filter) ([]api.PolicyIdentity, int, string, error) {
+	filter = getFilter(filter)
+	query := filterQuery(filter)
+	if filter.Org != "" {
+		query.Set("Org", filter.Org)
+	}
+	response := &types.ListAllPoliciesResponse{}
+	if err := c.do(requestInfo, http.MethodGet, POLICY_ALL_URL, query, nil, response); err != nil {
+		return nil, 0, "", err
+	}
+	return response.Policies, response.Total, response.NextToken, nil
+}
+
+func (c *Client) UpdatePolicy(requestInfo api.RequestInfo, org string, name string, newName string, newPath string,
+	newStatements []api.Statement) (*api.Policy, error) {
+	request := &types.UpdatePolicyRequest{
+		Name:       newName,
+		Path:       newPath,
+		Statements: newStatements,
+	}
+	policy := &api.Policy{}
+	if err := c.do(requestInfo, http.MethodPut, policyRoute(types.POLICY_ID_URL, org, name), nil, request, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (c *Client) RemovePolicy(requestInfo api.RequestInfo, org string, name string) error {
+	return c.do(requestInfo, http.MethodDelete, policyRoute(types.POLICY_ID_URL, org, name), nil, nil, nil)
+}
+
+func (c *Client) RestorePolicy(requestInfo api.RequestInfo, org string, name string) (*api.Policy, error) {
+	policy := &api.Policy{}
+	if err := c.do(requestInfo, http.MethodPost, policyRoute(types.POLICY_ID_RESTORE_URL, org, name), nil, nil, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (c *Client) ListAttachedGroups(requestInfo api.RequestInfo, filter *api.Filter) ([]api.PolicyGroups, int, string, error) {
+	filter = getFilter(filter)
+	response := &types.ListAttachedGroupsResponse{}
+	if err := c.do(requestInfo, http.MethodGet, policyRoute(types.POLICY_ID_GROUPS_URL, filter.Org, filter.PolicyName),
+		filterQuery(filter), nil, response); err != nil {
+		return nil, 0, "", err
+	}
+	return response.Groups, response.Total, response.NextToken, nil
+}
+
+func (c *Client) ListPolicyVersions(requestInfo api.RequestInfo, filter *api.Filter) ([]api.PolicyVersion, int, error) {
+	filter = getFilter(filter)
+	response := &types.ListPolicyVersionsResponse{}
+	if err := c.do(requestInfo, http.MethodGet, policyRoute(types.POLICY_ID_VERSIONS_URL, filter.Org, filter.PolicyName),
+		filterQuery(filter), nil, response); err != nil {
+		return nil, 0, err
+	}
+	return response.Versions, response.Total, nil
+}
+
+func (c *Client) GetPolicyVersion(requestInfo api.RequestInfo, org string, name string, version int) (*api.PolicyVersion, error) {
+	policyVersion := &api.PolicyVersion{}
+	if err := c.do(requestInfo, http.MethodGet, policyRoute(types.POLICY_ID_VERSIONS_ID_URL, org, name,
+		types.POLICY_VERSION, strconv.Itoa(version)), nil, nil, policyVersion); err != nil {
+		return nil, err
+	}
+	return policyVersion, nil
+}
+
+func (c *Client) DiffPolicyVersions(requestInfo api.RequestInfo, org string, name string, fromVersion int, toVersion int) (*api.PolicyVersionDiff, error) {
+	query := url.Values{}
+	query.Set("From", strconv.Itoa(fromVersion))
+	query.Set("To", strconv.Itoa(toVersion))
+	diff := &api.PolicyVersionDiff{}
+	if err := c.do(requestInfo, http.MethodGet, policyRoute(types.POLICY_ID_VERSIONS_DIFF_URL, org, name), query, nil, diff); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+func (c *Client) SetDefaultPolicyVersion(requestInfo api.RequestInfo, org string, name string, version int) (*api.Policy, error) {
+	request := &types.SetDefaultPolicyVersionRequest{
+		Version: version,
+	}
+	policy := &api.Policy{}
+	if err := c.do(requestInfo, http.MethodPut, policyRoute(types.POLICY_ID_DEFAULT_VERSION_URL, org, name), nil, request, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// PRIVATE HELPER METHODS
+
+// policyRoute replaces the organization and policy path params of pattern, along with the other params given
+func policyRoute(pattern string, org string, name string, params ...string) string {
+	return route(pattern, append([]string{types.ORG_NAME, org, types.POLICY_NAME, name}, params...)...)
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+func TestClient_PolicyAPI(t *testing.T) {
+	now := time.Date(2016, time.July, 1, 10, 0, 0, 0, time.UTC)
+	statements := []api.Statement{
+		{
+			Effect:    "allow",
+			Actions:   []string{api.USER_ACTION_GET_USER},
+			Resources: []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+		},
+	}
+	policy := &api.Policy{
+		ID:         "PolicyID",
+		Name:       "policy1",
+		Path:       "/path/",
+		Org:        "org1",
+		Urn:        api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "policy1"),
+		CreateAt:   now,
+		UpdateAt:   now,
+		Version:    2,
+		Statements: &statements,
+		Revision:   3,
+	}
+	policyVersion := &api.PolicyVersion{
+		ID:         "PolicyVersionID",
+		Version:    1,
+		Author:     "admin",
+		CreateAt:   now,
+		Statements: &statements,
+	}
+
+	runClientTestCases(t, map[string]clientTestCase{
+		"OkCaseAddPolicy": {
+			call: func(c *Client) (interface{}, error) {
+				return c.AddPolicy(api.RequestInfo{}, "policy1", "/path/", "org1", statements)
+			},
+			exchange: testExchange{
+				method: http.MethodPost,
+				url:    "/api/v1/organizations/org1/policies",
+				body: `{"name": "policy1", "path": "/path/", "statements": [
+					{"effect": "allow", "actions": ["iam:GetUser"], "resources": ["urn:iws:iam::user/path/*"]}
+				]}`,
+				status:   http.StatusCreated,
+				response: policy,
+			},
+			expectedResult: policy,
+		},
+		"OkCaseGetPolicyByName": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetPolicyByName(api.RequestInfo{}, "org1", "policy1")
+			},
+			exchange: testExchange{
+				method:   http.MethodGet,
+				url:      "/api/v1/organizations/org1/policies/policy1",
+				status:   http.StatusOK,
+				response: policy,
+			},
+			expectedResult: policy,
+		},
+		"OkCaseListPolicies": {
+			call: func(c *Client) (interface{}, error) {
+				policies, total, nextToken, err := c.ListPolicies(api.RequestInfo{}, &api.Filter{
+					Action:    "iam:*",
+					NextToken: "token1",
+				})
+				return listResult{policies, total, nextToken}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/policies?Action=iam%3A%2A&NextToken=token1",
+				status: http.StatusOK,
+				response: &types.ListAllPoliciesResponse{
+					Policies:  []api.PolicyIdentity{{Org: "org1", Name: "policy1"}},
+					Total:     3,
+					NextToken: "token2",
+				},
+			},
+			expectedResult: listResult{[]api.PolicyIdentity{{Org: "org1", Name: "policy1"}}, 3, "token2"},
+		},
+		"OkCaseUpdatePolicy": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdatePolicy(api.RequestInfo{}, "org1", "policy0", "policy1", "/path/", statements)
+			},
+			exchange: testExchange{
+				method: http.MethodPut,
+				url:    "/api/v1/organizations/org1/policies/policy0",
+				body: `{"name": "policy1", "path": "/path/", "statements": [
+					{"effect": "allow", "actions": ["iam:GetUser"], "resources": ["urn:iws:iam::user/path/*"]}
+				]}`,
+				status:   http.StatusOK,
+				response: policy,
+			},
+			expectedResult: policy,
+		},
+		"OkCaseRemovePolicy": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.RemovePolicy(api.RequestInfo{}, "org1", "policy1")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/organizations/org1/policies/policy1",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseRestorePolicy": {
+			call: func(c *Client) (interface{}, error) {
+				return c.RestorePolicy(api.RequestInfo{}, "org1", "policy1")
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/organizations/org1/policies/policy1/restore",
+				status:   http.StatusOK,
+				response: policy,
+			},
+			expectedResult: policy,
+		},
+		"OkCaseListAttachedGroups": {
+			call: func(c *Client) (interface{}, error) {
+				groups, total, nextToken, err := c.ListAttachedGroups(api.RequestInfo{}, &api.Filter{
+					Org:        "org1",
+					PolicyName: "policy1",
+				})
+				return listResult{groups, total, nextToken}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/organizations/org1/policies/policy1/groups",
+				status: http.StatusOK,
+				response: &types.ListAttachedGroupsResponse{
+					Groups: []api.PolicyGroups{{Group: "group1", CreateAt: now}},
+					Total:  1,
+				},
+			},
+			expectedResult: listResult{[]api.PolicyGroups{{Group: "group1", CreateAt: now}}, 1, ""},
+		},
+		"OkCaseListPolicyVersions": {
+			call: func(c *Client) (interface{}, error) {
+				versions, total, err := c.ListPolicyVersions(api.RequestInfo{}, &api.Filter{
+					Org:        "org1",
+					PolicyName: "policy1",
+					Limit:      1,
+				})
+				return listResult{versions, total, ""}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/organizations/org1/policies/policy1/versions?Limit=1",
+				status: http.StatusOK,
+				response: &types.ListPolicyVersionsResponse{
+					Versions: []api.PolicyVersion{*policyVersion},
+					Limit:    1,
+					Total:    2,
+				},
+			},
+			expectedResult: listResult{[]api.PolicyVersion{*policyVersion}, 2, ""},
+		},
+		"OkCaseGetPolicyVersion": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetPolicyVersion(api.RequestInfo{}, "org1", "policy1", 1)
+			},
+			exchange: testExchange{
+				method:   http.MethodGet,
+				url:      "/api/v1/organizations/org1/policies/policy1/versions/1",
+				status:   http.StatusOK,
+				response: policyVersion,
+			},
+			expectedResult: policyVersion,
+		},
+		"OkCaseDiffPolicyVersions": {
+			call: func(c *Client) (interface{}, error) {
+				return c.DiffPolicyVersions(api.RequestInfo{}, "org1", "policy1", 1, 2)
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/organizations/org1/policies/policy1/versions-diff?From=1&To=2",
+				status: http.StatusOK,
+				response: &api.PolicyVersionDiff{
+					FromVersion:       1,
+					ToVersion:         2,
+					AddedStatements:   statements,
+					RemovedStatements: []api.Statement{},
+				},
+			},
+			expectedResult: &api.PolicyVersionDiff{
+				FromVersion:       1,
+				ToVersion:         2,
+				AddedStatements:   statements,
+				RemovedStatements: []api.Statement{},
+			},
+		},
+		"OkCaseSetDefaultPolicyVersion": {
+			call: func(c *Client) (interface{}, error) {
+				return c.SetDefaultPolicyVersion(api.RequestInfo{}, "org1", "policy1", 2)
+			},
+			exchange: testExchange{
+				method:   http.MethodPut,
+				url:      "/api/v1/organizations/org1/policies/policy1/default-version",
+				body:     `{"version": 2}`,
+				status:   http.StatusOK,
+				response: policy,
+			},
+			expectedResult: policy,
+		},
+	})
+}
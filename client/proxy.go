@@ -0,0 +1,75 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+// PROXY RESOURCE API IMPLEMENTATION
+
+func (c *Client) AddProxyResource(requestInfo api.RequestInfo, name string, org string, path string, resource api.ResourceEntity) (*api.ProxyResource, error) {
+	request := &types.CreateProxyResourceRequest{
+		Name:     name,
+		Path:     path,
+		Resource: resource,
+	}
+	proxyResource := &api.ProxyResource{}
+	if err := c.do(requestInfo, http.MethodPost, route(types.PROXY_RESOURCE_ROOT_URL, types.ORG_NAME, org),
+		nil, request, proxyResource); err != nil {
+		return nil, err
+	}
+	return proxyResource, nil
+}
+
+func (c *Client) GetProxyResourceByName(requestInfo api.RequestInfo, org string, name string) (*api.ProxyResource, error) {
+	proxyResource := &api.ProxyResource{}
+	if err := c.do(requestInfo, http.MethodGet, proxyResourceRoute(org, name), nil, nil, proxyResource); err != nil {
+		return nil, err
+	}
+	return proxyResource, nil
+}
+
+// ListProxyResources lists the proxy resources of filter organization, the worker doesn't list
+// proxy resources of all organizations
+func (c *Client) ListProxyResources(requestInfo api.RequestInfo, filter *api.Filter) ([]api.ProxyResourceIdentity, int, string, error) {
+	filter = getFilter(filter)
+	response := &types.ListProxyResourcesResponse{}
+	if err := c.do(requestInfo, http.MethodGet, route(types.PROXY_RESOURCE_ROOT_URL, types.ORG_NAME, filter.Org),
+		filterQuery(filter), nil, response); err != nil {
+		return nil, 0, "", err
+	}
+	proxyResources := []api.ProxyResourceIdentity{}
+	for _, name := range response.Resources {
+		proxyResources = append(proxyResources, api.ProxyResourceIdentity{
+			Org:  filter.Org,
+			Name: name,
+		})
+	}
+	return proxyResources, response.Total, response.NextToken, nil
+}
+
+func (c *Client) UpdateProxyResource(requestInfo api.RequestInfo, org string, name string, newName string, newPath string,
+	newResource api.ResourceEntity) (*api.ProxyResource, error) {
+	request := &types.UpdateProxyResourceRequest{
+		Name:     newName,
+		Path:     newPath,
+		Resource: newResource,
+	}
+	proxyResource := &api.ProxyResource{}
+	if err := c.do(requestInfo, http.MethodPut, proxyResourceRoute(org, name), nil, request, proxyResource); err != nil {
+		return nil, err
+	}
+	return proxyResource, nil
+}
+
+func (c *Client) RemoveProxyResource(requestInfo api.RequestInfo, org string, name string) error {
+	return c.do(requestInfo, http.MethodDelete, proxyResourceRoute(org, name), nil, nil, nil)
+}
+
+// PRIVATE HELPER METHODS
+
+func proxyResourceRoute(org string, name string) string {
+	return route(types.PROXY_RESOURCE_ID_URL, types.ORG_NAME, org, types.PROXY_RESOURCE_NAME, name)
+}
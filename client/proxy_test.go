@@ -0,0 +1,107 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+func TestClient_ProxyResourcesAPI(t *testing.T) {
+	now := time.Date(2016, time.July, 1, 10, 0, 0, 0, time.UTC)
+	resource := api.ResourceEntity{
+		Host:   "http://localhost:8000",
+		Path:   "/resource",
+		Method: http.MethodGet,
+		Urn:    "urn:ews:example:instance1:resource/get",
+		Action: "example:get",
+	}
+	proxyResource := &api.ProxyResource{
+		ID:       "ProxyResourceID",
+		Name:     "resource1",
+		Org:      "org1",
+		Path:     "/path/",
+		Urn:      api.CreateUrn("org1", api.RESOURCE_PROXY, "/path/", "resource1"),
+		Resource: resource,
+		CreateAt: now,
+		UpdateAt: now,
+		Revision: 1,
+	}
+	resourceBody := `{"host": "http://localhost:8000", "path": "/resource", "method": "GET",
+		"urn": "urn:ews:example:instance1:resource/get", "action": "example:get"}`
+
+	runClientTestCases(t, map[string]clientTestCase{
+		"OkCaseAddProxyResource": {
+			call: func(c *Client) (interface{}, error) {
+				return c.AddProxyResource(api.RequestInfo{}, "resource1", "org1", "/path/", resource)
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/organizations/org1/proxy-resources",
+				body:     `{"name": "resource1", "path": "/path/", "resource": ` + resourceBody + `}`,
+				status:   http.StatusCreated,
+				response: proxyResource,
+			},
+			expectedResult: proxyResource,
+		},
+		"OkCaseGetProxyResourceByName": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetProxyResourceByName(api.RequestInfo{}, "org1", "resource1")
+			},
+			exchange: testExchange{
+				method:   http.MethodGet,
+				url:      "/api/v1/organizations/org1/proxy-resources/resource1",
+				status:   http.StatusOK,
+				response: proxyResource,
+			},
+			expectedResult: proxyResource,
+		},
+		"OkCaseListProxyResources": {
+			call: func(c *Client) (interface{}, error) {
+				proxyResources, total, nextToken, err := c.ListProxyResources(api.RequestInfo{}, &api.Filter{
+					Org:        "org1",
+					PathPrefix: "/path/",
+				})
+				return listResult{proxyResources, total, nextToken}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/organizations/org1/proxy-resources?PathPrefix=%2Fpath%2F",
+				status: http.StatusOK,
+				response: &types.ListProxyResourcesResponse{
+					Resources: []string{"resource1", "resource2"},
+					Total:     2,
+				},
+			},
+			expectedResult: listResult{[]api.ProxyResourceIdentity{
+				{Org: "org1", Name: "resource1"},
+				{Org: "org1", Name: "resource2"},
+			}, 2, ""},
+		},
+		"OkCaseUpdateProxyResource": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateProxyResource(api.RequestInfo{}, "org1", "resource0", "resource1", "/path/", resource)
+			},
+			exchange: testExchange{
+				method:   http.MethodPut,
+				url:      "/api/v1/organizations/org1/proxy-resources/resource0",
+				body:     `{"name": "resource1", "path": "/path/", "resource": ` + resourceBody + `}`,
+				status:   http.StatusOK,
+				response: proxyResource,
+			},
+			expectedResult: proxyResource,
+		},
+		"OkCaseRemoveProxyResource": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.RemoveProxyResource(api.RequestInfo{}, "org1", "resource1")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/organizations/org1/proxy-resources/resource1",
+				status: http.StatusNoContent,
+			},
+		},
+	})
+}
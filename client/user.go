@@ -0,0 +1,91 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+// USER API IMPLEMENTATION
+
+func (c *Client) AddUser(requestInfo api.RequestInfo, externalId string, path string) (*api.User, error) {
+	request := &types.CreateUserRequest{
+		ExternalID: externalId,
+		Path:       path,
+	}
+	user := &api.User{}
+	if err := c.do(requestInfo, http.MethodPost, types.USER_ROOT_URL, nil, request, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (c *Client) GetUserByExternalID(requestInfo api.RequestInfo, externalId string) (*api.User, error) {
+	user := &api.User{}
+	if err := c.do(requestInfo, http.MethodGet, route(types.USER_ID_URL, types.USER_ID, externalId), nil, nil, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (c *Client) ListUsers(requestInfo api.RequestInfo, filter *api.Filter) ([]string, int, string, error) {
+	response := &types.GetUserExternalIDsResponse{}
+	if err := c.do(requestInfo, http.MethodGet, types.USER_ROOT_URL, filterQuery(getFilter(filter)), nil, response); err != nil {
+		return nil, 0, "", err
+	}
+	return response.ExternalIDs, response.Total, response.NextToken, nil
+}
+
+func (c *Client) UpdateUser(requestInfo api.RequestInfo, externalId string, newPath string) (*api.User, error) {
+	request := &types.UpdateUserRequest{
+		Path: newPath,
+	}
+	user := &api.User{}
+	if err := c.do(requestInfo, http.MethodPut, route(types.USER_ID_URL, types.USER_ID, externalId), nil, request, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (c *Client) RemoveUser(requestInfo api.RequestInfo, externalId string) error {
+	return c.do(requestInfo, http.MethodDelete, route(types.USER_ID_URL, types.USER_ID, externalId), nil, nil, nil)
+}
+
+func (c *Client) RestoreUser(requestInfo api.RequestInfo, externalId string) (*api.User, error) {
+	user := &api.User{}
+	if err := c.do(requestInfo, http.MethodPost, route(types.USER_ID_RESTORE_URL, types.USER_ID, externalId), nil, nil, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (c *Client) ListGroupsByUser(requestInfo api.RequestInfo, filter *api.Filter) ([]api.UserGroups, int, error) {
+	filter = getFilter(filter)
+	response := &types.GetGroupsByUserIdResponse{}
+	if err := c.do(requestInfo, http.MethodGet, route(types.USER_ID_GROUPS_URL, types.USER_ID, filter.ExternalID),
+		filterQuery(filter), nil, response); err != nil {
+		return nil, 0, err
+	}
+	return response.Groups, response.Total, nil
+}
+
+func (c *Client) AttachPolicyToUser(requestInfo api.RequestInfo, externalId string, org string, policyName string) error {
+	return c.do(requestInfo, http.MethodPost, route(types.USER_ID_POLICIES_ID_URL, types.USER_ID, externalId,
+		types.ORG_NAME, org, types.POLICY_NAME, policyName), nil, nil, nil)
+}
+
+func (c *Client) DetachPolicyFromUser(requestInfo api.RequestInfo, externalId string, org string, policyName string) error {
+	return c.do(requestInfo, http.MethodDelete, route(types.USER_ID_POLICIES_ID_URL, types.USER_ID, externalId,
+		types.ORG_NAME, org, types.POLICY_NAME, policyName), nil, nil, nil)
+}
+
+func (c *Client) ListAttachedUserPolicies(requestInfo api.RequestInfo, filter *api.Filter) ([]api.UserPolicies, int, error) {
+	filter = getFilter(filter)
+	response := &types.ListAttachedUserPoliciesResponse{}
+	if err := c.do(requestInfo, http.MethodGet, route(types.USER_ID_POLICIES_URL, types.USER_ID, filter.ExternalID),
+		filterQuery(filter), nil, response); err != nil {
+		return nil, 0, err
+	}
+	return response.AttachedPolicies, response.Total, nil
+}
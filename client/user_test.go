@@ -0,0 +1,184 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
+)
+
+func TestClient_UserAPI(t *testing.T) {
+	now := time.Date(2016, time.July, 1, 10, 0, 0, 0, time.UTC)
+	user := &api.User{
+		ID:         "UserID",
+		ExternalID: "user 1",
+		Path:       "/path/",
+		Urn:        api.CreateUrn("", api.RESOURCE_USER, "/path/", "user 1"),
+		CreateAt:   now,
+		UpdateAt:   now,
+		Revision:   2,
+	}
+
+	runClientTestCases(t, map[string]clientTestCase{
+		"OkCaseAddUser": {
+			call: func(c *Client) (interface{}, error) {
+				return c.AddUser(api.RequestInfo{}, "user 1", "/path/")
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/users",
+				body:     `{"externalId": "user 1", "path": "/path/"}`,
+				status:   http.StatusCreated,
+				response: user,
+			},
+			expectedResult: user,
+		},
+		"OkCaseGetUserByExternalID": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetUserByExternalID(api.RequestInfo{}, "user 1")
+			},
+			exchange: testExchange{
+				method:   http.MethodGet,
+				url:      "/api/v1/users/user%201",
+				status:   http.StatusOK,
+				response: user,
+			},
+			expectedResult: user,
+		},
+		"ErrorCaseGetUserByExternalID": {
+			call: func(c *Client) (interface{}, error) {
+				return c.GetUserByExternalID(api.RequestInfo{}, "user 1")
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/users/user%201",
+				status: http.StatusNotFound,
+				response: &api.Error{
+					Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+					Message: "User with externalId user 1 not found",
+				},
+			},
+			expectedResult: (*api.User)(nil),
+			wantError: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User with externalId user 1 not found",
+			},
+		},
+		"OkCaseListUsers": {
+			call: func(c *Client) (interface{}, error) {
+				externalIDs, total, nextToken, err := c.ListUsers(api.RequestInfo{}, &api.Filter{
+					PathPrefix: "/path/",
+					Limit:      2,
+				})
+				return listResult{externalIDs, total, nextToken}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/users?Limit=2&PathPrefix=%2Fpath%2F",
+				status: http.StatusOK,
+				response: &types.GetUserExternalIDsResponse{
+					ExternalIDs: []string{"user1", "user2"},
+					Limit:       2,
+					Total:       5,
+					NextToken:   "token",
+				},
+			},
+			expectedResult: listResult{[]string{"user1", "user2"}, 5, "token"},
+		},
+		"OkCaseUpdateUser": {
+			call: func(c *Client) (interface{}, error) {
+				return c.UpdateUser(api.RequestInfo{Revision: 1}, "user 1", "/path/")
+			},
+			exchange: testExchange{
+				method:   http.MethodPut,
+				url:      "/api/v1/users/user%201",
+				body:     `{"path": "/path/"}`,
+				status:   http.StatusOK,
+				response: user,
+			},
+			expectedResult: user,
+		},
+		"OkCaseRemoveUser": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.RemoveUser(api.RequestInfo{}, "user 1")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/users/user%201",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseRestoreUser": {
+			call: func(c *Client) (interface{}, error) {
+				return c.RestoreUser(api.RequestInfo{}, "user 1")
+			},
+			exchange: testExchange{
+				method:   http.MethodPost,
+				url:      "/api/v1/users/user%201/restore",
+				status:   http.StatusOK,
+				response: user,
+			},
+			expectedResult: user,
+		},
+		"OkCaseListGroupsByUser": {
+			call: func(c *Client) (interface{}, error) {
+				groups, total, err := c.ListGroupsByUser(api.RequestInfo{}, &api.Filter{
+					ExternalID: "user 1",
+					Offset:     1,
+				})
+				return listResult{groups, total, ""}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/users/user%201/groups?Offset=1",
+				status: http.StatusOK,
+				response: &types.GetGroupsByUserIdResponse{
+					Groups: []api.UserGroups{{Org: "org1", Name: "group1", CreateAt: now}},
+					Offset: 1,
+					Total:  2,
+				},
+			},
+			expectedResult: listResult{[]api.UserGroups{{Org: "org1", Name: "group1", CreateAt: now}}, 2, ""},
+		},
+		"OkCaseAttachPolicyToUser": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.AttachPolicyToUser(api.RequestInfo{}, "user 1", "org1", "policy1")
+			},
+			exchange: testExchange{
+				method: http.MethodPost,
+				url:    "/api/v1/users/user%201/policies/org1/policy1",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseDetachPolicyFromUser": {
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.DetachPolicyFromUser(api.RequestInfo{}, "user 1", "org1", "policy1")
+			},
+			exchange: testExchange{
+				method: http.MethodDelete,
+				url:    "/api/v1/users/user%201/policies/org1/policy1",
+				status: http.StatusNoContent,
+			},
+		},
+		"OkCaseListAttachedUserPolicies": {
+			call: func(c *Client) (interface{}, error) {
+				policies, total, err := c.ListAttachedUserPolicies(api.RequestInfo{}, &api.Filter{
+					ExternalID: "user 1",
+				})
+				return listResult{policies, total, ""}, err
+			},
+			exchange: testExchange{
+				method: http.MethodGet,
+				url:    "/api/v1/users/user%201/policies",
+				status: http.StatusOK,
+				response: &types.ListAttachedUserPoliciesResponse{
+					AttachedPolicies: []api.UserPolicies{{Org: "org1", Policy: "policy1", CreateAt: now}},
+					Total:            1,
+				},
+			},
+			expectedResult: listResult{[]api.UserPolicies{{Org: "org1", Policy: "policy1", CreateAt: now}}, 1, ""},
+		},
+	})
+}
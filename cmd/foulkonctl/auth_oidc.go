@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+
+	internalhttp "github.com/Tecsisa/foulkon/http"
+)
+
+var oidcProviderColumns = []string{"name", "path", "issuerUrl", "urn", "createAt", "updateAt"}
+
+func listOidcProviders(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	result, err := c.list(internalhttp.OIDC_AUTH_ROOT_URL, nil, "providers")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "providers", "name")
+}
+
+func getOidcProvider(c *ctl, args []string) error {
+	fs := c.flagSet()
+	name := fs.String("name", "", "OIDC provider name")
+	if err := c.parse(fs, args, "name"); err != nil {
+		return err
+	}
+	result, err := c.do(http.MethodGet, oidcProviderRoute(*name), nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.print(result, oidcProviderColumns...)
+}
+
+func createOidcProvider(c *ctl, args []string) error {
+	fs := c.flagSet()
+	name := fs.String("name", "", "OIDC provider name")
+	path := fs.String("path", "", "OIDC provider location")
+	issuerURL := fs.String("issuer-url", "", "OIDC issuer URL")
+	clients := fs.String("clients", "", "Comma separated list of OIDC client identifiers")
+	if err := c.parse(fs, args, "name", "path", "issuer-url", "clients"); err != nil {
+		return err
+	}
+	request := &internalhttp.CreateOidcProviderRequest{
+		Name:        *name,
+		Path:        *path,
+		IssuerURL:   *issuerURL,
+		OidcClients: splitList(*clients),
+	}
+	result, err := c.do(http.MethodPost, internalhttp.OIDC_AUTH_ROOT_URL, nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, oidcProviderColumns...)
+}
+
+func updateOidcProvider(c *ctl, args []string) error {
+	fs := c.flagSet()
+	name := fs.String("name", "", "OIDC provider name")
+	newName := fs.String("new-name", "", "New OIDC provider name, same name if empty")
+	path := fs.String("path", "", "New OIDC provider location")
+	issuerURL := fs.String("issuer-url", "", "New OIDC issuer URL")
+	clients := fs.String("clients", "", "Comma separated list of OIDC client identifiers")
+	if err := c.parse(fs, args, "name", "path", "issuer-url", "clients"); err != nil {
+		return err
+	}
+	request := &internalhttp.UpdateOidcProviderRequest{
+		Name:        *name,
+		Path:        *path,
+		IssuerURL:   *issuerURL,
+		OidcClients: splitList(*clients),
+	}
+	if *newName != "" {
+		request.Name = *newName
+	}
+	result, err := c.do(http.MethodPut, oidcProviderRoute(*name), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, oidcProviderColumns...)
+}
+
+func deleteOidcProvider(c *ctl, args []string) error {
+	fs := c.flagSet()
+	name := fs.String("name", "", "OIDC provider name")
+	if err := c.parse(fs, args, "name"); err != nil {
+		return err
+	}
+	_, err := c.do(http.MethodDelete, oidcProviderRoute(*name), nil, nil)
+	return err
+}
+
+func oidcProviderRoute(name string) string {
+	return route(internalhttp.OIDC_AUTH_ID_URL, internalhttp.AUTH_PROVIDER_NAME, name)
+}
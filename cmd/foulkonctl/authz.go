@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+
+	internalhttp "github.com/Tecsisa/foulkon/http"
+)
+
+func authorize(c *ctl, args []string) error {
+	fs := c.flagSet()
+	action := fs.String("action", "", "Action to authorize")
+	resources := fs.String("resources", "", "Comma separated list of resource URNs")
+	if err := c.parse(fs, args, "action", "resources"); err != nil {
+		return err
+	}
+	request := &internalhttp.AuthorizeResourcesRequest{
+		Action:    *action,
+		Resources: splitList(*resources),
+	}
+	result, err := c.do(http.MethodPost, internalhttp.RESOURCE_URL, nil, request)
+	if err != nil {
+		return err
+	}
+	if c.output != OUTPUT_TABLE {
+		return c.print(result)
+	}
+	response, _ := result.(map[string]interface{})
+	return c.printList(response, "resourcesAllowed", "resourcesAllowed")
+}
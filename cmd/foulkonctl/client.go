@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/api"
+)
+
+const (
+	// Page size used to retrieve all results with -all flag
+	ALL_PAGE_SIZE = api.MAX_LIMIT_SIZE
+)
+
+// errUsage is returned when flags are wrong, usage is already printed
+var errUsage = errors.New("usage")
+
+// ctl holds the state of the action being executed
+type ctl struct {
+	command string
+	action  string
+	out     io.Writer
+	errOut  io.Writer
+
+	// Common flags
+	configFile  string
+	profileName string
+	url         string
+	output      string
+
+	// List flags
+	offset     int
+	limit      int
+	all        bool
+	pathPrefix string
+	orderBy    string
+
+	profile *Profile
+	client  *http.Client
+}
+
+// flagSet returns a flag set with the flags shared by all actions
+func (c *ctl) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(strings.TrimSpace("foulkonctl "+c.command+" "+c.action), flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	fs.StringVar(&c.configFile, "config", "", "Config file with profiles (default $HOME/"+DEFAULT_CONFIG_FILE+")")
+	fs.StringVar(&c.profileName, "profile", "", "Profile to use (default "+DEFAULT_PROFILE+")")
+	fs.StringVar(&c.url, "url", "", "Worker URL, overrides profile URL")
+	fs.StringVar(&c.output, "output", OUTPUT_TABLE, "Output format: table, json or yaml")
+	return fs
+}
+
+// listFlagSet returns a flag set with the flags shared by all list actions
+func (c *ctl) listFlagSet() *flag.FlagSet {
+	fs := c.flagSet()
+	fs.IntVar(&c.offset, "offset", 0, "Offset of the first element")
+	fs.IntVar(&c.limit, "limit", 0, "Maximum number of elements")
+	fs.BoolVar(&c.all, "all", false, "Retrieve all elements following pagination")
+	fs.StringVar(&c.pathPrefix, "path-prefix", "", "Filter by path prefix")
+	fs.StringVar(&c.orderBy, "order-by", "", "Order elements by field")
+	return fs
+}
+
+// parse parses args and checks that required flags have a value
+func (c *ctl) parse(fs *flag.FlagSet, args []string, required ...string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(c.errOut, "Unexpected arguments: %v\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return errUsage
+	}
+	for _, name := range required {
+		if fs.Lookup(name).Value.String() == "" {
+			fmt.Fprintf(c.errOut, "Flag -%v is required\n", name)
+			fs.Usage()
+			return errUsage
+		}
+	}
+	switch c.output {
+	case OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_YAML:
+	default:
+		fmt.Fprintf(c.errOut, "Invalid output format %v\n", c.output)
+		fs.Usage()
+		return errUsage
+	}
+
+	profile, err := loadProfile(c.configFile, c.profileName)
+	if err != nil {
+		return err
+	}
+	if c.url != "" {
+		profile.URL = c.url
+	}
+	c.profile = profile
+	if c.client == nil {
+		c.client = http.DefaultClient
+	}
+	return nil
+}
+
+// do sends a request to the worker and decodes the JSON response
+func (c *ctl) do(method string, path string, query url.Values, body interface{}) (interface{}, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewBuffer(b)
+	}
+
+	u := strings.TrimSuffix(c.profile.URL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.profile.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.profile.Token)
+	} else if c.profile.Username != "" {
+		req.SetBasicAuth(c.profile.Username, c.profile.Password)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		apiError := &api.Error{}
+		if err := json.Unmarshal(b, apiError); err != nil || apiError.Code == "" {
+			return nil, fmt.Errorf("%v %v", res.StatusCode, http.StatusText(res.StatusCode))
+		}
+		return nil, apiError
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, nil
+	}
+	var result interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// list retrieves a list of elements stored in key field of the response.
+// With -all flag it requests pages until total elements are retrieved.
+func (c *ctl) list(path string, query url.Values, key string) (map[string]interface{}, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.pathPrefix != "" {
+		query.Set("PathPrefix", c.pathPrefix)
+	}
+	if c.orderBy != "" {
+		query.Set("OrderBy", c.orderBy)
+	}
+
+	limit := c.limit
+	if c.all && limit == 0 {
+		limit = ALL_PAGE_SIZE
+	}
+	offset := c.offset
+	items := []interface{}{}
+	var page map[string]interface{}
+	for {
+		if offset > 0 {
+			query.Set("Offset", strconv.Itoa(offset))
+		}
+		if limit > 0 {
+			query.Set("Limit", strconv.Itoa(limit))
+		}
+		result, err := c.do(http.MethodGet, path, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		page, _ = result.(map[string]interface{})
+		if page == nil {
+			page = map[string]interface{}{}
+		}
+		pageItems, _ := page[key].([]interface{})
+		items = append(items, pageItems...)
+
+		if !c.all || len(pageItems) == 0 {
+			break
+		}
+		offset += len(pageItems)
+		total, err := strconv.Atoi(fmt.Sprint(page["total"]))
+		if err != nil || offset >= total {
+			break
+		}
+	}
+
+	page[key] = items
+	if c.all {
+		page["offset"] = json.Number(strconv.Itoa(c.offset))
+		page["limit"] = json.Number(strconv.Itoa(len(items)))
+	}
+	return page, nil
+}
+
+// route replaces path params in pattern with their values. Params are pairs of name and value.
+func route(pattern string, params ...string) string {
+	for i := 0; i+1 < len(params); i += 2 {
+		pattern = strings.Replace(pattern, ":"+params[i], escapePathParam(params[i+1]), 1)
+	}
+	return pattern
+}
+
+func escapePathParam(value string) string {
+	return strings.Replace(url.QueryEscape(value), "+", "%20", -1)
+}
+
+// splitList splits a comma separated flag value
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
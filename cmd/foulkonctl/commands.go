@@ -0,0 +1,92 @@
+package main
+
+// action is a subcommand of a command
+type action struct {
+	description string
+	run         func(c *ctl, args []string) error
+}
+
+var commandDescriptions = map[string]string{
+	"users":           "Manage users",
+	"organizations":   "Manage organizations",
+	"groups":          "Manage groups and subgroups",
+	"members":         "Manage group members",
+	"policies":        "Manage policies and policy versions",
+	"attachments":     "Manage policies attached to users and groups",
+	"proxy-resources": "Manage proxy resources",
+	"oidc-providers":  "Manage OIDC providers (admin only)",
+	"reconcile":       "Reconcile organizations with a desired state file",
+	"authorize":       "Get resources allowed for an action",
+}
+
+// commands mirrors worker routes, grouped by resource
+var commands = map[string]map[string]action{
+	"users": {
+		"list":   {"List users", listUsers},
+		"get":    {"Get a user", getUser},
+		"create": {"Create a user", createUser},
+		"update": {"Update a user", updateUser},
+		"delete": {"Delete a user", deleteUser},
+		"groups": {"List groups of a user", listUserGroups},
+	},
+	"organizations": {
+		"list":   {"List organizations", listOrganizations},
+		"get":    {"Get an organization", getOrganization},
+		"create": {"Create an organization", createOrganization},
+		"update": {"Update an organization", updateOrganization},
+		"delete": {"Delete an organization", deleteOrganization},
+	},
+	"groups": {
+		"list":            {"List groups", listGroups},
+		"get":             {"Get a group", getGroup},
+		"create":          {"Create a group", createGroup},
+		"update":          {"Update a group", updateGroup},
+		"delete":          {"Delete a group", deleteGroup},
+		"subgroups":       {"List subgroups of a group", listSubgroups},
+		"add-subgroup":    {"Add a subgroup to a group", addSubgroup},
+		"remove-subgroup": {"Remove a subgroup from a group", removeSubgroup},
+	},
+	"members": {
+		"list":   {"List members of a group", listMembers},
+		"add":    {"Add a user to a group", addMember},
+		"remove": {"Remove a user from a group", removeMember},
+	},
+	"policies": {
+		"list":        {"List policies", listPolicies},
+		"get":         {"Get a policy", getPolicy},
+		"create":      {"Create a policy", createPolicy},
+		"update":      {"Update a policy", updatePolicy},
+		"delete":      {"Delete a policy", deletePolicy},
+		"groups":      {"List groups a policy is attached to", listAttachedGroups},
+		"versions":    {"List versions of a policy", listPolicyVersions},
+		"version":     {"Get a version of a policy", getPolicyVersion},
+		"diff":        {"Show differences between two versions of a policy", diffPolicyVersions},
+		"set-default": {"Set the default version of a policy", setDefaultPolicyVersion},
+	},
+	"attachments": {
+		"list":   {"List policies attached to a user or group", listAttachments},
+		"attach": {"Attach a policy to a user or group", attachPolicy},
+		"detach": {"Detach a policy from a user or group", detachPolicy},
+	},
+	"proxy-resources": {
+		"list":   {"List proxy resources", listProxyResources},
+		"get":    {"Get a proxy resource", getProxyResource},
+		"create": {"Create a proxy resource", createProxyResource},
+		"update": {"Update a proxy resource", updateProxyResource},
+		"delete": {"Delete a proxy resource", deleteProxyResource},
+	},
+	"oidc-providers": {
+		"list":   {"List OIDC providers", listOidcProviders},
+		"get":    {"Get an OIDC provider", getOidcProvider},
+		"create": {"Create an OIDC provider", createOidcProvider},
+		"update": {"Update an OIDC provider", updateOidcProvider},
+		"delete": {"Delete an OIDC provider", deleteOidcProvider},
+	},
+	"reconcile": {
+		"plan":  {"Show changes needed to reach the desired state", planReconcile},
+		"apply": {"Apply changes needed to reach the desired state", applyReconcile},
+	},
+	"authorize": {
+		"": {"Get resources allowed for an action", authorize},
+	},
+}
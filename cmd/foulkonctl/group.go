@@ -0,0 +1,186 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+
+	internalhttp "github.com/Tecsisa/foulkon/http"
+)
+
+var groupColumns = []string{"org", "name", "path", "urn", "createAt", "updateAt"}
+
+func listGroups(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	org := fs.String("org", "", "Organization name, all organizations if empty")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *org == "" {
+		result, err := c.list(internalhttp.API_VERSION_1+"/groups", nil, "groups")
+		if err != nil {
+			return err
+		}
+		return c.printList(result, "groups", "org", "name")
+	}
+	result, err := c.list(route(internalhttp.GROUP_ORG_ROOT_URL, internalhttp.ORG_NAME, *org), nil, "groups")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "groups", "name")
+}
+
+func getGroup(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Group name")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	result, err := c.do(http.MethodGet, groupRoute(internalhttp.GROUP_ID_URL, *org, *name), nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.print(result, groupColumns...)
+}
+
+func createGroup(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Group name")
+	path := fs.String("path", "", "Group location")
+	if err := c.parse(fs, args, "org", "name", "path"); err != nil {
+		return err
+	}
+	request := &internalhttp.CreateGroupRequest{
+		Name: *name,
+		Path: *path,
+	}
+	result, err := c.do(http.MethodPost, route(internalhttp.GROUP_ORG_ROOT_URL, internalhttp.ORG_NAME, *org), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, groupColumns...)
+}
+
+func updateGroup(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Group name")
+	newName := fs.String("new-name", "", "New group name, same name if empty")
+	path := fs.String("path", "", "New group location")
+	if err := c.parse(fs, args, "org", "name", "path"); err != nil {
+		return err
+	}
+	request := &internalhttp.UpdateGroupRequest{
+		Name: *name,
+		Path: *path,
+	}
+	if *newName != "" {
+		request.Name = *newName
+	}
+	result, err := c.do(http.MethodPut, groupRoute(internalhttp.GROUP_ID_URL, *org, *name), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, groupColumns...)
+}
+
+func deleteGroup(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Group name")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	_, err := c.do(http.MethodDelete, groupRoute(internalhttp.GROUP_ID_URL, *org, *name), nil, nil)
+	return err
+}
+
+func listSubgroups(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Group name")
+	transitive := fs.Bool("transitive", false, "Include subgroups of subgroups")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if *transitive {
+		query.Set("Transitive", "true")
+	}
+	result, err := c.list(groupRoute(internalhttp.GROUP_ID_GROUPS_URL, *org, *name), query, "groups")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "groups", "group", "joined")
+}
+
+func addSubgroup(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Group name")
+	subgroup := fs.String("subgroup", "", "Subgroup name")
+	if err := c.parse(fs, args, "org", "name", "subgroup"); err != nil {
+		return err
+	}
+	path := route(groupRoute(internalhttp.GROUP_ID_GROUPS_ID_URL, *org, *name), internalhttp.SUBGROUP_NAME, *subgroup)
+	_, err := c.do(http.MethodPost, path, nil, nil)
+	return err
+}
+
+func removeSubgroup(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Group name")
+	subgroup := fs.String("subgroup", "", "Subgroup name")
+	if err := c.parse(fs, args, "org", "name", "subgroup"); err != nil {
+		return err
+	}
+	path := route(groupRoute(internalhttp.GROUP_ID_GROUPS_ID_URL, *org, *name), internalhttp.SUBGROUP_NAME, *subgroup)
+	_, err := c.do(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+func listMembers(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	org := fs.String("org", "", "Organization name")
+	group := fs.String("group", "", "Group name")
+	if err := c.parse(fs, args, "org", "group"); err != nil {
+		return err
+	}
+	result, err := c.list(groupRoute(internalhttp.GROUP_ID_USERS_URL, *org, *group), nil, "members")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "members", "user", "joined")
+}
+
+func addMember(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	group := fs.String("group", "", "Group name")
+	user := fs.String("user", "", "User external identifier")
+	if err := c.parse(fs, args, "org", "group", "user"); err != nil {
+		return err
+	}
+	path := route(groupRoute(internalhttp.GROUP_ID_USERS_ID_URL, *org, *group), internalhttp.USER_ID, *user)
+	_, err := c.do(http.MethodPost, path, nil, nil)
+	return err
+}
+
+func removeMember(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	group := fs.String("group", "", "Group name")
+	user := fs.String("user", "", "User external identifier")
+	if err := c.parse(fs, args, "org", "group", "user"); err != nil {
+		return err
+	}
+	path := route(groupRoute(internalhttp.GROUP_ID_USERS_ID_URL, *org, *group), internalhttp.USER_ID, *user)
+	_, err := c.do(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+func groupRoute(pattern string, org string, name string) string {
+	return route(pattern, internalhttp.ORG_NAME, org, internalhttp.GROUP_NAME, name)
+}
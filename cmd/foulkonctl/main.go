@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr)
+		return 2
+	}
+
+	// authorize has no actions
+	if args[0] == "authorize" {
+		args = append([]string{args[0], ""}, args[1:]...)
+	}
+
+	actions, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown command %v\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+	if len(args) < 2 {
+		printActions(stderr, args[0], actions)
+		return 2
+	}
+	act, ok := actions[args[1]]
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown action %v for command %v\n\n", args[1], args[0])
+		printActions(stderr, args[0], actions)
+		return 2
+	}
+
+	c := &ctl{
+		command: args[0],
+		action:  args[1],
+		out:     stdout,
+		errOut:  stderr,
+	}
+	if err := act.run(c, args[2:]); err != nil {
+		if err != errUsage {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+		}
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: foulkonctl <command> <action> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, name := range sortedCommands() {
+		fmt.Fprintf(tw, "  %v\t%v\n", name, commandDescriptions[name])
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'foulkonctl <command>' to list its actions and 'foulkonctl <command> <action> -h' to list its flags.")
+}
+
+func printActions(w io.Writer, command string, actions map[string]action) {
+	fmt.Fprintf(w, "Usage: foulkonctl %v <action> [flags]\n\n", command)
+	fmt.Fprintln(w, "Actions:")
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %v\t%v\n", name, actions[name].description)
+	}
+	tw.Flush()
+}
+
+func sortedCommands() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	internalhttp "github.com/Tecsisa/foulkon/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	users := []string{"user1", "user2", "user3", "user4", "user5"}
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == internalhttp.USER_ROOT_URL:
+			offset, _ := strconv.Atoi(r.URL.Query().Get("Offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("Limit"))
+			end := len(users)
+			if limit > 0 && offset+limit < end {
+				end = offset + limit
+			}
+			json.NewEncoder(w).Encode(internalhttp.GetUserExternalIDsResponse{
+				ExternalIDs: users[offset:end],
+				Offset:      offset,
+				Limit:       limit,
+				Total:       len(users),
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/organizations/org1/groups/group 1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"org":     "org1",
+				"name":    "group 1",
+				"path":    "/path/",
+				"urn":     api.CreateUrn("org1", api.RESOURCE_GROUP, "/path/", "group 1"),
+				"version": 2,
+				"default": true,
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/organizations/org1/groups/group1/users/user1":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == internalhttp.RECONCILE_PLAN_URL:
+			request := &api.State{}
+			json.NewDecoder(r.Body).Decode(request)
+			json.NewEncoder(w).Encode(api.ReconcileResult{
+				Orgs:            []string{request.Organizations[0].Name},
+				IgnoreUnmanaged: r.URL.Query().Get("IgnoreUnmanaged") == "true",
+				Changes: []api.StateChange{
+					{
+						Operation: api.STATE_OPERATION_CREATE,
+						Resource:  api.CreateUrn("org1", api.RESOURCE_GROUP, "/path/", request.Groups[0].Name),
+					},
+					{
+						Operation: api.STATE_OPERATION_UPDATE,
+						Resource:  api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "policy1"),
+						Details:   []string{"path: /old/ -> /path/"},
+					},
+					{
+						Operation: api.STATE_OPERATION_REMOVE_MEMBER,
+						Resource:  api.CreateUrn("org1", api.RESOURCE_GROUP, "/path/", "group2"),
+						Target:    "user2",
+					},
+				},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == internalhttp.RESOURCE_URL:
+			request := &internalhttp.AuthorizeResourcesRequest{}
+			json.NewDecoder(r.Body).Decode(request)
+			json.NewEncoder(w).Encode(internalhttp.AuthorizeResourcesResponse{
+				ResourcesAllowed: request.Resources[:1],
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not found",
+			})
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "foulkonctl")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "config.toml")
+	config := fmt.Sprintf(`
+[profiles.default]
+url = "%v"
+username = "admin"
+password = "${FOULKONCTL_TEST_PASSWORD}"
+
+[profiles.user]
+url = "%v"
+token = "token"
+`, server.URL, server.URL)
+	assert.Nil(t, ioutil.WriteFile(configFile, []byte(config), 0600))
+	stateFile := filepath.Join(dir, "state.yaml")
+	state := `
+version: v1
+organizations:
+- name: org1
+  path: /path/
+groups:
+- org: org1
+  name: group1
+  path: /path/
+`
+	assert.Nil(t, ioutil.WriteFile(stateFile, []byte(state), 0600))
+	os.Setenv("FOULKONCTL_TEST_PASSWORD", "secret")
+	defer os.Unsetenv("FOULKONCTL_TEST_PASSWORD")
+
+	testcases := map[string]struct {
+		args []string
+		// Expected result
+		expectedCode     int
+		expectedOut      string
+		expectedErr      string
+		expectedRequests []string
+		expectedAuth     string
+	}{
+		"OkCaseListTable": {
+			args:             []string{"users", "list", "-limit", "2"},
+			expectedOut:      "EXTERNALID\nuser1\nuser2\n",
+			expectedRequests: []string{"GET /api/v1/users?Limit=2"},
+			expectedAuth:     "Basic YWRtaW46c2VjcmV0",
+		},
+		"OkCaseListAll": {
+			args:        []string{"users", "list", "-all", "-limit", "2", "-offset", "1", "-output", "json"},
+			expectedOut: "{\n  \"limit\": 4,\n  \"offset\": 1,\n  \"total\": 5,\n  \"users\": [\n    \"user2\",\n    \"user3\",\n    \"user4\",\n    \"user5\"\n  ]\n}\n",
+			expectedRequests: []string{
+				"GET /api/v1/users?Limit=2&Offset=1",
+				"GET /api/v1/users?Limit=2&Offset=3",
+			},
+			expectedAuth: "Basic YWRtaW46c2VjcmV0",
+		},
+		"OkCaseGetYAML": {
+			args:             []string{"groups", "get", "-org", "org1", "-name", "group 1", "-output", "yaml", "-profile", "user"},
+			expectedOut:      "default: true\nname: group 1\norg: org1\npath: /path/\nurn: urn:iws:iam:org1:group/path/group 1\nversion: 2\n",
+			expectedRequests: []string{"GET /api/v1/organizations/org1/groups/group%201"},
+			expectedAuth:     "Bearer token",
+		},
+		"OkCaseAddMember": {
+			args:             []string{"members", "add", "-org", "org1", "-group", "group1", "-user", "user1"},
+			expectedRequests: []string{"POST /api/v1/organizations/org1/groups/group1/users/user1"},
+			expectedAuth:     "Basic YWRtaW46c2VjcmV0",
+		},
+		"OkCaseAuthorize": {
+			args:             []string{"authorize", "-action", "example:get", "-resources", "urn:ews:example:res1, urn:ews:example:res2"},
+			expectedOut:      "RESOURCESALLOWED\nurn:ews:example:res1\n",
+			expectedRequests: []string{"POST /api/v1/resource"},
+			expectedAuth:     "Basic YWRtaW46c2VjcmV0",
+		},
+		"OkCaseReconcilePlan": {
+			args: []string{"reconcile", "plan", "-file", stateFile, "-ignore-unmanaged"},
+			expectedOut: "+ create urn:iws:iam:org1:group/path/group1\n" +
+				"~ update urn:iws:iam:org1:policy/path/policy1\n" +
+				"    path: /old/ -> /path/\n" +
+				"- removeMember urn:iws:iam:org1:group/path/group2 user2\n" +
+				"Plan: 1 to add, 1 to change, 1 to remove\n",
+			expectedRequests: []string{"POST /api/v1/reconcile/plan?IgnoreUnmanaged=true"},
+			expectedAuth:     "Basic YWRtaW46c2VjcmV0",
+		},
+		"ErrorCaseApiError": {
+			args:             []string{"users", "get", "-id", "user6"},
+			expectedCode:     1,
+			expectedErr:      "Error: Code: UserWithExternalIDNotFound, Message: User not found\n",
+			expectedRequests: []string{"GET /api/v1/users/user6"},
+			expectedAuth:     "Basic YWRtaW46c2VjcmV0",
+		},
+		"ErrorCaseRequiredFlag": {
+			args:         []string{"users", "get"},
+			expectedCode: 1,
+		},
+		"ErrorCaseUnknownProfile": {
+			args:         []string{"users", "list", "-profile", "unknown"},
+			expectedCode: 1,
+			expectedErr:  fmt.Sprintf("Error: profile unknown not found in configuration file %v\n", configFile),
+		},
+		"ErrorCaseUnknownCommand": {
+			args:         []string{"roles", "list"},
+			expectedCode: 2,
+		},
+	}
+
+	for n, test := range testcases {
+		requests = nil
+		stdout := new(bytes.Buffer)
+		stderr := new(bytes.Buffer)
+		code := run(append(test.args, "-config", configFile), stdout, stderr)
+
+		assert.Equal(t, test.expectedCode, code, "Error in test case %v: %v", n, stderr.String())
+		assert.Equal(t, test.expectedOut, stdout.String(), "Error in test case %v", n)
+		if test.expectedErr != "" {
+			assert.Equal(t, test.expectedErr, stderr.String(), "Error in test case %v", n)
+		}
+		received := []string{}
+		for _, r := range requests {
+			received = append(received, r.Method+" "+r.URL.RequestURI())
+			assert.Equal(t, test.expectedAuth, r.Header.Get("Authorization"), "Error in test case %v", n)
+		}
+		if test.expectedRequests == nil {
+			test.expectedRequests = []string{}
+		}
+		assert.Equal(t, test.expectedRequests, received, "Error in test case %v", n)
+	}
+}
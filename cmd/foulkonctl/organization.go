@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+
+	internalhttp "github.com/Tecsisa/foulkon/http"
+)
+
+var organizationColumns = []string{"name", "path", "urn", "createAt", "updateAt"}
+
+func listOrganizations(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	result, err := c.list(internalhttp.ORGANIZATION_ROOT_URL, nil, "organizations")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "organizations", "name")
+}
+
+func getOrganization(c *ctl, args []string) error {
+	fs := c.flagSet()
+	name := fs.String("name", "", "Organization name")
+	if err := c.parse(fs, args, "name"); err != nil {
+		return err
+	}
+	result, err := c.do(http.MethodGet, route(internalhttp.ORGANIZATION_ID_URL, internalhttp.ORG_NAME, *name), nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.print(result, organizationColumns...)
+}
+
+func createOrganization(c *ctl, args []string) error {
+	fs := c.flagSet()
+	name := fs.String("name", "", "Organization name")
+	path := fs.String("path", "", "Organization location")
+	if err := c.parse(fs, args, "name", "path"); err != nil {
+		return err
+	}
+	request := &internalhttp.CreateOrganizationRequest{
+		Name: *name,
+		Path: *path,
+	}
+	result, err := c.do(http.MethodPost, internalhttp.ORGANIZATION_ROOT_URL, nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, organizationColumns...)
+}
+
+func updateOrganization(c *ctl, args []string) error {
+	fs := c.flagSet()
+	name := fs.String("name", "", "Organization name")
+	path := fs.String("path", "", "New organization location")
+	if err := c.parse(fs, args, "name", "path"); err != nil {
+		return err
+	}
+	request := &internalhttp.UpdateOrganizationRequest{
+		Path: *path,
+	}
+	result, err := c.do(http.MethodPut, route(internalhttp.ORGANIZATION_ID_URL, internalhttp.ORG_NAME, *name), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, organizationColumns...)
+}
+
+func deleteOrganization(c *ctl, args []string) error {
+	fs := c.flagSet()
+	name := fs.String("name", "", "Organization name")
+	if err := c.parse(fs, args, "name"); err != nil {
+		return err
+	}
+	_, err := c.do(http.MethodDelete, route(internalhttp.ORGANIZATION_ID_URL, internalhttp.ORG_NAME, *name), nil, nil)
+	return err
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// Output formats
+	OUTPUT_TABLE = "table"
+	OUTPUT_JSON  = "json"
+	OUTPUT_YAML  = "yaml"
+)
+
+// print writes a single element. In table format only columns are printed, in a single row.
+func (c *ctl) print(result interface{}, columns ...string) error {
+	if c.output != OUTPUT_TABLE {
+		return c.encode(result)
+	}
+	if result == nil {
+		return nil
+	}
+	return c.printTable([]interface{}{result}, columns)
+}
+
+// printList writes a list of elements stored in key field of result, one row per element in table format
+func (c *ctl) printList(result map[string]interface{}, key string, columns ...string) error {
+	if c.output != OUTPUT_TABLE {
+		return c.encode(result)
+	}
+	items, _ := result[key].([]interface{})
+	return c.printTable(items, columns)
+}
+
+func (c *ctl) encode(result interface{}) error {
+	switch c.output {
+	case OUTPUT_YAML:
+		b, err := yaml.Marshal(yamlValue(result))
+		if err != nil {
+			return err
+		}
+		_, err = c.out.Write(b)
+		return err
+	default:
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.out, string(b))
+		return err
+	}
+}
+
+// printTable writes items as a table. Items can be objects, printed using columns as field names,
+// or plain values, printed in the first column.
+func (c *ctl) printTable(items []interface{}, columns []string) error {
+	tw := tabwriter.NewWriter(c.out, 0, 8, 3, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, item := range items {
+		row := make([]string, len(columns))
+		if object, ok := item.(map[string]interface{}); ok {
+			for i, column := range columns {
+				row[i] = formatValue(object[column])
+			}
+		} else if len(row) > 0 {
+			row[0] = formatValue(item)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = formatValue(item)
+		}
+		return strings.Join(values, ",")
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+// yamlValue converts JSON numbers of decoded responses so they are written as YAML numbers
+func yamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = yamlValue(item)
+		}
+		return object
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = yamlValue(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Tecsisa/foulkon/api"
+	internalhttp "github.com/Tecsisa/foulkon/http"
+	"gopkg.in/yaml.v2"
+)
+
+var policyColumns = []string{"org", "name", "path", "version", "urn", "createAt", "updateAt"}
+
+func listPolicies(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	org := fs.String("org", "", "Organization name, all organizations if empty")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *org == "" {
+		result, err := c.list(internalhttp.API_VERSION_1+"/policies", nil, "policies")
+		if err != nil {
+			return err
+		}
+		return c.printList(result, "policies", "org", "name")
+	}
+	result, err := c.list(route(internalhttp.POLICY_ROOT_URL, internalhttp.ORG_NAME, *org), nil, "policies")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "policies", "name")
+}
+
+func getPolicy(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	result, err := c.do(http.MethodGet, policyRoute(internalhttp.POLICY_ID_URL, *org, *name), nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.print(result, policyColumns...)
+}
+
+func createPolicy(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	path := fs.String("path", "", "Policy location")
+	file := fs.String("statements", "", "JSON or YAML file with the list of policy statements")
+	if err := c.parse(fs, args, "org", "name", "path", "statements"); err != nil {
+		return err
+	}
+	statements, err := readStatements(*file)
+	if err != nil {
+		return err
+	}
+	request := &internalhttp.CreatePolicyRequest{
+		Name:       *name,
+		Path:       *path,
+		Statements: statements,
+	}
+	result, err := c.do(http.MethodPost, route(internalhttp.POLICY_ROOT_URL, internalhttp.ORG_NAME, *org), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, policyColumns...)
+}
+
+func updatePolicy(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	newName := fs.String("new-name", "", "New policy name, same name if empty")
+	path := fs.String("path", "", "New policy location")
+	file := fs.String("statements", "", "JSON or YAML file with the new list of policy statements")
+	if err := c.parse(fs, args, "org", "name", "path", "statements"); err != nil {
+		return err
+	}
+	statements, err := readStatements(*file)
+	if err != nil {
+		return err
+	}
+	request := &internalhttp.UpdatePolicyRequest{
+		Name:       *name,
+		Path:       *path,
+		Statements: statements,
+	}
+	if *newName != "" {
+		request.Name = *newName
+	}
+	result, err := c.do(http.MethodPut, policyRoute(internalhttp.POLICY_ID_URL, *org, *name), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, policyColumns...)
+}
+
+func deletePolicy(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	_, err := c.do(http.MethodDelete, policyRoute(internalhttp.POLICY_ID_URL, *org, *name), nil, nil)
+	return err
+}
+
+func listAttachedGroups(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	result, err := c.list(policyRoute(internalhttp.POLICY_ID_GROUPS_URL, *org, *name), nil, "groups")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "groups", "group", "attached")
+}
+
+func listPolicyVersions(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	result, err := c.list(policyRoute(internalhttp.POLICY_ID_VERSIONS_URL, *org, *name), nil, "versions")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "versions", "version", "default", "author", "createAt")
+}
+
+func getPolicyVersion(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	version := fs.Int("version", 0, "Policy version")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	path := route(policyRoute(internalhttp.POLICY_ID_VERSIONS_ID_URL, *org, *name), internalhttp.POLICY_VERSION, strconv.Itoa(*version))
+	result, err := c.do(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.print(result, "version", "default", "author", "createAt", "statements")
+}
+
+func diffPolicyVersions(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	from := fs.Int("from", 0, "Version to compare from")
+	to := fs.Int("to", 0, "Version to compare to")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Set("From", strconv.Itoa(*from))
+	query.Set("To", strconv.Itoa(*to))
+	result, err := c.do(http.MethodGet, policyRoute(internalhttp.POLICY_ID_VERSIONS_DIFF_URL, *org, *name), query, nil)
+	if err != nil {
+		return err
+	}
+	return c.print(result, "fromVersion", "toVersion", "addedStatements", "removedStatements")
+}
+
+func setDefaultPolicyVersion(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Policy name")
+	version := fs.Int("version", 0, "Policy version")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	request := &internalhttp.SetDefaultPolicyVersionRequest{
+		Version: *version,
+	}
+	result, err := c.do(http.MethodPut, policyRoute(internalhttp.POLICY_ID_DEFAULT_VERSION_URL, *org, *name), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, policyColumns...)
+}
+
+func listAttachments(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	user := fs.String("user", "", "User external identifier")
+	org := fs.String("org", "", "Organization name of the group")
+	group := fs.String("group", "", "Group name")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	switch {
+	case *user != "" && *group == "":
+		result, err := c.list(route(internalhttp.USER_ID_POLICIES_URL, internalhttp.USER_ID, *user), nil, "policies")
+		if err != nil {
+			return err
+		}
+		return c.printList(result, "policies", "org", "policy", "attached")
+	case *user == "" && *group != "" && *org != "":
+		result, err := c.list(groupRoute(internalhttp.GROUP_ID_POLICIES_URL, *org, *group), nil, "policies")
+		if err != nil {
+			return err
+		}
+		return c.printList(result, "policies", "policy", "attached")
+	default:
+		fs.Usage()
+		return fmt.Errorf("either -user or -org and -group flags are required")
+	}
+}
+
+func attachPolicy(c *ctl, args []string) error {
+	return changeAttachment(c, args, http.MethodPost)
+}
+
+func detachPolicy(c *ctl, args []string) error {
+	return changeAttachment(c, args, http.MethodDelete)
+}
+
+// changeAttachment attaches or detaches, depending on method, a policy to a user or group
+func changeAttachment(c *ctl, args []string, method string) error {
+	fs := c.flagSet()
+	user := fs.String("user", "", "User external identifier")
+	group := fs.String("group", "", "Group name")
+	org := fs.String("org", "", "Organization name of the policy and the group")
+	policy := fs.String("policy", "", "Policy name")
+	if err := c.parse(fs, args, "org", "policy"); err != nil {
+		return err
+	}
+	var path string
+	switch {
+	case *user != "" && *group == "":
+		path = route(internalhttp.USER_ID_POLICIES_ID_URL, internalhttp.USER_ID, *user, internalhttp.ORG_NAME, *org,
+			internalhttp.POLICY_NAME, *policy)
+	case *user == "" && *group != "":
+		path = route(groupRoute(internalhttp.GROUP_ID_POLICIES_ID_URL, *org, *group), internalhttp.POLICY_NAME, *policy)
+	default:
+		fs.Usage()
+		return fmt.Errorf("either -user or -group flag is required")
+	}
+	_, err := c.do(method, path, nil, nil)
+	return err
+}
+
+func policyRoute(pattern string, org string, name string) string {
+	return route(pattern, internalhttp.ORG_NAME, org, internalhttp.POLICY_NAME, name)
+}
+
+// readStatements reads policy statements from a JSON or YAML file, using its extension
+func readStatements(file string) ([]api.Statement, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	statements := []api.Statement{}
+	switch filepath.Ext(file) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &statements)
+	default:
+		err = json.Unmarshal(b, &statements)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read statements file %v: %v", file, err)
+	}
+	return statements, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// Environment variables to override defaults
+	CONFIG_FILE_ENV = "FOULKONCTL_CONFIG"
+	PROFILE_ENV     = "FOULKONCTL_PROFILE"
+
+	DEFAULT_CONFIG_FILE = ".foulkonctl.toml"
+	DEFAULT_PROFILE     = "default"
+	DEFAULT_URL         = "http://localhost:8000"
+)
+
+var rEnvVar, _ = regexp.Compile(`^\$\{(\w+)\}$`)
+
+// Profile holds the worker address and credentials used to call it.
+// Admin users authenticate with username and password, any other user
+// with an OIDC token sent as bearer.
+type Profile struct {
+	URL      string
+	Username string
+	Password string
+	Token    string
+}
+
+// loadProfile reads profile name from config file. Config file has a table for each profile:
+//
+//	[profiles.default]
+//	url = "http://localhost:8000"
+//	username = "admin"
+//	password = "admin"
+//
+//	[profiles.dev]
+//	url = "https://foulkon.example.com"
+//	token = "eyJhbGciOiJSUzI1NiIsImtpZCI6..."
+//
+// Values like '${SOME_KEY}' are read from OS ENV vars, so secrets don't have to be stored in the file.
+// A missing config file is only allowed for the default profile.
+func loadProfile(configFile string, name string) (*Profile, error) {
+	if configFile == "" {
+		configFile = os.Getenv(CONFIG_FILE_ENV)
+	}
+	if configFile == "" {
+		configFile = filepath.Join(os.Getenv("HOME"), DEFAULT_CONFIG_FILE)
+	}
+	if name == "" {
+		name = os.Getenv(PROFILE_ENV)
+	}
+	if name == "" {
+		name = DEFAULT_PROFILE
+	}
+
+	if _, err := os.Stat(configFile); os.IsNotExist(err) && name == DEFAULT_PROFILE {
+		return &Profile{URL: DEFAULT_URL}, nil
+	}
+
+	config, err := toml.LoadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read configuration file %v: %v", configFile, err)
+	}
+	key := "profiles." + name
+	if !config.Has(key) {
+		return nil, fmt.Errorf("profile %v not found in configuration file %v", name, configFile)
+	}
+
+	profile := &Profile{
+		URL:      getProfileValue(config, key+".url"),
+		Username: getProfileValue(config, key+".username"),
+		Password: getProfileValue(config, key+".password"),
+		Token:    getProfileValue(config, key+".token"),
+	}
+	if profile.URL == "" {
+		profile.URL = DEFAULT_URL
+	}
+	if profile.Token != "" && profile.Username != "" {
+		return nil, fmt.Errorf("profile %v can't define both token and username", name)
+	}
+	return profile, nil
+}
+
+func getProfileValue(config *toml.TomlTree, key string) string {
+	if !config.Has(key) {
+		return ""
+	}
+	value, ok := config.Get(key).(string)
+	if !ok {
+		return ""
+	}
+	if match := rEnvVar.FindStringSubmatch(value); len(match) > 1 {
+		return os.Getenv(match[1])
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/api"
+	internalhttp "github.com/Tecsisa/foulkon/http"
+)
+
+var proxyResourceColumns = []string{"org", "name", "path", "urn", "createAt", "updateAt"}
+
+func listProxyResources(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	org := fs.String("org", "", "Organization name")
+	if err := c.parse(fs, args, "org"); err != nil {
+		return err
+	}
+	result, err := c.list(route(internalhttp.PROXY_RESOURCE_ROOT_URL, internalhttp.ORG_NAME, *org), nil, "resources")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "resources", "name")
+}
+
+func getProxyResource(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Proxy resource name")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	result, err := c.do(http.MethodGet, proxyResourceRoute(*org, *name), nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.print(result, proxyResourceColumns...)
+}
+
+func createProxyResource(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Proxy resource name")
+	path := fs.String("path", "", "Proxy resource location")
+	resource := resourceEntityFlags(fs)
+	if err := c.parse(fs, args, "org", "name", "path", "host", "resource-path", "method", "urn", "action"); err != nil {
+		return err
+	}
+	request := &internalhttp.CreateProxyResourceRequest{
+		Name:     *name,
+		Path:     *path,
+		Resource: *resource,
+	}
+	result, err := c.do(http.MethodPost, route(internalhttp.PROXY_RESOURCE_ROOT_URL, internalhttp.ORG_NAME, *org), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, proxyResourceColumns...)
+}
+
+func updateProxyResource(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Proxy resource name")
+	newName := fs.String("new-name", "", "New proxy resource name, same name if empty")
+	path := fs.String("path", "", "New proxy resource location")
+	resource := resourceEntityFlags(fs)
+	if err := c.parse(fs, args, "org", "name", "path", "host", "resource-path", "method", "urn", "action"); err != nil {
+		return err
+	}
+	request := &internalhttp.UpdateProxyResourceRequest{
+		Name:     *name,
+		Path:     *path,
+		Resource: *resource,
+	}
+	if *newName != "" {
+		request.Name = *newName
+	}
+	result, err := c.do(http.MethodPut, proxyResourceRoute(*org, *name), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, proxyResourceColumns...)
+}
+
+func deleteProxyResource(c *ctl, args []string) error {
+	fs := c.flagSet()
+	org := fs.String("org", "", "Organization name")
+	name := fs.String("name", "", "Proxy resource name")
+	if err := c.parse(fs, args, "org", "name"); err != nil {
+		return err
+	}
+	_, err := c.do(http.MethodDelete, proxyResourceRoute(*org, *name), nil, nil)
+	return err
+}
+
+func resourceEntityFlags(fs *flag.FlagSet) *api.ResourceEntity {
+	resource := &api.ResourceEntity{}
+	fs.StringVar(&resource.Host, "host", "", "Destination host of the proxy resource")
+	fs.StringVar(&resource.Path, "resource-path", "", "Path of the proxy resource")
+	fs.StringVar(&resource.Method, "method", "", "HTTP method of the proxy resource")
+	fs.StringVar(&resource.Urn, "urn", "", "URN of the proxy resource")
+	fs.StringVar(&resource.Action, "action", "", "Action of the proxy resource")
+	return resource
+}
+
+func proxyResourceRoute(org string, name string) string {
+	return route(internalhttp.PROXY_RESOURCE_ID_URL, internalhttp.ORG_NAME, org, internalhttp.PROXY_RESOURCE_NAME, name)
+}
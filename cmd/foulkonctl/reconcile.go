@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/api"
+	internalhttp "github.com/Tecsisa/foulkon/http"
+	"gopkg.in/yaml.v2"
+)
+
+// Symbols of the diff written for each kind of change
+var changeSymbols = map[string]string{
+	api.STATE_OPERATION_CREATE:          "+",
+	api.STATE_OPERATION_ADD_MEMBER:      "+",
+	api.STATE_OPERATION_ADD_SUBGROUP:    "+",
+	api.STATE_OPERATION_ATTACH_POLICY:   "+",
+	api.STATE_OPERATION_UPDATE:          "~",
+	api.STATE_OPERATION_DELETE:          "-",
+	api.STATE_OPERATION_REMOVE_MEMBER:   "-",
+	api.STATE_OPERATION_REMOVE_SUBGROUP: "-",
+	api.STATE_OPERATION_DETACH_POLICY:   "-",
+}
+
+func planReconcile(c *ctl, args []string) error {
+	return reconcile(c, args, internalhttp.RECONCILE_PLAN_URL)
+}
+
+func applyReconcile(c *ctl, args []string) error {
+	return reconcile(c, args, internalhttp.RECONCILE_APPLY_URL)
+}
+
+// reconcile sends the desired state of some organizations to the plan or apply endpoint
+func reconcile(c *ctl, args []string, path string) error {
+	fs := c.flagSet()
+	file := fs.String("file", "", "JSON or YAML file with the desired state of the organizations")
+	ignoreUnmanaged := fs.Bool("ignore-unmanaged", false, "Don't delete groups, policies and proxy resources missing from the file")
+	if err := c.parse(fs, args, "file"); err != nil {
+		return err
+	}
+	state, err := readState(*file)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	if *ignoreUnmanaged {
+		query.Set("IgnoreUnmanaged", "true")
+	}
+	result, err := c.do(http.MethodPost, path, query, state)
+	if err != nil {
+		return err
+	}
+	if c.output != OUTPUT_TABLE {
+		return c.encode(result)
+	}
+	return c.printDiff(result)
+}
+
+// printDiff writes the changes of a reconcile result as a diff, one line per change
+func (c *ctl) printDiff(result interface{}) error {
+	object, _ := result.(map[string]interface{})
+	changes, _ := object["changes"].([]interface{})
+	counts := map[string]int{}
+	for _, item := range changes {
+		change, _ := item.(map[string]interface{})
+		operation := formatValue(change["operation"])
+		symbol := changeSymbols[operation]
+		counts[symbol]++
+		line := fmt.Sprintf("%v %v %v", symbol, operation, formatValue(change["resource"]))
+		if target := formatValue(change["target"]); target != "" {
+			line += " " + target
+		}
+		fmt.Fprintln(c.out, line)
+		if details, ok := change["details"].([]interface{}); ok {
+			for _, detail := range details {
+				fmt.Fprintf(c.out, "    %v\n", formatValue(detail))
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		_, err := fmt.Fprintf(c.out, "No changes, organizations %v are up to date\n", formatOrgs(object["orgs"]))
+		return err
+	}
+	format := "Plan: %v to add, %v to change, %v to remove\n"
+	if applied, _ := object["applied"].(bool); applied {
+		format = "Applied: %v added, %v changed, %v removed\n"
+	}
+	_, err := fmt.Fprintf(c.out, format, counts["+"], counts["~"], counts["-"])
+	return err
+}
+
+func formatOrgs(value interface{}) string {
+	orgs, _ := value.([]interface{})
+	names := make([]string, len(orgs))
+	for i, org := range orgs {
+		names[i] = formatValue(org)
+	}
+	return strings.Join(names, ", ")
+}
+
+// readState reads a state document from a JSON or YAML file, using its extension
+func readState(file string) (*api.State, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	state := &api.State{}
+	switch filepath.Ext(file) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, state)
+	default:
+		err = json.Unmarshal(b, state)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read state file %v: %v", file, err)
+	}
+	return state, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+
+	internalhttp "github.com/Tecsisa/foulkon/http"
+)
+
+var userColumns = []string{"externalId", "path", "urn", "createAt", "updateAt"}
+
+func listUsers(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	result, err := c.list(internalhttp.USER_ROOT_URL, nil, "users")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "users", "externalId")
+}
+
+func getUser(c *ctl, args []string) error {
+	fs := c.flagSet()
+	id := fs.String("id", "", "User external identifier")
+	if err := c.parse(fs, args, "id"); err != nil {
+		return err
+	}
+	result, err := c.do(http.MethodGet, route(internalhttp.USER_ID_URL, internalhttp.USER_ID, *id), nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.print(result, userColumns...)
+}
+
+func createUser(c *ctl, args []string) error {
+	fs := c.flagSet()
+	id := fs.String("id", "", "User external identifier")
+	path := fs.String("path", "", "User location")
+	if err := c.parse(fs, args, "id", "path"); err != nil {
+		return err
+	}
+	request := &internalhttp.CreateUserRequest{
+		ExternalID: *id,
+		Path:       *path,
+	}
+	result, err := c.do(http.MethodPost, internalhttp.USER_ROOT_URL, nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, userColumns...)
+}
+
+func updateUser(c *ctl, args []string) error {
+	fs := c.flagSet()
+	id := fs.String("id", "", "User external identifier")
+	path := fs.String("path", "", "New user location")
+	if err := c.parse(fs, args, "id", "path"); err != nil {
+		return err
+	}
+	request := &internalhttp.UpdateUserRequest{
+		Path: *path,
+	}
+	result, err := c.do(http.MethodPut, route(internalhttp.USER_ID_URL, internalhttp.USER_ID, *id), nil, request)
+	if err != nil {
+		return err
+	}
+	return c.print(result, userColumns...)
+}
+
+func deleteUser(c *ctl, args []string) error {
+	fs := c.flagSet()
+	id := fs.String("id", "", "User external identifier")
+	if err := c.parse(fs, args, "id"); err != nil {
+		return err
+	}
+	_, err := c.do(http.MethodDelete, route(internalhttp.USER_ID_URL, internalhttp.USER_ID, *id), nil, nil)
+	return err
+}
+
+func listUserGroups(c *ctl, args []string) error {
+	fs := c.listFlagSet()
+	id := fs.String("id", "", "User external identifier")
+	if err := c.parse(fs, args, "id"); err != nil {
+		return err
+	}
+	result, err := c.list(route(internalhttp.USER_ID_GROUPS_URL, internalhttp.USER_ID, *id), nil, "groups")
+	if err != nil {
+		return err
+	}
+	return c.printList(result, "groups", "org", "name", "joined")
+}
@@ -16,6 +16,11 @@ import (
 )
 
 func main() {
+	// Schema migrations are run with "worker migrate" subcommand
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(migrate(os.Args[2:]))
+	}
+
 	// Retrieve config file
 	fs := flag.NewFlagSet("foulkon", flag.ExitOnError)
 	configFile := fs.String("config-file", "", "Config file for worker")
@@ -59,6 +64,9 @@ func main() {
 		}
 	}()
 
+	core.StartPurger()
+	core.StartWebhookDispatcher()
+
 	api.Log.Infof("Server running in %v:%v", core.Host, core.Port)
 	ws := internalhttp.NewWorker(core, internalhttp.WorkerHandlerRouter(core))
 	ws.Configuration()
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Tecsisa/foulkon/foulkon"
+	"github.com/pelletier/go-toml"
+)
+
+// migrate runs the migrate subcommand and returns the exit status
+func migrate(args []string) int {
+	fs := flag.NewFlagSet("foulkon migrate", flag.ExitOnError)
+	configFile := fs.String("config-file", "", "Config file for worker")
+	version := fs.Int("version", -1, "Schema version to migrate to, latest version if negative")
+	status := fs.Bool("status", false, "Show schema version without migrating")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	// Access to file
+	config, err := toml.LoadFile(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot read configuration file %v, error: %v\n", *configFile, err)
+		return 1
+	}
+
+	migrator, err := foulkon.NewMigrator(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	defer migrator.Close()
+
+	if *status {
+		current, latest, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+		fmt.Printf("Database schema version: %v, latest version: %v\n", current, latest)
+		return 0
+	}
+
+	from, to, err := migrator.Migrate(*version)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	if from == to {
+		fmt.Printf("Database schema is already at version %v\n", to)
+	} else {
+		fmt.Printf("Database schema migrated from version %v to %v\n", from, to)
+	}
+	return 0
+}
@@ -7,25 +7,51 @@ const (
 	INTERNAL_ERROR = "InternalError"
 
 	// User Codes
-	USER_NOT_FOUND = "UserNotFound"
+	USER_NOT_FOUND     = "UserNotFound"
+	USER_ALREADY_EXIST = "UserAlreadyExist"
 
 	// Group Codes
-	GROUP_NOT_FOUND = "GroupNotFound"
+	GROUP_NOT_FOUND     = "GroupNotFound"
+	GROUP_ALREADY_EXIST = "GroupAlreadyExist"
 
 	// Group User Relation Codes
-	GROUP_USER_RELATION_NOT_FOUND = "GroupUserRelationNotFound"
+	GROUP_USER_RELATION_NOT_FOUND     = "GroupUserRelationNotFound"
+	GROUP_USER_RELATION_ALREADY_EXIST = "GroupUserRelationAlreadyExist"
 
 	// Group Policy Relation Codes
-	GROUP_POLICY_RELATION_NOT_FOUND = "GroupPolicyRelationNotFound"
+	GROUP_POLICY_RELATION_NOT_FOUND     = "GroupPolicyRelationNotFound"
+	GROUP_POLICY_RELATION_ALREADY_EXIST = "GroupPolicyRelationAlreadyExist"
+
+	// Group Subgroup Relation Codes
+	GROUP_SUBGROUP_RELATION_ALREADY_EXIST = "GroupSubgroupRelationAlreadyExist"
+
+	// User Policy Relation Codes
+	USER_POLICY_RELATION_ALREADY_EXIST = "UserPolicyRelationAlreadyExist"
 
 	// Policy Codes
-	POLICY_NOT_FOUND = "PolicyNotFound"
+	POLICY_NOT_FOUND         = "PolicyNotFound"
+	POLICY_VERSION_NOT_FOUND = "PolicyVersionNotFound"
+	POLICY_ALREADY_EXIST     = "PolicyAlreadyExist"
+
+	// Organization Codes
+	ORGANIZATION_NOT_FOUND     = "OrganizationNotFound"
+	ORGANIZATION_ALREADY_EXIST = "OrganizationAlreadyExist"
 
 	// Proxy resource Codes
-	PROXY_RESOURCE_NOT_FOUND = "ProxyResourceNotFound"
+	PROXY_RESOURCE_NOT_FOUND     = "ProxyResourceNotFound"
+	PROXY_RESOURCE_ALREADY_EXIST = "ProxyResourceAlreadyExist"
 
 	// Auth Provider Codes
-	AUTH_OIDC_PROVIDER_NOT_FOUND = "AuthOidcProviderNotFound"
+	AUTH_OIDC_PROVIDER_NOT_FOUND     = "AuthOidcProviderNotFound"
+	AUTH_OIDC_PROVIDER_ALREADY_EXIST = "AuthOidcProviderAlreadyExist"
+
+	// Webhook Codes
+	WEBHOOK_NOT_FOUND            = "WebhookNotFound"
+	WEBHOOK_ALREADY_EXIST        = "WebhookAlreadyExist"
+	WEBHOOK_DELIVERY_NOT_CLAIMED = "WebhookDeliveryNotClaimed"
+
+	// Optimistic concurrency Codes
+	REVISION_MISMATCH = "RevisionMismatch"
 )
 
 type Error struct {
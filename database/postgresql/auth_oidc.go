@@ -24,15 +24,13 @@ func (pr PostgresRepo) AddOidcProvider(oidcProvider api.OidcProvider) (*api.Oidc
 		IssuerURL: oidcProvider.IssuerURL,
 	}
 
-	transaction := pr.Dbmap.Begin()
+	transaction := pr.begin()
 
 	// Create OIDC Provider
 	if err := transaction.Create(oidcProviderDB).Error; err != nil {
-		transaction.Rollback()
-		return nil, &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		pr.rollback(transaction)
+		return nil, storeError(err, database.AUTH_OIDC_PROVIDER_ALREADY_EXIST,
+			fmt.Sprintf("OIDC provider with name %v already exist", oidcProvider.Name))
 	}
 
 	// Create OIDC Clients
@@ -44,7 +42,7 @@ func (pr PostgresRepo) AddOidcProvider(oidcProvider api.OidcProvider) (*api.Oidc
 			Name:           oidcClientApi.Name,
 		}
 		if err := transaction.Create(oidcClientDB).Error; err != nil {
-			transaction.Rollback()
+			pr.rollback(transaction)
 			return nil, &database.Error{
 				Code:    database.INTERNAL_ERROR,
 				Message: err.Error(),
@@ -52,7 +50,7 @@ func (pr PostgresRepo) AddOidcProvider(oidcProvider api.OidcProvider) (*api.Oidc
 		}
 	}
 
-	transaction.Commit()
+	pr.commit(transaction)
 
 	// Create API OIDC Provider
 	oidcProviderApi := dbOidcProviderToAPIOidcProvider(oidcProviderDB)
@@ -160,20 +158,18 @@ func (pr PostgresRepo) UpdateOidcProvider(oidcProvider api.OidcProvider) (*api.O
 		IssuerURL: oidcProvider.IssuerURL,
 	}
 
-	transaction := pr.Dbmap.Begin()
+	transaction := pr.begin()
 
 	// Update OIDC Provider
 	if err := transaction.Model(&OidcProvider{ID: oidcProvider.ID}).Update(oidcProviderDB).Error; err != nil {
-		transaction.Rollback()
-		return nil, &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		pr.rollback(transaction)
+		return nil, storeError(err, database.AUTH_OIDC_PROVIDER_ALREADY_EXIST,
+			fmt.Sprintf("OIDC provider with name %v already exist", oidcProvider.Name))
 	}
 
 	// Clean old OIDC Clients
 	if err := transaction.Where("oidc_provider_id like ?", oidcProvider.ID).Delete(OidcClient{}).Error; err != nil {
-		transaction.Rollback()
+		pr.rollback(transaction)
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -188,7 +184,7 @@ func (pr PostgresRepo) UpdateOidcProvider(oidcProvider api.OidcProvider) (*api.O
 			Name:           oc.Name,
 		}
 		if err := transaction.Create(oidcClientDB).Error; err != nil {
-			transaction.Rollback()
+			pr.rollback(transaction)
 			return nil, &database.Error{
 				Code:    database.INTERNAL_ERROR,
 				Message: err.Error(),
@@ -196,18 +192,18 @@ func (pr PostgresRepo) UpdateOidcProvider(oidcProvider api.OidcProvider) (*api.O
 		}
 	}
 
-	transaction.Commit()
+	pr.commit(transaction)
 
 	return &oidcProvider, nil
 }
 
 func (pr PostgresRepo) RemoveOidcProvider(id string) error {
-	transaction := pr.Dbmap.Begin()
+	transaction := pr.begin()
 
 	// Delete OIDC Provider
 	transaction.Where("id like ?", id).Delete(&OidcProvider{})
 	if err := transaction.Error; err != nil {
-		transaction.Rollback()
+		pr.rollback(transaction)
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -217,7 +213,7 @@ func (pr PostgresRepo) RemoveOidcProvider(id string) error {
 	// Delete all OIDC Clients
 	transaction.Where("oidc_provider_id like ?", id).Delete(&OidcClient{})
 	if err := transaction.Error; err != nil {
-		transaction.Rollback()
+		pr.rollback(transaction)
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -225,7 +221,7 @@ func (pr PostgresRepo) RemoveOidcProvider(id string) error {
 
 	}
 
-	transaction.Commit()
+	pr.commit(transaction)
 	return nil
 }
 
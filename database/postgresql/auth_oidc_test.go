@@ -113,8 +113,8 @@ func TestPostgresRepo_AddOidcProvider(t *testing.T) {
 				},
 			},
 			expectedError: &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: "pq: duplicate key value violates unique constraint \"oidc_providers_pkey\"",
+				Code:    database.AUTH_OIDC_PROVIDER_ALREADY_EXIST,
+				Message: "OIDC provider with name Name already exist",
 			},
 		},
 		"ErrorCaseOIDCClientAlreadyExists": {
@@ -549,8 +549,8 @@ func TestPostgresRepo_UpdateOidcProvider(t *testing.T) {
 				},
 			},
 			expectedError: &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: "pq: duplicate key value violates unique constraint \"oidc_providers_urn_key\"",
+				Code:    database.AUTH_OIDC_PROVIDER_ALREADY_EXIST,
+				Message: "OIDC provider with name test1 already exist",
 			},
 		},
 		"ErrorCaseClientDuplicated": {
@@ -8,6 +8,11 @@ import (
 	"github.com/Tecsisa/foulkon/database"
 )
 
+const (
+	// Key of the advisory locks held while the group hierarchy changes, paired with the hash of the organization
+	GROUP_HIERARCHY_LOCK_ID = 4350412
+)
+
 // GROUP REPOSITORY IMPLEMENTATION
 
 func (pr PostgresRepo) AddGroup(group api.Group) (*api.Group, error) {
@@ -20,6 +25,7 @@ func (pr PostgresRepo) AddGroup(group api.Group) (*api.Group, error) {
 		UpdateAt: group.UpdateAt.UnixNano(),
 		Urn:      group.Urn,
 		Org:      group.Org,
+		Revision: 1,
 	}
 
 	// Store group
@@ -27,10 +33,8 @@ func (pr PostgresRepo) AddGroup(group api.Group) (*api.Group, error) {
 
 	// Error handling
 	if err != nil {
-		return nil, &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		return nil, storeError(err, database.GROUP_ALREADY_EXIST,
+			fmt.Sprintf("Group with organization %v and name %v already exist", group.Org, group.Name))
 	}
 
 	return dbGroupToAPIGroup(groupDB), nil
@@ -38,7 +42,7 @@ func (pr PostgresRepo) AddGroup(group api.Group) (*api.Group, error) {
 
 func (pr PostgresRepo) GetGroupByName(org string, name string) (*api.Group, error) {
 	group := &Group{}
-	query := pr.Dbmap.Where("org like ? AND name like ?", org, name).First(group)
+	query := pr.lockRows().Where("org like ? AND name like ? AND delete_at = 0", org, name).First(group)
 
 	// Check if group exists
 	if query.RecordNotFound() {
@@ -85,7 +89,7 @@ func (pr PostgresRepo) GetGroupById(id string) (*api.Group, error) {
 func (pr PostgresRepo) GetGroupsFiltered(filter *api.Filter) ([]api.Group, int, error) {
 	var total int
 	groups := []Group{}
-	query := pr.Dbmap
+	query := pr.Dbmap.Where(deletedCondition(filter))
 
 	if len(filter.Org) > 0 {
 		query = query.Where("org like ? ", filter.Org)
@@ -93,12 +97,24 @@ func (pr PostgresRepo) GetGroupsFiltered(filter *api.Filter) ([]api.Group, int,
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ? ", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
+	if len(filter.Member) > 0 {
+		query = query.Where("id IN (SELECT group_id FROM group_user_relations WHERE user_id IN "+
+			"(SELECT id FROM users WHERE external_id = ? AND delete_at = 0))", filter.Member)
+	}
+	if len(filter.AttachedPolicy) > 0 {
+		query = query.Where("id IN (SELECT group_id FROM group_policy_relations WHERE policy_id IN "+
+			"(SELECT id FROM policies WHERE name = ? AND delete_at = 0))", filter.AttachedPolicy)
 	}
+	query = search(query, filter, "name")
 
 	// Error handling
-	if err := query.Find(&groups).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&groups).Error; err != nil {
+	if err := query.Model(&Group{}).Count(&total).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if err := paginate(query, filter, "id").Find(&groups).Error; err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -127,10 +143,11 @@ func (pr PostgresRepo) UpdateGroup(group api.Group) (*api.Group, error) {
 		UpdateAt: group.UpdateAt.UTC().UnixNano(),
 		Urn:      group.Urn,
 		Org:      group.Org,
+		Revision: group.Revision + 1,
 	}
 
-	// Update group
-	query := pr.Dbmap.Model(&Group{ID: group.ID}).Updates(groupDB)
+	// Update group if it wasn't modified after the revision retrieved
+	query := pr.Dbmap.Model(&Group{ID: group.ID}).Where("revision = ?", group.Revision).Updates(groupDB)
 
 	// Check if group exist
 	if query.RecordNotFound() {
@@ -142,52 +159,82 @@ func (pr PostgresRepo) UpdateGroup(group api.Group) (*api.Group, error) {
 
 	// Error Handling
 	if err := query.Error; err != nil {
-		return nil, &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		return nil, storeError(err, database.GROUP_ALREADY_EXIST,
+			fmt.Sprintf("Group with organization %v and name %v already exist", group.Org, group.Name))
+	}
+	if query.RowsAffected == 0 {
+		return nil, revisionMismatchError("Group", group.ID, group.Revision)
 	}
 
+	group.Revision = groupDB.Revision
 	return &group, nil
 }
 
-func (pr PostgresRepo) RemoveGroup(id string) error {
-	transaction := pr.Dbmap.Begin()
+func (pr PostgresRepo) RemoveGroup(id string, revision int) error {
+	// Mark group as deleted, its relations are kept until it's purged
+	query := pr.Dbmap.Model(&Group{}).Where("id like ? AND revision = ?", id, revision).UpdateColumn("delete_at", time.Now().UTC().UnixNano())
 
-	// Delete group
-	transaction.Where("id like ?", id).Delete(&Group{})
-	if err := transaction.Error; err != nil {
-		transaction.Rollback()
+	// Error handling
+	if err := query.Error; err != nil {
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
+	if query.RowsAffected == 0 {
+		return revisionMismatchError("Group", id, revision)
+	}
 
-	// Delete all group relations
-	transaction.Where("group_id like ?", id).Delete(&GroupUserRelation{})
-	if err := transaction.Error; err != nil {
-		transaction.Rollback()
-		return &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+	return nil
+}
 
+func (pr PostgresRepo) GetDeletedGroupByName(org string, name string) (*api.Group, error) {
+	group := &Group{}
+	query := pr.Dbmap.Where("org like ? AND name like ? AND delete_at > 0", org, name).Order("delete_at desc").First(group)
+
+	// Check if group exists
+	if query.RecordNotFound() {
+		return nil, &database.Error{
+			Code:    database.GROUP_NOT_FOUND,
+			Message: fmt.Sprintf("Deleted group with organization %v and name %v not found", org, name),
+		}
 	}
-	// Delete all policy relations
-	transaction.Where("group_id like ?", id).Delete(&GroupPolicyRelation{})
-	if err := transaction.Error; err != nil {
-		transaction.Rollback()
-		return &database.Error{
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
-	transaction.Commit()
+	return dbGroupToAPIGroup(group), nil
+}
+
+func (pr PostgresRepo) RestoreGroup(id string) error {
+	err := pr.Dbmap.Model(&Group{}).Where("id like ?", id).UpdateColumn("delete_at", 0).Error
+
+	// Error handling
+	if err != nil {
+		return storeError(err, database.GROUP_ALREADY_EXIST,
+			fmt.Sprintf("Group with id %v can't be restored, its name is in use", id))
+	}
+
 	return nil
 }
 
+func (pr PostgresRepo) PurgeGroups(deleteBefore time.Time) (int, error) {
+	deletedGroups := "SELECT id FROM groups WHERE delete_at > 0 AND delete_at < ?"
+
+	// Delete groups with their member, policy and nested group relations
+	return pr.purge([]string{
+		"DELETE FROM group_user_relations WHERE group_id IN (" + deletedGroups + ")",
+		"DELETE FROM group_policy_relations WHERE group_id IN (" + deletedGroups + ")",
+		"DELETE FROM group_subgroup_relations WHERE group_id IN (" + deletedGroups + ") OR subgroup_id IN (" + deletedGroups + ")",
+		"DELETE FROM groups WHERE delete_at > 0 AND delete_at < ?",
+	}, deleteBefore)
+}
+
 func (pr PostgresRepo) AddMember(userID string, groupID string) error {
 	// Create relation
 	relation := &GroupUserRelation{
@@ -201,10 +248,8 @@ func (pr PostgresRepo) AddMember(userID string, groupID string) error {
 
 	// Error handling
 	if err != nil {
-		return &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		return storeError(err, database.GROUP_USER_RELATION_ALREADY_EXIST,
+			fmt.Sprintf("User with id %v is already a member of group with id %v", userID, groupID))
 	}
 
 	return nil
@@ -246,14 +291,16 @@ func (pr PostgresRepo) IsMemberOfGroup(userID string, groupID string) (bool, err
 func (pr PostgresRepo) GetGroupMembers(groupID string, filter *api.Filter) ([]api.UserGroupRelation, int, error) {
 	var total int
 	members := []GroupUserRelation{}
-	query := pr.Dbmap.Where("group_id like ?", groupID)
-
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
+	query := pr.Dbmap.Where("group_id like ? AND user_id NOT IN (SELECT id FROM users WHERE delete_at > 0)", groupID)
 
 	// Error handling
-	if err := query.Find(&members).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&members).Error; err != nil {
+	if err := query.Model(&GroupUserRelation{}).Count(&total).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if err := paginate(query, filter, "user_id").Find(&members).Error; err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -285,6 +332,147 @@ func (pr PostgresRepo) GetGroupMembers(groupID string, filter *api.Filter) ([]ap
 	return membersList, total, nil
 }
 
+func (pr PostgresRepo) AddSubgroup(groupID string, subgroupID string) error {
+	// Create relation
+	relation := &GroupSubgroupRelation{
+		GroupID:    groupID,
+		SubgroupID: subgroupID,
+		CreateAt:   time.Now().UTC().UnixNano(),
+	}
+
+	// Store relation
+	err := pr.Dbmap.Create(relation).Error
+
+	// Error handling
+	if err != nil {
+		return storeError(err, database.GROUP_SUBGROUP_RELATION_ALREADY_EXIST,
+			fmt.Sprintf("Group with id %v is already a subgroup of group with id %v", subgroupID, groupID))
+	}
+
+	return nil
+}
+
+func (pr PostgresRepo) RemoveSubgroup(groupID string, subgroupID string) error {
+	err := pr.Dbmap.Where("group_id like ? AND subgroup_id like ?", groupID, subgroupID).Delete(&GroupSubgroupRelation{}).Error
+
+	// Error handling
+	if err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+func (pr PostgresRepo) IsSubgroupOfGroup(subgroupID string, groupID string) (bool, error) {
+	relation := GroupSubgroupRelation{}
+	query := pr.Dbmap.Where("group_id like ? AND subgroup_id like ?", groupID, subgroupID).First(&relation)
+
+	// Check if relation exists
+	if query.RecordNotFound() {
+		return false, nil
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return false, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return true, nil
+}
+
+func (pr PostgresRepo) GetSubgroups(groupID string, filter *api.Filter) ([]api.GroupSubgroupRelation, int, error) {
+	var total int
+	relations := []GroupSubgroupRelation{}
+	query := pr.Dbmap.Where("group_id like ? AND subgroup_id NOT IN (SELECT id FROM groups WHERE delete_at > 0)", groupID)
+
+	if len(filter.OrderBy) > 0 {
+		query = query.Order(filter.OrderBy)
+	}
+
+	// Error handling
+	if err := query.Find(&relations).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&relations).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	var subgroups []api.GroupSubgroupRelation
+	// Transform relations to API domain
+	if relations != nil {
+		subgroups = make([]api.GroupSubgroupRelation, len(relations), cap(relations))
+		for i, r := range relations {
+			subgroup, err := pr.GetGroupById(r.SubgroupID)
+
+			// Error handling
+			if err != nil {
+				return nil, total, &database.Error{
+					Code:    database.INTERNAL_ERROR,
+					Message: err.Error(),
+				}
+			}
+
+			subgroups[i] = &GroupSubgroup{
+				Subgroup: subgroup,
+				CreateAt: time.Unix(0, r.CreateAt).UTC(),
+			}
+		}
+	}
+
+	return subgroups, total, nil
+}
+
+func (pr PostgresRepo) GetParentGroups(groupID string) ([]api.Group, error) {
+	groups := []Group{}
+	query := pr.Dbmap.Where("id IN (SELECT group_id FROM group_subgroup_relations WHERE subgroup_id like ?) AND delete_at = 0",
+		groupID).Find(&groups)
+
+	// Error handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	apiGroups := []api.Group{}
+	for _, g := range groups {
+		apiGroups = append(apiGroups, *dbGroupToAPIGroup(&g))
+	}
+
+	return apiGroups, nil
+}
+
+func (pr PostgresRepo) GetParentGroupIDs(groupID string) ([]string, error) {
+	groupIDs := []string{}
+	query := pr.Dbmap.Model(&GroupSubgroupRelation{}).Where("subgroup_id like ?", groupID).Pluck("group_id", &groupIDs)
+
+	// Error handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return groupIDs, nil
+}
+
+func (pr PostgresRepo) LockGroupHierarchy(org string) error {
+	if err := pr.Dbmap.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", GROUP_HIERARCHY_LOCK_ID, org).Error; err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
 func (pr PostgresRepo) AttachPolicy(groupID string, policyID string) error {
 	// Create relation
 	relation := &GroupPolicyRelation{
@@ -298,10 +486,8 @@ func (pr PostgresRepo) AttachPolicy(groupID string, policyID string) error {
 
 	// Error handling
 	if err != nil {
-		return &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		return storeError(err, database.GROUP_POLICY_RELATION_ALREADY_EXIST,
+			fmt.Sprintf("Policy with id %v is already attached to group with id %v", policyID, groupID))
 	}
 
 	return nil
@@ -345,7 +531,7 @@ func (pr PostgresRepo) IsAttachedToGroup(groupID string, policyID string) (bool,
 func (pr PostgresRepo) GetAttachedPolicies(groupID string, filter *api.Filter) ([]api.PolicyGroupRelation, int, error) {
 	var total int
 	relations := []GroupPolicyRelation{}
-	query := pr.Dbmap.Where("group_id like ?", groupID)
+	query := pr.Dbmap.Where("group_id like ? AND policy_id NOT IN (SELECT id FROM policies WHERE delete_at > 0)", groupID)
 
 	if len(filter.OrderBy) > 0 {
 		query = query.Order(filter.OrderBy)
@@ -394,5 +580,6 @@ func dbGroupToAPIGroup(groupdb *Group) *api.Group {
 		UpdateAt: time.Unix(0, groupdb.UpdateAt).UTC(),
 		Urn:      groupdb.Urn,
 		Org:      groupdb.Org,
+		Revision: groupdb.Revision,
 	}
 }
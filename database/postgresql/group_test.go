@@ -38,6 +38,7 @@ func TestPostgresRepo_AddGroup(t *testing.T) {
 				Urn:      "urn",
 				CreateAt: now,
 				UpdateAt: now,
+				Revision: 1,
 				Org:      "Org",
 			},
 		},
@@ -61,8 +62,8 @@ func TestPostgresRepo_AddGroup(t *testing.T) {
 				Org:      "Org",
 			},
 			expectedError: &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: "pq: duplicate key value violates unique constraint \"groups_pkey\"",
+				Code:    database.GROUP_ALREADY_EXIST,
+				Message: "Group with organization Org and name Name already exist",
 			},
 		},
 	}
@@ -553,6 +554,122 @@ func TestPostgresRepo_GetGroupsFiltered(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_GetGroupsFilteredByRelations(t *testing.T) {
+	now := time.Now().UTC()
+	groups := []Group{
+		{
+			ID:       "GroupID1",
+			Name:     "Name1",
+			Path:     "Path123",
+			Urn:      "urn1",
+			CreateAt: now.UnixNano(),
+			UpdateAt: now.UnixNano(),
+			Org:      "Org1",
+		},
+		{
+			ID:       "GroupID2",
+			Name:     "Name2",
+			Path:     "Path456",
+			Urn:      "urn2",
+			CreateAt: now.UnixNano(),
+			UpdateAt: now.UnixNano(),
+			Org:      "Org1",
+		},
+	}
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		filter *api.Filter
+		// Expected result
+		expectedResponse []api.Group
+	}{
+		"OkCaseMember": {
+			filter: &api.Filter{
+				Limit:  20,
+				Member: "ExternalID1",
+			},
+			expectedResponse: []api.Group{
+				{
+					ID:       "GroupID1",
+					Name:     "Name1",
+					Path:     "Path123",
+					Urn:      "urn1",
+					CreateAt: now,
+					UpdateAt: now,
+					Org:      "Org1",
+				},
+			},
+		},
+		"OkCaseAttachedPolicy": {
+			filter: &api.Filter{
+				Limit:          20,
+				AttachedPolicy: "Policy1",
+			},
+			expectedResponse: []api.Group{
+				{
+					ID:       "GroupID2",
+					Name:     "Name2",
+					Path:     "Path456",
+					Urn:      "urn2",
+					CreateAt: now,
+					UpdateAt: now,
+					Org:      "Org1",
+				},
+			},
+		},
+		"OkCaseMemberAndAttachedPolicy": {
+			filter: &api.Filter{
+				Limit:          20,
+				Member:         "ExternalID1",
+				AttachedPolicy: "Policy1",
+			},
+			expectedResponse: []api.Group{},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean database
+		cleanUserTable(t, n)
+		cleanGroupTable(t, n)
+		cleanPolicyTable(t, n)
+		cleanGroupUserRelationTable(t, n)
+		cleanGroupPolicyRelationTable(t, n)
+
+		// Insert previous data
+		for _, group := range groups {
+			insertGroup(t, n, group)
+		}
+		insertUser(t, n, User{
+			ID:         "UserID1",
+			ExternalID: "ExternalID1",
+			Path:       "Path123",
+			Urn:        "urn1",
+			CreateAt:   now.UnixNano(),
+			UpdateAt:   now.UnixNano(),
+		})
+		insertGroupUserRelation(t, n, "UserID1", "GroupID1", now.UnixNano())
+		insertPolicy(t, n, Policy{
+			ID:       "PolicyID1",
+			Name:     "Policy1",
+			Path:     "Path123",
+			Urn:      "urn1",
+			CreateAt: now.UnixNano(),
+			UpdateAt: now.UnixNano(),
+			Org:      "Org1",
+		}, nil)
+		insertGroupPolicyRelation(t, n, "GroupID2", "PolicyID1", now.UnixNano())
+
+		// Call to repository to get groups
+		receivedGroups, total, err := repoDB.GetGroupsFiltered(test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check total
+		assert.Equal(t, len(test.expectedResponse), total, "Error in test case %v", n)
+
+		// Check response
+		assert.Equal(t, test.expectedResponse, receivedGroups, "Error in test case %v", n)
+	}
+}
+
 func TestPostgresRepo_UpdateGroup(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -573,6 +690,7 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 					Urn:      "Urn",
 					CreateAt: now.UnixNano(),
 					UpdateAt: now.UnixNano(),
+					Revision: 1,
 					Org:      "Org",
 				},
 			},
@@ -583,6 +701,7 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 				Urn:      "NewUrn",
 				CreateAt: now,
 				UpdateAt: now,
+				Revision: 1,
 				Org:      "Org",
 			},
 			expectedResponse: &api.Group{
@@ -592,6 +711,7 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 				Urn:      "NewUrn",
 				CreateAt: now,
 				UpdateAt: now,
+				Revision: 2,
 				Org:      "Org",
 			},
 		},
@@ -625,8 +745,8 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 				Org:      "Org",
 			},
 			expectedError: &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: "pq: duplicate key value violates unique constraint \"groups_urn_key\"",
+				Code:    database.GROUP_ALREADY_EXIST,
+				Message: "Group with organization Org and name Name already exist",
 			},
 		},
 	}
@@ -671,14 +791,21 @@ func TestPostgresRepo_RemoveGroup(t *testing.T) {
 		groupID  string
 		CreateAt int64
 	}
+	type subgroupRelation struct {
+		groupID    string
+		subgroupID string
+		CreateAt   int64
+	}
 	now := time.Now().UTC()
 	testcases := map[string]struct {
 		// Previous data
-		previousGroups  []Group
-		userRelations   []userRelation
-		policyRelations []policyRelation
+		previousGroups    []Group
+		userRelations     []userRelation
+		policyRelations   []policyRelation
+		subgroupRelations []subgroupRelation
 		// Postgres Repo Args
 		groupToDelete string
+		revision      int
 	}{
 		"OkCase": {
 			previousGroups: []Group{
@@ -689,11 +816,12 @@ func TestPostgresRepo_RemoveGroup(t *testing.T) {
 					Urn:      "Urn",
 					CreateAt: now.UnixNano(),
 					UpdateAt: now.UnixNano(),
+					Revision: 1,
 					Org:      "Org",
 				},
 				{
 					ID:       "GroupID2",
-					Name:     "Name",
+					Name:     "Name2",
 					Path:     "Path",
 					Urn:      "Urn2",
 					CreateAt: now.UnixNano(),
@@ -725,7 +853,25 @@ func TestPostgresRepo_RemoveGroup(t *testing.T) {
 					CreateAt: now.UnixNano(),
 				},
 			},
+			subgroupRelations: []subgroupRelation{
+				{
+					groupID:    "GroupID",
+					subgroupID: "GroupID2",
+					CreateAt:   now.UnixNano(),
+				},
+				{
+					groupID:    "GroupID3",
+					subgroupID: "GroupID",
+					CreateAt:   now.UnixNano(),
+				},
+				{
+					groupID:    "GroupID3",
+					subgroupID: "GroupID2",
+					CreateAt:   now.UnixNano(),
+				},
+			},
 			groupToDelete: "GroupID",
+			revision:      1,
 		},
 	}
 
@@ -733,6 +879,7 @@ func TestPostgresRepo_RemoveGroup(t *testing.T) {
 		cleanGroupTable(t, n)
 		cleanGroupUserRelationTable(t, n)
 		cleanGroupPolicyRelationTable(t, n)
+		cleanGroupSubgroupRelationTable(t, n)
 
 		// Insert previous data
 		if test.previousGroups != nil {
@@ -751,38 +898,241 @@ func TestPostgresRepo_RemoveGroup(t *testing.T) {
 				insertGroupPolicyRelation(t, n, rel.groupID, rel.policyID, rel.CreateAt)
 			}
 		}
+		for _, rel := range test.subgroupRelations {
+			insertGroupSubgroupRelation(t, n, rel.groupID, rel.subgroupID, rel.CreateAt)
+		}
 		// Call to repository to remove group
-		err := repoDB.RemoveGroup(test.groupToDelete)
+		err := repoDB.RemoveGroup(test.groupToDelete, test.revision)
 		assert.Nil(t, err, "Error in test case %v", n)
 
-		// Check database
-		groupNumber := getGroupsCountFiltered(t, n, test.groupToDelete, "", "", 0, 0, "", "")
-		assert.Equal(t, 0, groupNumber, "Error in test case %v", n)
+		// Check group is marked as deleted
+		deleteAt := getDeleteAt(t, n, Group{}.TableName(), test.groupToDelete)
+		assert.True(t, deleteAt > 0, "Error in test case %v", n)
 
-		// Check total groups
+		// Check total groups, deleted groups are kept until purged
 		totalGroupNumber := getGroupsCountFiltered(t, n, "", "", "", 0, 0, "", "")
-		assert.Equal(t, 1, totalGroupNumber, "Error in test case %v", n)
+		assert.Equal(t, 2, totalGroupNumber, "Error in test case %v", n)
 
-		// Check group user relations
+		// Check group user relations are kept
 		relations := getGroupUserRelations(t, n, test.groupToDelete, "")
-		assert.Equal(t, 0, relations, "Error in test case %v", n)
-
-		// Check total group user relations
-		totalRelations := getGroupUserRelations(t, n, "", "")
-		assert.Equal(t, 1, totalRelations, "Error in test case %v", n)
+		assert.Equal(t, 1, relations, "Error in test case %v", n)
 
-		// Check group policy relations
+		// Check group policy relations are kept
 		relations = getGroupPolicyRelationCount(t, n, "", test.groupToDelete)
-		assert.Equal(t, 0, relations, "Error in test case %v", n)
+		assert.Equal(t, 1, relations, "Error in test case %v", n)
+
+		// Check nested group relations are kept
+		relations = getGroupSubgroupRelationCount(t, n, test.groupToDelete, "") +
+			getGroupSubgroupRelationCount(t, n, "", test.groupToDelete)
+		assert.Equal(t, 2, relations, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetDeletedGroupByName(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousGroups []Group
+		// Postgres Repo Args
+		org  string
+		name string
+		// Expected result
+		expectedResponse *api.Group
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			previousGroups: []Group{
+				{
+					ID:       "GroupID",
+					Name:     "Name",
+					Path:     "Path",
+					Urn:      "Urn",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Org:      "Org",
+					DeleteAt: now.UnixNano(),
+				},
+				{
+					ID:       "GroupID2",
+					Name:     "Name",
+					Path:     "Path",
+					Urn:      "Urn",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Org:      "Org",
+				},
+			},
+			org:  "Org",
+			name: "Name",
+			expectedResponse: &api.Group{
+				ID:       "GroupID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "Urn",
+				CreateAt: now,
+				UpdateAt: now,
+				Org:      "Org",
+			},
+		},
+		"ErrorCaseGroupNotDeleted": {
+			previousGroups: []Group{
+				{
+					ID:       "GroupID",
+					Name:     "Name",
+					Path:     "Path",
+					Urn:      "Urn",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Org:      "Org",
+				},
+			},
+			org:  "Org",
+			name: "Name",
+			expectedError: &database.Error{
+				Code:    database.GROUP_NOT_FOUND,
+				Message: "Deleted group with organization Org and name Name not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean group database
+		cleanGroupTable(t, n)
+
+		// Insert previous data
+		for _, g := range test.previousGroups {
+			insertGroup(t, n, g)
+		}
+		// Call to repository to get a deleted group
+		receivedGroup, err := repoDB.GetDeletedGroupByName(test.org, test.name)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+
+			// Check response
+			assert.Equal(t, test.expectedResponse, receivedGroup, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_RestoreGroup(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousGroup Group
+		// Postgres Repo Args
+		groupToRestore string
+	}{
+		"OkCase": {
+			previousGroup: Group{
+				ID:       "GroupID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "Urn",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Org:      "Org",
+				DeleteAt: now.UnixNano(),
+			},
+			groupToRestore: "GroupID",
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean group database
+		cleanGroupTable(t, n)
+
+		// Insert previous data
+		insertGroup(t, n, test.previousGroup)
+
+		// Call to repository to restore group
+		err := repoDB.RestoreGroup(test.groupToRestore)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check group is active again
+		_, err = repoDB.GetGroupByName(test.previousGroup.Org, test.previousGroup.Name)
+		assert.Nil(t, err, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_PurgeGroups(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousGroups []Group
+		// Postgres Repo Args
+		deleteBefore time.Time
+		// Expected result
+		expectedPurged int
+		expectedGroups int
+	}{
+		"OkCase": {
+			previousGroups: []Group{
+				{
+					ID:       "GroupID",
+					Name:     "Name",
+					Urn:      "Urn",
+					Org:      "Org",
+					DeleteAt: now.Add(-2 * time.Hour).UnixNano(),
+				},
+				{
+					ID:       "GroupID2",
+					Name:     "Name2",
+					Urn:      "Urn2",
+					Org:      "Org",
+					DeleteAt: now.UnixNano(),
+				},
+				{
+					ID:   "GroupID3",
+					Name: "Name3",
+					Urn:  "Urn3",
+					Org:  "Org",
+				},
+			},
+			deleteBefore:   now.Add(-time.Hour),
+			expectedPurged: 1,
+			expectedGroups: 2,
+		},
+	}
+
+	for n, test := range testcases {
+		cleanGroupTable(t, n)
+		cleanGroupUserRelationTable(t, n)
+		cleanGroupPolicyRelationTable(t, n)
+		cleanGroupSubgroupRelationTable(t, n)
 
-		// Check total group policy relations
-		totalRelations = getGroupPolicyRelationCount(t, n, "", "")
-		assert.Equal(t, 1, totalRelations, "Error in test case %v", n)
+		// Insert previous data
+		for _, g := range test.previousGroups {
+			insertGroup(t, n, g)
+			insertGroupUserRelation(t, n, "UserID", g.ID, now.UnixNano())
+			insertGroupPolicyRelation(t, n, g.ID, "PolicyID", now.UnixNano())
+			insertGroupSubgroupRelation(t, n, "ParentID", g.ID, now.UnixNano())
+		}
+		// Call to repository to purge groups
+		purged, err := repoDB.PurgeGroups(test.deleteBefore)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedPurged, purged, "Error in test case %v", n)
+
+		// Check database
+		totalGroupNumber := getGroupsCountFiltered(t, n, "", "", "", 0, 0, "", "")
+		assert.Equal(t, test.expectedGroups, totalGroupNumber, "Error in test case %v", n)
+
+		// Check purged group relations
+		relations := getGroupUserRelations(t, n, "", "")
+		assert.Equal(t, test.expectedGroups, relations, "Error in test case %v", n)
+		relations = getGroupPolicyRelationCount(t, n, "", "")
+		assert.Equal(t, test.expectedGroups, relations, "Error in test case %v", n)
+		relations = getGroupSubgroupRelationCount(t, n, "", "")
+		assert.Equal(t, test.expectedGroups, relations, "Error in test case %v", n)
 	}
 }
 
 func TestPostgresRepo_AddMember(t *testing.T) {
 	testcases := map[string]struct {
+		// Previous data
+		previousMember bool
 		// Postgres Repo Args
 		userID  string
 		groupID string
@@ -793,6 +1143,15 @@ func TestPostgresRepo_AddMember(t *testing.T) {
 			userID:  "UserID",
 			groupID: "GroupID",
 		},
+		"ErrorCaseAlreadyMember": {
+			previousMember: true,
+			userID:         "UserID",
+			groupID:        "GroupID",
+			expectedError: &database.Error{
+				Code:    database.GROUP_USER_RELATION_ALREADY_EXIST,
+				Message: "User with id UserID is already a member of group with id GroupID",
+			},
+		},
 		"ErrorCaseInternalError": {
 			groupID: "GroupID",
 			expectedError: &database.Error{
@@ -806,6 +1165,11 @@ func TestPostgresRepo_AddMember(t *testing.T) {
 		// Clean GroupUserRelation database
 		cleanGroupUserRelationTable(t, n)
 
+		// Insert previous data
+		if test.previousMember {
+			insertGroupUserRelation(t, n, test.userID, test.groupID, time.Now().UTC().UnixNano())
+		}
+
 		// Call to repository to store member
 		err := repoDB.AddMember(test.userID, test.groupID)
 		if test.expectedError != nil {
@@ -1287,6 +1651,7 @@ func TestPostgresRepo_GetAttachedPolicies(t *testing.T) {
 						CreateAt:   now,
 						UpdateAt:   now,
 						Urn:        "Urn2",
+						Version:    1,
 						Statements: &[]api.Statement{},
 					},
 					CreateAt: now,
@@ -1300,6 +1665,7 @@ func TestPostgresRepo_GetAttachedPolicies(t *testing.T) {
 						CreateAt:   now,
 						UpdateAt:   now,
 						Urn:        "Urn1",
+						Version:    1,
 						Statements: &[]api.Statement{},
 					},
 					CreateAt: now.Add(-1),
@@ -1376,3 +1742,339 @@ func TestPostgresRepo_GetAttachedPolicies(t *testing.T) {
 		}
 	}
 }
+
+func TestPostgresRepo_AddSubgroup(t *testing.T) {
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		groupID    string
+		subgroupID string
+		// Expected result
+		expectedError *database.Error
+	}{
+		"OkCase": {
+			groupID:    "GroupID",
+			subgroupID: "SubgroupID",
+		},
+		"ErrorCaseInternalError": {
+			expectedError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "pq: null value in column \"group_id\" violates not-null constraint",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean GroupSubgroupRelation database
+		cleanGroupSubgroupRelationTable(t, n)
+
+		// Call to repository to add subgroup
+		err := repoDB.AddSubgroup(test.groupID, test.subgroupID)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check database
+			relations := getGroupSubgroupRelationCount(t, n, test.groupID, test.subgroupID)
+			assert.Equal(t, 1, relations, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_RemoveSubgroup(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		groupID    string
+		subgroupID string
+	}{
+		"OkCase": {
+			groupID:    "GroupID",
+			subgroupID: "SubgroupID",
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean GroupSubgroupRelation database
+		cleanGroupSubgroupRelationTable(t, n)
+
+		// Insert previous data
+		insertGroupSubgroupRelation(t, n, test.groupID, test.subgroupID, now.UnixNano())
+
+		// Call to repository to remove subgroup
+		err := repoDB.RemoveSubgroup(test.groupID, test.subgroupID)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check database
+		relations := getGroupSubgroupRelationCount(t, n, test.groupID, test.subgroupID)
+		assert.Equal(t, 0, relations, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_IsSubgroupOfGroup(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		subgroupID string
+		groupID    string
+		// Expected result
+		expectedResult bool
+	}{
+		"OkCase": {
+			subgroupID:     "SubgroupID",
+			groupID:        "GroupID",
+			expectedResult: true,
+		},
+		"OkCaseReverseRelation": {
+			subgroupID:     "GroupID",
+			groupID:        "SubgroupID",
+			expectedResult: false,
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean GroupSubgroupRelation database
+		cleanGroupSubgroupRelationTable(t, n)
+
+		// Insert previous data
+		insertGroupSubgroupRelation(t, n, "GroupID", "SubgroupID", now.UnixNano())
+
+		// Call repository to check if group is a subgroup
+		result, err := repoDB.IsSubgroupOfGroup(test.subgroupID, test.groupID)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResult, result, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetSubgroups(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		subgroups       []Group
+		createAt        []int64
+		subgroupMissing bool
+		// Postgres Repo Args
+		groupID string
+		filter  *api.Filter
+		// Expected result
+		expectedResponse []*GroupSubgroup
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			subgroups: []Group{
+				{
+					ID:       "GroupID1",
+					Name:     "Name1",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      "Urn1",
+				},
+				{
+					ID:       "GroupID2",
+					Name:     "Name2",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      "Urn2",
+				},
+			},
+			createAt: []int64{now.UnixNano() - 1, now.UnixNano()},
+			groupID:  "ParentID",
+			filter: &api.Filter{
+				OrderBy: "create_at desc",
+			},
+			expectedResponse: []*GroupSubgroup{
+				{
+					Subgroup: &api.Group{
+						ID:       "GroupID2",
+						Name:     "Name2",
+						Org:      "org1",
+						Path:     "/path/",
+						CreateAt: now,
+						UpdateAt: now,
+						Urn:      "Urn2",
+					},
+					CreateAt: now,
+				},
+				{
+					Subgroup: &api.Group{
+						ID:       "GroupID1",
+						Name:     "Name1",
+						Org:      "org1",
+						Path:     "/path/",
+						CreateAt: now,
+						UpdateAt: now,
+						Urn:      "Urn1",
+					},
+					CreateAt: now.Add(-1),
+				},
+			},
+		},
+		"ErrorCase": {
+			subgroups: []Group{
+				{
+					ID: "GroupID1",
+				},
+			},
+			createAt:        []int64{now.UnixNano()},
+			subgroupMissing: true,
+			groupID:         "ParentID",
+			filter:          testFilter,
+			expectedError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Code: GroupNotFound, Message: Group with id GroupID1 not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		cleanGroupTable(t, n)
+		cleanGroupSubgroupRelationTable(t, n)
+
+		// Insert previous data
+		for i, g := range test.subgroups {
+			insertGroupSubgroupRelation(t, n, test.groupID, g.ID, test.createAt[i])
+			if !test.subgroupMissing {
+				insertGroup(t, n, g)
+			}
+		}
+
+		receivedSubgroups, total, err := repoDB.GetSubgroups(test.groupID, test.filter)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+
+			// Check total
+			assert.Equal(t, len(test.expectedResponse), total, "Error in test case %v", n)
+
+			// Check response
+			for i, r := range receivedSubgroups {
+				assert.Equal(t, test.expectedResponse[i].GetSubgroup(), r.GetSubgroup(), "Error in test case %v", n)
+				assert.Equal(t, test.expectedResponse[i].GetDate(), r.GetDate(), "Error in test case %v", n)
+			}
+		}
+	}
+}
+
+func TestPostgresRepo_GetParentGroups(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		groups []Group
+		// Postgres Repo Args
+		groupID string
+		// Expected result
+		expectedResponse []api.Group
+	}{
+		"OkCase": {
+			groups: []Group{
+				{
+					ID:       "ParentID",
+					Name:     "Parent",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      "Urn1",
+				},
+				{
+					ID:       "OtherID",
+					Name:     "Other",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      "Urn2",
+				},
+			},
+			groupID: "ChildID",
+			expectedResponse: []api.Group{
+				{
+					ID:       "ParentID",
+					Name:     "Parent",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now,
+					UpdateAt: now,
+					Urn:      "Urn1",
+				},
+			},
+		},
+		"OkCaseNoParents": {
+			groupID:          "ParentID",
+			expectedResponse: []api.Group{},
+		},
+	}
+
+	for n, test := range testcases {
+		cleanGroupTable(t, n)
+		cleanGroupSubgroupRelationTable(t, n)
+
+		// Insert previous data
+		for _, g := range test.groups {
+			insertGroup(t, n, g)
+		}
+		insertGroupSubgroupRelation(t, n, "ParentID", "ChildID", now.UnixNano())
+		insertGroupSubgroupRelation(t, n, "ChildID", "OtherID", now.UnixNano())
+
+		groups, err := repoDB.GetParentGroups(test.groupID)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, groups, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetParentGroupIDs(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		groupID string
+		// Expected result
+		expectedResponse []string
+	}{
+		"OkCaseDeletedParent": {
+			groupID:          "ChildID",
+			expectedResponse: []string{"ParentID"},
+		},
+		"OkCaseNoParents": {
+			groupID:          "ParentID",
+			expectedResponse: []string{},
+		},
+	}
+
+	for n, test := range testcases {
+		cleanGroupTable(t, n)
+		cleanGroupSubgroupRelationTable(t, n)
+
+		// Insert previous data
+		insertGroup(t, n, Group{
+			ID:       "ParentID",
+			Name:     "Parent",
+			Org:      "org1",
+			Path:     "/path/",
+			CreateAt: now.UnixNano(),
+			UpdateAt: now.UnixNano(),
+			DeleteAt: now.UnixNano(),
+			Urn:      "Urn1",
+		})
+		insertGroupSubgroupRelation(t, n, "ParentID", "ChildID", now.UnixNano())
+
+		groupIDs, err := repoDB.GetParentGroupIDs(test.groupID)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, groupIDs, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_LockGroupHierarchy(t *testing.T) {
+	// Lock is held until the transaction ends
+	err := repoDB.WithTransaction(func(repos api.Repos) error {
+		return repos.GroupRepo.LockGroupHierarchy("org1")
+	})
+	assert.Nil(t, err, "Error locking group hierarchy")
+	err = repoDB.LockGroupHierarchy("org1")
+	assert.Nil(t, err, "Error locking group hierarchy")
+}
@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/lib/pq"
+)
+
+const (
+	// Channel notified by the database when proxy resources change
+	PROXY_RESOURCES_CHANNEL = "foulkon_proxy_resources"
+
+	// Bounds of the wait between reconnections of listeners
+	LISTENER_MIN_RECONNECT = time.Second
+	LISTENER_MAX_RECONNECT = time.Minute
+
+	// Time between checks of idle listener connections, lost ones aren't detected otherwise
+	LISTENER_PING_INTERVAL = 90 * time.Second
+)
+
+// ProxyResourcesListener implements api.ProxyResourcesWatcher with notifications sent by the
+// database triggers of proxy resources
+type ProxyResourcesListener struct {
+	listener *pq.Listener
+	changes  chan struct{}
+}
+
+// NewProxyResourcesListener opens a connection to the database to listen to changes of proxy resources.
+// The connection is reestablished if it's lost.
+func NewProxyResourcesListener(datasourcename string) (*ProxyResourcesListener, error) {
+	listener := pq.NewListener(datasourcename, LISTENER_MIN_RECONNECT, LISTENER_MAX_RECONNECT, logListenerEvent)
+	if err := listener.Listen(PROXY_RESOURCES_CHANNEL); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	prl := &ProxyResourcesListener{
+		listener: listener,
+		// Buffered so changes received while resources are reloaded aren't lost
+		changes: make(chan struct{}, 1),
+	}
+	go prl.run()
+
+	return prl, nil
+}
+
+func (prl *ProxyResourcesListener) Changes() <-chan struct{} {
+	return prl.changes
+}
+
+func (prl *ProxyResourcesListener) Close() error {
+	return prl.listener.Close()
+}
+
+// run forwards notifications until the listener is closed. Notifications received while a change is
+// pending are merged into it.
+func (prl *ProxyResourcesListener) run() {
+	ticker := time.NewTicker(LISTENER_PING_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		// Nil notifications are received after reconnections
+		case _, ok := <-prl.listener.Notify:
+			if !ok {
+				close(prl.changes)
+				return
+			}
+			select {
+			case prl.changes <- struct{}{}:
+			default:
+			}
+		case <-ticker.C:
+			go prl.listener.Ping()
+		}
+	}
+}
+
+func logListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		api.Log.Warnf("Connection of proxy resources listener lost: %v", err)
+	case pq.ListenerEventReconnected:
+		api.Log.Info("Connection of proxy resources listener reestablished")
+	case pq.ListenerEventConnectionAttemptFailed:
+		api.Log.Errorf("Unable to connect proxy resources listener: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyResourcesListener(t *testing.T) {
+	now := time.Now().UTC()
+	cleanProxyResourcesTable(t, "ProxyResourcesListener")
+
+	listener, err := NewProxyResourcesListener(testDatasourcename)
+	assert.Nil(t, err)
+
+	testcases := map[string]func() error{
+		"OkCaseAdd": func() error {
+			_, err := repoDB.AddProxyResource(api.ProxyResource{
+				ID:   "ID",
+				Name: "name",
+				Org:  "org",
+				Path: "/path/",
+				Urn:  "urn",
+				Resource: api.ResourceEntity{
+					Host:   "host",
+					Path:   "/resource",
+					Method: "GET",
+					Urn:    "urn",
+					Action: "action",
+				},
+				CreateAt: now,
+				UpdateAt: now,
+			})
+			return err
+		},
+		"OkCaseRemove": func() error {
+			return repoDB.RemoveProxyResource("ID", 1)
+		},
+	}
+
+	for _, n := range []string{"OkCaseAdd", "OkCaseRemove"} {
+		assert.Nil(t, testcases[n](), "Error in test case %v", n)
+		select {
+		case _, ok := <-listener.Changes():
+			assert.True(t, ok, "Error in test case %v", n)
+		case <-time.After(5 * time.Second):
+			t.Errorf("Test %v failed. Change not notified", n)
+		}
+	}
+
+	// Changes channel is closed with the listener
+	assert.Nil(t, listener.Close())
+	_, ok := <-listener.Changes()
+	assert.False(t, ok)
+}
@@ -0,0 +1,541 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/jinzhu/gorm"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// Key of the advisory lock held while migrations run
+	MIGRATION_LOCK_ID = 4350411
+)
+
+// migration changes the database schema from version-1 to version (up) and back (down).
+// Both functions run inside the transaction of the migration process.
+type migration struct {
+	version     int
+	description string
+	up          func(tx *gorm.DB) error
+	down        func(tx *gorm.DB) error
+}
+
+// Ordered list of schema migrations. New migrations must be appended with the next version,
+// existing ones must never be changed once released.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "Create initial schema",
+		up:          createInitialSchema,
+		down:        dropInitialSchema,
+	},
+	{
+		version:     2,
+		description: "Add soft deletion of users, groups and policies",
+		up:          addSoftDeletion,
+		down:        dropSoftDeletion,
+	},
+	{
+		version:     3,
+		description: "Add revisions of users, groups, policies and proxy resources",
+		up:          addRevisions,
+		down:        dropRevisions,
+	},
+	{
+		version:     4,
+		description: "Add unique indexes of group, policy, proxy resource and OIDC provider names",
+		up:          addNameUniqueKeys,
+		down:        dropNameUniqueKeys,
+	},
+	{
+		version:     5,
+		description: "Add webhooks and their deliveries",
+		up:          addWebhooks,
+		down:        dropWebhooks,
+	},
+	{
+		version:     6,
+		description: "Notify changes of proxy resources",
+		up:          addProxyResourcesNotify,
+		down:        dropProxyResourcesNotify,
+	},
+}
+
+// SchemaMigration table
+type SchemaMigration struct {
+	Version     int    `gorm:"primary_key"`
+	Description string `gorm:"not null"`
+	AppliedAt   int64  `gorm:"not null"`
+}
+
+// SchemaMigration's table name
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// LatestSchemaVersion returns the schema version required by this binary
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// GetSchemaVersion returns the current schema version of the database, 0 if it was never migrated
+func GetSchemaVersion(db *gorm.DB) (int, error) {
+	exist, err := hasTable(db, SchemaMigration{}.TableName())
+	if err != nil || !exist {
+		return 0, err
+	}
+	var version int
+	if err := db.Raw("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Row().Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// CheckSchemaVersion returns an error if the database schema version doesn't match the one required by this binary
+func CheckSchemaVersion(db *gorm.DB) error {
+	version, err := GetSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if version != LatestSchemaVersion() {
+		return fmt.Errorf("Database schema version is %v but this binary requires version %v, run worker migrate command",
+			version, LatestSchemaVersion())
+	}
+	return nil
+}
+
+// MigrateDb applies up or down migrations until the database schema reaches version, or the latest version
+// if it's negative. Migrations run in a single transaction that holds an advisory lock, so concurrent
+// runners wait for each other. It returns the schema versions before and after the migration.
+func MigrateDb(db *gorm.DB, version int) (int, int, error) {
+	if version < 0 {
+		version = LatestSchemaVersion()
+	}
+	if version > LatestSchemaVersion() {
+		return 0, 0, fmt.Errorf("Invalid schema version %v, latest version is %v", version, LatestSchemaVersion())
+	}
+
+	transaction := db.Begin()
+	if err := transaction.Error; err != nil {
+		return 0, 0, err
+	}
+	from, err := migrate(transaction, version)
+	if err != nil {
+		transaction.Rollback()
+		return 0, 0, err
+	}
+	if err := transaction.Commit().Error; err != nil {
+		return 0, 0, err
+	}
+
+	return from, version, nil
+}
+
+// Aux method that runs migrations inside transaction and returns the initial schema version
+func migrate(transaction *gorm.DB, version int) (int, error) {
+	if err := transaction.Exec("SELECT pg_advisory_xact_lock(?)", MIGRATION_LOCK_ID).Error; err != nil {
+		return 0, err
+	}
+	if err := transaction.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version integer, description text NOT NULL, " +
+		"applied_at bigint NOT NULL, PRIMARY KEY (version))").Error; err != nil {
+		return 0, err
+	}
+
+	current, err := GetSchemaVersion(transaction)
+	if err != nil {
+		return 0, err
+	}
+	if current > LatestSchemaVersion() {
+		return 0, fmt.Errorf("Database schema version %v is newer than latest version %v known by this binary",
+			current, LatestSchemaVersion())
+	}
+
+	// Upgrade
+	for _, m := range migrations {
+		if m.version <= current || m.version > version {
+			continue
+		}
+		if err := m.up(transaction); err != nil {
+			return 0, fmt.Errorf("Error applying migration %v (%v): %v", m.version, m.description, err)
+		}
+		if err := transaction.Create(&SchemaMigration{
+			Version:     m.version,
+			Description: m.description,
+			AppliedAt:   time.Now().UTC().UnixNano(),
+		}).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	// Downgrade
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.version > current || m.version <= version {
+			continue
+		}
+		if err := m.down(transaction); err != nil {
+			return 0, fmt.Errorf("Error reverting migration %v (%v): %v", m.version, m.description, err)
+		}
+		if err := transaction.Where("version = ?", m.version).Delete(&SchemaMigration{}).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	return current, nil
+}
+
+// MIGRATIONS
+
+// Tables of the initial schema, in creation order
+var initialSchemaTables = []string{
+	"CREATE TABLE IF NOT EXISTS users (id text, external_id text NOT NULL UNIQUE, path text NOT NULL, " +
+		"create_at bigint NOT NULL, update_at bigint NOT NULL, urn text NOT NULL UNIQUE, PRIMARY KEY (id))",
+	"CREATE TABLE IF NOT EXISTS groups (id text, name text NOT NULL, path text NOT NULL, org text NOT NULL, " +
+		"create_at bigint NOT NULL, update_at bigint NOT NULL, urn text NOT NULL UNIQUE, PRIMARY KEY (id))",
+	"CREATE TABLE IF NOT EXISTS policies (id text, name text NOT NULL, path text NOT NULL, org text NOT NULL, " +
+		"create_at bigint NOT NULL, update_at bigint NOT NULL, urn text NOT NULL UNIQUE, version integer NOT NULL DEFAULT 1, " +
+		"PRIMARY KEY (id))",
+	"CREATE TABLE IF NOT EXISTS policy_versions (id text, policy_id text NOT NULL, version integer NOT NULL, " +
+		"author text NOT NULL, create_at bigint NOT NULL, PRIMARY KEY (id))",
+	"CREATE UNIQUE INDEX IF NOT EXISTS idx_policy_version ON policy_versions (policy_id, version)",
+	"CREATE TABLE IF NOT EXISTS statements (id text, policy_version_id text NOT NULL, effect text NOT NULL, " +
+		"actions text NOT NULL, not_actions text NOT NULL DEFAULT '', resources text NOT NULL, " +
+		"not_resources text NOT NULL DEFAULT '', PRIMARY KEY (id))",
+	"CREATE TABLE IF NOT EXISTS group_user_relations (user_id text, group_id text, create_at bigint NOT NULL, " +
+		"PRIMARY KEY (user_id, group_id))",
+	"CREATE TABLE IF NOT EXISTS group_subgroup_relations (group_id text, subgroup_id text, create_at bigint NOT NULL, " +
+		"PRIMARY KEY (group_id, subgroup_id))",
+	"CREATE TABLE IF NOT EXISTS group_policy_relations (group_id text, policy_id text, create_at bigint NOT NULL, " +
+		"PRIMARY KEY (group_id, policy_id))",
+	"CREATE TABLE IF NOT EXISTS user_policy_relations (user_id text, policy_id text, create_at bigint NOT NULL, " +
+		"PRIMARY KEY (user_id, policy_id))",
+	"CREATE TABLE IF NOT EXISTS proxy_resources (id text, name text NOT NULL, org text NOT NULL, path text NOT NULL, " +
+		"host text NOT NULL, path_resource text NOT NULL, method text NOT NULL, urn_resource text NOT NULL, " +
+		"urn text NOT NULL, action text NOT NULL, create_at bigint NOT NULL, update_at bigint NOT NULL, PRIMARY KEY (id))",
+	"CREATE UNIQUE INDEX IF NOT EXISTS idx_resource ON proxy_resources (host, path_resource, method, urn_resource, action)",
+	"CREATE TABLE IF NOT EXISTS oidc_providers (id text, name text NOT NULL, path text NOT NULL, urn text NOT NULL UNIQUE, " +
+		"create_at bigint NOT NULL, update_at bigint NOT NULL, issuer_url text NOT NULL, PRIMARY KEY (id))",
+	"CREATE TABLE IF NOT EXISTS oidc_clients (id text, oidc_provider_id text NOT NULL, name text NOT NULL, PRIMARY KEY (id))",
+	"CREATE UNIQUE INDEX IF NOT EXISTS idx_oidc_client ON oidc_clients (oidc_provider_id, name)",
+	"CREATE TABLE IF NOT EXISTS organizations (id text, name text NOT NULL UNIQUE, path text NOT NULL, " +
+		"create_at bigint NOT NULL, update_at bigint NOT NULL, urn text NOT NULL UNIQUE, PRIMARY KEY (id))",
+}
+
+// Columns added to existing tables by AutoMigrate before schema migrations
+var initialSchemaColumns = []string{
+	"ALTER TABLE policies ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1",
+	"ALTER TABLE statements ADD COLUMN IF NOT EXISTS not_actions text NOT NULL DEFAULT ''",
+	"ALTER TABLE statements ADD COLUMN IF NOT EXISTS not_resources text NOT NULL DEFAULT ''",
+}
+
+// createInitialSchema creates the schema of this version on new databases. Databases created by
+// AutoMigrate in previous versions are upgraded to the same schema, so they can be migrated too.
+func createInitialSchema(tx *gorm.DB) error {
+	// Check legacy schemas before changing anything
+	statementsByPolicy, err := hasColumn(tx, Statement{}.TableName(), "policy_id")
+	if err != nil {
+		return err
+	}
+	organizationsExist, err := hasTable(tx, Organization{}.TableName())
+	if err != nil {
+		return err
+	}
+
+	for _, query := range append(initialSchemaTables, initialSchemaColumns...) {
+		if err := tx.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+
+	if statementsByPolicy {
+		if err := migrateStatementsToPolicyVersions(tx); err != nil {
+			return err
+		}
+	}
+	if !organizationsExist {
+		return migrateOrganizations(tx)
+	}
+	return nil
+}
+
+func dropInitialSchema(tx *gorm.DB) error {
+	for _, table := range []string{"organizations", "oidc_clients", "oidc_providers", "proxy_resources",
+		"user_policy_relations", "group_policy_relations", "group_subgroup_relations", "group_user_relations",
+		"statements", "policy_versions", "policies", "groups", "users"} {
+		if err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %v", table)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Statements were stored by policy before policy versioning. This aux method creates the first version
+// of every existing policy and moves its statements to that version.
+func migrateStatementsToPolicyVersions(tx *gorm.DB) error {
+	for _, query := range []string{
+		"INSERT INTO policy_versions (id, policy_id, version, author, create_at) " +
+			"SELECT id, id, 1, '', update_at FROM policies",
+		"ALTER TABLE statements ADD COLUMN IF NOT EXISTS policy_version_id text",
+		"UPDATE statements SET policy_version_id = policy_id",
+		"ALTER TABLE statements ALTER COLUMN policy_version_id SET NOT NULL",
+		"ALTER TABLE statements DROP COLUMN policy_id",
+	} {
+		if err := tx.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Organizations were only a column of groups, policies and proxy resources before they were managed.
+// This aux method creates an organization for every org already in use.
+func migrateOrganizations(tx *gorm.DB) error {
+	orgs := map[string]bool{}
+	for _, table := range []string{Group{}.TableName(), Policy{}.TableName(), ProxyResource{}.TableName()} {
+		names := []string{}
+		if err := tx.Table(table).Pluck("DISTINCT org", &names).Error; err != nil {
+			return err
+		}
+		for _, name := range names {
+			orgs[name] = true
+		}
+	}
+
+	now := time.Now().UTC().UnixNano()
+	for name := range orgs {
+		org := &Organization{
+			ID:       uuid.NewV4().String(),
+			Name:     name,
+			Path:     "/",
+			CreateAt: now,
+			UpdateAt: now,
+			Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/", name),
+		}
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unique constraints of the initial schema are replaced by unique indexes of not deleted rows,
+// so names of deleted entities can be reused before they are purged
+var softDeletionUniqueKeys = []struct {
+	table, column, constraint, index string
+}{
+	{"users", "external_id", "users_external_id_key", "idx_users_external_id"},
+	{"users", "urn", "users_urn_key", "idx_users_urn"},
+	{"groups", "urn", "groups_urn_key", "idx_groups_urn"},
+	{"policies", "urn", "policies_urn_key", "idx_policies_urn"},
+}
+
+func addSoftDeletion(tx *gorm.DB) error {
+	queries := []string{
+		"ALTER TABLE users ADD COLUMN delete_at bigint NOT NULL DEFAULT 0",
+		"ALTER TABLE groups ADD COLUMN delete_at bigint NOT NULL DEFAULT 0",
+		"ALTER TABLE policies ADD COLUMN delete_at bigint NOT NULL DEFAULT 0",
+	}
+	for _, key := range softDeletionUniqueKeys {
+		queries = append(queries,
+			fmt.Sprintf("ALTER TABLE %v DROP CONSTRAINT IF EXISTS %v", key.table, key.constraint),
+			fmt.Sprintf("CREATE UNIQUE INDEX %v ON %v (%v) WHERE delete_at = 0", key.index, key.table, key.column))
+	}
+
+	for _, query := range queries {
+		if err := tx.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropSoftDeletion deletes all soft deleted entities with their relations before removing the columns
+func dropSoftDeletion(tx *gorm.DB) error {
+	deletedUsers := "SELECT id FROM users WHERE delete_at > 0"
+	deletedGroups := "SELECT id FROM groups WHERE delete_at > 0"
+	deletedPolicies := "SELECT id FROM policies WHERE delete_at > 0"
+	queries := []string{
+		"DELETE FROM group_user_relations WHERE user_id IN (" + deletedUsers + ") OR group_id IN (" + deletedGroups + ")",
+		"DELETE FROM group_subgroup_relations WHERE group_id IN (" + deletedGroups + ") OR subgroup_id IN (" + deletedGroups + ")",
+		"DELETE FROM group_policy_relations WHERE group_id IN (" + deletedGroups + ") OR policy_id IN (" + deletedPolicies + ")",
+		"DELETE FROM user_policy_relations WHERE user_id IN (" + deletedUsers + ") OR policy_id IN (" + deletedPolicies + ")",
+		"DELETE FROM statements WHERE policy_version_id IN (SELECT id FROM policy_versions WHERE policy_id IN (" +
+			deletedPolicies + "))",
+		"DELETE FROM policy_versions WHERE policy_id IN (" + deletedPolicies + ")",
+		"DELETE FROM users WHERE delete_at > 0",
+		"DELETE FROM groups WHERE delete_at > 0",
+		"DELETE FROM policies WHERE delete_at > 0",
+	}
+	for _, key := range softDeletionUniqueKeys {
+		queries = append(queries,
+			fmt.Sprintf("DROP INDEX %v", key.index),
+			fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT %v UNIQUE (%v)", key.table, key.constraint, key.column))
+	}
+	queries = append(queries,
+		"ALTER TABLE users DROP COLUMN delete_at",
+		"ALTER TABLE groups DROP COLUMN delete_at",
+		"ALTER TABLE policies DROP COLUMN delete_at",
+	)
+
+	for _, query := range queries {
+		if err := tx.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Aux methods to inspect the schema using the given connection, so they see changes of its transaction
+func hasTable(db *gorm.DB, table string) (bool, error) {
+	var count int
+	err := db.Raw("SELECT count(*) FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name = ?",
+		table).Row().Scan(&count)
+	return count > 0, err
+}
+
+func hasColumn(db *gorm.DB, table string, column string) (bool, error) {
+	var count int
+	err := db.Raw("SELECT count(*) FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? "+
+		"AND column_name = ?", table, column).Row().Scan(&count)
+	return count > 0, err
+}
+
+// Tables of entities that carry a revision for optimistic concurrency
+var revisionTables = []string{"users", "groups", "policies", "proxy_resources"}
+
+func addRevisions(tx *gorm.DB) error {
+	for _, table := range revisionTables {
+		if err := tx.Exec(fmt.Sprintf("ALTER TABLE %v ADD COLUMN revision integer NOT NULL DEFAULT 1", table)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropRevisions(tx *gorm.DB) error {
+	for _, table := range revisionTables {
+		if err := tx.Exec(fmt.Sprintf("ALTER TABLE %v DROP COLUMN revision", table)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Names were only checked by the API before creating or renaming entities, these unique indexes
+// reject the duplicates stored by concurrent requests
+var nameUniqueKeys = []struct {
+	table, columns, index, where string
+}{
+	{"groups", "org, name", "idx_groups_org_name", "WHERE delete_at = 0"},
+	{"policies", "org, name", "idx_policies_org_name", "WHERE delete_at = 0"},
+	{"proxy_resources", "org, name", "idx_proxy_resources_org_name", ""},
+	{"oidc_providers", "name", "idx_oidc_providers_name", ""},
+}
+
+func addNameUniqueKeys(tx *gorm.DB) error {
+	// Duplicates stored before would make index creation fail with a constraint error, list all of them instead
+	duplicates := []string{}
+	for _, key := range nameUniqueKeys {
+		rows, err := tx.Raw(fmt.Sprintf("SELECT concat_ws(', ', %v), count(*) FROM %v %v GROUP BY %v HAVING count(*) > 1 ORDER BY 1",
+			key.columns, key.table, key.where, key.columns)).Rows()
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var values string
+			var count int
+			if err := rows.Scan(&values, &count); err != nil {
+				rows.Close()
+				return err
+			}
+			duplicates = append(duplicates, fmt.Sprintf("%v (%v) = (%v) in %v rows", key.table, key.columns, values, count))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("Names must be unique, rename or delete the duplicated entities and run migrate again: %v",
+			strings.Join(duplicates, "; "))
+	}
+
+	for _, key := range nameUniqueKeys {
+		if err := tx.Exec(fmt.Sprintf("CREATE UNIQUE INDEX %v ON %v (%v) %v", key.index, key.table, key.columns, key.where)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropNameUniqueKeys(tx *gorm.DB) error {
+	for _, key := range nameUniqueKeys {
+		if err := tx.Exec(fmt.Sprintf("DROP INDEX %v", key.index)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var webhookTables = []string{
+	"CREATE TABLE webhooks (id text, name text NOT NULL UNIQUE, url text NOT NULL, secret text NOT NULL, " +
+		"events text NOT NULL, create_at bigint NOT NULL, update_at bigint NOT NULL, PRIMARY KEY (id))",
+	"CREATE TABLE webhook_deliveries (id text, webhook_id text NOT NULL, event_id text NOT NULL, " +
+		"event_type text NOT NULL, payload text NOT NULL, status text NOT NULL, attempts integer NOT NULL DEFAULT 0, " +
+		"response_code integer NOT NULL DEFAULT 0, error text NOT NULL DEFAULT '', next_attempt_at bigint NOT NULL, " +
+		"create_at bigint NOT NULL, update_at bigint NOT NULL, PRIMARY KEY (id))",
+	"CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, create_at)",
+	// Only pending deliveries are read by the dispatcher
+	"CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at) WHERE status = 'pending'",
+}
+
+func addWebhooks(tx *gorm.DB) error {
+	for _, query := range webhookTables {
+		if err := tx.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropWebhooks(tx *gorm.DB) error {
+	for _, table := range []string{"webhook_deliveries", "webhooks"} {
+		if err := tx.Exec(fmt.Sprintf("DROP TABLE %v", table)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Statement triggers notify once per change, and notifications of a transaction are sent on commit
+var proxyResourcesNotify = []string{
+	fmt.Sprintf("CREATE FUNCTION notify_proxy_resources() RETURNS trigger AS $$ "+
+		"BEGIN PERFORM pg_notify('%v', TG_OP); RETURN NULL; END; $$ LANGUAGE plpgsql", PROXY_RESOURCES_CHANNEL),
+	"CREATE TRIGGER proxy_resources_notify AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON proxy_resources " +
+		"FOR EACH STATEMENT EXECUTE PROCEDURE notify_proxy_resources()",
+}
+
+func addProxyResourcesNotify(tx *gorm.DB) error {
+	for _, query := range proxyResourcesNotify {
+		if err := tx.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropProxyResourcesNotify(tx *gorm.DB) error {
+	for _, query := range []string{
+		"DROP TRIGGER proxy_resources_notify ON proxy_resources",
+		"DROP FUNCTION notify_proxy_resources()",
+	} {
+		if err := tx.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateDb(t *testing.T) {
+	testcases := map[string]struct {
+		// Schema version before the test
+		initialVersion int
+		// Method args
+		version int
+		// Expected result
+		expectedFrom  int
+		expectedTo    int
+		expectedError error
+	}{
+		"OkCaseUpgradeFromEmptyDatabase": {
+			initialVersion: 0,
+			version:        -1,
+			expectedFrom:   0,
+			expectedTo:     LatestSchemaVersion(),
+		},
+		"OkCaseAlreadyMigrated": {
+			initialVersion: LatestSchemaVersion(),
+			version:        LatestSchemaVersion(),
+			expectedFrom:   LatestSchemaVersion(),
+			expectedTo:     LatestSchemaVersion(),
+		},
+		"OkCaseDowngrade": {
+			initialVersion: LatestSchemaVersion(),
+			version:        0,
+			expectedFrom:   LatestSchemaVersion(),
+			expectedTo:     0,
+		},
+		"OkCaseDowngradeOneVersion": {
+			initialVersion: LatestSchemaVersion(),
+			version:        LatestSchemaVersion() - 1,
+			expectedFrom:   LatestSchemaVersion(),
+			expectedTo:     LatestSchemaVersion() - 1,
+		},
+		"ErrorCaseUnknownVersion": {
+			initialVersion: LatestSchemaVersion(),
+			version:        LatestSchemaVersion() + 1,
+			expectedError: fmt.Errorf("Invalid schema version %v, latest version is %v",
+				LatestSchemaVersion()+1, LatestSchemaVersion()),
+		},
+	}
+
+	for n, test := range testcases {
+		_, _, err := MigrateDb(repoDB.Dbmap, test.initialVersion)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		from, to, err := MigrateDb(repoDB.Dbmap, test.version)
+		if test.expectedError != nil {
+			assert.Equal(t, test.expectedError, err, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedFrom, from, "Error in test case %v", n)
+			assert.Equal(t, test.expectedTo, to, "Error in test case %v", n)
+		}
+
+		// Check database
+		version, err := GetSchemaVersion(repoDB.Dbmap)
+		assert.Nil(t, err, "Error in test case %v", n)
+		if test.expectedError == nil {
+			assert.Equal(t, test.expectedTo, version, "Error in test case %v", n)
+			exist, err := hasTable(repoDB.Dbmap, User{}.TableName())
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedTo > 0, exist, "Error in test case %v", n)
+		}
+	}
+
+	// Leave database migrated for other tests
+	_, _, err := MigrateDb(repoDB.Dbmap, -1)
+	assert.Nil(t, err, "Error migrating database")
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	testcases := map[string]struct {
+		// Schema version before the test
+		version int
+		// Expected result
+		expectedError error
+	}{
+		"OkCase": {
+			version: LatestSchemaVersion(),
+		},
+		"ErrorCaseNotMigrated": {
+			version: 0,
+			expectedError: fmt.Errorf("Database schema version is 0 but this binary requires version %v, "+
+				"run worker migrate command", LatestSchemaVersion()),
+		},
+	}
+
+	for n, test := range testcases {
+		_, _, err := MigrateDb(repoDB.Dbmap, test.version)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		err = CheckSchemaVersion(repoDB.Dbmap)
+		assert.Equal(t, test.expectedError, err, "Error in test case %v", n)
+	}
+
+	// Leave database migrated for other tests
+	_, _, err := MigrateDb(repoDB.Dbmap, -1)
+	assert.Nil(t, err, "Error migrating database")
+}
+
+func TestMigrateDbDuplicatedNames(t *testing.T) {
+	// Schema version before unique name indexes
+	_, _, err := MigrateDb(repoDB.Dbmap, 3)
+	assert.Nil(t, err, "Error migrating database")
+	cleanGroupTable(t, "DuplicatedNames")
+	insertGroup(t, "DuplicatedNames", Group{ID: "GroupID1", Name: "group1", Path: "/path/", Org: "org1", Revision: 1})
+	insertGroup(t, "DuplicatedNames", Group{ID: "GroupID2", Name: "group1", Path: "/path2/", Org: "org1", Revision: 1})
+	// Removed groups aren't duplicates
+	insertGroup(t, "DuplicatedNames", Group{ID: "GroupID3", Name: "group2", Path: "/path/", Org: "org1", Revision: 1})
+	insertGroup(t, "DuplicatedNames", Group{ID: "GroupID4", Name: "group2", Path: "/path/", Org: "org1", DeleteAt: 1, Revision: 1})
+
+	_, _, err = MigrateDb(repoDB.Dbmap, -1)
+	assert.Equal(t, fmt.Errorf("Error applying migration 4 (Add unique indexes of group, policy, proxy resource and OIDC provider names): "+
+		"Names must be unique, rename or delete the duplicated entities and run migrate again: "+
+		"groups (org, name) = (org1, group1) in 2 rows"), err, "Error migrating database")
+	version, err := GetSchemaVersion(repoDB.Dbmap)
+	assert.Nil(t, err, "Error getting schema version")
+	assert.Equal(t, 3, version, "Error getting schema version")
+
+	// Leave database migrated for other tests
+	cleanGroupTable(t, "DuplicatedNames")
+	_, _, err = MigrateDb(repoDB.Dbmap, -1)
+	assert.Nil(t, err, "Error migrating database")
+}
@@ -0,0 +1,167 @@
+package postgresql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+)
+
+// ORGANIZATION REPOSITORY IMPLEMENTATION
+
+func (pr PostgresRepo) AddOrganization(org api.Organization) (*api.Organization, error) {
+	// Create organization model
+	orgDB := &Organization{
+		ID:       org.ID,
+		Name:     org.Name,
+		Path:     org.Path,
+		CreateAt: org.CreateAt.UnixNano(),
+		UpdateAt: org.UpdateAt.UnixNano(),
+		Urn:      org.Urn,
+	}
+
+	// Store organization
+	err := pr.Dbmap.Create(orgDB).Error
+
+	// Error handling
+	if err != nil {
+		return nil, storeError(err, database.ORGANIZATION_ALREADY_EXIST,
+			fmt.Sprintf("Organization with name %v already exist", org.Name))
+	}
+
+	return dbOrganizationToAPIOrganization(orgDB), nil
+}
+
+func (pr PostgresRepo) GetOrganizationByName(name string) (*api.Organization, error) {
+	org := &Organization{}
+	query := pr.lockRows().Where("name like ?", name).First(org)
+
+	// Check if organization exists
+	if query.RecordNotFound() {
+		return nil, &database.Error{
+			Code:    database.ORGANIZATION_NOT_FOUND,
+			Message: fmt.Sprintf("Organization with name %v not found", name),
+		}
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return dbOrganizationToAPIOrganization(org), nil
+}
+
+func (pr PostgresRepo) GetOrganizationsFiltered(filter *api.Filter) ([]api.Organization, int, error) {
+	var total int
+	orgs := []Organization{}
+	query := pr.Dbmap
+
+	if len(filter.PathPrefix) > 0 {
+		query = query.Where("path like ?", filter.PathPrefix+"%")
+	}
+	if len(filter.OrderBy) > 0 {
+		query = query.Order(filter.OrderBy)
+	}
+
+	// Error handling
+	if err := query.Find(&orgs).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&orgs).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Transform organizations to API
+	var apiOrgs []api.Organization
+	if orgs != nil {
+		apiOrgs = make([]api.Organization, len(orgs), cap(orgs))
+		for i, o := range orgs {
+			apiOrgs[i] = *dbOrganizationToAPIOrganization(&o)
+		}
+	}
+
+	return apiOrgs, total, nil
+}
+
+func (pr PostgresRepo) UpdateOrganization(org api.Organization) (*api.Organization, error) {
+	orgDB := Organization{
+		ID:       org.ID,
+		Name:     org.Name,
+		Path:     org.Path,
+		CreateAt: org.CreateAt.UnixNano(),
+		UpdateAt: org.UpdateAt.UnixNano(),
+		Urn:      org.Urn,
+	}
+
+	// Update organization
+	query := pr.Dbmap.Model(&Organization{ID: org.ID}).Updates(orgDB)
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return &org, nil
+}
+
+func (pr PostgresRepo) RemoveOrganization(id string) error {
+	orgName := "SELECT name FROM organizations WHERE id like ?"
+	orgGroups := "SELECT id FROM groups WHERE org IN (" + orgName + ")"
+	orgPolicies := "SELECT id FROM policies WHERE org IN (" + orgName + ")"
+
+	transaction := pr.begin()
+
+	// Delete groups and policies of the organization with all their relations,
+	// proxy resources and finally the organization itself
+	for _, query := range []struct {
+		sql  string
+		args []interface{}
+	}{
+		{"DELETE FROM group_user_relations WHERE group_id IN (" + orgGroups + ")", []interface{}{id}},
+		{"DELETE FROM group_subgroup_relations WHERE group_id IN (" + orgGroups + ") OR subgroup_id IN (" + orgGroups + ")",
+			[]interface{}{id, id}},
+		{"DELETE FROM group_policy_relations WHERE group_id IN (" + orgGroups + ") OR policy_id IN (" + orgPolicies + ")",
+			[]interface{}{id, id}},
+		{"DELETE FROM user_policy_relations WHERE policy_id IN (" + orgPolicies + ")", []interface{}{id}},
+		{"DELETE FROM statements WHERE policy_version_id IN (SELECT id FROM policy_versions WHERE policy_id IN (" + orgPolicies + "))",
+			[]interface{}{id}},
+		{"DELETE FROM policy_versions WHERE policy_id IN (" + orgPolicies + ")", []interface{}{id}},
+		{"DELETE FROM policies WHERE org IN (" + orgName + ")", []interface{}{id}},
+		{"DELETE FROM groups WHERE org IN (" + orgName + ")", []interface{}{id}},
+		{"DELETE FROM proxy_resources WHERE org IN (" + orgName + ")", []interface{}{id}},
+		{"DELETE FROM organizations WHERE id like ?", []interface{}{id}},
+	} {
+		if err := transaction.Exec(query.sql, query.args...).Error; err != nil {
+			pr.rollback(transaction)
+			return &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	pr.commit(transaction)
+	return nil
+}
+
+// PRIVATE HELPER METHODS
+
+// Transform an organization retrieved from db into an organization for API
+func dbOrganizationToAPIOrganization(org *Organization) *api.Organization {
+	return &api.Organization{
+		ID:       org.ID,
+		Name:     org.Name,
+		Path:     org.Path,
+		CreateAt: time.Unix(0, org.CreateAt).UTC(),
+		UpdateAt: time.Unix(0, org.UpdateAt).UTC(),
+		Urn:      org.Urn,
+	}
+}
@@ -0,0 +1,540 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepo_AddOrganization(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousOrganization *Organization
+		// Postgres Repo Args
+		orgToCreate *api.Organization
+		// Expected result
+		expectedResponse *api.Organization
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			orgToCreate: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			expectedResponse: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"ErrorCaseAlreadyExists": {
+			previousOrganization: &Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+			},
+			orgToCreate: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			expectedError: &database.Error{
+				Code:    database.ORGANIZATION_ALREADY_EXIST,
+				Message: "Organization with name org1 already exist",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean organization database
+		cleanOrganizationsTable(t, n)
+
+		// Insert previous data
+		if test.previousOrganization != nil {
+			insertOrganization(t, n, *test.previousOrganization)
+		}
+		// Call to repository to store organization
+		storedOrg, err := repoDB.AddOrganization(*test.orgToCreate)
+		if test.expectedError != nil {
+			dbError, ok := err.(*database.Error)
+			if !ok || dbError == nil {
+				t.Errorf("Test %v failed. Unexpected data retrieved from error: %v", n, err)
+				continue
+			}
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse, storedOrg, "Error in test case %v", n)
+			// Check database
+			orgNumber := getOrganizationsCountFiltered(t, n, test.orgToCreate.ID, test.orgToCreate.Name, test.orgToCreate.Path,
+				test.orgToCreate.CreateAt.UnixNano(), test.orgToCreate.UpdateAt.UnixNano(), test.orgToCreate.Urn)
+			assert.Equal(t, 1, orgNumber, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_GetOrganizationByName(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousOrganization *Organization
+		// Postgres Repo Args
+		name string
+		// Expected result
+		expectedResponse *api.Organization
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			previousOrganization: &Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+			},
+			name: "org1",
+			expectedResponse: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"ErrorCaseNotFound": {
+			name: "org1",
+			expectedError: &database.Error{
+				Code:    database.ORGANIZATION_NOT_FOUND,
+				Message: "Organization with name org1 not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean organization database
+		cleanOrganizationsTable(t, n)
+
+		// Insert previous data
+		if test.previousOrganization != nil {
+			insertOrganization(t, n, *test.previousOrganization)
+		}
+		// Call to repository to get organization
+		receivedOrg, err := repoDB.GetOrganizationByName(test.name)
+		if test.expectedError != nil {
+			dbError, ok := err.(*database.Error)
+			if !ok || dbError == nil {
+				t.Errorf("Test %v failed. Unexpected data retrieved from error: %v", n, err)
+				continue
+			}
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse, receivedOrg, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_GetOrganizationsFiltered(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousOrganizations []Organization
+		// Postgres Repo Args
+		filter *api.Filter
+		// Expected result
+		expectedResponse []api.Organization
+	}{
+		"OkCaseAll": {
+			previousOrganizations: []Organization{
+				{
+					ID:       "ORG-ID-1",
+					Name:     "org1",
+					Path:     "/path1/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path1/", "org1"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+				{
+					ID:       "ORG-ID-2",
+					Name:     "org2",
+					Path:     "/path2/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path2/", "org2"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+			},
+			filter: &api.Filter{
+				OrderBy: "name asc",
+			},
+			expectedResponse: []api.Organization{
+				{
+					ID:       "ORG-ID-1",
+					Name:     "org1",
+					Path:     "/path1/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path1/", "org1"),
+					CreateAt: now,
+					UpdateAt: now,
+				},
+				{
+					ID:       "ORG-ID-2",
+					Name:     "org2",
+					Path:     "/path2/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path2/", "org2"),
+					CreateAt: now,
+					UpdateAt: now,
+				},
+			},
+		},
+		"OkCasePathPrefix": {
+			previousOrganizations: []Organization{
+				{
+					ID:       "ORG-ID-1",
+					Name:     "org1",
+					Path:     "/path1/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path1/", "org1"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+				{
+					ID:       "ORG-ID-2",
+					Name:     "org2",
+					Path:     "/path2/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path2/", "org2"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+			},
+			filter: &api.Filter{
+				PathPrefix: "/path2/",
+			},
+			expectedResponse: []api.Organization{
+				{
+					ID:       "ORG-ID-2",
+					Name:     "org2",
+					Path:     "/path2/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path2/", "org2"),
+					CreateAt: now,
+					UpdateAt: now,
+				},
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean organization database
+		cleanOrganizationsTable(t, n)
+
+		// Insert previous data
+		for _, org := range test.previousOrganizations {
+			insertOrganization(t, n, org)
+		}
+		// Call to repository to get organizations
+		receivedOrgs, total, err := repoDB.GetOrganizationsFiltered(test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, receivedOrgs, "Error in test case %v", n)
+		assert.Equal(t, len(test.expectedResponse), total, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_UpdateOrganization(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousOrganization *Organization
+		// Postgres Repo Args
+		orgToUpdate *api.Organization
+		// Expected result
+		expectedResponse *api.Organization
+	}{
+		"OkCase": {
+			previousOrganization: &Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+			},
+			orgToUpdate: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/newpath/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/newpath/", "org1"),
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			expectedResponse: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/newpath/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/newpath/", "org1"),
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean organization database
+		cleanOrganizationsTable(t, n)
+
+		// Insert previous data
+		if test.previousOrganization != nil {
+			insertOrganization(t, n, *test.previousOrganization)
+		}
+		// Call to repository to update organization
+		updatedOrg, err := repoDB.UpdateOrganization(*test.orgToUpdate)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, updatedOrg, "Error in test case %v", n)
+		// Check database
+		orgNumber := getOrganizationsCountFiltered(t, n, test.orgToUpdate.ID, test.orgToUpdate.Name, test.orgToUpdate.Path,
+			test.orgToUpdate.CreateAt.UnixNano(), test.orgToUpdate.UpdateAt.UnixNano(), test.orgToUpdate.Urn)
+		assert.Equal(t, 1, orgNumber, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_RemoveOrganization(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousOrganizations  []Organization
+		previousGroups         []Group
+		previousPolicies       []Policy
+		previousProxyResources []ProxyResource
+		previousMembers        map[string][]string
+		previousGroupPolicies  map[string][]string
+		previousUserPolicies   map[string][]string
+		// Postgres Repo Args
+		orgToDelete string
+	}{
+		"OkCase": {
+			previousOrganizations: []Organization{
+				{
+					ID:       "ORG-ID-1",
+					Name:     "org1",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+				{
+					ID:       "ORG-ID-2",
+					Name:     "org2",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org2"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+			},
+			previousGroups: []Group{
+				{
+					ID:       "GROUP-1",
+					Name:     "group",
+					Org:      "org1",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("org1", api.RESOURCE_GROUP, "/path/", "group"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+				{
+					ID:       "GROUP-2",
+					Name:     "group",
+					Org:      "org2",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("org2", api.RESOURCE_GROUP, "/path/", "group"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+			},
+			previousPolicies: []Policy{
+				{
+					ID:       "POLICY-1",
+					Name:     "policy",
+					Org:      "org1",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "policy"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+				{
+					ID:       "POLICY-2",
+					Name:     "policy",
+					Org:      "org2",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("org2", api.RESOURCE_POLICY, "/path/", "policy"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+			},
+			previousProxyResources: []ProxyResource{
+				{
+					ID:           "PROXY-1",
+					Name:         "proxy",
+					Org:          "org1",
+					Path:         "/path/",
+					Host:         "https://host.com",
+					PathResource: "/one",
+					Method:       "GET",
+					UrnResource:  "urn:ews:example:instance1:resource/one",
+					Urn:          api.CreateUrn("org1", api.RESOURCE_PROXY, "/path/", "proxy"),
+					Action:       "example:one",
+					CreateAt:     now.UnixNano(),
+					UpdateAt:     now.UnixNano(),
+				},
+				{
+					ID:           "PROXY-2",
+					Name:         "proxy",
+					Org:          "org2",
+					Path:         "/path/",
+					Host:         "https://host.com",
+					PathResource: "/two",
+					Method:       "GET",
+					UrnResource:  "urn:ews:example:instance1:resource/two",
+					Urn:          api.CreateUrn("org2", api.RESOURCE_PROXY, "/path/", "proxy"),
+					Action:       "example:two",
+					CreateAt:     now.UnixNano(),
+					UpdateAt:     now.UnixNano(),
+				},
+			},
+			previousMembers: map[string][]string{
+				"GROUP-1": {"USER-1"},
+				"GROUP-2": {"USER-1"},
+			},
+			previousGroupPolicies: map[string][]string{
+				"GROUP-1": {"POLICY-1"},
+				"GROUP-2": {"POLICY-1", "POLICY-2"},
+			},
+			previousUserPolicies: map[string][]string{
+				"USER-1": {"POLICY-1", "POLICY-2"},
+			},
+			orgToDelete: "ORG-ID-1",
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean database
+		cleanOrganizationsTable(t, n)
+		cleanGroupTable(t, n)
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+		cleanProxyResourcesTable(t, n)
+		cleanGroupUserRelationTable(t, n)
+		cleanGroupPolicyRelationTable(t, n)
+		cleanUserPolicyRelationTable(t, n)
+
+		// Insert previous data
+		for _, org := range test.previousOrganizations {
+			insertOrganization(t, n, org)
+		}
+		for _, group := range test.previousGroups {
+			insertGroup(t, n, group)
+		}
+		for _, policy := range test.previousPolicies {
+			insertPolicy(t, n, policy, []Statement{
+				{
+					ID:        policy.ID + "-STATEMENT",
+					Effect:    "allow",
+					Actions:   api.USER_ACTION_GET_USER,
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+				},
+			})
+		}
+		for _, pr := range test.previousProxyResources {
+			insertProxyResource(t, n, pr)
+		}
+		for groupID, userIDs := range test.previousMembers {
+			for _, userID := range userIDs {
+				insertGroupUserRelation(t, n, userID, groupID, now.UnixNano())
+			}
+		}
+		for groupID, policyIDs := range test.previousGroupPolicies {
+			for _, policyID := range policyIDs {
+				insertGroupPolicyRelation(t, n, groupID, policyID, now.UnixNano())
+			}
+		}
+		for userID, policyIDs := range test.previousUserPolicies {
+			for _, policyID := range policyIDs {
+				insertUserPolicyRelation(t, n, userID, policyID, now.UnixNano())
+			}
+		}
+
+		// Call to repository to remove organization
+		err := repoDB.RemoveOrganization(test.orgToDelete)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check organizations
+		assert.Equal(t, 0, getOrganizationsCountFiltered(t, n, test.orgToDelete, "", "", 0, 0, ""), "Error in test case %v", n)
+		assert.Equal(t, 1, getOrganizationsCountFiltered(t, n, "", "", "", 0, 0, ""), "Error in test case %v", n)
+
+		// Check resources of the organization
+		assert.Equal(t, 0, getGroupsCountFiltered(t, n, "", "", "", 0, 0, "", "org1"), "Error in test case %v", n)
+		assert.Equal(t, 1, getGroupsCountFiltered(t, n, "", "", "", 0, 0, "", "org2"), "Error in test case %v", n)
+		assert.Equal(t, 0, getPoliciesCountFiltered(t, n, "", "org1", "", "", 0, ""), "Error in test case %v", n)
+		assert.Equal(t, 1, getPoliciesCountFiltered(t, n, "", "org2", "", "", 0, ""), "Error in test case %v", n)
+		assert.Equal(t, 0, getPolicyVersionsCountFiltered(t, n, "POLICY-1", 0, ""), "Error in test case %v", n)
+		assert.Equal(t, 1, getPolicyVersionsCountFiltered(t, n, "POLICY-2", 0, ""), "Error in test case %v", n)
+		assert.Equal(t, 0, getStatementsCountFiltered(t, n, "", "POLICY-1", "", "", ""), "Error in test case %v", n)
+		assert.Equal(t, 1, getStatementsCountFiltered(t, n, "", "POLICY-2", "", "", ""), "Error in test case %v", n)
+		assert.Equal(t, 0, getProxyResourcesCountFiltered(t, n, "", "", "org1", "", "", 0, 0), "Error in test case %v", n)
+		assert.Equal(t, 1, getProxyResourcesCountFiltered(t, n, "", "", "org2", "", "", 0, 0), "Error in test case %v", n)
+
+		// Check relations
+		assert.Equal(t, 0, getGroupUserRelations(t, n, "GROUP-1", ""), "Error in test case %v", n)
+		assert.Equal(t, 1, getGroupUserRelations(t, n, "GROUP-2", ""), "Error in test case %v", n)
+		assert.Equal(t, 0, getGroupPolicyRelationCount(t, n, "POLICY-1", ""), "Error in test case %v", n)
+		assert.Equal(t, 1, getGroupPolicyRelationCount(t, n, "POLICY-2", "GROUP-2"), "Error in test case %v", n)
+		assert.Equal(t, 0, getUserPolicyRelationCount(t, n, "POLICY-1", ""), "Error in test case %v", n)
+		assert.Equal(t, 1, getUserPolicyRelationCount(t, n, "POLICY-2", "USER-1"), "Error in test case %v", n)
+	}
+}
+
+func Test_dbOrganizationToAPIOrganization(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		dbOrganization   *Organization
+		expectedResponse *api.Organization
+	}{
+		"OkCase": {
+			dbOrganization: &Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+			},
+			expectedResponse: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		receivedOrg := dbOrganizationToAPIOrganization(test.dbOrganization)
+		assert.Equal(t, test.expectedResponse, receivedOrg, "Error in test case %v", n)
+	}
+}
@@ -7,12 +7,13 @@ import (
 
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/database"
+	"github.com/jinzhu/gorm"
 	"github.com/satori/go.uuid"
 )
 
 // POLICY REPOSITORY IMPLEMENTATION
 
-func (pr PostgresRepo) AddPolicy(policy api.Policy) (*api.Policy, error) {
+func (pr PostgresRepo) AddPolicy(policy api.Policy, author string) (*api.Policy, error) {
 	// Create policy model
 	policyDB := &Policy{
 		ID:       policy.ID,
@@ -22,39 +23,29 @@ func (pr PostgresRepo) AddPolicy(policy api.Policy) (*api.Policy, error) {
 		UpdateAt: policy.UpdateAt.UnixNano(),
 		Urn:      policy.Urn,
 		Org:      policy.Org,
+		Version:  1,
+		Revision: 1,
 	}
 
-	transaction := pr.Dbmap.Begin()
+	transaction := pr.begin()
 
 	// Create policy
 	if err := transaction.Create(policyDB).Error; err != nil {
-		transaction.Rollback()
+		pr.rollback(transaction)
+		return nil, storeError(err, database.POLICY_ALREADY_EXIST,
+			fmt.Sprintf("Policy with organization %v and name %v already exist", policy.Org, policy.Name))
+	}
+
+	// Create first policy version with its statements
+	if err := createPolicyVersion(transaction, policy.ID, policyDB.Version, author, policyDB.CreateAt, *policy.Statements); err != nil {
+		pr.rollback(transaction)
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
-	// Create statements
-	for _, statementApi := range *policy.Statements {
-		// Create statement model
-		statementDB := &Statement{
-			ID:        uuid.NewV4().String(),
-			PolicyID:  policy.ID,
-			Effect:    statementApi.Effect,
-			Actions:   stringArrayToString(statementApi.Actions),
-			Resources: stringArrayToString(statementApi.Resources),
-		}
-		if err := transaction.Create(statementDB).Error; err != nil {
-			transaction.Rollback()
-			return nil, &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: err.Error(),
-			}
-		}
-	}
-
-	transaction.Commit()
+	pr.commit(transaction)
 
 	// Create API policy
 	policyApi := dbPolicyToAPIPolicy(policyDB)
@@ -65,7 +56,7 @@ func (pr PostgresRepo) AddPolicy(policy api.Policy) (*api.Policy, error) {
 
 func (pr PostgresRepo) GetPolicyByName(org string, name string) (*api.Policy, error) {
 	policy := &Policy{}
-	query := pr.Dbmap.Where("org like ? AND name like ?", org, name).First(policy)
+	query := pr.lockRows().Where("org like ? AND name like ? AND delete_at = 0", org, name).First(policy)
 
 	// Check if policy exists
 	if query.RecordNotFound() {
@@ -83,11 +74,10 @@ func (pr PostgresRepo) GetPolicyByName(org string, name string) (*api.Policy, er
 		}
 	}
 
-	// Retrieve associated statements
-	statements := []Statement{}
-	query = pr.Dbmap.Where("policy_id like ?", policy.ID).Find(&statements)
+	// Retrieve statements of default version
+	statements, err := pr.getPolicyVersionStatements(policy.ID, policy.Version)
 	// Error Handling
-	if err := query.Error; err != nil {
+	if err != nil {
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -121,11 +111,10 @@ func (pr PostgresRepo) GetPolicyById(id string) (*api.Policy, error) {
 		}
 	}
 
-	// Retrieve associated statements
-	statements := []Statement{}
-	query = pr.Dbmap.Where("policy_id like ?", policy.ID).Find(&statements)
+	// Retrieve statements of default version
+	statements, err := pr.getPolicyVersionStatements(policy.ID, policy.Version)
 	// Error Handling
-	if err := query.Error; err != nil {
+	if err != nil {
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -142,7 +131,7 @@ func (pr PostgresRepo) GetPolicyById(id string) (*api.Policy, error) {
 func (pr PostgresRepo) GetPoliciesFiltered(filter *api.Filter) ([]api.Policy, int, error) {
 	var total int
 	policies := []Policy{}
-	query := pr.Dbmap
+	query := pr.Dbmap.Where(deletedCondition(filter))
 
 	if len(filter.Org) > 0 {
 		query = query.Where("org like ?", filter.Org)
@@ -150,12 +139,22 @@ func (pr PostgresRepo) GetPoliciesFiltered(filter *api.Filter) ([]api.Policy, in
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ?", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
+	if len(filter.Action) > 0 {
+		query = query.Where(statementCondition("actions"), filter.Action)
 	}
+	if len(filter.Resource) > 0 {
+		query = query.Where(statementCondition("resources"), filter.Resource)
+	}
+	query = search(query, filter, "name")
 
 	// Error handling
-	if err := query.Find(&policies).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&policies).Error; err != nil {
+	if err := query.Model(&Policy{}).Count(&total).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if err := paginate(query, filter, "id").Find(&policies).Error; err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -170,11 +169,10 @@ func (pr PostgresRepo) GetPoliciesFiltered(filter *api.Filter) ([]api.Policy, in
 		for i, pol := range policies {
 			policy := dbPolicyToAPIPolicy(&pol)
 
-			// Retrieve associated statements
-			statements := []Statement{}
-			query = pr.Dbmap.Where("policy_id like ?", policy.ID).Find(&statements)
+			// Retrieve statements of default version
+			statements, err := pr.getPolicyVersionStatements(policy.ID, policy.Version)
 			// Error Handling
-			if err := query.Error; err != nil {
+			if err != nil {
 				return nil, total, &database.Error{
 					Code:    database.INTERNAL_ERROR,
 					Message: err.Error(),
@@ -192,7 +190,20 @@ func (pr PostgresRepo) GetPoliciesFiltered(filter *api.Filter) ([]api.Policy, in
 	return apiPolicies, total, nil
 }
 
-func (pr PostgresRepo) UpdatePolicy(policy api.Policy) (*api.Policy, error) {
+func (pr PostgresRepo) UpdatePolicy(policy api.Policy, author string) (*api.Policy, error) {
+
+	transaction := pr.begin()
+
+	// Retrieve last version number, it could be different to default version after a rollback
+	var lastVersion int
+	row := transaction.Model(&PolicyVersion{}).Where("policy_id like ?", policy.ID).Select("COALESCE(MAX(version), 0)").Row()
+	if err := row.Scan(&lastVersion); err != nil {
+		pr.rollback(transaction)
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
 
 	policyDB := Policy{
 		ID:       policy.ID,
@@ -202,100 +213,132 @@ func (pr PostgresRepo) UpdatePolicy(policy api.Policy) (*api.Policy, error) {
 		UpdateAt: policy.UpdateAt.UTC().UnixNano(),
 		Urn:      policy.Urn,
 		Org:      policy.Org,
+		Version:  lastVersion + 1,
+		Revision: policy.Revision + 1,
 	}
 
-	transaction := pr.Dbmap.Begin()
-
-	// Update policy
-	if err := transaction.Model(&Policy{ID: policy.ID}).Update(policyDB).Error; err != nil {
-		transaction.Rollback()
-		return nil, &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+	// Update policy if it wasn't modified after the revision retrieved
+	query := transaction.Model(&Policy{ID: policy.ID}).Where("revision = ?", policy.Revision).Update(policyDB)
+	if err := query.Error; err != nil {
+		pr.rollback(transaction)
+		return nil, storeError(err, database.POLICY_ALREADY_EXIST,
+			fmt.Sprintf("Policy with organization %v and name %v already exist", policy.Org, policy.Name))
+	}
+	if query.RowsAffected == 0 {
+		pr.rollback(transaction)
+		return nil, revisionMismatchError("Policy", policy.ID, policy.Revision)
 	}
 
-	// Clear old statements
-	if err := transaction.Where("policy_id like ?", policy.ID).Delete(Statement{}).Error; err != nil {
-		transaction.Rollback()
+	// Create new version with its statements
+	if err := createPolicyVersion(transaction, policy.ID, policyDB.Version, author, policyDB.UpdateAt, *policy.Statements); err != nil {
+		pr.rollback(transaction)
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
-	// Create new statements
-	for _, s := range *policy.Statements {
-		statementDB := &Statement{
-			ID:        uuid.NewV4().String(),
-			PolicyID:  policy.ID,
-			Effect:    s.Effect,
-			Actions:   stringArrayToString(s.Actions),
-			Resources: stringArrayToString(s.Resources),
-		}
-		if err := transaction.Create(statementDB).Error; err != nil {
-			transaction.Rollback()
-			return nil, &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: err.Error(),
-			}
-		}
-	}
-
-	transaction.Commit()
+	pr.commit(transaction)
 
+	policy.Version = policyDB.Version
+	policy.Revision = policyDB.Revision
 	return &policy, nil
 }
 
-func (pr PostgresRepo) RemovePolicy(id string) error {
-
-	transaction := pr.Dbmap.Begin()
+func (pr PostgresRepo) RemovePolicy(id string, revision int) error {
+	// Mark policy as deleted, its versions and relations are kept until it's purged
+	query := pr.Dbmap.Model(&Policy{}).Where("id like ? AND revision = ?", id, revision).UpdateColumn("delete_at", time.Now().UTC().UnixNano())
 
-	// Delete policy relations (group)
-	transaction.Where("policy_id like ?", id).Delete(&GroupPolicyRelation{})
-	if err := transaction.Error; err != nil {
-		transaction.Rollback()
+	// Error handling
+	if err := query.Error; err != nil {
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
-	// Delete policy statements
-	transaction.Where("policy_id like ?", id).Delete(&Statement{})
-	if err := transaction.Error; err != nil {
-		transaction.Rollback()
-		return &database.Error{
+	if query.RowsAffected == 0 {
+		return revisionMismatchError("Policy", id, revision)
+	}
+
+	return nil
+}
+
+func (pr PostgresRepo) GetDeletedPolicyByName(org string, name string) (*api.Policy, error) {
+	policy := &Policy{}
+	query := pr.Dbmap.Where("org like ? AND name like ? AND delete_at > 0", org, name).Order("delete_at desc").First(policy)
+
+	// Check if policy exists
+	if query.RecordNotFound() {
+		return nil, &database.Error{
+			Code:    database.POLICY_NOT_FOUND,
+			Message: fmt.Sprintf("Deleted policy with organization %v and name %v not found", org, name),
+		}
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
-	//  Delete policy
-	transaction.Where("id like ?", id).Delete(&Policy{})
-	if err := transaction.Error; err != nil {
-		transaction.Rollback()
-		return &database.Error{
+
+	// Retrieve statements of default version
+	statements, err := pr.getPolicyVersionStatements(policy.ID, policy.Version)
+	// Error Handling
+	if err != nil {
+		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
-	transaction.Commit()
+	// Create API policy
+	policyApi := dbPolicyToAPIPolicy(policy)
+	policyApi.Statements = dbStatementsToAPIStatements(statements)
+
+	return policyApi, nil
+}
+
+func (pr PostgresRepo) RestorePolicy(id string) error {
+	err := pr.Dbmap.Model(&Policy{}).Where("id like ?", id).UpdateColumn("delete_at", 0).Error
+
+	// Error handling
+	if err != nil {
+		return storeError(err, database.POLICY_ALREADY_EXIST,
+			fmt.Sprintf("Policy with id %v can't be restored, its name is in use", id))
+	}
+
 	return nil
 }
 
+func (pr PostgresRepo) PurgePolicies(deleteBefore time.Time) (int, error) {
+	deletedPolicies := "SELECT id FROM policies WHERE delete_at > 0 AND delete_at < ?"
+
+	// Delete policies with their relations, versions and statements
+	return pr.purge([]string{
+		"DELETE FROM group_policy_relations WHERE policy_id IN (" + deletedPolicies + ")",
+		"DELETE FROM user_policy_relations WHERE policy_id IN (" + deletedPolicies + ")",
+		"DELETE FROM statements WHERE policy_version_id IN (SELECT id FROM policy_versions WHERE policy_id IN (" +
+			deletedPolicies + "))",
+		"DELETE FROM policy_versions WHERE policy_id IN (" + deletedPolicies + ")",
+		"DELETE FROM policies WHERE delete_at > 0 AND delete_at < ?",
+	}, deleteBefore)
+}
+
 func (pr PostgresRepo) GetAttachedGroups(policyID string, filter *api.Filter) ([]api.PolicyGroupRelation, int, error) {
 	var total int
 	relations := []GroupPolicyRelation{}
-	query := pr.Dbmap
-
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
-
-	query.Where("policy_id like ?", policyID).Find(&relations).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&relations)
+	query := pr.Dbmap.Where("policy_id like ? AND group_id NOT IN (SELECT id FROM groups WHERE delete_at > 0)", policyID)
 
 	// Error Handling
-	if err := query.Error; err != nil {
+	if err := query.Model(&GroupPolicyRelation{}).Count(&total).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if err := paginate(query, filter, "group_id").Find(&relations).Error; err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -326,8 +369,153 @@ func (pr PostgresRepo) GetAttachedGroups(policyID string, filter *api.Filter) ([
 	return groups, total, nil
 }
 
+func (pr PostgresRepo) GetPolicyVersions(policyID string, filter *api.Filter) ([]api.PolicyVersion, int, error) {
+	var total int
+	versions := []PolicyVersion{}
+	query := pr.Dbmap.Where("policy_id like ?", policyID)
+
+	if len(filter.OrderBy) > 0 {
+		query = query.Order(filter.OrderBy)
+	} else {
+		query = query.Order("version desc")
+	}
+
+	// Error Handling
+	if err := query.Find(&versions).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&versions).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Transform versions to API domain
+	var apiVersions []api.PolicyVersion
+	if versions != nil {
+		apiVersions = make([]api.PolicyVersion, len(versions), cap(versions))
+		for i, v := range versions {
+			apiVersions[i] = *dbPolicyVersionToAPIPolicyVersion(&v)
+		}
+	}
+
+	return apiVersions, total, nil
+}
+
+func (pr PostgresRepo) GetPolicyVersion(policyID string, version int) (*api.PolicyVersion, error) {
+	policyVersion := &PolicyVersion{}
+	query := pr.Dbmap.Where("policy_id like ? AND version = ?", policyID, version).First(policyVersion)
+
+	// Check if version exists
+	if query.RecordNotFound() {
+		return nil, &database.Error{
+			Code:    database.POLICY_VERSION_NOT_FOUND,
+			Message: fmt.Sprintf("Version %v of policy with id %v not found", version, policyID),
+		}
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Retrieve associated statements
+	statements := []Statement{}
+	if err := pr.Dbmap.Where("policy_version_id like ?", policyVersion.ID).Find(&statements).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Create API policy version
+	versionApi := dbPolicyVersionToAPIPolicyVersion(policyVersion)
+	versionApi.Statements = dbStatementsToAPIStatements(statements)
+
+	return versionApi, nil
+}
+
+func (pr PostgresRepo) SetDefaultPolicyVersion(policyID string, version int, revision int, updateAt time.Time) (*api.Policy, error) {
+	// Update policy if it wasn't modified after the revision retrieved, statements change so revision does too
+	query := pr.Dbmap.Model(&Policy{ID: policyID}).Where("revision = ?", revision).Updates(Policy{
+		Version:  version,
+		UpdateAt: updateAt.UTC().UnixNano(),
+		Revision: revision + 1,
+	})
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if query.RowsAffected == 0 {
+		return nil, revisionMismatchError("Policy", policyID, revision)
+	}
+
+	return pr.GetPolicyById(policyID)
+}
+
 // PRIVATE HELPER METHODS
 
+// Store a policy version with its statements using the transaction received
+func createPolicyVersion(transaction *gorm.DB, policyID string, version int, author string, createAt int64,
+	statements []api.Statement) error {
+	versionDB := &PolicyVersion{
+		ID:       uuid.NewV4().String(),
+		PolicyID: policyID,
+		Version:  version,
+		Author:   author,
+		CreateAt: createAt,
+	}
+	if err := transaction.Create(versionDB).Error; err != nil {
+		return err
+	}
+
+	for _, s := range statements {
+		statementDB := &Statement{
+			ID:              uuid.NewV4().String(),
+			PolicyVersionID: versionDB.ID,
+			Effect:          s.Effect,
+			Actions:         stringArrayToString(s.Actions),
+			NotActions:      stringArrayToString(s.NotActions),
+			Resources:       stringArrayToString(s.Resources),
+			NotResources:    stringArrayToString(s.NotResources),
+		}
+		if err := transaction.Create(statementDB).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Retrieve statements of a policy version
+func (pr PostgresRepo) getPolicyVersionStatements(policyID string, version int) ([]Statement, error) {
+	statements := []Statement{}
+	err := pr.Dbmap.Where("policy_version_id IN (SELECT id FROM policy_versions WHERE policy_id like ? AND version = ?)",
+		policyID, version).Find(&statements).Error
+
+	return statements, err
+}
+
+// Aux method that returns the condition to select policies with a statement of their current version
+// that contains a value in column, where values are stored as a semicolon-separated string
+func statementCondition(column string) string {
+	return "id IN (SELECT policy_versions.policy_id FROM policy_versions JOIN statements ON statements.policy_version_id = policy_versions.id " +
+		"WHERE policy_versions.version = policies.version AND ? = ANY(string_to_array(statements." + column + ", ';')))"
+}
+
+// Transform a policy version retrieved from db into a policy version for API
+func dbPolicyVersionToAPIPolicyVersion(versiondb *PolicyVersion) *api.PolicyVersion {
+	return &api.PolicyVersion{
+		ID:       versiondb.ID,
+		Version:  versiondb.Version,
+		Author:   versiondb.Author,
+		CreateAt: time.Unix(0, versiondb.CreateAt).UTC(),
+	}
+}
+
 // Transform a policy retrieved from db into a policy for API
 func dbPolicyToAPIPolicy(policydb *Policy) *api.Policy {
 	return &api.Policy{
@@ -338,6 +526,8 @@ func dbPolicyToAPIPolicy(policydb *Policy) *api.Policy {
 		UpdateAt: time.Unix(0, policydb.UpdateAt).UTC(),
 		Urn:      policydb.Urn,
 		Org:      policydb.Org,
+		Version:  policydb.Version,
+		Revision: policydb.Revision,
 	}
 }
 
@@ -346,9 +536,11 @@ func dbStatementsToAPIStatements(statements []Statement) *[]api.Statement {
 	statementsApi := make([]api.Statement, len(statements), cap(statements))
 	for i, s := range statements {
 		statementsApi[i] = api.Statement{
-			Actions:   strings.Split(s.Actions, ";"),
-			Effect:    s.Effect,
-			Resources: strings.Split(s.Resources, ";"),
+			Actions:      stringToStringArray(s.Actions),
+			NotActions:   stringToStringArray(s.NotActions),
+			Effect:       s.Effect,
+			Resources:    stringToStringArray(s.Resources),
+			NotResources: stringToStringArray(s.NotResources),
 		}
 	}
 
@@ -368,3 +560,12 @@ func stringArrayToString(array []string) string {
 
 	return stringVal
 }
+
+// Transform a semicolon-separated string into an array of strings, nil if string is empty
+func stringToStringArray(stringVal string) []string {
+	if len(stringVal) == 0 {
+		return nil
+	}
+
+	return strings.Split(stringVal, ";")
+}
@@ -28,6 +28,7 @@ func TestPostgresRepo_AddPolicy(t *testing.T) {
 				CreateAt: now,
 				UpdateAt: now,
 				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  1,
 				Statements: &[]api.Statement{
 					{
 						Effect: "allow",
@@ -47,7 +48,9 @@ func TestPostgresRepo_AddPolicy(t *testing.T) {
 				Path:     "/path/",
 				CreateAt: now,
 				UpdateAt: now,
+				Revision: 1,
 				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  1,
 				Statements: &[]api.Statement{
 					{
 						Effect: "allow",
@@ -73,11 +76,11 @@ func TestPostgresRepo_AddPolicy(t *testing.T) {
 			},
 			statements: []Statement{
 				{
-					ID:        "test1",
-					PolicyID:  "test1",
-					Effect:    "allow",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					ID:              "test1",
+					PolicyVersionID: "test1",
+					Effect:          "allow",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 				},
 			},
 			policy: api.Policy{
@@ -87,6 +90,7 @@ func TestPostgresRepo_AddPolicy(t *testing.T) {
 				Path:     "/path/",
 				CreateAt: now,
 				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  1,
 				Statements: &[]api.Statement{
 					{
 						Effect: "allow",
@@ -100,8 +104,8 @@ func TestPostgresRepo_AddPolicy(t *testing.T) {
 				},
 			},
 			expectedError: &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: "pq: duplicate key value violates unique constraint \"policies_pkey\"",
+				Code:    database.POLICY_ALREADY_EXIST,
+				Message: "Policy with organization 123 and name test already exist",
 			},
 		},
 	}
@@ -115,7 +119,7 @@ func TestPostgresRepo_AddPolicy(t *testing.T) {
 		if test.previousPolicy != nil {
 			insertPolicy(t, n, *test.previousPolicy, test.statements)
 		}
-		receivedPolicy, err := repoDB.AddPolicy(test.policy)
+		receivedPolicy, err := repoDB.AddPolicy(test.policy, "author")
 		if test.expectedError != nil {
 			dbError, _ := err.(*database.Error)
 			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
@@ -126,6 +130,8 @@ func TestPostgresRepo_AddPolicy(t *testing.T) {
 			// Check database
 			policyNumber := getPoliciesCountFiltered(t, n, test.policy.ID, test.policy.Org, test.policy.Name, test.policy.Path, test.policy.CreateAt.UnixNano(), test.policy.Urn)
 			assert.Equal(t, 1, policyNumber, "Error in test case %v", n)
+			versionNumber := getPolicyVersionsCountFiltered(t, n, test.policy.ID, 1, "author")
+			assert.Equal(t, 1, versionNumber, "Error in test case %v", n)
 
 			for _, statement := range *test.policy.Statements {
 				statementNumber := getStatementsCountFiltered(
@@ -168,11 +174,11 @@ func TestPostgresRepo_GetPolicyByName(t *testing.T) {
 			},
 			statements: []Statement{
 				{
-					ID:        "0123",
-					Effect:    "allow",
-					PolicyID:  "1234",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					ID:              "0123",
+					Effect:          "allow",
+					PolicyVersionID: "1234",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 				},
 			},
 			expectedResponse: &api.Policy{
@@ -183,6 +189,7 @@ func TestPostgresRepo_GetPolicyByName(t *testing.T) {
 				CreateAt: now,
 				UpdateAt: now,
 				Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  1,
 				Statements: &[]api.Statement{
 					{
 						Effect: "allow",
@@ -252,11 +259,11 @@ func TestPostgresRepo_GetPolicyById(t *testing.T) {
 			},
 			statements: []Statement{
 				{
-					ID:        "0123",
-					Effect:    "allow",
-					PolicyID:  "1234",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					ID:              "0123",
+					Effect:          "allow",
+					PolicyVersionID: "1234",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 				},
 			},
 			expectedResponse: &api.Policy{
@@ -267,6 +274,7 @@ func TestPostgresRepo_GetPolicyById(t *testing.T) {
 				CreateAt: now,
 				UpdateAt: now,
 				Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  1,
 				Statements: &[]api.Statement{
 					{
 						Effect: "allow",
@@ -351,18 +359,18 @@ func TestPostgresRepo_GetPoliciesFiltered(t *testing.T) {
 			},
 			statements: []Statement{
 				{
-					ID:        "1",
-					Effect:    "allow",
-					PolicyID:  "111",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path1/"),
+					ID:              "1",
+					Effect:          "allow",
+					PolicyVersionID: "111",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path1/"),
 				},
 				{
-					ID:        "2",
-					Effect:    "allow",
-					PolicyID:  "222",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path2/"),
+					ID:              "2",
+					Effect:          "allow",
+					PolicyVersionID: "222",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path2/"),
 				},
 			},
 			expectedResponse: []api.Policy{
@@ -374,6 +382,7 @@ func TestPostgresRepo_GetPoliciesFiltered(t *testing.T) {
 					CreateAt: now,
 					UpdateAt: now,
 					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path2/", "test2"),
+					Version:  1,
 					Statements: &[]api.Statement{
 						{
 							Effect: "allow",
@@ -394,6 +403,7 @@ func TestPostgresRepo_GetPoliciesFiltered(t *testing.T) {
 					CreateAt: now,
 					UpdateAt: now,
 					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path1/", "test1"),
+					Version:  1,
 					Statements: &[]api.Statement{
 						{
 							Effect: "allow",
@@ -417,6 +427,73 @@ func TestPostgresRepo_GetPoliciesFiltered(t *testing.T) {
 			},
 			expectedResponse: []api.Policy{},
 		},
+		"OkCaseAction": {
+			filter: &api.Filter{
+				PathPrefix: "/",
+				Org:        "org1",
+				Limit:      20,
+				Action:     api.USER_ACTION_LIST_USERS,
+			},
+			policies: []Policy{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Org:      "org1",
+					Path:     "/path1/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path1/", "test1"),
+				}, {
+					ID:       "222",
+					Name:     "test2",
+					Org:      "org1",
+					Path:     "/path2/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path2/", "test2"),
+				},
+			},
+			statements: []Statement{
+				{
+					ID:              "1",
+					Effect:          "allow",
+					PolicyVersionID: "111",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path1/"),
+				},
+				{
+					ID:              "2",
+					Effect:          "allow",
+					PolicyVersionID: "222",
+					Actions:         api.USER_ACTION_GET_USER + ";" + api.USER_ACTION_LIST_USERS,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path2/"),
+				},
+			},
+			expectedResponse: []api.Policy{
+				{
+					ID:       "222",
+					Name:     "test2",
+					Org:      "org1",
+					Path:     "/path2/",
+					CreateAt: now,
+					UpdateAt: now,
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path2/", "test2"),
+					Version:  1,
+					Statements: &[]api.Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								api.USER_ACTION_GET_USER,
+								api.USER_ACTION_LIST_USERS,
+							},
+							Resources: []string{
+								api.GetUrnPrefix("", api.RESOURCE_USER, "/path2/"),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for n, test := range testcases {
@@ -459,16 +536,17 @@ func TestPostgresRepo_UpdatePolicy(t *testing.T) {
 					Path:     "/path/",
 					CreateAt: now.UnixNano(),
 					UpdateAt: now.UnixNano(),
+					Revision: 1,
 					Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
 				},
 			},
 			previousStatements: []Statement{
 				{
-					ID:        "1",
-					PolicyID:  "111",
-					Effect:    "allow",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					ID:              "1",
+					PolicyVersionID: "111",
+					Effect:          "allow",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 				},
 			},
 			policy: &api.Policy{
@@ -477,7 +555,9 @@ func TestPostgresRepo_UpdatePolicy(t *testing.T) {
 				Org:      "123",
 				Path:     "/newPath/",
 				CreateAt: now,
+				Revision: 1,
 				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/newPath/", "newName"),
+				Version:  1,
 				Statements: &[]api.Statement{
 					{
 						Effect: "allow",
@@ -496,7 +576,9 @@ func TestPostgresRepo_UpdatePolicy(t *testing.T) {
 				Org:      "123",
 				Path:     "/newPath/",
 				CreateAt: now,
+				Revision: 2,
 				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/newPath/", "newName"),
+				Version:  2,
 				Statements: &[]api.Statement{
 					{
 						Effect: "allow",
@@ -523,11 +605,20 @@ func TestPostgresRepo_UpdatePolicy(t *testing.T) {
 				insertPolicy(t, n, p, test.previousStatements)
 			}
 		}
-		receivedPolicy, err := repoDB.UpdatePolicy(*test.policy)
+		receivedPolicy, err := repoDB.UpdatePolicy(*test.policy, "author")
 		assert.Nil(t, err, "Error in test case %v", n)
 
 		// Check response
 		assert.Equal(t, test.expectedResponse, receivedPolicy, "Error in test case %v", n)
+
+		// Check database, previous version must be kept
+		versionNumber := getPolicyVersionsCountFiltered(t, n, test.policy.ID, 0, "")
+		assert.Equal(t, 2, versionNumber, "Error in test case %v", n)
+		for _, statement := range *test.policy.Statements {
+			statementNumber := getStatementsCountFiltered(t, n, "", "", statement.Effect,
+				stringArrayToString(statement.Actions), stringArrayToString(statement.Resources))
+			assert.Equal(t, 1, statementNumber, "Error in test case %v", n)
+		}
 	}
 }
 
@@ -549,6 +640,7 @@ func TestPostgresRepo_RemovePolicy(t *testing.T) {
 		relations        []relation
 		// Postgres Repo Args
 		policyToDelete string
+		revision       int
 	}{
 		"OkCase": {
 			previousPolicies: []policyData{
@@ -560,15 +652,16 @@ func TestPostgresRepo_RemovePolicy(t *testing.T) {
 						Path:     "/path/",
 						CreateAt: now.UnixNano(),
 						UpdateAt: now.UnixNano(),
+						Revision: 1,
 						Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test1"),
 					},
 					statements: []Statement{
 						{
-							ID:        "test1",
-							PolicyID:  "test1",
-							Effect:    "allow",
-							Actions:   api.USER_ACTION_GET_USER,
-							Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+							ID:              "test1",
+							PolicyVersionID: "test1",
+							Effect:          "allow",
+							Actions:         api.USER_ACTION_GET_USER,
+							Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 						},
 					},
 				},
@@ -584,11 +677,11 @@ func TestPostgresRepo_RemovePolicy(t *testing.T) {
 					},
 					statements: []Statement{
 						{
-							ID:        "test2",
-							PolicyID:  "test2",
-							Effect:    "allow",
-							Actions:   api.USER_ACTION_GET_USER,
-							Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+							ID:              "test2",
+							PolicyVersionID: "test2",
+							Effect:          "allow",
+							Actions:         api.USER_ACTION_GET_USER,
+							Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 						},
 					},
 				},
@@ -606,6 +699,7 @@ func TestPostgresRepo_RemovePolicy(t *testing.T) {
 				},
 			},
 			policyToDelete: "test1",
+			revision:       1,
 		},
 	}
 
@@ -615,6 +709,7 @@ func TestPostgresRepo_RemovePolicy(t *testing.T) {
 		cleanStatementTable(t, n)
 		cleanGroupTable(t, n)
 		cleanGroupPolicyRelationTable(t, n)
+		cleanUserPolicyRelationTable(t, n)
 
 		// insert previous policy
 		if test.previousPolicies != nil {
@@ -625,34 +720,250 @@ func TestPostgresRepo_RemovePolicy(t *testing.T) {
 		if test.relations != nil {
 			for _, rel := range test.relations {
 				insertGroupPolicyRelation(t, n, rel.groupID, rel.policyID, rel.createAt)
+				insertUserPolicyRelation(t, n, "UserID", rel.policyID, rel.createAt)
 			}
 		}
-		err := repoDB.RemovePolicy(test.policyToDelete)
+		err := repoDB.RemovePolicy(test.policyToDelete, test.revision)
 		assert.Nil(t, err, "Error in test case %v", n)
 
-		// Check database
-		policyNumber := getPoliciesCountFiltered(t, n, test.policyToDelete, "", "", "", 0, "")
-		assert.Equal(t, 0, policyNumber, "Error in test case %v", n)
-
-		statementNumber := getStatementsCountFiltered(
-			t,
-			n,
-			"",
-			test.policyToDelete,
-			"",
-			"",
-			"")
-		assert.Equal(t, 0, statementNumber, "Error in test case %v", n)
-
-		// Check total policy number
+		// Check policy is marked as deleted
+		deleteAt := getDeleteAt(t, n, Policy{}.TableName(), test.policyToDelete)
+		assert.True(t, deleteAt > 0, "Error in test case %v", n)
+
+		// Check total policies and statements, deleted policies are kept until purged
 		totalPolicyNumber := getPoliciesCountFiltered(t, n, "", "", "", "", 0, "")
-		assert.Equal(t, 1, totalPolicyNumber, "Error in test case %v", n)
+		assert.Equal(t, 2, totalPolicyNumber, "Error in test case %v", n)
 
 		totalStatementNumber := getStatementsCountFiltered(t, n, "", "", "", "", "")
-		assert.Equal(t, 1, totalStatementNumber, "Error in test case %v", n)
+		assert.Equal(t, 2, totalStatementNumber, "Error in test case %v", n)
 
+		// Check policy relations are kept
 		totalGroupPolicyRelationNumber := getGroupPolicyRelationCount(t, n, "", "")
-		assert.Equal(t, 1, totalGroupPolicyRelationNumber, "Error in test case %v", n)
+		assert.Equal(t, 2, totalGroupPolicyRelationNumber, "Error in test case %v", n)
+
+		totalUserPolicyRelationNumber := getUserPolicyRelationCount(t, n, "", "")
+		assert.Equal(t, 2, totalUserPolicyRelationNumber, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetDeletedPolicyByName(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		policy     *Policy
+		statements []Statement
+		// Postgres Repo Args
+		org  string
+		name string
+		// Expected result
+		expectedResponse *api.Policy
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			org:  "org1",
+			name: "test",
+			policy: &Policy{
+				ID:       "1234",
+				Name:     "test",
+				Org:      "org1",
+				Path:     "/path/",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+				DeleteAt: now.UnixNano(),
+			},
+			statements: []Statement{
+				{
+					ID:              "0123",
+					Effect:          "allow",
+					PolicyVersionID: "1234",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+				},
+			},
+			expectedResponse: &api.Policy{
+				ID:       "1234",
+				Name:     "test",
+				Org:      "org1",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  1,
+				Statements: &[]api.Statement{
+					{
+						Effect: "allow",
+						Actions: []string{
+							api.USER_ACTION_GET_USER,
+						},
+						Resources: []string{
+							api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+						},
+					},
+				},
+			},
+		},
+		"ErrorCasePolicyNotDeleted": {
+			org:  "org1",
+			name: "test",
+			policy: &Policy{
+				ID:       "1234",
+				Name:     "test",
+				Org:      "org1",
+				Path:     "/path/",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+			},
+			expectedError: &database.Error{
+				Code:    database.POLICY_NOT_FOUND,
+				Message: "Deleted policy with organization org1 and name test not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean policy database
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+
+		// Insert previous data
+		if test.policy != nil {
+			insertPolicy(t, n, *test.policy, test.statements)
+		}
+		// Call to repository to get a deleted policy
+		receivedPolicy, err := repoDB.GetDeletedPolicyByName(test.org, test.name)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+
+			// Check response
+			assert.Equal(t, test.expectedResponse, receivedPolicy, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_RestorePolicy(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousPolicy Policy
+		// Postgres Repo Args
+		policyToRestore string
+	}{
+		"OkCase": {
+			previousPolicy: Policy{
+				ID:       "1234",
+				Name:     "test",
+				Org:      "org1",
+				Path:     "/path/",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+				DeleteAt: now.UnixNano(),
+			},
+			policyToRestore: "1234",
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean policy database
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+
+		// Insert previous data
+		insertPolicy(t, n, test.previousPolicy, []Statement{})
+
+		// Call to repository to restore policy
+		err := repoDB.RestorePolicy(test.policyToRestore)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check policy is active again
+		_, err = repoDB.GetPolicyByName(test.previousPolicy.Org, test.previousPolicy.Name)
+		assert.Nil(t, err, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_PurgePolicies(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousPolicies []Policy
+		// Postgres Repo Args
+		deleteBefore time.Time
+		// Expected result
+		expectedPurged   int
+		expectedPolicies int
+	}{
+		"OkCase": {
+			previousPolicies: []Policy{
+				{
+					ID:       "test1",
+					Name:     "test1",
+					Org:      "123",
+					Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test1"),
+					DeleteAt: now.Add(-2 * time.Hour).UnixNano(),
+				},
+				{
+					ID:       "test2",
+					Name:     "test2",
+					Org:      "123",
+					Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test2"),
+					DeleteAt: now.UnixNano(),
+				},
+				{
+					ID:   "test3",
+					Name: "test3",
+					Org:  "123",
+					Urn:  api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test3"),
+				},
+			},
+			deleteBefore:     now.Add(-time.Hour),
+			expectedPurged:   1,
+			expectedPolicies: 2,
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean policy database
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+		cleanGroupPolicyRelationTable(t, n)
+		cleanUserPolicyRelationTable(t, n)
+
+		// Insert previous data
+		for _, p := range test.previousPolicies {
+			insertPolicy(t, n, p, []Statement{
+				{
+					ID:              p.ID,
+					PolicyVersionID: p.ID,
+					Effect:          "allow",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+				},
+			})
+			insertGroupPolicyRelation(t, n, "GroupID", p.ID, now.UnixNano())
+			insertUserPolicyRelation(t, n, "UserID", p.ID, now.UnixNano())
+		}
+		// Call to repository to purge policies
+		purged, err := repoDB.PurgePolicies(test.deleteBefore)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedPurged, purged, "Error in test case %v", n)
+
+		// Check database
+		totalPolicyNumber := getPoliciesCountFiltered(t, n, "", "", "", "", 0, "")
+		assert.Equal(t, test.expectedPolicies, totalPolicyNumber, "Error in test case %v", n)
+		totalStatementNumber := getStatementsCountFiltered(t, n, "", "", "", "", "")
+		assert.Equal(t, test.expectedPolicies, totalStatementNumber, "Error in test case %v", n)
+		totalVersionNumber := getPolicyVersionsCountFiltered(t, n, "", 0, "")
+		assert.Equal(t, test.expectedPolicies, totalVersionNumber, "Error in test case %v", n)
+
+		// Check purged policy relations
+		relations := getGroupPolicyRelationCount(t, n, "", "")
+		assert.Equal(t, test.expectedPolicies, relations, "Error in test case %v", n)
+		relations = getUserPolicyRelationCount(t, n, "", "")
+		assert.Equal(t, test.expectedPolicies, relations, "Error in test case %v", n)
 	}
 }
 
@@ -678,11 +989,11 @@ func TestPostgresRepo_GetAttachedGroups(t *testing.T) {
 			},
 			statements: []Statement{
 				{
-					ID:        "test1",
-					PolicyID:  "test1",
-					Effect:    "allow",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					ID:              "test1",
+					PolicyVersionID: "test1",
+					Effect:          "allow",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 				},
 			},
 			filter: &api.Filter{
@@ -767,6 +1078,304 @@ func TestPostgresRepo_GetAttachedGroups(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_GetPolicyVersions(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		policy   *Policy
+		versions []PolicyVersion
+		// Postgres Repo Args
+		policyID string
+		filter   *api.Filter
+		// Expected result
+		expectedResponse []api.PolicyVersion
+		expectedTotal    int
+	}{
+		"OkCase": {
+			policy: &Policy{
+				ID:       "test1",
+				Name:     "test",
+				Org:      "123",
+				Path:     "/path/",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+			},
+			versions: []PolicyVersion{
+				{
+					ID:       "v2",
+					PolicyID: "test1",
+					Version:  2,
+					Author:   "user2",
+					CreateAt: now.UnixNano(),
+				},
+			},
+			policyID: "test1",
+			filter:   testFilter,
+			expectedResponse: []api.PolicyVersion{
+				{
+					ID:       "v2",
+					Version:  2,
+					Author:   "user2",
+					CreateAt: now,
+				},
+				{
+					ID:       "test1",
+					Version:  1,
+					Author:   "author",
+					CreateAt: now,
+				},
+			},
+			expectedTotal: 2,
+		},
+		"OkCaseNotFound": {
+			policyID:         "test1",
+			filter:           testFilter,
+			expectedResponse: []api.PolicyVersion{},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean policy database
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+
+		// Insert previous data
+		if test.policy != nil {
+			insertPolicy(t, n, *test.policy, nil)
+		}
+		for _, v := range test.versions {
+			insertPolicyVersion(t, n, v, nil)
+		}
+		receivedVersions, total, err := repoDB.GetPolicyVersions(test.policyID, test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check response
+		assert.Equal(t, test.expectedTotal, total, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, receivedVersions, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetPolicyVersion(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		version    *PolicyVersion
+		statements []Statement
+		// Postgres Repo Args
+		policyID      string
+		policyVersion int
+		// Expected result
+		expectedResponse *api.PolicyVersion
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			version: &PolicyVersion{
+				ID:       "v2",
+				PolicyID: "test1",
+				Version:  2,
+				Author:   "user2",
+				CreateAt: now.UnixNano(),
+			},
+			statements: []Statement{
+				{
+					ID:        "1",
+					Effect:    "allow",
+					Actions:   api.USER_ACTION_GET_USER,
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+				},
+			},
+			policyID:      "test1",
+			policyVersion: 2,
+			expectedResponse: &api.PolicyVersion{
+				ID:       "v2",
+				Version:  2,
+				Author:   "user2",
+				CreateAt: now,
+				Statements: &[]api.Statement{
+					{
+						Effect:    "allow",
+						Actions:   []string{api.USER_ACTION_GET_USER},
+						Resources: []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+					},
+				},
+			},
+		},
+		"ErrorCaseNotFound": {
+			policyID:      "test1",
+			policyVersion: 3,
+			expectedError: &database.Error{
+				Code:    database.POLICY_VERSION_NOT_FOUND,
+				Message: "Version 3 of policy with id test1 not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean policy database
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+
+		// Insert previous data
+		if test.version != nil {
+			insertPolicyVersion(t, n, *test.version, test.statements)
+		}
+		receivedVersion, err := repoDB.GetPolicyVersion(test.policyID, test.policyVersion)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse, receivedVersion, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_SetDefaultPolicyVersion(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		policy     Policy
+		version    PolicyVersion
+		statements []Statement
+		// Postgres Repo Args
+		policyID      string
+		policyVersion int
+		revision      int
+		updateAt      time.Time
+		// Expected result
+		expectedResponse *api.Policy
+		expectedError    error
+	}{
+		"OkCase": {
+			policy: Policy{
+				ID:       "test1",
+				Name:     "test",
+				Org:      "123",
+				Path:     "/path/",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  2,
+				Revision: 1,
+			},
+			version: PolicyVersion{
+				ID:       "v1",
+				PolicyID: "test1",
+				Version:  1,
+				Author:   "user1",
+				CreateAt: now.UnixNano(),
+			},
+			statements: []Statement{
+				{
+					ID:        "1",
+					Effect:    "deny",
+					Actions:   api.USER_ACTION_GET_USER,
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+				},
+			},
+			policyID:      "test1",
+			policyVersion: 1,
+			revision:      1,
+			updateAt:      now.Add(time.Minute),
+			expectedResponse: &api.Policy{
+				ID:       "test1",
+				Name:     "test",
+				Org:      "123",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now.Add(time.Minute),
+				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  1,
+				Revision: 2,
+				Statements: &[]api.Statement{
+					{
+						Effect:    "deny",
+						Actions:   []string{api.USER_ACTION_GET_USER},
+						Resources: []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+					},
+				},
+			},
+		},
+		"ErrorCaseRevisionMismatch": {
+			policy: Policy{
+				ID:       "test1",
+				Name:     "test",
+				Org:      "123",
+				Path:     "/path/",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  2,
+				Revision: 2,
+			},
+			version: PolicyVersion{
+				ID:       "v1",
+				PolicyID: "test1",
+				Version:  1,
+				Author:   "user1",
+				CreateAt: now.UnixNano(),
+			},
+			policyID:      "test1",
+			policyVersion: 1,
+			revision:      1,
+			updateAt:      now.Add(time.Minute),
+			expectedError: &database.Error{
+				Code:    database.REVISION_MISMATCH,
+				Message: "Policy with id test1 has been modified, revision 1 isn't the current one",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean policy database
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+
+		// Insert previous data
+		insertPolicy(t, n, test.policy, nil)
+		insertPolicyVersion(t, n, test.version, test.statements)
+
+		receivedPolicy, err := repoDB.SetDefaultPolicyVersion(test.policyID, test.policyVersion, test.revision, test.updateAt)
+		if test.expectedError != nil {
+			assert.Equal(t, test.expectedError, err, "Error in test case %v", n)
+			continue
+		}
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, receivedPolicy, "Error in test case %v", n)
+	}
+}
+
+func Test_dbPolicyVersionToAPIPolicyVersion(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		dbPolicyVersion  *PolicyVersion
+		apiPolicyVersion *api.PolicyVersion
+	}{
+		"OkCase": {
+			dbPolicyVersion: &PolicyVersion{
+				ID:       "v1",
+				PolicyID: "test1",
+				Version:  1,
+				Author:   "user1",
+				CreateAt: now.UnixNano(),
+			},
+			apiPolicyVersion: &api.PolicyVersion{
+				ID:       "v1",
+				Version:  1,
+				Author:   "user1",
+				CreateAt: now,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		receivedAPIPolicyVersion := dbPolicyVersionToAPIPolicyVersion(test.dbPolicyVersion)
+		// Check response
+		assert.Equal(t, test.apiPolicyVersion, receivedAPIPolicyVersion, "Error in test case %v", n)
+	}
+}
+
 func Test_dbPolicyToAPIPolicy(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -782,6 +1391,7 @@ func Test_dbPolicyToAPIPolicy(t *testing.T) {
 				CreateAt: now.UnixNano(),
 				UpdateAt: now.UnixNano(),
 				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  2,
 			},
 			apiPolicy: &api.Policy{
 				ID:       "test1",
@@ -791,6 +1401,7 @@ func Test_dbPolicyToAPIPolicy(t *testing.T) {
 				CreateAt: now,
 				UpdateAt: now,
 				Urn:      api.CreateUrn("123", api.RESOURCE_POLICY, "/path/", "test"),
+				Version:  2,
 			},
 		},
 	}
@@ -810,11 +1421,11 @@ func Test_dbStatementsToAPIStatements(t *testing.T) {
 		"OkCase": {
 			dbStatements: []Statement{
 				{
-					ID:        "0123",
-					Effect:    "allow",
-					PolicyID:  "1234",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					ID:              "0123",
+					Effect:          "allow",
+					PolicyVersionID: "1234",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 				},
 			},
 			apiStatements: &[]api.Statement{
@@ -832,18 +1443,18 @@ func Test_dbStatementsToAPIStatements(t *testing.T) {
 		"OkCase2": {
 			dbStatements: []Statement{
 				{
-					ID:        "0123",
-					Effect:    "allow",
-					PolicyID:  "1234",
-					Actions:   api.USER_ACTION_GET_USER,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					ID:              "0123",
+					Effect:          "allow",
+					PolicyVersionID: "1234",
+					Actions:         api.USER_ACTION_GET_USER,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
 				},
 				{
-					ID:        "4321",
-					Effect:    "deny",
-					PolicyID:  "1234",
-					Actions:   api.GROUP_ACTION_GET_GROUP + ";" + api.GROUP_ACTION_CREATE_GROUP,
-					Resources: api.GetUrnPrefix("", api.RESOURCE_GROUP, "/xxx/") + ";" + api.GetUrnPrefix("", api.RESOURCE_GROUP, "/xxx2/"),
+					ID:              "4321",
+					Effect:          "deny",
+					PolicyVersionID: "1234",
+					Actions:         api.GROUP_ACTION_GET_GROUP + ";" + api.GROUP_ACTION_CREATE_GROUP,
+					Resources:       api.GetUrnPrefix("", api.RESOURCE_GROUP, "/xxx/") + ";" + api.GetUrnPrefix("", api.RESOURCE_GROUP, "/xxx2/"),
 				},
 			},
 			apiStatements: &[]api.Statement{
@@ -869,6 +1480,29 @@ func Test_dbStatementsToAPIStatements(t *testing.T) {
 				},
 			},
 		},
+		"OkCaseNotActionsNotResources": {
+			dbStatements: []Statement{
+				{
+					ID:              "0123",
+					Effect:          "allow",
+					PolicyVersionID: "1234",
+					NotActions:      api.USER_ACTION_DELETE_USER + ";" + api.USER_ACTION_UPDATE_USER,
+					NotResources:    api.GetUrnPrefix("", api.RESOURCE_USER, "/admin/"),
+				},
+			},
+			apiStatements: &[]api.Statement{
+				{
+					Effect: "allow",
+					NotActions: []string{
+						api.USER_ACTION_DELETE_USER,
+						api.USER_ACTION_UPDATE_USER,
+					},
+					NotResources: []string{
+						api.GetUrnPrefix("", api.RESOURCE_USER, "/admin/"),
+					},
+				},
+			},
+		},
 	}
 
 	for n, test := range testcases {
@@ -904,3 +1538,30 @@ func Test_stringArrayToString(t *testing.T) {
 		assert.Equal(t, test.expectedString, receivedString, "Error in test case %v", n)
 	}
 }
+
+func Test_stringToStringArray(t *testing.T) {
+	testcases := map[string]struct {
+		stringVal     string
+		expectedArray []string
+	}{
+		"OkCase": {
+			stringVal: "asd;123;456;zxc",
+			expectedArray: []string{
+				"asd",
+				"123",
+				"456",
+				"zxc",
+			},
+		},
+		"OkCaseEmpty": {
+			stringVal:     "",
+			expectedArray: nil,
+		},
+	}
+
+	for n, test := range testcases {
+		receivedArray := stringToStringArray(test.stringVal)
+		// Check response
+		assert.Equal(t, test.expectedArray, receivedArray, "Error in test case %v", n)
+	}
+}
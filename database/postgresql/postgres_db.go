@@ -3,18 +3,39 @@ package postgresql
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
 	"github.com/jinzhu/gorm"
-	_ "github.com/lib/pq" //GORM needs to import the lib/pq driver
+	"github.com/lib/pq"
 )
 
 type PostgresRepo struct {
 	Dbmap *gorm.DB
+	// Repo is bound to a transaction started by WithTransaction
+	inTransaction bool
 }
 
+// InitDb connects to the database and checks that its schema version is the one required by this binary
 func InitDb(datasourcename string, idleConns string, maxOpenConns string, connTTL string) (*gorm.DB, error) {
+	db, err := OpenDb(datasourcename, idleConns, maxOpenConns, connTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schema is only changed by migrations
+	if err = CheckSchemaVersion(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// OpenDb connects to the database without checking its schema, e.g. to migrate it
+func OpenDb(datasourcename string, idleConns string, maxOpenConns string, connTTL string) (*gorm.DB, error) {
 	// connect to db using GORM - github.com/jinzhu/gorm
 	db, err := gorm.Open("postgres", datasourcename)
 	if err != nil {
@@ -44,24 +65,193 @@ func InitDb(datasourcename string, idleConns string, maxOpenConns string, connTT
 		return nil, err
 	}
 
-	// Create tables if not exist
-	err = db.AutoMigrate(&User{}, &Group{}, &Policy{}, &Statement{}, &GroupUserRelation{}, &GroupPolicyRelation{},
-		&ProxyResource{}, &OidcProvider{}, &OidcClient{}).Error
-	if err != nil {
-		return nil, err
+	return db, nil
+}
+
+// TRANSACTION REPOSITORY IMPLEMENTATION
+
+func (pr PostgresRepo) WithTransaction(f func(repos api.Repos) error) error {
+	// Nested transactions are joined to the outer one
+	if pr.inTransaction {
+		return f(pr.repos())
 	}
 
-	return db, nil
+	transaction := pr.Dbmap.Begin()
+	if err := transaction.Error; err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	txRepo := PostgresRepo{
+		Dbmap:         transaction,
+		inTransaction: true,
+	}
+	if err := f(txRepo.repos()); err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit().Error; err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+// Aux method to use this repo as every repository
+func (pr PostgresRepo) repos() api.Repos {
+	return api.Repos{
+		UserRepo:         pr,
+		GroupRepo:        pr,
+		PolicyRepo:       pr,
+		ProxyRepo:        pr,
+		AuthOidcRepo:     pr,
+		OrganizationRepo: pr,
+		TransactionRepo:  pr,
+	}
+}
+
+// Aux methods used by repository operations that need a transaction. When the repo is already bound
+// to a transaction, operations run inside it and the outer transaction decides whether to commit.
+func (pr PostgresRepo) begin() *gorm.DB {
+	if pr.inTransaction {
+		return pr.Dbmap
+	}
+	return pr.Dbmap.Begin()
+}
+
+func (pr PostgresRepo) commit(transaction *gorm.DB) {
+	if !pr.inTransaction {
+		transaction.Commit()
+	}
+}
+
+func (pr PostgresRepo) rollback(transaction *gorm.DB) {
+	if !pr.inTransaction {
+		transaction.Rollback()
+	}
+}
+
+// Aux method used to read entities that operations depend on. Inside a transaction rows read are locked
+// until it finishes, so concurrent operations can't delete or modify them in the meantime.
+func (pr PostgresRepo) lockRows() *gorm.DB {
+	if pr.inTransaction {
+		return pr.Dbmap.Set("gorm:query_option", "FOR SHARE")
+	}
+	return pr.Dbmap
+}
+
+// Aux method that runs the queries to delete soft deleted entities and their relations in a transaction.
+// Every query receives deleteBefore as its arguments, it returns the rows deleted by the last one.
+func (pr PostgresRepo) purge(queries []string, deleteBefore time.Time) (int, error) {
+	transaction := pr.begin()
+
+	var deleted int64
+	for _, query := range queries {
+		args := make([]interface{}, strings.Count(query, "?"))
+		for i := range args {
+			args[i] = deleteBefore.UnixNano()
+		}
+		result := transaction.Exec(query, args...)
+		if err := result.Error; err != nil {
+			pr.rollback(transaction)
+			return 0, &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: err.Error(),
+			}
+		}
+		deleted = result.RowsAffected
+	}
+
+	pr.commit(transaction)
+	return int(deleted), nil
+}
+
+// Aux method that returns the condition to select deleted or not deleted rows according to filter
+func deletedCondition(filter *api.Filter) string {
+	if filter.Deleted {
+		return "delete_at > 0"
+	}
+	return "delete_at = 0"
+}
+
+// Aux method that returns the error of an update or deletion of a revision that isn't the current one
+func revisionMismatchError(entity string, id string, revision int) error {
+	return &database.Error{
+		Code:    database.REVISION_MISMATCH,
+		Message: fmt.Sprintf("%v with id %v has been modified, revision %v isn't the current one", entity, id, revision),
+	}
+}
+
+// Aux method that returns the error of a query that stores an entity or relation. Unique violations are
+// returned with the given code, since a concurrent operation has already stored it.
+func storeError(err error, code string, message string) error {
+	if pqError, ok := err.(*pq.Error); ok && pqError.Code.Name() == "unique_violation" {
+		return &database.Error{
+			Code:    code,
+			Message: message,
+		}
+	}
+	return &database.Error{
+		Code:    database.INTERNAL_ERROR,
+		Message: err.Error(),
+	}
+}
+
+// Aux method that paginates the query according to filter. Rows are sorted by creation date and idColumn
+// unless filter has its own order, so they can be retrieved after the filter cursor.
+func paginate(query *gorm.DB, filter *api.Filter, idColumn string) *gorm.DB {
+	if len(filter.OrderBy) > 0 {
+		query = query.Order(filter.OrderBy)
+	} else {
+		query = query.Order("create_at, " + idColumn)
+	}
+	if filter.Cursor != nil {
+		query = query.Where("(create_at, "+idColumn+") > (?, ?)", filter.Cursor.CreateAt.UnixNano(), filter.Cursor.ID)
+	}
+	return query.Offset(filter.Offset).Limit(filter.Limit)
+}
+
+// Escape wildcards of a value searched with like
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Aux method that applies filter name search and date ranges to the query, nameColumn is the column searched by name
+func search(query *gorm.DB, filter *api.Filter, nameColumn string) *gorm.DB {
+	if len(filter.NamePrefix) > 0 {
+		query = query.Where(nameColumn+" like ?", likeEscaper.Replace(filter.NamePrefix)+"%")
+	}
+	if len(filter.NameContains) > 0 {
+		query = query.Where(nameColumn+" like ?", "%"+likeEscaper.Replace(filter.NameContains)+"%")
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("create_at >= ?", filter.CreatedAfter.UnixNano())
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("create_at < ?", filter.CreatedBefore.UnixNano())
+	}
+	if !filter.UpdatedAfter.IsZero() {
+		query = query.Where("update_at >= ?", filter.UpdatedAfter.UnixNano())
+	}
+	if !filter.UpdatedBefore.IsZero() {
+		query = query.Where("update_at < ?", filter.UpdatedBefore.UnixNano())
+	}
+	return query
 }
 
 // User table
 type User struct {
 	ID         string `gorm:"primary_key"`
-	ExternalID string `gorm:"not null;unique"`
+	ExternalID string `gorm:"not null"`
 	Path       string `gorm:"not null"`
 	CreateAt   int64  `gorm:"not null"`
 	UpdateAt   int64  `gorm:"not null"`
-	Urn        string `gorm:"not null;unique"`
+	Urn        string `gorm:"not null"`
+	DeleteAt   int64  `gorm:"not null;default:0"`
+	Revision   int    `gorm:"not null;default:1"`
 }
 
 // User's table name
@@ -77,7 +267,9 @@ type Group struct {
 	Org      string `gorm:"not null"`
 	CreateAt int64  `gorm:"not null"`
 	UpdateAt int64  `gorm:"not null"`
-	Urn      string `gorm:"not null;unique"`
+	Urn      string `gorm:"not null"`
+	DeleteAt int64  `gorm:"not null;default:0"`
+	Revision int    `gorm:"not null;default:1"`
 }
 
 // Group's table name
@@ -93,7 +285,10 @@ type Policy struct {
 	Org      string `gorm:"not null"`
 	CreateAt int64  `gorm:"not null"`
 	UpdateAt int64  `gorm:"not null"`
-	Urn      string `gorm:"not null;unique"`
+	Urn      string `gorm:"not null"`
+	Version  int    `gorm:"not null;default:1"`
+	DeleteAt int64  `gorm:"not null;default:0"`
+	Revision int    `gorm:"not null;default:1"`
 }
 
 // Policy's table name
@@ -101,13 +296,29 @@ func (Policy) TableName() string {
 	return "policies"
 }
 
+// Policy version table
+type PolicyVersion struct {
+	ID       string `gorm:"primary_key"`
+	PolicyID string `gorm:"not null;unique_index:idx_policy_version"`
+	Version  int    `gorm:"not null;unique_index:idx_policy_version"`
+	Author   string `gorm:"not null"`
+	CreateAt int64  `gorm:"not null"`
+}
+
+// PolicyVersion's table name
+func (PolicyVersion) TableName() string {
+	return "policy_versions"
+}
+
 // Statement table
 type Statement struct {
-	ID        string `gorm:"primary_key"`
-	PolicyID  string `gorm:"not null"`
-	Effect    string `gorm:"not null"`
-	Actions   string `gorm:"not null"`
-	Resources string `gorm:"not null"`
+	ID              string `gorm:"primary_key"`
+	PolicyVersionID string `gorm:"not null"`
+	Effect          string `gorm:"not null"`
+	Actions         string `gorm:"not null"`
+	NotActions      string `gorm:"not null;default:''"`
+	Resources       string `gorm:"not null"`
+	NotResources    string `gorm:"not null;default:''"`
 }
 
 // Statement's table name
@@ -127,6 +338,18 @@ func (GroupUserRelation) TableName() string {
 	return "group_user_relations"
 }
 
+// Group-Subgroups Relationship
+type GroupSubgroupRelation struct {
+	GroupID    string `gorm:"primary_key"`
+	SubgroupID string `gorm:"primary_key"`
+	CreateAt   int64  `gorm:"not null"`
+}
+
+// GroupSubgroupRelation's table name
+func (GroupSubgroupRelation) TableName() string {
+	return "group_subgroup_relations"
+}
+
 // Group Policy table
 type GroupPolicyRelation struct {
 	GroupID  string `gorm:"primary_key"`
@@ -139,27 +362,51 @@ func (GroupPolicyRelation) TableName() string {
 	return "group_policy_relations"
 }
 
+// User Policy table
+type UserPolicyRelation struct {
+	UserID   string `gorm:"primary_key"`
+	PolicyID string `gorm:"primary_key"`
+	CreateAt int64  `gorm:"not null"`
+}
+
+// UserPolicyRelation's table name
+func (UserPolicyRelation) TableName() string {
+	return "user_policy_relations"
+}
+
 func (pr PostgresRepo) OrderByValidColumns(action string) []string {
 	switch action {
 	case api.USER_ACTION_LIST_USERS:
 		return []string{"path", "external_id", "create_at", "update_at", "urn"}
 	case api.USER_ACTION_LIST_GROUPS_FOR_USER:
 		return []string{"create_at"}
+	case api.USER_ACTION_LIST_ATTACHED_USER_POLICIES:
+		return []string{"create_at"}
 	case api.GROUP_ACTION_LIST_GROUPS:
 		return []string{"name", "path", "org", "create_at", "update_at", "urn"}
 	case api.GROUP_ACTION_LIST_MEMBERS:
 		return []string{"create_at"}
 	case api.GROUP_ACTION_LIST_ATTACHED_GROUP_POLICIES:
 		return []string{"create_at"}
+	case api.GROUP_ACTION_LIST_SUBGROUPS:
+		return []string{"create_at"}
 	case api.POLICY_ACTION_LIST_POLICIES:
 		return []string{"name", "path", "org", "create_at", "update_at", "urn"}
 	case api.POLICY_ACTION_LIST_ATTACHED_GROUPS:
 		return []string{"create_at"}
+	case api.POLICY_ACTION_LIST_POLICY_VERSIONS:
+		return []string{"version", "create_at"}
 	case api.PROXY_ACTION_LIST_RESOURCES:
 		return []string{"name", "path", "org", "host", "path_resource", "method",
 			"urn_resource", "urn", "action", "create_at", "update_at"}
 	case api.AUTH_OIDC_ACTION_LIST_PROVIDERS:
 		return []string{"name", "path", "create_at", "update_at", "urn"}
+	case api.ORGANIZATION_ACTION_LIST_ORGANIZATIONS:
+		return []string{"name", "path", "create_at", "update_at", "urn"}
+	case api.WEBHOOK_ACTION_LIST_WEBHOOKS:
+		return []string{"name", "url", "create_at", "update_at"}
+	case api.WEBHOOK_ACTION_LIST_DELIVERIES:
+		return []string{"event_type", "status", "attempts", "next_attempt_at", "create_at", "update_at"}
 	default:
 		return nil
 	}
@@ -179,6 +426,7 @@ type ProxyResource struct {
 	Action       string `gorm:"not null;unique_index:idx_resource"`
 	CreateAt     int64  `gorm:"not null"`
 	UpdateAt     int64  `gorm:"not null"`
+	Revision     int    `gorm:"not null;default:1"`
 }
 
 // ProxyResource's table name
@@ -213,3 +461,55 @@ type OidcClient struct {
 func (OidcClient) TableName() string {
 	return "oidc_clients"
 }
+
+// Organization table
+type Organization struct {
+	ID       string `gorm:"primary_key"`
+	Name     string `gorm:"not null;unique"`
+	Path     string `gorm:"not null"`
+	CreateAt int64  `gorm:"not null"`
+	UpdateAt int64  `gorm:"not null"`
+	Urn      string `gorm:"not null;unique"`
+}
+
+// Organization's table name
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// Webhook table
+type Webhook struct {
+	ID       string `gorm:"primary_key"`
+	Name     string `gorm:"not null;unique"`
+	URL      string `gorm:"column:url;not null"`
+	Secret   string `gorm:"not null"`
+	Events   string `gorm:"not null"`
+	CreateAt int64  `gorm:"not null"`
+	UpdateAt int64  `gorm:"not null"`
+}
+
+// Webhook's table name
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Webhook delivery table
+type WebhookDelivery struct {
+	ID            string `gorm:"primary_key"`
+	WebhookID     string `gorm:"not null"`
+	EventID       string `gorm:"not null"`
+	EventType     string `gorm:"not null"`
+	Payload       string `gorm:"not null"`
+	Status        string `gorm:"not null"`
+	Attempts      int    `gorm:"not null;default:0"`
+	ResponseCode  int    `gorm:"not null;default:0"`
+	Error         string `gorm:"not null;default:''"`
+	NextAttemptAt int64  `gorm:"not null"`
+	CreateAt      int64  `gorm:"not null"`
+	UpdateAt      int64  `gorm:"not null"`
+}
+
+// WebhookDelivery's table name
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -44,12 +44,16 @@ func (pr PostgresRepo) GetProxyResources(filter *api.Filter) ([]api.ProxyResourc
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ? ", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
+	query = search(query, filter, "name")
 
 	// Error handling
-	if err := query.Find(&resources).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&resources).Error; err != nil {
+	if err := query.Model(&ProxyResource{}).Count(&total).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if err := paginate(query, filter, "id").Find(&resources).Error; err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -83,6 +87,7 @@ func (pr PostgresRepo) AddProxyResource(proxyResource api.ProxyResource) (*api.P
 		Urn:          proxyResource.Urn,
 		CreateAt:     proxyResource.CreateAt.UnixNano(),
 		UpdateAt:     proxyResource.UpdateAt.UnixNano(),
+		Revision:     1,
 	}
 
 	// Store proxyResource
@@ -90,10 +95,8 @@ func (pr PostgresRepo) AddProxyResource(proxyResource api.ProxyResource) (*api.P
 
 	// Error handling
 	if err != nil {
-		return nil, &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		return nil, storeError(err, database.PROXY_RESOURCE_ALREADY_EXIST,
+			fmt.Sprintf("Proxy resource with organization %v and name %v already exist", proxyResource.Org, proxyResource.Name))
 	}
 
 	return dbResourceToApiResource(proxyResourceDB), nil
@@ -113,25 +116,28 @@ func (pr PostgresRepo) UpdateProxyResource(proxyResource api.ProxyResource) (*ap
 		Urn:          proxyResource.Urn,
 		CreateAt:     proxyResource.CreateAt.UnixNano(),
 		UpdateAt:     proxyResource.UpdateAt.UnixNano(),
+		Revision:     proxyResource.Revision + 1,
 	}
 
-	// Store proxyResource
-	query := pr.Dbmap.Model(&ProxyResource{ID: proxyResource.ID}).Updates(proxyResourceDB)
+	// Store proxyResource if it wasn't modified after the revision retrieved
+	query := pr.Dbmap.Model(&ProxyResource{ID: proxyResource.ID}).Where("revision = ?", proxyResource.Revision).Updates(proxyResourceDB)
 
 	// Error Handling
 	if err := query.Error; err != nil {
-		return nil, &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		return nil, storeError(err, database.PROXY_RESOURCE_ALREADY_EXIST,
+			fmt.Sprintf("Proxy resource with organization %v and name %v already exist", proxyResource.Org, proxyResource.Name))
+	}
+	if query.RowsAffected == 0 {
+		return nil, revisionMismatchError("Proxy resource", proxyResource.ID, proxyResource.Revision)
 	}
 
+	proxyResource.Revision = proxyResourceDB.Revision
 	return &proxyResource, nil
 }
 
-func (pr PostgresRepo) RemoveProxyResource(id string) error {
+func (pr PostgresRepo) RemoveProxyResource(id string, revision int) error {
 	// Remove proxy resource
-	query := pr.Dbmap.Where("id like ?", id).Delete(&ProxyResource{})
+	query := pr.Dbmap.Where("id like ? AND revision = ?", id, revision).Delete(&ProxyResource{})
 
 	// Error handling
 	if err := query.Error; err != nil {
@@ -140,6 +146,9 @@ func (pr PostgresRepo) RemoveProxyResource(id string) error {
 			Message: err.Error(),
 		}
 	}
+	if query.RowsAffected == 0 {
+		return revisionMismatchError("Proxy resource", id, revision)
+	}
 
 	return nil
 }
@@ -163,5 +172,6 @@ func dbResourceToApiResource(pr *ProxyResource) *api.ProxyResource {
 		Urn:      pr.Urn,
 		CreateAt: time.Unix(0, pr.CreateAt).UTC(),
 		UpdateAt: time.Unix(0, pr.UpdateAt).UTC(),
+		Revision: pr.Revision,
 	}
 }
@@ -326,6 +326,7 @@ func TestPostgresRepo_AddProxyResource(t *testing.T) {
 				Urn:      "urn",
 				CreateAt: now,
 				UpdateAt: now,
+				Revision: 1,
 			},
 		},
 		"ErrorCaseUserAlreadyExist": {
@@ -360,8 +361,8 @@ func TestPostgresRepo_AddProxyResource(t *testing.T) {
 				UpdateAt: now,
 			},
 			expectedError: &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: "pq: duplicate key value violates unique constraint \"proxy_resources_pkey\"",
+				Code:    database.PROXY_RESOURCE_ALREADY_EXIST,
+				Message: "Proxy resource with organization org and name name already exist",
 			},
 		},
 	}
@@ -418,6 +419,7 @@ func TestPostgresRepo_UpdateProxyResource(t *testing.T) {
 					Urn:          "urn",
 					CreateAt:     now.UnixNano(),
 					UpdateAt:     now.UnixNano(),
+					Revision:     1,
 				},
 			},
 			proxyResourceToUpdate: &api.ProxyResource{
@@ -435,6 +437,7 @@ func TestPostgresRepo_UpdateProxyResource(t *testing.T) {
 				Urn:      "urn",
 				CreateAt: now,
 				UpdateAt: now,
+				Revision: 1,
 			},
 			expectedResponse: &api.ProxyResource{
 				ID:   "ID",
@@ -451,6 +454,7 @@ func TestPostgresRepo_UpdateProxyResource(t *testing.T) {
 				Urn:      "urn",
 				CreateAt: now,
 				UpdateAt: now,
+				Revision: 2,
 			},
 		},
 	}
@@ -490,6 +494,7 @@ func TestPostgresRepo_RemoveProxyResource(t *testing.T) {
 		previousProxyResources []ProxyResource
 		// Postgres Repo Args
 		proxyResourceToDelete string
+		revision              int
 	}{
 		"OKCase": {
 			previousProxyResources: []ProxyResource{
@@ -506,6 +511,7 @@ func TestPostgresRepo_RemoveProxyResource(t *testing.T) {
 					UrnResource:  "urnResource",
 					CreateAt:     now.UnixNano(),
 					UpdateAt:     now.UnixNano(),
+					Revision:     1,
 				},
 				{
 					ID:           "PrID2",
@@ -523,6 +529,7 @@ func TestPostgresRepo_RemoveProxyResource(t *testing.T) {
 				},
 			},
 			proxyResourceToDelete: "PrID1",
+			revision:              1,
 		},
 	}
 
@@ -538,7 +545,7 @@ func TestPostgresRepo_RemoveProxyResource(t *testing.T) {
 		}
 
 		// Call to repository to remove proxy resource
-		err := repoDB.RemoveProxyResource(test.proxyResourceToDelete)
+		err := repoDB.RemoveProxyResource(test.proxyResourceToDelete, test.revision)
 		assert.Nil(t, err, "Error in test case %v", n)
 
 		// Check database
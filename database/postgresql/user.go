@@ -19,6 +19,7 @@ func (pr PostgresRepo) AddUser(user api.User) (*api.User, error) {
 		CreateAt:   user.CreateAt.UnixNano(),
 		UpdateAt:   user.UpdateAt.UnixNano(),
 		Urn:        user.Urn,
+		Revision:   1,
 	}
 
 	// Store user
@@ -26,10 +27,8 @@ func (pr PostgresRepo) AddUser(user api.User) (*api.User, error) {
 
 	// Error handling
 	if err != nil {
-		return nil, &database.Error{
-			Code:    database.INTERNAL_ERROR,
-			Message: err.Error(),
-		}
+		return nil, storeError(err, database.USER_ALREADY_EXIST,
+			fmt.Sprintf("User with externalId %v already exist", user.ExternalID))
 	}
 
 	return dbUserToAPIUser(userDB), nil
@@ -37,7 +36,7 @@ func (pr PostgresRepo) AddUser(user api.User) (*api.User, error) {
 
 func (pr PostgresRepo) GetUserByExternalID(id string) (*api.User, error) {
 	user := &User{}
-	query := pr.Dbmap.Where("external_id like ?", id).First(user)
+	query := pr.lockRows().Where("external_id like ? AND delete_at = 0", id).First(user)
 
 	// Check if user exists
 	if query.RecordNotFound() {
@@ -84,17 +83,21 @@ func (pr PostgresRepo) GetUserByID(id string) (*api.User, error) {
 func (pr PostgresRepo) GetUsersFiltered(filter *api.Filter) ([]api.User, int, error) {
 	var total int
 	users := []User{}
-	query := pr.Dbmap
+	query := pr.Dbmap.Where(deletedCondition(filter))
 
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ?", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
+	query = search(query, filter, "external_id")
 
 	// Error handling
-	if err := query.Find(&users).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error; err != nil {
+	if err := query.Model(&User{}).Count(&total).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if err := paginate(query, filter, "id").Find(&users).Error; err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -121,10 +124,11 @@ func (pr PostgresRepo) UpdateUser(user api.User) (*api.User, error) {
 		CreateAt:   user.CreateAt.UnixNano(),
 		UpdateAt:   user.UpdateAt.UnixNano(),
 		Urn:        user.Urn,
+		Revision:   user.Revision + 1,
 	}
 
-	// Update user
-	query := pr.Dbmap.Model(&User{ID: user.ID}).Updates(userDB)
+	// Update user if it wasn't modified after the revision retrieved
+	query := pr.Dbmap.Model(&User{ID: user.ID}).Where("revision = ?", user.Revision).Updates(userDB)
 
 	// Error Handling
 	if err := query.Error; err != nil {
@@ -133,40 +137,78 @@ func (pr PostgresRepo) UpdateUser(user api.User) (*api.User, error) {
 			Message: err.Error(),
 		}
 	}
+	if query.RowsAffected == 0 {
+		return nil, revisionMismatchError("User", user.ID, user.Revision)
+	}
 
+	user.Revision = userDB.Revision
 	return &user, nil
 }
 
-func (pr PostgresRepo) RemoveUser(id string) error {
-	transaction := pr.Dbmap.Begin()
-	// Delete user
-	transaction.Where("id like ?", id).Delete(&User{})
+func (pr PostgresRepo) RemoveUser(id string, revision int) error {
+	// Mark user as deleted, its relations are kept until it's purged
+	query := pr.Dbmap.Model(&User{}).Where("id like ? AND revision = ?", id, revision).UpdateColumn("delete_at", time.Now().UTC().UnixNano())
 
 	// Error handling
-	if err := transaction.Error; err != nil {
-		transaction.Rollback()
+	if err := query.Error; err != nil {
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
+	if query.RowsAffected == 0 {
+		return revisionMismatchError("User", id, revision)
+	}
 
-	//  delete all user relations
-	transaction.Where("user_id like ?", id).Delete(&GroupUserRelation{})
+	return nil
+}
 
-	// Error handling
-	if err := transaction.Error; err != nil {
-		transaction.Rollback()
-		return &database.Error{
+func (pr PostgresRepo) GetDeletedUserByExternalID(id string) (*api.User, error) {
+	user := &User{}
+	query := pr.Dbmap.Where("external_id like ? AND delete_at > 0", id).Order("delete_at desc").First(user)
+
+	// Check if user exists
+	if query.RecordNotFound() {
+		return nil, &database.Error{
+			Code:    database.USER_NOT_FOUND,
+			Message: fmt.Sprintf("Deleted user with externalId %v not found", id),
+		}
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
-	transaction.Commit()
+	return dbUserToAPIUser(user), nil
+}
+
+func (pr PostgresRepo) RestoreUser(id string) error {
+	err := pr.Dbmap.Model(&User{}).Where("id like ?", id).UpdateColumn("delete_at", 0).Error
+
+	// Error handling
+	if err != nil {
+		return storeError(err, database.USER_ALREADY_EXIST,
+			fmt.Sprintf("User with id %v can't be restored, its externalId is in use", id))
+	}
+
 	return nil
 }
 
+func (pr PostgresRepo) PurgeUsers(deleteBefore time.Time) (int, error) {
+	deletedUsers := "SELECT id FROM users WHERE delete_at > 0 AND delete_at < ?"
+
+	// Delete users with their group and policy relations
+	return pr.purge([]string{
+		"DELETE FROM group_user_relations WHERE user_id IN (" + deletedUsers + ")",
+		"DELETE FROM user_policy_relations WHERE user_id IN (" + deletedUsers + ")",
+		"DELETE FROM users WHERE delete_at > 0 AND delete_at < ?",
+	}, deleteBefore)
+}
+
 func (pr PostgresRepo) GetGroupsByUserID(id string, filter *api.Filter) ([]api.UserGroupRelation, int, error) {
 	var total int
 	relations := []GroupUserRelation{}
@@ -176,7 +218,7 @@ func (pr PostgresRepo) GetGroupsByUserID(id string, filter *api.Filter) ([]api.U
 		query = query.Order(filter.OrderBy)
 	}
 
-	query.Where("user_id like ?", id).Find(&relations).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&relations)
+	query.Where("user_id like ? AND group_id NOT IN (SELECT id FROM groups WHERE delete_at > 0)", id).Find(&relations).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&relations)
 
 	// Error Handling
 	if err := query.Error; err != nil {
@@ -209,6 +251,102 @@ func (pr PostgresRepo) GetGroupsByUserID(id string, filter *api.Filter) ([]api.U
 	return groups, total, nil
 }
 
+func (pr PostgresRepo) AttachPolicyToUser(userID string, policyID string) error {
+	// Create relation
+	relation := &UserPolicyRelation{
+		UserID:   userID,
+		PolicyID: policyID,
+		CreateAt: time.Now().UTC().UnixNano(),
+	}
+
+	// Store relation
+	err := pr.Dbmap.Create(relation).Error
+
+	// Error handling
+	if err != nil {
+		return storeError(err, database.USER_POLICY_RELATION_ALREADY_EXIST,
+			fmt.Sprintf("Policy with id %v is already attached to user with id %v", policyID, userID))
+	}
+
+	return nil
+}
+
+func (pr PostgresRepo) DetachPolicyFromUser(userID string, policyID string) error {
+	// Remove relation
+	err := pr.Dbmap.Where("user_id like ? AND policy_id like ?", userID, policyID).Delete(&UserPolicyRelation{}).Error
+
+	// Error handling
+	if err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (pr PostgresRepo) IsAttachedToUser(userID string, policyID string) (bool, error) {
+	relation := UserPolicyRelation{}
+	query := pr.Dbmap.Where("user_id like ? AND policy_id like ?", userID, policyID).First(&relation)
+
+	// Check if relation exists
+	if query.RecordNotFound() {
+		return false, nil
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return false, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return true, nil
+}
+
+func (pr PostgresRepo) GetAttachedUserPolicies(userID string, filter *api.Filter) ([]api.PolicyUserRelation, int, error) {
+	var total int
+	relations := []UserPolicyRelation{}
+	query := pr.Dbmap.Where("user_id like ? AND policy_id NOT IN (SELECT id FROM policies WHERE delete_at > 0)", userID)
+
+	if len(filter.OrderBy) > 0 {
+		query = query.Order(filter.OrderBy)
+	}
+
+	// Error Handling
+	if err := query.Find(&relations).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&relations).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	var policies []api.PolicyUserRelation
+	// Transform relations to API domain
+	if relations != nil {
+		policies = make([]api.PolicyUserRelation, len(relations), cap(relations))
+		for i, r := range relations {
+			policy, err := pr.GetPolicyById(r.PolicyID)
+			// Error handling
+			if err != nil {
+				return nil, total, &database.Error{
+					Code:    database.INTERNAL_ERROR,
+					Message: err.Error(),
+				}
+			}
+
+			policies[i] = &PolicyUser{
+				Policy:   policy,
+				CreateAt: time.Unix(0, r.CreateAt).UTC(),
+			}
+		}
+	}
+
+	return policies, total, nil
+}
+
 // PRIVATE HELPER METHODS
 
 // Transform a user retrieved from db into a user for API
@@ -220,5 +358,6 @@ func dbUserToAPIUser(userdb *User) *api.User {
 		CreateAt:   time.Unix(0, userdb.CreateAt).UTC(),
 		UpdateAt:   time.Unix(0, userdb.UpdateAt).UTC(),
 		Urn:        userdb.Urn,
+		Revision:   userdb.Revision,
 	}
 }
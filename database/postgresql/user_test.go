@@ -36,6 +36,7 @@ func TestPostgresRepo_AddUser(t *testing.T) {
 				Urn:        "urn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Revision:   1,
 			},
 		},
 		"ErrorCaseUserAlreadyExist": {
@@ -56,8 +57,8 @@ func TestPostgresRepo_AddUser(t *testing.T) {
 				UpdateAt:   now,
 			},
 			expectedError: &database.Error{
-				Code:    database.INTERNAL_ERROR,
-				Message: "pq: duplicate key value violates unique constraint \"users_pkey\"",
+				Code:    database.USER_ALREADY_EXIST,
+				Message: "User with externalId ExternalID already exist",
 			},
 		},
 	}
@@ -236,6 +237,7 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 		filter *api.Filter
 		// Expected result
 		expectedResponse []api.User
+		expectedTotal    int
 	}{
 		"OkCase1": {
 			previousUsers: []User{
@@ -280,6 +282,7 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 					UpdateAt:   now,
 				},
 			},
+			expectedTotal: 2,
 		},
 		"OkCase2": {
 			previousUsers: []User{
@@ -315,6 +318,7 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 					UpdateAt:   now,
 				},
 			},
+			expectedTotal: 1,
 		},
 		"OkCase3": {
 			previousUsers: []User{
@@ -342,6 +346,91 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 			},
 			expectedResponse: []api.User{},
 		},
+		"OkCaseCursor": {
+			previousUsers: []User{
+				{
+					ID:         "UserID1",
+					ExternalID: "ExternalID1",
+					Path:       "Path123",
+					Urn:        "urn1",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID2",
+					Path:       "Path456",
+					Urn:        "urn2",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			filter: &api.Filter{
+				PathPrefix: "Path",
+				Limit:      20,
+				Cursor: &api.Cursor{
+					CreateAt: now,
+					ID:       "UserID1",
+				},
+			},
+			expectedResponse: []api.User{
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID2",
+					Path:       "Path456",
+					Urn:        "urn2",
+					CreateAt:   now,
+					UpdateAt:   now,
+				},
+			},
+			expectedTotal: 2,
+		},
+		"OkCaseSearch": {
+			previousUsers: []User{
+				{
+					ID:         "UserID1",
+					ExternalID: "ExternalID1",
+					Path:       "Path123",
+					Urn:        "urn1",
+					CreateAt:   now.Add(-time.Hour).UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID2",
+					Path:       "Path456",
+					Urn:        "urn2",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID3",
+					ExternalID: "Other_ID3",
+					Path:       "Path789",
+					Urn:        "urn3",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			filter: &api.Filter{
+				PathPrefix:   "Path",
+				Limit:        20,
+				NameContains: "ID",
+				NamePrefix:   "External",
+				CreatedAfter: now.Add(-time.Minute),
+			},
+			expectedResponse: []api.User{
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID2",
+					Path:       "Path456",
+					Urn:        "urn2",
+					CreateAt:   now,
+					UpdateAt:   now,
+				},
+			},
+			expectedTotal: 1,
+		},
 	}
 
 	for n, test := range testcases {
@@ -359,7 +448,7 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 		assert.Nil(t, err, "Error in test case %v", n)
 
 		// Check total
-		assert.Equal(t, len(test.expectedResponse), total, "Error in test case %v", n)
+		assert.Equal(t, test.expectedTotal, total, "Error in test case %v", n)
 
 		// Check response
 		assert.Equal(t, test.expectedResponse, receivedUsers, "Error in test case %v", n)
@@ -375,6 +464,7 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 		userToUpdate *api.User
 		// Expected result
 		expectedResponse *api.User
+		expectedError    *database.Error
 	}{
 		"OkCase": {
 			previousUser: &User{
@@ -384,6 +474,7 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 				Urn:        "Oldurn",
 				CreateAt:   now.UnixNano(),
 				UpdateAt:   now.UnixNano(),
+				Revision:   1,
 			},
 			userToUpdate: &api.User{
 				ID:         "UserID",
@@ -392,6 +483,7 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 				Urn:        "NewUrn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Revision:   1,
 			},
 			expectedResponse: &api.User{
 				ID:         "UserID",
@@ -400,6 +492,31 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 				Urn:        "NewUrn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Revision:   2,
+			},
+		},
+		"ErrorCaseRevisionMismatch": {
+			previousUser: &User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "OldPath",
+				Urn:        "Oldurn",
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+				Revision:   2,
+			},
+			userToUpdate: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "NewPath",
+				Urn:        "NewUrn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Revision:   1,
+			},
+			expectedError: &database.Error{
+				Code:    database.REVISION_MISMATCH,
+				Message: "User with id UserID has been modified, revision 1 isn't the current one",
 			},
 		},
 	}
@@ -414,14 +531,19 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 		}
 		// Call to repository to update an user
 		updatedUser, err := repoDB.UpdateUser(*test.userToUpdate)
-		assert.Nil(t, err, "Error in test case %v", n)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
 
-		// Check response
-		assert.Equal(t, test.expectedResponse, updatedUser, "Error in test case %v", n)
-		// Check database
-		userNumber := getUsersCountFiltered(t, n, test.expectedResponse.ID, test.expectedResponse.ExternalID, test.expectedResponse.Path,
-			test.expectedResponse.CreateAt.UnixNano(), test.expectedResponse.UpdateAt.UnixNano(), test.expectedResponse.Urn, "")
-		assert.Equal(t, 1, userNumber, "Error in test case %v", n)
+			// Check response
+			assert.Equal(t, test.expectedResponse, updatedUser, "Error in test case %v", n)
+			// Check database
+			userNumber := getUsersCountFiltered(t, n, test.expectedResponse.ID, test.expectedResponse.ExternalID, test.expectedResponse.Path,
+				test.expectedResponse.CreateAt.UnixNano(), test.expectedResponse.UpdateAt.UnixNano(), test.expectedResponse.Urn, "")
+			assert.Equal(t, 1, userNumber, "Error in test case %v", n)
+		}
 	}
 }
 
@@ -432,13 +554,20 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 		createAt      int64
 		groupNotFound bool
 	}
+	type policyRelation struct {
+		userID   string
+		policyID string
+		createAt int64
+	}
 	now := time.Now().UTC()
 	testcases := map[string]struct {
 		// Previous data
-		previousUsers []User
-		relations     []relation
+		previousUsers   []User
+		relations       []relation
+		policyRelations []policyRelation
 		// Postgres Repo Args
 		userToDelete string
+		revision     int
 	}{
 		"OkCase": {
 			previousUsers: []User{
@@ -449,6 +578,7 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 					Urn:        "Oldurn",
 					CreateAt:   now.UnixNano(),
 					UpdateAt:   now.UnixNano(),
+					Revision:   1,
 				},
 				{
 					ID:         "UserID2",
@@ -471,7 +601,20 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 					createAt: now.UnixNano(),
 				},
 			},
+			policyRelations: []policyRelation{
+				{
+					userID:   "UserID",
+					policyID: "PolicyID",
+					createAt: now.UnixNano(),
+				},
+				{
+					userID:   "UserID2",
+					policyID: "PolicyID",
+					createAt: now.UnixNano(),
+				},
+			},
 			userToDelete: "UserID",
+			revision:     1,
 		},
 	}
 
@@ -479,6 +622,7 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 		// Clean user database
 		cleanUserTable(t, n)
 		cleanGroupUserRelationTable(t, n)
+		cleanUserPolicyRelationTable(t, n)
 
 		// Insert previous data
 		if test.previousUsers != nil {
@@ -491,25 +635,215 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 				insertGroupUserRelation(t, n, rel.userID, rel.groupID, rel.createAt)
 			}
 		}
+		for _, rel := range test.policyRelations {
+			insertUserPolicyRelation(t, n, rel.userID, rel.policyID, rel.createAt)
+		}
 		// Call to repository to remove user
-		err := repoDB.RemoveUser(test.userToDelete)
+		err := repoDB.RemoveUser(test.userToDelete, test.revision)
 		assert.Nil(t, err, "Error in test case %v", n)
 
-		// Check database
-		userNumber := getUsersCountFiltered(t, n, test.userToDelete, "", "", 0, 0, "", "")
-		assert.Equal(t, 0, userNumber, "Error in test case %v", n)
+		// Check user is marked as deleted
+		deleteAt := getDeleteAt(t, n, User{}.TableName(), test.userToDelete)
+		assert.True(t, deleteAt > 0, "Error in test case %v", n)
 
-		// Check total users
+		// Check total users, deleted users are kept until purged
 		totalUserNumber := getUsersCountFiltered(t, n, "", "", "", 0, 0, "", "")
-		assert.Equal(t, 1, totalUserNumber, "Error in test case %v", n)
+		assert.Equal(t, 2, totalUserNumber, "Error in test case %v", n)
 
-		// Check user deleted relations
+		// Check user relations are kept
 		relations := getGroupUserRelations(t, n, "", test.userToDelete)
-		assert.Equal(t, 0, relations, "Error in test case %v", n)
+		assert.Equal(t, 1, relations, "Error in test case %v", n)
+
+		// Check user policy relations are kept
+		policyRelations := getUserPolicyRelationCount(t, n, "", test.userToDelete)
+		assert.Equal(t, 1, policyRelations, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetDeletedUserByExternalID(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousUsers []User
+		// Postgres Repo Args
+		externalID string
+		// Expected result
+		expectedResponse *api.User
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			previousUsers: []User{
+				{
+					ID:         "UserID",
+					ExternalID: "ExternalID",
+					Path:       "Path",
+					Urn:        "urn",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+					DeleteAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID",
+					Path:       "Path",
+					Urn:        "urn",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			externalID: "ExternalID",
+			expectedResponse: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+			},
+		},
+		"ErrorCaseUserNotDeleted": {
+			previousUsers: []User{
+				{
+					ID:         "UserID",
+					ExternalID: "ExternalID",
+					Path:       "Path",
+					Urn:        "urn",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			externalID: "ExternalID",
+			expectedError: &database.Error{
+				Code:    database.USER_NOT_FOUND,
+				Message: "Deleted user with externalId ExternalID not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean user database
+		cleanUserTable(t, n)
+
+		// Insert previous data
+		for _, usr := range test.previousUsers {
+			insertUser(t, n, usr)
+		}
+		// Call to repository to get a deleted user
+		receivedUser, err := repoDB.GetDeletedUserByExternalID(test.externalID)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+
+			// Check response
+			assert.Equal(t, test.expectedResponse, receivedUser, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_RestoreUser(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousUser User
+		// Postgres Repo Args
+		userToRestore string
+	}{
+		"OkCase": {
+			previousUser: User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+				DeleteAt:   now.UnixNano(),
+			},
+			userToRestore: "UserID",
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean user database
+		cleanUserTable(t, n)
+
+		// Insert previous data
+		insertUser(t, n, test.previousUser)
+
+		// Call to repository to restore user
+		err := repoDB.RestoreUser(test.userToRestore)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check user is active again
+		_, err = repoDB.GetUserByExternalID(test.previousUser.ExternalID)
+		assert.Nil(t, err, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_PurgeUsers(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousUsers []User
+		// Postgres Repo Args
+		deleteBefore time.Time
+		// Expected result
+		expectedPurged int
+		expectedUsers  int
+	}{
+		"OkCase": {
+			previousUsers: []User{
+				{
+					ID:         "UserID",
+					ExternalID: "ExternalID",
+					Urn:        "urn",
+					DeleteAt:   now.Add(-2 * time.Hour).UnixNano(),
+				},
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID2",
+					Urn:        "urn2",
+					DeleteAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID3",
+					ExternalID: "ExternalID3",
+					Urn:        "urn3",
+				},
+			},
+			deleteBefore:   now.Add(-time.Hour),
+			expectedPurged: 1,
+			expectedUsers:  2,
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean user database
+		cleanUserTable(t, n)
+		cleanGroupUserRelationTable(t, n)
+		cleanUserPolicyRelationTable(t, n)
 
-		// Check total user relations
-		totalRelations := getGroupUserRelations(t, n, "", "")
-		assert.Equal(t, 1, totalRelations, "Error in test case %v", n)
+		// Insert previous data
+		for _, usr := range test.previousUsers {
+			insertUser(t, n, usr)
+			insertGroupUserRelation(t, n, usr.ID, "GroupID", now.UnixNano())
+			insertUserPolicyRelation(t, n, usr.ID, "PolicyID", now.UnixNano())
+		}
+		// Call to repository to purge users
+		purged, err := repoDB.PurgeUsers(test.deleteBefore)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedPurged, purged, "Error in test case %v", n)
+
+		// Check database
+		totalUserNumber := getUsersCountFiltered(t, n, "", "", "", 0, 0, "", "")
+		assert.Equal(t, test.expectedUsers, totalUserNumber, "Error in test case %v", n)
+
+		// Check purged user relations
+		relations := getGroupUserRelations(t, n, "", "")
+		assert.Equal(t, test.expectedUsers, relations, "Error in test case %v", n)
+		policyRelations := getUserPolicyRelationCount(t, n, "", "")
+		assert.Equal(t, test.expectedUsers, policyRelations, "Error in test case %v", n)
 	}
 }
 
@@ -699,3 +1033,231 @@ func TestPostgresRepo_GetGroupsByUserID(t *testing.T) {
 		}
 	}
 }
+
+func TestPostgresRepo_AttachPolicyToUser(t *testing.T) {
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		userID   string
+		policyID string
+		// Expected result
+		expectedError *database.Error
+	}{
+		"OkCase": {
+			userID:   "UserID",
+			policyID: "PolicyID",
+		},
+		"ErrorCaseInternalError": {
+			expectedError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "pq: null value in column \"user_id\" violates not-null constraint",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean UserPolicyRelation database
+		cleanUserPolicyRelationTable(t, n)
+
+		// Call to repository to attach policy
+		err := repoDB.AttachPolicyToUser(test.userID, test.policyID)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check database
+			relations := getUserPolicyRelationCount(t, n, test.policyID, test.userID)
+			assert.Equal(t, 1, relations, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_DetachPolicyFromUser(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		userID   string
+		policyID string
+	}{
+		"OkCase": {
+			userID:   "UserID",
+			policyID: "PolicyID",
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean UserPolicyRelation database
+		cleanUserPolicyRelationTable(t, n)
+
+		// Insert previous data
+		insertUserPolicyRelation(t, n, test.userID, test.policyID, now.UnixNano())
+
+		// Call to repository to detach policy
+		err := repoDB.DetachPolicyFromUser(test.userID, test.policyID)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check database
+		relations := getUserPolicyRelationCount(t, n, test.policyID, test.userID)
+		assert.Equal(t, 0, relations, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_IsAttachedToUser(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		userID   string
+		policyID string
+		// Expected result
+		expectedResult bool
+	}{
+		"OkCase": {
+			userID:         "UserID",
+			policyID:       "PolicyID",
+			expectedResult: true,
+		},
+		"OkCaseNotFound": {
+			userID:         "UserID",
+			policyID:       "PolicyIDXXXXXXX",
+			expectedResult: false,
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean UserPolicyRelation database
+		cleanUserPolicyRelationTable(t, n)
+
+		// Insert previous data
+		insertUserPolicyRelation(t, n, "UserID", "PolicyID", now.UnixNano())
+
+		// Call repository to check if policy is attached to user
+		result, err := repoDB.IsAttachedToUser(test.userID, test.policyID)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResult, result, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetAttachedUserPolicies(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		policies       []Policy
+		createAt       []int64
+		policyNotFound bool
+		// Postgres Repo Args
+		userID string
+		filter *api.Filter
+		// Expected result
+		expectedResponse []*PolicyUser
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			policies: []Policy{
+				{
+					ID:       "PolicyID1",
+					Name:     "Name1",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      "Urn1",
+				},
+				{
+					ID:       "PolicyID2",
+					Name:     "Name2",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      "Urn2",
+				},
+			},
+			createAt: []int64{now.UnixNano() - 1, now.UnixNano()},
+			userID:   "UserID",
+			filter: &api.Filter{
+				OrderBy: "create_at desc",
+			},
+			expectedResponse: []*PolicyUser{
+				{
+					Policy: &api.Policy{
+						ID:         "PolicyID2",
+						Name:       "Name2",
+						Org:        "org1",
+						Path:       "/path/",
+						CreateAt:   now,
+						UpdateAt:   now,
+						Urn:        "Urn2",
+						Version:    1,
+						Statements: &[]api.Statement{},
+					},
+					CreateAt: now,
+				},
+				{
+					Policy: &api.Policy{
+						ID:         "PolicyID1",
+						Name:       "Name1",
+						Org:        "org1",
+						Path:       "/path/",
+						CreateAt:   now,
+						UpdateAt:   now,
+						Urn:        "Urn1",
+						Version:    1,
+						Statements: &[]api.Statement{},
+					},
+					CreateAt: now.Add(-1),
+				},
+			},
+		},
+		"ErrorCase": {
+			policies: []Policy{
+				{
+					ID:       "PolicyID1",
+					Name:     "Name1",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      "Urn1",
+				},
+			},
+			createAt:       []int64{now.UnixNano()},
+			policyNotFound: true,
+			userID:         "UserID",
+			filter:         testFilter,
+			expectedError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Code: PolicyNotFound, Message: Policy with id PolicyID1 not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		cleanPolicyTable(t, n)
+		cleanUserPolicyRelationTable(t, n)
+
+		// Insert previous data
+		for i, policy := range test.policies {
+			insertUserPolicyRelation(t, n, test.userID, policy.ID, test.createAt[i])
+			if !test.policyNotFound {
+				insertPolicy(t, n, policy, []Statement{})
+			}
+		}
+
+		receivedPolicies, total, err := repoDB.GetAttachedUserPolicies(test.userID, test.filter)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+
+			// Check total
+			assert.Equal(t, len(test.expectedResponse), total, "Error in test case %v", n)
+
+			// Check response
+			for i, r := range receivedPolicies {
+				assert.Equal(t, test.expectedResponse[i].GetPolicy(), r.GetPolicy(), "Error in test case %v", n)
+				assert.Equal(t, test.expectedResponse[i].GetDate(), r.GetDate(), "Error in test case %v", n)
+			}
+		}
+	}
+}
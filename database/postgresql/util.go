@@ -28,6 +28,28 @@ func (gu *GroupUser) GetDate() time.Time {
 	return gu.CreateAt
 }
 
+// GroupSubgroup struct contains (Group-Group) relationship
+type GroupSubgroup struct {
+	Group    *api.Group
+	Subgroup *api.Group
+	CreateAt time.Time
+}
+
+// GetGroup returns the parent Group of a GroupSubgroup relation
+func (gs *GroupSubgroup) GetGroup() *api.Group {
+	return gs.Group
+}
+
+// GetSubgroup returns the member Group of a GroupSubgroup relation
+func (gs *GroupSubgroup) GetSubgroup() *api.Group {
+	return gs.Subgroup
+}
+
+// GetDate returns the date when the relation was created
+func (gs *GroupSubgroup) GetDate() time.Time {
+	return gs.CreateAt
+}
+
 // PolicyGroup struct contains (Policy-Group) relationship
 type PolicyGroup struct {
 	Group    *api.Group
@@ -49,3 +71,25 @@ func (pg *PolicyGroup) GetPolicy() *api.Policy {
 func (pg *PolicyGroup) GetDate() time.Time {
 	return pg.CreateAt
 }
+
+// PolicyUser struct contains (Policy-User) relationship
+type PolicyUser struct {
+	User     *api.User
+	Policy   *api.Policy
+	CreateAt time.Time
+}
+
+// GetUser returns a User of a PolicyUser relation
+func (pu *PolicyUser) GetUser() *api.User {
+	return pu.User
+}
+
+// GetPolicy returns a Policy of a PolicyUser relation
+func (pu *PolicyUser) GetPolicy() *api.Policy {
+	return pu.Policy
+}
+
+// GetDate returns the date when the relation was created
+func (pu *PolicyUser) GetDate() time.Time {
+	return pu.CreateAt
+}
@@ -0,0 +1,303 @@
+package postgresql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+)
+
+// WEBHOOK REPOSITORY IMPLEMENTATION
+
+func (pr PostgresRepo) AddWebhook(webhook api.Webhook) (*api.Webhook, error) {
+	// Create webhook model
+	webhookDB := &Webhook{
+		ID:       webhook.ID,
+		Name:     webhook.Name,
+		URL:      webhook.URL,
+		Secret:   webhook.Secret,
+		Events:   stringArrayToString(webhook.Events),
+		CreateAt: webhook.CreateAt.UnixNano(),
+		UpdateAt: webhook.UpdateAt.UnixNano(),
+	}
+
+	// Store webhook
+	err := pr.Dbmap.Create(webhookDB).Error
+
+	// Error handling
+	if err != nil {
+		return nil, storeError(err, database.WEBHOOK_ALREADY_EXIST,
+			fmt.Sprintf("Webhook with name %v already exist", webhook.Name))
+	}
+
+	return dbWebhookToAPIWebhook(webhookDB), nil
+}
+
+func (pr PostgresRepo) GetWebhookByName(name string) (*api.Webhook, error) {
+	webhook := &Webhook{}
+	query := pr.Dbmap.Where("name like ?", name).First(webhook)
+
+	// Check if webhook exists
+	if query.RecordNotFound() {
+		return nil, &database.Error{
+			Code:    database.WEBHOOK_NOT_FOUND,
+			Message: fmt.Sprintf("Webhook with name %v not found", name),
+		}
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return dbWebhookToAPIWebhook(webhook), nil
+}
+
+func (pr PostgresRepo) GetWebhooksFiltered(filter *api.Filter) ([]api.Webhook, int, error) {
+	var total int
+	webhooks := []Webhook{}
+	query := search(pr.Dbmap, filter, "name")
+
+	if len(filter.OrderBy) > 0 {
+		query = query.Order(filter.OrderBy)
+	}
+
+	// Error handling
+	if err := query.Find(&webhooks).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&webhooks).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Transform webhooks to API
+	var apiWebhooks []api.Webhook
+	if webhooks != nil {
+		apiWebhooks = make([]api.Webhook, len(webhooks), cap(webhooks))
+		for i, w := range webhooks {
+			apiWebhooks[i] = *dbWebhookToAPIWebhook(&w)
+		}
+	}
+
+	return apiWebhooks, total, nil
+}
+
+func (pr PostgresRepo) UpdateWebhook(webhook api.Webhook) (*api.Webhook, error) {
+	webhookDB := Webhook{
+		ID:       webhook.ID,
+		Name:     webhook.Name,
+		URL:      webhook.URL,
+		Secret:   webhook.Secret,
+		Events:   stringArrayToString(webhook.Events),
+		CreateAt: webhook.CreateAt.UnixNano(),
+		UpdateAt: webhook.UpdateAt.UnixNano(),
+	}
+
+	// Update webhook
+	query := pr.Dbmap.Model(&Webhook{ID: webhook.ID}).Updates(webhookDB)
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, storeError(err, database.WEBHOOK_ALREADY_EXIST,
+			fmt.Sprintf("Webhook with name %v already exist", webhook.Name))
+	}
+
+	return &webhook, nil
+}
+
+func (pr PostgresRepo) RemoveWebhook(id string) error {
+	transaction := pr.begin()
+
+	// Delete deliveries of the webhook and the webhook itself
+	for _, query := range []string{
+		"DELETE FROM webhook_deliveries WHERE webhook_id like ?",
+		"DELETE FROM webhooks WHERE id like ?",
+	} {
+		if err := transaction.Exec(query, id).Error; err != nil {
+			pr.rollback(transaction)
+			return &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	pr.commit(transaction)
+	return nil
+}
+
+func (pr PostgresRepo) AddWebhookDeliveries(deliveries []api.WebhookDelivery) error {
+	transaction := pr.begin()
+
+	for _, d := range deliveries {
+		deliveryDB := apiWebhookDeliveryToDBWebhookDelivery(d)
+		if err := transaction.Create(&deliveryDB).Error; err != nil {
+			pr.rollback(transaction)
+			return &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	pr.commit(transaction)
+	return nil
+}
+
+func (pr PostgresRepo) GetWebhookDeliveries(webhookID string, filter *api.Filter) ([]api.WebhookDelivery, int, error) {
+	var total int
+	deliveries := []WebhookDelivery{}
+	query := pr.Dbmap.Where("webhook_id like ?", webhookID)
+
+	// Newest deliveries first unless filter has its own order
+	if len(filter.OrderBy) > 0 {
+		query = query.Order(filter.OrderBy)
+	} else {
+		query = query.Order("create_at desc, id desc")
+	}
+
+	// Error handling
+	if err := query.Find(&deliveries).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&deliveries).Error; err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return dbWebhookDeliveriesToAPIWebhookDeliveries(deliveries), total, nil
+}
+
+func (pr PostgresRepo) ClaimWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]api.WebhookDelivery, error) {
+	transaction := pr.begin()
+
+	// Deliveries locked by other workers while they claim them are skipped
+	deliveries := []WebhookDelivery{}
+	if err := transaction.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+		Where("status = ? AND next_attempt_at <= ?", api.WEBHOOK_DELIVERY_PENDING, now.UnixNano()).
+		Order("next_attempt_at, id").Limit(limit).Find(&deliveries).Error; err != nil {
+		pr.rollback(transaction)
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Postpone next attempt, so deliveries aren't pending until lease expires
+	if len(deliveries) > 0 {
+		ids := make([]string, len(deliveries))
+		nextAttemptAt := now.Add(lease).UnixNano()
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+			deliveries[i].NextAttemptAt = nextAttemptAt
+		}
+		if err := transaction.Model(&WebhookDelivery{}).Where("id IN (?)", ids).
+			UpdateColumn("next_attempt_at", nextAttemptAt).Error; err != nil {
+			pr.rollback(transaction)
+			return nil, &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	pr.commit(transaction)
+	return dbWebhookDeliveriesToAPIWebhookDeliveries(deliveries), nil
+}
+
+func (pr PostgresRepo) UpdateWebhookDelivery(delivery api.WebhookDelivery, claimedUntil time.Time) error {
+	deliveryDB := apiWebhookDeliveryToDBWebhookDelivery(delivery)
+
+	// Update every column, empty values included, only if the delivery hasn't been claimed again
+	query := pr.Dbmap.Model(&WebhookDelivery{}).
+		Where("id like ? AND status = ? AND next_attempt_at = ?", delivery.ID, api.WEBHOOK_DELIVERY_PENDING, claimedUntil.UnixNano()).
+		Updates(map[string]interface{}{
+			"status":          deliveryDB.Status,
+			"attempts":        deliveryDB.Attempts,
+			"response_code":   deliveryDB.ResponseCode,
+			"error":           deliveryDB.Error,
+			"next_attempt_at": deliveryDB.NextAttemptAt,
+			"update_at":       deliveryDB.UpdateAt,
+		})
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if query.RowsAffected == 0 {
+		return &database.Error{
+			Code:    database.WEBHOOK_DELIVERY_NOT_CLAIMED,
+			Message: fmt.Sprintf("Webhook delivery with id %v isn't claimed anymore", delivery.ID),
+		}
+	}
+
+	return nil
+}
+
+func (pr PostgresRepo) PurgeWebhookDeliveries(updatedBefore time.Time) (int, error) {
+	// Pending deliveries are kept until they are delivered or fail
+	return pr.purge([]string{
+		"DELETE FROM webhook_deliveries WHERE status IN ('delivered', 'failed') AND update_at < ?",
+	}, updatedBefore)
+}
+
+// PRIVATE HELPER METHODS
+
+// Transform a webhook retrieved from db into a webhook for API
+func dbWebhookToAPIWebhook(webhook *Webhook) *api.Webhook {
+	return &api.Webhook{
+		ID:       webhook.ID,
+		Name:     webhook.Name,
+		URL:      webhook.URL,
+		Secret:   webhook.Secret,
+		Events:   stringToStringArray(webhook.Events),
+		CreateAt: time.Unix(0, webhook.CreateAt).UTC(),
+		UpdateAt: time.Unix(0, webhook.UpdateAt).UTC(),
+	}
+}
+
+// Transform a webhook delivery for API into a webhook delivery for db
+func apiWebhookDeliveryToDBWebhookDelivery(delivery api.WebhookDelivery) WebhookDelivery {
+	return WebhookDelivery{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		ResponseCode:  delivery.ResponseCode,
+		Error:         delivery.Error,
+		NextAttemptAt: delivery.NextAttemptAt.UnixNano(),
+		CreateAt:      delivery.CreateAt.UnixNano(),
+		UpdateAt:      delivery.UpdateAt.UnixNano(),
+	}
+}
+
+// Transform webhook deliveries retrieved from db into webhook deliveries for API
+func dbWebhookDeliveriesToAPIWebhookDeliveries(deliveries []WebhookDelivery) []api.WebhookDelivery {
+	apiDeliveries := make([]api.WebhookDelivery, len(deliveries), cap(deliveries))
+	for i, d := range deliveries {
+		apiDeliveries[i] = api.WebhookDelivery{
+			ID:            d.ID,
+			WebhookID:     d.WebhookID,
+			EventID:       d.EventID,
+			EventType:     d.EventType,
+			Payload:       d.Payload,
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			ResponseCode:  d.ResponseCode,
+			Error:         d.Error,
+			NextAttemptAt: time.Unix(0, d.NextAttemptAt).UTC(),
+			CreateAt:      time.Unix(0, d.CreateAt).UTC(),
+			UpdateAt:      time.Unix(0, d.UpdateAt).UTC(),
+		}
+	}
+	return apiDeliveries
+}
@@ -0,0 +1,447 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepo_AddWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhook *Webhook
+		// Postgres Repo Args
+		webhookToCreate api.Webhook
+		// Expected result
+		expectedResponse *api.Webhook
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			webhookToCreate: api.Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook1",
+				URL:      "https://example.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.USER_EVENT_CREATED, api.GROUP_EVENT_MEMBER_ADDED},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			expectedResponse: &api.Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook1",
+				URL:      "https://example.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.USER_EVENT_CREATED, api.GROUP_EVENT_MEMBER_ADDED},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"ErrorCaseAlreadyExists": {
+			previousWebhook: &Webhook{
+				ID:       "HOOK-ID-OLD",
+				Name:     "hook1",
+				URL:      "https://example.com/hook",
+				Secret:   "secret",
+				Events:   api.ALL_EVENTS,
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+			},
+			webhookToCreate: api.Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook1",
+				URL:      "https://example.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.ALL_EVENTS},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			expectedError: &database.Error{
+				Code:    database.WEBHOOK_ALREADY_EXIST,
+				Message: "Webhook with name hook1 already exist",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhooksTable(t, n)
+
+		// Insert previous data
+		if test.previousWebhook != nil {
+			insertWebhook(t, n, *test.previousWebhook)
+		}
+		// Call to repository to store webhook
+		storedWebhook, err := repoDB.AddWebhook(test.webhookToCreate)
+		if test.expectedError != nil {
+			dbError, ok := err.(*database.Error)
+			if !ok || dbError == nil {
+				t.Errorf("Test %v failed. Unexpected data retrieved from error: %v", n, err)
+				continue
+			}
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse, storedWebhook, "Error in test case %v", n)
+			// Check database
+			webhookNumber := getWebhooksCountFiltered(t, n, test.webhookToCreate.ID, test.webhookToCreate.Name,
+				test.webhookToCreate.URL, stringArrayToString(test.webhookToCreate.Events))
+			assert.Equal(t, 1, webhookNumber, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_GetWebhookByName(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhook *Webhook
+		// Postgres Repo Args
+		name string
+		// Expected result
+		expectedResponse *api.Webhook
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			previousWebhook: &Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook1",
+				URL:      "https://example.com/hook",
+				Secret:   "secret",
+				Events:   api.ALL_EVENTS,
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+			},
+			name: "hook1",
+			expectedResponse: &api.Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook1",
+				URL:      "https://example.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.ALL_EVENTS},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"ErrorCaseWebhookNotExist": {
+			name: "hook1",
+			expectedError: &database.Error{
+				Code:    database.WEBHOOK_NOT_FOUND,
+				Message: "Webhook with name hook1 not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhooksTable(t, n)
+
+		// Insert previous data
+		if test.previousWebhook != nil {
+			insertWebhook(t, n, *test.previousWebhook)
+		}
+		// Call to repository to get webhook
+		receivedWebhook, err := repoDB.GetWebhookByName(test.name)
+		if test.expectedError != nil {
+			dbError, ok := err.(*database.Error)
+			if !ok || dbError == nil {
+				t.Errorf("Test %v failed. Unexpected data retrieved from error: %v", n, err)
+				continue
+			}
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse, receivedWebhook, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_GetWebhooksFiltered(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhooks []Webhook
+		// Postgres Repo Args
+		filter *api.Filter
+		// Expected result
+		expectedResponse []api.Webhook
+		expectedTotal    int
+	}{
+		"OkCase": {
+			previousWebhooks: []Webhook{
+				{
+					ID:       "HOOK-ID-1",
+					Name:     "hook1",
+					URL:      "https://example.com/hook1",
+					Secret:   "secret",
+					Events:   api.ALL_EVENTS,
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+				{
+					ID:       "HOOK-ID-2",
+					Name:     "hook2",
+					URL:      "https://example.com/hook2",
+					Secret:   "secret",
+					Events:   api.USER_EVENT_CREATED,
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+			},
+			filter: &api.Filter{
+				OrderBy: "name desc",
+				Limit:   1,
+			},
+			expectedResponse: []api.Webhook{
+				{
+					ID:       "HOOK-ID-2",
+					Name:     "hook2",
+					URL:      "https://example.com/hook2",
+					Secret:   "secret",
+					Events:   []string{api.USER_EVENT_CREATED},
+					CreateAt: now,
+					UpdateAt: now,
+				},
+			},
+			expectedTotal: 2,
+		},
+		"OkCaseNoWebhooks": {
+			filter:        &api.Filter{},
+			expectedTotal: 0,
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhooksTable(t, n)
+
+		// Insert previous data
+		for _, w := range test.previousWebhooks {
+			insertWebhook(t, n, w)
+		}
+		// Call to repository to get webhooks
+		webhooks, total, err := repoDB.GetWebhooksFiltered(test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedTotal, total, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, webhooks, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_UpdateWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhooks []Webhook
+		// Postgres Repo Args
+		webhookToUpdate api.Webhook
+		// Expected result
+		expectedError *database.Error
+	}{
+		"OkCase": {
+			previousWebhooks: []Webhook{
+				{
+					ID:       "HOOK-ID",
+					Name:     "hook1",
+					URL:      "https://example.com/hook",
+					Secret:   "secret",
+					Events:   api.ALL_EVENTS,
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+			},
+			webhookToUpdate: api.Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook2",
+				URL:      "https://example.com/new",
+				Secret:   "newsecret",
+				Events:   []string{api.POLICY_EVENT_CREATED},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"ErrorCaseDuplicatedName": {
+			previousWebhooks: []Webhook{
+				{
+					ID:       "HOOK-ID",
+					Name:     "hook1",
+					URL:      "https://example.com/hook",
+					Secret:   "secret",
+					Events:   api.ALL_EVENTS,
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+				{
+					ID:       "HOOK-ID-2",
+					Name:     "hook2",
+					URL:      "https://example.com/hook",
+					Secret:   "secret",
+					Events:   api.ALL_EVENTS,
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+				},
+			},
+			webhookToUpdate: api.Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook2",
+				URL:      "https://example.com/new",
+				Secret:   "newsecret",
+				Events:   []string{api.POLICY_EVENT_CREATED},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			expectedError: &database.Error{
+				Code:    database.WEBHOOK_ALREADY_EXIST,
+				Message: "Webhook with name hook2 already exist",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhooksTable(t, n)
+
+		// Insert previous data
+		for _, w := range test.previousWebhooks {
+			insertWebhook(t, n, w)
+		}
+		// Call to repository to update webhook
+		updatedWebhook, err := repoDB.UpdateWebhook(test.webhookToUpdate)
+		if test.expectedError != nil {
+			dbError, ok := err.(*database.Error)
+			if !ok || dbError == nil {
+				t.Errorf("Test %v failed. Unexpected data retrieved from error: %v", n, err)
+				continue
+			}
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, &test.webhookToUpdate, updatedWebhook, "Error in test case %v", n)
+			// Check database
+			webhookNumber := getWebhooksCountFiltered(t, n, test.webhookToUpdate.ID, test.webhookToUpdate.Name,
+				test.webhookToUpdate.URL, stringArrayToString(test.webhookToUpdate.Events))
+			assert.Equal(t, 1, webhookNumber, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_RemoveWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	// Clean database
+	cleanWebhooksTable(t, "RemoveWebhook")
+	cleanWebhookDeliveriesTable(t, "RemoveWebhook")
+
+	for _, id := range []string{"HOOK-ID-1", "HOOK-ID-2"} {
+		insertWebhook(t, "RemoveWebhook", Webhook{
+			ID:       id,
+			Name:     id,
+			URL:      "https://example.com/hook",
+			Secret:   "secret",
+			Events:   api.ALL_EVENTS,
+			CreateAt: now.UnixNano(),
+			UpdateAt: now.UnixNano(),
+		})
+		insertWebhookDelivery(t, "RemoveWebhook", WebhookDelivery{
+			ID:            "DELIVERY-" + id,
+			WebhookID:     id,
+			EventID:       "EVENT-ID",
+			EventType:     api.USER_EVENT_CREATED,
+			Payload:       "{}",
+			Status:        api.WEBHOOK_DELIVERY_PENDING,
+			NextAttemptAt: now.UnixNano(),
+			CreateAt:      now.UnixNano(),
+			UpdateAt:      now.UnixNano(),
+		})
+	}
+
+	err := repoDB.RemoveWebhook("HOOK-ID-1")
+	assert.Nil(t, err)
+
+	// Check database, deliveries are removed with their webhook
+	assert.Equal(t, 0, getWebhooksCountFiltered(t, "RemoveWebhook", "HOOK-ID-1", "", "", ""))
+	assert.Equal(t, 0, getWebhookDeliveriesCountFiltered(t, "RemoveWebhook", "HOOK-ID-1", ""))
+	assert.Equal(t, 1, getWebhooksCountFiltered(t, "RemoveWebhook", "HOOK-ID-2", "", "", ""))
+	assert.Equal(t, 1, getWebhookDeliveriesCountFiltered(t, "RemoveWebhook", "HOOK-ID-2", ""))
+}
+
+func TestPostgresRepo_WebhookDeliveries(t *testing.T) {
+	now := time.Now().UTC()
+	// Clean database
+	cleanWebhookDeliveriesTable(t, "WebhookDeliveries")
+
+	deliveries := []api.WebhookDelivery{
+		{
+			ID:            "DELIVERY-ID-1",
+			WebhookID:     "HOOK-ID",
+			EventID:       "EVENT-ID-1",
+			EventType:     api.USER_EVENT_CREATED,
+			Payload:       `{"id":"EVENT-ID-1"}`,
+			Status:        api.WEBHOOK_DELIVERY_PENDING,
+			NextAttemptAt: now,
+			CreateAt:      now,
+			UpdateAt:      now,
+		},
+		{
+			ID:            "DELIVERY-ID-2",
+			WebhookID:     "HOOK-ID",
+			EventID:       "EVENT-ID-2",
+			EventType:     api.USER_EVENT_DELETED,
+			Payload:       `{"id":"EVENT-ID-2"}`,
+			Status:        api.WEBHOOK_DELIVERY_PENDING,
+			NextAttemptAt: now.Add(time.Hour),
+			CreateAt:      now.Add(time.Second),
+			UpdateAt:      now.Add(time.Second),
+		},
+	}
+
+	// Store deliveries
+	err := repoDB.AddWebhookDeliveries(deliveries)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, getWebhookDeliveriesCountFiltered(t, "WebhookDeliveries", "HOOK-ID", api.WEBHOOK_DELIVERY_PENDING))
+
+	// Newest deliveries are listed first
+	listed, total, err := repoDB.GetWebhookDeliveries("HOOK-ID", &api.Filter{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []api.WebhookDelivery{deliveries[1], deliveries[0]}, listed)
+
+	// Only deliveries whose next attempt is due are claimed, and they aren't claimed again until lease expires
+	claimed, err := repoDB.ClaimWebhookDeliveries(now, time.Minute, 10)
+	assert.Nil(t, err)
+	expected := deliveries[0]
+	expected.NextAttemptAt = now.Add(time.Minute)
+	assert.Equal(t, []api.WebhookDelivery{expected}, claimed)
+	claimed, err = repoDB.ClaimWebhookDeliveries(now, time.Minute, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []api.WebhookDelivery{}, claimed)
+
+	// Update delivery with the result of an attempt, only while it's claimed until the given time
+	delivered := deliveries[0]
+	delivered.Status = api.WEBHOOK_DELIVERY_DELIVERED
+	delivered.Attempts = 1
+	delivered.ResponseCode = 200
+	err = repoDB.UpdateWebhookDelivery(delivered, now)
+	assert.Equal(t, &database.Error{
+		Code:    database.WEBHOOK_DELIVERY_NOT_CLAIMED,
+		Message: "Webhook delivery with id DELIVERY-ID-1 isn't claimed anymore",
+	}, err)
+	assert.Equal(t, 0, getWebhookDeliveriesCountFiltered(t, "WebhookDeliveries", "HOOK-ID", api.WEBHOOK_DELIVERY_DELIVERED))
+	err = repoDB.UpdateWebhookDelivery(delivered, now.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, getWebhookDeliveriesCountFiltered(t, "WebhookDeliveries", "HOOK-ID", api.WEBHOOK_DELIVERY_DELIVERED))
+
+	claimed, err = repoDB.ClaimWebhookDeliveries(now.Add(2*time.Hour), time.Minute, 10)
+	assert.Nil(t, err)
+	expected = deliveries[1]
+	expected.NextAttemptAt = now.Add(2*time.Hour + time.Minute)
+	assert.Equal(t, []api.WebhookDelivery{expected}, claimed)
+
+	// Only finished deliveries are purged
+	purged, err := repoDB.PurgeWebhookDeliveries(now.Add(2 * time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 0, getWebhookDeliveriesCountFiltered(t, "WebhookDeliveries", "HOOK-ID", api.WEBHOOK_DELIVERY_DELIVERED))
+	assert.Equal(t, 1, getWebhookDeliveriesCountFiltered(t, "WebhookDeliveries", "HOOK-ID", api.WEBHOOK_DELIVERY_PENDING))
+}
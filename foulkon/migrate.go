@@ -0,0 +1,63 @@
+package foulkon
+
+import (
+	"errors"
+
+	"github.com/Tecsisa/foulkon/database/postgresql"
+	"github.com/jinzhu/gorm"
+	"github.com/pelletier/go-toml"
+)
+
+// Migrator applies schema migrations to the database configured in a worker configuration file
+type Migrator struct {
+	gormDB *gorm.DB
+}
+
+// NewMigrator connects to the database of the worker configuration without checking its schema version
+func NewMigrator(config *toml.TomlTree) (*Migrator, error) {
+	dbType, err := getMandatoryValue(config, "database.type")
+	if err != nil {
+		return nil, err
+	}
+
+	switch dbType {
+	case "postgres": // PostgreSQL DB
+		dbdsn, err := getMandatoryValue(config, "database.postgres.datasourcename")
+		if err != nil {
+			return nil, err
+		}
+		gormDB, err := postgresql.OpenDb(dbdsn,
+			getDefaultValue(config, "database.postgres.idleconns", "5"),
+			getDefaultValue(config, "database.postgres.maxopenconns", "20"),
+			getDefaultValue(config, "database.postgres.connttl", "300"),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return &Migrator{
+			gormDB: gormDB,
+		}, nil
+
+	default:
+		return nil, errors.New("Unexpected db_type value in configuration file (Maybe it is empty)")
+	}
+}
+
+// Status returns the current schema version of the database and the version required by this binary
+func (m *Migrator) Status() (int, int, error) {
+	version, err := postgresql.GetSchemaVersion(m.gormDB)
+	if err != nil {
+		return 0, 0, err
+	}
+	return version, postgresql.LatestSchemaVersion(), nil
+}
+
+// Migrate changes the database schema to version, or to the latest version if it's negative.
+// It returns the schema versions before and after the migration.
+func (m *Migrator) Migrate(version int) (int, int, error) {
+	return postgresql.MigrateDb(m.gormDB, version)
+}
+
+func (m *Migrator) Close() error {
+	return m.gormDB.Close()
+}
@@ -8,6 +8,7 @@ import (
 
 	"fmt"
 
+	"strconv"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -18,6 +19,7 @@ import (
 )
 
 var proxyLogfile *os.File
+var proxyWatcher api.ProxyResourcesWatcher
 
 // Proxy - Authorize resources using definitions in proxy config file
 type Proxy struct {
@@ -37,6 +39,9 @@ type Proxy struct {
 
 	// Refresh time
 	RefreshTime time.Duration
+
+	// Notifies changes of proxy resources, resources are refreshed every RefreshTime only without it or after it stops
+	ResourcesWatcher api.ProxyResourcesWatcher
 }
 
 func NewProxy(config *toml.TomlTree) (*Proxy, error) {
@@ -67,6 +72,13 @@ func NewProxy(config *toml.TomlTree) (*Proxy, error) {
 	}
 	api.Log.Infof("Logger type: %v, LogLevel: %v", loggerType, api.Log.Level.String())
 
+	notifications, err := strconv.ParseBool(getDefaultValue(config, "resources.notifications", "true"))
+	if err != nil {
+		err = fmt.Errorf("Invalid resources.notifications value: %v", err)
+		api.Log.Error(err)
+		return nil, err
+	}
+
 	// Start DB with API
 	var prApi api.ProxyAPI
 
@@ -103,6 +115,17 @@ func NewProxy(config *toml.TomlTree) (*Proxy, error) {
 			ProxyRepo: repoDB,
 		}
 
+		// Listen to changes before resources are loaded, so none is missed
+		if notifications {
+			listener, err := postgresql.NewProxyResourcesListener(dbdsn)
+			if err != nil {
+				api.Log.Warnf("Unable to listen to changes of proxy resources, they will be refreshed every interval: %v", err)
+			} else {
+				proxyWatcher = listener
+				api.Log.Info("Listening to changes of proxy resources")
+			}
+		}
+
 	default:
 		err := errors.New("Unexpected db_type value in configuration file (Maybe it is empty)")
 		api.Log.Error(err)
@@ -139,11 +162,19 @@ func NewProxy(config *toml.TomlTree) (*Proxy, error) {
 		KeyFile:     getDefaultValue(config, "server.keyfile", ""),
 		ProxyApi:    prApi,
 		RefreshTime: refresh,
+
+		ResourcesWatcher: proxyWatcher,
 	}, nil
 }
 
 func CloseProxy() int {
 	status := 0
+	if proxyWatcher != nil {
+		if err := proxyWatcher.Close(); err != nil {
+			api.Log.Errorf("Couldn't close proxy resources listener: %v", err)
+			status = 1
+		}
+	}
 	if err := db.Close(); err != nil {
 		api.Log.Errorf("Couldn't close DB connection: %v", err)
 		status = 1
@@ -0,0 +1,54 @@
+package foulkon
+
+import (
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+)
+
+var purgeTicker *time.Ticker
+
+// StartPurger removes users, groups and policies deleted longer than the retention window ago, and
+// finished webhook deliveries older than their retention, every purge interval, until the worker is closed
+func (w *Worker) StartPurger() {
+	purgeTicker = time.NewTicker(w.Config.PurgeInterval)
+	api.Log.Infof("Purging deleted entities every %v with retention %v", w.Config.PurgeInterval, w.Config.DeletionRetention)
+	go func(ticker *time.Ticker) {
+		for range ticker.C {
+			w.PurgeDeletedEntities()
+			w.PurgeWebhookDeliveries()
+		}
+	}(purgeTicker)
+}
+
+// PurgeDeletedEntities removes users, groups and policies deleted longer than the retention window ago
+func (w *Worker) PurgeDeletedEntities() {
+	deleteBefore := time.Now().UTC().Add(-w.Config.DeletionRetention)
+	purged, err := w.PurgeApi.PurgeDeletedEntities(deleteBefore)
+	if err != nil {
+		api.Log.Errorf("Unexpected error purging deleted entities: %v", err)
+		return
+	}
+	if purged > 0 {
+		api.Log.Infof("Purged %v entities deleted before %v", purged, deleteBefore.Format(time.RFC3339))
+	}
+}
+
+// PurgeWebhookDeliveries removes delivered and failed webhook deliveries last updated before their retention
+func (w *Worker) PurgeWebhookDeliveries() {
+	updatedBefore := time.Now().UTC().Add(-w.Config.WebhookRetention)
+	purged, err := w.DeliveryApi.PurgeWebhookDeliveries(updatedBefore)
+	if err != nil {
+		api.Log.Errorf("Unexpected error purging webhook deliveries: %v", err)
+		return
+	}
+	if purged > 0 {
+		api.Log.Infof("Purged %v webhook deliveries updated before %v", purged, updatedBefore.Format(time.RFC3339))
+	}
+}
+
+func stopPurger() {
+	if purgeTicker != nil {
+		purgeTicker.Stop()
+	}
+}
@@ -0,0 +1,41 @@
+package foulkon
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+)
+
+var webhookTicker *time.Ticker
+
+// StartWebhookDispatcher sends the pending deliveries of events to webhooks every delivery
+// interval, until the worker is closed
+func (w *Worker) StartWebhookDispatcher() {
+	client := &http.Client{Timeout: w.Config.WebhookTimeout}
+	webhookTicker = time.NewTicker(w.Config.WebhookDeliveryInterval)
+	api.Log.Infof("Delivering webhook events every %v with %v attempts", w.Config.WebhookDeliveryInterval, w.Config.WebhookMaxAttempts)
+	go func(ticker *time.Ticker) {
+		for range ticker.C {
+			w.DeliverWebhookEvents(client)
+		}
+	}(webhookTicker)
+}
+
+// DeliverWebhookEvents sends the pending deliveries of events to webhooks whose next attempt is due
+func (w *Worker) DeliverWebhookEvents(client *http.Client) {
+	sent, err := w.DeliveryApi.DeliverWebhookEvents(client, w.Config.WebhookMaxAttempts, w.Config.WebhookBackoff)
+	if err != nil {
+		api.Log.Errorf("Unexpected error delivering webhook events: %v", err)
+		return
+	}
+	if sent > 0 {
+		api.Log.Debugf("Sent %v webhook event deliveries", sent)
+	}
+}
+
+func stopWebhookDispatcher() {
+	if webhookTicker != nil {
+		webhookTicker.Stop()
+	}
+}
@@ -14,12 +14,15 @@ import (
 
 	"strconv"
 
+	"time"
+
 	"github.com/Sirupsen/logrus"
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/database/postgresql"
 	"github.com/Tecsisa/foulkon/middleware"
 	"github.com/Tecsisa/foulkon/middleware/auth"
 	"github.com/Tecsisa/foulkon/middleware/auth/oidc"
+	"github.com/Tecsisa/foulkon/middleware/cors"
 	"github.com/Tecsisa/foulkon/middleware/logger"
 	"github.com/Tecsisa/foulkon/middleware/xrequestid"
 	"github.com/pelletier/go-toml"
@@ -45,12 +48,17 @@ type Worker struct {
 	KeyFile  string
 
 	// APIs
-	UserApi     api.UserAPI
-	GroupApi    api.GroupAPI
-	PolicyApi   api.PolicyAPI
-	AuthzApi    api.AuthzAPI
-	ProxyApi    api.ProxyResourcesAPI
-	AuthOidcAPI api.AuthOidcAPI
+	UserApi         api.UserAPI
+	GroupApi        api.GroupAPI
+	PolicyApi       api.PolicyAPI
+	AuthzApi        api.AuthzAPI
+	ProxyApi        api.ProxyResourcesAPI
+	AuthOidcAPI     api.AuthOidcAPI
+	OrganizationApi api.OrganizationAPI
+	StateApi        api.StateAPI
+	PurgeApi        api.InternalPurgeAPI
+	WebhookApi      api.WebhookAPI
+	DeliveryApi     api.InternalWebhookAPI
 
 	//  Middleware handler
 	MiddlewareHandler *middleware.MiddlewareHandler
@@ -76,6 +84,31 @@ type WorkerConfig struct {
 	AuthType      string
 	OidcProviders []api.OidcProvider
 
+	// CORS Config
+	CORSEnabled          bool
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           int
+
+	// Deletion Config
+	DeletionRetention time.Duration
+	PurgeInterval     time.Duration
+
+	// Webhooks Config
+	WebhookDeliveryInterval time.Duration
+	WebhookMaxAttempts      int
+	WebhookBackoff          time.Duration
+	WebhookTimeout          time.Duration
+	WebhookRetention        time.Duration
+
+	// Kubernetes authorization Config
+	KubernetesAction         string
+	KubernetesResourceUrn    string
+	KubernetesNonResourceUrn string
+	KubernetesOrg            string
+
 	Version string
 }
 
@@ -148,11 +181,14 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 			Dbmap: gormDB,
 		}
 		authApi = api.WorkerAPI{
-			GroupRepo:    repoDB,
-			UserRepo:     repoDB,
-			PolicyRepo:   repoDB,
-			ProxyRepo:    repoDB,
-			AuthOidcRepo: repoDB,
+			GroupRepo:        repoDB,
+			UserRepo:         repoDB,
+			PolicyRepo:       repoDB,
+			ProxyRepo:        repoDB,
+			AuthOidcRepo:     repoDB,
+			OrganizationRepo: repoDB,
+			TransactionRepo:  repoDB,
+			WebhookRepo:      repoDB,
 		}
 		wc.IdleConns, _ = strconv.Atoi(dbIdleconns)
 		wc.MaxOpenConns, _ = strconv.Atoi(dbMaxopenconns)
@@ -231,6 +267,89 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 	requestLoggerMiddleware := logger.NewRequestLoggerMiddleware()
 	middlewares[middleware.REQUEST_LOGGER_MIDDLEWARE] = requestLoggerMiddleware
 
+	// CORS middleware, only if configured
+	if config.Has("cors") {
+		allowedOrigins, err := getMandatoryValue(config, "cors.allowedorigins")
+		if err != nil {
+			api.Log.Error(err)
+			return nil, err
+		}
+		allowCredentials, err := strconv.ParseBool(getDefaultValue(config, "cors.allowcredentials", "false"))
+		if err != nil {
+			err = fmt.Errorf("Invalid cors.allowcredentials value: %v", err)
+			api.Log.Error(err)
+			return nil, err
+		}
+		maxAge, err := strconv.Atoi(getDefaultValue(config, "cors.maxage", "0"))
+		if err != nil || maxAge < 0 {
+			err = fmt.Errorf("Invalid cors.maxage value: %v", getVar(config, "cors.maxage"))
+			api.Log.Error(err)
+			return nil, err
+		}
+		wc.CORSEnabled = true
+		wc.CORSAllowedOrigins = splitConfigList(allowedOrigins)
+		wc.CORSAllowedMethods = splitConfigList(getDefaultValue(config, "cors.allowedmethods", "GET,POST,PUT,PATCH,DELETE"))
+		wc.CORSAllowedHeaders = splitConfigList(getDefaultValue(config, "cors.allowedheaders", "Authorization,Content-Type,If-Match"))
+		wc.CORSAllowCredentials = allowCredentials
+		wc.CORSMaxAge = maxAge
+		middlewares[middleware.CORS_MIDDLEWARE] = cors.NewCORSMiddleware(wc.CORSAllowedOrigins, wc.CORSAllowedMethods,
+			wc.CORSAllowedHeaders, allowCredentials, maxAge)
+		api.Log.Infof("CORS enabled for origins %v", wc.CORSAllowedOrigins)
+	}
+
+	// Retention of deleted users, groups and policies before they are purged
+	retention, err := time.ParseDuration(getDefaultValue(config, "deletion.retention", "720h"))
+	if err != nil || retention < 0 {
+		err = fmt.Errorf("Invalid deletion.retention value: %v", getVar(config, "deletion.retention"))
+		api.Log.Error(err)
+		return nil, err
+	}
+	purgeInterval, err := time.ParseDuration(getDefaultValue(config, "deletion.purgeinterval", "1h"))
+	if err != nil || purgeInterval <= 0 {
+		err = fmt.Errorf("Invalid deletion.purgeinterval value: %v", getVar(config, "deletion.purgeinterval"))
+		api.Log.Error(err)
+		return nil, err
+	}
+	wc.DeletionRetention = retention
+	wc.PurgeInterval = purgeInterval
+
+	// Deliveries of events to webhooks
+	for _, d := range []struct {
+		key, defaultValue string
+		value             *time.Duration
+	}{
+		{"webhooks.deliveryinterval", "5s", &wc.WebhookDeliveryInterval},
+		{"webhooks.backoff", "30s", &wc.WebhookBackoff},
+		{"webhooks.timeout", "10s", &wc.WebhookTimeout},
+		{"webhooks.retention", "168h", &wc.WebhookRetention},
+	} {
+		duration, err := time.ParseDuration(getDefaultValue(config, d.key, d.defaultValue))
+		if err != nil || duration <= 0 {
+			err = fmt.Errorf("Invalid %v value: %v", d.key, getVar(config, d.key))
+			api.Log.Error(err)
+			return nil, err
+		}
+		*d.value = duration
+	}
+	maxAttempts, err := strconv.Atoi(getDefaultValue(config, "webhooks.maxattempts", "8"))
+	if err != nil || maxAttempts < 1 {
+		err = fmt.Errorf("Invalid webhooks.maxattempts value: %v", getVar(config, "webhooks.maxattempts"))
+		api.Log.Error(err)
+		return nil, err
+	}
+	wc.WebhookMaxAttempts = maxAttempts
+
+	// Mapping of Kubernetes requests to actions and external resources
+	wc.KubernetesAction = getDefaultValue(config, "kubernetes.action", "k8s:{verb}")
+	wc.KubernetesResourceUrn = getDefaultValue(config, "kubernetes.resourceurn", "urn:k8s:cluster::{namespace}/{apiGroup}/{resource}/{subresource}/{name}")
+	wc.KubernetesNonResourceUrn = getDefaultValue(config, "kubernetes.nonresourceurn", "urn:k8s:cluster::nonresource/{path}")
+	wc.KubernetesOrg = getDefaultValue(config, "kubernetes.org", "")
+	if wc.KubernetesOrg != "" && !api.IsValidOrg(wc.KubernetesOrg) {
+		err = fmt.Errorf("Invalid kubernetes.org value: %v", wc.KubernetesOrg)
+		api.Log.Error(err)
+		return nil, err
+	}
+
 	host, err := getMandatoryValue(config, "server.host")
 	if err != nil {
 		api.Log.Error(err)
@@ -256,12 +375,19 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 		AuthzApi:          authApi,
 		ProxyApi:          authApi,
 		AuthOidcAPI:       authApi,
+		OrganizationApi:   authApi,
+		StateApi:          authApi,
+		PurgeApi:          authApi,
+		WebhookApi:        authApi,
+		DeliveryApi:       authApi,
 		Config:            wc,
 	}, nil
 }
 
 func CloseWorker() int {
 	status := 0
+	stopPurger()
+	stopWebhookDispatcher()
 	if err := db.Close(); err != nil {
 		api.Log.Errorf("Couldn't close DB connection: %v", err)
 		status = 1
@@ -299,6 +425,17 @@ func getDefaultValue(config *toml.TomlTree, key string, def string) string {
 	return value
 }
 
+// This aux method splits a comma separated config value, ignoring empty items
+func splitConfigList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // Check variables in TOML file.
 // If the value of a key is '${SOME_KEY}', we will search the value in the OS ENV vars
 // If the value of a key is 'something_else', returns that as the value
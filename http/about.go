@@ -27,10 +27,42 @@ type AuthConnectorConfig struct {
 	OidcProviders []api.OidcProvider `json:"oidcProviders,omitempty"`
 }
 
+type CORSConfig struct {
+	AllowedOrigins   []string `json:"allowedOrigins,omitempty"`
+	AllowedMethods   []string `json:"allowedMethods,omitempty"`
+	AllowedHeaders   []string `json:"allowedHeaders,omitempty"`
+	AllowCredentials bool     `json:"allowCredentials"`
+	MaxAge           int      `json:"maxAge,omitempty"`
+}
+
+type DeletionConfig struct {
+	Retention     string `json:"retention,omitempty"`
+	PurgeInterval string `json:"purgeinterval,omitempty"`
+}
+
+type WebhooksConfig struct {
+	DeliveryInterval string `json:"deliveryinterval,omitempty"`
+	MaxAttempts      int    `json:"maxattempts,omitempty"`
+	Backoff          string `json:"backoff,omitempty"`
+	Timeout          string `json:"timeout,omitempty"`
+	Retention        string `json:"retention,omitempty"`
+}
+
+type KubernetesConfig struct {
+	Action         string `json:"action,omitempty"`
+	ResourceUrn    string `json:"resourceurn,omitempty"`
+	NonResourceUrn string `json:"nonresourceurn,omitempty"`
+	Org            string `json:"org,omitempty"`
+}
+
 type Config struct {
 	Logger        LoggerConfig        `json:"logger,omitempty"`
 	Database      DatabaseConfig      `json:"database,omitempty"`
 	AuthConnector AuthConnectorConfig `json:"authenticator,omitempty"`
+	CORS          *CORSConfig         `json:"cors,omitempty"`
+	Deletion      DeletionConfig      `json:"deletion,omitempty"`
+	Webhooks      WebhooksConfig      `json:"webhooks,omitempty"`
+	Kubernetes    KubernetesConfig    `json:"kubernetes,omitempty"`
 	Version       string              `json:"version,omitempty"`
 }
 
@@ -76,13 +108,50 @@ func (wh *WorkerHandler) HandleGetCurrentConfig(w http.ResponseWriter, r *http.R
 		OidcProviders: wc.OidcProviders,
 	}
 
+	// Get Deletion config
+	deletion := DeletionConfig{
+		Retention:     wc.DeletionRetention.String(),
+		PurgeInterval: wc.PurgeInterval.String(),
+	}
+
+	// Get Webhooks config
+	webhooks := WebhooksConfig{
+		DeliveryInterval: wc.WebhookDeliveryInterval.String(),
+		MaxAttempts:      wc.WebhookMaxAttempts,
+		Backoff:          wc.WebhookBackoff.String(),
+		Timeout:          wc.WebhookTimeout.String(),
+		Retention:        wc.WebhookRetention.String(),
+	}
+
+	// Get Kubernetes config
+	kubernetes := KubernetesConfig{
+		Action:         wc.KubernetesAction,
+		ResourceUrn:    wc.KubernetesResourceUrn,
+		NonResourceUrn: wc.KubernetesNonResourceUrn,
+		Org:            wc.KubernetesOrg,
+	}
+
 	// Config Response
 	response := Config{
 		Logger:        logger,
 		Database:      db,
 		AuthConnector: auth,
+		Deletion:      deletion,
+		Webhooks:      webhooks,
+		Kubernetes:    kubernetes,
 		Version:       wc.Version,
 	}
 
+	// Get CORS config
+	if wc.CORSEnabled {
+		response.CORS = &CORSConfig{
+			AllowedOrigins:   wc.CORSAllowedOrigins,
+			AllowedMethods:   wc.CORSAllowedMethods,
+			AllowedHeaders:   wc.CORSAllowedHeaders,
+			AllowCredentials: wc.CORSAllowCredentials,
+			MaxAge:           wc.CORSMaxAge,
+		}
+	}
+
 	wh.processHttpResponse(r, w, requestInfo, response, nil, http.StatusOK)
 }
@@ -56,6 +56,23 @@ func TestWorkerHandler_HandleGetCurrentConfig(t *testing.T) {
 						},
 					},
 				},
+				Deletion: DeletionConfig{
+					Retention:     "720h0m0s",
+					PurgeInterval: "1h0m0s",
+				},
+				Webhooks: WebhooksConfig{
+					DeliveryInterval: "5s",
+					MaxAttempts:      8,
+					Backoff:          "30s",
+					Timeout:          "10s",
+					Retention:        "168h0m0s",
+				},
+				Kubernetes: KubernetesConfig{
+					Action:         "k8s:{verb}",
+					ResourceUrn:    "urn:k8s:cluster::{namespace}/{apiGroup}/{resource}/{subresource}/{name}",
+					NonResourceUrn: "urn:k8s:cluster::nonresource/{path}",
+					Org:            "example",
+				},
 				Version: "test",
 			},
 		},
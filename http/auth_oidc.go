@@ -3,33 +3,19 @@ package http
 import (
 	"net/http"
 
+	"github.com/Tecsisa/foulkon/http/types"
 	"github.com/julienschmidt/httprouter"
 )
 
 // REQUESTS
 
-type CreateOidcProviderRequest struct {
-	Name        string   `json:"name,omitempty"`
-	Path        string   `json:"path,omitempty"`
-	IssuerURL   string   `json:"issuerUrl,omitempty"`
-	OidcClients []string `json:"clients,omitempty"`
-}
+type CreateOidcProviderRequest = types.CreateOidcProviderRequest
 
-type UpdateOidcProviderRequest struct {
-	Name        string   `json:"name,omitempty"`
-	Path        string   `json:"path,omitempty"`
-	IssuerURL   string   `json:"issuerUrl,omitempty"`
-	OidcClients []string `json:"clients,omitempty"`
-}
+type UpdateOidcProviderRequest = types.UpdateOidcProviderRequest
 
 // RESPONSES
 
-type ListOidcProvidersResponse struct {
-	Providers []string `json:"providers,omitempty"`
-	Limit     int      `json:"limit"`
-	Offset    int      `json:"offset"`
-	Total     int      `json:"total"`
-}
+type ListOidcProvidersResponse = types.ListOidcProvidersResponse
 
 // HANDLERS
 
@@ -95,6 +81,39 @@ func (wh *WorkerHandler) HandleUpdateOidcProvider(w http.ResponseWriter, r *http
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
+func (wh *WorkerHandler) HandlePatchOidcProvider(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call Auth Provider API to retrieve the OIDC Provider to patch
+	provider, err := wh.worker.AuthOidcAPI.GetOidcProviderByName(requestInfo, filterData.AuthProviderName)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusOK)
+		return
+	}
+	request := &UpdateOidcProviderRequest{
+		Name:      provider.Name,
+		Path:      provider.Path,
+		IssuerURL: provider.IssuerURL,
+	}
+	for _, client := range provider.OidcClients {
+		request.OidcClients = append(request.OidcClients, client.Name)
+	}
+	if apiErr := applyPatch(r, requestInfo, request); apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call Auth Provider API to update the OIDC Provider
+	response, err := wh.worker.AuthOidcAPI.UpdateOidcProvider(requestInfo, filterData.AuthProviderName,
+		request.Name, request.Path, request.IssuerURL, request.OidcClients)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
 func (wh *WorkerHandler) HandleRemoveOidcProvider(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Process request
 	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
@@ -545,8 +545,9 @@ func TestWorkerHandler_HandleUpdateOidcProvider(t *testing.T) {
 		"ErrorCaseOidcProviderNotFound": {
 			oidcProviderName: "oidcProviderName",
 			request: &UpdateOidcProviderRequest{
-				Name: "newName",
-				Path: "NewPath",
+				Name:      "newName",
+				Path:      "NewPath",
+				IssuerURL: "http://test.com",
 			},
 			expectedStatusCode: http.StatusNotFound,
 			expectedError: api.Error{
@@ -561,8 +562,9 @@ func TestWorkerHandler_HandleUpdateOidcProvider(t *testing.T) {
 		"ErrorCaseInvalidParameterError": {
 			oidcProviderName: "oidcProviderName",
 			request: &UpdateOidcProviderRequest{
-				Name: "newName",
-				Path: "InvalidPath",
+				Name:      "newName",
+				Path:      "InvalidPath",
+				IssuerURL: "http://test.com",
 			},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedError: api.Error{
@@ -577,8 +579,9 @@ func TestWorkerHandler_HandleUpdateOidcProvider(t *testing.T) {
 		"ErrorCaseOidcProviderAlreadyExistError": {
 			oidcProviderName: "oidcProviderName",
 			request: &UpdateOidcProviderRequest{
-				Name: "newName",
-				Path: "newPath",
+				Name:      "newName",
+				Path:      "newPath",
+				IssuerURL: "http://test.com",
 			},
 			expectedStatusCode: http.StatusConflict,
 			expectedError: api.Error{
@@ -593,8 +596,9 @@ func TestWorkerHandler_HandleUpdateOidcProvider(t *testing.T) {
 		"ErrorCaseUnauthorizedResourcesError": {
 			oidcProviderName: "oidcProviderName",
 			request: &UpdateOidcProviderRequest{
-				Name: "newName",
-				Path: "NewPath",
+				Name:      "newName",
+				Path:      "NewPath",
+				IssuerURL: "http://test.com",
 			},
 			expectedStatusCode: http.StatusForbidden,
 			expectedError: api.Error{
@@ -609,8 +613,9 @@ func TestWorkerHandler_HandleUpdateOidcProvider(t *testing.T) {
 		"ErrorCaseUnknownApiError": {
 			oidcProviderName: "oidcProviderName",
 			request: &UpdateOidcProviderRequest{
-				Name: "newName",
-				Path: "NewPath",
+				Name:      "newName",
+				Path:      "NewPath",
+				IssuerURL: "http://test.com",
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			updateOidcProviderErr: &api.Error{
@@ -675,6 +680,104 @@ func TestWorkerHandler_HandleUpdateOidcProvider(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandlePatchOidcProvider(t *testing.T) {
+	now := time.Now().UTC()
+	provider := &api.OidcProvider{
+		ID:        "ProviderID",
+		Name:      "provider1",
+		Path:      "/path/",
+		Urn:       api.CreateUrn("", api.RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "provider1"),
+		CreateAt:  now,
+		UpdateAt:  now,
+		IssuerURL: "https://issuer.com",
+		OidcClients: []api.OidcClient{
+			{
+				Name: "client1",
+			},
+		},
+	}
+	testcases := map[string]struct {
+		// API method args
+		patch string
+		// Expected result
+		expectedStatusCode int
+		expectedIssuerURL  string
+		expectedClients    []string
+		expectedError      api.Error
+		// Manager Errors
+		getOidcProviderByNameErr error
+	}{
+		"OkCase": {
+			patch:              `{"issuerUrl":"https://newissuer.com"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedIssuerURL:  "https://newissuer.com",
+			expectedClients:    []string{"client1"},
+		},
+		"ErrorCaseOidcProviderNotFound": {
+			patch:              `{"issuerUrl":"https://newissuer.com"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.AUTH_OIDC_PROVIDER_BY_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+			getOidcProviderByNameErr: &api.Error{
+				Code:    api.AUTH_OIDC_PROVIDER_BY_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+		},
+		"ErrorCaseMalformedPatch": {
+			patch:              `{`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "unexpected EOF",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetOidcProviderByNameMethod][0] = provider
+		testApi.ArgsOut[GetOidcProviderByNameMethod][1] = test.getOidcProviderByNameErr
+		testApi.ArgsOut[UpdateOidcProviderMethod][0] = provider
+		testApi.ArgsOut[UpdateOidcProviderMethod][1] = nil
+
+		url := fmt.Sprintf(server.URL + API_VERSION_1 + "/admin/auth/oidc/providers/provider1")
+		req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(test.patch))
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", MERGE_PATCH_CONTENT_TYPE)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Check patched parameters
+			assert.Equal(t, "provider1", testApi.ArgsIn[UpdateOidcProviderMethod][1], "Error in test case %v", n)
+			assert.Equal(t, provider.Name, testApi.ArgsIn[UpdateOidcProviderMethod][2], "Error in test case %v", n)
+			assert.Equal(t, provider.Path, testApi.ArgsIn[UpdateOidcProviderMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.expectedIssuerURL, testApi.ArgsIn[UpdateOidcProviderMethod][4], "Error in test case %v", n)
+			assert.Equal(t, test.expectedClients, testApi.ArgsIn[UpdateOidcProviderMethod][5], "Error in test case %v", n)
+			response := &api.OidcProvider{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, provider, response, "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleRemoveOidcProvider(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
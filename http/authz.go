@@ -3,21 +3,17 @@ package http
 import (
 	"net/http"
 
+	"github.com/Tecsisa/foulkon/http/types"
 	"github.com/julienschmidt/httprouter"
 )
 
 // REQUESTS
 
-type AuthorizeResourcesRequest struct {
-	Action    string   `json:"action,omitempty"`
-	Resources []string `json:"resources,omitempty"`
-}
+type AuthorizeResourcesRequest = types.AuthorizeResourcesRequest
 
 // RESPONSES
 
-type AuthorizeResourcesResponse struct {
-	ResourcesAllowed []string `json:"resourcesAllowed,omitempty"`
-}
+type AuthorizeResourcesResponse = types.AuthorizeResourcesResponse
 
 // HANDLERS
 
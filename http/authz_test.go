@@ -25,7 +25,7 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 	}{
 		"OkCase": {
 			request: &AuthorizeResourcesRequest{
-				Resources: []string{},
+				Resources: []string{"urn:ews:product:instance:example/resource1"},
 				Action:    api.USER_ACTION_GET_USER,
 			},
 			expectedStatusCode: http.StatusOK,
@@ -43,7 +43,7 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 		},
 		"ErrorCaseInvalidParameter": {
 			request: &AuthorizeResourcesRequest{
-				Resources: []string{},
+				Resources: []string{"urn:ews:product:instance:example/resource1"},
 				Action:    api.USER_ACTION_GET_USER,
 			},
 			expectedStatusCode: http.StatusBadRequest,
@@ -58,7 +58,7 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 		},
 		"ErrorCaseUnauthorizedError": {
 			request: &AuthorizeResourcesRequest{
-				Resources: []string{},
+				Resources: []string{"urn:ews:product:instance:example/resource1"},
 				Action:    api.USER_ACTION_GET_USER,
 			},
 			expectedStatusCode: http.StatusForbidden,
@@ -73,7 +73,7 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 		},
 		"ErrorCaseUnknownApiError": {
 			request: &AuthorizeResourcesRequest{
-				Resources: []string{},
+				Resources: []string{"urn:ews:product:instance:example/resource1"},
 				Action:    api.USER_ACTION_GET_USER,
 			},
 			expectedStatusCode: http.StatusInternalServerError,
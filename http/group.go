@@ -4,50 +4,31 @@ import (
 	"net/http"
 
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
 	"github.com/julienschmidt/httprouter"
 )
 
 // REQUESTS
 
-type CreateGroupRequest struct {
-	Name string `json:"name,omitempty"`
-	Path string `json:"path,omitempty"`
-}
+type CreateGroupRequest = types.CreateGroupRequest
 
-type UpdateGroupRequest struct {
-	Name string `json:"name,omitempty"`
-	Path string `json:"path,omitempty"`
-}
+type UpdateGroupRequest = types.UpdateGroupRequest
+
+type UpdateMembersRequest = types.UpdateMembersRequest
+
+type UpdateAttachedGroupPoliciesRequest = types.UpdateAttachedGroupPoliciesRequest
 
 // RESPONSES
 
-type ListGroupsResponse struct {
-	Groups []string `json:"groups,omitempty"`
-	Limit  int      `json:"limit"`
-	Offset int      `json:"offset"`
-	Total  int      `json:"total"`
-}
+type ListGroupsResponse = types.ListGroupsResponse
 
-type ListAllGroupsResponse struct {
-	Groups []api.GroupIdentity `json:"groups,omitempty"`
-	Limit  int                 `json:"limit"`
-	Offset int                 `json:"offset"`
-	Total  int                 `json:"total"`
-}
+type ListAllGroupsResponse = types.ListAllGroupsResponse
 
-type ListMembersResponse struct {
-	Members []api.GroupMembers `json:"members,omitempty"`
-	Limit   int                `json:"limit"`
-	Offset  int                `json:"offset"`
-	Total   int                `json:"total"`
-}
+type ListMembersResponse = types.ListMembersResponse
 
-type ListAttachedGroupPoliciesResponse struct {
-	AttachedPolicies []api.GroupPolicies `json:"policies,omitempty"`
-	Limit            int                 `json:"limit"`
-	Offset           int                 `json:"offset"`
-	Total            int                 `json:"total"`
-}
+type ListAttachedGroupPoliciesResponse = types.ListAttachedGroupPoliciesResponse
+
+type ListSubgroupsResponse = types.ListSubgroupsResponse
 
 // HANDLERS
 
@@ -73,6 +54,9 @@ func (wh *WorkerHandler) HandleGetGroupByName(w http.ResponseWriter, r *http.Req
 	}
 	// Call group API to retrieve group
 	response, err := wh.worker.GroupApi.GetGroupByName(requestInfo, filterData.Org, filterData.GroupName)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -84,17 +68,18 @@ func (wh *WorkerHandler) HandleListGroups(w http.ResponseWriter, r *http.Request
 		return
 	}
 	// Call group API to retrieve group list
-	result, total, err := wh.worker.GroupApi.ListGroups(requestInfo, filterData)
+	result, total, nextToken, err := wh.worker.GroupApi.ListGroups(requestInfo, filterData)
 	groups := []string{}
 	for _, group := range result {
 		groups = append(groups, group.Name)
 	}
 	// Create response
 	response := &ListGroupsResponse{
-		Groups: groups,
-		Offset: filterData.Offset,
-		Limit:  filterData.Limit,
-		Total:  total,
+		Groups:    groups,
+		Offset:    filterData.Offset,
+		Limit:     filterData.Limit,
+		Total:     total,
+		NextToken: nextToken,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -107,13 +92,14 @@ func (wh *WorkerHandler) HandleListAllGroups(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	// Call group API to get all groups
-	result, total, err := wh.worker.GroupApi.ListGroups(requestInfo, filterData)
+	result, total, nextToken, err := wh.worker.GroupApi.ListGroups(requestInfo, filterData)
 	// Create response
 	response := &ListAllGroupsResponse{
-		Groups: result,
-		Offset: filterData.Offset,
-		Limit:  filterData.Limit,
-		Total:  total,
+		Groups:    result,
+		Offset:    filterData.Offset,
+		Limit:     filterData.Limit,
+		Total:     total,
+		NextToken: nextToken,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -128,6 +114,42 @@ func (wh *WorkerHandler) HandleUpdateGroup(w http.ResponseWriter, r *http.Reques
 	}
 	// Call group API to update group
 	response, err := wh.worker.GroupApi.UpdateGroup(requestInfo, filterData.Org, filterData.GroupName, request.Name, request.Path)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandlePatchGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to retrieve the group to patch
+	group, err := wh.worker.GroupApi.GetGroupByName(requestInfo, filterData.Org, filterData.GroupName)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusOK)
+		return
+	}
+	request := &UpdateGroupRequest{
+		Name: group.Name,
+		Path: group.Path,
+	}
+	if apiErr := applyPatch(r, requestInfo, request); apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Patched group is only updated over the retrieved revision
+	if requestInfo.Revision == 0 {
+		requestInfo.Revision = group.Revision
+	}
+	// Call group API to update group
+	response, err := wh.worker.GroupApi.UpdateGroup(requestInfo, filterData.Org, filterData.GroupName, request.Name, request.Path)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -143,6 +165,18 @@ func (wh *WorkerHandler) HandleRemoveGroup(w http.ResponseWriter, r *http.Reques
 	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
 }
 
+func (wh *WorkerHandler) HandleRestoreGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to restore deleted group
+	response, err := wh.worker.GroupApi.RestoreGroup(requestInfo, filterData.Org, filterData.GroupName)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
 func (wh *WorkerHandler) HandleAddMember(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Process request
 	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
@@ -175,16 +209,30 @@ func (wh *WorkerHandler) HandleListMembers(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	// Call group API to list members of group
-	result, total, err := wh.worker.GroupApi.ListMembers(requestInfo, filterData)
+	result, total, nextToken, err := wh.worker.GroupApi.ListMembers(requestInfo, filterData)
 	response := &ListMembersResponse{
-		Members: result,
-		Offset:  filterData.Offset,
-		Limit:   filterData.Limit,
-		Total:   total,
+		Members:   result,
+		Offset:    filterData.Offset,
+		Limit:     filterData.Limit,
+		Total:     total,
+		NextToken: nextToken,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
+func (wh *WorkerHandler) HandleUpdateMembers(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &UpdateMembersRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to add and remove members of group
+	response, err := wh.worker.GroupApi.UpdateMembers(requestInfo, filterData.Org, filterData.GroupName, request.Add, request.Remove)
+	wh.processGroupBulkResponse(r, w, requestInfo, response, err)
+}
+
 func (wh *WorkerHandler) HandleAttachPolicyToGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Process request
 	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
@@ -209,6 +257,20 @@ func (wh *WorkerHandler) HandleDetachPolicyToGroup(w http.ResponseWriter, r *htt
 	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
 }
 
+func (wh *WorkerHandler) HandleUpdateAttachedGroupPolicies(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &UpdateAttachedGroupPoliciesRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to attach and detach policies of group
+	response, err := wh.worker.GroupApi.UpdateAttachedGroupPolicies(requestInfo, filterData.Org, filterData.GroupName,
+		request.Attach, request.Detach)
+	wh.processGroupBulkResponse(r, w, requestInfo, response, err)
+}
+
 func (wh *WorkerHandler) HandleListAttachedGroupPolicies(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Process request
 	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
@@ -227,3 +289,59 @@ func (wh *WorkerHandler) HandleListAttachedGroupPolicies(w http.ResponseWriter,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
+
+func (wh *WorkerHandler) HandleAddSubgroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to add subgroup to group
+	err := wh.worker.GroupApi.AddSubgroup(requestInfo, filterData.Org, filterData.GroupName, ps.ByName(SUBGROUP_NAME))
+	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
+
+func (wh *WorkerHandler) HandleRemoveSubgroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to remove subgroup from group
+	err := wh.worker.GroupApi.RemoveSubgroup(requestInfo, filterData.Org, filterData.GroupName, ps.ByName(SUBGROUP_NAME))
+	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
+
+func (wh *WorkerHandler) HandleListSubgroups(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to list subgroups of group
+	result, total, err := wh.worker.GroupApi.ListSubgroups(requestInfo, filterData)
+	// Create response
+	response := &ListSubgroupsResponse{
+		Subgroups: result,
+		Offset:    filterData.Offset,
+		Limit:     filterData.Limit,
+		Total:     total,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+// processGroupBulkResponse writes the result of every item of a bulk request, with the status code
+// of the first item error if changes weren't applied
+func (wh *WorkerHandler) processGroupBulkResponse(r *http.Request, w http.ResponseWriter, requestInfo api.RequestInfo,
+	response *api.GroupBulkResult, err error) {
+	if err != nil && response != nil {
+		apiError := err.(*api.Error)
+		api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, apiError)
+		WriteHttpResponse(r, w, requestInfo.RequestID, requestInfo.Identifier, getErrorStatusCode(apiError), response)
+		return
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
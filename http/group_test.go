@@ -427,7 +427,7 @@ func TestWorkerHandler_HandleListGroups(t *testing.T) {
 
 		testApi.ArgsOut[ListGroupsMethod][0] = test.getListGroupResult
 		testApi.ArgsOut[ListGroupsMethod][1] = test.totalGroupsResult
-		testApi.ArgsOut[ListGroupsMethod][2] = test.getListGroupsErr
+		testApi.ArgsOut[ListGroupsMethod][3] = test.getListGroupsErr
 
 		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups", test.filter.Org)
 		req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -473,6 +473,7 @@ func TestWorkerHandler_HandleListAllGroups(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
 		filter       *api.Filter
+		createdAfter string
 		ignoreArgsIn bool
 		// Expected result
 		expectedStatusCode int
@@ -508,6 +509,44 @@ func TestWorkerHandler_HandleListAllGroups(t *testing.T) {
 			},
 			totalGroupsResult: 1,
 		},
+		"OkCaseSearch": {
+			filter: &api.Filter{
+				PathPrefix:     "/path/",
+				NameContains:   "group",
+				CreatedAfter:   time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+				Member:         "user1",
+				AttachedPolicy: "policy1",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListAllGroupsResponse{
+				Groups: []api.GroupIdentity{
+					{
+						Org:  "org1",
+						Name: "group1",
+					},
+				},
+				Total: 1,
+			},
+			getListAllGroupResult: []api.GroupIdentity{
+				{
+					Org:  "org1",
+					Name: "group1",
+				},
+			},
+			totalGroupsResult: 1,
+		},
+		"ErrorCaseInvalidCreatedAfter": {
+			filter: &api.Filter{
+				PathPrefix: "/path/",
+			},
+			createdAfter:       "yesterday",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: CreatedAfter yesterday",
+			},
+		},
 		"ErrorCaseInvalidFilterParams": {
 			filter: &api.Filter{
 				PathPrefix: "",
@@ -572,13 +611,18 @@ func TestWorkerHandler_HandleListAllGroups(t *testing.T) {
 
 		testApi.ArgsOut[ListGroupsMethod][0] = test.getListAllGroupResult
 		testApi.ArgsOut[ListGroupsMethod][1] = test.totalGroupsResult
-		testApi.ArgsOut[ListGroupsMethod][2] = test.getListAllGroupErr
+		testApi.ArgsOut[ListGroupsMethod][3] = test.getListAllGroupErr
 
 		url := fmt.Sprintf(server.URL + API_VERSION_1 + "/groups")
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		assert.Nil(t, err, "Error in test case %v", n)
 
 		addQueryParams(test.filter, req)
+		if test.createdAfter != "" {
+			q := req.URL.Query()
+			q.Add("CreatedAfter", test.createdAfter)
+			req.URL.RawQuery = q.Encode()
+		}
 
 		res, err := client.Do(req)
 		assert.Nil(t, err, "Error in test case %v", n)
@@ -787,6 +831,101 @@ func TestWorkerHandler_HandleUpdateGroup(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandlePatchGroup(t *testing.T) {
+	now := time.Now().UTC()
+	group := &api.Group{
+		ID:       "GroupID",
+		Name:     "group1",
+		Path:     "/path/",
+		Org:      "org1",
+		Urn:      api.CreateUrn("org1", api.RESOURCE_GROUP, "/path/", "group1"),
+		CreateAt: now,
+		UpdateAt: now,
+		Revision: 2,
+	}
+	testcases := map[string]struct {
+		// API method args
+		patch string
+		// Expected result
+		expectedStatusCode int
+		expectedName       string
+		expectedPath       string
+		expectedError      api.Error
+		// Manager Errors
+		getGroupByNameErr error
+	}{
+		"OkCase": {
+			patch:              `{"name":"group2"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedName:       "group2",
+			expectedPath:       "/path/",
+		},
+		"ErrorCaseGroupNotFound": {
+			patch:              `{"name":"group2"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+			getGroupByNameErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+		},
+		"ErrorCaseMalformedPatch": {
+			patch:              `{`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "unexpected EOF",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetGroupByNameMethod][0] = group
+		testApi.ArgsOut[GetGroupByNameMethod][1] = test.getGroupByNameErr
+		testApi.ArgsOut[UpdateGroupMethod][0] = group
+		testApi.ArgsOut[UpdateGroupMethod][1] = nil
+
+		url := fmt.Sprintf(server.URL + API_VERSION_1 + "/organizations/org1/groups/group1")
+		req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(test.patch))
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", MERGE_PATCH_CONTENT_TYPE)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Check patched parameters
+			requestInfo := testApi.ArgsIn[UpdateGroupMethod][0].(api.RequestInfo)
+			assert.Equal(t, group.Revision, requestInfo.Revision, "Error in test case %v", n)
+			assert.Equal(t, "org1", testApi.ArgsIn[UpdateGroupMethod][1], "Error in test case %v", n)
+			assert.Equal(t, "group1", testApi.ArgsIn[UpdateGroupMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.expectedName, testApi.ArgsIn[UpdateGroupMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.expectedPath, testApi.ArgsIn[UpdateGroupMethod][4], "Error in test case %v", n)
+			response := &api.Group{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, group, response, "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleRemoveGroup(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
@@ -912,6 +1051,134 @@ func TestWorkerHandler_HandleRemoveGroup(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandleRestoreGroup(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		org  string
+		name string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *api.Group
+		expectedError      api.Error
+		// Manager Results
+		restoreGroupResult *api.Group
+		// Manager Errors
+		restoreGroupErr error
+	}{
+		"OkCase": {
+			org:                "org1",
+			name:               "group1",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.Group{
+				ID:       "GroupID",
+				Name:     "group1",
+				Path:     "Path",
+				Urn:      "urn",
+				Org:      "org1",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			restoreGroupResult: &api.Group{
+				ID:       "GroupID",
+				Name:     "group1",
+				Path:     "Path",
+				Urn:      "urn",
+				Org:      "org1",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"ErrorCaseGroupAlreadyExist": {
+			org:                "org1",
+			name:               "group1",
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code:    api.GROUP_ALREADY_EXIST,
+				Message: "Group already exist",
+			},
+			restoreGroupErr: &api.Error{
+				Code:    api.GROUP_ALREADY_EXIST,
+				Message: "Group already exist",
+			},
+		},
+		"ErrorCaseGroupNotFound": {
+			org:                "org1",
+			name:               "group1",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Deleted group not found",
+			},
+			restoreGroupErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Deleted group not found",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			org:                "org1",
+			name:               "group1",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			restoreGroupErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:                "org1",
+			name:               "group1",
+			expectedStatusCode: http.StatusInternalServerError,
+			restoreGroupErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RestoreGroupMethod][0] = test.restoreGroupResult
+		testApi.ArgsOut[RestoreGroupMethod][1] = test.restoreGroupErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/restore", test.org, test.name)
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.org, testApi.ArgsIn[RestoreGroupMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.name, testApi.ArgsIn[RestoreGroupMethod][2], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &api.Group{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleAddMember(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
@@ -1239,6 +1506,7 @@ func TestWorkerHandler_HandleListMembers(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
 		filter       *api.Filter
+		transitive   string
 		ignoreArgsIn bool
 		// Expected result
 		expectedStatusCode int
@@ -1283,6 +1551,32 @@ func TestWorkerHandler_HandleListMembers(t *testing.T) {
 			},
 			totalGroupsResult: 2,
 		},
+		"OkCaseTransitive": {
+			filter: &api.Filter{
+				Org:        "org1",
+				GroupName:  "group1",
+				Transitive: true,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListMembersResponse{
+				Members: []api.GroupMembers{
+					{
+						User:     "member1",
+						CreateAt: now,
+					},
+				},
+				Offset: 0,
+				Limit:  0,
+				Total:  1,
+			},
+			getListMembersResult: []api.GroupMembers{
+				{
+					User:     "member1",
+					CreateAt: now,
+				},
+			},
+			totalGroupsResult: 1,
+		},
 		"ErrorCaseInvalidFilterParams": {
 			filter: &api.Filter{
 				PathPrefix: "",
@@ -1295,6 +1589,19 @@ func TestWorkerHandler_HandleListMembers(t *testing.T) {
 				Message: "Invalid parameter: Offset -1",
 			},
 		},
+		"ErrorCaseInvalidTransitive": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			transitive:         "invalid",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Transitive invalid",
+			},
+		},
 		"ErrorCaseGroupNotFoundErr": {
 			filter: &api.Filter{
 				Org:       "org1",
@@ -1356,13 +1663,18 @@ func TestWorkerHandler_HandleListMembers(t *testing.T) {
 
 		testApi.ArgsOut[ListMembersMethod][0] = test.getListMembersResult
 		testApi.ArgsOut[ListMembersMethod][1] = test.totalGroupsResult
-		testApi.ArgsOut[ListMembersMethod][2] = test.getListMembersErr
+		testApi.ArgsOut[ListMembersMethod][3] = test.getListMembersErr
 
 		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/users", test.filter.Org, test.filter.GroupName)
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		assert.Nil(t, err, "Error in test case %v", n)
 
 		addQueryParams(test.filter, req)
+		if test.transitive != "" {
+			q := req.URL.Query()
+			q.Add("Transitive", test.transitive)
+			req.URL.RawQuery = q.Encode()
+		}
 
 		res, err := client.Do(req)
 		assert.Nil(t, err, "Error in test case %v", n)
@@ -1399,40 +1711,235 @@ func TestWorkerHandler_HandleListMembers(t *testing.T) {
 	}
 }
 
-func TestWorkerHandler_HandleAttachPolicyToGroup(t *testing.T) {
+func TestWorkerHandler_HandleUpdateMembers(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
-		org          string
-		groupName    string
-		policyName   string
-		offset       string
-		ignoreArgsIn bool
+		org       string
+		groupName string
+		request   *UpdateMembersRequest
 		// Expected result
 		expectedStatusCode int
+		expectedResponse   *api.GroupBulkResult
 		expectedError      api.Error
+		// Manager Results
+		updateMembersResult *api.GroupBulkResult
 		// Manager Errors
-		attachGroupPolicyErr error
+		updateMembersErr error
 	}{
 		"OkCase": {
-			org:                "org1",
-			groupName:          "group1",
-			policyName:         "policy1",
-			expectedStatusCode: http.StatusNoContent,
-		},
-		"ErrorCaseInvalidRequest": {
-			org:                "org1",
-			groupName:          "group1",
-			policyName:         "policy1",
-			offset:             "-1",
-			ignoreArgsIn:       true,
-			expectedStatusCode: http.StatusBadRequest,
-			expectedError: api.Error{
-				Code:    api.INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: Offset -1",
-			},
-			attachGroupPolicyErr: &api.Error{
-				Code:    api.INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: Offset -1",
+			org:       "org1",
+			groupName: "group1",
+			request: &UpdateMembersRequest{
+				Add:    []string{"user1"},
+				Remove: []string{"user2"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.GroupBulkResult{
+				Applied: true,
+				Items: []api.GroupBulkItem{
+					{
+						Operation: api.GROUP_BULK_OPERATION_ADD,
+						Name:      "user1",
+					},
+					{
+						Operation: api.GROUP_BULK_OPERATION_REMOVE,
+						Name:      "user2",
+					},
+				},
+			},
+			updateMembersResult: &api.GroupBulkResult{
+				Applied: true,
+				Items: []api.GroupBulkItem{
+					{
+						Operation: api.GROUP_BULK_OPERATION_ADD,
+						Name:      "user1",
+					},
+					{
+						Operation: api.GROUP_BULK_OPERATION_REMOVE,
+						Name:      "user2",
+					},
+				},
+			},
+		},
+		"ErrorCaseItemFails": {
+			org:       "org1",
+			groupName: "group1",
+			request: &UpdateMembersRequest{
+				Add: []string{"user1"},
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: &api.GroupBulkResult{
+				Applied: false,
+				Items: []api.GroupBulkItem{
+					{
+						Operation: api.GROUP_BULK_OPERATION_ADD,
+						Name:      "user1",
+						Error: &api.Error{
+							Code:    api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+							Message: "User is already a member of group",
+						},
+					},
+				},
+			},
+			updateMembersResult: &api.GroupBulkResult{
+				Applied: false,
+				Items: []api.GroupBulkItem{
+					{
+						Operation: api.GROUP_BULK_OPERATION_ADD,
+						Name:      "user1",
+						Error: &api.Error{
+							Code:    api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+							Message: "User is already a member of group",
+						},
+					},
+				},
+			},
+			updateMembersErr: &api.Error{
+				Code:    api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+				Message: "User is already a member of group",
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			org:                "org1",
+			groupName:          "group1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseGroupNotFoundErr": {
+			org:       "org1",
+			groupName: "group1",
+			request: &UpdateMembersRequest{
+				Add: []string{"user1"},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+			updateMembersErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+		},
+		"ErrorCaseInvalidParameterErr": {
+			org:                "org1",
+			groupName:          "group1",
+			request:            &UpdateMembersRequest{},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+			updateMembersErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:       "org1",
+			groupName: "group1",
+			request: &UpdateMembersRequest{
+				Add: []string{"user1"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			updateMembersErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[UpdateMembersMethod][0] = test.updateMembersResult
+		testApi.ArgsOut[UpdateMembersMethod][1] = test.updateMembersErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/users", test.org, test.groupName)
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.org, testApi.ArgsIn[UpdateMembersMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.groupName, testApi.ArgsIn[UpdateMembersMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.Add, testApi.ArgsIn[UpdateMembersMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.Remove, testApi.ArgsIn[UpdateMembersMethod][4], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch {
+		case test.expectedResponse != nil:
+			response := &api.GroupBulkResult{}
+			err = json.NewDecoder(res.Body).Decode(response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case res.StatusCode == http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleAttachPolicyToGroup(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		org          string
+		groupName    string
+		policyName   string
+		offset       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		attachGroupPolicyErr error
+	}{
+		"OkCase": {
+			org:                "org1",
+			groupName:          "group1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseInvalidRequest": {
+			org:                "org1",
+			groupName:          "group1",
+			policyName:         "policy1",
+			offset:             "-1",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+			attachGroupPolicyErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
 			},
 		},
 		"ErrorCaseGroupNotFoundErr": {
@@ -1880,3 +2387,599 @@ func TestWorkerHandler_HandleListAttachedGroupPolicies(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkerHandler_HandleUpdateAttachedGroupPolicies(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		org       string
+		groupName string
+		request   *UpdateAttachedGroupPoliciesRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *api.GroupBulkResult
+		expectedError      api.Error
+		// Manager Results
+		updateAttachedGroupPoliciesResult *api.GroupBulkResult
+		// Manager Errors
+		updateAttachedGroupPoliciesErr error
+	}{
+		"OkCase": {
+			org:       "org1",
+			groupName: "group1",
+			request: &UpdateAttachedGroupPoliciesRequest{
+				Attach: []string{"policy1"},
+				Detach: []string{"policy2"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.GroupBulkResult{
+				Applied: true,
+				Items: []api.GroupBulkItem{
+					{
+						Operation: api.GROUP_BULK_OPERATION_ATTACH,
+						Name:      "policy1",
+					},
+					{
+						Operation: api.GROUP_BULK_OPERATION_DETACH,
+						Name:      "policy2",
+					},
+				},
+			},
+			updateAttachedGroupPoliciesResult: &api.GroupBulkResult{
+				Applied: true,
+				Items: []api.GroupBulkItem{
+					{
+						Operation: api.GROUP_BULK_OPERATION_ATTACH,
+						Name:      "policy1",
+					},
+					{
+						Operation: api.GROUP_BULK_OPERATION_DETACH,
+						Name:      "policy2",
+					},
+				},
+			},
+		},
+		"ErrorCaseItemFails": {
+			org:       "org1",
+			groupName: "group1",
+			request: &UpdateAttachedGroupPoliciesRequest{
+				Attach: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: &api.GroupBulkResult{
+				Applied: false,
+				Items: []api.GroupBulkItem{
+					{
+						Operation: api.GROUP_BULK_OPERATION_ATTACH,
+						Name:      "policy1",
+						Error: &api.Error{
+							Code:    api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+							Message: "Policy is already attached to group",
+						},
+					},
+				},
+			},
+			updateAttachedGroupPoliciesResult: &api.GroupBulkResult{
+				Applied: false,
+				Items: []api.GroupBulkItem{
+					{
+						Operation: api.GROUP_BULK_OPERATION_ATTACH,
+						Name:      "policy1",
+						Error: &api.Error{
+							Code:    api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+							Message: "Policy is already attached to group",
+						},
+					},
+				},
+			},
+			updateAttachedGroupPoliciesErr: &api.Error{
+				Code:    api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+				Message: "Policy is already attached to group",
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			org:                "org1",
+			groupName:          "group1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseGroupNotFoundErr": {
+			org:       "org1",
+			groupName: "group1",
+			request: &UpdateAttachedGroupPoliciesRequest{
+				Attach: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+			updateAttachedGroupPoliciesErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+		},
+		"ErrorCaseInvalidParameterErr": {
+			org:                "org1",
+			groupName:          "group1",
+			request:            &UpdateAttachedGroupPoliciesRequest{},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+			updateAttachedGroupPoliciesErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:       "org1",
+			groupName: "group1",
+			request: &UpdateAttachedGroupPoliciesRequest{
+				Attach: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			updateAttachedGroupPoliciesErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[UpdateAttachedGroupPoliciesMethod][0] = test.updateAttachedGroupPoliciesResult
+		testApi.ArgsOut[UpdateAttachedGroupPoliciesMethod][1] = test.updateAttachedGroupPoliciesErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/policies", test.org, test.groupName)
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.org, testApi.ArgsIn[UpdateAttachedGroupPoliciesMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.groupName, testApi.ArgsIn[UpdateAttachedGroupPoliciesMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.Attach, testApi.ArgsIn[UpdateAttachedGroupPoliciesMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.Detach, testApi.ArgsIn[UpdateAttachedGroupPoliciesMethod][4], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch {
+		case test.expectedResponse != nil:
+			response := &api.GroupBulkResult{}
+			err = json.NewDecoder(res.Body).Decode(response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case res.StatusCode == http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleAddSubgroup(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		org          string
+		groupName    string
+		subgroupName string
+		offset       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		addSubgroupErr error
+	}{
+		"OkCase": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseInvalidRequest": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			offset:             "-1",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseGroupNotFoundErr": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+			addSubgroupErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+		},
+		"ErrorCaseGroupIsAlreadyASubgroupErr": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code:    api.GROUP_IS_ALREADY_A_SUBGROUP,
+				Message: "Group is already a subgroup",
+			},
+			addSubgroupErr: &api.Error{
+				Code:    api.GROUP_IS_ALREADY_A_SUBGROUP,
+				Message: "Group is already a subgroup",
+			},
+		},
+		"ErrorCaseGroupHierarchyCycleErr": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code:    api.GROUP_HIERARCHY_CYCLE,
+				Message: "Cycle",
+			},
+			addSubgroupErr: &api.Error{
+				Code:    api.GROUP_HIERARCHY_CYCLE,
+				Message: "Cycle",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			addSubgroupErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusInternalServerError,
+			addSubgroupErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[AddSubgroupMethod][0] = test.addSubgroupErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/groups/%v", test.org, test.groupName, test.subgroupName)
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		q.Add("Offset", test.offset)
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			assert.Equal(t, test.org, testApi.ArgsIn[AddSubgroupMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.groupName, testApi.ArgsIn[AddSubgroupMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.subgroupName, testApi.ArgsIn[AddSubgroupMethod][3], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusNoContent:
+			// No message expected
+			continue
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleRemoveSubgroup(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		org          string
+		groupName    string
+		subgroupName string
+		offset       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		removeSubgroupErr error
+	}{
+		"OkCase": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseInvalidRequest": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			offset:             "-1",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseGroupIsNotASubgroupErr": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_IS_NOT_A_SUBGROUP,
+				Message: "Group is not a subgroup",
+			},
+			removeSubgroupErr: &api.Error{
+				Code:    api.GROUP_IS_NOT_A_SUBGROUP,
+				Message: "Group is not a subgroup",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			removeSubgroupErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:                "org1",
+			groupName:          "group1",
+			subgroupName:       "group2",
+			expectedStatusCode: http.StatusInternalServerError,
+			removeSubgroupErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RemoveSubgroupMethod][0] = test.removeSubgroupErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/groups/%v", test.org, test.groupName, test.subgroupName)
+		req, err := http.NewRequest(http.MethodDelete, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		q.Add("Offset", test.offset)
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			assert.Equal(t, test.org, testApi.ArgsIn[RemoveSubgroupMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.groupName, testApi.ArgsIn[RemoveSubgroupMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.subgroupName, testApi.ArgsIn[RemoveSubgroupMethod][3], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusNoContent:
+			// No message expected
+			continue
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListSubgroups(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		filter       *api.Filter
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListSubgroupsResponse
+		expectedError      api.Error
+		// Manager Results
+		listSubgroupsResult []api.GroupSubgroups
+		totalSubgroupResult int
+		// Manager Errors
+		listSubgroupsErr error
+	}{
+		"OkCase": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListSubgroupsResponse{
+				Subgroups: []api.GroupSubgroups{
+					{
+						Group:    "group2",
+						CreateAt: now,
+					},
+				},
+				Offset: 0,
+				Limit:  0,
+				Total:  1,
+			},
+			listSubgroupsResult: []api.GroupSubgroups{
+				{
+					Group:    "group2",
+					CreateAt: now,
+				},
+			},
+			totalSubgroupResult: 1,
+		},
+		"ErrorCaseInvalidFilterParams": {
+			filter: &api.Filter{
+				Offset: -1,
+			},
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseGroupNotFoundErr": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+			listSubgroupsErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			listSubgroupsErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			listSubgroupsErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListSubgroupsMethod][0] = test.listSubgroupsResult
+		testApi.ArgsOut[ListSubgroupsMethod][1] = test.totalSubgroupResult
+		testApi.ArgsOut[ListSubgroupsMethod][2] = test.listSubgroupsErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/groups", test.filter.Org, test.filter.GroupName)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameter
+			filterData, ok := testApi.ArgsIn[ListSubgroupsMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			listSubgroupsResponse := ListSubgroupsResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listSubgroupsResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listSubgroupsResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
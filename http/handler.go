@@ -6,65 +6,125 @@ import (
 
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/foulkon"
+	"github.com/Tecsisa/foulkon/http/types"
 	"github.com/julienschmidt/httprouter"
 )
 
 const (
+	// Routes and headers are defined in package types, so API clients don't depend on this package
+
 	// Constants for values in url
-	USER_ID             = "userid"
-	GROUP_NAME          = "groupname"
-	POLICY_NAME         = "policyname"
-	PROXY_RESOURCE_NAME = "proxyresourcename"
-	AUTH_PROVIDER_NAME  = "authprovidername"
-	ORG_NAME            = "orgname"
+	USER_ID             = types.USER_ID
+	GROUP_NAME          = types.GROUP_NAME
+	SUBGROUP_NAME       = types.SUBGROUP_NAME
+	POLICY_NAME         = types.POLICY_NAME
+	POLICY_VERSION      = types.POLICY_VERSION
+	PROXY_RESOURCE_NAME = types.PROXY_RESOURCE_NAME
+	AUTH_PROVIDER_NAME  = types.AUTH_PROVIDER_NAME
+	ORG_NAME            = types.ORG_NAME
+	WEBHOOK_NAME        = types.WEBHOOK_NAME
 
 	// URI Path param prefix
-	URI_PATH_PREFIX = "/:"
+	URI_PATH_PREFIX = types.URI_PATH_PREFIX
+
+	// Optimistic concurrency headers
+	ETAG_HEADER     = types.ETAG_HEADER
+	IF_MATCH_HEADER = types.IF_MATCH_HEADER
 
 	// API root reference
-	API_ROOT      = "/api"
-	API_VERSION_1 = API_ROOT + "/v1"
+	API_ROOT      = types.API_ROOT
+	API_VERSION_1 = types.API_VERSION_1
 
 	// Organization API ROOT
-	ORG_ROOT = "/organizations/:" + ORG_NAME
+	ORG_ROOT = types.ORG_ROOT
+
+	// Organization API urls
+	ORGANIZATION_ROOT_URL = types.ORGANIZATION_ROOT_URL
+	ORGANIZATION_ID_URL   = types.ORGANIZATION_ID_URL
 
 	// User API urls
-	USER_ROOT_URL      = API_VERSION_1 + "/users"
-	USER_ID_URL        = USER_ROOT_URL + URI_PATH_PREFIX + USER_ID
-	USER_ID_GROUPS_URL = USER_ID_URL + "/groups"
+	USER_ROOT_URL           = types.USER_ROOT_URL
+	USER_ID_URL             = types.USER_ID_URL
+	USER_ID_RESTORE_URL     = types.USER_ID_RESTORE_URL
+	USER_ID_GROUPS_URL      = types.USER_ID_GROUPS_URL
+	USER_ID_POLICIES_URL    = types.USER_ID_POLICIES_URL
+	USER_ID_POLICIES_ID_URL = types.USER_ID_POLICIES_ID_URL
 
 	// Group organization API urls
-	GROUP_ORG_ROOT_URL       = API_VERSION_1 + ORG_ROOT + "/groups"
-	GROUP_ID_URL             = GROUP_ORG_ROOT_URL + URI_PATH_PREFIX + GROUP_NAME
-	GROUP_ID_USERS_URL       = GROUP_ID_URL + "/users"
-	GROUP_ID_USERS_ID_URL    = GROUP_ID_USERS_URL + URI_PATH_PREFIX + USER_ID
-	GROUP_ID_POLICIES_URL    = GROUP_ID_URL + "/policies"
-	GROUP_ID_POLICIES_ID_URL = GROUP_ID_POLICIES_URL + URI_PATH_PREFIX + POLICY_NAME
+	GROUP_ORG_ROOT_URL       = types.GROUP_ORG_ROOT_URL
+	GROUP_ID_URL             = types.GROUP_ID_URL
+	GROUP_ID_RESTORE_URL     = types.GROUP_ID_RESTORE_URL
+	GROUP_ID_USERS_URL       = types.GROUP_ID_USERS_URL
+	GROUP_ID_USERS_ID_URL    = types.GROUP_ID_USERS_ID_URL
+	GROUP_ID_POLICIES_URL    = types.GROUP_ID_POLICIES_URL
+	GROUP_ID_POLICIES_ID_URL = types.GROUP_ID_POLICIES_ID_URL
+	GROUP_ID_GROUPS_URL      = types.GROUP_ID_GROUPS_URL
+	GROUP_ID_GROUPS_ID_URL   = types.GROUP_ID_GROUPS_ID_URL
 
 	// Policy API urls
-	POLICY_ROOT_URL      = API_VERSION_1 + ORG_ROOT + "/policies"
-	POLICY_ID_URL        = POLICY_ROOT_URL + URI_PATH_PREFIX + POLICY_NAME
-	POLICY_ID_GROUPS_URL = POLICY_ROOT_URL + URI_PATH_PREFIX + POLICY_NAME + "/groups"
+	POLICY_ROOT_URL       = types.POLICY_ROOT_URL
+	POLICY_ID_URL         = types.POLICY_ID_URL
+	POLICY_ID_GROUPS_URL  = types.POLICY_ID_GROUPS_URL
+	POLICY_ID_RESTORE_URL = types.POLICY_ID_RESTORE_URL
+
+	// Policy version API urls
+	POLICY_ID_VERSIONS_URL        = types.POLICY_ID_VERSIONS_URL
+	POLICY_ID_VERSIONS_ID_URL     = types.POLICY_ID_VERSIONS_ID_URL
+	POLICY_ID_VERSIONS_DIFF_URL   = types.POLICY_ID_VERSIONS_DIFF_URL
+	POLICY_ID_DEFAULT_VERSION_URL = types.POLICY_ID_DEFAULT_VERSION_URL
 
 	// Proxy resource API urls
-	PROXY_RESOURCE_ROOT_URL = API_VERSION_1 + ORG_ROOT + "/proxy-resources"
-	PROXY_RESOURCE_ID_URL   = PROXY_RESOURCE_ROOT_URL + URI_PATH_PREFIX + PROXY_RESOURCE_NAME
+	PROXY_RESOURCE_ROOT_URL = types.PROXY_RESOURCE_ROOT_URL
+	PROXY_RESOURCE_ID_URL   = types.PROXY_RESOURCE_ID_URL
 
 	// Authorization URLs
-	RESOURCE_URL = API_VERSION_1 + "/resource"
+	RESOURCE_URL = types.RESOURCE_URL
 
 	// Admin URLs
-	ADMIN_ROOT = "/admin"
+	ADMIN_ROOT = types.ADMIN_ROOT
 
 	// Admin OIDC Authentication API URLs
-	OIDC_AUTH_ROOT_URL = API_VERSION_1 + ADMIN_ROOT + "/auth/oidc/providers"
-	OIDC_AUTH_ID_URL   = OIDC_AUTH_ROOT_URL + URI_PATH_PREFIX + AUTH_PROVIDER_NAME
+	OIDC_AUTH_ROOT_URL = types.OIDC_AUTH_ROOT_URL
+	OIDC_AUTH_ID_URL   = types.OIDC_AUTH_ID_URL
+
+	// Admin IAM state API URLs
+	STATE_EXPORT_URL = types.STATE_EXPORT_URL
+	STATE_IMPORT_URL = types.STATE_IMPORT_URL
+
+	// Admin webhook API URLs
+	WEBHOOK_ROOT_URL          = types.WEBHOOK_ROOT_URL
+	WEBHOOK_ID_URL            = types.WEBHOOK_ID_URL
+	WEBHOOK_ID_DELIVERIES_URL = types.WEBHOOK_ID_DELIVERIES_URL
+
+	// Admin Kubernetes authorization webhook URL
+	KUBERNETES_SUBJECT_ACCESS_REVIEW_URL = types.KUBERNETES_SUBJECT_ACCESS_REVIEW_URL
+
+	// Reconcile API urls
+	RECONCILE_PLAN_URL  = types.RECONCILE_PLAN_URL
+	RECONCILE_APPLY_URL = types.RECONCILE_APPLY_URL
 
 	// Foulkon configuration URL
-	ABOUT = "/about"
+	ABOUT = types.ABOUT
+
+	// OpenAPI document URL
+	OPENAPI_URL = types.OPENAPI_URL
+
+	// OpenAPI tags of the routes
+	USER_TAG           = "Users"
+	ORGANIZATION_TAG   = "Organizations"
+	GROUP_TAG          = "Groups"
+	POLICY_TAG         = "Policies"
+	PROXY_RESOURCE_TAG = "Proxy resources"
+	AUTHORIZATION_TAG  = "Authorization"
+	OIDC_PROVIDER_TAG  = "OIDC providers"
+	STATE_TAG          = "IAM state"
+	WEBHOOK_TAG        = "Webhooks"
+	ABOUT_TAG          = "About"
 )
 
 // PROXY
@@ -84,14 +144,12 @@ func (wh *WorkerHandler) processHttpRequest(r *http.Request, w http.ResponseWrit
 	requestInfo api.RequestInfo, filterData *api.Filter, apiError *api.Error) {
 	// Get Request Info
 	requestInfo = wh.getRequestInfo(r)
-	// Decode request if passed
+	// Limit request body, so large requests aren't read into memory
+	r.Body = http.MaxBytesReader(w, r.Body, MAX_REQUEST_BODY_SIZE)
+	// Validate and decode request if passed
 	if request != nil {
-		err := json.NewDecoder(r.Body).Decode(&request)
-		if err != nil {
-			apiError = &api.Error{
-				Code:    api.INVALID_PARAMETER_ERROR,
-				Message: err.Error(),
-			}
+		apiError = decodeRequestBody(r, request)
+		if apiError != nil {
 			api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, apiError)
 		}
 	}
@@ -108,32 +166,7 @@ func (wh *WorkerHandler) processHttpResponse(r *http.Request, w http.ResponseWri
 		// Transform to API errors
 		apiError := err.(*api.Error)
 		api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, apiError)
-		var statusCode int
-		switch apiError.Code {
-		case api.USER_ALREADY_EXIST, api.GROUP_ALREADY_EXIST,
-			api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
-			api.PROXY_RESOURCE_ALREADY_EXIST,
-			api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP, api.POLICY_ALREADY_EXIST,
-			api.PROXY_RESOURCES_ROUTES_CONFLICT,
-			api.AUTH_OIDC_PROVIDER_ALREADY_EXIST:
-			// A conflict occurs
-			statusCode = http.StatusConflict
-		case api.UNAUTHORIZED_RESOURCES_ERROR:
-			// No authorization success
-			statusCode = http.StatusForbidden
-		case api.USER_BY_EXTERNAL_ID_NOT_FOUND, api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
-			api.USER_IS_NOT_A_MEMBER_OF_GROUP, api.POLICY_IS_NOT_ATTACHED_TO_GROUP,
-			api.POLICY_BY_ORG_AND_NAME_NOT_FOUND, api.PROXY_RESOURCE_BY_ORG_AND_NAME_NOT_FOUND,
-			api.AUTH_OIDC_PROVIDER_BY_NAME_NOT_FOUND:
-			// Resource or relation not found
-			statusCode = http.StatusNotFound
-		case api.INVALID_PARAMETER_ERROR, api.REGEX_NO_MATCH:
-			// Unexpected input in validation parameters
-			statusCode = http.StatusBadRequest
-		default: // Unexpected API error
-			statusCode = http.StatusInternalServerError
-		}
-		WriteHttpResponse(r, w, requestInfo.RequestID, requestInfo.Identifier, statusCode, apiError)
+		WriteHttpResponse(r, w, requestInfo.RequestID, requestInfo.Identifier, getErrorStatusCode(apiError), apiError)
 		return
 	}
 
@@ -141,14 +174,308 @@ func (wh *WorkerHandler) processHttpResponse(r *http.Request, w http.ResponseWri
 	WriteHttpResponse(r, w, requestInfo.RequestID, requestInfo.Identifier, responseCode, response)
 }
 
+// errorStatusCodes are the HTTP status codes of API errors, other errors are unexpected ones
+var errorStatusCodes = map[string]int{
+	// A conflict occurs
+	api.USER_ALREADY_EXIST:                  http.StatusConflict,
+	api.GROUP_ALREADY_EXIST:                 http.StatusConflict,
+	api.USER_IS_ALREADY_A_MEMBER_OF_GROUP:   http.StatusConflict,
+	api.PROXY_RESOURCE_ALREADY_EXIST:        http.StatusConflict,
+	api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP: http.StatusConflict,
+	api.POLICY_ALREADY_EXIST:                http.StatusConflict,
+	api.POLICY_IS_ALREADY_ATTACHED_TO_USER:  http.StatusConflict,
+	api.GROUP_IS_ALREADY_A_SUBGROUP:         http.StatusConflict,
+	api.GROUP_HIERARCHY_CYCLE:               http.StatusConflict,
+	api.PROXY_RESOURCES_ROUTES_CONFLICT:     http.StatusConflict,
+	api.ORGANIZATION_ALREADY_EXIST:          http.StatusConflict,
+	api.AUTH_OIDC_PROVIDER_ALREADY_EXIST:    http.StatusConflict,
+	api.WEBHOOK_ALREADY_EXIST:               http.StatusConflict,
+
+	// No authorization success
+	api.UNAUTHORIZED_RESOURCES_ERROR: http.StatusForbidden,
+
+	// Resource or relation not found
+	api.USER_BY_EXTERNAL_ID_NOT_FOUND:            http.StatusNotFound,
+	api.GROUP_BY_ORG_AND_NAME_NOT_FOUND:          http.StatusNotFound,
+	api.USER_IS_NOT_A_MEMBER_OF_GROUP:            http.StatusNotFound,
+	api.POLICY_IS_NOT_ATTACHED_TO_GROUP:          http.StatusNotFound,
+	api.POLICY_IS_NOT_ATTACHED_TO_USER:           http.StatusNotFound,
+	api.GROUP_IS_NOT_A_SUBGROUP:                  http.StatusNotFound,
+	api.POLICY_BY_ORG_AND_NAME_NOT_FOUND:         http.StatusNotFound,
+	api.POLICY_VERSION_NOT_FOUND:                 http.StatusNotFound,
+	api.PROXY_RESOURCE_BY_ORG_AND_NAME_NOT_FOUND: http.StatusNotFound,
+	api.ORGANIZATION_BY_NAME_NOT_FOUND:           http.StatusNotFound,
+	api.AUTH_OIDC_PROVIDER_BY_NAME_NOT_FOUND:     http.StatusNotFound,
+	api.WEBHOOK_BY_NAME_NOT_FOUND:                http.StatusNotFound,
+
+	// Resource was modified after the revision required
+	api.REVISION_MISMATCH: http.StatusPreconditionFailed,
+
+	// Unexpected input in validation parameters
+	api.INVALID_PARAMETER_ERROR: http.StatusBadRequest,
+	api.REGEX_NO_MATCH:          http.StatusBadRequest,
+
+	// Request body is larger than allowed
+	api.REQUEST_BODY_TOO_LARGE: http.StatusRequestEntityTooLarge,
+}
+
+// getErrorStatusCode returns the HTTP status code for an API error
+func getErrorStatusCode(apiError *api.Error) int {
+	if statusCode, ok := errorStatusCodes[apiError.Code]; ok {
+		return statusCode
+	}
+	// Unexpected API error
+	return http.StatusInternalServerError
+}
+
 func (wh *WorkerHandler) getRequestInfo(r *http.Request) api.RequestInfo {
 	// Retrieve request information from middleware context
 	mc := wh.worker.MiddlewareHandler.GetMiddlewareContext(r)
-	return api.RequestInfo{
+	requestInfo := api.RequestInfo{
 		Identifier: mc.UserId,
 		Admin:      mc.Admin,
 		RequestID:  mc.XRequestId,
 	}
+	// Updates and deletions are only done over the revision required by If-Match header
+	if r.Method == http.MethodPut || r.Method == http.MethodPatch || r.Method == http.MethodDelete {
+		requestInfo.Revision = getIfMatchRevision(r)
+	}
+	return requestInfo
+}
+
+// route of the worker API, used both to register it in the router and to describe it in the OpenAPI document
+type route struct {
+	method string
+	path   string
+	handle func(*WorkerHandler, http.ResponseWriter, *http.Request, httprouter.Params)
+	tag    string
+	// Query parameters read by the handler
+	query []string
+	// Request body, nil if the route doesn't read any
+	request interface{}
+	// Status code and body of successful responses, nil body if they don't have any
+	status   int
+	response interface{}
+	// Successful responses have an ETag header with the entity revision
+	etag bool
+	// Entity is updated or removed only if its revision matches the If-Match header
+	ifMatch bool
+	// Request and response bodies can be YAML documents too
+	yaml bool
+}
+
+// workerRoutes returns the routes of the worker API
+func workerRoutes() []route {
+	return []route{
+		// User api
+		{method: http.MethodGet, path: USER_ROOT_URL, handle: (*WorkerHandler).HandleListUsers, tag: USER_TAG,
+			query:  queryParams([]string{"PathPrefix", "Deleted", "NextToken"}, searchQuery, paginationQuery),
+			status: http.StatusOK, response: GetUserExternalIDsResponse{}},
+		{method: http.MethodPost, path: USER_ROOT_URL, handle: (*WorkerHandler).HandleAddUser, tag: USER_TAG,
+			request: CreateUserRequest{}, status: http.StatusCreated, response: api.User{}},
+
+		{method: http.MethodGet, path: USER_ID_URL, handle: (*WorkerHandler).HandleGetUserByExternalID, tag: USER_TAG,
+			status: http.StatusOK, response: api.User{}, etag: true},
+		{method: http.MethodPut, path: USER_ID_URL, handle: (*WorkerHandler).HandleUpdateUser, tag: USER_TAG,
+			request: UpdateUserRequest{}, status: http.StatusOK, response: api.User{}, etag: true, ifMatch: true},
+		{method: http.MethodPatch, path: USER_ID_URL, handle: (*WorkerHandler).HandlePatchUser, tag: USER_TAG,
+			request: UpdateUserRequest{}, status: http.StatusOK, response: api.User{}, etag: true, ifMatch: true},
+		{method: http.MethodDelete, path: USER_ID_URL, handle: (*WorkerHandler).HandleRemoveUser, tag: USER_TAG,
+			status: http.StatusNoContent, ifMatch: true},
+
+		{method: http.MethodPost, path: USER_ID_RESTORE_URL, handle: (*WorkerHandler).HandleRestoreUser, tag: USER_TAG,
+			status: http.StatusOK, response: api.User{}},
+
+		{method: http.MethodGet, path: USER_ID_GROUPS_URL, handle: (*WorkerHandler).HandleListGroupsByUser, tag: USER_TAG,
+			query: paginationQuery, status: http.StatusOK, response: GetGroupsByUserIdResponse{}},
+
+		{method: http.MethodGet, path: USER_ID_POLICIES_URL, handle: (*WorkerHandler).HandleListAttachedUserPolicies, tag: USER_TAG,
+			query: paginationQuery, status: http.StatusOK, response: ListAttachedUserPoliciesResponse{}},
+
+		{method: http.MethodPost, path: USER_ID_POLICIES_ID_URL, handle: (*WorkerHandler).HandleAttachPolicyToUser, tag: USER_TAG,
+			status: http.StatusNoContent},
+		{method: http.MethodDelete, path: USER_ID_POLICIES_ID_URL, handle: (*WorkerHandler).HandleDetachPolicyFromUser, tag: USER_TAG,
+			status: http.StatusNoContent},
+
+		// Organization api
+		{method: http.MethodGet, path: ORGANIZATION_ROOT_URL, handle: (*WorkerHandler).HandleListOrganizations, tag: ORGANIZATION_TAG,
+			query: queryParams([]string{"PathPrefix"}, paginationQuery), status: http.StatusOK, response: ListOrganizationsResponse{}},
+		{method: http.MethodPost, path: ORGANIZATION_ROOT_URL, handle: (*WorkerHandler).HandleAddOrganization, tag: ORGANIZATION_TAG,
+			request: CreateOrganizationRequest{}, status: http.StatusCreated, response: api.Organization{}},
+
+		{method: http.MethodDelete, path: ORGANIZATION_ID_URL, handle: (*WorkerHandler).HandleRemoveOrganization, tag: ORGANIZATION_TAG,
+			status: http.StatusNoContent},
+		{method: http.MethodGet, path: ORGANIZATION_ID_URL, handle: (*WorkerHandler).HandleGetOrganizationByName, tag: ORGANIZATION_TAG,
+			status: http.StatusOK, response: api.Organization{}},
+		{method: http.MethodPut, path: ORGANIZATION_ID_URL, handle: (*WorkerHandler).HandleUpdateOrganization, tag: ORGANIZATION_TAG,
+			request: UpdateOrganizationRequest{}, status: http.StatusOK, response: api.Organization{}},
+
+		// Group api
+		{method: http.MethodPost, path: GROUP_ORG_ROOT_URL, handle: (*WorkerHandler).HandleAddGroup, tag: GROUP_TAG,
+			request: CreateGroupRequest{}, status: http.StatusCreated, response: api.Group{}},
+		{method: http.MethodGet, path: GROUP_ORG_ROOT_URL, handle: (*WorkerHandler).HandleListGroups, tag: GROUP_TAG,
+			query:  queryParams([]string{"PathPrefix", "Member", "AttachedPolicy", "Deleted", "NextToken"}, searchQuery, paginationQuery),
+			status: http.StatusOK, response: ListGroupsResponse{}},
+
+		{method: http.MethodDelete, path: GROUP_ID_URL, handle: (*WorkerHandler).HandleRemoveGroup, tag: GROUP_TAG,
+			status: http.StatusNoContent, ifMatch: true},
+		{method: http.MethodGet, path: GROUP_ID_URL, handle: (*WorkerHandler).HandleGetGroupByName, tag: GROUP_TAG,
+			status: http.StatusOK, response: api.Group{}, etag: true},
+		{method: http.MethodPut, path: GROUP_ID_URL, handle: (*WorkerHandler).HandleUpdateGroup, tag: GROUP_TAG,
+			request: UpdateGroupRequest{}, status: http.StatusOK, response: api.Group{}, etag: true, ifMatch: true},
+		{method: http.MethodPatch, path: GROUP_ID_URL, handle: (*WorkerHandler).HandlePatchGroup, tag: GROUP_TAG,
+			request: UpdateGroupRequest{}, status: http.StatusOK, response: api.Group{}, etag: true, ifMatch: true},
+
+		{method: http.MethodPost, path: GROUP_ID_RESTORE_URL, handle: (*WorkerHandler).HandleRestoreGroup, tag: GROUP_TAG,
+			status: http.StatusOK, response: api.Group{}},
+
+		{method: http.MethodGet, path: GROUP_ID_USERS_URL, handle: (*WorkerHandler).HandleListMembers, tag: GROUP_TAG,
+			query: queryParams([]string{"Transitive", "NextToken"}, paginationQuery), status: http.StatusOK, response: ListMembersResponse{}},
+		{method: http.MethodPost, path: GROUP_ID_USERS_URL, handle: (*WorkerHandler).HandleUpdateMembers, tag: GROUP_TAG,
+			request: UpdateMembersRequest{}, status: http.StatusOK, response: api.GroupBulkResult{}},
+
+		{method: http.MethodPost, path: GROUP_ID_USERS_ID_URL, handle: (*WorkerHandler).HandleAddMember, tag: GROUP_TAG,
+			status: http.StatusNoContent},
+		{method: http.MethodDelete, path: GROUP_ID_USERS_ID_URL, handle: (*WorkerHandler).HandleRemoveMember, tag: GROUP_TAG,
+			status: http.StatusNoContent},
+
+		{method: http.MethodGet, path: GROUP_ID_POLICIES_URL, handle: (*WorkerHandler).HandleListAttachedGroupPolicies, tag: GROUP_TAG,
+			query: paginationQuery, status: http.StatusOK, response: ListAttachedGroupPoliciesResponse{}},
+		{method: http.MethodPost, path: GROUP_ID_POLICIES_URL, handle: (*WorkerHandler).HandleUpdateAttachedGroupPolicies, tag: GROUP_TAG,
+			request: UpdateAttachedGroupPoliciesRequest{}, status: http.StatusOK, response: api.GroupBulkResult{}},
+
+		{method: http.MethodPost, path: GROUP_ID_POLICIES_ID_URL, handle: (*WorkerHandler).HandleAttachPolicyToGroup, tag: GROUP_TAG,
+			status: http.StatusNoContent},
+		{method: http.MethodDelete, path: GROUP_ID_POLICIES_ID_URL, handle: (*WorkerHandler).HandleDetachPolicyToGroup, tag: GROUP_TAG,
+			status: http.StatusNoContent},
+
+		{method: http.MethodGet, path: GROUP_ID_GROUPS_URL, handle: (*WorkerHandler).HandleListSubgroups, tag: GROUP_TAG,
+			query: paginationQuery, status: http.StatusOK, response: ListSubgroupsResponse{}},
+
+		{method: http.MethodPost, path: GROUP_ID_GROUPS_ID_URL, handle: (*WorkerHandler).HandleAddSubgroup, tag: GROUP_TAG,
+			status: http.StatusNoContent},
+		{method: http.MethodDelete, path: GROUP_ID_GROUPS_ID_URL, handle: (*WorkerHandler).HandleRemoveSubgroup, tag: GROUP_TAG,
+			status: http.StatusNoContent},
+
+		// Special endpoint without organization URI for groups
+		{method: http.MethodGet, path: API_VERSION_1 + "/groups", handle: (*WorkerHandler).HandleListAllGroups, tag: GROUP_TAG,
+			query:  queryParams([]string{"Org", "PathPrefix", "Member", "AttachedPolicy", "Deleted", "NextToken"}, searchQuery, paginationQuery),
+			status: http.StatusOK, response: ListAllGroupsResponse{}},
+
+		// Policy api
+		{method: http.MethodGet, path: POLICY_ROOT_URL, handle: (*WorkerHandler).HandleListPolicies, tag: POLICY_TAG,
+			query:  queryParams([]string{"PathPrefix", "Action", "Resource", "Deleted", "NextToken"}, searchQuery, paginationQuery),
+			status: http.StatusOK, response: ListPoliciesResponse{}},
+		{method: http.MethodPost, path: POLICY_ROOT_URL, handle: (*WorkerHandler).HandleAddPolicy, tag: POLICY_TAG,
+			request: CreatePolicyRequest{}, status: http.StatusCreated, response: api.Policy{}},
+
+		{method: http.MethodDelete, path: POLICY_ID_URL, handle: (*WorkerHandler).HandleRemovePolicy, tag: POLICY_TAG,
+			status: http.StatusNoContent, ifMatch: true},
+
+		{method: http.MethodGet, path: POLICY_ID_URL, handle: (*WorkerHandler).HandleGetPolicyByName, tag: POLICY_TAG,
+			status: http.StatusOK, response: api.Policy{}, etag: true},
+		{method: http.MethodPut, path: POLICY_ID_URL, handle: (*WorkerHandler).HandleUpdatePolicy, tag: POLICY_TAG,
+			request: UpdatePolicyRequest{}, status: http.StatusOK, response: api.Policy{}, etag: true, ifMatch: true},
+		{method: http.MethodPatch, path: POLICY_ID_URL, handle: (*WorkerHandler).HandlePatchPolicy, tag: POLICY_TAG,
+			request: UpdatePolicyRequest{}, status: http.StatusOK, response: api.Policy{}, etag: true, ifMatch: true},
+
+		{method: http.MethodPost, path: POLICY_ID_RESTORE_URL, handle: (*WorkerHandler).HandleRestorePolicy, tag: POLICY_TAG,
+			status: http.StatusOK, response: api.Policy{}},
+
+		{method: http.MethodGet, path: POLICY_ID_GROUPS_URL, handle: (*WorkerHandler).HandleListAttachedGroups, tag: POLICY_TAG,
+			query: queryParams([]string{"NextToken"}, paginationQuery), status: http.StatusOK, response: ListAttachedGroupsResponse{}},
+
+		{method: http.MethodGet, path: POLICY_ID_VERSIONS_URL, handle: (*WorkerHandler).HandleListPolicyVersions, tag: POLICY_TAG,
+			query: paginationQuery, status: http.StatusOK, response: ListPolicyVersionsResponse{}},
+		{method: http.MethodGet, path: POLICY_ID_VERSIONS_ID_URL, handle: (*WorkerHandler).HandleGetPolicyVersion, tag: POLICY_TAG,
+			status: http.StatusOK, response: api.PolicyVersion{}},
+		{method: http.MethodGet, path: POLICY_ID_VERSIONS_DIFF_URL, handle: (*WorkerHandler).HandleDiffPolicyVersions, tag: POLICY_TAG,
+			query: []string{"From", "To"}, status: http.StatusOK, response: api.PolicyVersionDiff{}},
+		{method: http.MethodPut, path: POLICY_ID_DEFAULT_VERSION_URL, handle: (*WorkerHandler).HandleSetDefaultPolicyVersion, tag: POLICY_TAG,
+			request: SetDefaultPolicyVersionRequest{}, status: http.StatusOK, response: api.Policy{}, etag: true, ifMatch: true},
+
+		// Special endpoint without organization URI for policies
+		{method: http.MethodGet, path: API_VERSION_1 + "/policies", handle: (*WorkerHandler).HandleListAllPolicies, tag: POLICY_TAG,
+			query:  queryParams([]string{"Org", "PathPrefix", "Action", "Resource", "Deleted", "NextToken"}, searchQuery, paginationQuery),
+			status: http.StatusOK, response: ListAllPoliciesResponse{}},
+
+		// Proxy Resources api
+		{method: http.MethodGet, path: PROXY_RESOURCE_ROOT_URL, handle: (*WorkerHandler).HandleListProxyResource, tag: PROXY_RESOURCE_TAG,
+			query: queryParams([]string{"PathPrefix"}, searchQuery, paginationQuery), status: http.StatusOK, response: ListProxyResourcesResponse{}},
+		{method: http.MethodPost, path: PROXY_RESOURCE_ROOT_URL, handle: (*WorkerHandler).HandleAddProxyResource, tag: PROXY_RESOURCE_TAG,
+			request: CreateProxyResourceRequest{}, status: http.StatusCreated, response: api.ProxyResource{}},
+
+		{method: http.MethodDelete, path: PROXY_RESOURCE_ID_URL, handle: (*WorkerHandler).HandleRemoveProxyResource, tag: PROXY_RESOURCE_TAG,
+			status: http.StatusNoContent, ifMatch: true},
+
+		{method: http.MethodGet, path: PROXY_RESOURCE_ID_URL, handle: (*WorkerHandler).HandleGetProxyResourceByName, tag: PROXY_RESOURCE_TAG,
+			status: http.StatusOK, response: api.ProxyResource{}, etag: true},
+		{method: http.MethodPut, path: PROXY_RESOURCE_ID_URL, handle: (*WorkerHandler).HandleUpdateProxyResource, tag: PROXY_RESOURCE_TAG,
+			request: UpdateProxyResourceRequest{}, status: http.StatusOK, response: api.ProxyResource{}, etag: true, ifMatch: true},
+		{method: http.MethodPatch, path: PROXY_RESOURCE_ID_URL, handle: (*WorkerHandler).HandlePatchProxyResource, tag: PROXY_RESOURCE_TAG,
+			request: UpdateProxyResourceRequest{}, status: http.StatusOK, response: api.ProxyResource{}, etag: true, ifMatch: true},
+
+		// Resources authorized endpoint
+		{method: http.MethodPost, path: RESOURCE_URL, handle: (*WorkerHandler).HandleGetAuthorizedExternalResources, tag: AUTHORIZATION_TAG,
+			request: AuthorizeResourcesRequest{}, status: http.StatusOK, response: AuthorizeResourcesResponse{}},
+
+		// Kubernetes authorization webhook
+		{method: http.MethodPost, path: KUBERNETES_SUBJECT_ACCESS_REVIEW_URL, handle: (*WorkerHandler).HandleReviewKubernetesSubjectAccess, tag: AUTHORIZATION_TAG,
+			request: SubjectAccessReview{}, status: http.StatusOK, response: SubjectAccessReview{}},
+
+		// OIDC authentication api
+		{method: http.MethodGet, path: OIDC_AUTH_ROOT_URL, handle: (*WorkerHandler).HandleListOidcProviders, tag: OIDC_PROVIDER_TAG,
+			query: queryParams([]string{"PathPrefix"}, paginationQuery), status: http.StatusOK, response: ListOidcProvidersResponse{}},
+		{method: http.MethodPost, path: OIDC_AUTH_ROOT_URL, handle: (*WorkerHandler).HandleAddOidcProvider, tag: OIDC_PROVIDER_TAG,
+			request: CreateOidcProviderRequest{}, status: http.StatusCreated, response: api.OidcProvider{}},
+
+		{method: http.MethodDelete, path: OIDC_AUTH_ID_URL, handle: (*WorkerHandler).HandleRemoveOidcProvider, tag: OIDC_PROVIDER_TAG,
+			status: http.StatusNoContent},
+
+		{method: http.MethodGet, path: OIDC_AUTH_ID_URL, handle: (*WorkerHandler).HandleGetOidcProviderByName, tag: OIDC_PROVIDER_TAG,
+			status: http.StatusOK, response: api.OidcProvider{}},
+		{method: http.MethodPut, path: OIDC_AUTH_ID_URL, handle: (*WorkerHandler).HandleUpdateOidcProvider, tag: OIDC_PROVIDER_TAG,
+			request: UpdateOidcProviderRequest{}, status: http.StatusOK, response: api.OidcProvider{}},
+		{method: http.MethodPatch, path: OIDC_AUTH_ID_URL, handle: (*WorkerHandler).HandlePatchOidcProvider, tag: OIDC_PROVIDER_TAG,
+			request: UpdateOidcProviderRequest{}, status: http.StatusOK, response: api.OidcProvider{}},
+
+		// IAM state api
+		{method: http.MethodGet, path: STATE_EXPORT_URL, handle: (*WorkerHandler).HandleExportState, tag: STATE_TAG,
+			status: http.StatusOK, response: api.State{}, yaml: true},
+		{method: http.MethodPost, path: STATE_IMPORT_URL, handle: (*WorkerHandler).HandleImportState, tag: STATE_TAG,
+			query: []string{"Mode", "ValidateOnly"}, request: api.State{}, status: http.StatusOK, response: api.ImportResult{}, yaml: true},
+
+		// Webhook api
+		{method: http.MethodGet, path: WEBHOOK_ROOT_URL, handle: (*WorkerHandler).HandleListWebhooks, tag: WEBHOOK_TAG,
+			query: queryParams(searchQuery, paginationQuery), status: http.StatusOK, response: ListWebhooksResponse{}},
+		{method: http.MethodPost, path: WEBHOOK_ROOT_URL, handle: (*WorkerHandler).HandleAddWebhook, tag: WEBHOOK_TAG,
+			request: CreateWebhookRequest{}, status: http.StatusCreated, response: api.Webhook{}},
+
+		{method: http.MethodDelete, path: WEBHOOK_ID_URL, handle: (*WorkerHandler).HandleRemoveWebhook, tag: WEBHOOK_TAG,
+			status: http.StatusNoContent},
+
+		{method: http.MethodGet, path: WEBHOOK_ID_URL, handle: (*WorkerHandler).HandleGetWebhookByName, tag: WEBHOOK_TAG,
+			status: http.StatusOK, response: api.Webhook{}},
+		{method: http.MethodPut, path: WEBHOOK_ID_URL, handle: (*WorkerHandler).HandleUpdateWebhook, tag: WEBHOOK_TAG,
+			request: UpdateWebhookRequest{}, status: http.StatusOK, response: api.Webhook{}},
+
+		{method: http.MethodGet, path: WEBHOOK_ID_DELIVERIES_URL, handle: (*WorkerHandler).HandleListWebhookDeliveries, tag: WEBHOOK_TAG,
+			query: paginationQuery, status: http.StatusOK, response: ListWebhookDeliveriesResponse{}},
+
+		// Reconcile api
+		{method: http.MethodPost, path: RECONCILE_PLAN_URL, handle: (*WorkerHandler).HandleReconcilePlan, tag: STATE_TAG,
+			query: []string{"IgnoreUnmanaged"}, request: api.State{}, status: http.StatusOK, response: api.ReconcileResult{}, yaml: true},
+		{method: http.MethodPost, path: RECONCILE_APPLY_URL, handle: (*WorkerHandler).HandleReconcileApply, tag: STATE_TAG,
+			query: []string{"IgnoreUnmanaged"}, request: api.State{}, status: http.StatusOK, response: api.ReconcileResult{}, yaml: true},
+
+		// Current Foulkon configuration
+		{method: http.MethodGet, path: ABOUT, handle: (*WorkerHandler).HandleGetCurrentConfig, tag: ABOUT_TAG,
+			status: http.StatusOK, response: Config{}},
+
+		// OpenAPI document of these routes
+		{method: http.MethodGet, path: OPENAPI_URL, handle: (*WorkerHandler).HandleGetOpenAPIDocument, tag: ABOUT_TAG,
+			status: http.StatusOK, response: OpenAPIDocument{}},
+	}
 }
 
 // WorkerHandlerRouter returns http.Handler for the APIs.
@@ -156,76 +483,14 @@ func WorkerHandlerRouter(worker *foulkon.Worker) http.Handler {
 	// Create the muxer to handle the actual endpoints
 	router := httprouter.New()
 
-	workerHandler := WorkerHandler{worker: worker}
-
-	// User api
-	router.GET(USER_ROOT_URL, workerHandler.HandleListUsers)
-	router.POST(USER_ROOT_URL, workerHandler.HandleAddUser)
-
-	router.GET(USER_ID_URL, workerHandler.HandleGetUserByExternalID)
-	router.PUT(USER_ID_URL, workerHandler.HandleUpdateUser)
-	router.DELETE(USER_ID_URL, workerHandler.HandleRemoveUser)
-
-	router.GET(USER_ID_GROUPS_URL, workerHandler.HandleListGroupsByUser)
-
-	// Group api
-	router.POST(GROUP_ORG_ROOT_URL, workerHandler.HandleAddGroup)
-	router.GET(GROUP_ORG_ROOT_URL, workerHandler.HandleListGroups)
-
-	router.DELETE(GROUP_ID_URL, workerHandler.HandleRemoveGroup)
-	router.GET(GROUP_ID_URL, workerHandler.HandleGetGroupByName)
-	router.PUT(GROUP_ID_URL, workerHandler.HandleUpdateGroup)
-
-	router.GET(GROUP_ID_USERS_URL, workerHandler.HandleListMembers)
-
-	router.POST(GROUP_ID_USERS_ID_URL, workerHandler.HandleAddMember)
-	router.DELETE(GROUP_ID_USERS_ID_URL, workerHandler.HandleRemoveMember)
-
-	router.GET(GROUP_ID_POLICIES_URL, workerHandler.HandleListAttachedGroupPolicies)
-
-	router.POST(GROUP_ID_POLICIES_ID_URL, workerHandler.HandleAttachPolicyToGroup)
-	router.DELETE(GROUP_ID_POLICIES_ID_URL, workerHandler.HandleDetachPolicyToGroup)
-
-	// Special endpoint without organization URI for groups
-	router.GET(API_VERSION_1+"/groups", workerHandler.HandleListAllGroups)
-
-	// Policy api
-	router.GET(POLICY_ROOT_URL, workerHandler.HandleListPolicies)
-	router.POST(POLICY_ROOT_URL, workerHandler.HandleAddPolicy)
-
-	router.DELETE(POLICY_ID_URL, workerHandler.HandleRemovePolicy)
-
-	router.GET(POLICY_ID_URL, workerHandler.HandleGetPolicyByName)
-	router.PUT(POLICY_ID_URL, workerHandler.HandleUpdatePolicy)
-
-	router.GET(POLICY_ID_GROUPS_URL, workerHandler.HandleListAttachedGroups)
-
-	// Special endpoint without organization URI for policies
-	router.GET(API_VERSION_1+"/policies", workerHandler.HandleListAllPolicies)
-
-	// Proxy Resources api
-	router.GET(PROXY_RESOURCE_ROOT_URL, workerHandler.HandleListProxyResource)
-	router.POST(PROXY_RESOURCE_ROOT_URL, workerHandler.HandleAddProxyResource)
-
-	router.DELETE(PROXY_RESOURCE_ID_URL, workerHandler.HandleRemoveProxyResource)
+	workerHandler := &WorkerHandler{worker: worker}
 
-	router.GET(PROXY_RESOURCE_ID_URL, workerHandler.HandleGetProxyResourceByName)
-	router.PUT(PROXY_RESOURCE_ID_URL, workerHandler.HandleUpdateProxyResource)
-
-	// Resources authorized endpoint
-	router.POST(RESOURCE_URL, workerHandler.HandleGetAuthorizedExternalResources)
-
-	// OIDC authentication api
-	router.GET(OIDC_AUTH_ROOT_URL, workerHandler.HandleListOidcProviders)
-	router.POST(OIDC_AUTH_ROOT_URL, workerHandler.HandleAddOidcProvider)
-
-	router.DELETE(OIDC_AUTH_ID_URL, workerHandler.HandleRemoveOidcProvider)
-
-	router.GET(OIDC_AUTH_ID_URL, workerHandler.HandleGetOidcProviderByName)
-	router.PUT(OIDC_AUTH_ID_URL, workerHandler.HandleUpdateOidcProvider)
-
-	// Current Foulkon configuration
-	router.GET(ABOUT, workerHandler.HandleGetCurrentConfig)
+	for _, rt := range workerRoutes() {
+		handle := rt.handle
+		router.Handle(rt.method, rt.path, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			handle(workerHandler, w, r, ps)
+		})
+	}
 
 	return workerHandler.worker.MiddlewareHandler.Handle(router)
 }
@@ -283,6 +548,50 @@ func getFilterData(r *http.Request, ps httprouter.Params) (*api.Filter, error) {
 		}
 	}
 
+	// Retrieve Transitive
+	var transitive bool
+	trans := r.URL.Query().Get("Transitive")
+	if len(trans) != 0 {
+		transitive, err = strconv.ParseBool(trans)
+		if err != nil {
+			return nil, &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: Transitive %v", trans),
+			}
+		}
+	}
+
+	// Retrieve Deleted
+	var deleted bool
+	del := r.URL.Query().Get("Deleted")
+	if len(del) != 0 {
+		deleted, err = strconv.ParseBool(del)
+		if err != nil {
+			return nil, &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: Deleted %v", del),
+			}
+		}
+	}
+
+	// Retrieve date ranges
+	createdAfter, err := getDateParam(r, "CreatedAfter")
+	if err != nil {
+		return nil, err
+	}
+	createdBefore, err := getDateParam(r, "CreatedBefore")
+	if err != nil {
+		return nil, err
+	}
+	updatedAfter, err := getDateParam(r, "UpdatedAfter")
+	if err != nil {
+		return nil, err
+	}
+	updatedBefore, err := getDateParam(r, "UpdatedBefore")
+	if err != nil {
+		return nil, err
+	}
+
 	// Retrieve Org
 	var org string
 	if org = ps.ByName(ORG_NAME); len(org) == 0 {
@@ -297,8 +606,57 @@ func getFilterData(r *http.Request, ps httprouter.Params) (*api.Filter, error) {
 		GroupName:         ps.ByName(GROUP_NAME),
 		ProxyResourceName: ps.ByName(PROXY_RESOURCE_NAME),
 		AuthProviderName:  ps.ByName(AUTH_PROVIDER_NAME),
+		WebhookName:       ps.ByName(WEBHOOK_NAME),
+		NamePrefix:        r.URL.Query().Get("NamePrefix"),
+		NameContains:      r.URL.Query().Get("NameContains"),
+		CreatedAfter:      createdAfter,
+		CreatedBefore:     createdBefore,
+		UpdatedAfter:      updatedAfter,
+		UpdatedBefore:     updatedBefore,
+		Member:            r.URL.Query().Get("Member"),
+		AttachedPolicy:    r.URL.Query().Get("AttachedPolicy"),
+		Action:            r.URL.Query().Get("Action"),
+		Resource:          r.URL.Query().Get("Resource"),
 		Offset:            offset,
 		Limit:             limit,
+		NextToken:         r.URL.Query().Get("NextToken"),
 		OrderBy:           r.URL.Query().Get("OrderBy"),
+		Transitive:        transitive,
+		Deleted:           deleted,
 	}, nil
 }
+
+// Retrieve a RFC3339 date from query parameter, zero time if it isn't set
+func getDateParam(r *http.Request, param string) (time.Time, error) {
+	value := r.URL.Query().Get(param)
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: %v %v", param, value),
+		}
+	}
+	return date.UTC(), nil
+}
+
+// Set ETag header with the revision of the entity in response
+func setETag(w http.ResponseWriter, revision int) {
+	w.Header().Set(ETAG_HEADER, fmt.Sprintf("\"%v\"", revision))
+}
+
+// Retrieve the revision required by If-Match header, 0 if any revision is accepted. Values that
+// aren't an ETag of a revision are returned as -1, so they never match.
+func getIfMatchRevision(r *http.Request) int {
+	ifMatch := strings.TrimSpace(r.Header.Get(IF_MATCH_HEADER))
+	if len(ifMatch) == 0 || ifMatch == "*" {
+		return 0
+	}
+	revision, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(ifMatch, "\""), "\""))
+	if err != nil || revision < 1 || !strings.HasPrefix(ifMatch, "\"") || !strings.HasSuffix(ifMatch, "\"") {
+		return -1
+	}
+	return revision
+}
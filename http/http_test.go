@@ -22,33 +22,48 @@ import (
 
 const (
 	// USER API METHODS
-	AddUserMethod             = "AddUser"
-	GetUserByExternalIdMethod = "GetUserByExternalId"
-	ListUsersMethod           = "ListUsers"
-	UpdateUserMethod          = "UpdateUser"
-	RemoveUserMethod          = "RemoveUser"
-	ListGroupsByUserMethod    = "ListGroupsByUser"
+	AddUserMethod                  = "AddUser"
+	GetUserByExternalIdMethod      = "GetUserByExternalId"
+	ListUsersMethod                = "ListUsers"
+	UpdateUserMethod               = "UpdateUser"
+	RemoveUserMethod               = "RemoveUser"
+	RestoreUserMethod              = "RestoreUser"
+	ListGroupsByUserMethod         = "ListGroupsByUser"
+	AttachPolicyToUserMethod       = "AttachPolicyToUser"
+	DetachPolicyFromUserMethod     = "DetachPolicyFromUser"
+	ListAttachedUserPoliciesMethod = "ListAttachedUserPolicies"
 
 	// GROUP API METHODS
-	AddGroupMethod                  = "AddGroup"
-	GetGroupByNameMethod            = "GetGroupByName"
-	ListGroupsMethod                = "ListGroups"
-	UpdateGroupMethod               = "UpdateGroup"
-	RemoveGroupMethod               = "RemoveGroup"
-	AddMemberMethod                 = "AddMember"
-	RemoveMemberMethod              = "RemoveMember"
-	ListMembersMethod               = "ListMembers"
-	AttachPolicyToGroupMethod       = "AttachPolicyToGroup"
-	DetachPolicyToGroupMethod       = "DetachPolicyToGroup"
-	ListAttachedGroupPoliciesMethod = "ListAttachedGroupPolicies"
+	AddGroupMethod                    = "AddGroup"
+	GetGroupByNameMethod              = "GetGroupByName"
+	ListGroupsMethod                  = "ListGroups"
+	UpdateGroupMethod                 = "UpdateGroup"
+	RemoveGroupMethod                 = "RemoveGroup"
+	RestoreGroupMethod                = "RestoreGroup"
+	AddMemberMethod                   = "AddMember"
+	RemoveMemberMethod                = "RemoveMember"
+	ListMembersMethod                 = "ListMembers"
+	AttachPolicyToGroupMethod         = "AttachPolicyToGroup"
+	DetachPolicyToGroupMethod         = "DetachPolicyToGroup"
+	ListAttachedGroupPoliciesMethod   = "ListAttachedGroupPolicies"
+	AddSubgroupMethod                 = "AddSubgroup"
+	RemoveSubgroupMethod              = "RemoveSubgroup"
+	ListSubgroupsMethod               = "ListSubgroups"
+	UpdateMembersMethod               = "UpdateMembers"
+	UpdateAttachedGroupPoliciesMethod = "UpdateAttachedGroupPolicies"
 
 	// POLICY API METHODS
-	AddPolicyMethod          = "AddPolicy"
-	GetPolicyByNameMethod    = "GetPolicyByName"
-	ListPoliciesMethod       = "ListPolicies"
-	UpdatePolicyMethod       = "UpdatePolicy"
-	RemovePolicyMethod       = "RemovePolicy"
-	ListAttachedGroupsMethod = "ListAttachedGroups"
+	AddPolicyMethod               = "AddPolicy"
+	GetPolicyByNameMethod         = "GetPolicyByName"
+	ListPoliciesMethod            = "ListPolicies"
+	UpdatePolicyMethod            = "UpdatePolicy"
+	RemovePolicyMethod            = "RemovePolicy"
+	RestorePolicyMethod           = "RestorePolicy"
+	ListAttachedGroupsMethod      = "ListAttachedGroups"
+	ListPolicyVersionsMethod      = "ListPolicyVersions"
+	GetPolicyVersionMethod        = "GetPolicyVersion"
+	DiffPolicyVersionsMethod      = "DiffPolicyVersions"
+	SetDefaultPolicyVersionMethod = "SetDefaultPolicyVersion"
 
 	// AUTHZ API
 	GetAuthorizedUsersMethod             = "GetAuthorizedUsers"
@@ -56,6 +71,7 @@ const (
 	GetAuthorizedPoliciesMethod          = "GetAuthorizedPolicies"
 	GetAuthorizedExternalResourcesMethod = "GetAuthorizedExternalResources"
 	GetAuthorizedProxyResources          = "GetAuthorizedProxyResources"
+	ReviewSubjectAccessMethod            = "ReviewSubjectAccess"
 
 	// PROXY API
 	AddProxyResourceMethod       = "AddProxyResource"
@@ -71,6 +87,25 @@ const (
 	ListOidcProvidersMethod     = "ListOidcProviders"
 	UpdateOidcProviderMethod    = "UpdateOidcProvider"
 	RemoveOidcProviderMethod    = "RemoveOidcProvider"
+
+	AddOrganizationMethod       = "AddOrganization"
+	GetOrganizationByNameMethod = "GetOrganizationByName"
+	ListOrganizationsMethod     = "ListOrganizations"
+	UpdateOrganizationMethod    = "UpdateOrganization"
+	RemoveOrganizationMethod    = "RemoveOrganization"
+
+	// WEBHOOK API METHODS
+	AddWebhookMethod            = "AddWebhook"
+	GetWebhookByNameMethod      = "GetWebhookByName"
+	ListWebhooksMethod          = "ListWebhooks"
+	UpdateWebhookMethod         = "UpdateWebhook"
+	RemoveWebhookMethod         = "RemoveWebhook"
+	ListWebhookDeliveriesMethod = "ListWebhookDeliveries"
+
+	// STATE API METHODS
+	ExportStateMethod    = "ExportState"
+	ImportStateMethod    = "ImportState"
+	ReconcileStateMethod = "ReconcileState"
 )
 
 // Test server used to test handlers
@@ -182,7 +217,18 @@ func TestMain(m *testing.M) {
 				},
 			},
 		},
-		Version: "test",
+		DeletionRetention:        720 * time.Hour,
+		PurgeInterval:            time.Hour,
+		WebhookDeliveryInterval:  5 * time.Second,
+		WebhookMaxAttempts:       8,
+		WebhookBackoff:           30 * time.Second,
+		WebhookTimeout:           10 * time.Second,
+		WebhookRetention:         168 * time.Hour,
+		KubernetesAction:         "k8s:{verb}",
+		KubernetesResourceUrn:    "urn:k8s:cluster::{namespace}/{apiGroup}/{resource}/{subresource}/{name}",
+		KubernetesNonResourceUrn: "urn:k8s:cluster::nonresource/{path}",
+		KubernetesOrg:            "example",
+		Version:                  "test",
 	}
 
 	// Return created core
@@ -194,6 +240,9 @@ func TestMain(m *testing.M) {
 		AuthzApi:          testApi,
 		ProxyApi:          testApi,
 		AuthOidcAPI:       testApi,
+		OrganizationApi:   testApi,
+		WebhookApi:        testApi,
+		StateApi:          testApi,
 		Config:            config,
 	}
 
@@ -226,31 +275,47 @@ func makeTestApi() *TestAPI {
 	testApi.ArgsIn[ListUsersMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[UpdateUserMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[RemoveUserMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[RestoreUserMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[ListGroupsByUserMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[AttachPolicyToUserMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[DetachPolicyFromUserMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[ListAttachedUserPoliciesMethod] = make([]interface{}, 2)
 
 	testApi.ArgsIn[AddGroupMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[GetGroupByNameMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[ListGroupsMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[UpdateGroupMethod] = make([]interface{}, 5)
 	testApi.ArgsIn[RemoveGroupMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[RestoreGroupMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[AddMemberMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[RemoveMemberMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[ListMembersMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[AttachPolicyToGroupMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[DetachPolicyToGroupMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[ListAttachedGroupPoliciesMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[AddSubgroupMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[RemoveSubgroupMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[ListSubgroupsMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[UpdateMembersMethod] = make([]interface{}, 5)
+	testApi.ArgsIn[UpdateAttachedGroupPoliciesMethod] = make([]interface{}, 5)
 
 	testApi.ArgsIn[AddPolicyMethod] = make([]interface{}, 5)
 	testApi.ArgsIn[GetPolicyByNameMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[ListPoliciesMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[UpdatePolicyMethod] = make([]interface{}, 6)
 	testApi.ArgsIn[RemovePolicyMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[RestorePolicyMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[ListAttachedGroupsMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[ListPolicyVersionsMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[GetPolicyVersionMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[DiffPolicyVersionsMethod] = make([]interface{}, 5)
+	testApi.ArgsIn[SetDefaultPolicyVersionMethod] = make([]interface{}, 4)
 
 	testApi.ArgsIn[GetAuthorizedUsersMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[GetAuthorizedGroupsMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[GetAuthorizedPoliciesMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[GetAuthorizedExternalResourcesMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[ReviewSubjectAccessMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[GetAuthorizedProxyResources] = make([]interface{}, 4)
 
 	testApi.ArgsIn[AddProxyResourceMethod] = make([]interface{}, 5)
@@ -266,36 +331,69 @@ func makeTestApi() *TestAPI {
 	testApi.ArgsIn[UpdateOidcProviderMethod] = make([]interface{}, 6)
 	testApi.ArgsIn[RemoveOidcProviderMethod] = make([]interface{}, 2)
 
+	testApi.ArgsIn[AddOrganizationMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[GetOrganizationByNameMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[ListOrganizationsMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[UpdateOrganizationMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[RemoveOrganizationMethod] = make([]interface{}, 2)
+
+	testApi.ArgsIn[AddWebhookMethod] = make([]interface{}, 5)
+	testApi.ArgsIn[GetWebhookByNameMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[ListWebhooksMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[UpdateWebhookMethod] = make([]interface{}, 6)
+	testApi.ArgsIn[RemoveWebhookMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[ListWebhookDeliveriesMethod] = make([]interface{}, 2)
+
+	testApi.ArgsIn[ExportStateMethod] = make([]interface{}, 1)
+	testApi.ArgsIn[ImportStateMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[ReconcileStateMethod] = make([]interface{}, 4)
+
 	testApi.ArgsOut[AddUserMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetUserByExternalIdMethod] = make([]interface{}, 2)
-	testApi.ArgsOut[ListUsersMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListUsersMethod] = make([]interface{}, 4)
 	testApi.ArgsOut[UpdateUserMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveUserMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[RestoreUserMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[ListGroupsByUserMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[AttachPolicyToUserMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[DetachPolicyFromUserMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[ListAttachedUserPoliciesMethod] = make([]interface{}, 3)
 
 	testApi.ArgsOut[AddGroupMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetGroupByNameMethod] = make([]interface{}, 2)
-	testApi.ArgsOut[ListGroupsMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListGroupsMethod] = make([]interface{}, 4)
 	testApi.ArgsOut[UpdateGroupMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveGroupMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[RestoreGroupMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[AddMemberMethod] = make([]interface{}, 1)
 	testApi.ArgsOut[RemoveMemberMethod] = make([]interface{}, 1)
-	testApi.ArgsOut[ListMembersMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListMembersMethod] = make([]interface{}, 4)
 	testApi.ArgsOut[AttachPolicyToGroupMethod] = make([]interface{}, 1)
 	testApi.ArgsOut[DetachPolicyToGroupMethod] = make([]interface{}, 1)
 	testApi.ArgsOut[ListAttachedGroupPoliciesMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[AddSubgroupMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[RemoveSubgroupMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[ListSubgroupsMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[UpdateMembersMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[UpdateAttachedGroupPoliciesMethod] = make([]interface{}, 2)
 
 	testApi.ArgsOut[AddPolicyMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetPolicyByNameMethod] = make([]interface{}, 2)
-	testApi.ArgsOut[ListPoliciesMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListPoliciesMethod] = make([]interface{}, 4)
 	testApi.ArgsOut[UpdatePolicyMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemovePolicyMethod] = make([]interface{}, 1)
-	testApi.ArgsOut[ListAttachedGroupsMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[RestorePolicyMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ListAttachedGroupsMethod] = make([]interface{}, 4)
+	testApi.ArgsOut[ListPolicyVersionsMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[GetPolicyVersionMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[DiffPolicyVersionsMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[SetDefaultPolicyVersionMethod] = make([]interface{}, 2)
 
 	testApi.ArgsOut[GetAuthorizedUsersMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetAuthorizedGroupsMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetAuthorizedPoliciesMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetAuthorizedExternalResourcesMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ReviewSubjectAccessMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetAuthorizedProxyResources] = make([]interface{}, 2)
 
 	testApi.ArgsOut[AddProxyResourceMethod] = make([]interface{}, 2)
@@ -303,7 +401,7 @@ func makeTestApi() *TestAPI {
 	testApi.ArgsOut[GetProxyResourcesMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[UpdateProxyResourceMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveProxyResourceMethod] = make([]interface{}, 1)
-	testApi.ArgsOut[ListProxyResourcesMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListProxyResourcesMethod] = make([]interface{}, 4)
 
 	testApi.ArgsOut[AddOidcProviderMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetOidcProviderByNameMethod] = make([]interface{}, 2)
@@ -311,6 +409,23 @@ func makeTestApi() *TestAPI {
 	testApi.ArgsOut[UpdateOidcProviderMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveOidcProviderMethod] = make([]interface{}, 1)
 
+	testApi.ArgsOut[AddOrganizationMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[GetOrganizationByNameMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ListOrganizationsMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[UpdateOrganizationMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[RemoveOrganizationMethod] = make([]interface{}, 1)
+
+	testApi.ArgsOut[AddWebhookMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[GetWebhookByNameMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ListWebhooksMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[UpdateWebhookMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[RemoveWebhookMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[ListWebhookDeliveriesMethod] = make([]interface{}, 3)
+
+	testApi.ArgsOut[ExportStateMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ImportStateMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ReconcileStateMethod] = make([]interface{}, 2)
+
 	return testApi
 }
 
@@ -345,7 +460,7 @@ func (t TestAPI) GetUserByExternalID(authenticatedUser api.RequestInfo, id strin
 	return user, err
 }
 
-func (t TestAPI) ListUsers(authenticatedUser api.RequestInfo, filter *api.Filter) ([]string, int, error) {
+func (t TestAPI) ListUsers(authenticatedUser api.RequestInfo, filter *api.Filter) ([]string, int, string, error) {
 	t.ArgsIn[ListUsersMethod][0] = authenticatedUser
 	t.ArgsIn[ListUsersMethod][1] = filter
 	var externalIDs []string
@@ -356,11 +471,15 @@ func (t TestAPI) ListUsers(authenticatedUser api.RequestInfo, filter *api.Filter
 	if t.ArgsOut[ListUsersMethod][1] != nil {
 		total = t.ArgsOut[ListUsersMethod][1].(int)
 	}
-	var err error
+	var nextToken string
 	if t.ArgsOut[ListUsersMethod][2] != nil {
-		err = t.ArgsOut[ListUsersMethod][2].(error)
+		nextToken = t.ArgsOut[ListUsersMethod][2].(string)
+	}
+	var err error
+	if t.ArgsOut[ListUsersMethod][3] != nil {
+		err = t.ArgsOut[ListUsersMethod][3].(error)
 	}
-	return externalIDs, total, err
+	return externalIDs, total, nextToken, err
 }
 
 func (t TestAPI) UpdateUser(authenticatedUser api.RequestInfo, externalID string, newPath string) (*api.User, error) {
@@ -388,6 +507,20 @@ func (t TestAPI) RemoveUser(authenticatedUser api.RequestInfo, id string) error
 	return err
 }
 
+func (t TestAPI) RestoreUser(authenticatedUser api.RequestInfo, id string) (*api.User, error) {
+	t.ArgsIn[RestoreUserMethod][0] = authenticatedUser
+	t.ArgsIn[RestoreUserMethod][1] = id
+	var user *api.User
+	if t.ArgsOut[RestoreUserMethod][0] != nil {
+		user = t.ArgsOut[RestoreUserMethod][0].(*api.User)
+	}
+	var err error
+	if t.ArgsOut[RestoreUserMethod][1] != nil {
+		err = t.ArgsOut[RestoreUserMethod][1].(error)
+	}
+	return user, err
+}
+
 func (t TestAPI) ListGroupsByUser(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.UserGroups, int, error) {
 	t.ArgsIn[ListGroupsByUserMethod][0] = authenticatedUser
 	t.ArgsIn[ListGroupsByUserMethod][1] = filter
@@ -407,6 +540,48 @@ func (t TestAPI) ListGroupsByUser(authenticatedUser api.RequestInfo, filter *api
 	return groups, total, err
 }
 
+func (t TestAPI) AttachPolicyToUser(authenticatedUser api.RequestInfo, externalId string, org string, policyName string) error {
+	t.ArgsIn[AttachPolicyToUserMethod][0] = authenticatedUser
+	t.ArgsIn[AttachPolicyToUserMethod][1] = externalId
+	t.ArgsIn[AttachPolicyToUserMethod][2] = org
+	t.ArgsIn[AttachPolicyToUserMethod][3] = policyName
+	var err error
+	if t.ArgsOut[AttachPolicyToUserMethod][0] != nil {
+		err = t.ArgsOut[AttachPolicyToUserMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestAPI) DetachPolicyFromUser(authenticatedUser api.RequestInfo, externalId string, org string, policyName string) error {
+	t.ArgsIn[DetachPolicyFromUserMethod][0] = authenticatedUser
+	t.ArgsIn[DetachPolicyFromUserMethod][1] = externalId
+	t.ArgsIn[DetachPolicyFromUserMethod][2] = org
+	t.ArgsIn[DetachPolicyFromUserMethod][3] = policyName
+	var err error
+	if t.ArgsOut[DetachPolicyFromUserMethod][0] != nil {
+		err = t.ArgsOut[DetachPolicyFromUserMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestAPI) ListAttachedUserPolicies(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.UserPolicies, int, error) {
+	t.ArgsIn[ListAttachedUserPoliciesMethod][0] = authenticatedUser
+	t.ArgsIn[ListAttachedUserPoliciesMethod][1] = filter
+	var policies []api.UserPolicies
+	if t.ArgsOut[ListAttachedUserPoliciesMethod][0] != nil {
+		policies = t.ArgsOut[ListAttachedUserPoliciesMethod][0].([]api.UserPolicies)
+	}
+	var total int
+	if t.ArgsOut[ListAttachedUserPoliciesMethod][1] != nil {
+		total = t.ArgsOut[ListAttachedUserPoliciesMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[ListAttachedUserPoliciesMethod][2] != nil {
+		err = t.ArgsOut[ListAttachedUserPoliciesMethod][2].(error)
+	}
+	return policies, total, err
+}
+
 // GROUP API
 
 func (t TestAPI) AddGroup(authenticatedUser api.RequestInfo, org string, name string, path string) (*api.Group, error) {
@@ -440,7 +615,7 @@ func (t TestAPI) GetGroupByName(authenticatedUser api.RequestInfo, org string, n
 	return group, err
 }
 
-func (t TestAPI) ListGroups(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.GroupIdentity, int, error) {
+func (t TestAPI) ListGroups(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.GroupIdentity, int, string, error) {
 	t.ArgsIn[ListGroupsMethod][0] = authenticatedUser
 	t.ArgsIn[ListGroupsMethod][1] = filter
 
@@ -452,11 +627,15 @@ func (t TestAPI) ListGroups(authenticatedUser api.RequestInfo, filter *api.Filte
 	if t.ArgsOut[ListGroupsMethod][0] != nil {
 		groups = t.ArgsOut[ListGroupsMethod][0].([]api.GroupIdentity)
 	}
-	var err error
+	var nextToken string
 	if t.ArgsOut[ListGroupsMethod][2] != nil {
-		err = t.ArgsOut[ListGroupsMethod][2].(error)
+		nextToken = t.ArgsOut[ListGroupsMethod][2].(string)
 	}
-	return groups, total, err
+	var err error
+	if t.ArgsOut[ListGroupsMethod][3] != nil {
+		err = t.ArgsOut[ListGroupsMethod][3].(error)
+	}
+	return groups, total, nextToken, err
 }
 
 func (t TestAPI) UpdateGroup(authenticatedUser api.RequestInfo, org string, groupName string, newName string, newPath string) (*api.Group, error) {
@@ -487,6 +666,21 @@ func (t TestAPI) RemoveGroup(authenticatedUser api.RequestInfo, org string, name
 	return err
 }
 
+func (t TestAPI) RestoreGroup(authenticatedUser api.RequestInfo, org string, name string) (*api.Group, error) {
+	t.ArgsIn[RestoreGroupMethod][0] = authenticatedUser
+	t.ArgsIn[RestoreGroupMethod][1] = org
+	t.ArgsIn[RestoreGroupMethod][2] = name
+	var group *api.Group
+	if t.ArgsOut[RestoreGroupMethod][0] != nil {
+		group = t.ArgsOut[RestoreGroupMethod][0].(*api.Group)
+	}
+	var err error
+	if t.ArgsOut[RestoreGroupMethod][1] != nil {
+		err = t.ArgsOut[RestoreGroupMethod][1].(error)
+	}
+	return group, err
+}
+
 func (t TestAPI) AddMember(authenticatedUser api.RequestInfo, userID string, groupName string, org string) error {
 	t.ArgsIn[AddMemberMethod][0] = authenticatedUser
 	t.ArgsIn[AddMemberMethod][1] = userID
@@ -511,7 +705,7 @@ func (t TestAPI) RemoveMember(authenticatedUser api.RequestInfo, userID string,
 	return err
 }
 
-func (t TestAPI) ListMembers(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.GroupMembers, int, error) {
+func (t TestAPI) ListMembers(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.GroupMembers, int, string, error) {
 	t.ArgsIn[ListMembersMethod][0] = authenticatedUser
 	t.ArgsIn[ListMembersMethod][1] = filter
 
@@ -523,11 +717,15 @@ func (t TestAPI) ListMembers(authenticatedUser api.RequestInfo, filter *api.Filt
 	if t.ArgsOut[ListMembersMethod][0] != nil {
 		externalIDs = t.ArgsOut[ListMembersMethod][0].([]api.GroupMembers)
 	}
-	var err error
+	var nextToken string
 	if t.ArgsOut[ListMembersMethod][2] != nil {
-		err = t.ArgsOut[ListMembersMethod][2].(error)
+		nextToken = t.ArgsOut[ListMembersMethod][2].(string)
+	}
+	var err error
+	if t.ArgsOut[ListMembersMethod][3] != nil {
+		err = t.ArgsOut[ListMembersMethod][3].(error)
 	}
-	return externalIDs, total, err
+	return externalIDs, total, nextToken, err
 }
 
 func (t TestAPI) AttachPolicyToGroup(authenticatedUser api.RequestInfo, org string, groupName string, policyName string) error {
@@ -573,6 +771,85 @@ func (t TestAPI) ListAttachedGroupPolicies(authenticatedUser api.RequestInfo, fi
 	return policies, total, err
 }
 
+func (t TestAPI) UpdateMembers(authenticatedUser api.RequestInfo, org string, groupName string, add []string, remove []string) (*api.GroupBulkResult, error) {
+	t.ArgsIn[UpdateMembersMethod][0] = authenticatedUser
+	t.ArgsIn[UpdateMembersMethod][1] = org
+	t.ArgsIn[UpdateMembersMethod][2] = groupName
+	t.ArgsIn[UpdateMembersMethod][3] = add
+	t.ArgsIn[UpdateMembersMethod][4] = remove
+
+	var result *api.GroupBulkResult
+	if t.ArgsOut[UpdateMembersMethod][0] != nil {
+		result = t.ArgsOut[UpdateMembersMethod][0].(*api.GroupBulkResult)
+	}
+	var err error
+	if t.ArgsOut[UpdateMembersMethod][1] != nil {
+		err = t.ArgsOut[UpdateMembersMethod][1].(error)
+	}
+	return result, err
+}
+
+func (t TestAPI) UpdateAttachedGroupPolicies(authenticatedUser api.RequestInfo, org string, groupName string, attach []string, detach []string) (*api.GroupBulkResult, error) {
+	t.ArgsIn[UpdateAttachedGroupPoliciesMethod][0] = authenticatedUser
+	t.ArgsIn[UpdateAttachedGroupPoliciesMethod][1] = org
+	t.ArgsIn[UpdateAttachedGroupPoliciesMethod][2] = groupName
+	t.ArgsIn[UpdateAttachedGroupPoliciesMethod][3] = attach
+	t.ArgsIn[UpdateAttachedGroupPoliciesMethod][4] = detach
+
+	var result *api.GroupBulkResult
+	if t.ArgsOut[UpdateAttachedGroupPoliciesMethod][0] != nil {
+		result = t.ArgsOut[UpdateAttachedGroupPoliciesMethod][0].(*api.GroupBulkResult)
+	}
+	var err error
+	if t.ArgsOut[UpdateAttachedGroupPoliciesMethod][1] != nil {
+		err = t.ArgsOut[UpdateAttachedGroupPoliciesMethod][1].(error)
+	}
+	return result, err
+}
+
+func (t TestAPI) AddSubgroup(authenticatedUser api.RequestInfo, org string, groupName string, subgroupName string) error {
+	t.ArgsIn[AddSubgroupMethod][0] = authenticatedUser
+	t.ArgsIn[AddSubgroupMethod][1] = org
+	t.ArgsIn[AddSubgroupMethod][2] = groupName
+	t.ArgsIn[AddSubgroupMethod][3] = subgroupName
+	var err error
+	if t.ArgsOut[AddSubgroupMethod][0] != nil {
+		err = t.ArgsOut[AddSubgroupMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestAPI) RemoveSubgroup(authenticatedUser api.RequestInfo, org string, groupName string, subgroupName string) error {
+	t.ArgsIn[RemoveSubgroupMethod][0] = authenticatedUser
+	t.ArgsIn[RemoveSubgroupMethod][1] = org
+	t.ArgsIn[RemoveSubgroupMethod][2] = groupName
+	t.ArgsIn[RemoveSubgroupMethod][3] = subgroupName
+	var err error
+	if t.ArgsOut[RemoveSubgroupMethod][0] != nil {
+		err = t.ArgsOut[RemoveSubgroupMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestAPI) ListSubgroups(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.GroupSubgroups, int, error) {
+	t.ArgsIn[ListSubgroupsMethod][0] = authenticatedUser
+	t.ArgsIn[ListSubgroupsMethod][1] = filter
+
+	var subgroups []api.GroupSubgroups
+	var total int
+	if t.ArgsOut[ListSubgroupsMethod][1] != nil {
+		total = t.ArgsOut[ListSubgroupsMethod][1].(int)
+	}
+	if t.ArgsOut[ListSubgroupsMethod][0] != nil {
+		subgroups = t.ArgsOut[ListSubgroupsMethod][0].([]api.GroupSubgroups)
+	}
+	var err error
+	if t.ArgsOut[ListSubgroupsMethod][2] != nil {
+		err = t.ArgsOut[ListSubgroupsMethod][2].(error)
+	}
+	return subgroups, total, err
+}
+
 // POLICY API
 
 func (t TestAPI) AddPolicy(authenticatedUser api.RequestInfo, name string, path string, org string, statements []api.Statement) (*api.Policy, error) {
@@ -607,7 +884,7 @@ func (t TestAPI) GetPolicyByName(authenticatedUser api.RequestInfo, org string,
 	return policy, err
 }
 
-func (t TestAPI) ListPolicies(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.PolicyIdentity, int, error) {
+func (t TestAPI) ListPolicies(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.PolicyIdentity, int, string, error) {
 	t.ArgsIn[ListPoliciesMethod][0] = authenticatedUser
 	t.ArgsIn[ListPoliciesMethod][1] = filter
 
@@ -619,11 +896,15 @@ func (t TestAPI) ListPolicies(authenticatedUser api.RequestInfo, filter *api.Fil
 	if t.ArgsOut[ListPoliciesMethod][0] != nil {
 		policies = t.ArgsOut[ListPoliciesMethod][0].([]api.PolicyIdentity)
 	}
-	var err error
+	var nextToken string
 	if t.ArgsOut[ListPoliciesMethod][2] != nil {
-		err = t.ArgsOut[ListPoliciesMethod][2].(error)
+		nextToken = t.ArgsOut[ListPoliciesMethod][2].(string)
 	}
-	return policies, total, err
+	var err error
+	if t.ArgsOut[ListPoliciesMethod][3] != nil {
+		err = t.ArgsOut[ListPoliciesMethod][3].(error)
+	}
+	return policies, total, nextToken, err
 }
 
 func (t TestAPI) UpdatePolicy(authenticatedUser api.RequestInfo, org string, policyName string, newName string, newPath string,
@@ -657,7 +938,22 @@ func (t TestAPI) RemovePolicy(authenticatedUser api.RequestInfo, org string, nam
 	return err
 }
 
-func (t TestAPI) ListAttachedGroups(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.PolicyGroups, int, error) {
+func (t TestAPI) RestorePolicy(authenticatedUser api.RequestInfo, org string, name string) (*api.Policy, error) {
+	t.ArgsIn[RestorePolicyMethod][0] = authenticatedUser
+	t.ArgsIn[RestorePolicyMethod][1] = org
+	t.ArgsIn[RestorePolicyMethod][2] = name
+	var policy *api.Policy
+	if t.ArgsOut[RestorePolicyMethod][0] != nil {
+		policy = t.ArgsOut[RestorePolicyMethod][0].(*api.Policy)
+	}
+	var err error
+	if t.ArgsOut[RestorePolicyMethod][1] != nil {
+		err = t.ArgsOut[RestorePolicyMethod][1].(error)
+	}
+	return policy, err
+}
+
+func (t TestAPI) ListAttachedGroups(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.PolicyGroups, int, string, error) {
 	t.ArgsIn[ListAttachedGroupsMethod][0] = authenticatedUser
 	t.ArgsIn[ListAttachedGroupsMethod][1] = filter
 
@@ -669,11 +965,87 @@ func (t TestAPI) ListAttachedGroups(authenticatedUser api.RequestInfo, filter *a
 	if t.ArgsOut[ListAttachedGroupsMethod][0] != nil {
 		groups = t.ArgsOut[ListAttachedGroupsMethod][0].([]api.PolicyGroups)
 	}
-	var err error
+	var nextToken string
 	if t.ArgsOut[ListAttachedGroupsMethod][2] != nil {
-		err = t.ArgsOut[ListAttachedGroupsMethod][2].(error)
+		nextToken = t.ArgsOut[ListAttachedGroupsMethod][2].(string)
 	}
-	return groups, total, err
+	var err error
+	if t.ArgsOut[ListAttachedGroupsMethod][3] != nil {
+		err = t.ArgsOut[ListAttachedGroupsMethod][3].(error)
+	}
+	return groups, total, nextToken, err
+}
+
+func (t TestAPI) ListPolicyVersions(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.PolicyVersion, int, error) {
+	t.ArgsIn[ListPolicyVersionsMethod][0] = authenticatedUser
+	t.ArgsIn[ListPolicyVersionsMethod][1] = filter
+
+	var versions []api.PolicyVersion
+	var total int
+	if t.ArgsOut[ListPolicyVersionsMethod][1] != nil {
+		total = t.ArgsOut[ListPolicyVersionsMethod][1].(int)
+	}
+	if t.ArgsOut[ListPolicyVersionsMethod][0] != nil {
+		versions = t.ArgsOut[ListPolicyVersionsMethod][0].([]api.PolicyVersion)
+	}
+	var err error
+	if t.ArgsOut[ListPolicyVersionsMethod][2] != nil {
+		err = t.ArgsOut[ListPolicyVersionsMethod][2].(error)
+	}
+	return versions, total, err
+}
+
+func (t TestAPI) GetPolicyVersion(authenticatedUser api.RequestInfo, org string, policyName string, version int) (*api.PolicyVersion, error) {
+	t.ArgsIn[GetPolicyVersionMethod][0] = authenticatedUser
+	t.ArgsIn[GetPolicyVersionMethod][1] = org
+	t.ArgsIn[GetPolicyVersionMethod][2] = policyName
+	t.ArgsIn[GetPolicyVersionMethod][3] = version
+
+	var policyVersion *api.PolicyVersion
+	if t.ArgsOut[GetPolicyVersionMethod][0] != nil {
+		policyVersion = t.ArgsOut[GetPolicyVersionMethod][0].(*api.PolicyVersion)
+	}
+	var err error
+	if t.ArgsOut[GetPolicyVersionMethod][1] != nil {
+		err = t.ArgsOut[GetPolicyVersionMethod][1].(error)
+	}
+	return policyVersion, err
+}
+
+func (t TestAPI) DiffPolicyVersions(authenticatedUser api.RequestInfo, org string, policyName string, fromVersion int,
+	toVersion int) (*api.PolicyVersionDiff, error) {
+	t.ArgsIn[DiffPolicyVersionsMethod][0] = authenticatedUser
+	t.ArgsIn[DiffPolicyVersionsMethod][1] = org
+	t.ArgsIn[DiffPolicyVersionsMethod][2] = policyName
+	t.ArgsIn[DiffPolicyVersionsMethod][3] = fromVersion
+	t.ArgsIn[DiffPolicyVersionsMethod][4] = toVersion
+
+	var diff *api.PolicyVersionDiff
+	if t.ArgsOut[DiffPolicyVersionsMethod][0] != nil {
+		diff = t.ArgsOut[DiffPolicyVersionsMethod][0].(*api.PolicyVersionDiff)
+	}
+	var err error
+	if t.ArgsOut[DiffPolicyVersionsMethod][1] != nil {
+		err = t.ArgsOut[DiffPolicyVersionsMethod][1].(error)
+	}
+	return diff, err
+}
+
+func (t TestAPI) SetDefaultPolicyVersion(authenticatedUser api.RequestInfo, org string, policyName string, version int) (*api.Policy, error) {
+	t.ArgsIn[SetDefaultPolicyVersionMethod][0] = authenticatedUser
+	t.ArgsIn[SetDefaultPolicyVersionMethod][1] = org
+	t.ArgsIn[SetDefaultPolicyVersionMethod][2] = policyName
+	t.ArgsIn[SetDefaultPolicyVersionMethod][3] = version
+
+	var policy *api.Policy
+	if t.ArgsOut[SetDefaultPolicyVersionMethod][0] != nil {
+		policy = t.ArgsOut[SetDefaultPolicyVersionMethod][0].(*api.Policy)
+	}
+	var err error
+	if t.ArgsOut[SetDefaultPolicyVersionMethod][1] != nil {
+		err = t.ArgsOut[SetDefaultPolicyVersionMethod][1].(error)
+	}
+	return policy, err
 }
 
 // AUTHZ API
@@ -705,6 +1077,22 @@ func (t TestAPI) GetAuthorizedExternalResources(authenticatedUser api.RequestInf
 	return resourcesToReturn, err
 }
 
+func (t TestAPI) ReviewSubjectAccess(authenticatedUser api.RequestInfo, subject api.Subject, action string, resource string) (*api.AccessReview, error) {
+	t.ArgsIn[ReviewSubjectAccessMethod][0] = authenticatedUser
+	t.ArgsIn[ReviewSubjectAccessMethod][1] = subject
+	t.ArgsIn[ReviewSubjectAccessMethod][2] = action
+	t.ArgsIn[ReviewSubjectAccessMethod][3] = resource
+	var review *api.AccessReview
+	if t.ArgsOut[ReviewSubjectAccessMethod][0] != nil {
+		review = t.ArgsOut[ReviewSubjectAccessMethod][0].(*api.AccessReview)
+	}
+	var err error
+	if t.ArgsOut[ReviewSubjectAccessMethod][1] != nil {
+		err = t.ArgsOut[ReviewSubjectAccessMethod][1].(error)
+	}
+	return review, err
+}
+
 func (t TestAPI) GetAuthorizedProxyResources(authenticatedUser api.RequestInfo, resourceUrn string, action string, proxyResources []api.ProxyResource) ([]api.ProxyResource, error) {
 	return nil, nil
 }
@@ -743,6 +1131,9 @@ func (t TestAPI) GetProxyResourceByName(authenticatedUser api.RequestInfo, org s
 }
 
 func (t TestAPI) GetProxyResources() ([]api.ProxyResource, error) {
+	if specialFunc, ok := t.SpecialFuncs[GetProxyResourcesMethod].(func() ([]api.ProxyResource, error)); ok && specialFunc != nil {
+		return specialFunc()
+	}
 	var proxyResources []api.ProxyResource
 	if t.ArgsOut[GetProxyResourcesMethod][0] != nil {
 		proxyResources = t.ArgsOut[GetProxyResourcesMethod][0].([]api.ProxyResource)
@@ -754,7 +1145,7 @@ func (t TestAPI) GetProxyResources() ([]api.ProxyResource, error) {
 	return proxyResources, err
 }
 
-func (t TestAPI) ListProxyResources(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.ProxyResourceIdentity, int, error) {
+func (t TestAPI) ListProxyResources(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.ProxyResourceIdentity, int, string, error) {
 	t.ArgsIn[ListProxyResourcesMethod][0] = authenticatedUser
 	t.ArgsIn[ListProxyResourcesMethod][1] = filter
 
@@ -766,11 +1157,15 @@ func (t TestAPI) ListProxyResources(authenticatedUser api.RequestInfo, filter *a
 	if t.ArgsOut[ListProxyResourcesMethod][0] != nil {
 		proxyResources = t.ArgsOut[ListProxyResourcesMethod][0].([]api.ProxyResourceIdentity)
 	}
-	var err error
+	var nextToken string
 	if t.ArgsOut[ListProxyResourcesMethod][2] != nil {
-		err = t.ArgsOut[ListProxyResourcesMethod][2].(error)
+		nextToken = t.ArgsOut[ListProxyResourcesMethod][2].(string)
 	}
-	return proxyResources, total, err
+	var err error
+	if t.ArgsOut[ListProxyResourcesMethod][3] != nil {
+		err = t.ArgsOut[ListProxyResourcesMethod][3].(error)
+	}
+	return proxyResources, total, nextToken, err
 }
 
 func (t TestAPI) UpdateProxyResource(authenticatedUser api.RequestInfo, org string, name string, newName string, newPath string,
@@ -885,6 +1280,224 @@ func (t TestAPI) RemoveOidcProvider(requestInfo api.RequestInfo, name string) er
 	return err
 }
 
+func (t TestAPI) AddOrganization(requestInfo api.RequestInfo, name string, path string) (*api.Organization, error) {
+	t.ArgsIn[AddOrganizationMethod][0] = requestInfo
+	t.ArgsIn[AddOrganizationMethod][1] = name
+	t.ArgsIn[AddOrganizationMethod][2] = path
+	var org *api.Organization
+	if t.ArgsOut[AddOrganizationMethod][0] != nil {
+		org = t.ArgsOut[AddOrganizationMethod][0].(*api.Organization)
+	}
+	var err error
+	if t.ArgsOut[AddOrganizationMethod][1] != nil {
+		err = t.ArgsOut[AddOrganizationMethod][1].(error)
+	}
+	return org, err
+}
+
+func (t TestAPI) GetOrganizationByName(requestInfo api.RequestInfo, name string) (*api.Organization, error) {
+	t.ArgsIn[GetOrganizationByNameMethod][0] = requestInfo
+	t.ArgsIn[GetOrganizationByNameMethod][1] = name
+	var org *api.Organization
+	if t.ArgsOut[GetOrganizationByNameMethod][0] != nil {
+		org = t.ArgsOut[GetOrganizationByNameMethod][0].(*api.Organization)
+	}
+	var err error
+	if t.ArgsOut[GetOrganizationByNameMethod][1] != nil {
+		err = t.ArgsOut[GetOrganizationByNameMethod][1].(error)
+	}
+	return org, err
+}
+
+func (t TestAPI) ListOrganizations(requestInfo api.RequestInfo, filter *api.Filter) ([]string, int, error) {
+	t.ArgsIn[ListOrganizationsMethod][0] = requestInfo
+	t.ArgsIn[ListOrganizationsMethod][1] = filter
+
+	var orgs []string
+	if t.ArgsOut[ListOrganizationsMethod][0] != nil {
+		orgs = t.ArgsOut[ListOrganizationsMethod][0].([]string)
+	}
+	var total int
+	if t.ArgsOut[ListOrganizationsMethod][1] != nil {
+		total = t.ArgsOut[ListOrganizationsMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[ListOrganizationsMethod][2] != nil {
+		err = t.ArgsOut[ListOrganizationsMethod][2].(error)
+	}
+	return orgs, total, err
+}
+
+func (t TestAPI) UpdateOrganization(requestInfo api.RequestInfo, name string, newPath string) (*api.Organization, error) {
+	t.ArgsIn[UpdateOrganizationMethod][0] = requestInfo
+	t.ArgsIn[UpdateOrganizationMethod][1] = name
+	t.ArgsIn[UpdateOrganizationMethod][2] = newPath
+	var org *api.Organization
+	if t.ArgsOut[UpdateOrganizationMethod][0] != nil {
+		org = t.ArgsOut[UpdateOrganizationMethod][0].(*api.Organization)
+	}
+	var err error
+	if t.ArgsOut[UpdateOrganizationMethod][1] != nil {
+		err = t.ArgsOut[UpdateOrganizationMethod][1].(error)
+	}
+	return org, err
+}
+
+func (t TestAPI) RemoveOrganization(requestInfo api.RequestInfo, name string) error {
+	t.ArgsIn[RemoveOrganizationMethod][0] = requestInfo
+	t.ArgsIn[RemoveOrganizationMethod][1] = name
+	var err error
+	if t.ArgsOut[RemoveOrganizationMethod][0] != nil {
+		err = t.ArgsOut[RemoveOrganizationMethod][0].(error)
+	}
+	return err
+}
+
+// WEBHOOK API
+
+func (t TestAPI) AddWebhook(requestInfo api.RequestInfo, name string, url string, secret string, events []string) (*api.Webhook, error) {
+	t.ArgsIn[AddWebhookMethod][0] = requestInfo
+	t.ArgsIn[AddWebhookMethod][1] = name
+	t.ArgsIn[AddWebhookMethod][2] = url
+	t.ArgsIn[AddWebhookMethod][3] = secret
+	t.ArgsIn[AddWebhookMethod][4] = events
+	var webhook *api.Webhook
+	if t.ArgsOut[AddWebhookMethod][0] != nil {
+		webhook = t.ArgsOut[AddWebhookMethod][0].(*api.Webhook)
+	}
+	var err error
+	if t.ArgsOut[AddWebhookMethod][1] != nil {
+		err = t.ArgsOut[AddWebhookMethod][1].(error)
+	}
+	return webhook, err
+}
+
+func (t TestAPI) GetWebhookByName(requestInfo api.RequestInfo, name string) (*api.Webhook, error) {
+	t.ArgsIn[GetWebhookByNameMethod][0] = requestInfo
+	t.ArgsIn[GetWebhookByNameMethod][1] = name
+	var webhook *api.Webhook
+	if t.ArgsOut[GetWebhookByNameMethod][0] != nil {
+		webhook = t.ArgsOut[GetWebhookByNameMethod][0].(*api.Webhook)
+	}
+	var err error
+	if t.ArgsOut[GetWebhookByNameMethod][1] != nil {
+		err = t.ArgsOut[GetWebhookByNameMethod][1].(error)
+	}
+	return webhook, err
+}
+
+func (t TestAPI) ListWebhooks(requestInfo api.RequestInfo, filter *api.Filter) ([]string, int, error) {
+	t.ArgsIn[ListWebhooksMethod][0] = requestInfo
+	t.ArgsIn[ListWebhooksMethod][1] = filter
+	var webhooks []string
+	if t.ArgsOut[ListWebhooksMethod][0] != nil {
+		webhooks = t.ArgsOut[ListWebhooksMethod][0].([]string)
+	}
+	var total int
+	if t.ArgsOut[ListWebhooksMethod][1] != nil {
+		total = t.ArgsOut[ListWebhooksMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[ListWebhooksMethod][2] != nil {
+		err = t.ArgsOut[ListWebhooksMethod][2].(error)
+	}
+	return webhooks, total, err
+}
+
+func (t TestAPI) UpdateWebhook(requestInfo api.RequestInfo, name string, newName string, newURL string, newSecret string,
+	newEvents []string) (*api.Webhook, error) {
+	t.ArgsIn[UpdateWebhookMethod][0] = requestInfo
+	t.ArgsIn[UpdateWebhookMethod][1] = name
+	t.ArgsIn[UpdateWebhookMethod][2] = newName
+	t.ArgsIn[UpdateWebhookMethod][3] = newURL
+	t.ArgsIn[UpdateWebhookMethod][4] = newSecret
+	t.ArgsIn[UpdateWebhookMethod][5] = newEvents
+	var webhook *api.Webhook
+	if t.ArgsOut[UpdateWebhookMethod][0] != nil {
+		webhook = t.ArgsOut[UpdateWebhookMethod][0].(*api.Webhook)
+	}
+	var err error
+	if t.ArgsOut[UpdateWebhookMethod][1] != nil {
+		err = t.ArgsOut[UpdateWebhookMethod][1].(error)
+	}
+	return webhook, err
+}
+
+func (t TestAPI) RemoveWebhook(requestInfo api.RequestInfo, name string) error {
+	t.ArgsIn[RemoveWebhookMethod][0] = requestInfo
+	t.ArgsIn[RemoveWebhookMethod][1] = name
+	var err error
+	if t.ArgsOut[RemoveWebhookMethod][0] != nil {
+		err = t.ArgsOut[RemoveWebhookMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestAPI) ListWebhookDeliveries(requestInfo api.RequestInfo, filter *api.Filter) ([]api.WebhookDelivery, int, error) {
+	t.ArgsIn[ListWebhookDeliveriesMethod][0] = requestInfo
+	t.ArgsIn[ListWebhookDeliveriesMethod][1] = filter
+	var deliveries []api.WebhookDelivery
+	if t.ArgsOut[ListWebhookDeliveriesMethod][0] != nil {
+		deliveries = t.ArgsOut[ListWebhookDeliveriesMethod][0].([]api.WebhookDelivery)
+	}
+	var total int
+	if t.ArgsOut[ListWebhookDeliveriesMethod][1] != nil {
+		total = t.ArgsOut[ListWebhookDeliveriesMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[ListWebhookDeliveriesMethod][2] != nil {
+		err = t.ArgsOut[ListWebhookDeliveriesMethod][2].(error)
+	}
+	return deliveries, total, err
+}
+
+// STATE API
+
+func (t TestAPI) ExportState(requestInfo api.RequestInfo) (*api.State, error) {
+	t.ArgsIn[ExportStateMethod][0] = requestInfo
+	var state *api.State
+	if t.ArgsOut[ExportStateMethod][0] != nil {
+		state = t.ArgsOut[ExportStateMethod][0].(*api.State)
+	}
+	var err error
+	if t.ArgsOut[ExportStateMethod][1] != nil {
+		err = t.ArgsOut[ExportStateMethod][1].(error)
+	}
+	return state, err
+}
+
+func (t TestAPI) ImportState(requestInfo api.RequestInfo, state *api.State, mode string, validateOnly bool) (*api.ImportResult, error) {
+	t.ArgsIn[ImportStateMethod][0] = requestInfo
+	t.ArgsIn[ImportStateMethod][1] = state
+	t.ArgsIn[ImportStateMethod][2] = mode
+	t.ArgsIn[ImportStateMethod][3] = validateOnly
+	var result *api.ImportResult
+	if t.ArgsOut[ImportStateMethod][0] != nil {
+		result = t.ArgsOut[ImportStateMethod][0].(*api.ImportResult)
+	}
+	var err error
+	if t.ArgsOut[ImportStateMethod][1] != nil {
+		err = t.ArgsOut[ImportStateMethod][1].(error)
+	}
+	return result, err
+}
+
+func (t TestAPI) ReconcileState(requestInfo api.RequestInfo, state *api.State, ignoreUnmanaged bool, apply bool) (*api.ReconcileResult, error) {
+	t.ArgsIn[ReconcileStateMethod][0] = requestInfo
+	t.ArgsIn[ReconcileStateMethod][1] = state
+	t.ArgsIn[ReconcileStateMethod][2] = ignoreUnmanaged
+	t.ArgsIn[ReconcileStateMethod][3] = apply
+	var result *api.ReconcileResult
+	if t.ArgsOut[ReconcileStateMethod][0] != nil {
+		result = t.ArgsOut[ReconcileStateMethod][0].(*api.ReconcileResult)
+	}
+	var err error
+	if t.ArgsOut[ReconcileStateMethod][1] != nil {
+		err = t.ArgsOut[ReconcileStateMethod][1].(error)
+	}
+	return result, err
+}
+
 // Private helper methods
 
 func addQueryParams(filter *api.Filter, r *http.Request) {
@@ -895,6 +1508,39 @@ func addQueryParams(filter *api.Filter, r *http.Request) {
 		}
 		q.Add("Offset", fmt.Sprintf("%v", filter.Offset))
 		q.Add("Limit", fmt.Sprintf("%v", filter.Limit))
+		if filter.Transitive {
+			q.Add("Transitive", "true")
+		}
+		if filter.Deleted {
+			q.Add("Deleted", "true")
+		}
+		if filter.NextToken != "" {
+			q.Add("NextToken", filter.NextToken)
+		}
+		params := map[string]string{
+			"NamePrefix":     filter.NamePrefix,
+			"NameContains":   filter.NameContains,
+			"Member":         filter.Member,
+			"AttachedPolicy": filter.AttachedPolicy,
+			"Action":         filter.Action,
+			"Resource":       filter.Resource,
+		}
+		for param, value := range params {
+			if value != "" {
+				q.Add(param, value)
+			}
+		}
+		dates := map[string]time.Time{
+			"CreatedAfter":  filter.CreatedAfter,
+			"CreatedBefore": filter.CreatedBefore,
+			"UpdatedAfter":  filter.UpdatedAfter,
+			"UpdatedBefore": filter.UpdatedBefore,
+		}
+		for param, date := range dates {
+			if !date.IsZero() {
+				q.Add(param, date.Format(time.RFC3339))
+			}
+		}
 		r.URL.RawQuery = q.Encode()
 	}
 }
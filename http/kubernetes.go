@@ -0,0 +1,184 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// Kubernetes authorization API
+	KUBERNETES_AUTHORIZATION_API_VERSION = "authorization.k8s.io/v1"
+	KUBERNETES_SUBJECT_ACCESS_REVIEW     = "SubjectAccessReview"
+
+	// Variables of the templates used to map Kubernetes requests to Foulkon actions and resources
+	KUBERNETES_VARIABLE_VERB        = "{verb}"
+	KUBERNETES_VARIABLE_API_GROUP   = "{apiGroup}"
+	KUBERNETES_VARIABLE_RESOURCE    = "{resource}"
+	KUBERNETES_VARIABLE_SUBRESOURCE = "{subresource}"
+	KUBERNETES_VARIABLE_NAMESPACE   = "{namespace}"
+	KUBERNETES_VARIABLE_NAME        = "{name}"
+	KUBERNETES_VARIABLE_PATH        = "{path}"
+
+	// Value of the template variables that are empty in the request, like the API group of core resources
+	KUBERNETES_EMPTY_VALUE = "-"
+)
+
+var rKubernetesVariable, _ = regexp.Compile(`\{\w+\}`)
+
+// REQUESTS
+
+type ResourceAttributes struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Verb        string `json:"verb,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type NonResourceAttributes struct {
+	Path string `json:"path,omitempty"`
+	Verb string `json:"verb,omitempty"`
+}
+
+type SubjectAccessReviewSpec struct {
+	ResourceAttributes    *ResourceAttributes    `json:"resourceAttributes,omitempty"`
+	NonResourceAttributes *NonResourceAttributes `json:"nonResourceAttributes,omitempty"`
+	User                  string                 `json:"user,omitempty"`
+	Groups                []string               `json:"groups,omitempty"`
+	UID                   string                 `json:"uid,omitempty"`
+}
+
+type SubjectAccessReviewStatus struct {
+	Allowed         bool   `json:"allowed"`
+	Reason          string `json:"reason,omitempty"`
+	EvaluationError string `json:"evaluationError,omitempty"`
+}
+
+// SubjectAccessReview is both the request and the response of Kubernetes authorization webhooks
+type SubjectAccessReview struct {
+	APIVersion string                     `json:"apiVersion,omitempty"`
+	Kind       string                     `json:"kind,omitempty"`
+	Spec       *SubjectAccessReviewSpec   `json:"spec,omitempty"`
+	Status     *SubjectAccessReviewStatus `json:"status,omitempty"`
+}
+
+// HANDLERS
+
+func (wh *WorkerHandler) HandleReviewKubernetesSubjectAccess(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Process request
+	request := &SubjectAccessReview{}
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, nil, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	if request.APIVersion != KUBERNETES_AUTHORIZATION_API_VERSION || request.Kind != KUBERNETES_SUBJECT_ACCESS_REVIEW || request.Spec == nil {
+		apiErr = &api.Error{
+			Code: api.INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: expected a %v %v, received %v %v",
+				KUBERNETES_AUTHORIZATION_API_VERSION, KUBERNETES_SUBJECT_ACCESS_REVIEW, request.APIVersion, request.Kind),
+		}
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	response := SubjectAccessReview{
+		APIVersion: KUBERNETES_AUTHORIZATION_API_VERSION,
+		Kind:       KUBERNETES_SUBJECT_ACCESS_REVIEW,
+		Status:     &SubjectAccessReviewStatus{},
+	}
+
+	wc := wh.worker.Config
+	subject := api.Subject{
+		ExternalID: request.Spec.User,
+	}
+	// Groups are only looked up when Foulkon knows the organization they belong to
+	if wc.KubernetesOrg != "" {
+		subject.Org = wc.KubernetesOrg
+		subject.Groups = request.Spec.Groups
+	}
+	action, resource, err := mapSubjectAccessReviewSpec(request.Spec, wc.KubernetesAction, wc.KubernetesResourceUrn, wc.KubernetesNonResourceUrn)
+	if err == nil {
+		var review *api.AccessReview
+		review, err = wh.worker.AuthzApi.ReviewSubjectAccess(requestInfo, subject, action, resource)
+		if err == nil {
+			response.Status.Allowed = review.Allowed
+			response.Status.Reason = review.Reason
+		}
+	}
+
+	// Kubernetes denies the request when the review can't be evaluated, so invalid attributes
+	// are reported in the review and only unexpected errors are returned
+	if e, ok := err.(*api.Error); ok && e.Code == api.INVALID_PARAMETER_ERROR {
+		api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, e)
+		response.Status.EvaluationError = e.Message
+		err = nil
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+// PRIVATE HELPER METHODS
+
+// Map the attributes of a Kubernetes request to a Foulkon action and external resource urn, replacing
+// the template variables with their values
+func mapSubjectAccessReviewSpec(spec *SubjectAccessReviewSpec, actionTemplate string, resourceUrnTemplate string,
+	nonResourceUrnTemplate string) (string, string, error) {
+	var replacer *strings.Replacer
+	var urnTemplate string
+	switch {
+	case spec.ResourceAttributes != nil:
+		attributes := spec.ResourceAttributes
+		replacer = strings.NewReplacer(
+			KUBERNETES_VARIABLE_VERB, kubernetesValue(attributes.Verb),
+			KUBERNETES_VARIABLE_API_GROUP, kubernetesValue(attributes.Group),
+			KUBERNETES_VARIABLE_RESOURCE, kubernetesValue(attributes.Resource),
+			KUBERNETES_VARIABLE_SUBRESOURCE, kubernetesValue(attributes.Subresource),
+			KUBERNETES_VARIABLE_NAMESPACE, kubernetesValue(attributes.Namespace),
+			KUBERNETES_VARIABLE_NAME, kubernetesValue(attributes.Name),
+			KUBERNETES_VARIABLE_PATH, KUBERNETES_EMPTY_VALUE,
+		)
+		urnTemplate = resourceUrnTemplate
+	case spec.NonResourceAttributes != nil:
+		attributes := spec.NonResourceAttributes
+		replacer = strings.NewReplacer(
+			KUBERNETES_VARIABLE_VERB, kubernetesValue(attributes.Verb),
+			KUBERNETES_VARIABLE_API_GROUP, KUBERNETES_EMPTY_VALUE,
+			KUBERNETES_VARIABLE_RESOURCE, KUBERNETES_EMPTY_VALUE,
+			KUBERNETES_VARIABLE_SUBRESOURCE, KUBERNETES_EMPTY_VALUE,
+			KUBERNETES_VARIABLE_NAMESPACE, KUBERNETES_EMPTY_VALUE,
+			KUBERNETES_VARIABLE_NAME, KUBERNETES_EMPTY_VALUE,
+			// Paths are inserted without their leading and trailing slashes to get valid urns
+			KUBERNETES_VARIABLE_PATH, kubernetesValue(strings.Trim(attributes.Path, "/")),
+		)
+		urnTemplate = nonResourceUrnTemplate
+	default:
+		return "", "", &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: resourceAttributes or nonResourceAttributes are required",
+		}
+	}
+
+	action, urn := replacer.Replace(actionTemplate), replacer.Replace(urnTemplate)
+	if rKubernetesVariable.MatchString(action + urn) {
+		return "", "", &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: unknown variables in action %v or resource %v", action, urn),
+		}
+	}
+
+	return action, urn, nil
+}
+
+func kubernetesValue(value string) string {
+	if value == "" {
+		return KUBERNETES_EMPTY_VALUE
+	}
+	return value
+}
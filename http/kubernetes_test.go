@@ -0,0 +1,274 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// Reads a SubjectAccessReview recorded from a Kubernetes API server
+func readKubernetesFixture(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "kubernetes", name))
+	assert.Nil(t, err, "Error reading fixture %v", name)
+	return data
+}
+
+func TestWorkerHandler_HandleReviewKubernetesSubjectAccess(t *testing.T) {
+	testcases := map[string]struct {
+		// Recorded request, empty to send an empty body
+		requestFixture string
+		// Expected API method args
+		expectedSubject  api.Subject
+		expectedAction   string
+		expectedResource string
+		// Expected result
+		expectedStatusCode      int
+		expectedResponseFixture string
+		expectedError           api.Error
+		// Manager Results
+		reviewSubjectAccessResult *api.AccessReview
+		// Manager Errors
+		reviewSubjectAccessErr error
+	}{
+		"OkCaseAllowedResource": {
+			requestFixture: "get-pod.request.json",
+			expectedSubject: api.Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers", "system:authenticated"},
+			},
+			expectedAction:          "k8s:get",
+			expectedResource:        "urn:k8s:cluster::default/-/pods/-/nginx",
+			expectedStatusCode:      http.StatusOK,
+			expectedResponseFixture: "get-pod.response.json",
+			reviewSubjectAccessResult: &api.AccessReview{
+				Allowed: true,
+				Reason:  "User with externalId jane is allowed to do action k8s:get over resource urn:k8s:cluster::default/-/pods/-/nginx",
+			},
+		},
+		"OkCaseDeniedResourceList": {
+			requestFixture: "list-deployments.request.json",
+			expectedSubject: api.Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers", "system:authenticated"},
+			},
+			expectedAction:          "k8s:list",
+			expectedResource:        "urn:k8s:cluster::kube-system/apps/deployments/-/-",
+			expectedStatusCode:      http.StatusOK,
+			expectedResponseFixture: "list-deployments.response.json",
+			reviewSubjectAccessResult: &api.AccessReview{
+				Allowed: false,
+				Reason:  "User with externalId jane is not allowed to do action k8s:list over resource urn:k8s:cluster::kube-system/apps/deployments/-/-",
+			},
+		},
+		"OkCaseDeniedSubresource": {
+			requestFixture: "exec-pod.request.json",
+			expectedSubject: api.Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"system:authenticated"},
+			},
+			expectedAction:          "k8s:create",
+			expectedResource:        "urn:k8s:cluster::default/-/pods/exec/nginx",
+			expectedStatusCode:      http.StatusOK,
+			expectedResponseFixture: "exec-pod.response.json",
+			reviewSubjectAccessResult: &api.AccessReview{
+				Allowed: false,
+				Reason:  "User with externalId jane is not allowed to do action k8s:create over resource urn:k8s:cluster::default/-/pods/exec/nginx",
+			},
+		},
+		"OkCaseAllowedNonResource": {
+			requestFixture: "get-healthz.request.json",
+			expectedSubject: api.Subject{
+				ExternalID: "monitoring",
+				Org:        "example",
+				Groups:     []string{"system:authenticated"},
+			},
+			expectedAction:          "k8s:get",
+			expectedResource:        "urn:k8s:cluster::nonresource/healthz",
+			expectedStatusCode:      http.StatusOK,
+			expectedResponseFixture: "get-healthz.response.json",
+			reviewSubjectAccessResult: &api.AccessReview{
+				Allowed: true,
+				Reason:  "User with externalId monitoring is allowed to do action k8s:get over resource urn:k8s:cluster::nonresource/healthz",
+			},
+		},
+		"OkCaseNoAttributes": {
+			requestFixture:          "no-attributes.request.json",
+			expectedStatusCode:      http.StatusOK,
+			expectedResponseFixture: "no-attributes.response.json",
+		},
+		"OkCaseInvalidParameter": {
+			requestFixture: "get-pod.request.json",
+			expectedSubject: api.Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers", "system:authenticated"},
+			},
+			expectedAction:          "k8s:get",
+			expectedResource:        "urn:k8s:cluster::default/-/pods/-/nginx",
+			expectedStatusCode:      http.StatusOK,
+			expectedResponseFixture: "no-attributes.response.json",
+			reviewSubjectAccessErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: resourceAttributes or nonResourceAttributes are required",
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseUnsupportedVersion": {
+			requestFixture:     "v1beta1.request.json",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: expected a authorization.k8s.io/v1 SubjectAccessReview, received authorization.k8s.io/v1beta1 SubjectAccessReview",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			requestFixture: "get-pod.request.json",
+			expectedSubject: api.Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers", "system:authenticated"},
+			},
+			expectedAction:     "k8s:get",
+			expectedResource:   "urn:k8s:cluster::default/-/pods/-/nginx",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Error",
+			},
+			reviewSubjectAccessErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			requestFixture: "get-pod.request.json",
+			expectedSubject: api.Subject{
+				ExternalID: "jane",
+				Org:        "example",
+				Groups:     []string{"developers", "system:authenticated"},
+			},
+			expectedAction:     "k8s:get",
+			expectedResource:   "urn:k8s:cluster::default/-/pods/-/nginx",
+			expectedStatusCode: http.StatusInternalServerError,
+			reviewSubjectAccessErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsIn[ReviewSubjectAccessMethod] = make([]interface{}, 4)
+		testApi.ArgsOut[ReviewSubjectAccessMethod][0] = test.reviewSubjectAccessResult
+		testApi.ArgsOut[ReviewSubjectAccessMethod][1] = test.reviewSubjectAccessErr
+
+		body := bytes.NewBuffer([]byte{})
+		if test.requestFixture != "" {
+			body = bytes.NewBuffer(readKubernetesFixture(t, test.requestFixture))
+		}
+		req, err := http.NewRequest(http.MethodPost, server.URL+KUBERNETES_SUBJECT_ACCESS_REVIEW_URL, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		if test.expectedAction != "" {
+			assert.Equal(t, test.expectedSubject, testApi.ArgsIn[ReviewSubjectAccessMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.expectedAction, testApi.ArgsIn[ReviewSubjectAccessMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.expectedResource, testApi.ArgsIn[ReviewSubjectAccessMethod][3], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[ReviewSubjectAccessMethod][2], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			review := SubjectAccessReview{}
+			err = json.NewDecoder(res.Body).Decode(&review)
+			assert.Nil(t, err, "Error in test case %v", n)
+			expectedReview := SubjectAccessReview{}
+			err = json.Unmarshal(readKubernetesFixture(t, test.expectedResponseFixture), &expectedReview)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, expectedReview, review, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestMapSubjectAccessReviewSpec(t *testing.T) {
+	testcases := map[string]struct {
+		spec                *SubjectAccessReviewSpec
+		actionTemplate      string
+		resourceUrnTemplate string
+		// Expected result
+		expectedAction   string
+		expectedResource string
+		wantError        error
+	}{
+		"OkCaseAllVariables": {
+			spec: &SubjectAccessReviewSpec{
+				ResourceAttributes: &ResourceAttributes{
+					Namespace: "default",
+					Verb:      "delete",
+					Group:     "rbac.authorization.k8s.io",
+					Resource:  "rolebindings",
+					Name:      "admin",
+				},
+			},
+			actionTemplate:      "k8s:{resource}:{verb}",
+			resourceUrnTemplate: "urn:k8s:{apiGroup}:example:{namespace}/{name}{subresource}{path}",
+			expectedAction:      "k8s:rolebindings:delete",
+			expectedResource:    "urn:k8s:rbac.authorization.k8s.io:example:default/admin--",
+		},
+		"ErrorCaseUnknownVariable": {
+			spec: &SubjectAccessReviewSpec{
+				ResourceAttributes: &ResourceAttributes{
+					Verb:     "get",
+					Resource: "nodes",
+				},
+			},
+			actionTemplate:      "k8s:{verb}",
+			resourceUrnTemplate: "urn:k8s:cluster::{cluster}/{resource}",
+			wantError: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: unknown variables in action k8s:get or resource urn:k8s:cluster::{cluster}/nodes",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		action, resource, err := mapSubjectAccessReviewSpec(test.spec, test.actionTemplate, test.resourceUrnTemplate, "")
+		assert.Equal(t, test.wantError, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedAction, action, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResource, resource, "Error in test case %v", n)
+	}
+}
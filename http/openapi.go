@@ -0,0 +1,456 @@
+package http
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	OPENAPI_VERSION = "3.0.0"
+	OPENAPI_TITLE   = "Foulkon"
+
+	JSON_MEDIA_TYPE = "application/json"
+
+	openAPISchemaRefPrefix = "#/components/schemas/"
+)
+
+var (
+	// Query parameters shared by list endpoints
+	paginationQuery = []string{"Offset", "Limit", "OrderBy"}
+	searchQuery     = []string{"NamePrefix", "NameContains", "CreatedAfter", "CreatedBefore", "UpdatedAfter", "UpdatedBefore"}
+
+	// Schemas of query parameters, the rest are strings
+	queryParamSchemas = map[string]OpenAPISchema{
+		"Offset":          {"type": "integer", "minimum": 0},
+		"Limit":           {"type": "integer", "minimum": 0, "maximum": api.MAX_LIMIT_SIZE},
+		"Deleted":         {"type": "boolean"},
+		"Transitive":      {"type": "boolean"},
+		"ValidateOnly":    {"type": "boolean"},
+		"IgnoreUnmanaged": {"type": "boolean"},
+		"From":            {"type": "integer", "minimum": 1},
+		"To":              {"type": "integer", "minimum": 1},
+		"CreatedAfter":    {"type": "string", "format": "date-time"},
+		"CreatedBefore":   {"type": "string", "format": "date-time"},
+		"UpdatedAfter":    {"type": "string", "format": "date-time"},
+		"UpdatedBefore":   {"type": "string", "format": "date-time"},
+	}
+
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte{})
+)
+
+// RESPONSE
+
+// OpenAPISchema is a JSON schema as described by the OpenAPI specification
+type OpenAPISchema map[string]interface{}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIMediaType struct {
+	Schema OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIParameter struct {
+	Name     string        `json:"name"`
+	In       string        `json:"in"`
+	Required bool          `json:"required,omitempty"`
+	Schema   OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIHeader struct {
+	Description string        `json:"description,omitempty"`
+	Schema      OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Headers     map[string]OpenAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIComponents struct {
+	Schemas         map[string]OpenAPISchema `json:"schemas"`
+	SecuritySchemes map[string]OpenAPISchema `json:"securitySchemes"`
+}
+
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+	Security   []map[string][]string                   `json:"security"`
+}
+
+// HANDLER
+
+func (wh *WorkerHandler) HandleGetOpenAPIDocument(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, err := wh.processHttpRequest(r, w, ps, nil)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+
+	response := getOpenAPIDocument(workerRoutes(), wh.worker.Config.Version)
+
+	// Return document
+	wh.processHttpResponse(r, w, requestInfo, response, nil, http.StatusOK)
+}
+
+// PRIVATE HELPER METHODS
+
+// queryParams joins several sets of query parameters
+func queryParams(sets ...[]string) []string {
+	params := []string{}
+	for _, set := range sets {
+		params = append(params, set...)
+	}
+	return params
+}
+
+// getOpenAPIDocument describes the routes given, with their request and response bodies and the errors they return
+func getOpenAPIDocument(routes []route, version string) *OpenAPIDocument {
+	g := &openAPIGenerator{
+		schemas: map[string]OpenAPISchema{},
+		types:   map[string]reflect.Type{},
+	}
+	errorSchema := g.schema(reflect.TypeOf(api.Error{}))
+
+	doc := &OpenAPIDocument{
+		OpenAPI: OPENAPI_VERSION,
+		Info: OpenAPIInfo{
+			Title:   OPENAPI_TITLE,
+			Version: version,
+		},
+		Paths: map[string]map[string]*OpenAPIOperation{},
+		Components: OpenAPIComponents{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]OpenAPISchema{
+				"basic":  {"type": "http", "scheme": "basic"},
+				"bearer": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		Security: []map[string][]string{
+			{"basic": {}},
+			{"bearer": {}},
+		},
+	}
+
+	for _, rt := range routes {
+		p, params := openAPIPath(rt.path)
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = map[string]*OpenAPIOperation{}
+		}
+		doc.Paths[p][strings.ToLower(rt.method)] = g.operation(rt, params, errorSchema)
+	}
+
+	return doc
+}
+
+// openAPIPath converts a router path to an OpenAPI one, returning the names of its parameters
+func openAPIPath(routerPath string) (string, []string) {
+	params := []string{}
+	segments := strings.Split(routerPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// openAPIErrorCodes returns the API error codes by HTTP status code
+func openAPIErrorCodes() map[int][]string {
+	codes := map[int][]string{}
+	for code, statusCode := range errorStatusCodes {
+		codes[statusCode] = append(codes[statusCode], code)
+	}
+	codes[http.StatusInternalServerError] = []string{api.UNKNOWN_API_ERROR}
+	for _, c := range codes {
+		sort.Strings(c)
+	}
+	return codes
+}
+
+// openAPIOperationName returns the name of the handler of a route without the Handle prefix
+func openAPIOperationName(rt route) string {
+	name := runtime.FuncForPC(reflect.ValueOf(rt.handle).Pointer()).Name()
+	// Method expressions are named like package.(*WorkerHandler).HandleX-fm
+	name = strings.TrimSuffix(name[strings.LastIndex(name, ".")+1:], "-fm")
+	return strings.TrimPrefix(name, "Handle")
+}
+
+// openAPISummary splits an operation name in words, keeping acronyms like ID together
+func openAPISummary(name string) string {
+	words := []string{}
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+		acronymEnd := unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+
+	for i := 1; i < len(words); i++ {
+		if strings.ToUpper(words[i]) != words[i] {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+type openAPIGenerator struct {
+	schemas map[string]OpenAPISchema
+	// Go types of the component schemas, to qualify the names of types with the same name
+	types map[string]reflect.Type
+}
+
+func (g *openAPIGenerator) operation(rt route, pathParams []string, errorSchema OpenAPISchema) *OpenAPIOperation {
+	name := openAPIOperationName(rt)
+	op := &OpenAPIOperation{
+		OperationID: name,
+		Summary:     openAPISummary(name),
+		Tags:        []string{rt.tag},
+		Parameters:  []OpenAPIParameter{},
+		Responses:   map[string]OpenAPIResponse{},
+	}
+
+	// Parameters
+	for _, param := range pathParams {
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:     param,
+			In:       "path",
+			Required: true,
+			Schema:   OpenAPISchema{"type": "string"},
+		})
+	}
+	for _, param := range rt.query {
+		schema, ok := queryParamSchemas[param]
+		if !ok {
+			schema = OpenAPISchema{"type": "string"}
+		}
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:   param,
+			In:     "query",
+			Schema: schema,
+		})
+	}
+	if rt.ifMatch {
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:   "If-Match",
+			In:     "header",
+			Schema: OpenAPISchema{"type": "string"},
+		})
+	}
+
+	// Request body
+	if rt.request != nil {
+		schema := g.schema(reflect.TypeOf(rt.request))
+		content := map[string]OpenAPIMediaType{}
+		if rt.method == http.MethodPatch {
+			content[MERGE_PATCH_CONTENT_TYPE] = OpenAPIMediaType{Schema: schema}
+			content[JSON_PATCH_CONTENT_TYPE] = OpenAPIMediaType{Schema: g.schema(reflect.TypeOf([]PatchOperation{}))}
+		} else {
+			content[JSON_MEDIA_TYPE] = OpenAPIMediaType{Schema: schema}
+		}
+		if rt.yaml {
+			content[YAML_MEDIA_TYPE] = OpenAPIMediaType{Schema: schema}
+		}
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  content,
+		}
+	}
+
+	// Successful response
+	response := OpenAPIResponse{
+		Description: http.StatusText(rt.status),
+	}
+	if rt.response != nil {
+		schema := g.schema(reflect.TypeOf(rt.response))
+		response.Content = map[string]OpenAPIMediaType{
+			JSON_MEDIA_TYPE: {Schema: schema},
+		}
+		if rt.yaml {
+			response.Content[YAML_MEDIA_TYPE] = OpenAPIMediaType{Schema: schema}
+		}
+	}
+	if rt.etag {
+		response.Headers = map[string]OpenAPIHeader{
+			"ETag": {
+				Description: "Revision of the resource",
+				Schema:      OpenAPISchema{"type": "string"},
+			},
+		}
+	}
+	op.Responses[strconv.Itoa(rt.status)] = response
+
+	// Error responses
+	statusCodes := []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError}
+	if len(pathParams) > 0 {
+		statusCodes = append(statusCodes, http.StatusNotFound)
+	}
+	if rt.method == http.MethodPost || rt.method == http.MethodPut || rt.method == http.MethodPatch {
+		statusCodes = append(statusCodes, http.StatusConflict)
+	}
+	if rt.ifMatch {
+		statusCodes = append(statusCodes, http.StatusPreconditionFailed)
+	}
+	if rt.request != nil {
+		statusCodes = append(statusCodes, http.StatusRequestEntityTooLarge)
+	}
+	errorCodes := openAPIErrorCodes()
+	for _, statusCode := range statusCodes {
+		op.Responses[strconv.Itoa(statusCode)] = OpenAPIResponse{
+			Description: http.StatusText(statusCode) + ", error codes: " + strings.Join(errorCodes[statusCode], ", "),
+			Content: map[string]OpenAPIMediaType{
+				JSON_MEDIA_TYPE: {Schema: errorSchema},
+			},
+		}
+	}
+	// Authentication errors are written by the authenticator in plain text
+	op.Responses[strconv.Itoa(http.StatusUnauthorized)] = OpenAPIResponse{
+		Description: http.StatusText(http.StatusUnauthorized),
+	}
+
+	return op
+}
+
+// schema returns the JSON schema of a Go type, adding named structs to the component schemas
+func (g *openAPIGenerator) schema(t reflect.Type) OpenAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return OpenAPISchema{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return OpenAPISchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return OpenAPISchema{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return OpenAPISchema{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return OpenAPISchema{"type": "number"}
+	case reflect.String:
+		return OpenAPISchema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.ConvertibleTo(bytesType) {
+			return OpenAPISchema{"type": "string", "format": "byte"}
+		}
+		return OpenAPISchema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return OpenAPISchema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := g.schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// Placeholder for types that reference themselves
+			g.schemas[name] = OpenAPISchema{}
+			g.schemas[name] = g.structSchema(t)
+		}
+		return OpenAPISchema{"$ref": openAPISchemaRefPrefix + name}
+	default:
+		// Any value
+		return OpenAPISchema{}
+	}
+}
+
+// schemaName returns the component name of a struct, qualified with its package if another one has the same name
+func (g *openAPIGenerator) schemaName(t reflect.Type) string {
+	name := t.Name()
+	if other, ok := g.types[name]; ok && other != t {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	g.types[name] = t
+	return name
+}
+
+func (g *openAPIGenerator) structSchema(t reflect.Type) OpenAPISchema {
+	properties := map[string]OpenAPISchema{}
+	required := []string{}
+	g.addFields(t, properties, &required)
+
+	schema := OpenAPISchema{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields adds the properties of the fields encoded by encoding/json, flattening embedded structs
+func (g *openAPIGenerator) addFields(t reflect.Type, properties map[string]OpenAPISchema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name := tag
+		omitEmpty := false
+		if idx := strings.Index(tag, ","); idx != -1 {
+			name = tag[:idx]
+			omitEmpty = strings.Contains(tag[idx:], ",omitempty")
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			g.addFields(fieldType, properties, required)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = g.schema(field.Type)
+		if !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// Collects the schema references of a decoded JSON document
+func collectSchemaRefs(value interface{}, refs map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if ref, ok := val.(string); ok && key == "$ref" {
+				refs[ref] = true
+			}
+			collectSchemaRefs(val, refs)
+		}
+	case []interface{}:
+		for _, val := range v {
+			collectSchemaRefs(val, refs)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleGetOpenAPIDocument(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, server.URL+OPENAPI_URL, nil)
+	assert.Nil(t, err, "Error creating request")
+
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err, "Error doing request")
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Error in status code")
+
+	doc := map[string]interface{}{}
+	err = json.NewDecoder(res.Body).Decode(&doc)
+	assert.Nil(t, err, "Error decoding document")
+
+	assert.Equal(t, OPENAPI_VERSION, doc["openapi"], "Error in OpenAPI version")
+	assert.Equal(t, map[string]interface{}{"title": OPENAPI_TITLE, "version": "test"}, doc["info"], "Error in info")
+
+	// Every route is described
+	paths := doc["paths"].(map[string]interface{})
+	for _, rt := range workerRoutes() {
+		p, params := openAPIPath(rt.path)
+		operations, ok := paths[p].(map[string]interface{})
+		if !assert.True(t, ok, "Path %v not found", p) {
+			continue
+		}
+		operation, ok := operations[strings.ToLower(rt.method)].(map[string]interface{})
+		if !assert.True(t, ok, "Operation %v %v not found", rt.method, p) {
+			continue
+		}
+		assert.NotEmpty(t, operation["operationId"], "Error in operation %v %v", rt.method, p)
+
+		responses := operation["responses"].(map[string]interface{})
+		assert.Contains(t, responses, "400", "Error in operation %v %v", rt.method, p)
+		assert.Contains(t, responses, "401", "Error in operation %v %v", rt.method, p)
+		assert.Contains(t, responses, "500", "Error in operation %v %v", rt.method, p)
+		if len(params) > 0 {
+			assert.Contains(t, responses, "404", "Error in operation %v %v", rt.method, p)
+		}
+		if rt.request != nil {
+			assert.Contains(t, operation, "requestBody", "Error in operation %v %v", rt.method, p)
+			assert.Contains(t, responses, "413", "Error in operation %v %v", rt.method, p)
+		}
+	}
+
+	// Every schema reference resolves
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	refs := map[string]bool{}
+	collectSchemaRefs(doc, refs)
+	assert.NotEmpty(t, refs, "Error in schema references")
+	for ref := range refs {
+		assert.Contains(t, schemas, strings.TrimPrefix(ref, openAPISchemaRefPrefix), "Schema of reference %v not found", ref)
+	}
+}
+
+func TestGetOpenAPIDocument(t *testing.T) {
+	doc := getOpenAPIDocument(workerRoutes(), "test")
+
+	// Operation of a route
+	p, params := openAPIPath(GROUP_ID_URL)
+	op := doc.Paths[p]["patch"]
+	assert.Equal(t, "PatchGroup", op.OperationID)
+	assert.Equal(t, "Patch group", op.Summary)
+	assert.Equal(t, []string{GROUP_TAG}, op.Tags)
+	assert.Equal(t, []OpenAPIParameter{
+		{Name: params[0], In: "path", Required: true, Schema: OpenAPISchema{"type": "string"}},
+		{Name: params[1], In: "path", Required: true, Schema: OpenAPISchema{"type": "string"}},
+		{Name: "If-Match", In: "header", Schema: OpenAPISchema{"type": "string"}},
+	}, op.Parameters)
+	assert.Equal(t, OpenAPISchema{"$ref": "#/components/schemas/UpdateGroupRequest"}, op.RequestBody.Content[MERGE_PATCH_CONTENT_TYPE].Schema)
+	assert.Equal(t, OpenAPISchema{"type": "array", "items": OpenAPISchema{"$ref": "#/components/schemas/PatchOperation"}},
+		op.RequestBody.Content[JSON_PATCH_CONTENT_TYPE].Schema)
+	assert.Contains(t, op.Responses["200"].Headers, "ETag")
+	assert.Equal(t, OpenAPISchema{"$ref": "#/components/schemas/Group"}, op.Responses["200"].Content[JSON_MEDIA_TYPE].Schema)
+	assert.Contains(t, op.Responses["404"].Description, api.GROUP_BY_ORG_AND_NAME_NOT_FOUND)
+	assert.Contains(t, op.Responses["409"].Description, api.GROUP_ALREADY_EXIST)
+	assert.Contains(t, op.Responses["412"].Description, api.REVISION_MISMATCH)
+
+	// Conditional requests
+	op = doc.Paths[p]["delete"]
+	assert.Contains(t, op.Parameters, OpenAPIParameter{Name: "If-Match", In: "header", Schema: OpenAPISchema{"type": "string"}})
+	assert.Contains(t, op.Responses, "412")
+	p, _ = openAPIPath(POLICY_ID_DEFAULT_VERSION_URL)
+	op = doc.Paths[p]["put"]
+	assert.Contains(t, op.Parameters, OpenAPIParameter{Name: "If-Match", In: "header", Schema: OpenAPISchema{"type": "string"}})
+	assert.Contains(t, op.Responses, "412")
+	p, _ = openAPIPath(GROUP_ID_USERS_URL)
+	op = doc.Paths[p]["post"]
+	assert.NotContains(t, op.Parameters, OpenAPIParameter{Name: "If-Match", In: "header", Schema: OpenAPISchema{"type": "string"}})
+	assert.NotContains(t, op.Responses, "412")
+
+	// Query parameters
+	op = doc.Paths[USER_ROOT_URL]["get"]
+	assert.Contains(t, op.Parameters, OpenAPIParameter{
+		Name:   "Limit",
+		In:     "query",
+		Schema: OpenAPISchema{"type": "integer", "minimum": 0, "maximum": api.MAX_LIMIT_SIZE},
+	})
+	assert.Contains(t, op.Parameters, OpenAPIParameter{
+		Name:   "CreatedAfter",
+		In:     "query",
+		Schema: OpenAPISchema{"type": "string", "format": "date-time"},
+	})
+
+	// Component schemas
+	assert.Equal(t, OpenAPISchema{
+		"type": "object",
+		"properties": map[string]OpenAPISchema{
+			"code":    {"type": "string"},
+			"message": {"type": "string"},
+			"details": {"type": "array", "items": OpenAPISchema{"$ref": "#/components/schemas/ErrorDetail"}},
+		},
+	}, doc.Components.Schemas["Error"])
+	user := doc.Components.Schemas["User"]["properties"].(map[string]OpenAPISchema)
+	assert.Equal(t, OpenAPISchema{"type": "string", "format": "date-time"}, user["createAt"])
+}
+
+func TestOpenAPISummary(t *testing.T) {
+	testcases := map[string]struct {
+		name     string
+		expected string
+	}{
+		"OkCaseWords": {
+			name:     "ListAttachedGroupPolicies",
+			expected: "List attached group policies",
+		},
+		"OkCaseAcronymAtEnd": {
+			name:     "GetUserByExternalID",
+			expected: "Get user by external ID",
+		},
+		"OkCaseAcronymInside": {
+			name:     "GetOpenAPIDocument",
+			expected: "Get open API document",
+		},
+	}
+
+	for n, test := range testcases {
+		assert.Equal(t, test.expected, openAPISummary(test.name), "Error in test case %v", n)
+	}
+}
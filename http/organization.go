@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/http/types"
+	"github.com/julienschmidt/httprouter"
+)
+
+// REQUESTS
+
+type CreateOrganizationRequest = types.CreateOrganizationRequest
+
+type UpdateOrganizationRequest = types.UpdateOrganizationRequest
+
+// RESPONSES
+
+type ListOrganizationsResponse = types.ListOrganizationsResponse
+
+// HANDLERS
+
+func (wh *WorkerHandler) HandleAddOrganization(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Process request
+	request := &CreateOrganizationRequest{}
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, nil, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call organization API to create the organization
+	response, err := wh.worker.OrganizationApi.AddOrganization(requestInfo, request.Name, request.Path)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusCreated)
+}
+
+func (wh *WorkerHandler) HandleGetOrganizationByName(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call organization API to retrieve the organization
+	response, err := wh.worker.OrganizationApi.GetOrganizationByName(requestInfo, filterData.Org)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleListOrganizations(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call organization API to list the organizations
+	result, total, err := wh.worker.OrganizationApi.ListOrganizations(requestInfo, filterData)
+	// Create response
+	response := &ListOrganizationsResponse{
+		Organizations: result,
+		Offset:        filterData.Offset,
+		Limit:         filterData.Limit,
+		Total:         total,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleUpdateOrganization(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &UpdateOrganizationRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call organization API to update the organization
+	response, err := wh.worker.OrganizationApi.UpdateOrganization(requestInfo, filterData.Org, request.Path)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleRemoveOrganization(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call organization API to delete the organization with all its resources
+	err := wh.worker.OrganizationApi.RemoveOrganization(requestInfo, filterData.Org)
+	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
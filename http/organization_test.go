@@ -0,0 +1,563 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_HandleAddOrganization(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		request *CreateOrganizationRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Organization
+		expectedError      api.Error
+		// Manager Results
+		addOrganizationResult *api.Organization
+		// Manager Errors
+		addOrganizationErr error
+	}{
+		"OkCase": {
+			request: &CreateOrganizationRequest{
+				Name: "org1",
+				Path: "/path/",
+			},
+			addOrganizationResult: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseOrganizationAlreadyExists": {
+			request: &CreateOrganizationRequest{
+				Name: "org1",
+				Path: "/path/",
+			},
+			addOrganizationErr: &api.Error{
+				Code: api.ORGANIZATION_ALREADY_EXIST,
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code: api.ORGANIZATION_ALREADY_EXIST,
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			request: &CreateOrganizationRequest{
+				Name: "org1",
+				Path: "/path/",
+			},
+			addOrganizationErr: &api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			request: &CreateOrganizationRequest{
+				Name: "org1",
+				Path: "/path/",
+			},
+			addOrganizationErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[AddOrganizationMethod][0] = test.addOrganizationResult
+		testApi.ArgsOut[AddOrganizationMethod][1] = test.addOrganizationErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+ORGANIZATION_ROOT_URL, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.request.Name, testApi.ArgsIn[AddOrganizationMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.request.Path, testApi.ArgsIn[AddOrganizationMethod][2], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusCreated:
+			response := api.Organization{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleGetOrganizationByName(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		name string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Organization
+		expectedError      api.Error
+		// Manager Results
+		getOrganizationByNameResult *api.Organization
+		// Manager Errors
+		getOrganizationByNameErr error
+	}{
+		"OkCase": {
+			name: "org1",
+			getOrganizationByNameResult: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/path/", "org1"),
+			},
+		},
+		"ErrorCaseOrganizationNotFound": {
+			name: "org1",
+			getOrganizationByNameErr: &api.Error{
+				Code:    api.ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization not found",
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization not found",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			name: "org1",
+			getOrganizationByNameErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetOrganizationByNameMethod][0] = test.getOrganizationByNameResult
+		testApi.ArgsOut[GetOrganizationByNameMethod][1] = test.getOrganizationByNameErr
+
+		url := fmt.Sprintf(server.URL+ORGANIZATION_ROOT_URL+"/%v", test.name)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.name, testApi.ArgsIn[GetOrganizationByNameMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.Organization{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListOrganizations(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		filter       *api.Filter
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListOrganizationsResponse
+		expectedError      api.Error
+		// Manager Results
+		listOrganizationsResult []string
+		listOrganizationsTotal  int
+		// Manager Errors
+		listOrganizationsErr error
+	}{
+		"OkCase": {
+			filter: &api.Filter{
+				PathPrefix: "/path/",
+				Offset:     0,
+				Limit:      0,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListOrganizationsResponse{
+				Organizations: []string{"org1"},
+				Offset:        0,
+				Limit:         0,
+				Total:         1,
+			},
+			listOrganizationsResult: []string{
+				"org1",
+			},
+			listOrganizationsTotal: 1,
+		},
+		"ErrorCaseInvalidFilterParams": {
+			filter: &api.Filter{
+				PathPrefix: "",
+				Limit:      -1,
+			},
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Limit -1",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter: &api.Filter{
+				PathPrefix: "/path/",
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			listOrganizationsErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			filter:             testFilter,
+			expectedStatusCode: http.StatusInternalServerError,
+			listOrganizationsErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListOrganizationsMethod][0] = test.listOrganizationsResult
+		testApi.ArgsOut[ListOrganizationsMethod][1] = test.listOrganizationsTotal
+		testApi.ArgsOut[ListOrganizationsMethod][2] = test.listOrganizationsErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+ORGANIZATION_ROOT_URL, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			filterData, ok := testApi.ArgsIn[ListOrganizationsMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+		}
+
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			listOrganizationsResponse := ListOrganizationsResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listOrganizationsResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listOrganizationsResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleUpdateOrganization(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		name    string
+		request *UpdateOrganizationRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Organization
+		expectedError      api.Error
+		// Manager Results
+		updateOrganizationResult *api.Organization
+		// Manager Errors
+		updateOrganizationErr error
+	}{
+		"OkCase": {
+			name: "org1",
+			request: &UpdateOrganizationRequest{
+				Path: "/newpath/",
+			},
+			updateOrganizationResult: &api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/newpath/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/newpath/", "org1"),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: api.Organization{
+				ID:       "ORG-ID",
+				Name:     "org1",
+				Path:     "/newpath/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_ORGANIZATION, "/newpath/", "org1"),
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			name:               "org1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseOrganizationNotFound": {
+			name: "org1",
+			request: &UpdateOrganizationRequest{
+				Path: "/newpath/",
+			},
+			updateOrganizationErr: &api.Error{
+				Code:    api.ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization not found",
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization not found",
+			},
+		},
+		"ErrorCaseInvalidParameter": {
+			name: "org1",
+			request: &UpdateOrganizationRequest{
+				Path: "/path/**",
+			},
+			updateOrganizationErr: &api.Error{
+				Code: api.INVALID_PARAMETER_ERROR,
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code: api.INVALID_PARAMETER_ERROR,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[UpdateOrganizationMethod][0] = test.updateOrganizationResult
+		testApi.ArgsOut[UpdateOrganizationMethod][1] = test.updateOrganizationErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL+ORGANIZATION_ROOT_URL+"/%v", test.name)
+		req, err := http.NewRequest(http.MethodPut, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.name, testApi.ArgsIn[UpdateOrganizationMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.request.Path, testApi.ArgsIn[UpdateOrganizationMethod][2], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.Organization{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleRemoveOrganization(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		name string
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		removeOrganizationErr error
+	}{
+		"OkCase": {
+			name:               "org1",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseOrganizationNotFound": {
+			name:               "org1",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization not found",
+			},
+			removeOrganizationErr: &api.Error{
+				Code:    api.ORGANIZATION_BY_NAME_NOT_FOUND,
+				Message: "Organization not found",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			name:               "org1",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			removeOrganizationErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			name:               "org1",
+			expectedStatusCode: http.StatusInternalServerError,
+			removeOrganizationErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RemoveOrganizationMethod][0] = test.removeOrganizationErr
+
+		url := fmt.Sprintf(server.URL+ORGANIZATION_ROOT_URL+"/%v", test.name)
+		req, err := http.NewRequest(http.MethodDelete, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.name, testApi.ArgsIn[RemoveOrganizationMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusNoContent, http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
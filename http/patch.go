@@ -0,0 +1,306 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/api"
+)
+
+const (
+	// Patch content types
+	MERGE_PATCH_CONTENT_TYPE = "application/merge-patch+json"
+	JSON_PATCH_CONTENT_TYPE  = "application/json-patch+json"
+
+	// JSON Patch operations
+	PATCH_OP_ADD     = "add"
+	PATCH_OP_REMOVE  = "remove"
+	PATCH_OP_REPLACE = "replace"
+	PATCH_OP_MOVE    = "move"
+	PATCH_OP_COPY    = "copy"
+	PATCH_OP_TEST    = "test"
+)
+
+// PatchOperation is a JSON Patch (RFC 6902) operation
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// applyPatch applies the patch in request body over the update request, that must be filled
+// with current entity values. Patch format is selected by the request content type, using JSON Merge
+// Patch (RFC 7396) for merge patch and plain JSON content types and JSON Patch (RFC 6902) for JSON patch ones.
+func applyPatch(r *http.Request, requestInfo api.RequestInfo, request interface{}) *api.Error {
+	patched, err := patchDocument(r, request)
+	if err == nil {
+		// Patched document must match the schema of the patch request
+		if apiError := validateRequestDocument(r, patched); apiError != nil {
+			api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, apiError)
+			return apiError
+		}
+		// Decode patched document over an empty request, so removed fields are left empty
+		value := reflect.ValueOf(request).Elem()
+		value.Set(reflect.Zero(value.Type()))
+		err = json.Unmarshal(patched, request)
+	}
+	if err != nil {
+		apiError := requestBodyError(err)
+		api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, apiError)
+		return apiError
+	}
+	return nil
+}
+
+func patchDocument(r *http.Request, request interface{}) ([]byte, error) {
+	current, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	var document interface{}
+	if err := json.Unmarshal(current, &document); err != nil {
+		return nil, err
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case MERGE_PATCH_CONTENT_TYPE, "application/json", "":
+		var patch interface{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			return nil, err
+		}
+		document = mergePatch(document, patch)
+	case JSON_PATCH_CONTENT_TYPE:
+		operations := []PatchOperation{}
+		if err := json.NewDecoder(r.Body).Decode(&operations); err != nil {
+			return nil, err
+		}
+		if document, err = jsonPatch(document, operations); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Invalid parameter: Content-Type %v", contentType)
+	}
+
+	return json.Marshal(document)
+}
+
+// mergePatch applies a JSON Merge Patch (RFC 7396) over target document
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+// jsonPatch applies the JSON Patch (RFC 6902) operations over document in order,
+// failing without partial results if any of them can't be applied
+func jsonPatch(document interface{}, operations []PatchOperation) (interface{}, error) {
+	for _, operation := range operations {
+		path, err := parsePointer(operation.Path)
+		if err != nil {
+			return nil, err
+		}
+		switch operation.Op {
+		case PATCH_OP_ADD:
+			document, err = pointerAdd(document, path, operation.Value)
+		case PATCH_OP_REMOVE:
+			document, _, err = pointerRemove(document, path)
+		case PATCH_OP_REPLACE:
+			if document, _, err = pointerRemove(document, path); err == nil {
+				document, err = pointerAdd(document, path, operation.Value)
+			}
+		case PATCH_OP_MOVE, PATCH_OP_COPY:
+			from, fromErr := parsePointer(operation.From)
+			if fromErr != nil {
+				return nil, fromErr
+			}
+			var value interface{}
+			if operation.Op == PATCH_OP_MOVE {
+				document, value, err = pointerRemove(document, from)
+			} else if value, err = pointerGet(document, from); err == nil {
+				value, err = copyValue(value)
+			}
+			if err == nil {
+				document, err = pointerAdd(document, path, value)
+			}
+		case PATCH_OP_TEST:
+			var value interface{}
+			if value, err = pointerGet(document, path); err == nil && !reflect.DeepEqual(value, operation.Value) {
+				err = fmt.Errorf("Value in path %v isn't the expected one", operation.Path)
+			}
+		default:
+			err = fmt.Errorf("Invalid parameter: op %v", operation.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid patch operation %v in path %v: %v", operation.Op, operation.Path, err.Error())
+		}
+	}
+	return document, nil
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("Invalid parameter: path %v", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+func pointerGet(document interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch container := document.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("member %v not found", token)
+			}
+			document = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			document = container[index]
+		default:
+			return nil, fmt.Errorf("member %v not found", token)
+		}
+	}
+	return document, nil
+}
+
+func pointerAdd(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token := path[0]
+	switch container := document.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			container[token] = value
+			return container, nil
+		}
+		child, ok := container[token]
+		if !ok {
+			return nil, fmt.Errorf("member %v not found", token)
+		}
+		child, err := pointerAdd(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []interface{}:
+		if len(path) == 1 {
+			if token == "-" {
+				return append(container, value), nil
+			}
+			index, err := arrayIndex(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		}
+		index, err := arrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		child, err := pointerAdd(container[index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = child
+		return container, nil
+	default:
+		return nil, fmt.Errorf("member %v not found", token)
+	}
+}
+
+func pointerRemove(document interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, document, nil
+	}
+	token := path[0]
+	switch container := document.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("member %v not found", token)
+		}
+		if len(path) == 1 {
+			delete(container, token)
+			return container, child, nil
+		}
+		child, removed, err := pointerRemove(child, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = child
+		return container, removed, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(path) == 1 {
+			removed := container[index]
+			return append(container[:index], container[index+1:]...), removed, nil
+		}
+		child, removed, err := pointerRemove(container[index], path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[index] = child
+		return container, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("member %v not found", token)
+	}
+}
+
+// arrayIndex parses an array reference token, that must be between 0 and max
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("index %v out of bounds", token)
+	}
+	return index, nil
+}
+
+func copyValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var copied interface{}
+	err = json.Unmarshal(data, &copied)
+	return copied, err
+}
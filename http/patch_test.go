@@ -0,0 +1,128 @@
+package http
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	testcases := map[string]struct {
+		target   string
+		patch    string
+		expected string
+	}{
+		"OkCaseReplaceMember": {
+			target:   `{"name":"policy","path":"/path/"}`,
+			patch:    `{"path":"/newpath/"}`,
+			expected: `{"name":"policy","path":"/newpath/"}`,
+		},
+		"OkCaseRemoveMember": {
+			target:   `{"name":"policy","path":"/path/"}`,
+			patch:    `{"path":null}`,
+			expected: `{"name":"policy"}`,
+		},
+		"OkCaseNestedObject": {
+			target:   `{"name":"resource","resource":{"host":"http://host.com","method":"GET"}}`,
+			patch:    `{"resource":{"method":"POST","action":"example:post"}}`,
+			expected: `{"name":"resource","resource":{"host":"http://host.com","method":"POST","action":"example:post"}}`,
+		},
+		"OkCaseReplaceArray": {
+			target:   `{"clients":["client1","client2"]}`,
+			patch:    `{"clients":["client3"]}`,
+			expected: `{"clients":["client3"]}`,
+		},
+		"OkCaseReplaceDocument": {
+			target:   `{"name":"policy"}`,
+			patch:    `["value"]`,
+			expected: `["value"]`,
+		},
+	}
+
+	for n, test := range testcases {
+		var target, patch, expected interface{}
+		assert.Nil(t, json.Unmarshal([]byte(test.target), &target), "Error in test case %v", n)
+		assert.Nil(t, json.Unmarshal([]byte(test.patch), &patch), "Error in test case %v", n)
+		assert.Nil(t, json.Unmarshal([]byte(test.expected), &expected), "Error in test case %v", n)
+
+		assert.Equal(t, expected, mergePatch(target, patch), "Error in test case %v", n)
+	}
+}
+
+func TestJsonPatch(t *testing.T) {
+	testcases := map[string]struct {
+		document   string
+		operations string
+		// Expected result
+		expected     string
+		errorMessage string
+	}{
+		"OkCaseAppendStatement": {
+			document:   `{"statements":[{"effect":"allow"}]}`,
+			operations: `[{"op":"add","path":"/statements/-","value":{"effect":"deny"}}]`,
+			expected:   `{"statements":[{"effect":"allow"},{"effect":"deny"}]}`,
+		},
+		"OkCaseInsertStatement": {
+			document:   `{"statements":[{"effect":"allow"}]}`,
+			operations: `[{"op":"add","path":"/statements/0","value":{"effect":"deny"}}]`,
+			expected:   `{"statements":[{"effect":"deny"},{"effect":"allow"}]}`,
+		},
+		"OkCaseRemoveAndReplace": {
+			document: `{"name":"policy","path":"/path/","statements":[{"effect":"allow"},{"effect":"deny"}]}`,
+			operations: `[{"op":"remove","path":"/statements/0"},` +
+				`{"op":"replace","path":"/statements/0/effect","value":"allow"},` +
+				`{"op":"replace","path":"/path","value":"/newpath/"}]`,
+			expected: `{"name":"policy","path":"/newpath/","statements":[{"effect":"allow"}]}`,
+		},
+		"OkCaseMoveCopyAndTest": {
+			document: `{"a~b":{"c/d":"value"},"list":[]}`,
+			operations: `[{"op":"test","path":"/a~0b/c~1d","value":"value"},` +
+				`{"op":"copy","from":"/a~0b/c~1d","path":"/list/-"},` +
+				`{"op":"move","from":"/a~0b","path":"/moved"}]`,
+			expected: `{"list":["value"],"moved":{"c/d":"value"}}`,
+		},
+		"ErrorCaseTestFailed": {
+			document:     `{"path":"/path/"}`,
+			operations:   `[{"op":"replace","path":"/path","value":"/newpath/"},{"op":"test","path":"/path","value":"/path/"}]`,
+			errorMessage: "Invalid patch operation test in path /path: Value in path /path isn't the expected one",
+		},
+		"ErrorCaseMemberNotFound": {
+			document:     `{"path":"/path/"}`,
+			operations:   `[{"op":"replace","path":"/name","value":"name"}]`,
+			errorMessage: "Invalid patch operation replace in path /name: member name not found",
+		},
+		"ErrorCaseIndexOutOfBounds": {
+			document:     `{"statements":[]}`,
+			operations:   `[{"op":"add","path":"/statements/1","value":{}}]`,
+			errorMessage: "Invalid patch operation add in path /statements/1: index 1 out of bounds",
+		},
+		"ErrorCaseInvalidOperation": {
+			document:     `{"path":"/path/"}`,
+			operations:   `[{"op":"update","path":"/path","value":"/newpath/"}]`,
+			errorMessage: "Invalid patch operation update in path /path: Invalid parameter: op update",
+		},
+		"ErrorCaseInvalidPointer": {
+			document:     `{"path":"/path/"}`,
+			operations:   `[{"op":"remove","path":"path"}]`,
+			errorMessage: "Invalid parameter: path path",
+		},
+	}
+
+	for n, test := range testcases {
+		var document interface{}
+		operations := []PatchOperation{}
+		assert.Nil(t, json.Unmarshal([]byte(test.document), &document), "Error in test case %v", n)
+		assert.Nil(t, json.Unmarshal([]byte(test.operations), &operations), "Error in test case %v", n)
+
+		patched, err := jsonPatch(document, operations)
+		if test.errorMessage != "" {
+			assert.EqualError(t, err, test.errorMessage, "Error in test case %v", n)
+			continue
+		}
+		assert.Nil(t, err, "Error in test case %v", n)
+		var expected interface{}
+		assert.Nil(t, json.Unmarshal([]byte(test.expected), &expected), "Error in test case %v", n)
+		assert.Equal(t, expected, patched, "Error in test case %v", n)
+	}
+}
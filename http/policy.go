@@ -1,48 +1,32 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
 	"github.com/julienschmidt/httprouter"
 )
 
 // REQUESTS
 
-type CreatePolicyRequest struct {
-	Name       string          `json:"name,omitempty"`
-	Path       string          `json:"path,omitempty"`
-	Statements []api.Statement `json:"statements,omitempty"`
-}
+type CreatePolicyRequest = types.CreatePolicyRequest
 
-type UpdatePolicyRequest struct {
-	Name       string          `json:"name,omitempty"`
-	Path       string          `json:"path,omitempty"`
-	Statements []api.Statement `json:"statements,omitempty"`
-}
+type UpdatePolicyRequest = types.UpdatePolicyRequest
+
+type SetDefaultPolicyVersionRequest = types.SetDefaultPolicyVersionRequest
 
 // RESPONSES
 
-type ListPoliciesResponse struct {
-	Policies []string `json:"policies,omitempty"`
-	Limit    int      `json:"limit"`
-	Offset   int      `json:"offset"`
-	Total    int      `json:"total"`
-}
+type ListPoliciesResponse = types.ListPoliciesResponse
 
-type ListAllPoliciesResponse struct {
-	Policies []api.PolicyIdentity `json:"policies,omitempty"`
-	Limit    int                  `json:"limit"`
-	Offset   int                  `json:"offset"`
-	Total    int                  `json:"total"`
-}
+type ListAllPoliciesResponse = types.ListAllPoliciesResponse
 
-type ListAttachedGroupsResponse struct {
-	Groups []api.PolicyGroups `json:"groups,omitempty"`
-	Limit  int                `json:"limit"`
-	Offset int                `json:"offset"`
-	Total  int                `json:"total"`
-}
+type ListAttachedGroupsResponse = types.ListAttachedGroupsResponse
+
+type ListPolicyVersionsResponse = types.ListPolicyVersionsResponse
 
 // HANDLERS
 
@@ -70,6 +54,9 @@ func (wh *WorkerHandler) HandleGetPolicyByName(w http.ResponseWriter, r *http.Re
 
 	// Call policy API to retrieve policy
 	response, err := wh.worker.PolicyApi.GetPolicyByName(requestInfo, filterData.Org, filterData.PolicyName)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -81,17 +68,18 @@ func (wh *WorkerHandler) HandleListPolicies(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	// Call policy API to list policies
-	result, total, err := wh.worker.PolicyApi.ListPolicies(requestInfo, filterData)
+	result, total, nextToken, err := wh.worker.PolicyApi.ListPolicies(requestInfo, filterData)
 	// Create response
 	policies := []string{}
 	for _, policy := range result {
 		policies = append(policies, policy.Name)
 	}
 	response := &ListPoliciesResponse{
-		Policies: policies,
-		Offset:   filterData.Offset,
-		Limit:    filterData.Limit,
-		Total:    total,
+		Policies:  policies,
+		Offset:    filterData.Offset,
+		Limit:     filterData.Limit,
+		Total:     total,
+		NextToken: nextToken,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -104,13 +92,14 @@ func (wh *WorkerHandler) HandleListAllPolicies(w http.ResponseWriter, r *http.Re
 		return
 	}
 	// Call policy API to list all policies
-	result, total, err := wh.worker.PolicyApi.ListPolicies(requestInfo, filterData)
+	result, total, nextToken, err := wh.worker.PolicyApi.ListPolicies(requestInfo, filterData)
 	// Create response
 	response := &ListAllPoliciesResponse{
-		Policies: result,
-		Offset:   filterData.Offset,
-		Limit:    filterData.Limit,
-		Total:    total,
+		Policies:  result,
+		Offset:    filterData.Offset,
+		Limit:     filterData.Limit,
+		Total:     total,
+		NextToken: nextToken,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -125,6 +114,45 @@ func (wh *WorkerHandler) HandleUpdatePolicy(w http.ResponseWriter, r *http.Reque
 	}
 	// Call policy API to update policy
 	response, err := wh.worker.PolicyApi.UpdatePolicy(requestInfo, filterData.Org, filterData.PolicyName, request.Name, request.Path, request.Statements)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandlePatchPolicy(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call policy API to retrieve the policy to patch
+	policy, err := wh.worker.PolicyApi.GetPolicyByName(requestInfo, filterData.Org, filterData.PolicyName)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusOK)
+		return
+	}
+	request := &UpdatePolicyRequest{
+		Name: policy.Name,
+		Path: policy.Path,
+	}
+	if policy.Statements != nil {
+		request.Statements = *policy.Statements
+	}
+	if apiErr := applyPatch(r, requestInfo, request); apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Patched policy is only updated over the retrieved revision
+	if requestInfo.Revision == 0 {
+		requestInfo.Revision = policy.Revision
+	}
+	// Call policy API to update policy
+	response, err := wh.worker.PolicyApi.UpdatePolicy(requestInfo, filterData.Org, filterData.PolicyName, request.Name, request.Path, request.Statements)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -140,6 +168,19 @@ func (wh *WorkerHandler) HandleRemovePolicy(w http.ResponseWriter, r *http.Reque
 	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
 }
 
+func (wh *WorkerHandler) HandleRestorePolicy(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call policy API to restore deleted policy
+	response, err := wh.worker.PolicyApi.RestorePolicy(requestInfo, filterData.Org, filterData.PolicyName)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
 func (wh *WorkerHandler) HandleListAttachedGroups(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Process request
 	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
@@ -148,13 +189,101 @@ func (wh *WorkerHandler) HandleListAttachedGroups(w http.ResponseWriter, r *http
 		return
 	}
 	// Call policy API to list attached groups
-	result, total, err := wh.worker.PolicyApi.ListAttachedGroups(requestInfo, filterData)
+	result, total, nextToken, err := wh.worker.PolicyApi.ListAttachedGroups(requestInfo, filterData)
 	// Create response
 	response := &ListAttachedGroupsResponse{
-		Groups: result,
-		Offset: filterData.Offset,
-		Limit:  filterData.Limit,
-		Total:  total,
+		Groups:    result,
+		Offset:    filterData.Offset,
+		Limit:     filterData.Limit,
+		Total:     total,
+		NextToken: nextToken,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
+
+func (wh *WorkerHandler) HandleListPolicyVersions(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call policy API to list policy versions
+	result, total, err := wh.worker.PolicyApi.ListPolicyVersions(requestInfo, filterData)
+	// Create response
+	response := &ListPolicyVersionsResponse{
+		Versions: result,
+		Offset:   filterData.Offset,
+		Limit:    filterData.Limit,
+		Total:    total,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleGetPolicyVersion(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	version, apiErr := getVersionParam("version", ps.ByName(POLICY_VERSION))
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call policy API to retrieve policy version
+	response, err := wh.worker.PolicyApi.GetPolicyVersion(requestInfo, filterData.Org, filterData.PolicyName, version)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleDiffPolicyVersions(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	fromVersion, apiErr := getVersionParam("From", r.URL.Query().Get("From"))
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	toVersion, apiErr := getVersionParam("To", r.URL.Query().Get("To"))
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call policy API to compare policy versions
+	response, err := wh.worker.PolicyApi.DiffPolicyVersions(requestInfo, filterData.Org, filterData.PolicyName, fromVersion, toVersion)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleSetDefaultPolicyVersion(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &SetDefaultPolicyVersionRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call policy API to set default policy version
+	response, err := wh.worker.PolicyApi.SetDefaultPolicyVersion(requestInfo, filterData.Org, filterData.PolicyName, request.Version)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+// Private Helper Methods
+
+func getVersionParam(name string, value string) (int, *api.Error) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: %v %v", name, value),
+		}
+	}
+	return version, nil
+}
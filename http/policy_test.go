@@ -539,7 +539,7 @@ func TestWorkerHandler_HandleListPolicies(t *testing.T) {
 
 		testApi.ArgsOut[ListPoliciesMethod][0] = test.getPolicyListResult
 		testApi.ArgsOut[ListPoliciesMethod][1] = test.totalPoliciesResult
-		testApi.ArgsOut[ListPoliciesMethod][2] = test.getPolicyListErr
+		testApi.ArgsOut[ListPoliciesMethod][3] = test.getPolicyListErr
 
 		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/policies", test.filter.Org)
 		req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -685,7 +685,7 @@ func TestWorkerHandler_HandleListAllPolicies(t *testing.T) {
 
 		testApi.ArgsOut[ListPoliciesMethod][0] = test.getPolicyListResult
 		testApi.ArgsOut[ListPoliciesMethod][1] = test.totalGroupsResult
-		testApi.ArgsOut[ListPoliciesMethod][2] = test.getPolicyListErr
+		testApi.ArgsOut[ListPoliciesMethod][3] = test.getPolicyListErr
 
 		url := fmt.Sprintf(server.URL + API_VERSION_1 + "/policies")
 		req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -982,6 +982,188 @@ func TestWorkerHandler_HandleUpdatePolicy(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandlePatchPolicy(t *testing.T) {
+	now := time.Now().UTC()
+	policy := &api.Policy{
+		ID:       "test1",
+		Name:     "policy1",
+		Path:     "/path/",
+		Org:      "org1",
+		CreateAt: now,
+		UpdateAt: now,
+		Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "policy1"),
+		Revision: 3,
+		Statements: &[]api.Statement{
+			{
+				Effect: "allow",
+				Actions: []string{
+					api.USER_ACTION_GET_USER,
+				},
+				Resources: []string{
+					api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+				},
+			},
+		},
+	}
+	testcases := map[string]struct {
+		// API method args
+		contentType string
+		ifMatch     string
+		patch       string
+		// Expected result
+		expectedStatusCode int
+		expectedName       string
+		expectedPath       string
+		expectedStatements []api.Statement
+		expectedRevision   int
+		expectedError      api.Error
+		// Manager Results
+		getPolicyByNameResult *api.Policy
+		updatePolicyResult    *api.Policy
+		// Manager Errors
+		getPolicyByNameErr error
+		updatePolicyErr    error
+	}{
+		"OkCaseMergePatch": {
+			contentType:           MERGE_PATCH_CONTENT_TYPE,
+			patch:                 `{"path":"/newpath/"}`,
+			expectedStatusCode:    http.StatusOK,
+			expectedName:          "policy1",
+			expectedPath:          "/newpath/",
+			expectedStatements:    *policy.Statements,
+			expectedRevision:      3,
+			getPolicyByNameResult: policy,
+			updatePolicyResult:    policy,
+		},
+		"OkCaseJsonPatch": {
+			contentType:        JSON_PATCH_CONTENT_TYPE,
+			ifMatch:            "\"3\"",
+			patch:              `[{"op":"add","path":"/statements/-","value":{"effect":"deny","actions":["iam:DeleteUser"],"resources":["urn:iws:iam::user/path/*"]}}]`,
+			expectedStatusCode: http.StatusOK,
+			expectedName:       "policy1",
+			expectedPath:       "/path/",
+			expectedStatements: []api.Statement{
+				(*policy.Statements)[0],
+				{
+					Effect: "deny",
+					Actions: []string{
+						api.USER_ACTION_DELETE_USER,
+					},
+					Resources: []string{
+						api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					},
+				},
+			},
+			expectedRevision:      3,
+			getPolicyByNameResult: policy,
+			updatePolicyResult:    policy,
+		},
+		"ErrorCasePolicyNotFound": {
+			contentType:        MERGE_PATCH_CONTENT_TYPE,
+			patch:              `{"path":"/newpath/"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+			getPolicyByNameErr: &api.Error{
+				Code:    api.POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+		},
+		"ErrorCaseInvalidContentType": {
+			contentType:        "text/plain",
+			patch:              `{"path":"/newpath/"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Content-Type text/plain",
+			},
+			getPolicyByNameResult: policy,
+		},
+		"ErrorCaseInvalidPatch": {
+			contentType:        JSON_PATCH_CONTENT_TYPE,
+			patch:              `[{"op":"remove","path":"/statements/1"}]`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid patch operation remove in path /statements/1: index 1 out of bounds",
+			},
+			getPolicyByNameResult: policy,
+		},
+		"ErrorCaseRevisionMismatch": {
+			contentType:        MERGE_PATCH_CONTENT_TYPE,
+			ifMatch:            "\"2\"",
+			patch:              `{"path":"/newpath/"}`,
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedName:       "policy1",
+			expectedPath:       "/newpath/",
+			expectedStatements: *policy.Statements,
+			expectedRevision:   2,
+			expectedError: api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "Revision 2 required by request isn't the current revision 3",
+			},
+			getPolicyByNameResult: policy,
+			updatePolicyErr: &api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "Revision 2 required by request isn't the current revision 3",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetPolicyByNameMethod][0] = test.getPolicyByNameResult
+		testApi.ArgsOut[GetPolicyByNameMethod][1] = test.getPolicyByNameErr
+		testApi.ArgsOut[UpdatePolicyMethod][0] = test.updatePolicyResult
+		testApi.ArgsOut[UpdatePolicyMethod][1] = test.updatePolicyErr
+
+		url := fmt.Sprintf(server.URL + API_VERSION_1 + "/organizations/org1/policies/policy1")
+		req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(test.patch))
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", test.contentType)
+		if test.ifMatch != "" {
+			req.Header.Set(IF_MATCH_HEADER, test.ifMatch)
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.expectedRevision != 0 {
+			// Check patched parameters
+			requestInfo := testApi.ArgsIn[UpdatePolicyMethod][0].(api.RequestInfo)
+			assert.Equal(t, test.expectedRevision, requestInfo.Revision, "Error in test case %v", n)
+			assert.Equal(t, "org1", testApi.ArgsIn[UpdatePolicyMethod][1], "Error in test case %v", n)
+			assert.Equal(t, "policy1", testApi.ArgsIn[UpdatePolicyMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.expectedName, testApi.ArgsIn[UpdatePolicyMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.expectedPath, testApi.ArgsIn[UpdatePolicyMethod][4], "Error in test case %v", n)
+			assert.Equal(t, test.expectedStatements, testApi.ArgsIn[UpdatePolicyMethod][5], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &api.Policy{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.updatePolicyResult, response, "Error in test case %v", n)
+			assert.Equal(t, "\"3\"", res.Header.Get(ETAG_HEADER), "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleRemovePolicy(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
@@ -1103,6 +1285,134 @@ func TestWorkerHandler_HandleRemovePolicy(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandleRestorePolicy(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		org  string
+		name string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *api.Policy
+		expectedError      api.Error
+		// Manager Results
+		restorePolicyResult *api.Policy
+		// Manager Errors
+		restorePolicyErr error
+	}{
+		"OkCase": {
+			org:                "org1",
+			name:               "policy1",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.Policy{
+				ID:       "PolicyID",
+				Name:     "policy1",
+				Path:     "Path",
+				Urn:      "urn",
+				Org:      "org1",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			restorePolicyResult: &api.Policy{
+				ID:       "PolicyID",
+				Name:     "policy1",
+				Path:     "Path",
+				Urn:      "urn",
+				Org:      "org1",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"ErrorCasePolicyAlreadyExist": {
+			org:                "org1",
+			name:               "policy1",
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code:    api.POLICY_ALREADY_EXIST,
+				Message: "Policy already exist",
+			},
+			restorePolicyErr: &api.Error{
+				Code:    api.POLICY_ALREADY_EXIST,
+				Message: "Policy already exist",
+			},
+		},
+		"ErrorCasePolicyNotFound": {
+			org:                "org1",
+			name:               "policy1",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Deleted policy not found",
+			},
+			restorePolicyErr: &api.Error{
+				Code:    api.POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Deleted policy not found",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			org:                "org1",
+			name:               "policy1",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			restorePolicyErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:                "org1",
+			name:               "policy1",
+			expectedStatusCode: http.StatusInternalServerError,
+			restorePolicyErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RestorePolicyMethod][0] = test.restorePolicyResult
+		testApi.ArgsOut[RestorePolicyMethod][1] = test.restorePolicyErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/policies/%v/restore", test.org, test.name)
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.org, testApi.ArgsIn[RestorePolicyMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.name, testApi.ArgsIn[RestorePolicyMethod][2], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &api.Policy{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleListAttachedGroups(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -1223,7 +1533,7 @@ func TestWorkerHandler_HandleListAttachedGroups(t *testing.T) {
 
 		testApi.ArgsOut[ListAttachedGroupsMethod][0] = test.getPolicyGroupsResult
 		testApi.ArgsOut[ListAttachedGroupsMethod][1] = test.totalGroupsResult
-		testApi.ArgsOut[ListAttachedGroupsMethod][2] = test.getPolicyGroupsErr
+		testApi.ArgsOut[ListAttachedGroupsMethod][3] = test.getPolicyGroupsErr
 
 		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/policies/%v/groups", test.filter.Org, test.filter.PolicyName)
 		req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -1264,3 +1574,465 @@ func TestWorkerHandler_HandleListAttachedGroups(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkerHandler_HandleListPolicyVersions(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		org        string
+		policyName string
+		offset     string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListPolicyVersionsResponse
+		expectedError      api.Error
+		// Manager Results
+		listPolicyVersionsResult []api.PolicyVersion
+		totalResult              int
+		// Manager Errors
+		listPolicyVersionsErr error
+	}{
+		"OkCase": {
+			org:                "org1",
+			policyName:         "p1",
+			expectedStatusCode: http.StatusOK,
+			listPolicyVersionsResult: []api.PolicyVersion{
+				{
+					ID:       "v1",
+					Version:  1,
+					Author:   "user1",
+					CreateAt: now,
+					Default:  true,
+				},
+			},
+			totalResult: 1,
+			expectedResponse: ListPolicyVersionsResponse{
+				Versions: []api.PolicyVersion{
+					{
+						ID:       "v1",
+						Version:  1,
+						Author:   "user1",
+						CreateAt: now,
+						Default:  true,
+					},
+				},
+				Total: 1,
+			},
+		},
+		"ErrorCaseInvalidRequest": {
+			org:                "org1",
+			policyName:         "p1",
+			offset:             "-1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCasePolicyNotFound": {
+			org:                "org1",
+			policyName:         "p1",
+			expectedStatusCode: http.StatusNotFound,
+			listPolicyVersionsErr: &api.Error{
+				Code: api.POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListPolicyVersionsMethod][0] = test.listPolicyVersionsResult
+		testApi.ArgsOut[ListPolicyVersionsMethod][1] = test.totalResult
+		testApi.ArgsOut[ListPolicyVersionsMethod][2] = test.listPolicyVersionsErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/policies/%v/versions", test.org, test.policyName)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		q.Add("Offset", test.offset)
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Check received parameters
+			filter := testApi.ArgsIn[ListPolicyVersionsMethod][1].(*api.Filter)
+			assert.Equal(t, test.org, filter.Org, "Error in test case %v", n)
+			assert.Equal(t, test.policyName, filter.PolicyName, "Error in test case %v", n)
+
+			response := ListPolicyVersionsResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleGetPolicyVersion(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		version string
+		// Expected result
+		expectedStatusCode int
+		expectedVersion    int
+		expectedResponse   api.PolicyVersion
+		expectedError      api.Error
+		// Manager Results
+		getPolicyVersionResult *api.PolicyVersion
+		// Manager Errors
+		getPolicyVersionErr error
+	}{
+		"OkCase": {
+			version:            "2",
+			expectedStatusCode: http.StatusOK,
+			expectedVersion:    2,
+			getPolicyVersionResult: &api.PolicyVersion{
+				ID:       "v2",
+				Version:  2,
+				Author:   "user2",
+				CreateAt: now,
+				Statements: &[]api.Statement{
+					{
+						Effect:    "allow",
+						Actions:   []string{api.USER_ACTION_GET_USER},
+						Resources: []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+					},
+				},
+			},
+			expectedResponse: api.PolicyVersion{
+				ID:       "v2",
+				Version:  2,
+				Author:   "user2",
+				CreateAt: now,
+				Statements: &[]api.Statement{
+					{
+						Effect:    "allow",
+						Actions:   []string{api.USER_ACTION_GET_USER},
+						Resources: []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+					},
+				},
+			},
+		},
+		"ErrorCaseInvalidVersion": {
+			version:            "v2",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: version v2",
+			},
+		},
+		"ErrorCaseVersionNotFound": {
+			version:            "3",
+			expectedStatusCode: http.StatusNotFound,
+			expectedVersion:    3,
+			getPolicyVersionErr: &api.Error{
+				Code: api.POLICY_VERSION_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.POLICY_VERSION_NOT_FOUND,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsIn[GetPolicyVersionMethod][3] = nil
+		testApi.ArgsOut[GetPolicyVersionMethod][0] = test.getPolicyVersionResult
+		testApi.ArgsOut[GetPolicyVersionMethod][1] = test.getPolicyVersionErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/org1/policies/p1/versions/%v", test.version)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.expectedVersion != 0 {
+			// Check received parameters
+			assert.Equal(t, "org1", testApi.ArgsIn[GetPolicyVersionMethod][1], "Error in test case %v", n)
+			assert.Equal(t, "p1", testApi.ArgsIn[GetPolicyVersionMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.expectedVersion, testApi.ArgsIn[GetPolicyVersionMethod][3], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.PolicyVersion{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleDiffPolicyVersions(t *testing.T) {
+	statement := api.Statement{
+		Effect:    "allow",
+		Actions:   []string{api.USER_ACTION_GET_USER},
+		Resources: []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+	}
+	testcases := map[string]struct {
+		// API method args
+		from string
+		to   string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.PolicyVersionDiff
+		expectedError      api.Error
+		// Manager Results
+		diffPolicyVersionsResult *api.PolicyVersionDiff
+		// Manager Errors
+		diffPolicyVersionsErr error
+	}{
+		"OkCase": {
+			from:               "1",
+			to:                 "2",
+			expectedStatusCode: http.StatusOK,
+			diffPolicyVersionsResult: &api.PolicyVersionDiff{
+				FromVersion:       1,
+				ToVersion:         2,
+				AddedStatements:   []api.Statement{statement},
+				RemovedStatements: []api.Statement{},
+			},
+			expectedResponse: api.PolicyVersionDiff{
+				FromVersion:       1,
+				ToVersion:         2,
+				AddedStatements:   []api.Statement{statement},
+				RemovedStatements: []api.Statement{},
+			},
+		},
+		"ErrorCaseMissingFrom": {
+			to:                 "2",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: From ",
+			},
+		},
+		"ErrorCaseInvalidTo": {
+			from:               "1",
+			to:                 "-2",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: To -2",
+			},
+		},
+		"ErrorCaseVersionNotFound": {
+			from:               "1",
+			to:                 "5",
+			expectedStatusCode: http.StatusNotFound,
+			diffPolicyVersionsErr: &api.Error{
+				Code: api.POLICY_VERSION_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.POLICY_VERSION_NOT_FOUND,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[DiffPolicyVersionsMethod][0] = test.diffPolicyVersionsResult
+		testApi.ArgsOut[DiffPolicyVersionsMethod][1] = test.diffPolicyVersionsErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+API_VERSION_1+"/organizations/org1/policies/p1/versions-diff", nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		q.Add("From", test.from)
+		q.Add("To", test.to)
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Check received parameters
+			assert.Equal(t, 1, testApi.ArgsIn[DiffPolicyVersionsMethod][3], "Error in test case %v", n)
+			assert.Equal(t, 2, testApi.ArgsIn[DiffPolicyVersionsMethod][4], "Error in test case %v", n)
+
+			response := api.PolicyVersionDiff{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleSetDefaultPolicyVersion(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		request *SetDefaultPolicyVersionRequest
+		ifMatch string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Policy
+		expectedError      api.Error
+		// Manager Results
+		setDefaultPolicyVersionResult *api.Policy
+		// Manager Errors
+		setDefaultPolicyVersionErr error
+	}{
+		"OkCase": {
+			request: &SetDefaultPolicyVersionRequest{
+				Version: 1,
+			},
+			expectedStatusCode: http.StatusOK,
+			setDefaultPolicyVersionResult: &api.Policy{
+				ID:       "test1",
+				Name:     "p1",
+				Org:      "org1",
+				Version:  1,
+				Revision: 3,
+			},
+			expectedResponse: api.Policy{
+				ID:       "test1",
+				Name:     "p1",
+				Org:      "org1",
+				Version:  1,
+				Revision: 3,
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseVersionNotFound": {
+			request: &SetDefaultPolicyVersionRequest{
+				Version: 3,
+			},
+			expectedStatusCode: http.StatusNotFound,
+			setDefaultPolicyVersionErr: &api.Error{
+				Code: api.POLICY_VERSION_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.POLICY_VERSION_NOT_FOUND,
+			},
+		},
+		"ErrorCaseRevisionMismatch": {
+			request: &SetDefaultPolicyVersionRequest{
+				Version: 1,
+			},
+			ifMatch:            "\"1\"",
+			expectedStatusCode: http.StatusPreconditionFailed,
+			setDefaultPolicyVersionErr: &api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "Revision 1 required by request isn't the current revision 2",
+			},
+			expectedError: api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "Revision 1 required by request isn't the current revision 2",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[SetDefaultPolicyVersionMethod][0] = test.setDefaultPolicyVersionResult
+		testApi.ArgsOut[SetDefaultPolicyVersionMethod][1] = test.setDefaultPolicyVersionErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		req, err := http.NewRequest(http.MethodPut, server.URL+API_VERSION_1+"/organizations/org1/policies/p1/default-version", body)
+		assert.Nil(t, err, "Error in test case %v", n)
+		if test.ifMatch != "" {
+			req.Header.Set(IF_MATCH_HEADER, test.ifMatch)
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, "org1", testApi.ArgsIn[SetDefaultPolicyVersionMethod][1], "Error in test case %v", n)
+			assert.Equal(t, "p1", testApi.ArgsIn[SetDefaultPolicyVersionMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.Version, testApi.ArgsIn[SetDefaultPolicyVersionMethod][3], "Error in test case %v", n)
+			requestInfo := testApi.ArgsIn[SetDefaultPolicyVersionMethod][0].(api.RequestInfo)
+			assert.Equal(t, getIfMatchRevision(req), requestInfo.Revision, "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.Policy{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+			assert.Equal(t, "\"3\"", res.Header.Get(ETAG_HEADER), "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
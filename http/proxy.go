@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
 	"github.com/Tecsisa/foulkon/middleware"
 	"github.com/julienschmidt/httprouter"
 	"github.com/satori/go.uuid"
@@ -26,30 +27,15 @@ const (
 
 // REQUESTS
 
-type CreateProxyResourceRequest struct {
-	Name     string             `json:"name,omitempty"`
-	Path     string             `json:"path,omitempty"`
-	Resource api.ResourceEntity `json:"resource,omitempty"`
-}
+type CreateProxyResourceRequest = types.CreateProxyResourceRequest
 
-type UpdateProxyResourceRequest struct {
-	Name     string             `json:"name,omitempty"`
-	Path     string             `json:"path,omitempty"`
-	Resource api.ResourceEntity `json:"resource,omitempty"`
-}
+type UpdateProxyResourceRequest = types.UpdateProxyResourceRequest
 
 // RESPONSES
 
-type ProxyResources struct {
-	Resources []api.ProxyResource `json:"resources,omitempty"`
-}
+type ProxyResources = types.ProxyResources
 
-type ListProxyResourcesResponse struct {
-	Resources []string `json:"resources,omitempty"`
-	Limit     int      `json:"limit"`
-	Offset    int      `json:"offset"`
-	Total     int      `json:"total"`
-}
+type ListProxyResourcesResponse = types.ListProxyResourcesResponse
 
 var rUrnParam, _ = regexp.Compile(`\{(\w+)\}`)
 
@@ -154,6 +140,9 @@ func (wh *WorkerHandler) HandleGetProxyResourceByName(w http.ResponseWriter, r *
 
 	// Call policy API to retrieve policy
 	response, err := wh.worker.ProxyApi.GetProxyResourceByName(requestInfo, filterData.Org, filterData.ProxyResourceName)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -165,7 +154,7 @@ func (wh *WorkerHandler) HandleListProxyResource(w http.ResponseWriter, r *http.
 		return
 	}
 	// Call proxy Resource API to create proxyResource
-	result, total, err := wh.worker.ProxyApi.ListProxyResources(requestInfo, filterData)
+	result, total, nextToken, err := wh.worker.ProxyApi.ListProxyResources(requestInfo, filterData)
 	proxyResources := []string{}
 	for _, proxyResource := range result {
 		proxyResources = append(proxyResources, proxyResource.Name)
@@ -176,6 +165,7 @@ func (wh *WorkerHandler) HandleListProxyResource(w http.ResponseWriter, r *http.
 		Offset:    filterData.Offset,
 		Limit:     filterData.Limit,
 		Total:     total,
+		NextToken: nextToken,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -190,6 +180,43 @@ func (wh *WorkerHandler) HandleUpdateProxyResource(w http.ResponseWriter, r *htt
 	}
 	// Call proxy resource API to update proxy resource
 	response, err := wh.worker.ProxyApi.UpdateProxyResource(requestInfo, filterData.Org, filterData.ProxyResourceName, request.Name, request.Path, request.Resource)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandlePatchProxyResource(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call proxy resource API to retrieve the proxy resource to patch
+	proxyResource, err := wh.worker.ProxyApi.GetProxyResourceByName(requestInfo, filterData.Org, filterData.ProxyResourceName)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusOK)
+		return
+	}
+	request := &UpdateProxyResourceRequest{
+		Name:     proxyResource.Name,
+		Path:     proxyResource.Path,
+		Resource: proxyResource.Resource,
+	}
+	if apiErr := applyPatch(r, requestInfo, request); apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Patched proxy resource is only updated over the retrieved revision
+	if requestInfo.Revision == 0 {
+		requestInfo.Revision = proxyResource.Revision
+	}
+	// Call proxy resource API to update proxy resource
+	response, err := wh.worker.ProxyApi.UpdateProxyResource(requestInfo, filterData.Org, filterData.ProxyResourceName, request.Name, request.Path, request.Resource)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -287,7 +314,7 @@ func getUrnParameters(urn string) [][]string {
 }
 
 func isFullUrn(resource string) bool {
-	return !strings.ContainsAny(resource, "*")
+	return !strings.ContainsAny(resource, "*?")
 }
 
 func getErrorMessage(errorCode string, message string) *api.Error {
@@ -981,7 +981,7 @@ func TestWorkerHandler_HandleListProxyResource(t *testing.T) {
 
 		testApi.ArgsOut[ListProxyResourcesMethod][0] = test.getProxyResourceListResult
 		testApi.ArgsOut[ListProxyResourcesMethod][1] = test.totalProxyResourcesResult
-		testApi.ArgsOut[ListProxyResourcesMethod][2] = test.getProxyResourceListErr
+		testApi.ArgsOut[ListProxyResourcesMethod][3] = test.getProxyResourceListErr
 
 		path := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/proxy-resources", test.filter.Org)
 		req, err := http.NewRequest(http.MethodGet, path, nil)
@@ -1022,6 +1022,113 @@ func TestWorkerHandler_HandleListProxyResource(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandlePatchProxyResource(t *testing.T) {
+	now := time.Now().UTC()
+	proxyResource := &api.ProxyResource{
+		ID:   "ProxyResourceID",
+		Name: "proxy1",
+		Path: "/path/",
+		Org:  "org1",
+		Urn:  api.CreateUrn("org1", api.RESOURCE_PROXY, "/path/", "proxy1"),
+		Resource: api.ResourceEntity{
+			Host:   "http://host.com",
+			Path:   "/resource",
+			Method: "GET",
+			Urn:    "urn:ews:example:instance1:resource/get",
+			Action: "example:get",
+		},
+		CreateAt: now,
+		UpdateAt: now,
+		Revision: 2,
+	}
+	testcases := map[string]struct {
+		// API method args
+		patch string
+		// Expected result
+		expectedStatusCode int
+		expectedResource   api.ResourceEntity
+		expectedError      api.Error
+		// Manager Errors
+		getProxyResourceByNameErr error
+	}{
+		"OkCase": {
+			patch:              `{"resource":{"method":"POST","action":"example:post"}}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResource: api.ResourceEntity{
+				Host:   "http://host.com",
+				Path:   "/resource",
+				Method: "POST",
+				Urn:    "urn:ews:example:instance1:resource/get",
+				Action: "example:post",
+			},
+		},
+		"ErrorCaseProxyResourceNotFound": {
+			patch:              `{"resource":{"method":"POST","action":"example:post"}}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.PROXY_RESOURCE_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+			getProxyResourceByNameErr: &api.Error{
+				Code:    api.PROXY_RESOURCE_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+		},
+		"ErrorCaseMalformedPatch": {
+			patch:              `{`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "unexpected EOF",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetProxyResourceByNameMethod][0] = proxyResource
+		testApi.ArgsOut[GetProxyResourceByNameMethod][1] = test.getProxyResourceByNameErr
+		testApi.ArgsOut[UpdateProxyResourceMethod][0] = proxyResource
+		testApi.ArgsOut[UpdateProxyResourceMethod][1] = nil
+
+		url := fmt.Sprintf(server.URL + API_VERSION_1 + "/organizations/org1/proxy-resources/proxy1")
+		req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(test.patch))
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", MERGE_PATCH_CONTENT_TYPE)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Check patched parameters
+			requestInfo := testApi.ArgsIn[UpdateProxyResourceMethod][0].(api.RequestInfo)
+			assert.Equal(t, proxyResource.Revision, requestInfo.Revision, "Error in test case %v", n)
+			assert.Equal(t, "org1", testApi.ArgsIn[UpdateProxyResourceMethod][1], "Error in test case %v", n)
+			assert.Equal(t, "proxy1", testApi.ArgsIn[UpdateProxyResourceMethod][2], "Error in test case %v", n)
+			assert.Equal(t, proxyResource.Name, testApi.ArgsIn[UpdateProxyResourceMethod][3], "Error in test case %v", n)
+			assert.Equal(t, proxyResource.Path, testApi.ArgsIn[UpdateProxyResourceMethod][4], "Error in test case %v", n)
+			assert.Equal(t, test.expectedResource, testApi.ArgsIn[UpdateProxyResourceMethod][5], "Error in test case %v", n)
+			response := &api.ProxyResource{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, proxyResource, response, "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleRemoveProxyResource(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
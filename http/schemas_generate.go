@@ -0,0 +1,57 @@
+//go:build ignore
+// +build ignore
+
+// Generates schemas.go with the schema documents of the API, used to validate request bodies
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// Files of the schema directory that aren't schema documents
+var ignoredFiles = map[string]bool{
+	"postman.json": true,
+}
+
+func main() {
+	files, err := filepath.Glob(filepath.Join("..", "schema", "*.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "// Code generated by schemas_generate.go from the schema directory. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package http")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "// Schema documents of the API by file name")
+	fmt.Fprintln(buf, "var schemaDocuments = map[string]string{")
+	for _, file := range files {
+		name := filepath.Base(file)
+		if ignoredFiles[name] {
+			continue
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Raw string literals can't contain backquotes, so they are concatenated as interpreted literals
+		value := "`" + strings.Replace(string(content), "`", "` + \"`\" + `", -1) + "`"
+		fmt.Fprintf(buf, "%q: %v,\n", name, value)
+	}
+	fmt.Fprintln(buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("schemas.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
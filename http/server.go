@@ -26,6 +26,7 @@ type ProxyServer struct {
 	resourceLock sync.Mutex
 	reloadFunc   ReloadHandlerFunc
 	refreshTime  time.Duration
+	watcher      api.ProxyResourcesWatcher
 
 	reloadServe      chan struct{}
 	currentResources []api.ProxyResource
@@ -93,17 +94,7 @@ func (ws *WorkerServer) Configuration() error { return nil }
 
 // Run starts an HTTP ProxyServer
 func (ps *ProxyServer) Run() error {
-	// Call reloadFunc every refreshTime
-	timer := time.NewTicker(ps.refreshTime)
-	// now wait for the other times when we needed to
-	go func() {
-		for range timer.C {
-			// change the handler
-			if ps.reloadFunc(ps) {
-				ps.reloadServe <- struct{}{} // reset the listening binding
-			}
-		}
-	}()
+	go ps.watchResources()
 
 	var err error
 	ln, err := net.Listen("tcp", ps.Addr)
@@ -124,6 +115,34 @@ func (ps *ProxyServer) Run() error {
 	}
 }
 
+// watchResources calls reloadFunc when the watcher notifies a change of resources. Resources are only
+// refreshed every refreshTime without watcher or after it stops, since the watcher notifies its reconnections too
+func (ps *ProxyServer) watchResources() {
+	var changes <-chan struct{}
+	var refresh <-chan time.Time
+	if ps.watcher != nil {
+		changes = ps.watcher.Changes()
+	} else {
+		refresh = time.NewTicker(ps.refreshTime).C
+	}
+	for {
+		select {
+		case _, ok := <-changes:
+			if !ok {
+				api.Log.Warnf("Changes of proxy resources are no longer notified, refreshing them every %v", ps.refreshTime)
+				changes = nil
+				refresh = time.NewTicker(ps.refreshTime).C
+				continue
+			}
+		case <-refresh:
+		}
+		// change the handler
+		if ps.reloadFunc(ps) {
+			ps.reloadServe <- struct{}{} // reset the listening binding
+		}
+	}
+}
+
 // NewProxy returns a new ProxyServer
 func NewProxy(proxy *foulkon.Proxy) Server {
 	// Initialization
@@ -137,6 +156,7 @@ func NewProxy(proxy *foulkon.Proxy) Server {
 
 	ps.Addr = proxy.Host + ":" + proxy.Port
 	ps.refreshTime = proxy.RefreshTime
+	ps.watcher = proxy.ResourcesWatcher
 	ps.reloadFunc = ps.RefreshResources(proxy)
 
 	ps.reloadFunc(ps)
@@ -168,12 +188,12 @@ func (ps *ProxyServer) RefreshResources(proxy *foulkon.Proxy) func(s *ProxyServe
 			return false
 		}
 
+		defer srv.resourceLock.Unlock()
+		srv.resourceLock.Lock()
+
 		if diff := pretty.Compare(srv.currentResources, newProxyResources); diff != "" {
 			router := httprouter.New()
 
-			defer srv.resourceLock.Unlock()
-			srv.resourceLock.Lock()
-
 			// writer lock
 			ps.currentResources = newProxyResources
 
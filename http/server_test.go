@@ -12,6 +12,8 @@ import (
 
 	"strings"
 
+	"sync"
+
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/foulkon"
 	"github.com/julienschmidt/httprouter"
@@ -278,6 +280,7 @@ func TestNewProxy(t *testing.T) {
 			assert.Equal(t, test.proxy.CertFile, ps.certFile, "Error in test case %v", n)
 			assert.Equal(t, test.proxy.KeyFile, ps.keyFile, "Error in test case %v", n)
 			assert.Equal(t, test.proxy.RefreshTime, ps.refreshTime, "Error in test case %v", n)
+			assert.Equal(t, test.proxy.ResourcesWatcher, ps.watcher, "Error in test case %v", n)
 			assert.Equal(t, test.expectedResources, ps.currentResources, "Error in test case %v", n)
 			// Check if panic errors where caught
 			if test.panicError != "" {
@@ -400,7 +403,6 @@ func TestWorkerServer_Run(t *testing.T) {
 }
 
 func TestProxyServer_Run(t *testing.T) {
-	testApi := makeTestApi()
 	testcases := map[string]struct {
 		proxy *foulkon.Proxy
 
@@ -410,7 +412,6 @@ func TestProxyServer_Run(t *testing.T) {
 		"OKCase": {
 			proxy: &foulkon.Proxy{
 				RefreshTime: 1 * time.Millisecond,
-				ProxyApi:    testApi,
 			},
 			expectedResources: []api.ProxyResource{
 				{
@@ -430,13 +431,15 @@ func TestProxyServer_Run(t *testing.T) {
 				Host:        "fail",
 				Port:        "53",
 				RefreshTime: 1 * time.Millisecond,
-				ProxyApi:    testApi,
 			},
 			expectedError: "listen tcp: lookup fail",
 		},
 	}
 	for n, test := range testcases {
 		var err error
+		// Each server keeps reading resources from its own API
+		testApi := makeTestApi()
+		test.proxy.ProxyApi = testApi
 		srv := NewProxy(test.proxy)
 		srv.Configuration()
 
@@ -464,13 +467,132 @@ func TestProxyServer_Run(t *testing.T) {
 			}()
 
 			// Wait reloadFunc
-			time.Sleep(5 * time.Millisecond)
-
 			ps := srv.(*ProxyServer)
+			var currentResources []api.ProxyResource
+			for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+				ps.resourceLock.Lock()
+				currentResources = ps.currentResources
+				ps.resourceLock.Unlock()
+				if len(currentResources) > 0 {
+					break
+				}
+			}
+			assert.Equal(t, test.expectedResources, currentResources, "Error in test case %v", n)
+		}
+	}
+}
 
-			ps.resourceLock.Lock()
-			assert.Equal(t, test.expectedResources, ps.currentResources, "Error in test case %v", n)
-			ps.resourceLock.Unlock()
+// Watcher that notifies changes sent to its channel
+type TestWatcher struct {
+	changes chan struct{}
+}
+
+func (w TestWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w TestWatcher) Close() error {
+	close(w.changes)
+	return nil
+}
+
+func TestProxyServer_RunWithWatcher(t *testing.T) {
+	resources := func(id string) []api.ProxyResource {
+		return []api.ProxyResource{
+			{
+				ID: id,
+				Resource: api.ResourceEntity{
+					Host:   "host",
+					Path:   "/" + id,
+					Method: "GET",
+					Urn:    "urn",
+					Action: "action",
+				},
+			},
+		}
+	}
+
+	testcases := map[string]struct {
+		refreshTime time.Duration
+		// Proxy without watcher
+		withoutWatcher bool
+		// Notify a change instead of waiting for refresh time
+		notify bool
+		// Close watcher before changing resources
+		closeWatcher bool
+		// Resources aren't refreshed while watcher works
+		expectedNotReloaded bool
+	}{
+		"OkCaseChangeNotified": {
+			refreshTime: time.Hour,
+			notify:      true,
+		},
+		"OkCaseChangeNotNotified": {
+			refreshTime:         time.Millisecond,
+			expectedNotReloaded: true,
+		},
+		"OkCaseWithoutWatcher": {
+			refreshTime:    time.Millisecond,
+			withoutWatcher: true,
+		},
+		"OkCaseWatcherClosed": {
+			refreshTime:  time.Millisecond,
+			closeWatcher: true,
+		},
+	}
+
+	for n, test := range testcases {
+		// Resources are read by the watching goroutine
+		var lock sync.Mutex
+		current := resources("ID1")
+		setResources := func(r []api.ProxyResource) {
+			lock.Lock()
+			current = r
+			lock.Unlock()
+		}
+		testApi := makeTestApi()
+		testApi.SpecialFuncs[GetProxyResourcesMethod] = func() ([]api.ProxyResource, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return current, nil
+		}
+
+		watcher := TestWatcher{changes: make(chan struct{})}
+		proxy := &foulkon.Proxy{
+			RefreshTime: test.refreshTime,
+			ProxyApi:    testApi,
+		}
+		if !test.withoutWatcher {
+			proxy.ResourcesWatcher = watcher
+		}
+		ps := NewProxy(proxy).(*ProxyServer)
+		go ps.watchResources()
+
+		if test.closeWatcher {
+			watcher.Close()
+		}
+		setResources(resources("ID2"))
+		if test.notify {
+			watcher.changes <- struct{}{}
+		}
+
+		if test.expectedNotReloaded {
+			select {
+			case <-ps.reloadServe:
+				t.Errorf("Error in test case %v: resources were reloaded without notification", n)
+			case <-time.After(50 * time.Millisecond):
+			}
+			continue
+		}
+
+		// Wait reload
+		select {
+		case <-ps.reloadServe:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Error in test case %v: resources weren't reloaded", n)
 		}
+		ps.resourceLock.Lock()
+		assert.Equal(t, resources("ID2"), ps.currentResources, "Error in test case %v", n)
+		ps.resourceLock.Unlock()
 	}
 }
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/julienschmidt/httprouter"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// Media type used to export and import state documents as YAML
+	YAML_MEDIA_TYPE = "application/x-yaml"
+)
+
+// HANDLERS
+
+func (wh *WorkerHandler) HandleExportState(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call state API to export the IAM state
+	response, err := wh.worker.StateApi.ExportState(requestInfo)
+	wh.processStateResponse(r, w, requestInfo, response, err)
+}
+
+func (wh *WorkerHandler) HandleImportState(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &api.State{}
+	requestInfo, apiErr := wh.processStateRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve import options
+	validateOnly, apiErr := getBoolParam(r, "ValidateOnly")
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call state API to import the IAM state
+	response, err := wh.worker.StateApi.ImportState(requestInfo, request, r.URL.Query().Get("Mode"), validateOnly)
+	wh.processStateResponse(r, w, requestInfo, response, err)
+}
+
+func (wh *WorkerHandler) HandleReconcilePlan(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	wh.handleReconcile(w, r, ps, false)
+}
+
+func (wh *WorkerHandler) HandleReconcileApply(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	wh.handleReconcile(w, r, ps, true)
+}
+
+// Private Helper Methods
+
+func (wh *WorkerHandler) handleReconcile(w http.ResponseWriter, r *http.Request, ps httprouter.Params, apply bool) {
+	// Process request
+	request := &api.State{}
+	requestInfo, apiErr := wh.processStateRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve reconcile options
+	ignoreUnmanaged, apiErr := getBoolParam(r, "IgnoreUnmanaged")
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call state API to reconcile the organizations
+	response, err := wh.worker.StateApi.ReconcileState(requestInfo, request, ignoreUnmanaged, apply)
+	wh.processStateResponse(r, w, requestInfo, response, err)
+}
+
+// processStateRequest works like processHttpRequest but decodes YAML bodies too
+func (wh *WorkerHandler) processStateRequest(r *http.Request, w http.ResponseWriter, ps httprouter.Params, request *api.State) (api.RequestInfo, *api.Error) {
+	if !isYamlMediaType(r.Header.Get("Content-Type")) {
+		requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, request)
+		return requestInfo, apiErr
+	}
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr == nil {
+		apiErr = decodeYamlRequest(r, request)
+	}
+	return requestInfo, apiErr
+}
+
+// processStateResponse works like processHttpResponse but writes YAML if it's accepted
+func (wh *WorkerHandler) processStateResponse(r *http.Request, w http.ResponseWriter, requestInfo api.RequestInfo, response interface{}, err error) {
+	if err == nil && isYamlMediaType(r.Header.Get("Accept")) {
+		writeHttpYamlResponse(r, w, requestInfo, http.StatusOK, response)
+		return
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func getBoolParam(r *http.Request, name string) (bool, *api.Error) {
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: %v %v", name, value),
+		}
+	}
+	return b, nil
+}
+
+func isYamlMediaType(header string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return false
+	}
+	return mediaType == YAML_MEDIA_TYPE || mediaType == "application/yaml" || mediaType == "text/yaml"
+}
+
+func decodeYamlRequest(r *http.Request, request interface{}) *api.Error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = yaml.Unmarshal(body, request)
+	}
+	if err != nil {
+		return requestBodyError(err)
+	}
+	return nil
+}
+
+// writeHttpYamlResponse works like WriteHttpResponse but marshals value as YAML
+func writeHttpYamlResponse(r *http.Request, w http.ResponseWriter, requestInfo api.RequestInfo, statusCode int, value interface{}) {
+	b, err := yaml.Marshal(value)
+	if err != nil {
+		apiErr := &api.Error{
+			Code:    api.UNKNOWN_API_ERROR,
+			Message: err.Error(),
+		}
+		api.TransactionResponseErrorLog(requestInfo.RequestID, requestInfo.Identifier, r, http.StatusInternalServerError, apiErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", YAML_MEDIA_TYPE)
+	w.WriteHeader(statusCode)
+	w.Write(b)
+}
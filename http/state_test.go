@@ -0,0 +1,458 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestWorkerHandler_HandleExportState(t *testing.T) {
+	state := &api.State{
+		Version: api.STATE_VERSION,
+		Organizations: []api.OrganizationState{
+			{
+				Name: "org1",
+				Path: "/path/",
+			},
+		},
+		Users: []api.UserState{
+			{
+				ExternalID: "user1",
+				Path:       "/path/",
+			},
+		},
+		Policies: []api.PolicyState{
+			{
+				Org:  "org1",
+				Name: "policy1",
+				Path: "/path/",
+				Statements: []api.Statement{
+					{
+						Effect:     "allow",
+						Actions:    []string{api.USER_ACTION_GET_USER},
+						NotActions: []string{api.USER_ACTION_LIST_USERS},
+						Resources:  []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+					},
+				},
+			},
+		},
+	}
+	testcases := map[string]struct {
+		// Request headers
+		accept string
+		// Expected result
+		expectedStatusCode  int
+		expectedContentType string
+		expectedResponse    *api.State
+		expectedError       api.Error
+		// Manager Results
+		exportStateResult *api.State
+		// Manager Errors
+		exportStateErr error
+	}{
+		"OkCaseJSON": {
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json",
+			expectedResponse:    state,
+			exportStateResult:   state,
+		},
+		"OkCaseYAML": {
+			accept:              YAML_MEDIA_TYPE,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: YAML_MEDIA_TYPE,
+			expectedResponse:    state,
+			exportStateResult:   state,
+		},
+		"ErrorCaseUnauthorized": {
+			accept:              YAML_MEDIA_TYPE,
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/json",
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			exportStateErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			expectedStatusCode: http.StatusInternalServerError,
+			exportStateErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ExportStateMethod][0] = test.exportStateResult
+		testApi.ArgsOut[ExportStateMethod][1] = test.exportStateErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+STATE_EXPORT_URL, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			assert.Equal(t, test.expectedContentType, res.Header.Get("Content-Type"), "Error in test case %v", n)
+			response := &api.State{}
+			if test.expectedContentType == YAML_MEDIA_TYPE {
+				buf := new(bytes.Buffer)
+				_, err = buf.ReadFrom(res.Body)
+				assert.Nil(t, err, "Error in test case %v", n)
+				err = yaml.Unmarshal(buf.Bytes(), response)
+			} else {
+				err = json.NewDecoder(res.Body).Decode(response)
+			}
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			assert.Equal(t, test.expectedContentType, res.Header.Get("Content-Type"), "Error in test case %v", n)
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleImportState(t *testing.T) {
+	state := &api.State{
+		Version: api.STATE_VERSION,
+		Groups: []api.GroupState{
+			{
+				Org:     "org1",
+				Name:    "group1",
+				Path:    "/path/",
+				Members: []string{"user1"},
+			},
+		},
+		ProxyResources: []api.ProxyResourceState{
+			{
+				Org:  "org1",
+				Name: "proxy1",
+				Path: "/path/",
+				Resource: api.ResourceEntity{
+					Host:   "http://host.com",
+					Path:   "/mypath",
+					Method: "GET",
+					Urn:    "urn:ews:example:instance1:resource/mypath",
+					Action: "example:One",
+				},
+			},
+		},
+	}
+	result := &api.ImportResult{
+		Mode: api.IMPORT_MODE_MERGE,
+		Changes: []api.StateChange{
+			{
+				Operation: api.STATE_OPERATION_ADD_MEMBER,
+				Resource:  api.CreateUrn("org1", api.RESOURCE_GROUP, "/path/", "group1"),
+				Target:    "user1",
+			},
+		},
+	}
+	testcases := map[string]struct {
+		// Request
+		contentType  string
+		body         string
+		request      *api.State
+		mode         string
+		validateOnly string
+		ignoreArgsIn bool
+		// Expected result
+		expectedValidateOnly bool
+		expectedStatusCode   int
+		expectedResponse     *api.ImportResult
+		expectedError        api.Error
+		// Manager Results
+		importStateResult *api.ImportResult
+		// Manager Errors
+		importStateErr error
+	}{
+		"OkCaseJSON": {
+			request:            state,
+			mode:               api.IMPORT_MODE_REPLACE,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   result,
+			importStateResult:  result,
+		},
+		"OkCaseYAMLValidateOnly": {
+			contentType:          YAML_MEDIA_TYPE + "; charset=utf-8",
+			request:              state,
+			validateOnly:         "true",
+			expectedValidateOnly: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponse:     result,
+			importStateResult:    result,
+		},
+		"ErrorCaseMalformedYAML": {
+			contentType:        YAML_MEDIA_TYPE,
+			body:               "version: [v1",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "yaml: line 1: did not find expected ',' or ']'",
+			},
+		},
+		"ErrorCaseInvalidValidateOnly": {
+			request:            state,
+			validateOnly:       "maybe",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: ValidateOnly maybe",
+			},
+		},
+		"ErrorCaseConflict": {
+			request:            state,
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code:    api.PROXY_RESOURCES_ROUTES_CONFLICT,
+				Message: "Error",
+			},
+			importStateErr: &api.Error{
+				Code:    api.PROXY_RESOURCES_ROUTES_CONFLICT,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ImportStateMethod][0] = test.importStateResult
+		testApi.ArgsOut[ImportStateMethod][1] = test.importStateErr
+
+		body := bytes.NewBufferString(test.body)
+		if test.request != nil {
+			var b []byte
+			var err error
+			if test.contentType != "" {
+				b, err = yaml.Marshal(test.request)
+			} else {
+				b, err = json.Marshal(test.request)
+			}
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(b)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+STATE_IMPORT_URL, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+		q := req.URL.Query()
+		if test.mode != "" {
+			q.Add("Mode", test.mode)
+		}
+		if test.validateOnly != "" {
+			q.Add("ValidateOnly", test.validateOnly)
+		}
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			assert.Equal(t, test.request, testApi.ArgsIn[ImportStateMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.mode, testApi.ArgsIn[ImportStateMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.expectedValidateOnly, testApi.ArgsIn[ImportStateMethod][3], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &api.ImportResult{}
+			err = json.NewDecoder(res.Body).Decode(response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleReconcile(t *testing.T) {
+	state := &api.State{
+		Version: api.STATE_VERSION,
+		Organizations: []api.OrganizationState{
+			{
+				Name: "org1",
+				Path: "/path/",
+			},
+		},
+		Groups: []api.GroupState{
+			{
+				Org:     "org1",
+				Name:    "group1",
+				Path:    "/path/",
+				Members: []string{"user1"},
+			},
+		},
+	}
+	result := &api.ReconcileResult{
+		Orgs: []string{"org1"},
+		Changes: []api.StateChange{
+			{
+				Operation: api.STATE_OPERATION_UPDATE,
+				Resource:  api.CreateUrn("org1", api.RESOURCE_GROUP, "/path/", "group1"),
+				Details:   []string{"path: /path2/ -> /path/"},
+			},
+		},
+	}
+	testcases := map[string]struct {
+		// Request
+		url             string
+		contentType     string
+		request         *api.State
+		ignoreUnmanaged string
+		ignoreArgsIn    bool
+		// Expected result
+		expectedIgnoreUnmanaged bool
+		expectedApply           bool
+		expectedStatusCode      int
+		expectedResponse        *api.ReconcileResult
+		expectedError           api.Error
+		// Manager Results
+		reconcileStateResult *api.ReconcileResult
+		// Manager Errors
+		reconcileStateErr error
+	}{
+		"OkCasePlan": {
+			url:                  RECONCILE_PLAN_URL,
+			request:              state,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponse:     result,
+			reconcileStateResult: result,
+		},
+		"OkCaseApplyYAMLIgnoreUnmanaged": {
+			url:                     RECONCILE_APPLY_URL,
+			contentType:             YAML_MEDIA_TYPE,
+			request:                 state,
+			ignoreUnmanaged:         "true",
+			expectedIgnoreUnmanaged: true,
+			expectedApply:           true,
+			expectedStatusCode:      http.StatusOK,
+			expectedResponse:        result,
+			reconcileStateResult:    result,
+		},
+		"ErrorCaseInvalidIgnoreUnmanaged": {
+			url:                RECONCILE_PLAN_URL,
+			request:            state,
+			ignoreUnmanaged:    "1x",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: IgnoreUnmanaged 1x",
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			url:                RECONCILE_APPLY_URL,
+			request:            state,
+			expectedApply:      true,
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			reconcileStateErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ReconcileStateMethod][0] = test.reconcileStateResult
+		testApi.ArgsOut[ReconcileStateMethod][1] = test.reconcileStateErr
+
+		var b []byte
+		var err error
+		if test.contentType != "" {
+			b, err = yaml.Marshal(test.request)
+		} else {
+			b, err = json.Marshal(test.request)
+		}
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+test.url, bytes.NewBuffer(b))
+		assert.Nil(t, err, "Error in test case %v", n)
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+		if test.ignoreUnmanaged != "" {
+			q := req.URL.Query()
+			q.Add("IgnoreUnmanaged", test.ignoreUnmanaged)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			assert.Equal(t, test.request, testApi.ArgsIn[ReconcileStateMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.expectedIgnoreUnmanaged, testApi.ArgsIn[ReconcileStateMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.expectedApply, testApi.ArgsIn[ReconcileStateMethod][3], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &api.ReconcileResult{}
+			err = json.NewDecoder(res.Body).Decode(response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
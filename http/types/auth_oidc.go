@@ -0,0 +1,26 @@
+package types
+
+// REQUESTS
+
+type CreateOidcProviderRequest struct {
+	Name        string   `json:"name,omitempty"`
+	Path        string   `json:"path,omitempty"`
+	IssuerURL   string   `json:"issuerUrl,omitempty"`
+	OidcClients []string `json:"clients,omitempty"`
+}
+
+type UpdateOidcProviderRequest struct {
+	Name        string   `json:"name,omitempty"`
+	Path        string   `json:"path,omitempty"`
+	IssuerURL   string   `json:"issuerUrl,omitempty"`
+	OidcClients []string `json:"clients,omitempty"`
+}
+
+// RESPONSES
+
+type ListOidcProvidersResponse struct {
+	Providers []string `json:"providers,omitempty"`
+	Limit     int      `json:"limit"`
+	Offset    int      `json:"offset"`
+	Total     int      `json:"total"`
+}
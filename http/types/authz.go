@@ -0,0 +1,14 @@
+package types
+
+// REQUESTS
+
+type AuthorizeResourcesRequest struct {
+	Action    string   `json:"action,omitempty"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+// RESPONSES
+
+type AuthorizeResourcesResponse struct {
+	ResourcesAllowed []string `json:"resourcesAllowed,omitempty"`
+}
@@ -0,0 +1,65 @@
+package types
+
+import "github.com/Tecsisa/foulkon/api"
+
+// REQUESTS
+
+type CreateGroupRequest struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type UpdateGroupRequest struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type UpdateMembersRequest struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+type UpdateAttachedGroupPoliciesRequest struct {
+	Attach []string `json:"attach,omitempty"`
+	Detach []string `json:"detach,omitempty"`
+}
+
+// RESPONSES
+
+type ListGroupsResponse struct {
+	Groups    []string `json:"groups,omitempty"`
+	Limit     int      `json:"limit"`
+	Offset    int      `json:"offset"`
+	Total     int      `json:"total"`
+	NextToken string   `json:"nextToken,omitempty"`
+}
+
+type ListAllGroupsResponse struct {
+	Groups    []api.GroupIdentity `json:"groups,omitempty"`
+	Limit     int                 `json:"limit"`
+	Offset    int                 `json:"offset"`
+	Total     int                 `json:"total"`
+	NextToken string              `json:"nextToken,omitempty"`
+}
+
+type ListMembersResponse struct {
+	Members   []api.GroupMembers `json:"members,omitempty"`
+	Limit     int                `json:"limit"`
+	Offset    int                `json:"offset"`
+	Total     int                `json:"total"`
+	NextToken string             `json:"nextToken,omitempty"`
+}
+
+type ListAttachedGroupPoliciesResponse struct {
+	AttachedPolicies []api.GroupPolicies `json:"policies,omitempty"`
+	Limit            int                 `json:"limit"`
+	Offset           int                 `json:"offset"`
+	Total            int                 `json:"total"`
+}
+
+type ListSubgroupsResponse struct {
+	Subgroups []api.GroupSubgroups `json:"groups,omitempty"`
+	Limit     int                  `json:"limit"`
+	Offset    int                  `json:"offset"`
+	Total     int                  `json:"total"`
+}
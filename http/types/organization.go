@@ -0,0 +1,21 @@
+package types
+
+// REQUESTS
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type UpdateOrganizationRequest struct {
+	Path string `json:"path,omitempty"`
+}
+
+// RESPONSES
+
+type ListOrganizationsResponse struct {
+	Organizations []string `json:"organizations,omitempty"`
+	Limit         int      `json:"limit"`
+	Offset        int      `json:"offset"`
+	Total         int      `json:"total"`
+}
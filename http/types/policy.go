@@ -0,0 +1,54 @@
+package types
+
+import "github.com/Tecsisa/foulkon/api"
+
+// REQUESTS
+
+type CreatePolicyRequest struct {
+	Name       string          `json:"name,omitempty"`
+	Path       string          `json:"path,omitempty"`
+	Statements []api.Statement `json:"statements,omitempty"`
+}
+
+type UpdatePolicyRequest struct {
+	Name       string          `json:"name,omitempty"`
+	Path       string          `json:"path,omitempty"`
+	Statements []api.Statement `json:"statements,omitempty"`
+}
+
+type SetDefaultPolicyVersionRequest struct {
+	Version int `json:"version,omitempty"`
+}
+
+// RESPONSES
+
+type ListPoliciesResponse struct {
+	Policies  []string `json:"policies,omitempty"`
+	Limit     int      `json:"limit"`
+	Offset    int      `json:"offset"`
+	Total     int      `json:"total"`
+	NextToken string   `json:"nextToken,omitempty"`
+}
+
+type ListAllPoliciesResponse struct {
+	Policies  []api.PolicyIdentity `json:"policies,omitempty"`
+	Limit     int                  `json:"limit"`
+	Offset    int                  `json:"offset"`
+	Total     int                  `json:"total"`
+	NextToken string               `json:"nextToken,omitempty"`
+}
+
+type ListAttachedGroupsResponse struct {
+	Groups    []api.PolicyGroups `json:"groups,omitempty"`
+	Limit     int                `json:"limit"`
+	Offset    int                `json:"offset"`
+	Total     int                `json:"total"`
+	NextToken string             `json:"nextToken,omitempty"`
+}
+
+type ListPolicyVersionsResponse struct {
+	Versions []api.PolicyVersion `json:"versions,omitempty"`
+	Limit    int                 `json:"limit"`
+	Offset   int                 `json:"offset"`
+	Total    int                 `json:"total"`
+}
@@ -0,0 +1,31 @@
+package types
+
+import "github.com/Tecsisa/foulkon/api"
+
+// REQUESTS
+
+type CreateProxyResourceRequest struct {
+	Name     string             `json:"name,omitempty"`
+	Path     string             `json:"path,omitempty"`
+	Resource api.ResourceEntity `json:"resource,omitempty"`
+}
+
+type UpdateProxyResourceRequest struct {
+	Name     string             `json:"name,omitempty"`
+	Path     string             `json:"path,omitempty"`
+	Resource api.ResourceEntity `json:"resource,omitempty"`
+}
+
+// RESPONSES
+
+type ProxyResources struct {
+	Resources []api.ProxyResource `json:"resources,omitempty"`
+}
+
+type ListProxyResourcesResponse struct {
+	Resources []string `json:"resources,omitempty"`
+	Limit     int      `json:"limit"`
+	Offset    int      `json:"offset"`
+	Total     int      `json:"total"`
+	NextToken string   `json:"nextToken,omitempty"`
+}
@@ -0,0 +1,101 @@
+// Package types defines the routes and the request and response bodies of the Foulkon worker API, shared by the
+// http handlers and the API client without their dependencies.
+package types
+
+const (
+	// Constants for values in url
+	USER_ID             = "userid"
+	GROUP_NAME          = "groupname"
+	SUBGROUP_NAME       = "subgroupname"
+	POLICY_NAME         = "policyname"
+	POLICY_VERSION      = "policyversion"
+	PROXY_RESOURCE_NAME = "proxyresourcename"
+	AUTH_PROVIDER_NAME  = "authprovidername"
+	ORG_NAME            = "orgname"
+	WEBHOOK_NAME        = "webhookname"
+
+	// URI Path param prefix
+	URI_PATH_PREFIX = "/:"
+
+	// Optimistic concurrency headers
+	ETAG_HEADER     = "ETag"
+	IF_MATCH_HEADER = "If-Match"
+
+	// API root reference
+	API_ROOT      = "/api"
+	API_VERSION_1 = API_ROOT + "/v1"
+
+	// Organization API ROOT
+	ORG_ROOT = "/organizations/:" + ORG_NAME
+
+	// Organization API urls
+	ORGANIZATION_ROOT_URL = API_VERSION_1 + "/organizations"
+	ORGANIZATION_ID_URL   = API_VERSION_1 + ORG_ROOT
+
+	// User API urls
+	USER_ROOT_URL           = API_VERSION_1 + "/users"
+	USER_ID_URL             = USER_ROOT_URL + URI_PATH_PREFIX + USER_ID
+	USER_ID_RESTORE_URL     = USER_ID_URL + "/restore"
+	USER_ID_GROUPS_URL      = USER_ID_URL + "/groups"
+	USER_ID_POLICIES_URL    = USER_ID_URL + "/policies"
+	USER_ID_POLICIES_ID_URL = USER_ID_POLICIES_URL + URI_PATH_PREFIX + ORG_NAME + URI_PATH_PREFIX + POLICY_NAME
+
+	// Group organization API urls
+	GROUP_ORG_ROOT_URL       = API_VERSION_1 + ORG_ROOT + "/groups"
+	GROUP_ID_URL             = GROUP_ORG_ROOT_URL + URI_PATH_PREFIX + GROUP_NAME
+	GROUP_ID_RESTORE_URL     = GROUP_ID_URL + "/restore"
+	GROUP_ID_USERS_URL       = GROUP_ID_URL + "/users"
+	GROUP_ID_USERS_ID_URL    = GROUP_ID_USERS_URL + URI_PATH_PREFIX + USER_ID
+	GROUP_ID_POLICIES_URL    = GROUP_ID_URL + "/policies"
+	GROUP_ID_POLICIES_ID_URL = GROUP_ID_POLICIES_URL + URI_PATH_PREFIX + POLICY_NAME
+	GROUP_ID_GROUPS_URL      = GROUP_ID_URL + "/groups"
+	GROUP_ID_GROUPS_ID_URL   = GROUP_ID_GROUPS_URL + URI_PATH_PREFIX + SUBGROUP_NAME
+
+	// Policy API urls
+	POLICY_ROOT_URL       = API_VERSION_1 + ORG_ROOT + "/policies"
+	POLICY_ID_URL         = POLICY_ROOT_URL + URI_PATH_PREFIX + POLICY_NAME
+	POLICY_ID_GROUPS_URL  = POLICY_ROOT_URL + URI_PATH_PREFIX + POLICY_NAME + "/groups"
+	POLICY_ID_RESTORE_URL = POLICY_ID_URL + "/restore"
+
+	// Policy version API urls
+	POLICY_ID_VERSIONS_URL        = POLICY_ID_URL + "/versions"
+	POLICY_ID_VERSIONS_ID_URL     = POLICY_ID_VERSIONS_URL + URI_PATH_PREFIX + POLICY_VERSION
+	POLICY_ID_VERSIONS_DIFF_URL   = POLICY_ID_URL + "/versions-diff"
+	POLICY_ID_DEFAULT_VERSION_URL = POLICY_ID_URL + "/default-version"
+
+	// Proxy resource API urls
+	PROXY_RESOURCE_ROOT_URL = API_VERSION_1 + ORG_ROOT + "/proxy-resources"
+	PROXY_RESOURCE_ID_URL   = PROXY_RESOURCE_ROOT_URL + URI_PATH_PREFIX + PROXY_RESOURCE_NAME
+
+	// Authorization URLs
+	RESOURCE_URL = API_VERSION_1 + "/resource"
+
+	// Admin URLs
+	ADMIN_ROOT = "/admin"
+
+	// Admin OIDC Authentication API URLs
+	OIDC_AUTH_ROOT_URL = API_VERSION_1 + ADMIN_ROOT + "/auth/oidc/providers"
+	OIDC_AUTH_ID_URL   = OIDC_AUTH_ROOT_URL + URI_PATH_PREFIX + AUTH_PROVIDER_NAME
+
+	// Admin IAM state API URLs
+	STATE_EXPORT_URL = API_VERSION_1 + ADMIN_ROOT + "/export"
+	STATE_IMPORT_URL = API_VERSION_1 + ADMIN_ROOT + "/import"
+
+	// Admin webhook API URLs
+	WEBHOOK_ROOT_URL          = API_VERSION_1 + ADMIN_ROOT + "/webhooks"
+	WEBHOOK_ID_URL            = WEBHOOK_ROOT_URL + URI_PATH_PREFIX + WEBHOOK_NAME
+	WEBHOOK_ID_DELIVERIES_URL = WEBHOOK_ID_URL + "/deliveries"
+
+	// Admin Kubernetes authorization webhook URL
+	KUBERNETES_SUBJECT_ACCESS_REVIEW_URL = API_VERSION_1 + ADMIN_ROOT + "/kubernetes/subjectaccessreview"
+
+	// Reconcile API urls
+	RECONCILE_PLAN_URL  = API_VERSION_1 + "/reconcile/plan"
+	RECONCILE_APPLY_URL = API_VERSION_1 + "/reconcile/apply"
+
+	// Foulkon configuration URL
+	ABOUT = "/about"
+
+	// OpenAPI document URL
+	OPENAPI_URL = API_VERSION_1 + "/openapi.json"
+)
@@ -0,0 +1,38 @@
+package types
+
+import "github.com/Tecsisa/foulkon/api"
+
+// REQUESTS
+
+type CreateUserRequest struct {
+	ExternalID string `json:"externalId,omitempty"`
+	Path       string `json:"path,omitempty"`
+}
+
+type UpdateUserRequest struct {
+	Path string `json:"path,omitempty"`
+}
+
+// RESPONSES
+
+type GetUserExternalIDsResponse struct {
+	ExternalIDs []string `json:"users,omitempty"`
+	Limit       int      `json:"limit"`
+	Offset      int      `json:"offset"`
+	Total       int      `json:"total"`
+	NextToken   string   `json:"nextToken,omitempty"`
+}
+
+type GetGroupsByUserIdResponse struct {
+	Groups []api.UserGroups `json:"groups,omitempty"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+	Total  int              `json:"total"`
+}
+
+type ListAttachedUserPoliciesResponse struct {
+	AttachedPolicies []api.UserPolicies `json:"policies,omitempty"`
+	Limit            int                `json:"limit"`
+	Offset           int                `json:"offset"`
+	Total            int                `json:"total"`
+}
@@ -0,0 +1,35 @@
+package types
+
+import "github.com/Tecsisa/foulkon/api"
+
+// REQUESTS
+
+type CreateWebhookRequest struct {
+	Name   string   `json:"name,omitempty"`
+	URL    string   `json:"url,omitempty"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+type UpdateWebhookRequest struct {
+	Name   string   `json:"name,omitempty"`
+	URL    string   `json:"url,omitempty"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// RESPONSES
+
+type ListWebhooksResponse struct {
+	Webhooks []string `json:"webhooks,omitempty"`
+	Limit    int      `json:"limit"`
+	Offset   int      `json:"offset"`
+	Total    int      `json:"total"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []api.WebhookDelivery `json:"deliveries,omitempty"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	Total      int                   `json:"total"`
+}
@@ -3,36 +3,23 @@ package http
 import (
 	"net/http"
 
-	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/http/types"
 	"github.com/julienschmidt/httprouter"
 )
 
 // REQUESTS
 
-type CreateUserRequest struct {
-	ExternalID string `json:"externalId,omitempty"`
-	Path       string `json:"path,omitempty"`
-}
+type CreateUserRequest = types.CreateUserRequest
 
-type UpdateUserRequest struct {
-	Path string `json:"path,omitempty"`
-}
+type UpdateUserRequest = types.UpdateUserRequest
 
 // RESPONSES
 
-type GetUserExternalIDsResponse struct {
-	ExternalIDs []string `json:"users,omitempty"`
-	Limit       int      `json:"limit"`
-	Offset      int      `json:"offset"`
-	Total       int      `json:"total"`
-}
+type GetUserExternalIDsResponse = types.GetUserExternalIDsResponse
 
-type GetGroupsByUserIdResponse struct {
-	Groups []api.UserGroups `json:"groups,omitempty"`
-	Limit  int              `json:"limit"`
-	Offset int              `json:"offset"`
-	Total  int              `json:"total"`
-}
+type GetGroupsByUserIdResponse = types.GetGroupsByUserIdResponse
+
+type ListAttachedUserPoliciesResponse = types.ListAttachedUserPoliciesResponse
 
 // HANDLERS
 
@@ -60,6 +47,9 @@ func (wh *WorkerHandler) HandleGetUserByExternalID(w http.ResponseWriter, r *htt
 
 	// Call user API to get user
 	response, err := wh.worker.UserApi.GetUserByExternalID(requestInfo, filterData.ExternalID)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -72,13 +62,14 @@ func (wh *WorkerHandler) HandleListUsers(w http.ResponseWriter, r *http.Request,
 	}
 
 	// Call user API to list users
-	result, total, err := wh.worker.UserApi.ListUsers(requestInfo, filterData)
+	result, total, nextToken, err := wh.worker.UserApi.ListUsers(requestInfo, filterData)
 	// Create response
 	response := &GetUserExternalIDsResponse{
 		ExternalIDs: result,
 		Offset:      filterData.Offset,
 		Limit:       filterData.Limit,
 		Total:       total,
+		NextToken:   nextToken,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -94,6 +85,43 @@ func (wh *WorkerHandler) HandleUpdateUser(w http.ResponseWriter, r *http.Request
 
 	// Call user API to update user
 	response, err := wh.worker.UserApi.UpdateUser(requestInfo, filterData.ExternalID, request.Path)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandlePatchUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call user API to retrieve the user to patch
+	user, err := wh.worker.UserApi.GetUserByExternalID(requestInfo, filterData.ExternalID)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusOK)
+		return
+	}
+	request := &UpdateUserRequest{
+		Path: user.Path,
+	}
+	if apiErr := applyPatch(r, requestInfo, request); apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Patched user is only updated over the retrieved revision
+	if requestInfo.Revision == 0 {
+		requestInfo.Revision = user.Revision
+	}
+
+	// Call user API to update user
+	response, err := wh.worker.UserApi.UpdateUser(requestInfo, filterData.ExternalID, request.Path)
+	if err == nil {
+		setETag(w, response.Revision)
+	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -110,6 +138,19 @@ func (wh *WorkerHandler) HandleRemoveUser(w http.ResponseWriter, r *http.Request
 	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
 }
 
+func (wh *WorkerHandler) HandleRestoreUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call user API to restore deleted user
+	response, err := wh.worker.UserApi.RestoreUser(requestInfo, filterData.ExternalID)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
 func (wh *WorkerHandler) HandleListGroupsByUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Process request
 	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
@@ -128,3 +169,46 @@ func (wh *WorkerHandler) HandleListGroupsByUser(w http.ResponseWriter, r *http.R
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
+
+func (wh *WorkerHandler) HandleAttachPolicyToUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call user API to attach policy to user
+	err := wh.worker.UserApi.AttachPolicyToUser(requestInfo, filterData.ExternalID, filterData.Org, filterData.PolicyName)
+	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
+
+func (wh *WorkerHandler) HandleDetachPolicyFromUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call user API to detach policy from user
+	err := wh.worker.UserApi.DetachPolicyFromUser(requestInfo, filterData.ExternalID, filterData.Org, filterData.PolicyName)
+	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
+
+func (wh *WorkerHandler) HandleListAttachedUserPolicies(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call user API to list user policies
+	result, total, err := wh.worker.UserApi.ListAttachedUserPolicies(requestInfo, filterData)
+	// Create response
+	response := &ListAttachedUserPoliciesResponse{
+		AttachedPolicies: result,
+		Offset:           filterData.Offset,
+		Limit:            filterData.Limit,
+		Total:            total,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
@@ -317,6 +317,7 @@ func TestWorkerHandler_HandleListUsers(t *testing.T) {
 		// Manager Results
 		getUserListResult []string
 		totalResult       int
+		nextTokenResult   string
 		// Manager Errors
 		getUserListErr error
 	}{
@@ -336,6 +337,36 @@ func TestWorkerHandler_HandleListUsers(t *testing.T) {
 			getUserListResult: []string{"userId1", "userId2"},
 			totalResult:       2,
 		},
+		"OkCaseDeleted": {
+			filter: &api.Filter{
+				PathPrefix: "myPath",
+				Deleted:    true,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: GetUserExternalIDsResponse{
+				ExternalIDs: []string{"userId3"},
+				Total:       1,
+			},
+			getUserListResult: []string{"userId3"},
+			totalResult:       1,
+		},
+		"OkCaseNextToken": {
+			filter: &api.Filter{
+				PathPrefix: "myPath",
+				Limit:      1,
+				NextToken:  "token1",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: GetUserExternalIDsResponse{
+				ExternalIDs: []string{"userId2"},
+				Limit:       1,
+				Total:       2,
+				NextToken:   "token2",
+			},
+			getUserListResult: []string{"userId2"},
+			totalResult:       2,
+			nextTokenResult:   "token2",
+		},
 		"ErrorCaseInvalidFilterParams": {
 			filter: &api.Filter{
 				PathPrefix: "",
@@ -394,7 +425,8 @@ func TestWorkerHandler_HandleListUsers(t *testing.T) {
 
 		testApi.ArgsOut[ListUsersMethod][0] = test.getUserListResult
 		testApi.ArgsOut[ListUsersMethod][1] = test.totalResult
-		testApi.ArgsOut[ListUsersMethod][2] = test.getUserListErr
+		testApi.ArgsOut[ListUsersMethod][2] = test.nextTokenResult
+		testApi.ArgsOut[ListUsersMethod][3] = test.getUserListErr
 
 		url := fmt.Sprintf(server.URL + USER_ROOT_URL)
 		req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -441,8 +473,11 @@ func TestWorkerHandler_HandleUpdateUser(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
 		request *UpdateUserRequest
+		ifMatch string
 		// Expected result
 		expectedStatusCode int
+		expectedRevision   int
+		expectedETag       string
 		expectedResponse   *api.User
 		expectedError      api.Error
 		// Manager Results
@@ -472,6 +507,65 @@ func TestWorkerHandler_HandleUpdateUser(t *testing.T) {
 				UpdateAt:   now,
 			},
 		},
+		"OkCaseIfMatch": {
+			request: &UpdateUserRequest{
+				Path: "NewPath",
+			},
+			ifMatch:            "\"1\"",
+			expectedStatusCode: http.StatusOK,
+			expectedRevision:   1,
+			expectedETag:       "\"2\"",
+			expectedResponse: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Revision:   2,
+			},
+			updateUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Revision:   2,
+			},
+		},
+		"ErrorCaseRevisionMismatch": {
+			request: &UpdateUserRequest{
+				Path: "NewPath",
+			},
+			ifMatch:            "\"1\"",
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedRevision:   1,
+			expectedError: api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "Revision 1 required by request isn't the current revision 2",
+			},
+			updateUserErr: &api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "Revision 1 required by request isn't the current revision 2",
+			},
+		},
+		"ErrorCaseMalformedIfMatch": {
+			request: &UpdateUserRequest{
+				Path: "NewPath",
+			},
+			ifMatch:            "W/\"1\"",
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedRevision:   -1,
+			expectedError: api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "Revision -1 required by request isn't the current revision 2",
+			},
+			updateUserErr: &api.Error{
+				Code:    api.REVISION_MISMATCH,
+				Message: "Revision -1 required by request isn't the current revision 2",
+			},
+		},
 		"ErrorCaseMalformedRequest": {
 			expectedStatusCode: http.StatusBadRequest,
 			expectedError: api.Error{
@@ -553,6 +647,9 @@ func TestWorkerHandler_HandleUpdateUser(t *testing.T) {
 		url := fmt.Sprintf(server.URL + USER_ROOT_URL + "/userid")
 		req, err := http.NewRequest(http.MethodPut, url, body)
 		assert.Nil(t, err, "Error in test case %v", n)
+		if test.ifMatch != "" {
+			req.Header.Set(IF_MATCH_HEADER, test.ifMatch)
+		}
 
 		res, err := client.Do(req)
 		assert.Nil(t, err, "Error in test case %v", n)
@@ -561,6 +658,8 @@ func TestWorkerHandler_HandleUpdateUser(t *testing.T) {
 			// Check received parameters
 			assert.Equal(t, "userid", testApi.ArgsIn[UpdateUserMethod][1], "Error in test case %v", n)
 			assert.Equal(t, test.request.Path, testApi.ArgsIn[UpdateUserMethod][2], "Error in test case %v", n)
+			requestInfo := testApi.ArgsIn[UpdateUserMethod][0].(api.RequestInfo)
+			assert.Equal(t, test.expectedRevision, requestInfo.Revision, "Error in test case %v", n)
 		}
 
 		// check status code
@@ -573,6 +672,9 @@ func TestWorkerHandler_HandleUpdateUser(t *testing.T) {
 			assert.Nil(t, err, "Error in test case %v", n)
 			// Check result
 			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+			if test.expectedETag != "" {
+				assert.Equal(t, test.expectedETag, res.Header.Get(ETAG_HEADER), "Error in test case %v", n)
+			}
 		case http.StatusInternalServerError: // Empty message so continue
 			continue
 		default:
@@ -585,6 +687,96 @@ func TestWorkerHandler_HandleUpdateUser(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandlePatchUser(t *testing.T) {
+	now := time.Now().UTC()
+	user := &api.User{
+		ID:         "UserID",
+		ExternalID: "user1",
+		Path:       "/path/",
+		Urn:        api.CreateUrn("", api.RESOURCE_USER, "/path/", "user1"),
+		CreateAt:   now,
+		UpdateAt:   now,
+		Revision:   2,
+	}
+	testcases := map[string]struct {
+		// API method args
+		patch string
+		// Expected result
+		expectedStatusCode int
+		expectedPath       string
+		expectedError      api.Error
+		// Manager Errors
+		getUserByExternalIdErr error
+	}{
+		"OkCase": {
+			patch:              `{"path":"/newpath/"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPath:       "/newpath/",
+		},
+		"ErrorCaseUserNotFound": {
+			patch:              `{"path":"/newpath/"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "Not found",
+			},
+			getUserByExternalIdErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "Not found",
+			},
+		},
+		"ErrorCaseMalformedPatch": {
+			patch:              `{`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "unexpected EOF",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetUserByExternalIdMethod][0] = user
+		testApi.ArgsOut[GetUserByExternalIdMethod][1] = test.getUserByExternalIdErr
+		testApi.ArgsOut[UpdateUserMethod][0] = user
+		testApi.ArgsOut[UpdateUserMethod][1] = nil
+
+		url := fmt.Sprintf(server.URL + USER_ROOT_URL + "/user1")
+		req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(test.patch))
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", MERGE_PATCH_CONTENT_TYPE)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Check patched parameters
+			requestInfo := testApi.ArgsIn[UpdateUserMethod][0].(api.RequestInfo)
+			assert.Equal(t, user.Revision, requestInfo.Revision, "Error in test case %v", n)
+			assert.Equal(t, "user1", testApi.ArgsIn[UpdateUserMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.expectedPath, testApi.ArgsIn[UpdateUserMethod][2], "Error in test case %v", n)
+			response := &api.User{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, user, response, "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleRemoveUser(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
@@ -702,6 +894,125 @@ func TestWorkerHandler_HandleRemoveUser(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandleRestoreUser(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *api.User
+		expectedError      api.Error
+		// Manager Results
+		restoreUserResult *api.User
+		// Manager Errors
+		restoreUserErr error
+	}{
+		"OkCase": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+			},
+			restoreUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+			},
+		},
+		"ErrorCaseUserAlreadyExist": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code:    api.USER_ALREADY_EXIST,
+				Message: "User already exist",
+			},
+			restoreUserErr: &api.Error{
+				Code:    api.USER_ALREADY_EXIST,
+				Message: "User already exist",
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "Deleted user not found",
+			},
+			restoreUserErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "Deleted user not found",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			restoreUserErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusInternalServerError,
+			restoreUserErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RestoreUserMethod][0] = test.restoreUserResult
+		testApi.ArgsOut[RestoreUserMethod][1] = test.restoreUserErr
+
+		url := fmt.Sprintf(server.URL+USER_ROOT_URL+"/%v/restore", test.externalID)
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.externalID, testApi.ArgsIn[RestoreUserMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &api.User{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleListGroupsByUser(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -868,3 +1179,307 @@ func TestWorkerHandler_HandleListGroupsByUser(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkerHandler_HandleAttachPolicyToUser(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		org        string
+		policyName string
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		attachUserPolicyErr error
+	}{
+		"OkCase": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseUserNotFoundErr": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User Not Found",
+			},
+			attachUserPolicyErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User Not Found",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			attachUserPolicyErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCasePolicyIsAlreadyAttachedErr": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code:    api.POLICY_IS_ALREADY_ATTACHED_TO_USER,
+				Message: "Policy is already attached to user",
+			},
+			attachUserPolicyErr: &api.Error{
+				Code:    api.POLICY_IS_ALREADY_ATTACHED_TO_USER,
+				Message: "Policy is already attached to user",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusInternalServerError,
+			attachUserPolicyErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[AttachPolicyToUserMethod][0] = test.attachUserPolicyErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/users/%v/policies/%v/%v", test.externalID, test.org, test.policyName)
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.externalID, testApi.ArgsIn[AttachPolicyToUserMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.org, testApi.ArgsIn[AttachPolicyToUserMethod][2], "Error in test case %v", n)
+		assert.Equal(t, test.policyName, testApi.ArgsIn[AttachPolicyToUserMethod][3], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusNoContent, http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleDetachPolicyFromUser(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		org        string
+		policyName string
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		detachUserPolicyErr error
+	}{
+		"OkCase": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCasePolicyNotFoundErr": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Policy Not Found",
+			},
+			detachUserPolicyErr: &api.Error{
+				Code:    api.POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Policy Not Found",
+			},
+		},
+		"ErrorCasePolicyIsNotAttachedErr": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.POLICY_IS_NOT_ATTACHED_TO_USER,
+				Message: "Policy is not attached to user",
+			},
+			detachUserPolicyErr: &api.Error{
+				Code:    api.POLICY_IS_NOT_ATTACHED_TO_USER,
+				Message: "Policy is not attached to user",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			externalID:         "UserID",
+			org:                "org1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusInternalServerError,
+			detachUserPolicyErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[DetachPolicyFromUserMethod][0] = test.detachUserPolicyErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/users/%v/policies/%v/%v", test.externalID, test.org, test.policyName)
+		req, err := http.NewRequest(http.MethodDelete, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.externalID, testApi.ArgsIn[DetachPolicyFromUserMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.org, testApi.ArgsIn[DetachPolicyFromUserMethod][2], "Error in test case %v", n)
+		assert.Equal(t, test.policyName, testApi.ArgsIn[DetachPolicyFromUserMethod][3], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusNoContent, http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListAttachedUserPolicies(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		offset     string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListAttachedUserPoliciesResponse
+		expectedError      api.Error
+		// Manager Results
+		listAttachedUserPoliciesResult []api.UserPolicies
+		totalResult                    int
+		// Manager Errors
+		listAttachedUserPoliciesErr error
+	}{
+		"OkCase": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusOK,
+			listAttachedUserPoliciesResult: []api.UserPolicies{
+				{
+					Org:      "org1",
+					Policy:   "policy1",
+					CreateAt: now,
+				},
+			},
+			totalResult: 1,
+			expectedResponse: ListAttachedUserPoliciesResponse{
+				AttachedPolicies: []api.UserPolicies{
+					{
+						Org:      "org1",
+						Policy:   "policy1",
+						CreateAt: now,
+					},
+				},
+				Total: 1,
+			},
+		},
+		"ErrorCaseInvalidRequest": {
+			externalID:         "UserID",
+			offset:             "-1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseUserNotFoundErr": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User Not Found",
+			},
+			listAttachedUserPoliciesErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User Not Found",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListAttachedUserPoliciesMethod][0] = test.listAttachedUserPoliciesResult
+		testApi.ArgsOut[ListAttachedUserPoliciesMethod][1] = test.totalResult
+		testApi.ArgsOut[ListAttachedUserPoliciesMethod][2] = test.listAttachedUserPoliciesErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/users/%v/policies", test.externalID)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		q.Add("Offset", test.offset)
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Check received parameters
+			filter := testApi.ArgsIn[ListAttachedUserPoliciesMethod][1].(*api.Filter)
+			assert.Equal(t, test.externalID, filter.ExternalID, "Error in test case %v", n)
+
+			response := ListAttachedUserPoliciesResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
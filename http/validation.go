@@ -0,0 +1,313 @@
+package http
+
+//go:generate go run schemas_generate.go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/api"
+)
+
+// Max size in bytes of request bodies
+const MAX_REQUEST_BODY_SIZE = 10 << 20
+
+// Request body schemas of the API links, loaded from the schema documents
+var requestSchemas = mustLoadRequestSchemas(schemaDocuments)
+
+// requestSchema is the schema of the request body of a link in a schema document
+type requestSchema struct {
+	method string
+	// Href path segments, empty for template variables
+	segments []string
+	schema   interface{}
+	// Schema document, to resolve references
+	document interface{}
+}
+
+// schemaValidator collects the violations found validating a JSON document against a schema
+type schemaValidator struct {
+	document   interface{}
+	violations []api.ErrorDetail
+}
+
+// PRIVATE HELPER METHODS
+
+// mustLoadRequestSchemas is like loadRequestSchemas but panics if a schema document is invalid, since
+// request bodies couldn't be validated
+func mustLoadRequestSchemas(documents map[string]string) []requestSchema {
+	schemas, err := loadRequestSchemas(documents)
+	if err != nil {
+		panic(err)
+	}
+	return schemas
+}
+
+// loadRequestSchemas returns the request body schemas of the links in the schema documents given
+func loadRequestSchemas(documents map[string]string) ([]requestSchema, error) {
+	names := []string{}
+	for name := range documents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schemas := []requestSchema{}
+	for _, name := range names {
+		var document interface{}
+		if err := json.Unmarshal([]byte(documents[name]), &document); err != nil {
+			return nil, fmt.Errorf("Invalid schema document %v: %v", name, err)
+		}
+		definitions, _ := schemaObject(document)["definitions"].(map[string]interface{})
+		definitionNames := []string{}
+		for definitionName := range definitions {
+			definitionNames = append(definitionNames, definitionName)
+		}
+		sort.Strings(definitionNames)
+		for _, definitionName := range definitionNames {
+			links, _ := schemaObject(definitions[definitionName])["links"].([]interface{})
+			for _, l := range links {
+				link := schemaObject(l)
+				schema, ok := link["schema"]
+				if !ok {
+					continue
+				}
+				method, _ := link["method"].(string)
+				href, _ := link["href"].(string)
+				schemas = append(schemas, requestSchema{
+					method:   method,
+					segments: hrefSegments(href),
+					schema:   schema,
+					document: document,
+				})
+			}
+		}
+	}
+	return schemas, nil
+}
+
+// hrefSegments splits the path of a link href, leaving template variables empty
+func hrefSegments(href string) []string {
+	if idx := strings.Index(href, "?"); idx != -1 {
+		href = href[:idx]
+	}
+	segments := strings.Split(href, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ""
+		}
+	}
+	return segments
+}
+
+// findRequestSchema returns the request body schema of the link that matches the method and path given, if any
+func findRequestSchema(method string, path string) *requestSchema {
+	segments := strings.Split(path, "/")
+	for i, rs := range requestSchemas {
+		if rs.method != method || len(rs.segments) != len(segments) {
+			continue
+		}
+		matches := true
+		for j, segment := range rs.segments {
+			if (segment == "" && segments[j] == "" && j > 0) || (segment != "" && segment != segments[j]) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return &requestSchemas[i]
+		}
+	}
+	return nil
+}
+
+// decodeRequestBody decodes the JSON request body, validating it first against the schema of the link requested
+func decodeRequestBody(r *http.Request, request interface{}) *api.Error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		if apiErr := validateRequestDocument(r, body); apiErr != nil {
+			return apiErr
+		}
+		err = json.NewDecoder(bytes.NewReader(body)).Decode(&request)
+	}
+	if err != nil {
+		return requestBodyError(err)
+	}
+	return nil
+}
+
+// requestBodyError returns the API error of a request body that can't be read or decoded. Bodies larger
+// than MAX_REQUEST_BODY_SIZE are cut by http.MaxBytesReader, which only tells it by the error message.
+func requestBodyError(err error) *api.Error {
+	if err.Error() == "http: request body too large" {
+		return &api.Error{
+			Code:    api.REQUEST_BODY_TOO_LARGE,
+			Message: fmt.Sprintf("Request body is larger than %v bytes", MAX_REQUEST_BODY_SIZE),
+		}
+	}
+	return &api.Error{
+		Code:    api.INVALID_PARAMETER_ERROR,
+		Message: err.Error(),
+	}
+}
+
+// validateRequestDocument validates a JSON document against the request body schema of the link requested, returning
+// every violation found. Documents of links without schema aren't validated.
+func validateRequestDocument(r *http.Request, body []byte) *api.Error {
+	rs := findRequestSchema(r.Method, r.URL.Path)
+	if rs == nil {
+		return nil
+	}
+
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// Numbers are kept as they are written to tell integers apart
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	v := &schemaValidator{document: rs.document}
+	v.validate("", rs.schema, document)
+	if len(v.violations) > 0 {
+		return &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: request body doesn't match its schema, %v errors found", len(v.violations)),
+			Details: v.violations,
+		}
+	}
+	return nil
+}
+
+func schemaObject(value interface{}) map[string]interface{} {
+	object, _ := value.(map[string]interface{})
+	return object
+}
+
+// resolve follows the local references of a schema
+func (v *schemaValidator) resolve(schema interface{}) map[string]interface{} {
+	s := schemaObject(schema)
+	// Nested references are followed a limited number of times to stop on reference cycles
+	for i := 0; i < 16; i++ {
+		ref, ok := s["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return s
+		}
+		target := v.document
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+			token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+			target = schemaObject(target)[token]
+		}
+		s = schemaObject(target)
+	}
+	return s
+}
+
+func (v *schemaValidator) addViolation(pointer string, format string, args ...interface{}) {
+	v.violations = append(v.violations, api.ErrorDetail{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validate checks a value against the type, properties, required, strictProperties and items keywords
+// of a schema. Like in prmd schemas, strictProperties forbids properties not described in the schema.
+func (v *schemaValidator) validate(pointer string, schema interface{}, value interface{}) {
+	s := v.resolve(schema)
+	if s == nil {
+		return
+	}
+
+	if types := schemaTypes(s["type"]); len(types) > 0 {
+		valueType := jsonType(value)
+		valid := false
+		for _, t := range types {
+			valid = valid || t == valueType || (t == "number" && valueType == "integer")
+		}
+		if !valid {
+			v.addViolation(pointer, "Expected %v, received %v", strings.Join(types, " or "), valueType)
+			return
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties := schemaObject(s["properties"])
+		if required, ok := s["required"].([]interface{}); ok {
+			for _, r := range required {
+				name := fmt.Sprint(r)
+				if _, ok := value[name]; !ok {
+					v.addViolation(pointer+"/"+pointerToken(name), "Property is required")
+				}
+			}
+		}
+		names := []string{}
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		strict, _ := s["strictProperties"].(bool)
+		for _, name := range names {
+			if property, ok := properties[name]; ok {
+				v.validate(pointer+"/"+pointerToken(name), property, value[name])
+			} else if strict {
+				v.addViolation(pointer+"/"+pointerToken(name), "Property is not allowed")
+			}
+		}
+	case []interface{}:
+		if items, ok := s["items"]; ok {
+			for i, item := range value {
+				v.validate(pointer+"/"+strconv.Itoa(i), items, item)
+			}
+		}
+	}
+}
+
+// schemaTypes returns the types allowed by a type keyword, that can be a type name or a list of them
+func schemaTypes(keyword interface{}) []string {
+	switch keyword := keyword.(type) {
+	case string:
+		return []string{keyword}
+	case []interface{}:
+		types := []string{}
+		for _, t := range keyword {
+			types = append(types, fmt.Sprint(t))
+		}
+		return types
+	}
+	return nil
+}
+
+// jsonType returns the JSON schema type of a value decoded using numbers
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// pointerToken escapes a property name to be used in a JSON Pointer
+func pointerToken(name string) string {
+	return strings.Replace(strings.Replace(name, "~", "~0", -1), "/", "~1", -1)
+}
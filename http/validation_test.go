@@ -0,0 +1,209 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaDocuments(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "schema", "*.json"))
+	assert.Nil(t, err, "Error listing schema files")
+
+	// Generated documents must be up to date, run go generate otherwise
+	documents := map[string]string{}
+	for _, file := range files {
+		if filepath.Base(file) == "postman.json" {
+			continue
+		}
+		content, err := ioutil.ReadFile(file)
+		assert.Nil(t, err, "Error reading %v", file)
+		documents[filepath.Base(file)] = string(content)
+	}
+	assert.Equal(t, documents, schemaDocuments, "Schema documents out of date")
+
+	schemas, err := loadRequestSchemas(schemaDocuments)
+	assert.Nil(t, err, "Error loading request schemas")
+	assert.Equal(t, schemas, requestSchemas, "Error loading request schemas")
+}
+
+func TestFindRequestSchema(t *testing.T) {
+	// Every route reading a request body has a schema to validate it
+	for _, rt := range workerRoutes() {
+		if rt.request == nil {
+			continue
+		}
+		segments := strings.Split(rt.path, "/")
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ":") {
+				segments[i] = "value"
+			}
+		}
+		assert.NotNil(t, findRequestSchema(rt.method, strings.Join(segments, "/")), "Schema of route %v %v not found", rt.method, rt.path)
+	}
+}
+
+func TestWorkerHandler_ValidateRequestBody(t *testing.T) {
+	testcases := map[string]struct {
+		method      string
+		url         string
+		contentType string
+		body        string
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+	}{
+		"ErrorCaseWrongTypes": {
+			method:             http.MethodPost,
+			url:                USER_ROOT_URL,
+			body:               `{"externalId": 1, "path": ["/path/"]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: request body doesn't match its schema, 2 errors found",
+				Details: []api.ErrorDetail{
+					{Pointer: "/externalId", Message: "Expected string, received integer"},
+					{Pointer: "/path", Message: "Expected string, received array"},
+				},
+			},
+		},
+		"ErrorCaseMissingAndUnknownProperties": {
+			method:             http.MethodPost,
+			url:                USER_ROOT_URL,
+			body:               `{"externalID": "user1"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: request body doesn't match its schema, 3 errors found",
+				Details: []api.ErrorDetail{
+					{Pointer: "/externalId", Message: "Property is required"},
+					{Pointer: "/path", Message: "Property is required"},
+					{Pointer: "/externalID", Message: "Property is not allowed"},
+				},
+			},
+		},
+		"ErrorCaseNestedViolations": {
+			method: http.MethodPost,
+			url:    API_VERSION_1 + "/organizations/org1/policies",
+			body: `{"name": "policy1", "path": "/path/", "statements": [
+				{"effect": "allow", "actions": ["iam:*"], "resources": ["urn:*"]},
+				{"effect": "allow", "actions": "iam:*", "resources": ["urn:*", 2], "condition": {}}
+			]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: request body doesn't match its schema, 3 errors found",
+				Details: []api.ErrorDetail{
+					{Pointer: "/statements/1/actions", Message: "Expected array, received string"},
+					{Pointer: "/statements/1/condition", Message: "Property is not allowed"},
+					{Pointer: "/statements/1/resources/1", Message: "Expected string, received integer"},
+				},
+			},
+		},
+		"ErrorCaseNotAnObject": {
+			method:             http.MethodPost,
+			url:                API_VERSION_1 + "/organizations/org1/groups",
+			body:               `["group1"]`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: request body doesn't match its schema, 1 errors found",
+				Details: []api.ErrorDetail{
+					{Pointer: "", Message: "Expected object, received array"},
+				},
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			method:             http.MethodPut,
+			url:                USER_ROOT_URL + "/user1",
+			body:               `{"path": `,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "unexpected EOF",
+			},
+		},
+		"ErrorCaseBodyTooLarge": {
+			method:             http.MethodPost,
+			url:                USER_ROOT_URL,
+			body:               `{"externalId": "` + strings.Repeat("a", MAX_REQUEST_BODY_SIZE) + `", "path": "/path/"}`,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			expectedError: api.Error{
+				Code:    api.REQUEST_BODY_TOO_LARGE,
+				Message: "Request body is larger than 10485760 bytes",
+			},
+		},
+		"ErrorCasePatchTooLarge": {
+			method:             http.MethodPatch,
+			url:                USER_ROOT_URL + "/user1",
+			contentType:        MERGE_PATCH_CONTENT_TYPE,
+			body:               `{"path": "/` + strings.Repeat("a", MAX_REQUEST_BODY_SIZE) + `/"}`,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			expectedError: api.Error{
+				Code:    api.REQUEST_BODY_TOO_LARGE,
+				Message: "Request body is larger than 10485760 bytes",
+			},
+		},
+		"ErrorCaseMergePatch": {
+			method:             http.MethodPatch,
+			url:                USER_ROOT_URL + "/user1",
+			contentType:        MERGE_PATCH_CONTENT_TYPE,
+			body:               `{"path": 5}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: request body doesn't match its schema, 1 errors found",
+				Details: []api.ErrorDetail{
+					{Pointer: "/path", Message: "Expected string, received integer"},
+				},
+			},
+		},
+		"ErrorCaseJSONPatch": {
+			method:             http.MethodPatch,
+			url:                USER_ROOT_URL + "/user1",
+			contentType:        JSON_PATCH_CONTENT_TYPE,
+			body:               `[{"op": "add", "path": "/org", "value": "org1"}]`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: request body doesn't match its schema, 1 errors found",
+				Details: []api.ErrorDetail{
+					{Pointer: "/org", Message: "Property is not allowed"},
+				},
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetUserByExternalIdMethod][0] = &api.User{ExternalID: "user1", Path: "/path/"}
+		testApi.ArgsOut[GetUserByExternalIdMethod][1] = nil
+
+		req, err := http.NewRequest(test.method, server.URL+test.url, bytes.NewBufferString(test.body))
+		assert.Nil(t, err, "Error in test case %v", n)
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		apiError := api.Error{}
+		err = json.NewDecoder(res.Body).Decode(&apiError)
+		assert.Nil(t, err, "Error in test case %v", n)
+		// Check result
+		assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+	}
+}
@@ -0,0 +1,577 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_HandleAddWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		request *CreateWebhookRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Webhook
+		expectedError      api.Error
+		// Manager Results
+		addWebhookResult *api.Webhook
+		// Manager Errors
+		addWebhookErr error
+	}{
+		"OkCase": {
+			request: &CreateWebhookRequest{
+				Name:   "hook1",
+				URL:    "https://example.com/hook",
+				Secret: "secret",
+				Events: []string{api.USER_EVENT_CREATED},
+			},
+			addWebhookResult: &api.Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook1",
+				URL:      "https://example.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.USER_EVENT_CREATED},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: api.Webhook{
+				ID:       "HOOK-ID",
+				Name:     "hook1",
+				URL:      "https://example.com/hook",
+				Events:   []string{api.USER_EVENT_CREATED},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseWebhookAlreadyExists": {
+			request: &CreateWebhookRequest{
+				Name:   "hook1",
+				URL:    "https://example.com/hook",
+				Secret: "secret",
+				Events: []string{api.USER_EVENT_CREATED},
+			},
+			addWebhookErr: &api.Error{
+				Code: api.WEBHOOK_ALREADY_EXIST,
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code: api.WEBHOOK_ALREADY_EXIST,
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			request: &CreateWebhookRequest{
+				Name:   "hook1",
+				URL:    "https://example.com/hook",
+				Secret: "secret",
+				Events: []string{api.USER_EVENT_CREATED},
+			},
+			addWebhookErr: &api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			request: &CreateWebhookRequest{
+				Name:   "hook1",
+				URL:    "https://example.com/hook",
+				Secret: "secret",
+				Events: []string{api.USER_EVENT_CREATED},
+			},
+			addWebhookErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[AddWebhookMethod][0] = test.addWebhookResult
+		testApi.ArgsOut[AddWebhookMethod][1] = test.addWebhookErr
+
+		body := bytes.NewBuffer([]byte{})
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+WEBHOOK_ROOT_URL, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.request.Name, testApi.ArgsIn[AddWebhookMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.request.URL, testApi.ArgsIn[AddWebhookMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.Secret, testApi.ArgsIn[AddWebhookMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.Events, testApi.ArgsIn[AddWebhookMethod][4], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusCreated:
+			response := api.Webhook{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result, secret is never returned
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleGetWebhookByName(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		name string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Webhook
+		expectedError      api.Error
+		// Manager Results
+		getWebhookByNameResult *api.Webhook
+		// Manager Errors
+		getWebhookByNameErr error
+	}{
+		"OkCase": {
+			name: "hook1",
+			getWebhookByNameResult: &api.Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+				URL:  "https://example.com/hook",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: api.Webhook{
+				ID:   "HOOK-ID",
+				Name: "hook1",
+				URL:  "https://example.com/hook",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			name: "hook1",
+			getWebhookByNameErr: &api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			name: "hook1",
+			getWebhookByNameErr: &api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetWebhookByNameMethod][0] = test.getWebhookByNameResult
+		testApi.ArgsOut[GetWebhookByNameMethod][1] = test.getWebhookByNameErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+WEBHOOK_ROOT_URL+"/"+test.name, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.name, testApi.ArgsIn[GetWebhookByNameMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.Webhook{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListWebhooks(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		filter *api.Filter
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListWebhooksResponse
+		expectedError      api.Error
+		// Manager Results
+		listWebhooksResult []string
+		listWebhooksTotal  int
+		// Manager Errors
+		listWebhooksErr error
+	}{
+		"OkCase": {
+			filter:             testFilter,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListWebhooksResponse{
+				Webhooks: []string{"hook1"},
+				Total:    1,
+			},
+			listWebhooksResult: []string{"hook1"},
+			listWebhooksTotal:  1,
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter:             testFilter,
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			listWebhooksErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListWebhooksMethod][0] = test.listWebhooksResult
+		testApi.ArgsOut[ListWebhooksMethod][1] = test.listWebhooksTotal
+		testApi.ArgsOut[ListWebhooksMethod][2] = test.listWebhooksErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+WEBHOOK_ROOT_URL, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := ListWebhooksResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleUpdateWebhook(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		name    string
+		request *UpdateWebhookRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Webhook
+		expectedError      api.Error
+		// Manager Results
+		updateWebhookResult *api.Webhook
+		// Manager Errors
+		updateWebhookErr error
+	}{
+		"OkCase": {
+			name: "hook1",
+			request: &UpdateWebhookRequest{
+				Name:   "hook2",
+				URL:    "https://example.com/new",
+				Secret: "secret",
+				Events: []string{api.ALL_EVENTS},
+			},
+			updateWebhookResult: &api.Webhook{
+				ID:     "HOOK-ID",
+				Name:   "hook2",
+				URL:    "https://example.com/new",
+				Events: []string{api.ALL_EVENTS},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: api.Webhook{
+				ID:     "HOOK-ID",
+				Name:   "hook2",
+				URL:    "https://example.com/new",
+				Events: []string{api.ALL_EVENTS},
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			name: "hook1",
+			request: &UpdateWebhookRequest{
+				Name:   "hook1",
+				URL:    "https://example.com/new",
+				Secret: "secret",
+				Events: []string{api.ALL_EVENTS},
+			},
+			updateWebhookErr: &api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+		},
+		"ErrorCaseWebhookAlreadyExists": {
+			name: "hook1",
+			request: &UpdateWebhookRequest{
+				Name:   "hook2",
+				URL:    "https://example.com/new",
+				Secret: "secret",
+				Events: []string{api.ALL_EVENTS},
+			},
+			updateWebhookErr: &api.Error{
+				Code: api.WEBHOOK_ALREADY_EXIST,
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code: api.WEBHOOK_ALREADY_EXIST,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[UpdateWebhookMethod][0] = test.updateWebhookResult
+		testApi.ArgsOut[UpdateWebhookMethod][1] = test.updateWebhookErr
+
+		jsonObject, err := json.Marshal(test.request)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		req, err := http.NewRequest(http.MethodPut, server.URL+WEBHOOK_ROOT_URL+"/"+test.name, bytes.NewBuffer(jsonObject))
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.name, testApi.ArgsIn[UpdateWebhookMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.request.Name, testApi.ArgsIn[UpdateWebhookMethod][2], "Error in test case %v", n)
+		assert.Equal(t, test.request.URL, testApi.ArgsIn[UpdateWebhookMethod][3], "Error in test case %v", n)
+		assert.Equal(t, test.request.Secret, testApi.ArgsIn[UpdateWebhookMethod][4], "Error in test case %v", n)
+		assert.Equal(t, test.request.Events, testApi.ArgsIn[UpdateWebhookMethod][5], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.Webhook{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleRemoveWebhook(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		name string
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		removeWebhookErr error
+	}{
+		"OkCase": {
+			name:               "hook1",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseWebhookNotFound": {
+			name: "hook1",
+			removeWebhookErr: &api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RemoveWebhookMethod][0] = test.removeWebhookErr
+
+		req, err := http.NewRequest(http.MethodDelete, server.URL+WEBHOOK_ROOT_URL+"/"+test.name, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.name, testApi.ArgsIn[RemoveWebhookMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		if res.StatusCode != http.StatusNoContent {
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListWebhookDeliveries(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		name string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListWebhookDeliveriesResponse
+		expectedError      api.Error
+		// Manager Results
+		listDeliveriesResult []api.WebhookDelivery
+		listDeliveriesTotal  int
+		// Manager Errors
+		listDeliveriesErr error
+	}{
+		"OkCase": {
+			name: "hook1",
+			listDeliveriesResult: []api.WebhookDelivery{
+				{
+					ID:            "DELIVERY-ID",
+					EventID:       "EVENT-ID",
+					EventType:     api.USER_EVENT_CREATED,
+					Status:        api.WEBHOOK_DELIVERY_DELIVERED,
+					Attempts:      1,
+					ResponseCode:  http.StatusOK,
+					NextAttemptAt: now,
+					CreateAt:      now,
+					UpdateAt:      now,
+				},
+			},
+			listDeliveriesTotal: 1,
+			expectedStatusCode:  http.StatusOK,
+			expectedResponse: ListWebhookDeliveriesResponse{
+				Deliveries: []api.WebhookDelivery{
+					{
+						ID:            "DELIVERY-ID",
+						EventID:       "EVENT-ID",
+						EventType:     api.USER_EVENT_CREATED,
+						Status:        api.WEBHOOK_DELIVERY_DELIVERED,
+						Attempts:      1,
+						ResponseCode:  http.StatusOK,
+						NextAttemptAt: now,
+						CreateAt:      now,
+						UpdateAt:      now,
+					},
+				},
+				Total: 1,
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			name: "hook1",
+			listDeliveriesErr: &api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListWebhookDeliveriesMethod][0] = test.listDeliveriesResult
+		testApi.ArgsOut[ListWebhookDeliveriesMethod][1] = test.listDeliveriesTotal
+		testApi.ArgsOut[ListWebhookDeliveriesMethod][2] = test.listDeliveriesErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+WEBHOOK_ROOT_URL+"/"+test.name+"/deliveries", nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		filterData := testApi.ArgsIn[ListWebhookDeliveriesMethod][1].(*api.Filter)
+		assert.Equal(t, test.name, filterData.WebhookName, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := ListWebhookDeliveriesResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
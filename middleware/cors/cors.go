@@ -0,0 +1,134 @@
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/middleware"
+)
+
+const (
+	// CORS request headers
+	ORIGIN_HEADER                        = "Origin"
+	ACCESS_CONTROL_REQUEST_METHOD_HEADER = "Access-Control-Request-Method"
+
+	// CORS response headers
+	ALLOW_ORIGIN_HEADER      = "Access-Control-Allow-Origin"
+	ALLOW_METHODS_HEADER     = "Access-Control-Allow-Methods"
+	ALLOW_HEADERS_HEADER     = "Access-Control-Allow-Headers"
+	ALLOW_CREDENTIALS_HEADER = "Access-Control-Allow-Credentials"
+	EXPOSE_HEADERS_HEADER    = "Access-Control-Expose-Headers"
+	MAX_AGE_HEADER           = "Access-Control-Max-Age"
+
+	// Response headers readable by clients
+	ETAG_HEADER = "ETag"
+)
+
+// CORSMiddleware adds Cross-Origin Resource Sharing headers to responses and
+// answers preflight requests without calling the next handler
+type CORSMiddleware struct {
+	allowedOrigins   []string
+	allowedMethods   []string
+	allowedHeaders   []string
+	allowCredentials bool
+	maxAge           int
+}
+
+// NewCORSMiddleware returns a configured CORSMiddleware. Origin "*" allows any origin.
+func NewCORSMiddleware(allowedOrigins []string, allowedMethods []string, allowedHeaders []string,
+	allowCredentials bool, maxAge int) *CORSMiddleware {
+	return &CORSMiddleware{
+		allowedOrigins:   allowedOrigins,
+		allowedMethods:   allowedMethods,
+		allowedHeaders:   allowedHeaders,
+		allowCredentials: allowCredentials,
+		maxAge:           maxAge,
+	}
+}
+
+func (c *CORSMiddleware) Action(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get(ORIGIN_HEADER)
+		// Not a CORS request
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get(ACCESS_CONTROL_REQUEST_METHOD_HEADER) != ""
+		w.Header().Add("Vary", ORIGIN_HEADER)
+		if !c.isAllowedOrigin(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c.setOriginHeaders(w, origin)
+		if !preflight {
+			w.Header().Set(EXPOSE_HEADERS_HEADER, middleware.REQUEST_ID_HEADER+", "+ETAG_HEADER)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Preflight request, answered here so it never reaches authentication
+		if !c.isAllowedMethod(r.Header.Get(ACCESS_CONTROL_REQUEST_METHOD_HEADER)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set(ALLOW_METHODS_HEADER, strings.Join(c.allowedMethods, ", "))
+		if len(c.allowedHeaders) > 0 {
+			w.Header().Set(ALLOW_HEADERS_HEADER, strings.Join(c.allowedHeaders, ", "))
+		}
+		if c.maxAge > 0 {
+			w.Header().Set(MAX_AGE_HEADER, strconv.Itoa(c.maxAge))
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (c *CORSMiddleware) GetInfo(r *http.Request, mc *middleware.MiddlewareContext) {}
+
+// PRIVATE HELPER METHODS
+
+func (c *CORSMiddleware) setOriginHeaders(w http.ResponseWriter, origin string) {
+	// Wildcard origin can't be used with credentials, so request origin is echoed instead
+	if c.isWildcardOrigin() && !c.allowCredentials {
+		w.Header().Set(ALLOW_ORIGIN_HEADER, "*")
+	} else {
+		w.Header().Set(ALLOW_ORIGIN_HEADER, origin)
+	}
+	if c.allowCredentials {
+		w.Header().Set(ALLOW_CREDENTIALS_HEADER, "true")
+	}
+}
+
+func (c *CORSMiddleware) isWildcardOrigin() bool {
+	for _, o := range c.allowedOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORSMiddleware) isAllowedOrigin(origin string) bool {
+	for _, o := range c.allowedOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORSMiddleware) isAllowedMethod(method string) bool {
+	for _, m := range c.allowedMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package cors
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware_Action(t *testing.T) {
+	testMessage := "TestMessage"
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(testMessage))
+	})
+	testcases := map[string]struct {
+		// Middleware config
+		origins     []string
+		credentials bool
+		// Request
+		method        string
+		origin        string
+		requestMethod string
+		// Expected result
+		expectedStatusCode int
+		expectedBody       string
+		expectedHeaders    map[string]string
+	}{
+		"OkCaseNoCORSRequest": {
+			origins:            []string{"http://example.com"},
+			method:             http.MethodGet,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       testMessage,
+			expectedHeaders: map[string]string{
+				ALLOW_ORIGIN_HEADER: "",
+			},
+		},
+		"OkCaseSimpleRequest": {
+			origins:            []string{"http://example.com"},
+			method:             http.MethodGet,
+			origin:             "http://example.com",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       testMessage,
+			expectedHeaders: map[string]string{
+				ALLOW_ORIGIN_HEADER:   "http://example.com",
+				EXPOSE_HEADERS_HEADER: middleware.REQUEST_ID_HEADER + ", " + ETAG_HEADER,
+				ALLOW_METHODS_HEADER:  "",
+			},
+		},
+		"OkCaseWildcardOrigin": {
+			origins:            []string{"*"},
+			method:             http.MethodGet,
+			origin:             "http://other.com",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       testMessage,
+			expectedHeaders: map[string]string{
+				ALLOW_ORIGIN_HEADER: "*",
+			},
+		},
+		"OkCaseWildcardOriginWithCredentials": {
+			origins:            []string{"*"},
+			credentials:        true,
+			method:             http.MethodGet,
+			origin:             "http://other.com",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       testMessage,
+			expectedHeaders: map[string]string{
+				ALLOW_ORIGIN_HEADER:      "http://other.com",
+				ALLOW_CREDENTIALS_HEADER: "true",
+			},
+		},
+		"OkCasePreflight": {
+			origins:            []string{"http://example.com"},
+			method:             http.MethodOptions,
+			origin:             "http://example.com",
+			requestMethod:      http.MethodPut,
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				ALLOW_ORIGIN_HEADER:  "http://example.com",
+				ALLOW_METHODS_HEADER: "GET, PUT",
+				ALLOW_HEADERS_HEADER: "Authorization, Content-Type",
+				MAX_AGE_HEADER:       "600",
+			},
+		},
+		"OkCaseNotAllowedOrigin": {
+			origins:            []string{"http://example.com"},
+			method:             http.MethodGet,
+			origin:             "http://other.com",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       testMessage,
+			expectedHeaders: map[string]string{
+				ALLOW_ORIGIN_HEADER: "",
+			},
+		},
+		"ErrorCasePreflightNotAllowedOrigin": {
+			origins:            []string{"http://example.com"},
+			method:             http.MethodOptions,
+			origin:             "http://other.com",
+			requestMethod:      http.MethodGet,
+			expectedStatusCode: http.StatusForbidden,
+			expectedHeaders: map[string]string{
+				ALLOW_ORIGIN_HEADER: "",
+			},
+		},
+		"ErrorCasePreflightNotAllowedMethod": {
+			origins:            []string{"http://example.com"},
+			method:             http.MethodOptions,
+			origin:             "http://example.com",
+			requestMethod:      http.MethodDelete,
+			expectedStatusCode: http.StatusForbidden,
+			expectedHeaders: map[string]string{
+				ALLOW_METHODS_HEADER: "",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		mw := NewCORSMiddleware(test.origins, []string{http.MethodGet, http.MethodPut},
+			[]string{"Authorization", "Content-Type"}, test.credentials, 600)
+		req := httptest.NewRequest(test.method, "/", nil)
+		if test.origin != "" {
+			req.Header.Set(ORIGIN_HEADER, test.origin)
+		}
+		if test.requestMethod != "" {
+			req.Header.Set(ACCESS_CONTROL_REQUEST_METHOD_HEADER, test.requestMethod)
+		}
+		w := httptest.NewRecorder()
+		mw.Action(testHandler).ServeHTTP(w, req)
+		res := w.Result()
+
+		// Check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		// Check body
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(res.Body)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedBody, buffer.String(), "Error in test case %v", n)
+
+		// Check headers
+		for header, value := range test.expectedHeaders {
+			assert.Equal(t, value, res.Header.Get(header), "Error in test case %v, header %v", n, header)
+		}
+	}
+}
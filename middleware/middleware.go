@@ -11,6 +11,7 @@ const (
 	AUTHENTICATOR_MIDDLEWARE  = "AUTHENTICATOR"
 	XREQUESTID_MIDDLEWARE     = "XREQUESTID"
 	REQUEST_LOGGER_MIDDLEWARE = "REQUEST-LOGGER"
+	CORS_MIDDLEWARE           = "CORS"
 )
 
 // MiddlewareHandler handles the HTTP request and applies its list of middlewares before calling the API
@@ -52,6 +53,10 @@ func (mwh *MiddlewareHandler) Handle(apiHandler http.Handler) http.Handler {
 	if val, ok := mwh.Middlewares[AUTHENTICATOR_MIDDLEWARE]; ok {
 		handler = val.Action(handler)
 	}
+	// CORS preflight requests must be answered before authentication
+	if val, ok := mwh.Middlewares[CORS_MIDDLEWARE]; ok {
+		handler = val.Action(handler)
+	}
 	if val, ok := mwh.Middlewares[XREQUESTID_MIDDLEWARE]; ok {
 		handler = val.Action(handler)
 	}
@@ -50,6 +50,9 @@ func TestMiddlewareHandler_Handle(t *testing.T) {
 				XREQUESTID_MIDDLEWARE: &TestMiddleware{
 					HeaderValue: XREQUESTID_MIDDLEWARE,
 				},
+				CORS_MIDDLEWARE: &TestMiddleware{
+					HeaderValue: CORS_MIDDLEWARE,
+				},
 			},
 		},
 	}
@@ -72,7 +75,7 @@ func TestMiddlewareHandler_Handle(t *testing.T) {
 		assert.Equal(t, string(buffer.Bytes()), testMessage)
 
 		// Check Header
-		expectedHeader := XREQUESTID_MIDDLEWARE + AUTHENTICATOR_MIDDLEWARE + REQUEST_LOGGER_MIDDLEWARE
+		expectedHeader := XREQUESTID_MIDDLEWARE + CORS_MIDDLEWARE + AUTHENTICATOR_MIDDLEWARE + REQUEST_LOGGER_MIDDLEWARE
 		assert.Equal(t, expectedHeader, req.Header.Get(TEST_HEADER_NAME))
 	}
 
@@ -4,4 +4,8 @@ prmd doc user.json > ../doc/api/user.md
 prmd doc policy.json > ../doc/api/policy.md
 prmd doc proxy_resource.json > ../doc/api/proxy_resource.md
 prmd doc resource.json > ../doc/api/resource.md
-prmd doc oidc_provider.json > ../doc/api/oidc_provider.md
\ No newline at end of file
+prmd doc oidc_provider.json > ../doc/api/oidc_provider.md
+prmd doc organization.json > ../doc/api/organization.md
+prmd doc state.json > ../doc/api/state.md
+prmd doc webhook.json > ../doc/api/webhook.md
+prmd doc kubernetes.json > ../doc/api/kubernetes.md
\ No newline at end of file